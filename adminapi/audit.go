@@ -0,0 +1,212 @@
+package adminapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	stdlog "log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AuditReasonHeader carries the optional justification recorded with a
+// mutation.
+const AuditReasonHeader = "X-Audit-Reason"
+
+// AuditRecord is one entry of the administrative change trail. Seq, PrevHash
+// and Hash are assigned by the log; Hash covers every other field and the
+// previous entry's hash, so editing or deleting an entry breaks the chain.
+type AuditRecord struct {
+	Seq        uint64         `json:"seq,omitempty"`
+	Time       time.Time      `json:"time"`
+	Actor      string         `json:"actor"`
+	ActorEmail string         `json:"actor_email,omitempty"`
+	Source     string         `json:"source,omitempty"`
+	Operation  string         `json:"operation"`
+	Module     string         `json:"module,omitempty"`
+	Resource   string         `json:"resource,omitempty"`
+	Action     string         `json:"action,omitempty"`
+	Context    string         `json:"context,omitempty"`
+	Before     map[string]any `json:"before,omitempty"`
+	After      map[string]any `json:"after,omitempty"`
+	Reason     string         `json:"reason,omitempty"`
+	PrevHash   string         `json:"prev_hash,omitempty"`
+	Hash       string         `json:"hash,omitempty"`
+}
+
+// AuditFilter selects audit records. Empty fields match everything; Limit
+// keeps the most recent records.
+type AuditFilter struct {
+	Actor     string
+	Operation string
+	Resource  string
+	Context   string
+	Since     time.Time
+	Until     time.Time
+	Limit     int
+}
+
+// AuditLog records successful mutations and serves the audit route. Record
+// errors fail the request so that no change goes unrecorded silently.
+type AuditLog interface {
+	Record(context.Context, AuditRecord) error
+	AuditRecords(context.Context, Principal, AuditFilter) ([]AuditRecord, error)
+}
+
+func (h *handler) auditLog() AuditLog {
+	if h.options.Audit != nil {
+		return h.options.Audit
+	}
+	if log, ok := h.options.Provider.(AuditLog); ok {
+		return log
+	}
+	return nil
+}
+
+// mutate applies a decoded mutation and records it in the audit log. before
+// is looked up only when an audit log is configured. A mutation whose record
+// cannot be written still succeeds, with audited: false in the response.
+func (h *handler) mutate(w http.ResponseWriter, r *http.Request, principal Principal, route Route, contextName string, before func() (any, error), after any, apply func() error) {
+	log := h.auditLog()
+	var previous any
+	if log != nil && before != nil {
+		var err error
+		if previous, err = before(); err != nil {
+			writeProviderResult(w, nil, err)
+			return
+		}
+	}
+	if err := apply(); err != nil {
+		writeProviderResult(w, nil, err)
+		return
+	}
+	if log != nil {
+		record := AuditRecord{
+			Time:       time.Now().UTC(),
+			Actor:      principal.Subject,
+			ActorEmail: principal.Email,
+			Source:     "adminapi",
+			Operation:  route.Name,
+			Resource:   route.Resource,
+			Action:     route.Action,
+			Context:    contextName,
+			Before:     auditValue(previous),
+			After:      auditValue(after),
+			Reason:     strings.TrimSpace(r.Header.Get(AuditReasonHeader)),
+		}
+		// The change is already committed, so a failed record is logged and
+		// reported as unaudited rather than as a failed mutation.
+		if err := log.Record(r.Context(), record); err != nil {
+			stdlog.Printf("adminapi: %s: record audit entry for a committed change: %v", route.Name, err)
+			writeJSON(w, http.StatusOK, map[string]any{"changed": true, "audited": false})
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"changed": true})
+}
+
+func (h *handler) serveAudit(w http.ResponseWriter, r *http.Request, principal Principal, allow func(string) bool) {
+	log := h.auditLog()
+	if log == nil {
+		writeError(w, http.StatusNotImplemented, "audit log not supported")
+		return
+	}
+	filter, err := auditFilterFromQuery(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	// A context-restricted principal's limit applies to the records it may
+	// see, so the log is read without a limit unless it already filters to
+	// one allowed context.
+	limit := filter.Limit
+	if allow != nil && filter.Context == "" {
+		filter.Limit = 0
+	}
+	records, err := log.AuditRecords(r.Context(), principal, filter)
+	records = filterContexts(records, allow, func(item AuditRecord) string { return item.Context })
+	if limit > 0 && len(records) > limit {
+		records = records[len(records)-limit:]
+	}
+	writeProviderResult(w, records, err)
+}
+
+func auditFilterFromQuery(r *http.Request) (AuditFilter, error) {
+	query := r.URL.Query()
+	filter := AuditFilter{
+		Actor:     query.Get("actor"),
+		Operation: query.Get("operation"),
+		Resource:  query.Get("resource"),
+		Context:   query.Get("context"),
+	}
+	for name, target := range map[string]*time.Time{"since": &filter.Since, "until": &filter.Until} {
+		value := query.Get(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return filter, fmt.Errorf("invalid %s query parameter", name)
+		}
+		*target = parsed
+	}
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 0 {
+			return filter, errors.New("invalid limit query parameter")
+		}
+		filter.Limit = limit
+	}
+	return filter, nil
+}
+
+func (h *handler) previousRole(ctx context.Context, principal Principal, input RoleAssignment) (any, error) {
+	items, err := h.roleAssignments(ctx, principal)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		if item.User == input.User && item.Role == input.Role && item.Context == input.Context {
+			return item, nil
+		}
+	}
+	return nil, nil
+}
+
+func (h *handler) previousAttributePolicy(ctx context.Context, principal Principal, input AttributePolicy) (any, error) {
+	items, err := h.options.Provider.AttributePolicies(ctx, principal)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		if item.ID == input.ID {
+			return item, nil
+		}
+	}
+	return nil, nil
+}
+
+// existing returns a before lookup for set-valued entries (policies and
+// tuples), where the deleted value is the input itself.
+func existing(value any) func() (any, error) {
+	return func() (any, error) { return value, nil }
+}
+
+// auditValue converts a wire value to its JSON object form.
+func auditValue(value any) map[string]any {
+	if value == nil {
+		return nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	var out map[string]any
+	if json.Unmarshal(data, &out) != nil {
+		return nil
+	}
+	return out
+}
@@ -0,0 +1,153 @@
+package adminapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type testAuditLog struct {
+	records []AuditRecord
+	filter  AuditFilter
+	err     error
+}
+
+func (l *testAuditLog) Record(_ context.Context, record AuditRecord) error {
+	if l.err != nil {
+		return l.err
+	}
+	l.records = append(l.records, record)
+	return nil
+}
+
+func (l *testAuditLog) AuditRecords(_ context.Context, _ Principal, filter AuditFilter) ([]AuditRecord, error) {
+	l.filter = filter
+	return l.records, nil
+}
+
+func newAuditHandler(t *testing.T, log AuditLog, authorizer Authorizer) http.Handler {
+	t.Helper()
+	h, err := NewHandler(Options{
+		PrincipalResolver: fixedPrincipal{Principal{Subject: "admin-1", Email: "admin@example.com"}},
+		Authorizer:        authorizer,
+		Provider:          testProvider{},
+		Audit:             log,
+	})
+	if err != nil {
+		t.Fatalf("NewHandler: %v", err)
+	}
+	return h
+}
+
+func TestHandlerRecordsMutationsInAuditLog(t *testing.T) {
+	log := &testAuditLog{}
+	h := newAuditHandler(t, log, allowAuthorizer{})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/authz/roles", strings.NewReader(`{"user":"admin-1","role":"tenant_admin","context":"admin","scopes":["cms.page.write"]}`))
+	req.Header.Set(AuditReasonHeader, "OPS-7 onboarding")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 body=%s", rec.Code, rec.Body.String())
+	}
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/api/authz/rebac/tuples", strings.NewReader(`{"subject":"user:admin-1","relation":"member","object":"tenant:blackorchid"}`)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 body=%s", rec.Code, rec.Body.String())
+	}
+
+	if len(log.records) != 2 {
+		t.Fatalf("records = %#v, want 2", log.records)
+	}
+	upsert := log.records[0]
+	if upsert.Actor != "admin-1" || upsert.ActorEmail != "admin@example.com" || upsert.Source != "adminapi" ||
+		upsert.Operation != "roles-upsert" || upsert.Context != "admin" || upsert.Reason != "OPS-7 onboarding" || upsert.Time.IsZero() {
+		t.Fatalf("upsert record = %#v", upsert)
+	}
+	// The existing assignment is the before value; the input is the after value.
+	if upsert.Before["scopes"].([]any)[0] != "cms.page.read" || upsert.After["scopes"].([]any)[0] != "cms.page.write" {
+		t.Fatalf("upsert before/after = %v/%v", upsert.Before, upsert.After)
+	}
+	remove := log.records[1]
+	if remove.Operation != "rebac-tuples-delete" || remove.Before["object"] != "tenant:blackorchid" || remove.After != nil {
+		t.Fatalf("delete record = %#v", remove)
+	}
+}
+
+func TestHandlerReportsUnauditedMutationWhenAuditRecordFails(t *testing.T) {
+	h := newAuditHandler(t, &testAuditLog{err: errors.New("disk full")}, allowAuthorizer{})
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/authz/policies", strings.NewReader(`{"subject":"admin","object":"cms.page","action":"read"}`)))
+	// The mutation was applied, so it is not reported as failed.
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 body=%s", rec.Code, rec.Body.String())
+	}
+	var body map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if body["changed"] != true || body["audited"] != false {
+		t.Fatalf("response = %v, want changed and not audited", body)
+	}
+}
+
+func TestHandlerServesFilteredAuditRecords(t *testing.T) {
+	log := &testAuditLog{records: []AuditRecord{
+		{Seq: 1, Actor: "admin-1", Operation: "roles-upsert", Context: "billing"},
+		{Seq: 2, Actor: "admin-1", Operation: "roles-upsert", Context: "platform"},
+		{Seq: 3, Actor: "admin-1", Operation: "policies-upsert"},
+		{Seq: 4, Actor: "admin-1", Operation: "roles-delete", Context: "billing"},
+		{Seq: 5, Actor: "admin-1", Operation: "roles-delete", Context: "platform"},
+	}}
+	source := &testScopeRoleSource{
+		held:     map[string][]string{"admin-1": {"billing:authz.audit:read"}},
+		contexts: []string{"admin", "billing", "platform"},
+	}
+	h := newAuditHandler(t, log, NewScopeAuthorizer(source))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/authz/audit?actor=admin-1&since=2026-01-01T00:00:00Z&limit=10", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 body=%s", rec.Code, rec.Body.String())
+	}
+	// The limit is applied after the context filter, not by the log.
+	if log.filter.Actor != "admin-1" || log.filter.Limit != 0 || log.filter.Since.IsZero() {
+		t.Fatalf("filter = %#v", log.filter)
+	}
+	var records []AuditRecord
+	if err := json.Unmarshal(rec.Body.Bytes(), &records); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(records) != 2 || records[0].Seq != 1 || records[1].Seq != 4 {
+		t.Fatalf("records = %#v, want only the billing records", records)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/authz/audit?limit=1", nil))
+	records = nil
+	if err := json.Unmarshal(rec.Body.Bytes(), &records); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(records) != 1 || records[0].Seq != 4 {
+		t.Fatalf("limited records = %#v, want the latest billing record", records)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/authz/audit?limit=many", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400 body=%s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestHandlerAuditWithoutLogReturnsNotImplemented(t *testing.T) {
+	h := newTestHandler(t)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/authz/audit", nil))
+	if rec.Code != http.StatusNotImplemented {
+		t.Fatalf("status = %d, want 501 body=%s", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,247 @@
+package adminapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrForbidden is returned by ScopeAuthorizer when the principal lacks the
+// scope required for a route or target.
+var ErrForbidden = errors.New("authz admin action forbidden")
+
+// Target describes what a mutating route acts on, decoded from the request
+// body. Context is empty for provider-global data such as Casbin policies.
+type Target struct {
+	Context string
+	Subject string
+	Role    string
+	Scopes  []string
+	// Grant reports that the request gives Role and Scopes to Subject, so
+	// the no-escalation rule applies. Removals leave it false.
+	Grant bool
+}
+
+// TargetAuthorizer is an optional Authorizer extension for delegated
+// administration. The handler still calls Authorize before reading the body,
+// then calls AuthorizeTarget with the decoded target before invoking the
+// provider.
+type TargetAuthorizer interface {
+	AuthorizeTarget(ctx context.Context, principal Principal, resource, action string, target Target) error
+}
+
+// ContextAuthorizer is an optional Authorizer extension that limits list
+// routes and the event stream to the contexts the principal administers. all
+// reports unrestricted access; otherwise items without a listed context are
+// hidden.
+type ContextAuthorizer interface {
+	AuthorizedContexts(ctx context.Context, principal Principal, resource, action string) (contexts []string, all bool, err error)
+}
+
+// ScopeRoleSource is the scope/role state ScopeAuthorizer decides against.
+// authz.NewScopeRoleSource adapts a registered authz module.
+type ScopeRoleSource interface {
+	CheckScope(ctx context.Context, subject, contextName, scope string) (bool, error)
+	RoleScopes(ctx context.Context, contextName, role string) ([]string, error)
+	Contexts(ctx context.Context) ([]string, error)
+}
+
+// ScopeAuthorizer is the default Authorizer. A principal may use a route in a
+// context when it holds the scope "<context>:<resource>:<action>" there, for
+// example "billing:authz.roles:update". Holding the scope in AdminContext makes
+// the principal a global administrator for that route.
+//
+// Grants are additionally limited to scopes the principal holds itself in the
+// target context, including the scopes of an assigned role, so delegated
+// admins cannot escalate their own privileges.
+type ScopeAuthorizer struct {
+	Source ScopeRoleSource
+	// AdminContext defaults to "admin".
+	AdminContext string
+}
+
+var (
+	_ TargetAuthorizer  = (*ScopeAuthorizer)(nil)
+	_ ContextAuthorizer = (*ScopeAuthorizer)(nil)
+)
+
+func NewScopeAuthorizer(source ScopeRoleSource) *ScopeAuthorizer {
+	return &ScopeAuthorizer{Source: source}
+}
+
+func (a *ScopeAuthorizer) Authorize(ctx context.Context, principal Principal, resource, action string) error {
+	contexts, all, err := a.AuthorizedContexts(ctx, principal, resource, action)
+	if err != nil {
+		return err
+	}
+	if !all && len(contexts) == 0 {
+		return fmt.Errorf("%w: %s %s", ErrForbidden, resource, action)
+	}
+	return nil
+}
+
+func (a *ScopeAuthorizer) AuthorizedContexts(ctx context.Context, principal Principal, resource, action string) ([]string, bool, error) {
+	global, err := a.holdsRoute(ctx, principal, a.adminContext(), resource, action)
+	if err != nil || global {
+		return nil, global, err
+	}
+	known, err := a.Source.Contexts(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	var contexts []string
+	for _, contextName := range known {
+		if contextName == a.adminContext() {
+			continue
+		}
+		ok, err := a.holdsRoute(ctx, principal, contextName, resource, action)
+		if err != nil {
+			return nil, false, err
+		}
+		if ok {
+			contexts = append(contexts, contextName)
+		}
+	}
+	return contexts, false, nil
+}
+
+func (a *ScopeAuthorizer) AuthorizeTarget(ctx context.Context, principal Principal, resource, action string, target Target) error {
+	contextName := strings.TrimSpace(target.Context)
+	allowed, err := a.holdsRoute(ctx, principal, a.adminContext(), resource, action)
+	if err != nil {
+		return err
+	}
+	if !allowed && contextName != "" {
+		if allowed, err = a.holdsRoute(ctx, principal, contextName, resource, action); err != nil {
+			return err
+		}
+	}
+	if !allowed {
+		return fmt.Errorf("%w: %s %s in context %q", ErrForbidden, resource, action, contextName)
+	}
+	if !target.Grant {
+		return nil
+	}
+	scopes := append([]string(nil), target.Scopes...)
+	if role := strings.TrimSpace(target.Role); role != "" {
+		roleScopes, err := a.Source.RoleScopes(ctx, contextName, role)
+		if err != nil {
+			return err
+		}
+		scopes = append(scopes, roleScopes...)
+	}
+	for _, scope := range scopes {
+		held, err := a.Source.CheckScope(ctx, principal.Subject, contextName, scope)
+		if err != nil {
+			return err
+		}
+		if !held {
+			return fmt.Errorf("%w: cannot grant scope %q not held by %q", ErrForbidden, scope, principal.Subject)
+		}
+	}
+	return nil
+}
+
+func (a *ScopeAuthorizer) holdsRoute(ctx context.Context, principal Principal, contextName, resource, action string) (bool, error) {
+	return a.Source.CheckScope(ctx, principal.Subject, contextName, contextName+":"+resource+":"+action)
+}
+
+func (a *ScopeAuthorizer) adminContext() string {
+	if a.AdminContext != "" {
+		return a.AdminContext
+	}
+	return "admin"
+}
+
+// contextFilter returns a predicate selecting the contexts the principal may
+// see on route, or nil when every context is visible.
+func (h *handler) contextFilter(ctx context.Context, principal Principal, resource, action string) (func(string) bool, error) {
+	authorizer, ok := h.options.Authorizer.(ContextAuthorizer)
+	if !ok {
+		return nil, nil
+	}
+	contexts, all, err := authorizer.AuthorizedContexts(ctx, principal, resource, action)
+	if err != nil || all {
+		return nil, err
+	}
+	allowed := make(map[string]bool, len(contexts))
+	for _, contextName := range contexts {
+		allowed[contextName] = true
+	}
+	return func(contextName string) bool { return allowed[contextName] }, nil
+}
+
+func filterContexts[T any](items []T, allow func(string) bool, contextOf func(T) string) []T {
+	if allow == nil {
+		return items
+	}
+	out := make([]T, 0, len(items))
+	for _, item := range items {
+		if allow(contextOf(item)) {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// contextBoundRoutes are the non-list routes whose request names the contexts
+// it reads, so a context-restricted principal may only target those.
+var contextBoundRoutes = map[string]bool{"rebac-check": true, "enforce": true, "simulate": true}
+
+// simulationAllowed reports whether every change and probe of request targets
+// an allowed context. Casbin policy and grouping changes carry no context, and
+// all_subject_scopes probes every context, so both need unrestricted access.
+func simulationAllowed(request SimulationRequest, allow func(string) bool) bool {
+	if request.AllSubjectScopes {
+		return false
+	}
+	for _, change := range request.Changes {
+		if !allow(simulationChangeContext(change)) {
+			return false
+		}
+	}
+	for _, probe := range request.Probes {
+		if !allow(probe.Context) {
+			return false
+		}
+	}
+	return true
+}
+
+// simulationChangeContext returns the context of the field Kind selects, the
+// one the simulator applies.
+func simulationChangeContext(change SimulationChange) string {
+	switch change.Kind {
+	case "role":
+		if change.Role != nil {
+			return change.Role.Context
+		}
+	case "assignment":
+		if change.Assignment != nil {
+			return change.Assignment.Context
+		}
+	case "relation":
+		if change.Tuple != nil {
+			return change.Tuple.Context
+		}
+	case "abac_policy":
+		if change.AttributePolicy != nil {
+			return change.AttributePolicy.Context
+		}
+	}
+	return ""
+}
+
+func filterDeclarations(declarations Declarations, allow func(string) bool) Declarations {
+	if allow == nil {
+		return declarations
+	}
+	declarations.Resources = filterContexts(declarations.Resources, allow, func(item ResourceDeclaration) string { return item.Context })
+	declarations.Scopes = filterContexts(declarations.Scopes, allow, func(item Scope) string { return item.Context })
+	declarations.Actions = filterContexts(declarations.Actions, allow, func(item ActionDeclaration) string { return item.Context })
+	declarations.Attributes = filterContexts(declarations.Attributes, allow, func(item AttributeDeclaration) string { return item.Context })
+	declarations.Relations = filterContexts(declarations.Relations, allow, func(item RelationDeclaration) string { return item.Context })
+	declarations.UIActions = filterContexts(declarations.UIActions, allow, func(item UIActionDeclaration) string { return item.Context })
+	return declarations
+}
@@ -0,0 +1,220 @@
+package adminapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestScopeAuthorizerFiltersListsToAdministeredContexts(t *testing.T) {
+	source := &testScopeRoleSource{
+		held: map[string][]string{
+			"root":          {"admin:authz.roles:read"},
+			"billing-admin": {"billing:authz.roles:read"},
+		},
+		contexts: []string{"admin", "billing", "platform"},
+	}
+	for subject, want := range map[string][]string{
+		"root":          {"billing", "platform"},
+		"billing-admin": {"billing"},
+	} {
+		h := newDelegationHandler(t, subject, source, &delegationProvider{})
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/authz/roles", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d body=%s", subject, rec.Code, rec.Body.String())
+		}
+		var assignments []RoleAssignment
+		if err := json.Unmarshal(rec.Body.Bytes(), &assignments); err != nil {
+			t.Fatalf("decode roles: %v", err)
+		}
+		var got []string
+		for _, assignment := range assignments {
+			got = append(got, assignment.Context)
+		}
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Fatalf("%s: contexts = %v, want %v", subject, got, want)
+		}
+	}
+
+	h := newDelegationHandler(t, "nobody", source, &delegationProvider{})
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/authz/roles", nil))
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("principal without admin scopes: status = %d, want 403", rec.Code)
+	}
+}
+
+func TestScopeAuthorizerLimitsGrantsToTargetContextAndHeldScopes(t *testing.T) {
+	source := &testScopeRoleSource{
+		held: map[string][]string{
+			"billing-admin": {"billing:authz.roles:update", "billing:invoice:read"},
+		},
+		roles: map[string][]string{
+			"billing|reader":    {"billing:invoice:read"},
+			"billing|refunder":  {"billing:invoice:refund"},
+			"platform|operator": {"platform:cluster:read"},
+		},
+		contexts: []string{"admin", "billing", "platform"},
+	}
+	provider := &delegationProvider{}
+	h := newDelegationHandler(t, "billing-admin", source, provider)
+
+	for _, tc := range []struct {
+		name   string
+		method string
+		body   string
+		want   int
+	}{
+		{"assign held role", http.MethodPost, `{"user":"bob","role":"reader","context":"billing"}`, http.StatusOK},
+		{"other context", http.MethodPost, `{"user":"bob","role":"operator","context":"platform"}`, http.StatusForbidden},
+		{"role escalation", http.MethodPost, `{"user":"bob","role":"refunder","context":"billing"}`, http.StatusForbidden},
+		{"direct scope escalation", http.MethodPost, `{"user":"bob","role":"reader","context":"billing","scopes":["billing:invoice:refund"]}`, http.StatusForbidden},
+		{"revoke unheld role", http.MethodDelete, `{"user":"bob","role":"refunder","context":"billing"}`, http.StatusOK},
+	} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(tc.method, "/api/authz/roles", strings.NewReader(tc.body)))
+		if rec.Code != tc.want {
+			t.Fatalf("%s: status = %d, want %d body=%s", tc.name, rec.Code, tc.want, rec.Body.String())
+		}
+	}
+	if len(provider.changes) != 2 || provider.changes[0].Role != "reader" || provider.changes[1].Role != "refunder" {
+		t.Fatalf("provider changes = %#v", provider.changes)
+	}
+}
+
+func newDelegationHandler(t *testing.T, subject string, source ScopeRoleSource, provider Provider) http.Handler {
+	t.Helper()
+	h, err := NewHandler(Options{
+		PrincipalResolver: fixedPrincipal{Principal{Subject: subject}},
+		Authorizer:        NewScopeAuthorizer(source),
+		Provider:          provider,
+	})
+	if err != nil {
+		t.Fatalf("NewHandler: %v", err)
+	}
+	return h
+}
+
+type testScopeRoleSource struct {
+	held     map[string][]string
+	roles    map[string][]string
+	contexts []string
+}
+
+func (s *testScopeRoleSource) CheckScope(_ context.Context, subject, contextName, scope string) (bool, error) {
+	for _, held := range s.held[subject] {
+		if held == scope && strings.HasPrefix(scope, contextName+":") {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *testScopeRoleSource) RoleScopes(_ context.Context, contextName, role string) ([]string, error) {
+	return s.roles[contextName+"|"+role], nil
+}
+
+func (s *testScopeRoleSource) Contexts(context.Context) ([]string, error) {
+	return s.contexts, nil
+}
+
+type delegationProvider struct {
+	testProvider
+	changes []RoleAssignment
+}
+
+func (p *delegationProvider) RoleAssignments(context.Context, Principal) ([]RoleAssignment, error) {
+	return []RoleAssignment{
+		{User: "alice", Role: "reader", Context: "billing"},
+		{User: "carol", Role: "operator", Context: "platform"},
+	}, nil
+}
+
+func (p *delegationProvider) UpsertRole(_ context.Context, _ Principal, assignment RoleAssignment) error {
+	p.changes = append(p.changes, assignment)
+	return nil
+}
+
+func (p *delegationProvider) DeleteRole(_ context.Context, _ Principal, assignment RoleAssignment) error {
+	p.changes = append(p.changes, assignment)
+	return nil
+}
+
+func TestScopeAuthorizerFiltersDeclarationEventsToAdministeredContexts(t *testing.T) {
+	source := &testScopeRoleSource{
+		held:     map[string][]string{"billing-admin": {"billing:authz.events:read", "billing:authz.declarations:read"}},
+		contexts: []string{"billing", "platform"},
+	}
+	h, err := NewHandler(Options{
+		PrincipalResolver: fixedPrincipal{Principal{Subject: "billing-admin"}},
+		Authorizer:        NewScopeAuthorizer(source),
+		Provider:          testProvider{},
+		Events: &testEventSource{events: []Event{
+			{ID: "1", Type: EventDeclarationsRegistered, Data: map[string]any{"declarations": map[string]any{
+				"owner_plugin": "billing-plugin",
+				"scopes": []map[string]any{
+					{"name": "billing:invoice:read", "context": "billing"},
+					{"name": "platform:cluster:read", "context": "platform"},
+				},
+			}}},
+			{ID: "2", Type: EventDeclarationsRegistered, Data: map[string]any{"declarations": map[string]any{
+				"scopes": []any{map[string]any{"name": "platform:cluster:write", "context": "platform"}},
+			}}},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("NewHandler: %v", err)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/authz/events", nil))
+	body := rec.Body.String()
+	if !strings.Contains(body, "billing:invoice:read") || !strings.Contains(body, "billing-plugin") {
+		t.Fatalf("body missing billing declarations: %s", body)
+	}
+	if strings.Contains(body, "platform:") || strings.Contains(body, "id: 2\n") {
+		t.Fatalf("body leaked platform declarations: %s", body)
+	}
+}
+
+func TestScopeAuthorizerLimitsChecksAndSimulationsToAdministeredContexts(t *testing.T) {
+	source := &testScopeRoleSource{
+		held: map[string][]string{"billing-admin": {
+			"billing:authz.rebac:check",
+			"billing:authz.decisions:enforce",
+			"billing:authz.simulation:simulate",
+		}},
+		contexts: []string{"billing", "platform"},
+	}
+	h, err := NewHandler(Options{
+		PrincipalResolver: fixedPrincipal{Principal{Subject: "billing-admin"}},
+		Authorizer:        NewScopeAuthorizer(source),
+		Provider:          testProvider{},
+		Simulator:         &testSimulator{},
+	})
+	if err != nil {
+		t.Fatalf("NewHandler: %v", err)
+	}
+	for _, tc := range []struct {
+		name, path, body string
+		want             int
+	}{
+		{"check own context", "/api/authz/rebac/check", `{"subject":"bob","relation":"viewer","object":"doc-1","context":"billing"}`, http.StatusOK},
+		{"check other context", "/api/authz/rebac/check", `{"subject":"bob","relation":"viewer","object":"doc-1","context":"platform"}`, http.StatusForbidden},
+		{"enforce other context", "/api/authz/enforce", `{"subject":"bob","resource":"cluster","action":"read","context":"platform"}`, http.StatusForbidden},
+		{"enforce without context", "/api/authz/enforce", `{"subject":"bob","resource":"/docs","action":"read"}`, http.StatusForbidden},
+		{"simulate own context", "/api/authz/simulate", `{"changes":[{"kind":"assignment","assignment":{"user":"bob","role":"reader","context":"billing"}}],"probes":[{"subject":"bob","context":"billing","scope":"billing:invoice:read"}]}`, http.StatusOK},
+		{"simulate other context probe", "/api/authz/simulate", `{"probes":[{"subject":"bob","context":"platform","scope":"platform:cluster:read"}]}`, http.StatusForbidden},
+		{"simulate policy change", "/api/authz/simulate", `{"changes":[{"kind":"policy","rule":["bob","/docs","read"],"role":{"role":"x","context":"billing"}}],"probes":[{"subject":"bob","context":"billing"}]}`, http.StatusForbidden},
+		{"simulate every context", "/api/authz/simulate", `{"all_subject_scopes":true}`, http.StatusForbidden},
+	} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, tc.path, strings.NewReader(tc.body)))
+		if rec.Code != tc.want {
+			t.Errorf("%s: status = %d, want %d body=%s", tc.name, rec.Code, tc.want, rec.Body.String())
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package adminapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var eventHeartbeatInterval = 15 * time.Second
+
+// EventResource returns the authorization resource guarding events of the
+// given type, matching the resource of the corresponding read route. Stream
+// control events return an empty resource and are always delivered.
+func EventResource(eventType string) string {
+	switch {
+	case strings.HasPrefix(eventType, "policy."):
+		return "authz.policies"
+	case strings.HasPrefix(eventType, "role."):
+		return "authz.roles"
+	case strings.HasPrefix(eventType, "relation."):
+		return "authz.rebac.tuples"
+	case strings.HasPrefix(eventType, "abac_policy."):
+		return "authz.abac.policies"
+	case strings.HasPrefix(eventType, "declarations."):
+		return "authz.declarations"
+	case strings.HasPrefix(eventType, "provider."):
+		return "authz.capabilities"
+	default:
+		return ""
+	}
+}
+
+func (h *handler) eventSource() EventSource {
+	if h.options.Events != nil {
+		return h.options.Events
+	}
+	if source, ok := h.options.Provider.(EventSource); ok {
+		return source
+	}
+	return nil
+}
+
+func (h *handler) serveEvents(w http.ResponseWriter, r *http.Request, principal Principal) {
+	source := h.eventSource()
+	if source == nil {
+		writeError(w, http.StatusNotImplemented, "event stream not supported")
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming unsupported")
+		return
+	}
+	lastEventID := strings.TrimSpace(r.Header.Get("Last-Event-ID"))
+	if lastEventID == "" {
+		lastEventID = strings.TrimSpace(r.URL.Query().Get("last_event_id"))
+	}
+	events, err := source.Events(r.Context(), principal, lastEventID)
+	if err != nil {
+		writeProviderResult(w, nil, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	_, _ = fmt.Fprint(w, "retry: 3000\n\n")
+	flusher.Flush()
+
+	// Each event is re-authorized against the resource of its read route so
+	// subscribers only see changes they could list directly, including the
+	// context filter applied to list routes. Decisions are cached for the
+	// lifetime of the stream.
+	allowed := map[string]bool{}
+	filters := map[string]func(string) bool{}
+	visible := func(event Event) (Event, bool) {
+		resource := EventResource(event.Type)
+		if resource == "" {
+			return event, true
+		}
+		ok, seen := allowed[resource]
+		if !seen {
+			ok = h.options.Authorizer.Authorize(r.Context(), principal, resource, "read") == nil
+			if ok {
+				filter, err := h.contextFilter(r.Context(), principal, resource, "read")
+				ok = err == nil
+				filters[resource] = filter
+			}
+			allowed[resource] = ok
+		}
+		if !ok {
+			return event, false
+		}
+		filter := filters[resource]
+		if filter == nil {
+			return event, true
+		}
+		if resource == "authz.declarations" {
+			return filterDeclarationEvent(event, filter)
+		}
+		contextName, _ := event.Data["context"].(string)
+		return event, filter(contextName)
+	}
+
+	heartbeat := time.NewTicker(eventHeartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			event, ok = visible(event)
+			if !ok {
+				continue
+			}
+			if err := writeEvent(w, event); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// filterDeclarationEvent narrows the declarations carried by a declarations.*
+// event to the allowed contexts, as the declarations route does. The event is
+// dropped when none remain.
+func filterDeclarationEvent(event Event, allow func(string) bool) (Event, bool) {
+	declarations, _ := event.Data["declarations"].(map[string]any)
+	filtered := make(map[string]any, len(declarations))
+	kept := 0
+	for key, value := range declarations {
+		var items []any
+		switch list := value.(type) {
+		case []map[string]any:
+			for _, item := range list {
+				items = append(items, item)
+			}
+		case []any:
+			items = list
+		default:
+			filtered[key] = value
+			continue
+		}
+		out := make([]any, 0, len(items))
+		for _, item := range items {
+			fields, _ := item.(map[string]any)
+			if contextName, _ := fields["context"].(string); allow(contextName) {
+				out = append(out, item)
+			}
+		}
+		filtered[key] = out
+		kept += len(out)
+	}
+	if kept == 0 {
+		return event, false
+	}
+	data := make(map[string]any, len(event.Data))
+	for key, value := range event.Data {
+		data[key] = value
+	}
+	data["declarations"] = filtered
+	event.Data = data
+	return event, true
+}
+
+func writeEvent(w http.ResponseWriter, event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	var b strings.Builder
+	if event.ID != "" {
+		fmt.Fprintf(&b, "id: %s\n", event.ID)
+	}
+	if event.Type != "" {
+		fmt.Fprintf(&b, "event: %s\n", event.Type)
+	}
+	fmt.Fprintf(&b, "data: %s\n\n", data)
+	_, err = fmt.Fprint(w, b.String())
+	return err
+}
@@ -117,6 +117,10 @@ func RoutesForBasePath(basePath string) RouteCatalog {
 		{Name: "rebac-tuples-delete", Method: http.MethodDelete, Path: basePath + "/rebac/tuples", Resource: "authz.rebac.tuples", Action: "update"},
 		{Name: "rebac-check", Method: http.MethodPost, Path: basePath + "/rebac/check", Resource: "authz.rebac", Action: "check"},
 		{Name: "enforce", Method: http.MethodPost, Path: basePath + "/enforce", Resource: "authz.decisions", Action: "enforce"},
+		{Name: "events", Method: http.MethodGet, Path: basePath + "/events", Resource: "authz.events", Action: "read"},
+		{Name: "simulate", Method: http.MethodPost, Path: basePath + "/simulate", Resource: "authz.simulation", Action: "simulate"},
+		{Name: "subject-projection", Method: http.MethodGet, Path: basePath + "/projection", Resource: "authz.projection", Action: "read"},
+		{Name: "audit", Method: http.MethodGet, Path: basePath + "/audit", Resource: "authz.audit", Action: "read"},
 	}
 	byPath := make(map[string]Route, len(routes)*2)
 	for _, route := range routes {
@@ -172,88 +176,122 @@ func (h *handler) dispatch(w http.ResponseWriter, r *http.Request, route Route)
 }
 
 func (h *handler) serveRoute(w http.ResponseWriter, r *http.Request, principal Principal, route Route) {
+	// List routes only return items from contexts the principal administers
+	// when the Authorizer restricts it to a subset of contexts. Check and
+	// simulation routes reject requests for any other context.
+	var allow func(string) bool
+	if (route.Method == http.MethodGet && route.Name != "events") || contextBoundRoutes[route.Name] {
+		var err error
+		if allow, err = h.contextFilter(r.Context(), principal, route.Resource, route.Action); err != nil {
+			writeError(w, http.StatusForbidden, "forbidden")
+			return
+		}
+	}
 	switch route.Name {
 	case "roles":
 		items, err := h.roleAssignments(r.Context(), principal)
-		writeProviderResult(w, items, err)
+		writeProviderResult(w, filterContexts(items, allow, func(item RoleAssignment) string { return item.Context }), err)
 	case "roles-upsert":
 		var input RoleAssignment
-		if !decodeRouteJSON(w, r, &input) {
+		if !decodeRouteJSON(w, r, &input) || !h.authorizeRouteTarget(w, r, principal, route, roleTarget(input, true)) {
 			return
 		}
-		writeProviderResult(w, map[string]any{"changed": true}, h.options.Provider.UpsertRole(r.Context(), principal, input))
+		h.mutate(w, r, principal, route, input.Context, func() (any, error) { return h.previousRole(r.Context(), principal, input) }, input, func() error {
+			return h.options.Provider.UpsertRole(r.Context(), principal, input)
+		})
 	case "roles-delete":
 		var input RoleAssignment
-		if !decodeRouteJSON(w, r, &input) {
+		if !decodeRouteJSON(w, r, &input) || !h.authorizeRouteTarget(w, r, principal, route, roleTarget(input, false)) {
 			return
 		}
-		writeProviderResult(w, map[string]any{"changed": true}, h.options.Provider.DeleteRole(r.Context(), principal, input))
+		h.mutate(w, r, principal, route, input.Context, func() (any, error) { return h.previousRole(r.Context(), principal, input) }, nil, func() error {
+			return h.options.Provider.DeleteRole(r.Context(), principal, input)
+		})
 	case "scopes":
 		items, err := h.options.Provider.Scopes(r.Context(), principal)
-		writeProviderResult(w, items, err)
+		writeProviderResult(w, filterContexts(items, allow, func(item Scope) string { return item.Context }), err)
 	case "capabilities":
 		items, err := h.options.Provider.Capabilities(r.Context(), principal)
 		writeProviderResult(w, map[string]any{"capabilities": items}, err)
 	case "declarations":
 		items, err := h.options.Provider.Declarations(r.Context(), principal)
-		writeProviderResult(w, items, err)
+		writeProviderResult(w, filterDeclarations(items, allow), err)
 	case "projection-inputs":
 		items, err := h.options.Provider.ProjectionInputs(r.Context(), principal)
+		items.Contexts = filterContexts(items.Contexts, allow, func(item string) string { return item })
 		writeProviderResult(w, items, err)
 	case "model":
 		item, err := h.options.Provider.Model(r.Context(), principal)
 		writeProviderResult(w, item, err)
 	case "policies":
 		items, err := h.options.Provider.Policies(r.Context(), principal)
-		writeProviderResult(w, items, err)
+		// Casbin policies carry no context, so only unrestricted principals
+		// see them.
+		writeProviderResult(w, filterContexts(items, allow, func(Policy) string { return "" }), err)
 	case "policies-upsert":
 		var input PolicyRule
-		if !decodeRouteJSON(w, r, &input) {
+		if !decodeRouteJSON(w, r, &input) || !h.authorizeRouteTarget(w, r, principal, route, Target{Subject: input.Subject}) {
 			return
 		}
-		writeProviderResult(w, map[string]any{"changed": true}, h.options.Provider.UpsertPolicy(r.Context(), principal, input))
+		h.mutate(w, r, principal, route, "", nil, input, func() error {
+			return h.options.Provider.UpsertPolicy(r.Context(), principal, input)
+		})
 	case "policies-delete":
 		var input PolicyRule
-		if !decodeRouteJSON(w, r, &input) {
+		if !decodeRouteJSON(w, r, &input) || !h.authorizeRouteTarget(w, r, principal, route, Target{Subject: input.Subject}) {
 			return
 		}
-		writeProviderResult(w, map[string]any{"changed": true}, h.options.Provider.DeletePolicy(r.Context(), principal, input))
+		h.mutate(w, r, principal, route, "", existing(input), nil, func() error {
+			return h.options.Provider.DeletePolicy(r.Context(), principal, input)
+		})
 	case "abac-policies":
 		items, err := h.options.Provider.AttributePolicies(r.Context(), principal)
-		writeProviderResult(w, items, err)
+		writeProviderResult(w, filterContexts(items, allow, func(item AttributePolicy) string { return item.Context }), err)
 	case "abac-policies-upsert":
 		var input AttributePolicy
-		if !decodeRouteJSON(w, r, &input) {
+		if !decodeRouteJSON(w, r, &input) || !h.authorizeRouteTarget(w, r, principal, route, Target{Context: input.Context}) {
 			return
 		}
-		writeProviderResult(w, map[string]any{"changed": true}, h.options.Provider.UpsertAttributePolicy(r.Context(), principal, input))
+		h.mutate(w, r, principal, route, input.Context, func() (any, error) { return h.previousAttributePolicy(r.Context(), principal, input) }, input, func() error {
+			return h.options.Provider.UpsertAttributePolicy(r.Context(), principal, input)
+		})
 	case "abac-policies-delete":
 		var input AttributePolicy
-		if !decodeRouteJSON(w, r, &input) {
+		if !decodeRouteJSON(w, r, &input) || !h.authorizeRouteTarget(w, r, principal, route, Target{Context: input.Context}) {
 			return
 		}
-		writeProviderResult(w, map[string]any{"changed": true}, h.options.Provider.DeleteAttributePolicy(r.Context(), principal, input))
+		h.mutate(w, r, principal, route, input.Context, func() (any, error) { return h.previousAttributePolicy(r.Context(), principal, input) }, nil, func() error {
+			return h.options.Provider.DeleteAttributePolicy(r.Context(), principal, input)
+		})
 	case "rebac-tuples":
 		items, err := h.options.Provider.RelationTuples(r.Context(), principal)
-		writeProviderResult(w, items, err)
+		writeProviderResult(w, filterContexts(items, allow, func(item RelationTuple) string { return item.Context }), err)
 	case "rebac-tuples-upsert":
 		var input RelationTuple
-		if !decodeRouteJSON(w, r, &input) {
+		if !decodeRouteJSON(w, r, &input) || !h.authorizeRouteTarget(w, r, principal, route, Target{Context: input.Context, Subject: input.Subject}) {
 			return
 		}
-		writeProviderResult(w, map[string]any{"changed": true}, h.options.Provider.UpsertRelationTuple(r.Context(), principal, input))
+		h.mutate(w, r, principal, route, input.Context, nil, input, func() error {
+			return h.options.Provider.UpsertRelationTuple(r.Context(), principal, input)
+		})
 	case "rebac-tuples-delete":
 		var input RelationTuple
-		if !decodeRouteJSON(w, r, &input) {
+		if !decodeRouteJSON(w, r, &input) || !h.authorizeRouteTarget(w, r, principal, route, Target{Context: input.Context, Subject: input.Subject}) {
 			return
 		}
-		writeProviderResult(w, map[string]any{"changed": true}, h.options.Provider.DeleteRelationTuple(r.Context(), principal, input))
+		h.mutate(w, r, principal, route, input.Context, existing(input), nil, func() error {
+			return h.options.Provider.DeleteRelationTuple(r.Context(), principal, input)
+		})
 	case "rebac-check":
 		var input RelationCheck
 		if err := decodeJSON(r, &input); err != nil {
 			writeError(w, http.StatusBadRequest, "invalid JSON")
 			return
 		}
+		if allow != nil && !allow(input.Context) {
+			writeError(w, http.StatusForbidden, "forbidden")
+			return
+		}
 		decision, err := h.options.Provider.CheckRelation(r.Context(), principal, input)
 		writeProviderResult(w, decision, err)
 	case "enforce":
@@ -262,13 +300,54 @@ func (h *handler) serveRoute(w http.ResponseWriter, r *http.Request, principal P
 			writeError(w, http.StatusBadRequest, "invalid JSON")
 			return
 		}
+		if allow != nil && !allow(input.Context) {
+			writeError(w, http.StatusForbidden, "forbidden")
+			return
+		}
 		decision, err := h.options.Provider.Enforce(r.Context(), principal, input)
 		writeProviderResult(w, decision, err)
+	case "events":
+		h.serveEvents(w, r, principal)
+	case "audit":
+		h.serveAudit(w, r, principal, allow)
+	case "subject-projection":
+		h.serveSubjectProjection(w, r, principal, route, allow)
+	case "simulate":
+		simulator := h.simulator()
+		if simulator == nil {
+			writeError(w, http.StatusNotImplemented, "simulation not supported")
+			return
+		}
+		var input SimulationRequest
+		if !decodeRouteJSON(w, r, &input) {
+			return
+		}
+		// The kind picks both the context checked below and the change the
+		// simulator applies, so both see it normalized.
+		for i := range input.Changes {
+			input.Changes[i].Kind = strings.ToLower(strings.TrimSpace(input.Changes[i].Kind))
+		}
+		if allow != nil && !simulationAllowed(input, allow) {
+			writeError(w, http.StatusForbidden, "forbidden")
+			return
+		}
+		result, err := simulator.Simulate(r.Context(), principal, input)
+		writeProviderResult(w, result, err)
 	default:
 		writeError(w, http.StatusNotFound, "not found")
 	}
 }
 
+func (h *handler) simulator() Simulator {
+	if h.options.Simulator != nil {
+		return h.options.Simulator
+	}
+	if simulator, ok := h.options.Provider.(Simulator); ok {
+		return simulator
+	}
+	return nil
+}
+
 func (h *handler) roleAssignments(ctx context.Context, principal Principal) ([]RoleAssignment, error) {
 	if provider, ok := h.options.Provider.(RoleAssignmentProvider); ok {
 		return provider.RoleAssignments(ctx, principal)
@@ -289,6 +368,24 @@ func (h *handler) roleAssignments(ctx context.Context, principal Principal) ([]R
 	return assignments, nil
 }
 
+// authorizeRouteTarget applies the optional TargetAuthorizer to a decoded
+// mutation and writes 403 when it refuses.
+func (h *handler) authorizeRouteTarget(w http.ResponseWriter, r *http.Request, principal Principal, route Route, target Target) bool {
+	authorizer, ok := h.options.Authorizer.(TargetAuthorizer)
+	if !ok {
+		return true
+	}
+	if err := authorizer.AuthorizeTarget(r.Context(), principal, route.Resource, route.Action, target); err != nil {
+		writeError(w, http.StatusForbidden, "forbidden")
+		return false
+	}
+	return true
+}
+
+func roleTarget(input RoleAssignment, grant bool) Target {
+	return Target{Context: input.Context, Subject: input.User, Role: input.Role, Scopes: input.Scopes, Grant: grant}
+}
+
 func decodeRouteJSON(w http.ResponseWriter, r *http.Request, out any) bool {
 	if err := decodeJSON(r, out); err != nil {
 		writeError(w, http.StatusBadRequest, "invalid JSON")
@@ -24,6 +24,10 @@ func TestRouteCatalogContainsAuthzUIBackendEndpoints(t *testing.T) {
 		"/api/authz/rebac/tuples",
 		"/api/authz/rebac/check",
 		"/api/authz/enforce",
+		"/api/authz/events",
+		"/api/authz/simulate",
+		"/api/authz/audit",
+		"/api/authz/projection",
 	} {
 		if _, ok := routes.ByPath[want]; !ok {
 			t.Fatalf("route catalog missing %s; routes=%#v", want, routes.ByPath)
@@ -284,6 +288,207 @@ func TestHandlerRejectsClientAssertedSubjectWithoutPermission(t *testing.T) {
 	}
 }
 
+func TestHandlerStreamsAuthorizedChangeEvents(t *testing.T) {
+	source := &testEventSource{events: []Event{
+		{ID: "2", Type: EventPolicyAdded, Source: "authz", Data: map[string]any{"rule": []any{"editor", "/docs", "write"}}},
+		{ID: "3", Type: EventRoleAssigned, Source: "authz"},
+		{Type: EventStreamReset},
+	}}
+	h, err := NewHandler(Options{
+		PrincipalResolver: fixedPrincipal{Principal{Subject: "admin-1"}},
+		Authorizer:        resourceDenyAuthorizer{denyResource: "authz.roles"},
+		Provider:          testProvider{},
+		Events:            source,
+	})
+	if err != nil {
+		t.Fatalf("NewHandler: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/api/authz/events", nil)
+	req.Header.Set("Last-Event-ID", "1")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 body=%s", rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get("Content-Type"); got != "text/event-stream" {
+		t.Fatalf("Content-Type = %q, want text/event-stream", got)
+	}
+	if source.lastEventID != "1" {
+		t.Fatalf("lastEventID = %q, want 1", source.lastEventID)
+	}
+	body := rec.Body.String()
+	if !strings.Contains(body, "id: 2\nevent: policy.added\ndata: ") {
+		t.Fatalf("body missing policy.added event: %s", body)
+	}
+	if strings.Contains(body, EventRoleAssigned) {
+		t.Fatalf("body leaked role event to principal without authz.roles read: %s", body)
+	}
+	if !strings.Contains(body, "event: "+EventStreamReset) {
+		t.Fatalf("body missing stream reset event: %s", body)
+	}
+}
+
+func TestHandlerEventsWithoutSourceReturnsNotImplemented(t *testing.T) {
+	h := newTestHandler(t)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/authz/events", nil))
+	if rec.Code != http.StatusNotImplemented {
+		t.Fatalf("status = %d, want 501 body=%s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestHandlerEventsRejectsInvalidResumeID(t *testing.T) {
+	h, err := NewHandler(Options{
+		PrincipalResolver: fixedPrincipal{Principal{Subject: "admin-1"}},
+		Authorizer:        allowAuthorizer{},
+		Provider:          testProvider{},
+		Events:            &testEventSource{err: ErrInvalidRequest},
+	})
+	if err != nil {
+		t.Fatalf("NewHandler: %v", err)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/authz/events?last_event_id=bogus", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400 body=%s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestHandlerSimulateReturnsFlips(t *testing.T) {
+	simulator := &testSimulator{result: SimulationResult{
+		Evaluated: 2,
+		Revoked:   1,
+		Flips: []SimulationFlip{{
+			Probe:  SimulationProbe{Subject: "alice", Context: "cms", Scope: "cms:page:publish"},
+			Before: true,
+			Change: "allow_to_deny",
+		}},
+	}}
+	h, err := NewHandler(Options{
+		PrincipalResolver: fixedPrincipal{Principal{Subject: "admin-1"}},
+		Authorizer:        allowAuthorizer{},
+		Provider:          testProvider{},
+		Simulator:         simulator,
+	})
+	if err != nil {
+		t.Fatalf("NewHandler: %v", err)
+	}
+	body := `{"changes":[{"op":"remove","kind":"Assignment","assignment":{"user":"alice","role":"editor","context":"cms"}}],"all_subject_scopes":true}`
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/authz/simulate", strings.NewReader(body)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 body=%s", rec.Code, rec.Body.String())
+	}
+	got := simulator.request
+	if !got.AllSubjectScopes || len(got.Changes) != 1 || got.Changes[0].Kind != "assignment" || got.Changes[0].Assignment == nil || got.Changes[0].Assignment.User != "alice" {
+		t.Fatalf("simulation request = %#v", got)
+	}
+	var result SimulationResult
+	if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if result.Revoked != 1 || len(result.Flips) != 1 || result.Flips[0].Change != "allow_to_deny" {
+		t.Fatalf("simulation result = %#v", result)
+	}
+}
+
+func TestHandlerSimulateWithoutSimulatorReturnsNotImplemented(t *testing.T) {
+	h := newTestHandler(t)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/authz/simulate", strings.NewReader(`{}`)))
+	if rec.Code != http.StatusNotImplemented {
+		t.Fatalf("status = %d, want 501 body=%s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestWireTypesKeepLegacyJSONShape(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{"declarations", Declarations{Resources: []ResourceDeclaration{{Name: "cms.page", Actions: []string{"read"}}}}, `{"resources":[{"name":"cms.page","actions":["read"]}]}`},
+		{"attribute policy", AttributePolicy{ID: "abac-1", Resource: "cms.page", Action: "read", Effect: "allow"}, `{"id":"abac-1","resource":"cms.page","action":"read","effect":"allow"}`},
+		{"relation tuple", RelationTuple{Subject: "user:1", Relation: "member", Object: "tenant:1"}, `{"subject":"user:1","relation":"member","object":"tenant:1"}`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := json.Marshal(tc.value)
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+			if string(data) != tc.want {
+				t.Fatalf("json = %s, want %s", data, tc.want)
+			}
+		})
+	}
+}
+
+func TestHandlerPassesABACConditionsAndTupleContextToProvider(t *testing.T) {
+	provider := &recordingProvider{}
+	h, err := NewHandler(Options{
+		PrincipalResolver: fixedPrincipal{Principal{Subject: "admin-1"}},
+		Authorizer:        allowAuthorizer{},
+		Provider:          provider,
+	})
+	if err != nil {
+		t.Fatalf("NewHandler: %v", err)
+	}
+	for _, tc := range []struct {
+		path string
+		body string
+	}{
+		{"/api/authz/abac/policies", `{"id":"abac-1","context":"cms","resource":"cms.page","action":"read","effect":"deny","owner_plugin":"cms","conditions":[{"target":"subject","attribute":"department","operator":"in","values":["eng","ops"]}]}`},
+		{"/api/authz/rebac/tuples", `{"subject":"user:1","relation":"viewer","object":"doc:1","context":"cms"}`},
+	} {
+		req := httptest.NewRequest(http.MethodPost, tc.path, strings.NewReader(tc.body))
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s status = %d, want 200 body=%s", tc.path, rec.Code, rec.Body.String())
+		}
+	}
+	policy := provider.attributePolicy
+	if policy.Context != "cms" || policy.Effect != "deny" || policy.OwnerPlugin != "cms" {
+		t.Fatalf("attribute policy = %#v, want context, effect, and owner", policy)
+	}
+	if len(policy.Conditions) != 1 || policy.Conditions[0].Operator != "in" || len(policy.Conditions[0].Values) != 2 {
+		t.Fatalf("conditions = %#v, want one in-condition with two values", policy.Conditions)
+	}
+	if provider.tuple.Context != "cms" {
+		t.Fatalf("tuple = %#v, want cms context", provider.tuple)
+	}
+}
+
+func TestDeclarationsRouteServesFullCatalog(t *testing.T) {
+	h, err := NewHandler(Options{
+		PrincipalResolver: fixedPrincipal{Principal{Subject: "admin-1"}},
+		Authorizer:        allowAuthorizer{},
+		Provider:          &recordingProvider{},
+	})
+	if err != nil {
+		t.Fatalf("NewHandler: %v", err)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/authz/declarations", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 body=%s", rec.Code, rec.Body.String())
+	}
+	var payload Declarations
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode declarations: %v", err)
+	}
+	if len(payload.Attributes) != 1 || payload.Attributes[0].DataType != "string" || len(payload.Attributes[0].AllowedValues) != 2 {
+		t.Fatalf("attributes = %#v, want string attribute with allowed values", payload.Attributes)
+	}
+	if len(payload.Relations) != 1 || payload.Relations[0].ObjectType != "document" {
+		t.Fatalf("relations = %#v, want document relation", payload.Relations)
+	}
+	if len(payload.UIActions) != 1 || payload.UIActions[0].RequiredScopes[0] != "cms.page.read" {
+		t.Fatalf("ui actions = %#v, want action requiring cms.page.read", payload.UIActions)
+	}
+}
+
 func newTestHandler(t *testing.T) http.Handler {
 	t.Helper()
 	h, err := NewHandler(Options{
@@ -326,6 +531,44 @@ func (a actionDenyAuthorizer) Authorize(_ context.Context, _ Principal, _ string
 	return nil
 }
 
+type resourceDenyAuthorizer struct{ denyResource string }
+
+func (a resourceDenyAuthorizer) Authorize(_ context.Context, _ Principal, resource string, _ string) error {
+	if resource == a.denyResource {
+		return errors.New("denied")
+	}
+	return nil
+}
+
+type testEventSource struct {
+	events      []Event
+	err         error
+	lastEventID string
+}
+
+func (s *testEventSource) Events(_ context.Context, _ Principal, lastEventID string) (<-chan Event, error) {
+	s.lastEventID = lastEventID
+	if s.err != nil {
+		return nil, s.err
+	}
+	out := make(chan Event, len(s.events))
+	for _, event := range s.events {
+		out <- event
+	}
+	close(out)
+	return out, nil
+}
+
+type testSimulator struct {
+	request SimulationRequest
+	result  SimulationResult
+}
+
+func (s *testSimulator) Simulate(_ context.Context, _ Principal, request SimulationRequest) (SimulationResult, error) {
+	s.request = request
+	return s.result, nil
+}
+
 type testProvider struct{}
 
 func (testProvider) Roles(context.Context, Principal) ([]Role, error) {
@@ -485,3 +728,40 @@ type invalidRoleProvider struct{ testProvider }
 func (invalidRoleProvider) UpsertRole(context.Context, Principal, RoleAssignment) error {
 	return ErrInvalidRequest
 }
+
+type recordingProvider struct {
+	testProvider
+	attributePolicy AttributePolicy
+	tuple           RelationTuple
+}
+
+func (p *recordingProvider) Declarations(context.Context, Principal) (Declarations, error) {
+	return Declarations{
+		Resources: []ResourceDeclaration{{Name: "cms.page", Context: "cms", Actions: []string{"read"}}},
+		Attributes: []AttributeDeclaration{{
+			Name:          "department",
+			Context:       "cms",
+			Target:        "subject",
+			DataType:      "string",
+			AllowedValues: []AttributeValue{{Value: "eng", Label: "Engineering"}, {Value: "ops"}},
+		}},
+		Relations: []RelationDeclaration{{Name: "viewer", Context: "cms", SubjectType: "user", ObjectType: "document"}},
+		UIActions: []UIActionDeclaration{{
+			ID:                   "cms.page.edit",
+			Context:              "cms",
+			Label:                "Edit page",
+			RequiredScopes:       []string{"cms.page.read"},
+			RequiredCapabilities: []CapabilityRequirement{{Mode: "rbac", Operations: []string{"check"}}},
+		}},
+	}, nil
+}
+
+func (p *recordingProvider) UpsertAttributePolicy(_ context.Context, _ Principal, policy AttributePolicy) error {
+	p.attributePolicy = policy
+	return nil
+}
+
+func (p *recordingProvider) UpsertRelationTuple(_ context.Context, _ Principal, tuple RelationTuple) error {
+	p.tuple = tuple
+	return nil
+}
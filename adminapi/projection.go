@@ -0,0 +1,75 @@
+package adminapi
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// SubjectProjection is the signed, expiring access projection an SPA caches
+// to decide which UI actions to show. It is a display hint; every backend
+// action is still authorized server-side.
+type SubjectProjection struct {
+	Subject       string              `json:"subject"`
+	Module        string              `json:"module,omitempty"`
+	Provider      string              `json:"provider,omitempty"`
+	Context       string              `json:"context,omitempty"`
+	GrantedScopes []string            `json:"granted_scopes"`
+	UIActionIDs   []string            `json:"ui_action_ids"`
+	Resources     []PermittedResource `json:"resources"`
+	IssuedAt      time.Time           `json:"issued_at"`
+	ExpiresAt     time.Time           `json:"expires_at"`
+	Signature     string              `json:"signature,omitempty"`
+}
+
+type PermittedResource struct {
+	Context  string   `json:"context"`
+	Resource string   `json:"resource"`
+	Actions  []string `json:"actions"`
+}
+
+// ProjectionResolver resolves the projection of subject, optionally limited
+// to one context.
+type ProjectionResolver interface {
+	SubjectProjection(ctx context.Context, principal Principal, subject, contextName string) (SubjectProjection, error)
+}
+
+func (h *handler) projectionResolver() ProjectionResolver {
+	if h.options.Projections != nil {
+		return h.options.Projections
+	}
+	if resolver, ok := h.options.Provider.(ProjectionResolver); ok {
+		return resolver
+	}
+	return nil
+}
+
+// serveSubjectProjection returns the principal's own projection. Projections
+// of other subjects additionally require authz.projection/inspect and, for
+// context-restricted principals, a context they administer.
+func (h *handler) serveSubjectProjection(w http.ResponseWriter, r *http.Request, principal Principal, route Route, allow func(string) bool) {
+	resolver := h.projectionResolver()
+	if resolver == nil {
+		writeError(w, http.StatusNotImplemented, "subject projection not supported")
+		return
+	}
+	query := r.URL.Query()
+	subject := strings.TrimSpace(query.Get("subject"))
+	contextName := strings.TrimSpace(query.Get("context"))
+	if subject == "" {
+		subject = principal.Subject
+	}
+	if subject != principal.Subject {
+		if err := h.options.Authorizer.Authorize(r.Context(), principal, route.Resource, "inspect"); err != nil {
+			writeError(w, http.StatusForbidden, "forbidden")
+			return
+		}
+		if allow != nil && !allow(contextName) {
+			writeError(w, http.StatusForbidden, "forbidden")
+			return
+		}
+	}
+	projection, err := resolver.SubjectProjection(r.Context(), principal, subject, contextName)
+	writeProviderResult(w, projection, err)
+}
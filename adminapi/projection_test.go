@@ -0,0 +1,74 @@
+package adminapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type testProjectionResolver struct {
+	subject string
+	context string
+}
+
+func (r *testProjectionResolver) SubjectProjection(_ context.Context, _ Principal, subject, contextName string) (SubjectProjection, error) {
+	r.subject, r.context = subject, contextName
+	return SubjectProjection{Subject: subject, Context: contextName, UIActionIDs: []string{"page.view"}, Signature: "sig"}, nil
+}
+
+func newProjectionHandler(t *testing.T, resolver ProjectionResolver, authorizer Authorizer) http.Handler {
+	t.Helper()
+	h, err := NewHandler(Options{
+		PrincipalResolver: fixedPrincipal{Principal{Subject: "user-1"}},
+		Authorizer:        authorizer,
+		Provider:          testProvider{},
+		Projections:       resolver,
+	})
+	if err != nil {
+		t.Fatalf("NewHandler: %v", err)
+	}
+	return h
+}
+
+func TestHandlerServesPrincipalProjection(t *testing.T) {
+	resolver := &testProjectionResolver{}
+	h := newProjectionHandler(t, resolver, actionDenyAuthorizer{denyAction: "inspect"})
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/authz/projection?context=cms", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 body=%s", rec.Code, rec.Body.String())
+	}
+	var projection SubjectProjection
+	if err := json.Unmarshal(rec.Body.Bytes(), &projection); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resolver.subject != "user-1" || resolver.context != "cms" || projection.Signature != "sig" || len(projection.UIActionIDs) != 1 {
+		t.Fatalf("projection = %#v, resolver = %#v", projection, resolver)
+	}
+
+	// Other subjects need authz.projection/inspect.
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/authz/projection?subject=super-admin", nil))
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, want 403 body=%s", rec.Code, rec.Body.String())
+	}
+
+	h = newProjectionHandler(t, resolver, allowAuthorizer{})
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/authz/projection?subject=alice", nil))
+	if rec.Code != http.StatusOK || resolver.subject != "alice" {
+		t.Fatalf("status = %d subject = %q, want 200 for alice", rec.Code, resolver.subject)
+	}
+}
+
+func TestHandlerProjectionWithoutResolverReturnsNotImplemented(t *testing.T) {
+	h := newTestHandler(t)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/authz/projection", nil))
+	if rec.Code != http.StatusNotImplemented {
+		t.Fatalf("status = %d, want 501 body=%s", rec.Code, rec.Body.String())
+	}
+}
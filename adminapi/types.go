@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"time"
 )
 
 var ErrInvalidRequest = errors.New("invalid authz request")
@@ -14,6 +15,20 @@ type Options struct {
 	PrincipalResolver PrincipalResolver
 	Authorizer        Authorizer
 	Provider          Provider
+	// Events is optional. When nil, the Provider is used if it implements
+	// EventSource; otherwise the events route reports 501.
+	Events EventSource
+	// Simulator is optional. When nil, the Provider is used if it implements
+	// Simulator; otherwise the simulate route reports 501.
+	Simulator Simulator
+	// Audit is optional. When nil, the Provider is used if it implements
+	// AuditLog; otherwise mutations are not recorded and the audit route
+	// reports 501.
+	Audit AuditLog
+	// Projections is optional. When nil, the Provider is used if it
+	// implements ProjectionResolver; otherwise the projection route reports
+	// 501.
+	Projections ProjectionResolver
 }
 
 type Principal struct {
@@ -44,6 +59,9 @@ type Scope struct {
 	Category    string   `json:"category,omitempty"`
 	OwnerPlugin string   `json:"owner_plugin,omitempty"`
 	OwnerModule string   `json:"owner_module,omitempty"`
+	Deprecated  bool     `json:"deprecated,omitempty"`
+	ReplacedBy  string   `json:"replaced_by,omitempty"`
+	Version     string   `json:"version,omitempty"`
 }
 
 type Capability struct {
@@ -53,12 +71,89 @@ type Capability struct {
 }
 
 type ResourceDeclaration struct {
-	Name    string   `json:"name"`
-	Actions []string `json:"actions,omitempty"`
+	Name           string   `json:"name"`
+	Actions        []string `json:"actions,omitempty"`
+	Context        string   `json:"context,omitempty"`
+	DisplayName    string   `json:"display_name,omitempty"`
+	Description    string   `json:"description,omitempty"`
+	Category       string   `json:"category,omitempty"`
+	LookupSourceID string   `json:"lookup_source_id,omitempty"`
+	OwnerPlugin    string   `json:"owner_plugin,omitempty"`
+	OwnerModule    string   `json:"owner_module,omitempty"`
+	Deprecated     bool     `json:"deprecated,omitempty"`
+	ReplacedBy     string   `json:"replaced_by,omitempty"`
+	Version        string   `json:"version,omitempty"`
 }
 
+type ActionDeclaration struct {
+	Name        string `json:"name"`
+	Context     string `json:"context,omitempty"`
+	Resource    string `json:"resource,omitempty"`
+	Description string `json:"description,omitempty"`
+	Category    string `json:"category,omitempty"`
+	OwnerPlugin string `json:"owner_plugin,omitempty"`
+	OwnerModule string `json:"owner_module,omitempty"`
+}
+
+type AttributeValue struct {
+	Value string `json:"value"`
+	Label string `json:"label,omitempty"`
+}
+
+type AttributeDeclaration struct {
+	Name           string           `json:"name"`
+	Context        string           `json:"context,omitempty"`
+	Target         string           `json:"target,omitempty"`
+	DataType       string           `json:"data_type,omitempty"`
+	AllowedValues  []AttributeValue `json:"allowed_values,omitempty"`
+	LookupSourceID string           `json:"lookup_source_id,omitempty"`
+	Description    string           `json:"description,omitempty"`
+	Category       string           `json:"category,omitempty"`
+	OwnerPlugin    string           `json:"owner_plugin,omitempty"`
+	OwnerModule    string           `json:"owner_module,omitempty"`
+}
+
+type RelationDeclaration struct {
+	Name        string `json:"name"`
+	Context     string `json:"context,omitempty"`
+	SubjectType string `json:"subject_type,omitempty"`
+	ObjectType  string `json:"object_type,omitempty"`
+	Description string `json:"description,omitempty"`
+	Category    string `json:"category,omitempty"`
+	OwnerPlugin string `json:"owner_plugin,omitempty"`
+	OwnerModule string `json:"owner_module,omitempty"`
+}
+
+type CapabilityRequirement struct {
+	Mode       string   `json:"mode"`
+	Operations []string `json:"operations,omitempty"`
+}
+
+type UIActionDeclaration struct {
+	ID                   string                  `json:"id"`
+	Context              string                  `json:"context,omitempty"`
+	Label                string                  `json:"label,omitempty"`
+	Route                string                  `json:"route,omitempty"`
+	RequiredScopes       []string                `json:"required_scopes,omitempty"`
+	RequiredCapabilities []CapabilityRequirement `json:"required_capabilities,omitempty"`
+	Description          string                  `json:"description,omitempty"`
+	Category             string                  `json:"category,omitempty"`
+	OwnerPlugin          string                  `json:"owner_plugin,omitempty"`
+	OwnerModule          string                  `json:"owner_module,omitempty"`
+}
+
+// Declarations mirrors the scope catalog's declaration set. Only Resources
+// existed originally; every other field is optional so older clients keep
+// decoding the payload unchanged.
 type Declarations struct {
-	Resources []ResourceDeclaration `json:"resources,omitempty"`
+	Resources   []ResourceDeclaration  `json:"resources,omitempty"`
+	Scopes      []Scope                `json:"scopes,omitempty"`
+	Actions     []ActionDeclaration    `json:"actions,omitempty"`
+	Attributes  []AttributeDeclaration `json:"attributes,omitempty"`
+	Relations   []RelationDeclaration  `json:"relations,omitempty"`
+	UIActions   []UIActionDeclaration  `json:"ui_actions,omitempty"`
+	OwnerPlugin string                 `json:"owner_plugin,omitempty"`
+	OwnerModule string                 `json:"owner_module,omitempty"`
 }
 
 type ProjectionInputs struct {
@@ -84,23 +179,39 @@ type PolicyRule struct {
 	Action  string `json:"action"`
 }
 
+// AttributeCondition matches one subject, resource, or environment attribute.
+// Operator is "equals" or "in".
+type AttributeCondition struct {
+	Target    string   `json:"target"`
+	Attribute string   `json:"attribute"`
+	Operator  string   `json:"operator,omitempty"`
+	Values    []string `json:"values,omitempty"`
+}
+
 type AttributePolicy struct {
-	ID       string `json:"id"`
-	Resource string `json:"resource,omitempty"`
-	Action   string `json:"action,omitempty"`
-	Effect   string `json:"effect,omitempty"`
+	ID          string               `json:"id"`
+	Resource    string               `json:"resource,omitempty"`
+	Action      string               `json:"action,omitempty"`
+	Effect      string               `json:"effect,omitempty"`
+	Context     string               `json:"context,omitempty"`
+	Conditions  []AttributeCondition `json:"conditions,omitempty"`
+	Description string               `json:"description,omitempty"`
+	OwnerPlugin string               `json:"owner_plugin,omitempty"`
+	OwnerModule string               `json:"owner_module,omitempty"`
 }
 
 type RelationTuple struct {
 	Subject  string `json:"subject"`
 	Relation string `json:"relation"`
 	Object   string `json:"object"`
+	Context  string `json:"context,omitempty"`
 }
 
 type RelationCheck struct {
 	Subject  string `json:"subject"`
 	Relation string `json:"relation"`
 	Object   string `json:"object"`
+	Context  string `json:"context,omitempty"`
 }
 
 type DecisionRequest struct {
@@ -150,6 +261,110 @@ type RoleAssignmentProvider interface {
 	RoleAssignments(context.Context, Principal) ([]RoleAssignment, error)
 }
 
+// SimulationChange is one proposed mutation for the simulate route. Op is
+// "add" (default) or "remove". Kind selects the populated field: "policy" and
+// "grouping" use Rule, "role" uses Role, "assignment" uses Assignment,
+// "relation" uses Tuple, and "abac_policy" uses AttributePolicy.
+type SimulationChange struct {
+	Op              string           `json:"op,omitempty"`
+	Kind            string           `json:"kind"`
+	Rule            []string         `json:"rule,omitempty"`
+	Role            *RoleAssignment  `json:"role,omitempty"`
+	Assignment      *RoleAssignment  `json:"assignment,omitempty"`
+	Tuple           *RelationTuple   `json:"tuple,omitempty"`
+	AttributePolicy *AttributePolicy `json:"abac_policy,omitempty"`
+}
+
+// SimulationProbe is a decision evaluated before and after the proposed
+// changes. Kind is "decision" (default) or "enforce" for raw Casbin requests.
+type SimulationProbe struct {
+	Kind                  string            `json:"kind,omitempty"`
+	Mode                  string            `json:"mode,omitempty"`
+	Subject               string            `json:"subject"`
+	Context               string            `json:"context,omitempty"`
+	Resource              string            `json:"resource,omitempty"`
+	Action                string            `json:"action,omitempty"`
+	Scope                 string            `json:"scope,omitempty"`
+	Relation              string            `json:"relation,omitempty"`
+	SubjectAttributes     map[string]string `json:"subject_attributes,omitempty"`
+	ResourceAttributes    map[string]string `json:"resource_attributes,omitempty"`
+	EnvironmentAttributes map[string]string `json:"environment_attributes,omitempty"`
+}
+
+type SimulationRequest struct {
+	Changes []SimulationChange `json:"changes"`
+	Probes  []SimulationProbe  `json:"probes,omitempty"`
+	// AllSubjectScopes probes every known subject against every declared
+	// scope in addition to Probes.
+	AllSubjectScopes bool `json:"all_subject_scopes,omitempty"`
+}
+
+// SimulationFlip reports one probe whose decision changed, or could not be
+// evaluated when Error is set. Change is "allow_to_deny" or "deny_to_allow".
+type SimulationFlip struct {
+	Probe        SimulationProbe `json:"probe"`
+	Before       bool            `json:"before"`
+	After        bool            `json:"after"`
+	BeforeReason string          `json:"before_reason,omitempty"`
+	AfterReason  string          `json:"after_reason,omitempty"`
+	Change       string          `json:"change,omitempty"`
+	Error        string          `json:"error,omitempty"`
+}
+
+type SimulationResult struct {
+	Evaluated int              `json:"evaluated"`
+	Granted   int              `json:"granted"`
+	Revoked   int              `json:"revoked"`
+	Flips     []SimulationFlip `json:"flips"`
+	Errors    []SimulationFlip `json:"errors,omitempty"`
+}
+
+// Simulator applies proposed changes to an isolated copy of provider state and
+// reports which decisions flip. The live provider must not be modified.
+type Simulator interface {
+	Simulate(context.Context, Principal, SimulationRequest) (SimulationResult, error)
+}
+
+// Change event types streamed by the events route.
+const (
+	EventPolicyAdded              = "policy.added"
+	EventPolicyRemoved            = "policy.removed"
+	EventRoleUpserted             = "role.upserted"
+	EventRoleAssigned             = "role.assigned"
+	EventRoleUnassigned           = "role.unassigned"
+	EventRelationWritten          = "relation.written"
+	EventRelationDeleted          = "relation.deleted"
+	EventAttributePolicyUpserted  = "abac_policy.upserted"
+	EventAttributePolicyRemoved   = "abac_policy.removed"
+	EventDeclarationsRegistered   = "declarations.registered"
+	EventDeclarationsUnregistered = "declarations.unregistered"
+	// EventProviderDrift reports that a provider's local state had diverged
+	// from its backend, such as Ory Keto, when it was reconciled.
+	EventProviderDrift = "provider.drift"
+	// EventDataUpdated reports a write to the data document of a policy
+	// provider such as OPA.
+	EventDataUpdated = "data.updated"
+	// EventStreamReset tells the subscriber that events were missed and its
+	// cached state should be reloaded from the read routes.
+	EventStreamReset = "stream.reset"
+)
+
+type Event struct {
+	ID       string         `json:"id"`
+	Type     string         `json:"type"`
+	Source   string         `json:"source,omitempty"`
+	Provider string         `json:"provider,omitempty"`
+	Time     time.Time      `json:"time"`
+	Data     map[string]any `json:"data,omitempty"`
+}
+
+// EventSource streams change events newer than lastEventID. An empty
+// lastEventID subscribes to new events only. The returned channel must be
+// closed when ctx is done.
+type EventSource interface {
+	Events(ctx context.Context, principal Principal, lastEventID string) (<-chan Event, error)
+}
+
 type RouteCatalog struct {
 	ByPath map[string]Route
 }
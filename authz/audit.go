@@ -0,0 +1,76 @@
+package authz
+
+import (
+	"context"
+
+	"github.com/GoCodeAlone/workflow-plugin-authz/adminapi"
+	"github.com/GoCodeAlone/workflow-plugin-authz/internal"
+)
+
+// moduleAuditLog bridges the adminapi audit trail to a registered
+// authz.audit module.
+type moduleAuditLog struct {
+	module string
+}
+
+// NewAuditLog returns an adminapi.AuditLog that appends to the hash-chained
+// log of the authz.audit module registered under moduleName (the first
+// registered audit module when empty), the same log written by mutating
+// pipeline steps. Pass it as adminapi.Options.Audit.
+func NewAuditLog(moduleName string) adminapi.AuditLog {
+	return moduleAuditLog{module: moduleName}
+}
+
+func (l moduleAuditLog) Record(ctx context.Context, record adminapi.AuditRecord) error {
+	_, err := internal.RecordAuditEntry(ctx, l.module, internal.AuditEntry{
+		Time:       record.Time,
+		Actor:      record.Actor,
+		ActorEmail: record.ActorEmail,
+		Source:     record.Source,
+		Operation:  record.Operation,
+		Module:     record.Module,
+		Resource:   record.Resource,
+		Action:     record.Action,
+		Context:    record.Context,
+		Before:     record.Before,
+		After:      record.After,
+		Reason:     record.Reason,
+	})
+	return err
+}
+
+func (l moduleAuditLog) AuditRecords(ctx context.Context, _ adminapi.Principal, filter adminapi.AuditFilter) ([]adminapi.AuditRecord, error) {
+	entries, err := internal.ListAuditEntries(ctx, l.module, internal.AuditFilter{
+		Actor:     filter.Actor,
+		Operation: filter.Operation,
+		Resource:  filter.Resource,
+		Context:   filter.Context,
+		Since:     filter.Since,
+		Until:     filter.Until,
+		Limit:     filter.Limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	records := make([]adminapi.AuditRecord, 0, len(entries))
+	for _, entry := range entries {
+		records = append(records, adminapi.AuditRecord{
+			Seq:        entry.Seq,
+			Time:       entry.Time,
+			Actor:      entry.Actor,
+			ActorEmail: entry.ActorEmail,
+			Source:     entry.Source,
+			Operation:  entry.Operation,
+			Module:     entry.Module,
+			Resource:   entry.Resource,
+			Action:     entry.Action,
+			Context:    entry.Context,
+			Before:     entry.Before,
+			After:      entry.After,
+			Reason:     entry.Reason,
+			PrevHash:   entry.PrevHash,
+			Hash:       entry.Hash,
+		})
+	}
+	return records, nil
+}
@@ -1,13 +1,18 @@
-// Package authz provides engine-native factories for the Casbin RBAC module
-// and pipeline steps. Import this package when embedding the authz plugin
-// directly into an engine plugin (avoiding gRPC / duplicate step registration).
+// Package authz provides engine-native factories for the authz plugin's
+// modules and pipeline steps. Import this package when embedding the authz
+// plugin directly into an engine plugin (avoiding gRPC / duplicate step
+// registration).
+//
+// Each module is registered in the modular service registry under its
+// configured name. authz.casbin is provided as a wrapper with Enforce,
+// AddPolicy, and RemovePolicy; the other modules are provided as the module
+// itself, which implements the authzsdk provider interfaces for the models it
+// supports and MethodInvoker for its service methods.
 //
 // Usage in a host plugin:
 //
 //	func (p *MyPlugin) ModuleFactories() map[string]plugin.ModuleFactory {
-//	    return map[string]plugin.ModuleFactory{
-//	        "authz.casbin": authz.NewCasbinModuleFactory(),
-//	    }
+//	    return authz.ModuleFactories()
 //	}
 //
 //	func (p *MyPlugin) StepFactories() map[string]plugin.StepFactory {
@@ -64,7 +69,9 @@ func (m *casbinModuleWrapper) ProvidesServices() []modular.ServiceProvider {
 	}
 }
 
-func (m *casbinModuleWrapper) RequiresServices() []modular.ServiceDependency { return nil }
+func (m *casbinModuleWrapper) RequiresServices() []modular.ServiceDependency {
+	return catalogDependency(m.config)
+}
 
 func (m *casbinModuleWrapper) Start(ctx context.Context) error {
 	if m.inner == nil {
@@ -125,50 +132,20 @@ func (s *stepAdapter) Execute(ctx context.Context, pc *module.PipelineContext) (
 	}, nil
 }
 
-// StepFactories returns all authz step factories as engine-native StepFactory values.
-// Step types: step.authz_check_casbin, step.authz_add_policy, step.authz_remove_policy, step.authz_role_assign.
+// StepFactories returns engine-native factories for every authz step type:
+// the Casbin steps, the provider-neutral steps such as step.authz_check and
+// step.authz_require_capabilities, and the step.permit_* steps.
 func StepFactories() map[string]plugin.StepFactory {
-	return map[string]plugin.StepFactory{
-		"step.authz_check_casbin":  newCasbinCheckFactory(),
-		"step.authz_add_policy":    newAuthzAddPolicyFactory(),
-		"step.authz_remove_policy": newAuthzRemovePolicyFactory(),
-		"step.authz_role_assign":   newAuthzRoleAssignFactory(),
-	}
-}
-
-func newCasbinCheckFactory() plugin.StepFactory {
-	return func(name string, config map[string]any, _ modular.Application) (any, error) {
-		inner, err := internal.NewCasbinCheckStep(name, config)
-		if err != nil {
-			return nil, err
-		}
-		return &stepAdapter{name: name, inner: inner}, nil
-	}
-}
-
-func newAuthzAddPolicyFactory() plugin.StepFactory {
-	return func(name string, config map[string]any, _ modular.Application) (any, error) {
-		inner, err := internal.NewAddPolicyStep(name, config)
-		if err != nil {
-			return nil, err
-		}
-		return &stepAdapter{name: name, inner: inner}, nil
-	}
-}
-
-func newAuthzRemovePolicyFactory() plugin.StepFactory {
-	return func(name string, config map[string]any, _ modular.Application) (any, error) {
-		inner, err := internal.NewRemovePolicyStep(name, config)
-		if err != nil {
-			return nil, err
-		}
-		return &stepAdapter{name: name, inner: inner}, nil
+	factories := make(map[string]plugin.StepFactory)
+	for _, typeName := range internal.StepTypes() {
+		factories[typeName] = newStepFactory(typeName)
 	}
+	return factories
 }
 
-func newAuthzRoleAssignFactory() plugin.StepFactory {
+func newStepFactory(typeName string) plugin.StepFactory {
 	return func(name string, config map[string]any, _ modular.Application) (any, error) {
-		inner, err := internal.NewRoleAssignStep(name, config)
+		inner, err := internal.NewStep(typeName, name, config)
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,74 @@
+package authz_test
+
+import (
+	"testing"
+
+	"github.com/GoCodeAlone/modular"
+	"github.com/GoCodeAlone/workflow/module"
+
+	"github.com/GoCodeAlone/workflow-plugin-authz/authz"
+	"github.com/GoCodeAlone/workflow-plugin-authz/authzsdk"
+	"github.com/GoCodeAlone/workflow-plugin-authz/internal"
+)
+
+type discardLogger struct{}
+
+func (discardLogger) Info(string, ...any)  {}
+func (discardLogger) Error(string, ...any) {}
+func (discardLogger) Warn(string, ...any)  {}
+func (discardLogger) Debug(string, ...any) {}
+
+func TestFactoriesCoverEveryType(t *testing.T) {
+	steps := authz.StepFactories()
+	for _, typeName := range internal.StepTypes() {
+		if steps[typeName] == nil {
+			t.Errorf("StepFactories is missing %s", typeName)
+		}
+	}
+	modules := authz.ModuleFactories()
+	for _, typeName := range internal.ModuleTypes() {
+		if modules[typeName] == nil {
+			t.Errorf("ModuleFactories is missing %s", typeName)
+		}
+	}
+
+	step, err := steps["step.authz_require_capabilities"]("require", map[string]any{
+		"module": "missing", "provider": "keto", "requirements": []any{map[string]any{"mode": "rbac"}},
+	}, nil)
+	if err != nil {
+		t.Fatalf("create step: %v", err)
+	}
+	if _, ok := step.(module.PipelineStep); !ok {
+		t.Fatalf("step is %T, want a module.PipelineStep", step)
+	}
+}
+
+func TestModulesAreProvidedAsTypedServices(t *testing.T) {
+	app := modular.NewStdApplication(modular.NewStdConfigProvider(struct{}{}), discardLogger{})
+	modules := authz.ModuleFactories()
+	// Registered before its catalog; RequiresServices orders it after.
+	app.RegisterModule(modules["authz.keto"]("embedded-keto", map[string]any{
+		"read_url": "http://127.0.0.1:1", "write_url": "http://127.0.0.1:1", "catalog": "embedded-catalog",
+	}))
+	app.RegisterModule(modules["authz.scope_catalog"]("embedded-catalog", map[string]any{}))
+	if err := app.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	var roles authzsdk.ScopeRoleProvider
+	if err := app.GetService("embedded-keto", &roles); err != nil {
+		t.Fatalf("GetService(embedded-keto) as ScopeRoleProvider: %v", err)
+	}
+	if _, ok := roles.(authzsdk.RelationshipProvider); !ok {
+		t.Fatal("expected authz.keto to be a RelationshipProvider as well")
+	}
+	var catalog authz.MethodInvoker
+	if err := app.GetService("embedded-catalog", &catalog); err != nil {
+		t.Fatalf("GetService(embedded-catalog) as MethodInvoker: %v", err)
+	}
+	if _, err := catalog.InvokeMethod("RegisterScopes", map[string]any{
+		"scopes": []any{map[string]any{"name": "billing:invoice:read"}},
+	}); err != nil {
+		t.Fatalf("RegisterScopes: %v", err)
+	}
+}
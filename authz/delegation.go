@@ -0,0 +1,36 @@
+package authz
+
+import (
+	"context"
+
+	"github.com/GoCodeAlone/workflow-plugin-authz/adminapi"
+	"github.com/GoCodeAlone/workflow-plugin-authz/internal"
+)
+
+// moduleScopeRoleSource bridges adminapi.ScopeAuthorizer to the scope roles of
+// a registered authz module.
+type moduleScopeRoleSource struct {
+	module   string
+	provider string
+}
+
+// NewScopeRoleSource returns an adminapi.ScopeRoleSource backed by the
+// ScopeRoleProvider of the authz module registered under moduleName. provider
+// is "casbin", "keto", "permit", or the kind of a provider registered with
+// authzsdk.RegisterProvider. Use it with adminapi.NewScopeAuthorizer to enable
+// delegated administration.
+func NewScopeRoleSource(moduleName, provider string) adminapi.ScopeRoleSource {
+	return moduleScopeRoleSource{module: moduleName, provider: provider}
+}
+
+func (s moduleScopeRoleSource) CheckScope(ctx context.Context, subject, contextName, scope string) (bool, error) {
+	return internal.CheckModuleScope(ctx, s.module, s.provider, subject, contextName, scope)
+}
+
+func (s moduleScopeRoleSource) RoleScopes(ctx context.Context, contextName, role string) ([]string, error) {
+	return internal.ModuleRoleScopes(ctx, s.module, s.provider, contextName, role)
+}
+
+func (s moduleScopeRoleSource) Contexts(ctx context.Context) ([]string, error) {
+	return internal.ModuleScopeContexts(ctx, s.module, s.provider)
+}
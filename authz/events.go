@@ -0,0 +1,75 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/GoCodeAlone/workflow-plugin-authz/adminapi"
+	"github.com/GoCodeAlone/workflow-plugin-authz/internal"
+)
+
+// changeEventSource bridges the in-process change feed populated by authz
+// modules and providers to the adminapi events route.
+type changeEventSource struct{}
+
+// NewEventSource returns an adminapi.EventSource streaming the change events
+// emitted by authz.casbin, authz.scope_catalog, authz.keto and permit.provider
+// modules running in this process. Pass it as adminapi.Options.Events.
+func NewEventSource() adminapi.EventSource {
+	return changeEventSource{}
+}
+
+func (changeEventSource) Events(ctx context.Context, _ adminapi.Principal, lastEventID string) (<-chan adminapi.Event, error) {
+	backlog, events, cancel, complete, err := internal.ResumeChangeEvents(lastEventID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: last event id %q", adminapi.ErrInvalidRequest, lastEventID)
+	}
+	out := make(chan adminapi.Event)
+	go func() {
+		defer close(out)
+		defer cancel()
+		send := func(event adminapi.Event) bool {
+			select {
+			case out <- event:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		if !complete && !send(adminapi.Event{Type: adminapi.EventStreamReset}) {
+			return
+		}
+		for _, event := range backlog {
+			if !send(adminEvent(event)) {
+				return
+			}
+		}
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-events:
+				if !ok {
+					// The feed dropped this subscriber for falling behind;
+					// ending the stream lets the client resume by event ID.
+					return
+				}
+				if !send(adminEvent(event)) {
+					return
+				}
+			}
+		}
+	}()
+	return out, nil
+}
+
+func adminEvent(event internal.ChangeEvent) adminapi.Event {
+	return adminapi.Event{
+		ID:       event.EventID(),
+		Type:     string(event.Type),
+		Source:   event.Source,
+		Provider: event.Provider,
+		Time:     event.Time,
+		Data:     event.Data,
+	}
+}
@@ -0,0 +1,74 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor authorizes each unary call before invoking the
+// handler. Rejections map to Unauthenticated and PermissionDenied, and a
+// failed check to Internal. The decision of an authorized call is available
+// from DecisionFromContext.
+func (g *Guard) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		decision, err := g.Authorize(grpcRequest(ctx, info.FullMethod))
+		if err != nil {
+			return nil, grpcAuthzError(err)
+		}
+		return handler(withDecision(ctx, decision), req)
+	}
+}
+
+// StreamServerInterceptor authorizes each stream when it is opened, as
+// UnaryServerInterceptor does for unary calls.
+func (g *Guard) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		decision, err := g.Authorize(grpcRequest(ss.Context(), info.FullMethod))
+		if err != nil {
+			return grpcAuthzError(err)
+		}
+		return handler(srv, &authorizedStream{ServerStream: ss, ctx: withDecision(ss.Context(), decision)})
+	}
+}
+
+func grpcRequest(ctx context.Context, fullMethod string) *Request {
+	header := http.Header{}
+	md, _ := metadata.FromIncomingContext(ctx)
+	for key, values := range md {
+		for _, value := range values {
+			header.Add(key, value)
+		}
+	}
+	return &Request{
+		Context: ctx,
+		Method:  fullMethod[strings.LastIndex(fullMethod, "/")+1:],
+		Path:    fullMethod,
+		Header:  header,
+	}
+}
+
+func grpcAuthzError(err error) error {
+	var authzErr *Error
+	if !errors.As(err, &authzErr) {
+		return status.Error(codes.Internal, "authorization check failed")
+	}
+	if authzErr.Status == http.StatusUnauthorized {
+		return status.Error(codes.Unauthenticated, authzErr.Message)
+	}
+	return status.Error(codes.PermissionDenied, authzErr.Message)
+}
+
+// authorizedStream carries the decision in the stream's context.
+type authorizedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authorizedStream) Context() context.Context { return s.ctx }
@@ -0,0 +1,314 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/GoCodeAlone/workflow-plugin-authz/authzsdk"
+	"github.com/GoCodeAlone/workflow-plugin-authz/internal"
+)
+
+// Request is the transport-neutral view of an incoming call that extractors
+// read. The HTTP middleware and the gRPC interceptors build it for you.
+type Request struct {
+	Context context.Context
+	// Method is the HTTP method, or the gRPC method name ("GetInvoice").
+	Method string
+	// Path is the URL path, or the full gRPC method
+	// ("/billing.v1.Billing/GetInvoice").
+	Path string
+	// Header holds the HTTP headers, or the incoming gRPC metadata.
+	Header http.Header
+	// Claims holds the claims returned by the guard's ClaimsFunc.
+	Claims map[string]any
+}
+
+// Extractor resolves one request dimension, such as the subject or object.
+type Extractor func(*Request) (string, error)
+
+// ClaimsFunc returns the verified token claims of a request. Returning
+// ErrNoCredentials rejects the request with 401.
+type ClaimsFunc func(*Request) (map[string]any, error)
+
+// ErrNoCredentials reports that a request carries no credentials.
+var ErrNoCredentials = errors.New("missing credentials")
+
+// GuardConfig configures a Guard.
+type GuardConfig struct {
+	// Module is the name of the authz module to check against (default: "authz").
+	Module string
+	// Provider selects how requests are decided. When empty, the guard calls
+	// Enforce on the authz.casbin module, as step.authz_check_casbin does.
+	// Otherwise it is a provider kind ("casbin", "keto", "permit", or one
+	// registered with authzsdk.RegisterProvider) and the guard uses the
+	// provider-neutral decision API, as step.authz_check does.
+	Provider string
+	// Mode forces RBAC, ABAC, or ReBAC for the decision API; when empty it is
+	// inferred from the fields that resolve to a value.
+	Mode authzsdk.AuthzCapability
+
+	// Subject, Object, and Action are required. With the decision API the
+	// object is the resource.
+	Subject Extractor
+	Object  Extractor
+	Action  Extractor
+	// Extra are additional Casbin request dimensions inserted between the
+	// subject and the object, in order. They are ignored by the decision API.
+	Extra []Extractor
+	// Context, Scope, and Relation are optional decision API fields.
+	Context  Extractor
+	Scope    Extractor
+	Relation Extractor
+
+	// Claims, when set, is called before the extractors and its result is
+	// available to Claim extractors.
+	Claims ClaimsFunc
+}
+
+// Guard authorizes requests against an embedded authz module. Use its
+// Middleware for net/http servers and its interceptors for gRPC servers.
+type Guard struct {
+	config GuardConfig
+}
+
+// NewGuard validates config and returns a Guard.
+func NewGuard(config GuardConfig) (*Guard, error) {
+	if config.Module == "" {
+		config.Module = "authz"
+	}
+	switch {
+	case config.Subject == nil:
+		return nil, fmt.Errorf("authz guard: Subject extractor is required")
+	case config.Object == nil:
+		return nil, fmt.Errorf("authz guard: Object extractor is required")
+	case config.Action == nil:
+		return nil, fmt.Errorf("authz guard: Action extractor is required")
+	}
+	return &Guard{config: config}, nil
+}
+
+// Decision is the outcome of an authorized request.
+type Decision struct {
+	Subject string
+	Object  string
+	Action  string
+	Extra   []string
+	// Reason is the provider's explanation, when the decision API gave one.
+	Reason string
+}
+
+// Error is returned by Authorize when a request is rejected. Status is 401
+// when the request has no subject or credentials and 403 when it is denied.
+type Error struct {
+	Status  int
+	Message string
+}
+
+func (e *Error) Error() string { return e.Message }
+
+// Authorize resolves the request dimensions and checks them against the
+// module. It returns an *Error when the request is rejected, and any other
+// error when the check itself failed.
+func (g *Guard) Authorize(req *Request) (Decision, error) {
+	if g.config.Claims != nil {
+		claims, err := g.config.Claims(req)
+		if err != nil {
+			return Decision{}, &Error{Status: http.StatusUnauthorized, Message: "unauthorized: " + err.Error()}
+		}
+		req.Claims = claims
+	}
+	subject, err := g.config.Subject(req)
+	if err != nil || subject == "" {
+		return Decision{}, &Error{Status: http.StatusUnauthorized, Message: "unauthorized: missing authentication subject"}
+	}
+	decision := Decision{Subject: subject}
+	if decision.Object, err = g.config.Object(req); err != nil {
+		return Decision{}, forbidden("forbidden: resolve object: %v", err)
+	}
+	if decision.Action, err = g.config.Action(req); err != nil {
+		return Decision{}, forbidden("forbidden: resolve action: %v", err)
+	}
+
+	if g.config.Provider == "" {
+		for i, extract := range g.config.Extra {
+			value, err := extract(req)
+			if err != nil {
+				return Decision{}, forbidden("forbidden: resolve extra[%d]: %v", i, err)
+			}
+			decision.Extra = append(decision.Extra, value)
+		}
+		allowed, err := internal.EnforceModule(g.config.Module, subject, decision.Object, decision.Action, decision.Extra...)
+		if err != nil {
+			return Decision{}, fmt.Errorf("authz guard: enforce: %w", err)
+		}
+		if !allowed {
+			return Decision{}, forbidden("forbidden: %s is not permitted to %s %s", subject, decision.Action, decision.Object)
+		}
+		return decision, nil
+	}
+
+	input := internal.AuthorizationDecisionInput{
+		Mode:     g.config.Mode,
+		Subject:  subject,
+		Resource: decision.Object,
+		Action:   decision.Action,
+	}
+	for _, field := range []struct {
+		name    string
+		extract Extractor
+		value   *string
+	}{
+		{"context", g.config.Context, &input.Context},
+		{"scope", g.config.Scope, &input.Scope},
+		{"relation", g.config.Relation, &input.Relation},
+	} {
+		if field.extract == nil {
+			continue
+		}
+		if *field.value, err = field.extract(req); err != nil {
+			return Decision{}, forbidden("forbidden: resolve %s: %v", field.name, err)
+		}
+	}
+	out, err := internal.DecideModuleAuthorization(req.Context, g.config.Module, g.config.Provider, input)
+	if err != nil {
+		return Decision{}, fmt.Errorf("authz guard: decide: %w", err)
+	}
+	if !out.Allowed {
+		message := fmt.Sprintf("forbidden: %s is not permitted to %s %s", subject, decision.Action, decision.Object)
+		if out.Reason != "" {
+			message += ": " + out.Reason
+		}
+		return Decision{}, &Error{Status: http.StatusForbidden, Message: message}
+	}
+	decision.Reason = out.Reason
+	return decision, nil
+}
+
+func forbidden(format string, args ...any) *Error {
+	return &Error{Status: http.StatusForbidden, Message: fmt.Sprintf(format, args...)}
+}
+
+type decisionKey struct{}
+
+// DecisionFromContext returns the decision the guard attached to the context
+// of an authorized request.
+func DecisionFromContext(ctx context.Context) (Decision, bool) {
+	decision, ok := ctx.Value(decisionKey{}).(Decision)
+	return decision, ok
+}
+
+func withDecision(ctx context.Context, decision Decision) context.Context {
+	return context.WithValue(ctx, decisionKey{}, decision)
+}
+
+// --- Extractors ---
+
+// Static always resolves to value.
+func Static(value string) Extractor {
+	return func(*Request) (string, error) { return value, nil }
+}
+
+// Method resolves to the HTTP method, or the gRPC method name.
+func Method() Extractor {
+	return func(req *Request) (string, error) { return req.Method, nil }
+}
+
+// Path resolves to the URL path, or the full gRPC method.
+func Path() Extractor {
+	return func(req *Request) (string, error) { return req.Path, nil }
+}
+
+// Header resolves to the named header or gRPC metadata value.
+func Header(name string) Extractor {
+	return func(req *Request) (string, error) {
+		value := req.Header.Get(name)
+		if value == "" {
+			return "", fmt.Errorf("header %q is not set", name)
+		}
+		return value, nil
+	}
+}
+
+// PathParam matches the path against template, whose segments are literals
+// or {name} placeholders, and resolves to the segment in the placeholder
+// called param. For example PathParam("/tenants/{tenant}/invoices/{id}",
+// "tenant").
+func PathParam(template, param string) Extractor {
+	parts := strings.Split(strings.Trim(template, "/"), "/")
+	return func(req *Request) (string, error) {
+		segments := strings.Split(strings.Trim(req.Path, "/"), "/")
+		if len(segments) != len(parts) {
+			return "", fmt.Errorf("path %q does not match %q", req.Path, template)
+		}
+		value, found := "", false
+		for i, part := range parts {
+			if strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") {
+				if part[1:len(part)-1] == param {
+					value, found = segments[i], true
+				}
+				continue
+			}
+			if part != segments[i] {
+				return "", fmt.Errorf("path %q does not match %q", req.Path, template)
+			}
+		}
+		if !found || value == "" {
+			return "", fmt.Errorf("path %q has no %q parameter", req.Path, param)
+		}
+		return value, nil
+	}
+}
+
+// Claim resolves to the named claim of the request's Claims. Dotted names
+// address nested claims, such as "org.id". Strings, numbers, and booleans
+// are accepted.
+func Claim(name string) Extractor {
+	return func(req *Request) (string, error) {
+		var value any = req.Claims
+		for _, key := range strings.Split(name, ".") {
+			object, ok := value.(map[string]any)
+			if !ok {
+				return "", fmt.Errorf("claim %q is not set", name)
+			}
+			if value, ok = object[key]; !ok {
+				return "", fmt.Errorf("claim %q is not set", name)
+			}
+		}
+		switch v := value.(type) {
+		case string:
+			return v, nil
+		case float64:
+			// JSON numbers decode as float64; %v would print large IDs in
+			// exponent form.
+			return strconv.FormatFloat(v, 'f', -1, 64), nil
+		case int, int64, bool:
+			return fmt.Sprint(v), nil
+		default:
+			return "", fmt.Errorf("claim %q is not a string, number, or boolean", name)
+		}
+	}
+}
+
+// BearerClaims returns a ClaimsFunc that verifies the bearer token in the
+// Authorization header or metadata with keyFunc and returns its claims.
+func BearerClaims(keyFunc jwt.Keyfunc, options ...jwt.ParserOption) ClaimsFunc {
+	parser := jwt.NewParser(options...)
+	return func(req *Request) (map[string]any, error) {
+		header := req.Header.Get("Authorization")
+		token, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok || strings.TrimSpace(token) == "" {
+			return nil, ErrNoCredentials
+		}
+		claims := jwt.MapClaims{}
+		if _, err := parser.ParseWithClaims(strings.TrimSpace(token), claims, keyFunc); err != nil {
+			return nil, fmt.Errorf("invalid bearer token: %w", err)
+		}
+		return claims, nil
+	}
+}
@@ -0,0 +1,198 @@
+package authz_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/GoCodeAlone/workflow-plugin-authz/authz"
+	"github.com/GoCodeAlone/workflow-plugin-authz/internal"
+)
+
+const guardModel = `
+[request_definition]
+r = sub, dom, obj, act
+
+[policy_definition]
+p = sub, dom, obj, act
+
+[policy_effect]
+e = some(where (p.eft == allow))
+
+[matchers]
+m = r.sub == p.sub && r.dom == p.dom && r.obj == p.obj && r.act == p.act
+`
+
+func newGuardModule(t *testing.T, name string) {
+	t.Helper()
+	mod, err := internal.NewModule("authz.casbin", name, map[string]any{
+		"model":    guardModel,
+		"policies": []any{[]any{"alice", "acme", "invoices", "GET"}},
+	})
+	if err != nil {
+		t.Fatalf("NewModule: %v", err)
+	}
+	if err := mod.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+}
+
+func TestGuardMiddlewareEnforces(t *testing.T) {
+	newGuardModule(t, "guard-http")
+	secret := []byte("test-secret")
+	guard, err := authz.NewGuard(authz.GuardConfig{
+		Module:  "guard-http",
+		Claims:  authz.BearerClaims(func(*jwt.Token) (any, error) { return secret, nil }),
+		Subject: authz.Claim("sub"),
+		Extra:   []authz.Extractor{authz.PathParam("/tenants/{tenant}/{collection}", "tenant")},
+		Object:  authz.PathParam("/tenants/{tenant}/{collection}", "collection"),
+		Action:  authz.Method(),
+	})
+	if err != nil {
+		t.Fatalf("NewGuard: %v", err)
+	}
+	handler := guard.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		decision, _ := authz.DecisionFromContext(r.Context())
+		_, _ = w.Write([]byte(decision.Subject))
+	}))
+	token := func(subject string) string {
+		signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": subject}).SignedString(secret)
+		if err != nil {
+			t.Fatalf("sign: %v", err)
+		}
+		return "Bearer " + signed
+	}
+
+	for _, tc := range []struct {
+		name, method, path, auth string
+		status                   int
+	}{
+		{"allowed", http.MethodGet, "/tenants/acme/invoices", token("alice"), http.StatusOK},
+		{"wrong tenant", http.MethodGet, "/tenants/globex/invoices", token("alice"), http.StatusForbidden},
+		{"wrong action", http.MethodDelete, "/tenants/acme/invoices", token("alice"), http.StatusForbidden},
+		{"no token", http.MethodGet, "/tenants/acme/invoices", "", http.StatusUnauthorized},
+		{"bad token", http.MethodGet, "/tenants/acme/invoices", "Bearer nope", http.StatusUnauthorized},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+			if tc.auth != "" {
+				req.Header.Set("Authorization", tc.auth)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tc.status {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, tc.status, rec.Body)
+			}
+			if tc.status == http.StatusOK {
+				if rec.Body.String() != "alice" {
+					t.Fatalf("body = %q, want the decision subject", rec.Body)
+				}
+				return
+			}
+			var body map[string]string
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body["error"] == "" {
+				t.Fatalf("body = %s, want a JSON error", rec.Body)
+			}
+			if got := rec.Header().Get("Content-Type"); got != "application/json" {
+				t.Fatalf("Content-Type = %q", got)
+			}
+		})
+	}
+}
+
+func TestGuardMiddlewareReportsCheckFailures(t *testing.T) {
+	guard, err := authz.NewGuard(authz.GuardConfig{
+		Module:  "guard-missing",
+		Subject: authz.Header("X-User"),
+		Object:  authz.Path(),
+		Action:  authz.Method(),
+	})
+	if err != nil {
+		t.Fatalf("NewGuard: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/x", nil)
+	req.Header.Set("X-User", "alice")
+	rec := httptest.NewRecorder()
+	guard.Middleware(http.NotFoundHandler()).ServeHTTP(rec, req)
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500 for an unknown module", rec.Code)
+	}
+}
+
+func TestGuardGRPCInterceptors(t *testing.T) {
+	newGuardModule(t, "guard-grpc")
+	guard, err := authz.NewGuard(authz.GuardConfig{
+		Module:  "guard-grpc",
+		Subject: authz.Header("x-user"),
+		Extra:   []authz.Extractor{authz.Header("x-tenant")},
+		Object:  authz.Static("invoices"),
+		Action:  authz.Method(),
+	})
+	if err != nil {
+		t.Fatalf("NewGuard: %v", err)
+	}
+	unary := guard.UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/billing.v1.Billing/GET"}
+	handler := func(ctx context.Context, _ any) (any, error) {
+		decision, ok := authz.DecisionFromContext(ctx)
+		if !ok {
+			t.Fatal("decision missing from handler context")
+		}
+		return decision.Subject, nil
+	}
+	call := func(pairs ...string) (any, error) {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(pairs...))
+		return unary(ctx, nil, info, handler)
+	}
+
+	if got, err := call("x-user", "alice", "x-tenant", "acme"); err != nil || got != "alice" {
+		t.Fatalf("allowed call = %v, %v", got, err)
+	}
+	if _, err := call("x-user", "alice", "x-tenant", "globex"); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("denied call code = %v, want PermissionDenied", status.Code(err))
+	}
+	if _, err := call("x-tenant", "acme"); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("anonymous call code = %v, want Unauthenticated", status.Code(err))
+	}
+
+	stream := guard.StreamServerInterceptor()
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-user", "alice", "x-tenant", "acme"))
+	err = stream(nil, &fakeStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: "/billing.v1.Billing/GET"}, func(_ any, ss grpc.ServerStream) error {
+		if _, ok := authz.DecisionFromContext(ss.Context()); !ok {
+			t.Fatal("decision missing from stream context")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("stream: %v", err)
+	}
+}
+
+type fakeStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *fakeStream) Context() context.Context { return s.ctx }
+
+func TestClaimFormatsLargeNumericClaims(t *testing.T) {
+	var claims map[string]any
+	if err := json.Unmarshal([]byte(`{"sub": 123456789012, "org": {"id": 1.5}}`), &claims); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	req := &authz.Request{Claims: claims}
+	if sub, err := authz.Claim("sub")(req); err != nil || sub != "123456789012" {
+		t.Fatalf("Claim(sub) = %q, %v; want 123456789012", sub, err)
+	}
+	if org, err := authz.Claim("org.id")(req); err != nil || org != "1.5" {
+		t.Fatalf("Claim(org.id) = %q, %v; want 1.5", org, err)
+	}
+}
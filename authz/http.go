@@ -0,0 +1,39 @@
+package authz
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// Middleware authorizes each request before calling next. Rejected requests
+// get a JSON {"error": "..."} body with status 401 or 403, matching the
+// response of step.authz_check_casbin; a failed check gets status 500. The
+// decision of an authorized request is available from DecisionFromContext.
+func (g *Guard) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		decision, err := g.Authorize(&Request{
+			Context: r.Context(),
+			Method:  r.Method,
+			Path:    r.URL.Path,
+			Header:  r.Header,
+		})
+		if err != nil {
+			writeAuthzError(w, err)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(withDecision(r.Context(), decision)))
+	})
+}
+
+func writeAuthzError(w http.ResponseWriter, err error) {
+	status, message := http.StatusInternalServerError, "authorization check failed"
+	var authzErr *Error
+	if errors.As(err, &authzErr) {
+		status, message = authzErr.Status, authzErr.Message
+	}
+	body, _ := json.Marshal(map[string]string{"error": message})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
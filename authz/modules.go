@@ -0,0 +1,125 @@
+package authz
+
+import (
+	"context"
+
+	"github.com/GoCodeAlone/modular"
+	"github.com/GoCodeAlone/workflow/plugin"
+
+	"github.com/GoCodeAlone/workflow-plugin-authz/internal"
+)
+
+// MethodInvoker is implemented by every module service. It calls the service
+// methods the plugin declares in plugin.contracts.json, such as
+// RegisterScopes on an authz.scope_catalog or ExportRelationships on an
+// authz.keto module.
+type MethodInvoker interface {
+	InvokeMethod(method string, args map[string]any) (map[string]any, error)
+}
+
+// providerModuleWrapper adapts a plugin module to the engine's modular.Module
+// interface. Once initialized, the module itself is provided as the service,
+// so dependants can assert the authzsdk provider interfaces it implements
+// (authzsdk.ScopeRoleProvider, authzsdk.RelationshipProvider, ...) or
+// MethodInvoker.
+type providerModuleWrapper struct {
+	typeName string
+	name     string
+	config   map[string]any
+	inner    internal.ModuleInstance
+}
+
+func newModuleFactory(typeName string) plugin.ModuleFactory {
+	return func(name string, config map[string]any) modular.Module {
+		return &providerModuleWrapper{typeName: typeName, name: name, config: config}
+	}
+}
+
+// NewKetoModuleFactory returns an engine-compatible ModuleFactory for "authz.keto".
+func NewKetoModuleFactory() plugin.ModuleFactory { return newModuleFactory("authz.keto") }
+
+// NewPermitModuleFactory returns an engine-compatible ModuleFactory for "permit.provider".
+func NewPermitModuleFactory() plugin.ModuleFactory { return newModuleFactory("permit.provider") }
+
+// NewScopeCatalogModuleFactory returns an engine-compatible ModuleFactory for
+// "authz.scope_catalog".
+func NewScopeCatalogModuleFactory() plugin.ModuleFactory {
+	return newModuleFactory("authz.scope_catalog")
+}
+
+// NewAuditModuleFactory returns an engine-compatible ModuleFactory for "authz.audit".
+func NewAuditModuleFactory() plugin.ModuleFactory { return newModuleFactory("authz.audit") }
+
+// NewCompositeModuleFactory returns an engine-compatible ModuleFactory for
+// "authz.composite".
+func NewCompositeModuleFactory() plugin.ModuleFactory { return newModuleFactory("authz.composite") }
+
+// NewOPAModuleFactory returns an engine-compatible ModuleFactory for "authz.opa".
+func NewOPAModuleFactory() plugin.ModuleFactory { return newModuleFactory("authz.opa") }
+
+// NewCedarModuleFactory returns an engine-compatible ModuleFactory for "authz.cedar".
+func NewCedarModuleFactory() plugin.ModuleFactory { return newModuleFactory("authz.cedar") }
+
+// ModuleFactories returns engine-native factories for every authz module type.
+func ModuleFactories() map[string]plugin.ModuleFactory {
+	factories := make(map[string]plugin.ModuleFactory)
+	for _, typeName := range internal.ModuleTypes() {
+		if typeName == "authz.casbin" {
+			factories[typeName] = NewCasbinModuleFactory()
+			continue
+		}
+		factories[typeName] = newModuleFactory(typeName)
+	}
+	return factories
+}
+
+func (m *providerModuleWrapper) Name() string { return m.name }
+
+func (m *providerModuleWrapper) Init(modular.Application) error {
+	inner, err := internal.NewModule(m.typeName, m.name, m.config)
+	if err != nil {
+		return err
+	}
+	if err := inner.Init(); err != nil {
+		return err
+	}
+	m.inner = inner
+	return nil
+}
+
+// ProvidesServices registers the initialized module under its name. The
+// application reads it after Init, when the instance exists.
+func (m *providerModuleWrapper) ProvidesServices() []modular.ServiceProvider {
+	return []modular.ServiceProvider{
+		{Name: m.name, Description: m.typeName + ": " + m.name, Instance: m.inner},
+	}
+}
+
+// RequiresServices orders a module after the scope catalog it subscribes to.
+func (m *providerModuleWrapper) RequiresServices() []modular.ServiceDependency {
+	return catalogDependency(m.config)
+}
+
+// catalogDependency orders a module after the authz.scope_catalog named by its
+// catalog config, so the catalog exists when the module subscribes in Init.
+func catalogDependency(config map[string]any) []modular.ServiceDependency {
+	catalog, _ := config["catalog"].(string)
+	if catalog == "" {
+		return nil
+	}
+	return []modular.ServiceDependency{{Name: catalog, Required: true}}
+}
+
+func (m *providerModuleWrapper) Start(ctx context.Context) error {
+	if m.inner == nil {
+		return nil
+	}
+	return m.inner.Start(ctx)
+}
+
+func (m *providerModuleWrapper) Stop(ctx context.Context) error {
+	if m.inner == nil {
+		return nil
+	}
+	return m.inner.Stop(ctx)
+}
@@ -0,0 +1,74 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/GoCodeAlone/workflow-plugin-authz/adminapi"
+	"github.com/GoCodeAlone/workflow-plugin-authz/internal"
+)
+
+// catalogProjectionResolver bridges the adminapi projection route to a
+// registered authz.scope_catalog module.
+type catalogProjectionResolver struct {
+	catalog  string
+	module   string
+	provider string
+}
+
+// NewProjectionResolver returns an adminapi.ProjectionResolver that evaluates
+// the declarations of the authz.scope_catalog module catalogName against the
+// provider module moduleName ("casbin", "keto", or "permit"). Pass it as
+// adminapi.Options.Projections.
+func NewProjectionResolver(catalogName, moduleName, provider string) adminapi.ProjectionResolver {
+	return catalogProjectionResolver{catalog: catalogName, module: moduleName, provider: provider}
+}
+
+func (r catalogProjectionResolver) SubjectProjection(ctx context.Context, _ adminapi.Principal, subject, contextName string) (adminapi.SubjectProjection, error) {
+	projection, err := internal.ResolveSubjectProjection(ctx, r.catalog, r.module, r.provider, subject, contextName)
+	if err != nil {
+		return adminapi.SubjectProjection{}, err
+	}
+	out := adminapi.SubjectProjection{
+		Subject:       projection.Subject,
+		Module:        projection.Module,
+		Provider:      projection.Provider,
+		Context:       projection.Context,
+		GrantedScopes: projection.GrantedScopes,
+		UIActionIDs:   projection.UIActionIDs,
+		Resources:     make([]adminapi.PermittedResource, 0, len(projection.Resources)),
+		IssuedAt:      projection.IssuedAt,
+		ExpiresAt:     projection.ExpiresAt,
+		Signature:     projection.Signature,
+	}
+	for _, resource := range projection.Resources {
+		out.Resources = append(out.Resources, adminapi.PermittedResource(resource))
+	}
+	return out, nil
+}
+
+// VerifyProjection checks that a projection handed back by a client was
+// issued by catalogName, is unmodified, and has not expired.
+func VerifyProjection(catalogName string, projection adminapi.SubjectProjection) error {
+	in := internal.SubjectProjection{
+		Subject:       projection.Subject,
+		Module:        projection.Module,
+		Provider:      projection.Provider,
+		Context:       projection.Context,
+		GrantedScopes: projection.GrantedScopes,
+		UIActionIDs:   projection.UIActionIDs,
+		Resources:     make([]internal.PermittedResource, 0, len(projection.Resources)),
+		IssuedAt:      projection.IssuedAt,
+		ExpiresAt:     projection.ExpiresAt,
+		Signature:     projection.Signature,
+	}
+	for _, resource := range projection.Resources {
+		in.Resources = append(in.Resources, internal.PermittedResource(resource))
+	}
+	err := internal.VerifySubjectProjection(catalogName, in)
+	if errors.Is(err, internal.ErrProjectionSignature) || errors.Is(err, internal.ErrProjectionExpired) {
+		return fmt.Errorf("%w: %w", adminapi.ErrInvalidRequest, err)
+	}
+	return err
+}
@@ -0,0 +1,83 @@
+package authz
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/GoCodeAlone/workflow-plugin-authz/adminapi"
+	"github.com/GoCodeAlone/workflow-plugin-authz/internal"
+)
+
+// moduleSimulator bridges the adminapi simulate route to a registered authz
+// module.
+type moduleSimulator struct {
+	module   string
+	provider string
+}
+
+// NewSimulator returns an adminapi.Simulator that evaluates proposed changes
+// against the authz module registered under moduleName. provider is "casbin",
+// "keto", or "permit"; Keto and Permit are simulated against their local
+// caches without contacting the remote service. Pass it as
+// adminapi.Options.Simulator.
+func NewSimulator(moduleName, provider string) adminapi.Simulator {
+	return moduleSimulator{module: moduleName, provider: provider}
+}
+
+func (s moduleSimulator) Simulate(ctx context.Context, _ adminapi.Principal, request adminapi.SimulationRequest) (adminapi.SimulationResult, error) {
+	// The request is decoded like the step.authz_simulate config so both
+	// paths give the same result for the same input.
+	data, err := json.Marshal(request)
+	if err != nil {
+		return adminapi.SimulationResult{}, fmt.Errorf("%w: %w", adminapi.ErrInvalidRequest, err)
+	}
+	var values map[string]any
+	if err := json.Unmarshal(data, &values); err != nil {
+		return adminapi.SimulationResult{}, fmt.Errorf("%w: %w", adminapi.ErrInvalidRequest, err)
+	}
+	out, err := internal.SimulateModule(ctx, s.module, s.provider, internal.SimulationInputFromMap(values))
+	if errors.Is(err, internal.ErrInvalidSimulation) {
+		return adminapi.SimulationResult{}, fmt.Errorf("%w: %w", adminapi.ErrInvalidRequest, err)
+	}
+	if err != nil {
+		return adminapi.SimulationResult{}, err
+	}
+	return adminapi.SimulationResult{
+		Evaluated: out.Evaluated,
+		Granted:   out.Granted,
+		Revoked:   out.Revoked,
+		Flips:     adminSimulationFlips(out.Flips),
+		Errors:    adminSimulationFlips(out.Errors),
+	}, nil
+}
+
+func adminSimulationFlips(results []internal.SimulationResult) []adminapi.SimulationFlip {
+	out := make([]adminapi.SimulationFlip, 0, len(results))
+	for _, result := range results {
+		probe := result.Probe
+		out = append(out, adminapi.SimulationFlip{
+			Probe: adminapi.SimulationProbe{
+				Kind:                  probe.Kind,
+				Mode:                  string(probe.Mode),
+				Subject:               probe.Subject,
+				Context:               probe.Context,
+				Resource:              probe.Resource,
+				Action:                probe.Action,
+				Scope:                 probe.Scope,
+				Relation:              probe.Relation,
+				SubjectAttributes:     probe.SubjectAttributes,
+				ResourceAttributes:    probe.ResourceAttributes,
+				EnvironmentAttributes: probe.EnvironmentAttributes,
+			},
+			Before:       result.Before,
+			After:        result.After,
+			BeforeReason: result.BeforeReason,
+			AfterReason:  result.AfterReason,
+			Change:       result.Change,
+			Error:        result.Error,
+		})
+	}
+	return out
+}
@@ -0,0 +1,154 @@
+// Package authzsdk is the stable Go API for the authz plugin's provider
+// contracts. It re-exports the provider interfaces and their request and
+// result types so a host embedding the plugin through the authz package can
+// call the built-in providers, implement its own, and register it so
+// step.authz_check, the other provider-neutral steps, and
+// authz.NewScopeRoleSource resolve it like casbin, keto, or permit.
+//
+// Every type is an alias of the type the plugin uses internally, so values
+// pass between this package, the steps, and adminapi bridges unchanged.
+// Within a major version, exported names are not removed or renamed, and
+// interfaces do not gain methods; new behavior is added through new optional
+// interfaces.
+//
+// Registering a custom provider that implements AuthzProvider and
+// AttributePolicyProvider:
+//
+//	if err := authzsdk.RegisterProvider("cerbos", "policy", newCerbosProvider()); err != nil {
+//	    return err
+//	}
+//
+// A step configured with `module: policy` and `provider: cerbos` then decides
+// against it.
+package authzsdk
+
+import (
+	"context"
+
+	"github.com/GoCodeAlone/workflow-plugin-authz/internal"
+	"github.com/GoCodeAlone/workflow-plugin-authz/internal/contracts"
+)
+
+// Capabilities.
+type (
+	// AuthzCapability is an authorization model: RBAC, ABAC, ReBAC, or ACL.
+	AuthzCapability = internal.AuthzCapability
+	// AuthzOperation is an operation a provider implements for a model.
+	AuthzOperation = internal.AuthzOperation
+	// CapabilityRequirement is the mode and operations a consumer needs.
+	CapabilityRequirement = internal.CapabilityRequirement
+	// CapabilityDescriptor describes one mode a provider supports.
+	CapabilityDescriptor = internal.CapabilityDescriptor
+	// AuthzProvider is implemented by every provider to declare the models it
+	// supports. Custom providers must implement it.
+	AuthzProvider = internal.AuthzProvider
+)
+
+const (
+	CapabilityRBAC  = internal.CapabilityRBAC
+	CapabilityABAC  = internal.CapabilityABAC
+	CapabilityReBAC = internal.CapabilityReBAC
+	CapabilityACL   = internal.CapabilityACL
+
+	OperationCheck           = internal.OperationCheck
+	OperationManageRoles     = internal.OperationManageRoles
+	OperationManagePolicies  = internal.OperationManagePolicies
+	OperationManageRelations = internal.OperationManageRelations
+	OperationList            = internal.OperationList
+)
+
+// RBAC scopes and roles.
+type (
+	// ScopeRoleProvider manages roles that grant scopes and checks scopes.
+	ScopeRoleProvider = internal.ScopeRoleProvider
+	ScopeDeclaration  = contracts.ScopeDeclaration
+	// RoleScopeGrant is the set of scopes a role grants in a context.
+	RoleScopeGrant = internal.RoleScopeGrant
+	// SubjectRoleAssignment assigns a role, direct scopes, or both to a
+	// subject in a context.
+	SubjectRoleAssignment = internal.SubjectRoleAssignment
+	AssignmentFilter      = internal.AssignmentFilter
+	ScopeCheck            = internal.ScopeCheck
+	ScopeCheckResult      = internal.ScopeCheckResult
+
+	// RoleLister lists a provider's role grants. Custom providers implement
+	// it so delegated administration can read their roles.
+	RoleLister = internal.RoleLister
+)
+
+// ABAC attributes and policies.
+type (
+	// AttributePolicyProvider manages attribute policies and checks them.
+	AttributePolicyProvider = internal.AttributePolicyProvider
+	AttributeDeclaration    = contracts.AttributeDeclaration
+	AttributeCondition      = internal.AttributeCondition
+	AttributePolicy         = internal.AttributePolicy
+	AttributePolicyFilter   = internal.AttributePolicyFilter
+	AttributeCheck          = internal.AttributeCheck
+	AttributeCheckResult    = internal.AttributeCheckResult
+)
+
+// ReBAC relationships.
+type (
+	// RelationshipProvider manages relation tuples and checks relations.
+	RelationshipProvider = internal.RelationshipProvider
+	RelationTuple        = internal.RelationTuple
+	RelationTupleFilter  = internal.RelationTupleFilter
+	RelationCheck        = internal.RelationCheck
+	RelationCheckResult  = internal.RelationCheckResult
+
+	// RelationExpander lists the subjects holding a relation, following
+	// subject sets. authz.keto implements it.
+	RelationExpander = internal.RelationExpander
+	RelationExpand   = internal.RelationExpand
+	UsersetTree      = internal.UsersetTree
+)
+
+// Provider-neutral decisions.
+type (
+	AuthorizationDecisionInput  = internal.AuthorizationDecisionInput
+	AuthorizationDecisionOutput = internal.AuthorizationDecisionOutput
+
+	// AuthorizationDecider is implemented by providers that make the whole
+	// decision themselves. authz.composite implements it to route checks to
+	// other providers.
+	AuthorizationDecider = internal.AuthorizationDecider
+)
+
+// DecideAuthorization decides input against provider, selecting RBAC, ABAC,
+// or ReBAC from input.Mode or, when empty, from the fields that are set.
+func DecideAuthorization(ctx context.Context, provider any, input AuthorizationDecisionInput) (AuthorizationDecisionOutput, error) {
+	return internal.DecideAuthorization(ctx, provider, input)
+}
+
+// Decide resolves the module registered as moduleName the way step.authz_check
+// does and decides input against it. provider is "casbin", "keto", "permit",
+// "composite", "opa", "cedar", or the kind passed to RegisterProvider.
+func Decide(ctx context.Context, moduleName, provider string, input AuthorizationDecisionInput) (AuthorizationDecisionOutput, error) {
+	return internal.DecideModuleAuthorization(ctx, moduleName, provider, input)
+}
+
+// RegisterProvider registers a host-implemented provider as module name under
+// provider kind. Steps and bridges configured with that module and provider
+// use it for every model it implements: ScopeRoleProvider for RBAC,
+// AttributePolicyProvider for ABAC, and RelationshipProvider for ReBAC. The
+// built-in kinds "casbin", "keto", "permit", "composite", "opa", and "cedar"
+// are reserved. A name already used by one of the plugin's modules, or by a
+// custom provider of another kind, is rejected; registering the same name and
+// kind again replaces the provider.
+func RegisterProvider(kind, name string, provider AuthzProvider) error {
+	return internal.RegisterCustomProvider(kind, name, provider)
+}
+
+// NewCapabilityDescriptor returns a configured, healthy descriptor, for
+// custom providers building their CapabilityDescriptors.
+func NewCapabilityDescriptor(mode AuthzCapability, operations []AuthzOperation, source string) CapabilityDescriptor {
+	return internal.NewCapabilityDescriptor(mode, operations, source)
+}
+
+// RequireCapabilities checks requirements against descriptors with the same
+// errors as the built-in providers, for custom providers implementing
+// AuthzProvider.RequireCapabilities.
+func RequireCapabilities(providerName string, descriptors []CapabilityDescriptor, requirements []CapabilityRequirement) error {
+	return internal.RequireDescribedCapabilities(providerName, descriptors, requirements)
+}
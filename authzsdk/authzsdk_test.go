@@ -0,0 +1,105 @@
+package authzsdk_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/GoCodeAlone/workflow-plugin-authz/authz"
+	"github.com/GoCodeAlone/workflow-plugin-authz/authzsdk"
+)
+
+// staticProvider grants the scopes listed per subject.
+type staticProvider struct {
+	grants map[string][]string
+}
+
+func (p *staticProvider) descriptors() []authzsdk.CapabilityDescriptor {
+	return []authzsdk.CapabilityDescriptor{
+		authzsdk.NewCapabilityDescriptor(authzsdk.CapabilityRBAC, []authzsdk.AuthzOperation{authzsdk.OperationCheck}, "static"),
+	}
+}
+
+func (p *staticProvider) Capabilities() []authzsdk.AuthzCapability {
+	return []authzsdk.AuthzCapability{authzsdk.CapabilityRBAC}
+}
+
+func (p *staticProvider) SupportsCapability(c authzsdk.AuthzCapability) bool {
+	return c == authzsdk.CapabilityRBAC
+}
+
+func (p *staticProvider) CapabilityDescriptors() []authzsdk.CapabilityDescriptor {
+	return p.descriptors()
+}
+
+func (p *staticProvider) RequireCapabilities(requirements []authzsdk.CapabilityRequirement) error {
+	return authzsdk.RequireCapabilities("static", p.descriptors(), requirements)
+}
+
+func (p *staticProvider) Name() string { return "static" }
+
+func (p *staticProvider) DeclareScopes(context.Context, []*authzsdk.ScopeDeclaration) error {
+	return nil
+}
+
+func (p *staticProvider) UpsertRole(context.Context, authzsdk.RoleScopeGrant) error { return nil }
+
+func (p *staticProvider) AssignRole(context.Context, authzsdk.SubjectRoleAssignment) error {
+	return nil
+}
+
+func (p *staticProvider) ListAssignments(context.Context, authzsdk.AssignmentFilter) ([]authzsdk.SubjectRoleAssignment, error) {
+	return nil, nil
+}
+
+func (p *staticProvider) RemoveAssignment(context.Context, authzsdk.SubjectRoleAssignment) error {
+	return nil
+}
+
+func (p *staticProvider) CheckScope(_ context.Context, check authzsdk.ScopeCheck) (authzsdk.ScopeCheckResult, error) {
+	result := authzsdk.ScopeCheckResult{Provider: "static", Subject: check.Subject, Context: check.Context, Scope: check.Scope}
+	result.Allowed = slices.Contains(p.grants[check.Subject], check.Scope)
+	if !result.Allowed {
+		result.Reason = "not granted"
+	}
+	return result, nil
+}
+
+func TestCustomProviderIsResolvedLikeBuiltins(t *testing.T) {
+	ctx := context.Background()
+	provider := &staticProvider{grants: map[string][]string{"alice": {"billing:invoice:read"}}}
+	if err := authzsdk.RegisterProvider("static", "sdk-static", provider); err != nil {
+		t.Fatalf("RegisterProvider: %v", err)
+	}
+
+	decision, err := authzsdk.Decide(ctx, "sdk-static", "static", authzsdk.AuthorizationDecisionInput{
+		Subject: "alice", Context: "billing", Scope: "billing:invoice:read",
+	})
+	if err != nil || !decision.Allowed || decision.Mode != authzsdk.CapabilityRBAC {
+		t.Fatalf("Decide = %+v, %v; want an allowed RBAC decision", decision, err)
+	}
+	if _, err := authzsdk.Decide(ctx, "sdk-static", "other", authzsdk.AuthorizationDecisionInput{Subject: "alice", Scope: "x"}); err == nil {
+		t.Fatal("expected an error for a provider kind the module was not registered with")
+	}
+	if _, err := authzsdk.Decide(ctx, "sdk-static", "static", authzsdk.AuthorizationDecisionInput{
+		Subject: "alice", Relation: "owner", Resource: "doc:1",
+	}); err == nil {
+		t.Fatal("expected an error for a model the provider does not support")
+	}
+
+	allowed, err := authz.NewScopeRoleSource("sdk-static", "static").CheckScope(ctx, "alice", "billing", "billing:invoice:read")
+	if err != nil || !allowed {
+		t.Fatalf("ScopeRoleSource.CheckScope = %v, %v; want true", allowed, err)
+	}
+}
+
+func TestRegisterProviderRejectsBuiltinKinds(t *testing.T) {
+	for _, kind := range []string{"casbin", "keto", "permit", ""} {
+		if err := authzsdk.RegisterProvider(kind, "sdk-reserved", &staticProvider{}); err == nil {
+			t.Fatalf("expected RegisterProvider(%q) to fail", kind)
+		}
+	}
+	if err := authzsdk.RegisterProvider("static", "sdk-nil", nil); err == nil {
+		t.Fatal("expected RegisterProvider to reject a nil provider")
+	}
+}
@@ -0,0 +1,395 @@
+// Package authztest checks that authz provider implementations honor the
+// semantics the plugin's steps and bridges rely on, and provides fakes for
+// testing hosts offline.
+//
+// The conformance suites run as subtests and take a constructor that returns
+// a fresh, empty provider for each subtest:
+//
+//	func TestMyProvider(t *testing.T) {
+//	    authztest.TestScopeRoleProvider(t, func(t *testing.T) authzsdk.ScopeRoleProvider {
+//	        return newMyProvider(t)
+//	    })
+//	}
+//
+// NewMemoryProvider is an in-memory provider for every model. NewKetoServer
+// and NewPermitServer are httptest stand-ins for Ory Keto and Permit.io that
+// authz.keto and permit.provider modules can be pointed at.
+package authztest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GoCodeAlone/workflow-plugin-authz/authzsdk"
+	"github.com/GoCodeAlone/workflow-plugin-authz/internal"
+)
+
+// MemoryProvider is an in-memory RBAC, ABAC, and ReBAC provider with the same
+// semantics as the built-in providers. Register it with
+// authzsdk.RegisterProvider to back steps in tests.
+type MemoryProvider = internal.MemoryProvider
+
+// NewMemoryProvider returns an empty MemoryProvider named name.
+func NewMemoryProvider(name string) *MemoryProvider {
+	return internal.NewMemoryProvider(name)
+}
+
+// TestScopeRoleProvider runs the RBAC conformance suite against providers
+// returned by newProvider. It covers name normalization, idempotent upserts,
+// context isolation, rejection of undeclared scopes, denial of missing
+// subjects, and assignment removal.
+func TestScopeRoleProvider(t *testing.T, newProvider func(t *testing.T) authzsdk.ScopeRoleProvider) {
+	t.Helper()
+	ctx := context.Background()
+	setup := func(t *testing.T) authzsdk.ScopeRoleProvider {
+		t.Helper()
+		provider := newProvider(t)
+		if err := provider.DeclareScopes(ctx, []*authzsdk.ScopeDeclaration{
+			{Name: " billing:invoice:read "},
+			{Name: "billing:invoice:update"},
+			{Name: "support:ticket:read"},
+		}); err != nil {
+			t.Fatalf("DeclareScopes: %v", err)
+		}
+		return provider
+	}
+	check := func(t *testing.T, provider authzsdk.ScopeRoleProvider, subject, contextName, scope string) bool {
+		t.Helper()
+		result, err := provider.CheckScope(ctx, authzsdk.ScopeCheck{Subject: subject, Context: contextName, Scope: scope})
+		if err != nil {
+			t.Fatalf("CheckScope(%s, %s, %s): %v", subject, contextName, scope, err)
+		}
+		return result.Allowed
+	}
+	mustUpsert := func(t *testing.T, provider authzsdk.ScopeRoleProvider, grant authzsdk.RoleScopeGrant) {
+		t.Helper()
+		if err := provider.UpsertRole(ctx, grant); err != nil {
+			t.Fatalf("UpsertRole(%+v): %v", grant, err)
+		}
+	}
+	mustAssign := func(t *testing.T, provider authzsdk.ScopeRoleProvider, assignment authzsdk.SubjectRoleAssignment) {
+		t.Helper()
+		if err := provider.AssignRole(ctx, assignment); err != nil {
+			t.Fatalf("AssignRole(%+v): %v", assignment, err)
+		}
+	}
+
+	t.Run("normalizes names", func(t *testing.T) {
+		provider := setup(t)
+		mustUpsert(t, provider, authzsdk.RoleScopeGrant{Role: " viewer ", Context: " billing ", Scopes: []string{" billing:invoice:read ", "billing:invoice:read"}})
+		mustAssign(t, provider, authzsdk.SubjectRoleAssignment{Subject: " alice ", Role: " viewer ", Context: " billing "})
+		if !check(t, provider, "alice", "billing", "billing:invoice:read") {
+			t.Fatal("expected the trimmed grant to allow alice")
+		}
+		result, err := provider.CheckScope(ctx, authzsdk.ScopeCheck{Subject: "alice", Context: "billing", Resource: "invoice", Action: "read"})
+		if err != nil || !result.Allowed {
+			t.Fatalf("CheckScope by resource and action = %+v, %v; want allowed", result, err)
+		}
+		assignments, err := provider.ListAssignments(ctx, authzsdk.AssignmentFilter{Subject: "alice"})
+		if err != nil || len(assignments) != 1 || assignments[0].Role != "viewer" || assignments[0].Context != "billing" {
+			t.Fatalf("ListAssignments = %+v, %v; want one trimmed assignment", assignments, err)
+		}
+	})
+
+	t.Run("upserts are idempotent", func(t *testing.T) {
+		provider := setup(t)
+		grant := authzsdk.RoleScopeGrant{Role: "viewer", Context: "billing", Scopes: []string{"billing:invoice:read"}}
+		assignment := authzsdk.SubjectRoleAssignment{Subject: "alice", Role: "viewer", Context: "billing"}
+		for range 2 {
+			mustUpsert(t, provider, grant)
+			mustAssign(t, provider, assignment)
+		}
+		assignments, err := provider.ListAssignments(ctx, authzsdk.AssignmentFilter{Subject: "alice"})
+		if err != nil || len(assignments) != 1 {
+			t.Fatalf("ListAssignments = %+v, %v; want one assignment", assignments, err)
+		}
+		if !check(t, provider, "alice", "billing", "billing:invoice:read") {
+			t.Fatal("expected alice to be allowed after repeated upserts")
+		}
+		mustUpsert(t, provider, authzsdk.RoleScopeGrant{Role: "viewer", Context: "billing", Scopes: []string{"billing:invoice:update"}})
+		if check(t, provider, "alice", "billing", "billing:invoice:read") {
+			t.Fatal("expected an upsert to replace the role's scopes")
+		}
+		if !check(t, provider, "alice", "billing", "billing:invoice:update") {
+			t.Fatal("expected the replaced scopes to be granted")
+		}
+	})
+
+	t.Run("isolates contexts", func(t *testing.T) {
+		provider := setup(t)
+		mustUpsert(t, provider, authzsdk.RoleScopeGrant{Role: "viewer", Context: "billing", Scopes: []string{"billing:invoice:read"}})
+		mustUpsert(t, provider, authzsdk.RoleScopeGrant{Role: "viewer", Context: "support", Scopes: []string{"support:ticket:read"}})
+		mustAssign(t, provider, authzsdk.SubjectRoleAssignment{Subject: "alice", Role: "viewer", Context: "billing"})
+		if check(t, provider, "alice", "support", "support:ticket:read") {
+			t.Fatal("a role assigned in billing must not grant scopes in support")
+		}
+		if check(t, provider, "alice", "support", "billing:invoice:read") {
+			t.Fatal("a scope must not be granted when checked in another context")
+		}
+		if err := provider.UpsertRole(ctx, authzsdk.RoleScopeGrant{Role: "viewer", Context: "support", Scopes: []string{"billing:invoice:read"}}); err == nil {
+			t.Fatal("expected UpsertRole to reject a scope declared in another context")
+		}
+	})
+
+	t.Run("rejects undeclared scopes", func(t *testing.T) {
+		provider := setup(t)
+		if err := provider.UpsertRole(ctx, authzsdk.RoleScopeGrant{Role: "viewer", Context: "billing", Scopes: []string{"billing:invoice:delete"}}); err == nil {
+			t.Fatal("expected UpsertRole to reject an undeclared scope")
+		}
+		if err := provider.AssignRole(ctx, authzsdk.SubjectRoleAssignment{Subject: "alice", Context: "billing", DirectScopes: []string{"billing:invoice:delete"}}); err == nil {
+			t.Fatal("expected AssignRole to reject an undeclared direct scope")
+		}
+		if check(t, provider, "alice", "billing", "billing:invoice:delete") {
+			t.Fatal("expected an undeclared scope to be denied")
+		}
+	})
+
+	t.Run("denies missing subjects", func(t *testing.T) {
+		provider := setup(t)
+		mustUpsert(t, provider, authzsdk.RoleScopeGrant{Role: "viewer", Context: "billing", Scopes: []string{"billing:invoice:read"}})
+		if err := provider.AssignRole(ctx, authzsdk.SubjectRoleAssignment{Role: "viewer", Context: "billing"}); err == nil {
+			t.Fatal("expected AssignRole to require a subject")
+		}
+		result, err := provider.CheckScope(ctx, authzsdk.ScopeCheck{Context: "billing", Scope: "billing:invoice:read"})
+		if err == nil && result.Allowed {
+			t.Fatal("expected a check without a subject to be denied")
+		}
+	})
+
+	t.Run("grants direct scopes", func(t *testing.T) {
+		provider := setup(t)
+		mustAssign(t, provider, authzsdk.SubjectRoleAssignment{Subject: "alice", Context: "billing", DirectScopes: []string{"billing:invoice:update"}})
+		if !check(t, provider, "alice", "billing", "billing:invoice:update") {
+			t.Fatal("expected a direct scope to be granted")
+		}
+		if check(t, provider, "alice", "billing", "billing:invoice:read") {
+			t.Fatal("expected only the direct scope to be granted")
+		}
+	})
+
+	t.Run("removes assignments", func(t *testing.T) {
+		provider := setup(t)
+		mustUpsert(t, provider, authzsdk.RoleScopeGrant{Role: "viewer", Context: "billing", Scopes: []string{"billing:invoice:read"}})
+		assignment := authzsdk.SubjectRoleAssignment{Subject: "alice", Role: "viewer", Context: "billing"}
+		mustAssign(t, provider, assignment)
+		if err := provider.RemoveAssignment(ctx, assignment); err != nil {
+			t.Fatalf("RemoveAssignment: %v", err)
+		}
+		if check(t, provider, "alice", "billing", "billing:invoice:read") {
+			t.Fatal("expected a removed assignment to stop granting scopes")
+		}
+		assignments, err := provider.ListAssignments(ctx, authzsdk.AssignmentFilter{Subject: "alice"})
+		if err != nil || len(assignments) != 0 {
+			t.Fatalf("ListAssignments = %+v, %v; want none", assignments, err)
+		}
+	})
+}
+
+// TestAttributePolicyProvider runs the ABAC conformance suite against
+// providers returned by newProvider. It covers name normalization,
+// idempotent upserts, context isolation, rejection of undeclared attributes,
+// denial of missing subjects, and policy removal.
+func TestAttributePolicyProvider(t *testing.T, newProvider func(t *testing.T) authzsdk.AttributePolicyProvider) {
+	t.Helper()
+	ctx := context.Background()
+	setup := func(t *testing.T) authzsdk.AttributePolicyProvider {
+		t.Helper()
+		provider := newProvider(t)
+		if err := provider.DeclareAttributes(ctx, []*authzsdk.AttributeDeclaration{
+			{Name: "department", Context: "billing", Target: "subject", DataType: "string"},
+			{Name: "department", Context: "support", Target: "subject", DataType: "string"},
+			{Name: "region", Context: "billing", Target: "resource", DataType: "string"},
+		}); err != nil {
+			t.Fatalf("DeclareAttributes: %v", err)
+		}
+		return provider
+	}
+	financePolicy := func(id, effect string) authzsdk.AttributePolicy {
+		return authzsdk.AttributePolicy{
+			ID: id, Context: "billing", Resource: "invoice", Action: "read", Effect: effect,
+			Conditions: []authzsdk.AttributeCondition{{Target: "subject", Attribute: "department", Operator: "equals", Values: []string{"finance"}}},
+		}
+	}
+	mustUpsert := func(t *testing.T, provider authzsdk.AttributePolicyProvider, policy authzsdk.AttributePolicy) {
+		t.Helper()
+		if err := provider.UpsertAttributePolicy(ctx, policy); err != nil {
+			t.Fatalf("UpsertAttributePolicy(%s): %v", policy.ID, err)
+		}
+	}
+	check := func(t *testing.T, provider authzsdk.AttributePolicyProvider, subject, contextName string) bool {
+		t.Helper()
+		result, err := provider.CheckAttributes(ctx, authzsdk.AttributeCheck{
+			Subject: subject, Context: contextName, Resource: "invoice", Action: "read",
+			SubjectAttributes: map[string]string{"department": "finance"},
+		})
+		if err != nil {
+			t.Fatalf("CheckAttributes: %v", err)
+		}
+		return result.Allowed
+	}
+
+	t.Run("normalizes names", func(t *testing.T) {
+		provider := setup(t)
+		policy := financePolicy(" finance-read ", "")
+		policy.Context, policy.Resource, policy.Action = " billing ", " invoice ", " read "
+		mustUpsert(t, provider, policy)
+		if !check(t, provider, "alice", "billing") {
+			t.Fatal("expected the trimmed policy to allow")
+		}
+		policies, err := provider.ListAttributePolicies(ctx, authzsdk.AttributePolicyFilter{Context: "billing"})
+		if err != nil || len(policies) != 1 || policies[0].ID != "finance-read" || policies[0].Effect != "allow" {
+			t.Fatalf("ListAttributePolicies = %+v, %v; want one trimmed allow policy", policies, err)
+		}
+	})
+
+	t.Run("upserts are idempotent", func(t *testing.T) {
+		provider := setup(t)
+		mustUpsert(t, provider, financePolicy("finance-read", "allow"))
+		mustUpsert(t, provider, financePolicy("finance-read", "allow"))
+		policies, err := provider.ListAttributePolicies(ctx, authzsdk.AttributePolicyFilter{Context: "billing"})
+		if err != nil || len(policies) != 1 {
+			t.Fatalf("ListAttributePolicies = %+v, %v; want one policy", policies, err)
+		}
+		mustUpsert(t, provider, financePolicy("finance-read", "deny"))
+		if check(t, provider, "alice", "billing") {
+			t.Fatal("expected an upsert to replace the policy's effect")
+		}
+	})
+
+	t.Run("isolates contexts", func(t *testing.T) {
+		provider := setup(t)
+		mustUpsert(t, provider, financePolicy("finance-read", "allow"))
+		if check(t, provider, "alice", "support") {
+			t.Fatal("a billing policy must not allow checks in support")
+		}
+		policy := financePolicy("region-read", "allow")
+		policy.Context = "support"
+		policy.Conditions = []authzsdk.AttributeCondition{{Target: "resource", Attribute: "region", Values: []string{"eu"}}}
+		if err := provider.UpsertAttributePolicy(ctx, policy); err == nil {
+			t.Fatal("expected a condition on an attribute declared in another context to be rejected")
+		}
+	})
+
+	t.Run("rejects undeclared attributes", func(t *testing.T) {
+		provider := setup(t)
+		policy := financePolicy("level-read", "allow")
+		policy.Conditions = []authzsdk.AttributeCondition{{Target: "subject", Attribute: "level", Values: []string{"senior"}}}
+		if err := provider.UpsertAttributePolicy(ctx, policy); err == nil {
+			t.Fatal("expected a condition on an undeclared attribute to be rejected")
+		}
+	})
+
+	t.Run("denies missing subjects", func(t *testing.T) {
+		provider := setup(t)
+		mustUpsert(t, provider, financePolicy("finance-read", "allow"))
+		result, err := provider.CheckAttributes(ctx, authzsdk.AttributeCheck{
+			Context: "billing", Resource: "invoice", Action: "read",
+			SubjectAttributes: map[string]string{"department": "finance"},
+		})
+		if err == nil && result.Allowed {
+			t.Fatal("expected a check without a subject to be denied")
+		}
+	})
+
+	t.Run("removes policies", func(t *testing.T) {
+		provider := setup(t)
+		mustUpsert(t, provider, financePolicy("finance-read", "allow"))
+		if err := provider.RemoveAttributePolicy(ctx, authzsdk.AttributePolicyFilter{ID: "finance-read", Context: "billing"}); err != nil {
+			t.Fatalf("RemoveAttributePolicy: %v", err)
+		}
+		if check(t, provider, "alice", "billing") {
+			t.Fatal("expected a removed policy to stop allowing")
+		}
+	})
+}
+
+// TestRelationshipProvider runs the ReBAC conformance suite against
+// providers returned by newProvider. It covers tuple normalization,
+// idempotent upserts, context isolation, denial of missing subjects, and
+// tuple removal.
+func TestRelationshipProvider(t *testing.T, newProvider func(t *testing.T) authzsdk.RelationshipProvider) {
+	t.Helper()
+	ctx := context.Background()
+	owner := authzsdk.RelationTuple{Subject: "alice", Relation: "owner", Object: "doc-1", Context: "docs"}
+	mustUpsert := func(t *testing.T, provider authzsdk.RelationshipProvider, tuple authzsdk.RelationTuple) {
+		t.Helper()
+		if err := provider.UpsertRelationTuple(ctx, tuple); err != nil {
+			t.Fatalf("UpsertRelationTuple(%+v): %v", tuple, err)
+		}
+	}
+	check := func(t *testing.T, provider authzsdk.RelationshipProvider, check authzsdk.RelationCheck) bool {
+		t.Helper()
+		result, err := provider.CheckRelation(ctx, check)
+		if err != nil {
+			t.Fatalf("CheckRelation(%+v): %v", check, err)
+		}
+		return result.Allowed
+	}
+	ownerCheck := authzsdk.RelationCheck{Subject: "alice", Relation: "owner", Object: "doc-1", Context: "docs"}
+
+	t.Run("normalizes names", func(t *testing.T) {
+		provider := newProvider(t)
+		mustUpsert(t, provider, authzsdk.RelationTuple{Subject: " alice ", Relation: " owner ", Object: " doc-1 ", Context: " docs "})
+		if !check(t, provider, ownerCheck) {
+			t.Fatal("expected the trimmed tuple to allow")
+		}
+		tuples, err := provider.ListRelationTuples(ctx, authzsdk.RelationTupleFilter{Subject: "alice"})
+		if err != nil || len(tuples) != 1 || tuples[0] != owner {
+			t.Fatalf("ListRelationTuples = %+v, %v; want the trimmed tuple", tuples, err)
+		}
+	})
+
+	t.Run("upserts are idempotent", func(t *testing.T) {
+		provider := newProvider(t)
+		mustUpsert(t, provider, owner)
+		mustUpsert(t, provider, owner)
+		tuples, err := provider.ListRelationTuples(ctx, authzsdk.RelationTupleFilter{Context: "docs"})
+		if err != nil || len(tuples) != 1 {
+			t.Fatalf("ListRelationTuples = %+v, %v; want one tuple", tuples, err)
+		}
+	})
+
+	t.Run("isolates contexts", func(t *testing.T) {
+		provider := newProvider(t)
+		mustUpsert(t, provider, owner)
+		other := ownerCheck
+		other.Context = "wiki"
+		if check(t, provider, other) {
+			t.Fatal("a tuple in docs must not allow checks in wiki")
+		}
+		other = ownerCheck
+		other.Relation = "viewer"
+		if check(t, provider, other) {
+			t.Fatal("a tuple must only grant its own relation")
+		}
+	})
+
+	t.Run("denies missing subjects", func(t *testing.T) {
+		provider := newProvider(t)
+		mustUpsert(t, provider, owner)
+		if err := provider.UpsertRelationTuple(ctx, authzsdk.RelationTuple{Relation: "owner", Object: "doc-1", Context: "docs"}); err == nil {
+			t.Fatal("expected UpsertRelationTuple to require a subject")
+		}
+		anonymous := ownerCheck
+		anonymous.Subject = ""
+		result, err := provider.CheckRelation(ctx, anonymous)
+		if err == nil && result.Allowed {
+			t.Fatal("expected a check without a subject to be denied")
+		}
+	})
+
+	t.Run("removes tuples", func(t *testing.T) {
+		provider := newProvider(t)
+		mustUpsert(t, provider, owner)
+		if err := provider.RemoveRelationTuple(ctx, owner); err != nil {
+			t.Fatalf("RemoveRelationTuple: %v", err)
+		}
+		if check(t, provider, ownerCheck) {
+			t.Fatal("expected a removed tuple to stop allowing")
+		}
+		tuples, err := provider.ListRelationTuples(ctx, authzsdk.RelationTupleFilter{Context: "docs"})
+		if err != nil || len(tuples) != 0 {
+			t.Fatalf("ListRelationTuples = %+v, %v; want none", tuples, err)
+		}
+	})
+}
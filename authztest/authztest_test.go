@@ -0,0 +1,142 @@
+package authztest
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/GoCodeAlone/workflow-plugin-authz/authzsdk"
+	"github.com/GoCodeAlone/workflow-plugin-authz/internal"
+)
+
+const rebacModel = `
+[request_definition]
+r = sub, obj, act
+
+[policy_definition]
+p = sub, obj, act
+
+[role_definition]
+g = _, _
+g2 = _, _, _
+
+[policy_effect]
+e = some(where (p.eft == allow))
+
+[matchers]
+m = g(r.sub, p.sub) && r.obj == p.obj && r.act == p.act
+`
+
+const abacModel = `
+[request_definition]
+r = sub, obj, act
+
+[policy_definition]
+p = sub_department, obj_region, act
+
+[policy_effect]
+e = some(where (p.eft == allow))
+
+[matchers]
+m = r.sub.department == p.sub_department && r.obj.region == p.obj_region && r.act == p.act
+`
+
+var moduleSeq atomic.Int64
+
+// newModule creates and initializes a uniquely named plugin module.
+func newModule(t *testing.T, typeName string, config map[string]any) internal.ModuleInstance {
+	t.Helper()
+	name := fmt.Sprintf("authztest-%d", moduleSeq.Add(1))
+	module, err := internal.NewModule(typeName, name, config)
+	if err != nil {
+		t.Fatalf("NewModule(%s): %v", typeName, err)
+	}
+	if err := module.Init(); err != nil {
+		t.Fatalf("Init(%s): %v", typeName, err)
+	}
+	return module
+}
+
+func TestMemoryProviderConformance(t *testing.T) {
+	t.Run("rbac", func(t *testing.T) {
+		TestScopeRoleProvider(t, func(*testing.T) authzsdk.ScopeRoleProvider { return NewMemoryProvider("memory") })
+	})
+	t.Run("abac", func(t *testing.T) {
+		TestAttributePolicyProvider(t, func(*testing.T) authzsdk.AttributePolicyProvider { return NewMemoryProvider("memory") })
+	})
+	t.Run("rebac", func(t *testing.T) {
+		TestRelationshipProvider(t, func(*testing.T) authzsdk.RelationshipProvider { return NewMemoryProvider("memory") })
+	})
+}
+
+func TestCasbinConformance(t *testing.T) {
+	t.Run("rbac", func(t *testing.T) {
+		TestScopeRoleProvider(t, func(t *testing.T) authzsdk.ScopeRoleProvider {
+			return newModule(t, "authz.casbin", map[string]any{"model": rebacModel}).(authzsdk.ScopeRoleProvider)
+		})
+	})
+	t.Run("abac", func(t *testing.T) {
+		TestAttributePolicyProvider(t, func(t *testing.T) authzsdk.AttributePolicyProvider {
+			return newModule(t, "authz.casbin", map[string]any{"model": abacModel}).(authzsdk.AttributePolicyProvider)
+		})
+	})
+	t.Run("rebac", func(t *testing.T) {
+		TestRelationshipProvider(t, func(t *testing.T) authzsdk.RelationshipProvider {
+			return newModule(t, "authz.casbin", map[string]any{"model": rebacModel}).(authzsdk.RelationshipProvider)
+		})
+	})
+}
+
+func TestKetoConformance(t *testing.T) {
+	newKeto := func(t *testing.T) internal.ModuleInstance {
+		server := NewKetoServer(t)
+		return newModule(t, "authz.keto", map[string]any{"read_url": server.URL, "write_url": server.URL})
+	}
+	t.Run("rbac", func(t *testing.T) {
+		TestScopeRoleProvider(t, func(t *testing.T) authzsdk.ScopeRoleProvider {
+			return newKeto(t).(authzsdk.ScopeRoleProvider)
+		})
+	})
+	t.Run("rebac", func(t *testing.T) {
+		TestRelationshipProvider(t, func(t *testing.T) authzsdk.RelationshipProvider {
+			return newKeto(t).(authzsdk.RelationshipProvider)
+		})
+	})
+}
+
+func TestPermitConformance(t *testing.T) {
+	TestScopeRoleProvider(t, func(t *testing.T) authzsdk.ScopeRoleProvider {
+		server := NewPermitServer(t)
+		return newModule(t, "permit.provider", map[string]any{
+			"apiKey":      "permit_key_test",
+			"apiUrl":      server.URL,
+			"pdpUrl":      server.URL,
+			"project":     "default",
+			"environment": "test",
+		}).(authzsdk.ScopeRoleProvider)
+	})
+}
+
+func TestKetoServerFollowsSubjectSets(t *testing.T) {
+	server := NewKetoServer(t)
+	module := newModule(t, "authz.keto", map[string]any{"read_url": server.URL, "write_url": server.URL})
+	provider := module.(authzsdk.ScopeRoleProvider)
+	ctx := t.Context()
+	if err := provider.DeclareScopes(ctx, []*authzsdk.ScopeDeclaration{{Name: "billing:invoice:read"}}); err != nil {
+		t.Fatalf("DeclareScopes: %v", err)
+	}
+	if err := provider.UpsertRole(ctx, authzsdk.RoleScopeGrant{Role: "viewer", Context: "billing", Scopes: []string{"billing:invoice:read"}}); err != nil {
+		t.Fatalf("UpsertRole: %v", err)
+	}
+	if err := provider.AssignRole(ctx, authzsdk.SubjectRoleAssignment{Subject: "alice", Role: "viewer", Context: "billing"}); err != nil {
+		t.Fatalf("AssignRole: %v", err)
+	}
+	want := []string{
+		"role:billing:viewer#member@alice",
+		"scope:billing:invoice:read#granted@role:billing:viewer#member",
+	}
+	got := server.Tuples()
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("tuples = %v, want %v", got, want)
+	}
+}
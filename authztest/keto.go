@@ -0,0 +1,251 @@
+package authztest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// KetoServer is an httptest stand-in for the Ory Keto read and write APIs. It
+// stores relation tuples in memory and answers permission checks by
+// following subject sets, which is how authz.keto resolves role grants.
+// Listing is paginated like Keto's. Point an authz.keto module's read_url and
+// write_url at URL.
+type KetoServer struct {
+	*httptest.Server
+
+	mu     sync.Mutex
+	tuples []ketoTuple
+}
+
+type ketoSubjectSet struct {
+	Namespace string `json:"namespace"`
+	Object    string `json:"object"`
+	Relation  string `json:"relation"`
+}
+
+type ketoTuple struct {
+	Namespace  string          `json:"namespace"`
+	Object     string          `json:"object"`
+	Relation   string          `json:"relation"`
+	SubjectID  string          `json:"subject_id,omitempty"`
+	SubjectSet *ketoSubjectSet `json:"subject_set,omitempty"`
+}
+
+// String formats the tuple in Keto's namespace:object#relation@subject
+// notation.
+func (t ketoTuple) String() string {
+	return fmt.Sprintf("%s:%s#%s@%s", t.Namespace, t.Object, t.Relation, t.subject())
+}
+
+// subject formats the tuple's subject as a subject ID or as
+// namespace:object#relation for a subject set.
+func (t ketoTuple) subject() string {
+	if t.SubjectSet != nil {
+		return fmt.Sprintf("%s:%s#%s", t.SubjectSet.Namespace, t.SubjectSet.Object, t.SubjectSet.Relation)
+	}
+	return t.SubjectID
+}
+
+// ketoDefaultPageSize is Keto's page size when page_size is not set.
+const ketoDefaultPageSize = 100
+
+// ketoMaxDepth bounds subject-set traversal like Keto's max-depth.
+const ketoMaxDepth = 32
+
+// NewKetoServer starts a KetoServer. It is closed when t's test ends.
+func NewKetoServer(t testing.TB) *KetoServer {
+	s := &KetoServer{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /admin/relation-tuples", s.create)
+	mux.HandleFunc("DELETE /admin/relation-tuples", s.delete)
+	mux.HandleFunc("GET /relation-tuples", s.list)
+	mux.HandleFunc("GET /relation-tuples/check/openapi", s.check)
+	mux.HandleFunc("GET /relation-tuples/expand", s.expand)
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Tuples returns the stored tuples in namespace:object#relation@subject
+// notation, sorted.
+func (s *KetoServer) Tuples() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]string, 0, len(s.tuples))
+	for _, tuple := range s.tuples {
+		out = append(out, tuple.String())
+	}
+	sort.Strings(out)
+	return out
+}
+
+func (s *KetoServer) create(w http.ResponseWriter, r *http.Request) {
+	var tuple ketoTuple
+	if err := json.NewDecoder(r.Body).Decode(&tuple); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": map[string]any{"message": err.Error()}})
+		return
+	}
+	s.mu.Lock()
+	exists := false
+	for _, existing := range s.tuples {
+		if existing.String() == tuple.String() {
+			exists = true
+			break
+		}
+	}
+	if !exists {
+		s.tuples = append(s.tuples, tuple)
+	}
+	s.mu.Unlock()
+	writeJSON(w, http.StatusCreated, tuple)
+}
+
+func (s *KetoServer) delete(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	s.mu.Lock()
+	kept := s.tuples[:0]
+	for _, tuple := range s.tuples {
+		if !ketoTupleMatches(tuple, query) {
+			kept = append(kept, tuple)
+		}
+	}
+	s.tuples = kept
+	s.mu.Unlock()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *KetoServer) list(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	s.mu.Lock()
+	matched := []ketoTuple{}
+	for _, tuple := range s.tuples {
+		if ketoTupleMatches(tuple, query) {
+			matched = append(matched, tuple)
+		}
+	}
+	s.mu.Unlock()
+	// Page tokens are offsets into the matched tuples.
+	start, _ := strconv.Atoi(query.Get("page_token"))
+	size, err := strconv.Atoi(query.Get("page_size"))
+	if err != nil || size <= 0 {
+		size = ketoDefaultPageSize
+	}
+	start = min(max(start, 0), len(matched))
+	end := min(start+size, len(matched))
+	next := ""
+	if end < len(matched) {
+		next = strconv.Itoa(end)
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"relation_tuples": matched[start:end], "next_page_token": next})
+}
+
+func (s *KetoServer) check(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	subject := ketoTuple{SubjectID: query.Get("subject_id")}
+	if query.Has("subject_set.namespace") {
+		subject = ketoTuple{SubjectSet: &ketoSubjectSet{
+			Namespace: query.Get("subject_set.namespace"),
+			Object:    query.Get("subject_set.object"),
+			Relation:  query.Get("subject_set.relation"),
+		}}
+	}
+	s.mu.Lock()
+	allowed := s.allowedLocked(query.Get("namespace"), query.Get("object"), query.Get("relation"), subject.subject(), ketoMaxDepth)
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, map[string]any{"allowed": allowed})
+}
+
+// allowedLocked reports whether subject, in the notation of
+// ketoTuple.subject, has relation on namespace:object, directly or through a
+// subject set.
+func (s *KetoServer) allowedLocked(namespace, object, relation, subject string, depth int) bool {
+	if depth <= 0 {
+		return false
+	}
+	for _, tuple := range s.tuples {
+		if tuple.Namespace != namespace || tuple.Object != object || tuple.Relation != relation {
+			continue
+		}
+		if tuple.subject() == subject {
+			return true
+		}
+		if set := tuple.SubjectSet; set != nil && s.allowedLocked(set.Namespace, set.Object, set.Relation, subject, depth-1) {
+			return true
+		}
+	}
+	return false
+}
+
+// ketoTreeNode is a node of Keto's expand response.
+type ketoTreeNode struct {
+	Type     string         `json:"type"`
+	Tuple    ketoTuple      `json:"tuple"`
+	Children []ketoTreeNode `json:"children,omitempty"`
+}
+
+func (s *KetoServer) expand(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	depth, err := strconv.Atoi(query.Get("max-depth"))
+	if err != nil || depth <= 0 {
+		depth = ketoMaxDepth
+	}
+	set := ketoSubjectSet{Namespace: query.Get("namespace"), Object: query.Get("object"), Relation: query.Get("relation")}
+	s.mu.Lock()
+	tree := s.expandLocked(set, depth)
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, tree)
+}
+
+// expandLocked returns the members of set as a union node. Subject sets
+// below depth are expanded in turn; at the depth limit they are leaves.
+func (s *KetoServer) expandLocked(set ketoSubjectSet, depth int) ketoTreeNode {
+	node := ketoTreeNode{Type: "union", Tuple: ketoTuple{SubjectSet: &set}}
+	for _, tuple := range s.tuples {
+		if tuple.Namespace != set.Namespace || tuple.Object != set.Object || tuple.Relation != set.Relation {
+			continue
+		}
+		switch {
+		case tuple.SubjectSet == nil:
+			node.Children = append(node.Children, ketoTreeNode{Type: "leaf", Tuple: ketoTuple{SubjectID: tuple.SubjectID}})
+		case depth <= 1:
+			node.Children = append(node.Children, ketoTreeNode{Type: "leaf", Tuple: ketoTuple{SubjectSet: tuple.SubjectSet}})
+		default:
+			node.Children = append(node.Children, s.expandLocked(*tuple.SubjectSet, depth-1))
+		}
+	}
+	return node
+}
+
+// ketoTupleMatches applies the query filters Keto accepts for listing and
+// deleting tuples. Absent parameters match anything.
+func ketoTupleMatches(tuple ketoTuple, query url.Values) bool {
+	matches := func(key, value string) bool {
+		return !query.Has(key) || query.Get(key) == value
+	}
+	if !matches("namespace", tuple.Namespace) || !matches("object", tuple.Object) || !matches("relation", tuple.Relation) {
+		return false
+	}
+	if query.Has("subject_id") {
+		return tuple.SubjectSet == nil && tuple.SubjectID == query.Get("subject_id")
+	}
+	if query.Has("subject_set.namespace") {
+		return tuple.SubjectSet != nil &&
+			tuple.SubjectSet.Namespace == query.Get("subject_set.namespace") &&
+			matches("subject_set.object", tuple.SubjectSet.Object) &&
+			matches("subject_set.relation", tuple.SubjectSet.Relation)
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,300 @@
+package authztest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// PermitServer is an httptest stand-in for the parts of the Permit.io API and
+// PDP that permit.provider uses: resources, roles and their permissions,
+// users, role assignments, and /allowed checks. A check is allowed when the
+// user holds, in the resource's tenant, a role with the "resource:action"
+// permission. Point a permit.provider module's apiUrl and pdpUrl at URL.
+type PermitServer struct {
+	*httptest.Server
+
+	mu          sync.Mutex
+	resources   map[string]map[string]bool
+	roles       map[string]map[string]bool
+	users       map[string]bool
+	assignments map[string]map[string]bool // user -> "tenant/role"
+}
+
+// NewPermitServer starts a PermitServer. It is closed when t's test ends.
+func NewPermitServer(t testing.TB) *PermitServer {
+	s := &PermitServer{
+		resources:   map[string]map[string]bool{},
+		roles:       map[string]map[string]bool{},
+		users:       map[string]bool{},
+		assignments: map[string]map[string]bool{},
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v2/schema/{proj}/{env}/resources/{resource}", s.getResource)
+	mux.HandleFunc("POST /v2/schema/{proj}/{env}/resources", s.createResource)
+	mux.HandleFunc("GET /v2/schema/{proj}/{env}/roles/{role}", s.getRole)
+	mux.HandleFunc("POST /v2/schema/{proj}/{env}/roles", s.createRole)
+	mux.HandleFunc("POST /v2/schema/{proj}/{env}/roles/{role}/permissions", s.assignPermissions)
+	mux.HandleFunc("DELETE /v2/schema/{proj}/{env}/roles/{role}/permissions", s.removePermissions)
+	mux.HandleFunc("GET /v2/facts/{proj}/{env}/users/{user}", s.getUser)
+	mux.HandleFunc("POST /v2/facts/{proj}/{env}/users", s.createUser)
+	mux.HandleFunc("PATCH /v2/facts/{proj}/{env}/users/{user}", s.getUser)
+	mux.HandleFunc("POST /v2/facts/{proj}/{env}/users/{user}/roles", s.assignRole)
+	mux.HandleFunc("DELETE /v2/facts/{proj}/{env}/users/{user}/roles", s.unassignRole)
+	mux.HandleFunc("POST /allowed", s.allowed)
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+	return s
+}
+
+// RolePermissions returns the sorted "resource:action" permissions of role.
+func (s *PermitServer) RolePermissions(role string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sortedSet(s.roles[role])
+}
+
+// UserRoles returns the sorted roles assigned to user, as "tenant/role".
+func (s *PermitServer) UserRoles(user string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sortedSet(s.assignments[user])
+}
+
+func (s *PermitServer) getResource(w http.ResponseWriter, r *http.Request) {
+	key := r.PathValue("resource")
+	s.mu.Lock()
+	_, ok := s.resources[key]
+	s.mu.Unlock()
+	if !ok {
+		permitNotFound(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, permitObject(r, key, map[string]any{"name": key}))
+}
+
+func (s *PermitServer) createResource(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Key     string                    `json:"key"`
+		Name    string                    `json:"name"`
+		Actions map[string]map[string]any `json:"actions"`
+	}
+	if !decodePermitBody(w, r, &body) {
+		return
+	}
+	s.mu.Lock()
+	if s.resources[body.Key] == nil {
+		s.resources[body.Key] = map[string]bool{}
+	}
+	for action := range body.Actions {
+		s.resources[body.Key][action] = true
+	}
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, permitObject(r, body.Key, map[string]any{"name": body.Name}))
+}
+
+func (s *PermitServer) getRole(w http.ResponseWriter, r *http.Request) {
+	key := r.PathValue("role")
+	s.mu.Lock()
+	permissions, ok := s.roles[key]
+	s.mu.Unlock()
+	if !ok {
+		permitNotFound(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, permitObject(r, key, map[string]any{"name": key, "permissions": sortedSet(permissions)}))
+}
+
+func (s *PermitServer) createRole(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Key  string `json:"key"`
+		Name string `json:"name"`
+	}
+	if !decodePermitBody(w, r, &body) {
+		return
+	}
+	s.mu.Lock()
+	if _, ok := s.roles[body.Key]; ok {
+		s.mu.Unlock()
+		writeJSON(w, http.StatusConflict, map[string]any{"detail": "role already exists"})
+		return
+	}
+	s.roles[body.Key] = map[string]bool{}
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, permitObject(r, body.Key, map[string]any{"name": body.Name}))
+}
+
+func (s *PermitServer) assignPermissions(w http.ResponseWriter, r *http.Request) {
+	s.updatePermissions(w, r, true)
+}
+
+func (s *PermitServer) removePermissions(w http.ResponseWriter, r *http.Request) {
+	s.updatePermissions(w, r, false)
+}
+
+func (s *PermitServer) updatePermissions(w http.ResponseWriter, r *http.Request, grant bool) {
+	key := r.PathValue("role")
+	var body struct {
+		Permissions []string `json:"permissions"`
+	}
+	if !decodePermitBody(w, r, &body) {
+		return
+	}
+	s.mu.Lock()
+	permissions, ok := s.roles[key]
+	if ok {
+		for _, permission := range body.Permissions {
+			if grant {
+				permissions[permission] = true
+			} else {
+				delete(permissions, permission)
+			}
+		}
+	}
+	s.mu.Unlock()
+	if !ok {
+		permitNotFound(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, permitObject(r, key, map[string]any{"name": key}))
+}
+
+func (s *PermitServer) getUser(w http.ResponseWriter, r *http.Request) {
+	key := r.PathValue("user")
+	s.mu.Lock()
+	ok := s.users[key]
+	s.mu.Unlock()
+	if !ok {
+		permitNotFound(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, permitObject(r, key, nil))
+}
+
+func (s *PermitServer) createUser(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Key string `json:"key"`
+	}
+	if !decodePermitBody(w, r, &body) {
+		return
+	}
+	s.mu.Lock()
+	s.users[body.Key] = true
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, permitObject(r, body.Key, nil))
+}
+
+func (s *PermitServer) assignRole(w http.ResponseWriter, r *http.Request) {
+	user := r.PathValue("user")
+	var body struct {
+		Role   string `json:"role"`
+		Tenant string `json:"tenant"`
+	}
+	if !decodePermitBody(w, r, &body) {
+		return
+	}
+	s.mu.Lock()
+	_, roleExists := s.roles[body.Role]
+	if roleExists && s.users[user] {
+		if s.assignments[user] == nil {
+			s.assignments[user] = map[string]bool{}
+		}
+		s.assignments[user][body.Tenant+"/"+body.Role] = true
+	}
+	userExists := s.users[user]
+	s.mu.Unlock()
+	if !roleExists || !userExists {
+		permitNotFound(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, permitObject(r, user+"/"+body.Role, map[string]any{
+		"user": user, "role": body.Role, "tenant": body.Tenant,
+		"user_id": user, "role_id": body.Role, "tenant_id": body.Tenant,
+	}))
+}
+
+func (s *PermitServer) unassignRole(w http.ResponseWriter, r *http.Request) {
+	user := r.PathValue("user")
+	var body struct {
+		Role   string `json:"role"`
+		Tenant string `json:"tenant"`
+	}
+	if !decodePermitBody(w, r, &body) {
+		return
+	}
+	s.mu.Lock()
+	delete(s.assignments[user], body.Tenant+"/"+body.Role)
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, permitObject(r, user, nil))
+}
+
+func (s *PermitServer) allowed(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		User     struct{ Key string } `json:"user"`
+		Action   string               `json:"action"`
+		Resource struct {
+			Type   string `json:"type"`
+			Tenant string `json:"tenant"`
+		} `json:"resource"`
+	}
+	if !decodePermitBody(w, r, &body) {
+		return
+	}
+	permission := body.Resource.Type + ":" + body.Action
+	s.mu.Lock()
+	allow := false
+	for assignment := range s.assignments[body.User.Key] {
+		tenant, role, _ := strings.Cut(assignment, "/")
+		if tenant == body.Resource.Tenant && s.roles[role][permission] {
+			allow = true
+			break
+		}
+	}
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, map[string]any{"allow": allow})
+}
+
+// permitObject returns a response object with the fields the Permit SDK
+// requires on every read model.
+func permitObject(r *http.Request, key string, fields map[string]any) map[string]any {
+	now := time.Now().UTC().Format(time.RFC3339)
+	object := map[string]any{
+		"key":             key,
+		"id":              key,
+		"organization_id": "org",
+		"project_id":      r.PathValue("proj"),
+		"environment_id":  r.PathValue("env"),
+		"created_at":      now,
+		"updated_at":      now,
+	}
+	for name, value := range fields {
+		object[name] = value
+	}
+	return object
+}
+
+func decodePermitBody(w http.ResponseWriter, r *http.Request, body any) bool {
+	if err := json.NewDecoder(r.Body).Decode(body); err != nil {
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]any{"detail": err.Error()})
+		return false
+	}
+	return true
+}
+
+func permitNotFound(w http.ResponseWriter) {
+	writeJSON(w, http.StatusNotFound, map[string]any{"detail": "not found"})
+}
+
+func sortedSet(set map[string]bool) []string {
+	out := make([]string, 0, len(set))
+	for value := range set {
+		out = append(out, value)
+	}
+	sort.Strings(out)
+	return out
+}
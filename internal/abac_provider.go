@@ -189,6 +189,33 @@ func (s *attributePolicyStore) CheckAttributes(_ context.Context, check Attribut
 	return result, nil
 }
 
+// declarations returns copies of the declared attributes keyed by
+// attributeDeclarationKey.
+func (s *attributePolicyStore) declarations() map[string]*contracts.AttributeDeclaration {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make(map[string]*contracts.AttributeDeclaration, len(s.attrs))
+	for key, attr := range s.attrs {
+		out[key] = cloneAttributeDeclaration(attr)
+	}
+	return out
+}
+
+// clone returns an independent copy of the store for what-if evaluation.
+func (s *attributePolicyStore) clone() *attributePolicyStore {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := newAttributePolicyStore(s.provider, s.supported)
+	for key, attr := range s.attrs {
+		out.attrs[key] = cloneAttributeDeclaration(attr)
+	}
+	for key, policy := range s.policies {
+		out.policies[key] = cloneAttributePolicy(policy)
+	}
+	out.attributes = cloneAttributeDeclarations(s.attributes)
+	return out
+}
+
 func (s *attributePolicyStore) ensureSupported() error {
 	if s.supported != nil && !s.supported() {
 		return errUnsupportedABAC
@@ -0,0 +1,482 @@
+package internal
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"text/template"
+	"time"
+)
+
+// ErrAuditChainBroken is matched by the *AuditChainError returned from
+// VerifyAuditChain.
+var ErrAuditChainBroken = errors.New("audit chain broken")
+
+// AuditChainError reports the first entry whose hash or link to its
+// predecessor does not match.
+type AuditChainError struct {
+	Seq    uint64
+	Reason string
+}
+
+func (e *AuditChainError) Error() string {
+	return fmt.Sprintf("%v at seq %d: %s", ErrAuditChainBroken, e.Seq, e.Reason)
+}
+
+func (e *AuditChainError) Unwrap() error { return ErrAuditChainBroken }
+
+// AuditEntry records one administrative mutation. Entries form a hash chain:
+// Hash covers every other field including PrevHash, so editing, removing or
+// reordering an entry invalidates every later hash.
+type AuditEntry struct {
+	Seq        uint64         `json:"seq"`
+	Time       time.Time      `json:"time"`
+	Actor      string         `json:"actor,omitempty"`
+	ActorEmail string         `json:"actor_email,omitempty"`
+	Source     string         `json:"source,omitempty"`    // "adminapi" or "step"
+	Operation  string         `json:"operation,omitempty"` // route name or step type
+	Module     string         `json:"module,omitempty"`
+	Resource   string         `json:"resource,omitempty"`
+	Action     string         `json:"action,omitempty"`
+	Context    string         `json:"context,omitempty"`
+	Before     map[string]any `json:"before,omitempty"`
+	After      map[string]any `json:"after,omitempty"`
+	Reason     string         `json:"reason,omitempty"`
+	PrevHash   string         `json:"prev_hash,omitempty"`
+	Hash       string         `json:"hash,omitempty"`
+}
+
+// AuditFilter selects audit entries. Zero fields match everything; Limit keeps
+// the most recent entries.
+type AuditFilter struct {
+	Actor     string
+	Operation string
+	Resource  string
+	Context   string
+	Since     time.Time
+	Until     time.Time
+	Limit     int
+}
+
+// AuditSink stores audit entries in sequence order.
+type AuditSink interface {
+	Append(context.Context, AuditEntry) error
+	// List returns the entries matching filter in sequence order.
+	List(context.Context, AuditFilter) ([]AuditEntry, error)
+	// Last returns the entry with the highest sequence number.
+	Last(context.Context) (AuditEntry, bool, error)
+	Close() error
+}
+
+// auditLog assigns sequence numbers and chain hashes before handing entries
+// to its sink. Appends are serialized per process.
+type auditLog struct {
+	mu     sync.Mutex
+	sink   AuditSink
+	last   AuditEntry
+	loaded bool
+	now    func() time.Time
+}
+
+func newAuditLog(sink AuditSink) *auditLog {
+	return &auditLog{sink: sink, now: time.Now}
+}
+
+// Record completes entry with its sequence number, timestamp and hashes and
+// appends it. When another writer appended to a shared sink in the meantime,
+// the chain head is reloaded and the append retried once.
+func (l *auditLog) Record(ctx context.Context, entry AuditEntry) (AuditEntry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if entry.Time.IsZero() {
+		entry.Time = l.now()
+	}
+	entry.Time = entry.Time.UTC().Truncate(time.Microsecond)
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		if !l.loaded || attempt > 0 {
+			if err := l.reload(ctx); err != nil {
+				return AuditEntry{}, err
+			}
+		}
+		entry.Seq = l.last.Seq + 1
+		entry.PrevHash = l.last.Hash
+		entry.Hash = auditEntryHash(entry)
+		if err = l.sink.Append(ctx, entry); err == nil {
+			l.last = entry
+			return entry, nil
+		}
+	}
+	return AuditEntry{}, fmt.Errorf("append audit entry: %w", err)
+}
+
+func (l *auditLog) reload(ctx context.Context) error {
+	last, _, err := l.sink.Last(ctx)
+	if err != nil {
+		return fmt.Errorf("load audit chain head: %w", err)
+	}
+	l.last = last
+	l.loaded = true
+	return nil
+}
+
+func (l *auditLog) List(ctx context.Context, filter AuditFilter) ([]AuditEntry, error) {
+	return l.sink.List(ctx, filter)
+}
+
+// Verify checks the whole chain held by the sink.
+func (l *auditLog) Verify(ctx context.Context) (int, error) {
+	entries, err := l.sink.List(ctx, AuditFilter{})
+	if err != nil {
+		return 0, err
+	}
+	return len(entries), VerifyAuditChain(entries)
+}
+
+// VerifyAuditChain checks that entries form an unbroken chain starting at
+// sequence 1.
+func VerifyAuditChain(entries []AuditEntry) error {
+	prev := AuditEntry{}
+	for _, entry := range entries {
+		if entry.Seq != prev.Seq+1 {
+			return &AuditChainError{Seq: entry.Seq, Reason: fmt.Sprintf("expected seq %d", prev.Seq+1)}
+		}
+		if entry.PrevHash != prev.Hash {
+			return &AuditChainError{Seq: entry.Seq, Reason: "previous hash mismatch"}
+		}
+		if entry.Hash != auditEntryHash(entry) {
+			return &AuditChainError{Seq: entry.Seq, Reason: "entry hash mismatch"}
+		}
+		prev = entry
+	}
+	return nil
+}
+
+// auditEntryHash hashes the canonical JSON encoding of entry without its own
+// hash. encoding/json sorts map keys, so Before/After encode deterministically
+// after a round trip through any sink.
+func auditEntryHash(entry AuditEntry) string {
+	entry.Hash = ""
+	entry.Time = entry.Time.UTC()
+	data, _ := json.Marshal(entry)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func (f AuditFilter) matches(entry AuditEntry) bool {
+	switch {
+	case f.Actor != "" && entry.Actor != f.Actor:
+		return false
+	case f.Operation != "" && entry.Operation != f.Operation:
+		return false
+	case f.Resource != "" && entry.Resource != f.Resource:
+		return false
+	case f.Context != "" && entry.Context != f.Context:
+		return false
+	case !f.Since.IsZero() && entry.Time.Before(f.Since):
+		return false
+	case !f.Until.IsZero() && entry.Time.After(f.Until):
+		return false
+	}
+	return true
+}
+
+func limitAuditEntries(entries []AuditEntry, limit int) []AuditEntry {
+	if limit > 0 && len(entries) > limit {
+		return entries[len(entries)-limit:]
+	}
+	return entries
+}
+
+// memoryAuditSink keeps entries in process memory. It is the default sink
+// and is mainly useful for tests and short-lived hosts.
+type memoryAuditSink struct {
+	mu      sync.RWMutex
+	entries []AuditEntry
+}
+
+func (s *memoryAuditSink) Append(_ context.Context, entry AuditEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if n := len(s.entries); n > 0 && s.entries[n-1].Seq >= entry.Seq {
+		return fmt.Errorf("audit seq %d already recorded", entry.Seq)
+	}
+	s.entries = append(s.entries, entry)
+	return nil
+}
+
+func (s *memoryAuditSink) List(_ context.Context, filter AuditFilter) ([]AuditEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var out []AuditEntry
+	for _, entry := range s.entries {
+		if filter.matches(entry) {
+			out = append(out, entry)
+		}
+	}
+	return limitAuditEntries(out, filter.Limit), nil
+}
+
+func (s *memoryAuditSink) Last(context.Context) (AuditEntry, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if len(s.entries) == 0 {
+		return AuditEntry{}, false, nil
+	}
+	return s.entries[len(s.entries)-1], true, nil
+}
+
+func (s *memoryAuditSink) Close() error { return nil }
+
+// jsonlAuditSink appends one JSON object per line to a file. Reads scan the
+// whole file, which keeps the format trivially greppable and shippable.
+type jsonlAuditSink struct {
+	mu   sync.Mutex
+	path string
+}
+
+func newJSONLAuditSink(path string) (*jsonlAuditSink, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	_ = f.Close()
+	return &jsonlAuditSink{path: path}, nil
+}
+
+func (s *jsonlAuditSink) Append(_ context.Context, entry AuditEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (s *jsonlAuditSink) List(_ context.Context, filter AuditFilter) ([]AuditEntry, error) {
+	entries, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	out := entries[:0]
+	for _, entry := range entries {
+		if filter.matches(entry) {
+			out = append(out, entry)
+		}
+	}
+	return limitAuditEntries(out, filter.Limit), nil
+}
+
+func (s *jsonlAuditSink) Last(context.Context) (AuditEntry, bool, error) {
+	entries, err := s.read()
+	if err != nil || len(entries) == 0 {
+		return AuditEntry{}, false, err
+	}
+	return entries[len(entries)-1], true, nil
+}
+
+func (s *jsonlAuditSink) Close() error { return nil }
+
+func (s *jsonlAuditSink) read() ([]AuditEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, err := os.Open(s.path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var entries []AuditEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", s.path, line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// --- registry ---
+
+var auditRegistry = struct {
+	sync.RWMutex
+	logs        map[string]*auditLog
+	defaultName string
+}{logs: map[string]*auditLog{}}
+
+// registerAuditLog makes log available to steps and adminapi under name. The
+// first registered log becomes the default for steps without audit_module.
+func registerAuditLog(name string, log *auditLog) {
+	auditRegistry.Lock()
+	defer auditRegistry.Unlock()
+	auditRegistry.logs[name] = log
+	if auditRegistry.defaultName == "" {
+		auditRegistry.defaultName = name
+	}
+}
+
+func unregisterAuditLog(name string) {
+	auditRegistry.Lock()
+	defer auditRegistry.Unlock()
+	delete(auditRegistry.logs, name)
+	if auditRegistry.defaultName == name {
+		auditRegistry.defaultName = ""
+		names := make([]string, 0, len(auditRegistry.logs))
+		for other := range auditRegistry.logs {
+			names = append(names, other)
+		}
+		sort.Strings(names)
+		if len(names) > 0 {
+			auditRegistry.defaultName = names[0]
+		}
+	}
+}
+
+// lookupAuditLog returns the named log, or the default log when name is empty.
+func lookupAuditLog(name string) (*auditLog, bool) {
+	auditRegistry.RLock()
+	defer auditRegistry.RUnlock()
+	if name == "" {
+		name = auditRegistry.defaultName
+	}
+	log, ok := auditRegistry.logs[name]
+	return log, ok
+}
+
+// --- step integration ---
+
+// stepAudit records the mutations of one step instance. Steps audit only when
+// an authz.audit module is running; naming a missing audit_module is an error.
+//
+// Config keys shared by mutating steps:
+//
+//	actor: "{{.auth_user_id}}"   # who made the change; Go template (default: auth_user_id)
+//	reason: "ticket {{.ticket}}" # why; Go template
+//	audit_module: "audit"        # authz.audit module (default: first registered)
+type stepAudit struct {
+	stepType string
+	module   string
+	static   []string
+	tmpls    []*template.Template
+}
+
+func newStepAudit(stepType string, config map[string]any) stepAudit {
+	static, tmpls := compileRuleTemplates([]string{stringValue(config["actor"]), stringValue(config["reason"])})
+	return stepAudit{stepType: stepType, module: stringValue(config["audit_module"]), static: static, tmpls: tmpls}
+}
+
+// errAuditActorRequired is returned by begin when an audit log is in use but
+// neither config.actor nor auth_user_id names who is making the change.
+var errAuditActorRequired = errors.New("audit requires an actor: set config.actor or authenticate the request first")
+
+// begin resolves the audit log, actor and reason before a step mutates
+// anything, so a misconfigured audit fails the step without a change. The
+// actor defaults to the authenticated user, auth_user_id. Without any actor
+// begin returns errAuditActorRequired along with an audit whose records all
+// fail, for steps that only sometimes write.
+func (a stepAudit) begin(data map[string]any) (pendingAudit, error) {
+	auditLog, ok := lookupAuditLog(a.module)
+	if !ok {
+		if a.module != "" {
+			return pendingAudit{}, fmt.Errorf("audit module %q not found", a.module)
+		}
+		return pendingAudit{}, nil
+	}
+	values, err := resolveRule(a.static, a.tmpls, data)
+	if err != nil {
+		return pendingAudit{}, fmt.Errorf("resolve audit fields: %w", err)
+	}
+	pending := pendingAudit{log: auditLog, stepType: a.stepType, actor: defaultString(values[0], stringValue(data["auth_user_id"])), reason: values[1]}
+	if pending.actor == "" {
+		return pending, errAuditActorRequired
+	}
+	return pending, nil
+}
+
+// pendingAudit records the changes of one step execution.
+type pendingAudit struct {
+	log      *auditLog
+	stepType string
+	actor    string
+	reason   string
+}
+
+// record completes entry with the step's actor and reason and appends it to
+// the audit log. The change it describes is already committed, so a failure
+// is logged rather than returned, and record reports false.
+func (p pendingAudit) record(ctx context.Context, entry AuditEntry) bool {
+	if p.log == nil {
+		return true
+	}
+	if p.actor == "" {
+		log.Printf("authz: %s: record audit entry for a committed change: %v", p.stepType, errAuditActorRequired)
+		return false
+	}
+	entry.Source = "step"
+	entry.Operation = p.stepType
+	entry.Actor, entry.Reason = p.actor, p.reason
+	if _, err := p.log.Record(ctx, entry); err != nil {
+		log.Printf("authz: %s: record audit entry for a committed change: %v", p.stepType, err)
+		return false
+	}
+	return true
+}
+
+// markUnaudited adds audited: false to output when a change was not recorded.
+func markUnaudited(output map[string]any, audited bool) map[string]any {
+	if !audited {
+		output["audited"] = false
+	}
+	return output
+}
+
+// ruleAuditValue describes a Casbin policy or grouping rule in audit entries.
+func ruleAuditValue(rule []string) map[string]any {
+	return map[string]any{"rule": stringsToAny(rule)}
+}
+
+// relationAuditValue describes a (subject, relation, object) tuple in audit
+// entries.
+func relationAuditValue(vals []string) map[string]any {
+	return map[string]any{"subject": vals[0], "relation": vals[1], "object": vals[2]}
+}
+
+// auditBeforeAfter returns the before/after values for an add (add=true) or
+// remove of value. changed reports whether the provider actually mutated
+// state. An add that changed nothing found value already present, so it is
+// recorded on both sides; a remove that changed nothing found it absent, so
+// neither side is recorded.
+func auditBeforeAfter(value map[string]any, add, changed bool) (before, after map[string]any) {
+	switch {
+	case add && changed:
+		return nil, value
+	case add:
+		return value, value
+	case changed:
+		return value, nil
+	default:
+		return nil, nil
+	}
+}
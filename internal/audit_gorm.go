@@ -0,0 +1,189 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const defaultAuditTable = "authz_audit"
+
+// auditRow is the table schema of gormAuditSink. Before and After hold JSON
+// text so the schema is identical on every supported database. Time keeps
+// microseconds, the precision auditLog hashes; MySQL's datetime would
+// otherwise round it to milliseconds and break every recomputed hash.
+type auditRow struct {
+	Seq        uint64    `gorm:"column:seq;primaryKey;autoIncrement:false"`
+	Time       time.Time `gorm:"column:time;precision:6;index"`
+	Actor      string    `gorm:"column:actor;size:255;index"`
+	ActorEmail string    `gorm:"column:actor_email;size:255"`
+	Source     string    `gorm:"column:source;size:32"`
+	Operation  string    `gorm:"column:operation;size:128"`
+	Module     string    `gorm:"column:module;size:255"`
+	Resource   string    `gorm:"column:resource;size:255"`
+	Action     string    `gorm:"column:action;size:64"`
+	Context    string    `gorm:"column:context;size:255"`
+	Before     string    `gorm:"column:before_value;type:text"`
+	After      string    `gorm:"column:after_value;type:text"`
+	Reason     string    `gorm:"column:reason;type:text"`
+	PrevHash   string    `gorm:"column:prev_hash;size:64"`
+	Hash       string    `gorm:"column:hash;size:64"`
+}
+
+// gormAuditSink stores entries in a database table. The primary key on Seq
+// makes concurrent writers sharing the table fail instead of forking the
+// chain; auditLog then reloads the head and retries.
+type gormAuditSink struct {
+	db    *gorm.DB
+	table string
+}
+
+func newGORMAuditSink(db *gorm.DB, table string) (*gormAuditSink, error) {
+	if table == "" {
+		table = defaultAuditTable
+	}
+	if db.Dialector.Name() != "sqlite" || !db.Migrator().HasTable(table) {
+		if err := db.Table(table).AutoMigrate(&auditRow{}); err != nil {
+			return nil, err
+		}
+	}
+	return &gormAuditSink{db: db, table: table}, nil
+}
+
+func (s *gormAuditSink) Append(ctx context.Context, entry AuditEntry) error {
+	row, err := auditRowFromEntry(entry)
+	if err != nil {
+		return err
+	}
+	return s.db.WithContext(ctx).Table(s.table).Create(&row).Error
+}
+
+func (s *gormAuditSink) List(ctx context.Context, filter AuditFilter) ([]AuditEntry, error) {
+	query := s.db.WithContext(ctx).Table(s.table)
+	for column, value := range map[string]string{
+		"actor":     filter.Actor,
+		"operation": filter.Operation,
+		"resource":  filter.Resource,
+		"context":   filter.Context,
+	} {
+		if value != "" {
+			query = query.Where(quoteIdent(s.db, column)+" = ?", value)
+		}
+	}
+	if !filter.Since.IsZero() {
+		query = query.Where(quoteIdent(s.db, "time")+" >= ?", filter.Since.UTC())
+	}
+	if !filter.Until.IsZero() {
+		query = query.Where(quoteIdent(s.db, "time")+" <= ?", filter.Until.UTC())
+	}
+	var rows []auditRow
+	if filter.Limit > 0 {
+		query = query.Order("seq DESC").Limit(filter.Limit)
+	} else {
+		query = query.Order("seq ASC")
+	}
+	if err := query.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	if filter.Limit > 0 {
+		for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+			rows[i], rows[j] = rows[j], rows[i]
+		}
+	}
+	entries := make([]AuditEntry, 0, len(rows))
+	for _, row := range rows {
+		entry, err := row.entry()
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func (s *gormAuditSink) Last(ctx context.Context) (AuditEntry, bool, error) {
+	var rows []auditRow
+	if err := s.db.WithContext(ctx).Table(s.table).Order("seq DESC").Limit(1).Find(&rows).Error; err != nil {
+		return AuditEntry{}, false, err
+	}
+	if len(rows) == 0 {
+		return AuditEntry{}, false, nil
+	}
+	entry, err := rows[0].entry()
+	return entry, err == nil, err
+}
+
+func (s *gormAuditSink) Close() error {
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
+
+func auditRowFromEntry(entry AuditEntry) (auditRow, error) {
+	before, err := auditJSONText(entry.Before)
+	if err != nil {
+		return auditRow{}, err
+	}
+	after, err := auditJSONText(entry.After)
+	if err != nil {
+		return auditRow{}, err
+	}
+	return auditRow{
+		Seq:        entry.Seq,
+		Time:       entry.Time.UTC(),
+		Actor:      entry.Actor,
+		ActorEmail: entry.ActorEmail,
+		Source:     entry.Source,
+		Operation:  entry.Operation,
+		Module:     entry.Module,
+		Resource:   entry.Resource,
+		Action:     entry.Action,
+		Context:    entry.Context,
+		Before:     before,
+		After:      after,
+		Reason:     entry.Reason,
+		PrevHash:   entry.PrevHash,
+		Hash:       entry.Hash,
+	}, nil
+}
+
+func (r auditRow) entry() (AuditEntry, error) {
+	entry := AuditEntry{
+		Seq:        r.Seq,
+		Time:       r.Time.UTC(),
+		Actor:      r.Actor,
+		ActorEmail: r.ActorEmail,
+		Source:     r.Source,
+		Operation:  r.Operation,
+		Module:     r.Module,
+		Resource:   r.Resource,
+		Action:     r.Action,
+		Context:    r.Context,
+		Reason:     r.Reason,
+		PrevHash:   r.PrevHash,
+		Hash:       r.Hash,
+	}
+	if r.Before != "" {
+		if err := json.Unmarshal([]byte(r.Before), &entry.Before); err != nil {
+			return AuditEntry{}, err
+		}
+	}
+	if r.After != "" {
+		if err := json.Unmarshal([]byte(r.After), &entry.After); err != nil {
+			return AuditEntry{}, err
+		}
+	}
+	return entry, nil
+}
+
+func auditJSONText(value map[string]any) (string, error) {
+	if value == nil {
+		return "", nil
+	}
+	data, err := json.Marshal(value)
+	return string(data), err
+}
@@ -0,0 +1,280 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gorm.io/gorm/schema"
+)
+
+func startAuditModule(t *testing.T, name string, config map[string]any) *auditModule {
+	t.Helper()
+	mod, err := newAuditModule(name, config)
+	if err != nil {
+		t.Fatalf("newAuditModule: %v", err)
+	}
+	if err := mod.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	t.Cleanup(func() { _ = mod.Stop(context.Background()) })
+	return mod
+}
+
+func TestAuditLogDetectsTampering(t *testing.T) {
+	sink := &memoryAuditSink{}
+	log := newAuditLog(sink)
+	ctx := context.Background()
+	for _, actor := range []string{"alice", "bob", "carol"} {
+		if _, err := log.Record(ctx, AuditEntry{Actor: actor, Operation: "roles-upsert"}); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+	}
+	checked, err := log.Verify(ctx)
+	if err != nil || checked != 3 {
+		t.Fatalf("Verify = %d, %v; want 3, nil", checked, err)
+	}
+
+	entries, _ := sink.List(ctx, AuditFilter{})
+	entries[1].Actor = "mallory"
+	var chainErr *AuditChainError
+	if err := VerifyAuditChain(entries); !errors.As(err, &chainErr) || chainErr.Seq != 2 {
+		t.Fatalf("edited entry: got %v, want chain error at seq 2", err)
+	}
+
+	entries, _ = sink.List(ctx, AuditFilter{})
+	if err := VerifyAuditChain(append(entries[:1:1], entries[2])); !errors.Is(err, ErrAuditChainBroken) {
+		t.Fatalf("deleted entry: got %v, want ErrAuditChainBroken", err)
+	}
+}
+
+func TestAuditJSONLSinkResumesChain(t *testing.T) {
+	path := t.TempDir() + "/audit.jsonl"
+	ctx := context.Background()
+	sink, err := newJSONLAuditSink(path)
+	if err != nil {
+		t.Fatalf("newJSONLAuditSink: %v", err)
+	}
+	if _, err := newAuditLog(sink).Record(ctx, AuditEntry{Actor: "alice", Before: map[string]any{"n": 1}}); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+
+	// A new log over the same file continues the chain from its last entry.
+	log := newAuditLog(sink)
+	entry, err := log.Record(ctx, AuditEntry{Actor: "bob"})
+	if err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	if entry.Seq != 2 {
+		t.Fatalf("Seq = %d, want 2", entry.Seq)
+	}
+	if checked, err := log.Verify(ctx); err != nil || checked != 2 {
+		t.Fatalf("Verify = %d, %v; want 2, nil", checked, err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tampered := strings.Replace(string(data), `"alice"`, `"mallory"`, 1)
+	if err := os.WriteFile(path, []byte(tampered), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := log.Verify(ctx); !errors.Is(err, ErrAuditChainBroken) {
+		t.Fatalf("Verify after tampering = %v, want ErrAuditChainBroken", err)
+	}
+}
+
+func TestAuditGORMSinkFiltersAndVerifies(t *testing.T) {
+	mod := startAuditModule(t, "audit-gorm", map[string]any{
+		"sink":   "gorm",
+		"driver": "sqlite3",
+		"dsn":    "file:" + t.TempDir() + "/audit.db",
+	})
+	ctx := context.Background()
+	for _, entry := range []AuditEntry{
+		{Actor: "alice", Operation: "roles-upsert", Context: "billing", After: map[string]any{"role": "viewer"}},
+		{Actor: "bob", Operation: "roles-delete", Context: "billing"},
+		{Actor: "alice", Operation: "policies-upsert"},
+	} {
+		if _, err := mod.log.Record(ctx, entry); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+	}
+
+	out, err := mod.InvokeMethod("ListAuditEntries", map[string]any{"actor": "alice", "limit": 1})
+	if err != nil {
+		t.Fatalf("ListAuditEntries: %v", err)
+	}
+	entries := out["entries"].([]any)
+	if len(entries) != 1 || entries[0].(map[string]any)["operation"] != "policies-upsert" {
+		t.Fatalf("entries = %v, want alice's latest entry only", entries)
+	}
+
+	out, err = mod.InvokeMethod("ListAuditEntries", map[string]any{"context": "billing"})
+	if err != nil {
+		t.Fatalf("ListAuditEntries: %v", err)
+	}
+	entries = out["entries"].([]any)
+	if len(entries) != 2 || entries[0].(map[string]any)["after"].(map[string]any)["role"] != "viewer" {
+		t.Fatalf("entries = %v, want both billing entries with after value", entries)
+	}
+
+	out, err = mod.InvokeMethod("VerifyAuditChain", nil)
+	if err != nil {
+		t.Fatalf("VerifyAuditChain: %v", err)
+	}
+	if out["valid"] != true || out["checked"] != 3 {
+		t.Fatalf("VerifyAuditChain = %v, want valid chain of 3", out)
+	}
+
+	if _, err := mod.InvokeMethod("ListAuditEntries", map[string]any{"since": "yesterday"}); err == nil {
+		t.Fatal("expected error for invalid since")
+	}
+}
+
+func TestAuditGORMSinkRoundTripsEntryTimes(t *testing.T) {
+	// MySQL's datetime defaults to milliseconds; the column must keep the
+	// microseconds covered by the hash.
+	rowSchema, err := schema.Parse(&auditRow{}, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatalf("schema.Parse: %v", err)
+	}
+	if field := rowSchema.LookUpField("time"); field == nil || field.Precision != 6 {
+		t.Fatalf("time column = %#v, want precision 6", field)
+	}
+
+	mod := startAuditModule(t, "audit-gorm-times", map[string]any{
+		"sink":   "gorm",
+		"driver": "sqlite3",
+		"dsn":    "file:" + t.TempDir() + "/audit.db",
+	})
+	ctx := context.Background()
+	recorded, err := mod.log.Record(ctx, AuditEntry{Actor: "alice", Time: time.Date(2026, 3, 1, 12, 0, 0, 123456789, time.FixedZone("CET", 3600))})
+	if err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	entries, err := mod.log.List(ctx, AuditFilter{})
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("List = %v, %v", entries, err)
+	}
+	if !entries[0].Time.Equal(recorded.Time) || entries[0].Time.Nanosecond() != 123456000 {
+		t.Fatalf("stored time = %v, want %v", entries[0].Time, recorded.Time)
+	}
+	if err := VerifyAuditChain(entries); err != nil {
+		t.Fatalf("VerifyAuditChain after round trip: %v", err)
+	}
+}
+
+func TestMutatingStepRecordsAuditEntry(t *testing.T) {
+	audit := startAuditModule(t, "audit", nil)
+	mod := buildModule(t, nil, nil)
+
+	s, err := newAuthzRoleAssignStep("assign", map[string]any{
+		"assignments": []any{[]any{"{{.user}}", "editor"}},
+		"actor":       "{{.auth_user_id}}",
+		"reason":      "ticket {{.ticket}}",
+	})
+	if err != nil {
+		t.Fatalf("newAuthzRoleAssignStep: %v", err)
+	}
+	s.registry = &testRegistry{mod: mod}
+
+	start := time.Now().Add(-time.Second)
+	data := map[string]any{"user": "alice", "auth_user_id": "admin-1", "ticket": "OPS-7"}
+	for i := 0; i < 2; i++ {
+		if _, err := s.Execute(context.Background(), data, nil, nil, nil, nil); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+	}
+
+	// The repeated assignment changed nothing; it is still recorded, with the
+	// same value on both sides.
+	entries, err := audit.log.List(context.Background(), AuditFilter{Since: start})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("entries = %+v, want 2", entries)
+	}
+	entry := entries[0]
+	if entry.Actor != "admin-1" || entry.Reason != "ticket OPS-7" || entry.Source != "step" || entry.Operation != "step.authz_role_assign" {
+		t.Fatalf("entry = %+v", entry)
+	}
+	if entry.Before != nil || entry.After == nil {
+		t.Fatalf("before/after = %v/%v, want only after", entry.Before, entry.After)
+	}
+	if entries[1].Before == nil || entries[1].After == nil {
+		t.Fatalf("unchanged entry before/after = %v/%v, want both", entries[1].Before, entries[1].After)
+	}
+}
+
+// failingAuditSink accepts reads but rejects every append.
+type failingAuditSink struct{ memoryAuditSink }
+
+func (s *failingAuditSink) Append(context.Context, AuditEntry) error {
+	return errors.New("disk full")
+}
+
+func TestStepAuditActorDefaultsToAuthenticatedUser(t *testing.T) {
+	audit := startAuditModule(t, "audit", nil)
+	mod := buildModule(t, nil, nil)
+	s, err := newAuthzAddPolicyStep("add", map[string]any{"rule": []any{"editor", "/api/posts", "POST"}})
+	if err != nil {
+		t.Fatalf("newAuthzAddPolicyStep: %v", err)
+	}
+	s.registry = &testRegistry{mod: mod}
+
+	// Without an actor the step fails before it changes anything.
+	if _, err := s.Execute(context.Background(), nil, nil, nil, nil, nil); !errors.Is(err, errAuditActorRequired) {
+		t.Fatalf("Execute without an actor = %v, want errAuditActorRequired", err)
+	}
+	if allowed, _ := mod.Enforce("editor", "/api/posts", "POST"); allowed {
+		t.Fatal("policy was added although the change could not be audited")
+	}
+
+	start := time.Now().Add(-time.Second)
+	if _, err := s.Execute(context.Background(), map[string]any{"auth_user_id": "admin-1"}, nil, nil, nil, nil); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	entries, err := audit.log.List(context.Background(), AuditFilter{Since: start})
+	if err != nil || len(entries) != 1 || entries[0].Actor != "admin-1" {
+		t.Fatalf("entries = %+v, %v; want one by admin-1", entries, err)
+	}
+}
+
+func TestStepReportsUnauditedChangeWhenRecordFails(t *testing.T) {
+	audit := startAuditModule(t, "audit", nil)
+	audit.log.sink = &failingAuditSink{}
+	mod := buildModule(t, nil, nil)
+	s, err := newAuthzAddPolicyStep("add", map[string]any{"rule": []any{"editor", "/api/posts", "POST"}, "actor": "admin-1"})
+	if err != nil {
+		t.Fatalf("newAuthzAddPolicyStep: %v", err)
+	}
+	s.registry = &testRegistry{mod: mod}
+
+	// The change is committed, so the step succeeds and says it is unaudited.
+	result, err := s.Execute(context.Background(), nil, nil, nil, nil, nil)
+	if err != nil || result.Output["authz_policy_added"] != true || result.Output["audited"] != false {
+		t.Fatalf("Execute = %#v, %v; want the policy added and audited false", result, err)
+	}
+}
+
+func TestStepAuditRequiresNamedModule(t *testing.T) {
+	mod := buildModule(t, nil, nil)
+	s, err := newAuthzAddPolicyStep("add", map[string]any{
+		"rule":         []any{"editor", "/api/posts", "POST"},
+		"audit_module": "missing-audit",
+	})
+	if err != nil {
+		t.Fatalf("newAuthzAddPolicyStep: %v", err)
+	}
+	s.registry = &testRegistry{mod: mod}
+	if _, err := s.Execute(context.Background(), nil, nil, nil, nil, nil); err == nil {
+		t.Fatal("expected error for missing audit module")
+	}
+}
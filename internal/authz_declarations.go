@@ -1,6 +1,7 @@
 package internal
 
 import (
+	"context"
 	"fmt"
 	"sort"
 	"strings"
@@ -31,58 +32,99 @@ func (m *scopeCatalogModule) registerDeclarations(input *contracts.RegisterDecla
 		return nil, err
 	}
 
-	registered := int32(0)
-	if len(set.GetScopes()) > 0 {
-		scopeOut, err := m.registerScopes(&contracts.RegisterScopesInput{
-			Scopes:      set.GetScopes(),
-			OwnerPlugin: ownerPlugin,
-			OwnerModule: ownerModule,
-		})
-		if err != nil {
-			return nil, err
+	out, err := m.storeDeclarations(set, ownerPlugin, ownerModule)
+	if err != nil {
+		return nil, err
+	}
+	if declarationCount(out.GetDeclarations()) > 0 {
+		if err := m.persist(context.Background(), out.GetDeclarations()); err != nil {
+			return nil, fmt.Errorf("authz.scope_catalog %q: persist declarations: %w", m.name, err)
 		}
-		registered += scopeOut.GetRegistered()
-		set.Scopes = scopeOut.GetScopes()
+		publishChange(m.name, "scope_catalog", ChangeDeclarationsRegistered, registerDeclarationsOutputToMap(out))
+		out.SubscriberErrors = errorStrings(m.pushToSubscribers(context.Background(), out.GetDeclarations()))
 	}
+	return out, nil
+}
 
+// storeDeclarations stores the declarations of set that the caller may claim
+// and returns them alongside any ownership conflicts. Scopes whose resource
+// lifecycle changed them are returned too.
+func (m *scopeCatalogModule) storeDeclarations(set *contracts.AuthzDeclarationSet, ownerPlugin, ownerModule string) (*contracts.RegisterDeclarationsOutput, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	if err := m.validateReplacementsLocked(set); err != nil {
+		return nil, err
+	}
+	scopeOut := m.storeScopesLocked(&contracts.RegisterScopesInput{
+		Scopes:      set.GetScopes(),
+		OwnerPlugin: ownerPlugin,
+		OwnerModule: ownerModule,
+	})
+	registered := scopeOut.GetRegistered()
+	conflicts := scopeOut.GetConflicts()
+	stored := &contracts.AuthzDeclarationSet{OwnerPlugin: set.GetOwnerPlugin(), OwnerModule: set.GetOwnerModule(), Scopes: scopeOut.GetScopes()}
 	for _, resource := range set.GetResources() {
 		key := resourceKey(resource.GetContext(), resource.GetName())
-		if _, exists := m.resources[key]; !exists {
+		existing, exists := m.resources[key]
+		if !m.claimLocked("resource", key, existing, exists, resource, &conflicts) {
+			continue
+		}
+		if !exists {
 			registered++
 		}
 		m.resources[key] = cloneResourceDeclaration(resource)
+		stored.Resources = append(stored.Resources, resource)
 	}
 	for _, action := range set.GetActions() {
 		key := actionKey(action.GetContext(), action.GetResource(), action.GetName())
-		if _, exists := m.actions[key]; !exists {
+		existing, exists := m.actions[key]
+		if !m.claimLocked("action", key, existing, exists, action, &conflicts) {
+			continue
+		}
+		if !exists {
 			registered++
 		}
 		m.actions[key] = cloneActionDeclaration(action)
+		stored.Actions = append(stored.Actions, action)
 	}
 	for _, attribute := range set.GetAttributes() {
 		key := attributeKey(attribute.GetContext(), attribute.GetName())
-		if _, exists := m.attributes[key]; !exists {
+		existing, exists := m.attributes[key]
+		if !m.claimLocked("attribute", key, existing, exists, attribute, &conflicts) {
+			continue
+		}
+		if !exists {
 			registered++
 		}
 		m.attributes[key] = cloneAttributeDeclaration(attribute)
+		stored.Attributes = append(stored.Attributes, attribute)
 	}
 	for _, relation := range set.GetRelations() {
 		key := relationKey(relation.GetContext(), relation.GetObjectType(), relation.GetName())
-		if _, exists := m.relations[key]; !exists {
+		existing, exists := m.relations[key]
+		if !m.claimLocked("relation", key, existing, exists, relation, &conflicts) {
+			continue
+		}
+		if !exists {
 			registered++
 		}
 		m.relations[key] = cloneRelationDeclaration(relation)
+		stored.Relations = append(stored.Relations, relation)
 	}
 	for _, action := range set.GetUiActions() {
 		key := uiActionKey(action.GetContext(), action.GetId())
-		if _, exists := m.uiActions[key]; !exists {
+		existing, exists := m.uiActions[key]
+		if !m.claimLocked("ui_action", key, existing, exists, action, &conflicts) {
+			continue
+		}
+		if !exists {
 			registered++
 		}
 		m.uiActions[key] = cloneUIActionDeclaration(action)
+		stored.UiActions = append(stored.UiActions, action)
 	}
-	return &contracts.RegisterDeclarationsOutput{Registered: registered, Declarations: set}, nil
+	stored.Scopes = mergeScopes(stored.GetScopes(), m.inheritResourceLifecycleLocked())
+	return &contracts.RegisterDeclarationsOutput{Registered: registered, Declarations: stored, Conflicts: conflicts}, nil
 }
 
 func (m *scopeCatalogModule) listDeclarations(input *contracts.ListDeclarationsInput) *contracts.AuthzDeclarationSet {
@@ -230,9 +272,14 @@ func resolveProjectionInputsInputFromMap(values map[string]any) *contracts.Resol
 
 func registerDeclarationsOutputToMap(out *contracts.RegisterDeclarationsOutput) map[string]any {
 	if out == nil {
-		return map[string]any{"registered": 0, "declarations": declarationSetToMap(nil)}
+		return map[string]any{"registered": 0, "declarations": declarationSetToMap(nil), "conflicts": []map[string]any{}, "subscriber_errors": []string{}}
+	}
+	return map[string]any{
+		"registered":        int(out.GetRegistered()),
+		"declarations":      declarationSetToMap(out.GetDeclarations()),
+		"conflicts":         declarationConflictsToMaps(out.GetConflicts()),
+		"subscriber_errors": append([]string{}, out.GetSubscriberErrors()...),
 	}
-	return map[string]any{"registered": int(out.GetRegistered()), "declarations": declarationSetToMap(out.GetDeclarations())}
 }
 
 func declarationSetFromAny(value any, ownerPlugin, ownerModule string) *contracts.AuthzDeclarationSet {
@@ -401,6 +448,9 @@ func resourceDeclarationsFromAny(value any, ownerPlugin, ownerModule string) []*
 			OwnerModule:    defaultString(stringValue(v["owner_module"]), ownerModule),
 			Category:       stringValue(v["category"]),
 			LookupSourceId: stringValue(v["lookup_source_id"]),
+			Deprecated:     boolValue(v["deprecated"]),
+			ReplacedBy:     stringValue(v["replaced_by"]),
+			Version:        stringValue(v["version"]),
 		})
 	}
 	return out
@@ -558,15 +608,16 @@ func uiActionDeclarationsFromAny(value any, ownerPlugin, ownerModule string) []*
 	for _, item := range items {
 		v := mapValue(item)
 		out = append(out, &contracts.UIActionDeclaration{
-			Id:             stringValue(v["id"]),
-			Context:        stringValue(v["context"]),
-			Label:          stringValue(v["label"]),
-			Route:          stringValue(v["route"]),
-			RequiredScopes: stringSliceValue(v["required_scopes"]),
-			Description:    stringValue(v["description"]),
-			OwnerPlugin:    defaultString(stringValue(v["owner_plugin"]), ownerPlugin),
-			OwnerModule:    defaultString(stringValue(v["owner_module"]), ownerModule),
-			Category:       stringValue(v["category"]),
+			Id:                   stringValue(v["id"]),
+			Context:              stringValue(v["context"]),
+			Label:                stringValue(v["label"]),
+			Route:                stringValue(v["route"]),
+			RequiredScopes:       stringSliceValue(v["required_scopes"]),
+			RequiredCapabilities: contractCapabilityRequirements(capabilityRequirementsFromAny(v["required_capabilities"])),
+			Description:          stringValue(v["description"]),
+			OwnerPlugin:          defaultString(stringValue(v["owner_plugin"]), ownerPlugin),
+			OwnerModule:          defaultString(stringValue(v["owner_module"]), ownerModule),
+			Category:             stringValue(v["category"]),
 		})
 	}
 	return out
@@ -588,7 +639,11 @@ func attributeValuesFromAny(value any) []*contracts.AttributeValue {
 func resourceDeclarationsToMaps(items []*contracts.ResourceDeclaration) []map[string]any {
 	out := make([]map[string]any, 0, len(items))
 	for _, item := range items {
-		out = append(out, compactMap(map[string]any{"name": item.GetName(), "context": item.GetContext(), "display_name": item.GetDisplayName(), "description": item.GetDescription(), "owner_plugin": item.GetOwnerPlugin(), "owner_module": item.GetOwnerModule(), "category": item.GetCategory(), "lookup_source_id": item.GetLookupSourceId()}))
+		values := compactMap(map[string]any{"name": item.GetName(), "context": item.GetContext(), "display_name": item.GetDisplayName(), "description": item.GetDescription(), "owner_plugin": item.GetOwnerPlugin(), "owner_module": item.GetOwnerModule(), "category": item.GetCategory(), "lookup_source_id": item.GetLookupSourceId(), "replaced_by": item.GetReplacedBy(), "version": item.GetVersion()})
+		if item.GetDeprecated() {
+			values["deprecated"] = true
+		}
+		out = append(out, values)
 	}
 	return out
 }
@@ -620,7 +675,7 @@ func relationDeclarationsToMaps(items []*contracts.RelationDeclaration) []map[st
 func uiActionDeclarationsToMaps(items []*contracts.UIActionDeclaration) []map[string]any {
 	out := make([]map[string]any, 0, len(items))
 	for _, item := range items {
-		out = append(out, compactMap(map[string]any{"id": item.GetId(), "context": item.GetContext(), "label": item.GetLabel(), "route": item.GetRoute(), "required_scopes": stringsToAny(item.GetRequiredScopes()), "description": item.GetDescription(), "owner_plugin": item.GetOwnerPlugin(), "owner_module": item.GetOwnerModule(), "category": item.GetCategory()}))
+		out = append(out, compactMap(map[string]any{"id": item.GetId(), "context": item.GetContext(), "label": item.GetLabel(), "route": item.GetRoute(), "required_scopes": stringsToAny(item.GetRequiredScopes()), "required_capabilities": capabilityRequirementsToAny(item.GetRequiredCapabilities()), "description": item.GetDescription(), "owner_plugin": item.GetOwnerPlugin(), "owner_module": item.GetOwnerModule(), "category": item.GetCategory()}))
 	}
 	return out
 }
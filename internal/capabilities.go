@@ -249,18 +249,24 @@ func (s *authzCapabilitiesStep) Execute(
 			return nil, fmt.Errorf("step.authz_capabilities %q: permit module %q not found", s.name, s.moduleName)
 		}
 		provider = &PermitModule{name: s.moduleName}
-	case "keto":
+	case "keto", "composite", "opa", "cedar":
 		reg, ok := s.registry.(authzProviderRegistry)
 		if !ok {
-			return nil, fmt.Errorf("step.authz_capabilities %q: registry cannot look up keto module %q", s.name, s.moduleName)
+			return nil, fmt.Errorf("step.authz_capabilities %q: registry cannot look up %s module %q", s.name, s.provider, s.moduleName)
 		}
 		registered, found := reg.GetAuthzProvider(s.moduleName)
 		if !found {
-			return nil, fmt.Errorf("step.authz_capabilities %q: keto module %q not found", s.name, s.moduleName)
+			return nil, fmt.Errorf("step.authz_capabilities %q: %s module %q not found", s.name, s.provider, s.moduleName)
 		}
 		provider = registered
 	default:
-		return nil, fmt.Errorf("step.authz_capabilities %q: unknown provider %q (expected \"casbin\", \"permit\", or \"keto\")", s.name, s.provider)
+		if reg, ok := s.registry.(customProviderRegistry); ok {
+			if registered, found := reg.GetCustomProvider(s.provider, s.moduleName); found {
+				provider = registered
+				break
+			}
+		}
+		return nil, fmt.Errorf("step.authz_capabilities %q: unknown provider %q (expected \"casbin\", \"permit\", \"keto\", \"composite\", \"opa\", \"cedar\", or a registered custom provider)", s.name, s.provider)
 	}
 
 	caps := provider.Capabilities()
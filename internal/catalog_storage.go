@@ -0,0 +1,442 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/GoCodeAlone/workflow-plugin-authz/internal/contracts"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"gorm.io/gorm"
+)
+
+const defaultCatalogTable = "authz_scope_catalog"
+
+// catalogStore persists the declarations of an authz.scope_catalog so they
+// survive restarts and can be shared by replicas. Put and Delete take the
+// declarations that changed, not the whole catalog.
+type catalogStore interface {
+	Load(context.Context) (*contracts.AuthzDeclarationSet, error)
+	Put(context.Context, *contracts.AuthzDeclarationSet) error
+	Delete(context.Context, *contracts.AuthzDeclarationSet) error
+	Close() error
+}
+
+// openCatalogStore returns the store configured by the catalog's adapter
+// section, or nil for the default in-memory catalog.
+func openCatalogStore(cfg adapterConfig) (catalogStore, error) {
+	switch strings.ToLower(cfg.Type) {
+	case "", "memory":
+		return nil, nil
+	case "file":
+		if cfg.Path == "" {
+			return nil, fmt.Errorf("adapter.path is required for file adapter")
+		}
+		return &fileCatalogStore{path: cfg.Path}, nil
+	case "gorm":
+		if cfg.DSN == "" {
+			return nil, fmt.Errorf("adapter.dsn is required for gorm adapter")
+		}
+		if cfg.FilterField != "" || cfg.FilterValue != "" {
+			return nil, fmt.Errorf("adapter.filter_field is not supported by the scope catalog; use table_name and tenant")
+		}
+		table, err := resolveTableNameTemplate(cfg.TableName, cfg)
+		if err != nil {
+			return nil, err
+		}
+		db, err := openGORM(cfg.Driver, cfg.DSN)
+		if err != nil {
+			return nil, err
+		}
+		return newGORMCatalogStore(db, table)
+	default:
+		return nil, fmt.Errorf("unknown adapter type %q", cfg.Type)
+	}
+}
+
+// catalogRecord is one stored declaration. Kind and Key match the names used
+// for ownership conflicts, so a record is replaced exactly when the in-memory
+// declaration would be.
+type catalogRecord struct {
+	Kind        string
+	Key         string
+	Declaration proto.Message
+}
+
+func declarationRecords(set *contracts.AuthzDeclarationSet) []catalogRecord {
+	var records []catalogRecord
+	for _, scope := range set.GetScopes() {
+		records = append(records, catalogRecord{"scope", scope.GetName(), scope})
+	}
+	for _, resource := range set.GetResources() {
+		records = append(records, catalogRecord{"resource", resourceKey(resource.GetContext(), resource.GetName()), resource})
+	}
+	for _, action := range set.GetActions() {
+		records = append(records, catalogRecord{"action", actionKey(action.GetContext(), action.GetResource(), action.GetName()), action})
+	}
+	for _, attribute := range set.GetAttributes() {
+		records = append(records, catalogRecord{"attribute", attributeKey(attribute.GetContext(), attribute.GetName()), attribute})
+	}
+	for _, relation := range set.GetRelations() {
+		records = append(records, catalogRecord{"relation", relationKey(relation.GetContext(), relation.GetObjectType(), relation.GetName()), relation})
+	}
+	for _, action := range set.GetUiActions() {
+		records = append(records, catalogRecord{"ui_action", uiActionKey(action.GetContext(), action.GetId()), action})
+	}
+	return records
+}
+
+func newDeclarationMessage(kind string) (proto.Message, error) {
+	switch kind {
+	case "scope":
+		return &contracts.ScopeDeclaration{}, nil
+	case "resource":
+		return &contracts.ResourceDeclaration{}, nil
+	case "action":
+		return &contracts.ActionDeclaration{}, nil
+	case "attribute":
+		return &contracts.AttributeDeclaration{}, nil
+	case "relation":
+		return &contracts.RelationDeclaration{}, nil
+	case "ui_action":
+		return &contracts.UIActionDeclaration{}, nil
+	default:
+		return nil, fmt.Errorf("unknown declaration kind %q", kind)
+	}
+}
+
+func addDeclarationRecord(set *contracts.AuthzDeclarationSet, declaration proto.Message) {
+	switch item := declaration.(type) {
+	case *contracts.ScopeDeclaration:
+		set.Scopes = append(set.Scopes, item)
+	case *contracts.ResourceDeclaration:
+		set.Resources = append(set.Resources, item)
+	case *contracts.ActionDeclaration:
+		set.Actions = append(set.Actions, item)
+	case *contracts.AttributeDeclaration:
+		set.Attributes = append(set.Attributes, item)
+	case *contracts.RelationDeclaration:
+		set.Relations = append(set.Relations, item)
+	case *contracts.UIActionDeclaration:
+		set.UiActions = append(set.UiActions, item)
+	}
+}
+
+// fileCatalogStore keeps the catalog in a single JSON file holding an
+// AuthzDeclarationSet. Every change rewrites the file through a temporary
+// file and a rename, so readers never see a partial write.
+type fileCatalogStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+func (s *fileCatalogStore) Load(_ context.Context) (*contracts.AuthzDeclarationSet, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.read()
+}
+
+func (s *fileCatalogStore) Put(_ context.Context, set *contracts.AuthzDeclarationSet) error {
+	return s.update(set, func(records map[string]catalogRecord, record catalogRecord) {
+		records[record.Kind+"\x00"+record.Key] = record
+	})
+}
+
+func (s *fileCatalogStore) Delete(_ context.Context, set *contracts.AuthzDeclarationSet) error {
+	return s.update(set, func(records map[string]catalogRecord, record catalogRecord) {
+		delete(records, record.Kind+"\x00"+record.Key)
+	})
+}
+
+func (s *fileCatalogStore) Close() error { return nil }
+
+func (s *fileCatalogStore) update(set *contracts.AuthzDeclarationSet, apply func(map[string]catalogRecord, catalogRecord)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	current, err := s.read()
+	if err != nil {
+		return err
+	}
+	records := map[string]catalogRecord{}
+	for _, record := range declarationRecords(current) {
+		records[record.Kind+"\x00"+record.Key] = record
+	}
+	for _, record := range declarationRecords(set) {
+		apply(records, record)
+	}
+	next := &contracts.AuthzDeclarationSet{}
+	for _, key := range sortedKeys(records) {
+		addDeclarationRecord(next, records[key].Declaration)
+	}
+	sortDeclarationSet(next)
+	return s.write(next)
+}
+
+func (s *fileCatalogStore) read() (*contracts.AuthzDeclarationSet, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &contracts.AuthzDeclarationSet{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	set := &contracts.AuthzDeclarationSet{}
+	if len(data) == 0 {
+		return set, nil
+	}
+	if err := protojson.Unmarshal(data, set); err != nil {
+		return nil, fmt.Errorf("read %s: %w", s.path, err)
+	}
+	return set, nil
+}
+
+func (s *fileCatalogStore) write(set *contracts.AuthzDeclarationSet) error {
+	data, err := protojson.MarshalOptions{Multiline: true, UseProtoNames: true}.Marshal(set)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+// catalogRow is the table schema of gormCatalogStore. Declaration holds the
+// protojson encoding of the declaration named by Kind.
+type catalogRow struct {
+	Kind        string    `gorm:"column:kind;primaryKey;size:32"`
+	Key         string    `gorm:"column:decl_key;primaryKey;size:512"`
+	Declaration string    `gorm:"column:declaration;type:text"`
+	UpdatedAt   time.Time `gorm:"column:updated_at"`
+}
+
+// gormCatalogStore keeps one row per declaration, so replicas sharing the
+// table only overwrite the declarations they register.
+type gormCatalogStore struct {
+	db    *gorm.DB
+	table string
+}
+
+func newGORMCatalogStore(db *gorm.DB, table string) (*gormCatalogStore, error) {
+	if table == "" {
+		table = defaultCatalogTable
+	}
+	if db.Dialector.Name() != "sqlite" || !db.Migrator().HasTable(table) {
+		if err := db.Table(table).AutoMigrate(&catalogRow{}); err != nil {
+			return nil, err
+		}
+	}
+	return &gormCatalogStore{db: db, table: table}, nil
+}
+
+func (s *gormCatalogStore) Load(ctx context.Context) (*contracts.AuthzDeclarationSet, error) {
+	var rows []catalogRow
+	if err := s.db.WithContext(ctx).Table(s.table).Order("kind ASC").Order("decl_key ASC").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	set := &contracts.AuthzDeclarationSet{}
+	for _, row := range rows {
+		declaration, err := newDeclarationMessage(row.Kind)
+		if err != nil {
+			return nil, err
+		}
+		if err := protojson.Unmarshal([]byte(row.Declaration), declaration); err != nil {
+			return nil, fmt.Errorf("decode %s %q: %w", row.Kind, row.Key, err)
+		}
+		addDeclarationRecord(set, declaration)
+	}
+	sortDeclarationSet(set)
+	return set, nil
+}
+
+func (s *gormCatalogStore) Put(ctx context.Context, set *contracts.AuthzDeclarationSet) error {
+	now := time.Now().UTC()
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, record := range declarationRecords(set) {
+			data, err := protojson.Marshal(record.Declaration)
+			if err != nil {
+				return err
+			}
+			if err := s.deleteRecord(tx, record); err != nil {
+				return err
+			}
+			row := catalogRow{Kind: record.Kind, Key: record.Key, Declaration: string(data), UpdatedAt: now}
+			if err := tx.Table(s.table).Create(&row).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *gormCatalogStore) Delete(ctx context.Context, set *contracts.AuthzDeclarationSet) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, record := range declarationRecords(set) {
+			if err := s.deleteRecord(tx, record); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *gormCatalogStore) deleteRecord(tx *gorm.DB, record catalogRecord) error {
+	return tx.Table(s.table).
+		Where(quoteIdent(s.db, "kind")+" = ? AND "+quoteIdent(s.db, "decl_key")+" = ?", record.Kind, record.Key).
+		Delete(&catalogRow{}).Error
+}
+
+func (s *gormCatalogStore) Close() error {
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
+
+// persist writes set to the configured store, if any.
+func (m *scopeCatalogModule) persist(ctx context.Context, set *contracts.AuthzDeclarationSet) error {
+	if m.store == nil {
+		return nil
+	}
+	return m.store.Put(ctx, set)
+}
+
+// openStore merges the stored declarations over the ones from config, writes
+// the result back so config declarations are shared too, and replays it to
+// providers that subscribed before the catalog initialized.
+func (m *scopeCatalogModule) openStore(ctx context.Context) error {
+	stored, err := m.store.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("load declarations: %w", err)
+	}
+	m.mu.Lock()
+	m.putLocked(stored)
+	m.mu.Unlock()
+	all := m.listDeclarations(nil)
+	if err := m.store.Put(ctx, all); err != nil {
+		return fmt.Errorf("store declarations: %w", err)
+	}
+	return errors.Join(m.pushToSubscribers(ctx, all)...)
+}
+
+// reload replaces the catalog with the store's contents. Declarations another
+// replica registered or changed are pushed to subscribers, and scopes it
+// unregistered are removed from them. Both are published as change events.
+func (m *scopeCatalogModule) reload(ctx context.Context) error {
+	stored, err := m.store.Load(ctx)
+	if err != nil {
+		return err
+	}
+	before := m.listDeclarations(nil)
+	m.mu.Lock()
+	m.scopes = map[string]*contracts.ScopeDeclaration{}
+	m.resources = map[string]*contracts.ResourceDeclaration{}
+	m.actions = map[string]*contracts.ActionDeclaration{}
+	m.attributes = map[string]*contracts.AttributeDeclaration{}
+	m.relations = map[string]*contracts.RelationDeclaration{}
+	m.uiActions = map[string]*contracts.UIActionDeclaration{}
+	m.putLocked(stored)
+	m.mu.Unlock()
+	after := m.listDeclarations(nil)
+
+	removed := diffDeclarationSets(after, before, true)
+	if declarationCount(removed) > 0 {
+		m.mu.Lock()
+		m.undeclareInSubscribersLocked(removed)
+		m.mu.Unlock()
+		publishChange(m.name, "scope_catalog", ChangeDeclarationsUnregistered, map[string]any{
+			"removed":      declarationCount(removed),
+			"declarations": declarationSetToMap(removed),
+		})
+	}
+	changed := diffDeclarationSets(before, after, false)
+	if declarationCount(changed) == 0 {
+		return nil
+	}
+	publishChange(m.name, "scope_catalog", ChangeDeclarationsRegistered, map[string]any{
+		"registered":   declarationCount(changed),
+		"declarations": declarationSetToMap(changed),
+	})
+	return errors.Join(m.pushToSubscribers(ctx, changed)...)
+}
+
+// diffDeclarationSets returns the declarations of to that are missing from
+// from, and unless missingOnly, those that differ from their counterpart in
+// from.
+func diffDeclarationSets(from, to *contracts.AuthzDeclarationSet, missingOnly bool) *contracts.AuthzDeclarationSet {
+	return &contracts.AuthzDeclarationSet{
+		Scopes: diffDeclarations(from.GetScopes(), to.GetScopes(), missingOnly, func(d *contracts.ScopeDeclaration) string {
+			return d.GetName()
+		}),
+		Resources: diffDeclarations(from.GetResources(), to.GetResources(), missingOnly, func(d *contracts.ResourceDeclaration) string {
+			return resourceKey(d.GetContext(), d.GetName())
+		}),
+		Actions: diffDeclarations(from.GetActions(), to.GetActions(), missingOnly, func(d *contracts.ActionDeclaration) string {
+			return actionKey(d.GetContext(), d.GetResource(), d.GetName())
+		}),
+		Attributes: diffDeclarations(from.GetAttributes(), to.GetAttributes(), missingOnly, func(d *contracts.AttributeDeclaration) string {
+			return attributeKey(d.GetContext(), d.GetName())
+		}),
+		Relations: diffDeclarations(from.GetRelations(), to.GetRelations(), missingOnly, func(d *contracts.RelationDeclaration) string {
+			return relationKey(d.GetContext(), d.GetObjectType(), d.GetName())
+		}),
+		UiActions: diffDeclarations(from.GetUiActions(), to.GetUiActions(), missingOnly, func(d *contracts.UIActionDeclaration) string {
+			return uiActionKey(d.GetContext(), d.GetId())
+		}),
+	}
+}
+
+func diffDeclarations[T proto.Message](from, to []T, missingOnly bool, key func(T) string) []T {
+	existing := make(map[string]T, len(from))
+	for _, declaration := range from {
+		existing[key(declaration)] = declaration
+	}
+	var out []T
+	for _, declaration := range to {
+		previous, ok := existing[key(declaration)]
+		if !ok || (!missingOnly && !proto.Equal(previous, declaration)) {
+			out = append(out, declaration)
+		}
+	}
+	return out
+}
+
+// putLocked stores set as is, without ownership checks; it is used for
+// declarations that were already accepted, by this or another replica.
+func (m *scopeCatalogModule) putLocked(set *contracts.AuthzDeclarationSet) {
+	for _, scope := range set.GetScopes() {
+		m.upsertLocked(scope)
+	}
+	for _, resource := range set.GetResources() {
+		m.resources[resourceKey(resource.GetContext(), resource.GetName())] = cloneResourceDeclaration(resource)
+	}
+	for _, action := range set.GetActions() {
+		m.actions[actionKey(action.GetContext(), action.GetResource(), action.GetName())] = cloneActionDeclaration(action)
+	}
+	for _, attribute := range set.GetAttributes() {
+		m.attributes[attributeKey(attribute.GetContext(), attribute.GetName())] = cloneAttributeDeclaration(attribute)
+	}
+	for _, relation := range set.GetRelations() {
+		m.relations[relationKey(relation.GetContext(), relation.GetObjectType(), relation.GetName())] = cloneRelationDeclaration(relation)
+	}
+	for _, action := range set.GetUiActions() {
+		m.uiActions[uiActionKey(action.GetContext(), action.GetId())] = cloneUIActionDeclaration(action)
+	}
+}
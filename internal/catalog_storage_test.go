@@ -0,0 +1,130 @@
+package internal
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func initStoredCatalog(t *testing.T, name string, adapter map[string]any) *scopeCatalogModule {
+	t.Helper()
+	catalog := newScopeCatalogModule(name, map[string]any{
+		"adapter": adapter,
+		"scopes": []any{
+			map[string]any{"name": "docs:document:read", "context": "docs", "resource": "document", "actions": []any{"read"}},
+		},
+	})
+	if err := catalog.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	t.Cleanup(func() { _ = catalog.Stop(context.Background()) })
+	return catalog
+}
+
+func TestScopeCatalogStorageSurvivesRestart(t *testing.T) {
+	dir := t.TempDir()
+	for name, adapter := range map[string]map[string]any{
+		"file": {"type": "file", "path": filepath.Join(dir, "catalog.json")},
+		"gorm": {"type": "gorm", "driver": "sqlite3", "dsn": "file:" + filepath.Join(dir, "catalog.db"), "table_name": "catalog_{{.Tenant}}", "tenant": "acme"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			first := initStoredCatalog(t, "catalog-"+name, adapter)
+			if _, err := first.InvokeMethod("RegisterDeclarations", ordersDeclarations("plugin-orders")); err != nil {
+				t.Fatalf("RegisterDeclarations: %v", err)
+			}
+			if err := first.Stop(context.Background()); err != nil {
+				t.Fatalf("Stop: %v", err)
+			}
+
+			second := initStoredCatalog(t, "catalog-"+name, adapter)
+			set := second.listDeclarations(nil)
+			if len(set.GetScopes()) != 2 || len(set.GetResources()) != 1 || set.GetResources()[0].GetOwnerPlugin() != "plugin-orders" {
+				t.Fatalf("declarations after restart = %v, want config and registered declarations", set)
+			}
+			if _, err := second.InvokeMethod("UnregisterDeclarations", map[string]any{"owner_plugin": "plugin-orders"}); err != nil {
+				t.Fatalf("UnregisterDeclarations: %v", err)
+			}
+			if err := second.Stop(context.Background()); err != nil {
+				t.Fatalf("Stop: %v", err)
+			}
+
+			third := initStoredCatalog(t, "catalog-"+name, adapter)
+			if set := third.listDeclarations(nil); len(set.GetScopes()) != 1 || len(set.GetResources()) != 0 {
+				t.Fatalf("declarations after unregister = %v, want only the config scope", set)
+			}
+		})
+	}
+}
+
+func TestScopeCatalogReloadSharesReplicaRegistrations(t *testing.T) {
+	adapter := map[string]any{"type": "gorm", "driver": "sqlite3", "dsn": "file:" + filepath.Join(t.TempDir(), "catalog.db")}
+	replicaA := initStoredCatalog(t, "replica-a", adapter)
+	replicaB := initStoredCatalog(t, "replica-b", adapter)
+	if _, err := replicaA.InvokeMethod("RegisterDeclarations", ordersDeclarations("plugin-orders")); err != nil {
+		t.Fatalf("RegisterDeclarations: %v", err)
+	}
+	if err := replicaB.reload(context.Background()); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if scopes := replicaB.listScopes(nil); len(scopes) != 2 {
+		t.Fatalf("replica scopes = %v, want the scope registered on the other replica", scopes)
+	}
+}
+
+func TestScopeCatalogReloadRemovesReplicaUnregistrations(t *testing.T) {
+	ctx := context.Background()
+	adapter := map[string]any{"type": "gorm", "driver": "sqlite3", "dsn": "file:" + filepath.Join(t.TempDir(), "catalog.db")}
+	replicaA := initStoredCatalog(t, "unregister-replica-a", adapter)
+	replicaB := initStoredCatalog(t, "unregister-replica-b", adapter)
+	provider := NewMemoryProvider("unregister-memory")
+	if err := replicaB.subscribe(ctx, provider); err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	if _, err := replicaA.InvokeMethod("RegisterDeclarations", ordersDeclarations("plugin-orders")); err != nil {
+		t.Fatalf("RegisterDeclarations: %v", err)
+	}
+	if err := replicaB.reload(ctx); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if scopes := provider.store.declaredScopes(); len(scopes) != 2 {
+		t.Fatalf("provider scopes = %v, want the registered scope pushed", scopes)
+	}
+
+	_, events, cancel, _ := SubscribeChangeEvents(0)
+	defer cancel()
+	if _, err := replicaA.InvokeMethod("UnregisterDeclarations", map[string]any{"owner_plugin": "plugin-orders"}); err != nil {
+		t.Fatalf("UnregisterDeclarations: %v", err)
+	}
+	if err := replicaB.reload(ctx); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if scopes := replicaB.listScopes(nil); len(scopes) != 1 {
+		t.Fatalf("replica scopes = %v, want only the config scope", scopes)
+	}
+	if scopes := provider.store.declaredScopes(); len(scopes) != 1 || scopes[0].GetName() != "docs:document:read" {
+		t.Fatalf("provider scopes = %v, want the unregistered scope removed", scopes)
+	}
+	for {
+		select {
+		case event := <-events:
+			if event.Source == "unregister-replica-b" && event.Type == ChangeDeclarationsUnregistered {
+				return
+			}
+		case <-time.After(time.Second):
+			t.Fatal("missing declarations.unregistered event from the reloading replica")
+		}
+	}
+}
+
+func TestScopeCatalogRejectsUnknownAdapter(t *testing.T) {
+	for _, adapter := range []map[string]any{
+		{"type": "redis"},
+		{"type": "file"},
+		{"type": "gorm", "driver": "sqlite3", "dsn": "file::memory:", "filter_field": "v0", "filter_value": "acme"},
+	} {
+		if err := newScopeCatalogModule("bad", map[string]any{"adapter": adapter}).Init(); err == nil {
+			t.Fatalf("expected Init error for adapter %v", adapter)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/GoCodeAlone/workflow-plugin-authz/internal/contracts"
+)
+
+// relationNamespaceDeclarer is implemented by ReBAC providers that track the
+// relation namespaces declared in a scope catalog.
+type relationNamespaceDeclarer interface {
+	DeclareRelations(context.Context, []*contracts.RelationDeclaration) error
+}
+
+type attributeDeclarer interface {
+	DeclareAttributes(context.Context, []*contracts.AttributeDeclaration) error
+}
+
+// subscribeToScopeCatalog connects provider to the catalog named by a
+// provider module's `catalog` config and replays the catalog's declarations
+// into it. An empty catalogName is a no-op.
+func subscribeToScopeCatalog(registry moduleRegistry, catalogName string, provider ScopeRoleProvider) error {
+	if catalogName == "" {
+		return nil
+	}
+	catalog, err := lookupScopeCatalog(registry, catalogName)
+	if err != nil {
+		return err
+	}
+	return catalog.subscribe(context.Background(), provider)
+}
+
+// subscribe registers provider to receive declarations registered from now on
+// and pushes everything the catalog already holds.
+func (m *scopeCatalogModule) subscribe(ctx context.Context, provider ScopeRoleProvider) error {
+	m.mu.Lock()
+	if m.subscribers == nil {
+		m.subscribers = map[string]ScopeRoleProvider{}
+	}
+	m.subscribers[provider.Name()] = provider
+	m.mu.Unlock()
+	if err := pushDeclarations(ctx, provider, m.listDeclarations(nil)); err != nil {
+		return fmt.Errorf("replay scope catalog %q: %w", m.name, err)
+	}
+	return nil
+}
+
+// pushToSubscribers declares set in every subscribed provider. Every provider
+// is attempted; the failures are returned one per provider.
+func (m *scopeCatalogModule) pushToSubscribers(ctx context.Context, set *contracts.AuthzDeclarationSet) []error {
+	m.mu.RLock()
+	names := make([]string, 0, len(m.subscribers))
+	for name := range m.subscribers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	providers := make([]ScopeRoleProvider, 0, len(names))
+	for _, name := range names {
+		providers = append(providers, m.subscribers[name])
+	}
+	m.mu.RUnlock()
+
+	var errs []error
+	for _, provider := range providers {
+		if err := pushDeclarations(ctx, provider, set); err != nil {
+			errs = append(errs, fmt.Errorf("provider %q: %w", provider.Name(), err))
+		}
+	}
+	return errs
+}
+
+// undeclareInSubscribersLocked removes the scopes of set from the scopes
+// declared in every subscribed provider. Role grants that still name them are
+// left alone.
+func (m *scopeCatalogModule) undeclareInSubscribersLocked(set *contracts.AuthzDeclarationSet) {
+	if len(set.GetScopes()) == 0 {
+		return
+	}
+	for _, name := range sortedKeys(m.subscribers) {
+		if source, ok := m.subscribers[name].(scopeRoleStoreProvider); ok && source.scopeRoleStore() != nil {
+			source.scopeRoleStore().undeclareScopes(set.GetScopes())
+		}
+	}
+}
+
+// pushDeclarations declares the scopes, attributes and relations of set in
+// provider. Attributes and relations are skipped when the provider's model
+// does not support ABAC or ReBAC.
+func pushDeclarations(ctx context.Context, provider ScopeRoleProvider, set *contracts.AuthzDeclarationSet) error {
+	if len(set.GetScopes()) > 0 {
+		if err := provider.DeclareScopes(ctx, set.GetScopes()); err != nil {
+			return err
+		}
+	}
+	if declarer, ok := provider.(attributeDeclarer); ok && len(set.GetAttributes()) > 0 {
+		if err := declarer.DeclareAttributes(ctx, set.GetAttributes()); err != nil && !errors.Is(err, errUnsupportedABAC) {
+			return err
+		}
+	}
+	if declarer, ok := provider.(relationNamespaceDeclarer); ok && len(set.GetRelations()) > 0 {
+		if err := declarer.DeclareRelations(ctx, set.GetRelations()); err != nil && !errors.Is(err, errUnsupportedReBAC) {
+			return err
+		}
+	}
+	return nil
+}
+
+// relationNamespaces records the relations declared for each object type.
+type relationNamespaces struct {
+	mu        sync.RWMutex
+	relations map[string]*contracts.RelationDeclaration
+}
+
+func newRelationNamespaces() *relationNamespaces {
+	return &relationNamespaces{relations: map[string]*contracts.RelationDeclaration{}}
+}
+
+func (n *relationNamespaces) declare(relations []*contracts.RelationDeclaration) error {
+	for _, relation := range relations {
+		if strings.TrimSpace(relation.GetObjectType()) == "" || strings.TrimSpace(relation.GetName()) == "" {
+			return fmt.Errorf("relation declarations require object_type and name")
+		}
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for _, relation := range relations {
+		n.relations[relationKey(relation.GetContext(), relation.GetObjectType(), relation.GetName())] = cloneRelationDeclaration(relation)
+	}
+	return nil
+}
+
+// list returns copies of the declared relations ordered by object type and
+// name.
+func (n *relationNamespaces) list() []*contracts.RelationDeclaration {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	out := make([]*contracts.RelationDeclaration, 0, len(n.relations))
+	for _, relation := range n.relations {
+		out = append(out, cloneRelationDeclaration(relation))
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].GetObjectType() != out[j].GetObjectType() {
+			return out[i].GetObjectType() < out[j].GetObjectType()
+		}
+		if out[i].GetName() != out[j].GetName() {
+			return out[i].GetName() < out[j].GetName()
+		}
+		return out[i].GetContext() < out[j].GetContext()
+	})
+	return out
+}
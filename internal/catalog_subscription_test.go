@@ -0,0 +1,157 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/GoCodeAlone/workflow-plugin-authz/internal/contracts"
+)
+
+// rejectingProvider is a subscriber whose scope declarations always fail.
+type rejectingProvider struct {
+	ScopeRoleProvider
+}
+
+func (rejectingProvider) Name() string { return "rejecting" }
+
+func (rejectingProvider) DeclareScopes(context.Context, []*contracts.ScopeDeclaration) error {
+	return errors.New("provider offline")
+}
+
+func subscriptionTestCatalog(t *testing.T, name string) *scopeCatalogModule {
+	t.Helper()
+	catalog := newScopeCatalogModule(name, map[string]any{
+		"declarations": map[string]any{
+			"scopes": []any{
+				map[string]any{"name": "docs:document:read", "context": "docs", "resource": "document", "actions": []any{"read"}},
+			},
+			"attributes": []any{
+				map[string]any{"name": "department", "context": "docs", "target": "subject", "data_type": "string"},
+			},
+			"relations": []any{
+				map[string]any{"name": "owner", "context": "docs", "object_type": "document", "subject_type": "user"},
+			},
+		},
+	})
+	registerScopeCatalog(catalog)
+	return catalog
+}
+
+func TestProviderReplaysAndFollowsScopeCatalog(t *testing.T) {
+	ctx := context.Background()
+	catalog := subscriptionTestCatalog(t, "subscription-catalog")
+	mod, err := newCasbinModule("catalog-casbin", map[string]any{
+		"catalog": "subscription-catalog",
+		"model": `
+[request_definition]
+r = sub, obj, act
+
+[policy_definition]
+p = sub, obj, act
+
+[role_definition]
+g = _, _
+g2 = _, _, _
+
+[policy_effect]
+e = some(where (p.eft == allow))
+
+[matchers]
+m = g(r.sub, p.sub) && r.obj == p.obj && r.act == p.act
+`,
+	})
+	if err != nil {
+		t.Fatalf("newCasbinModule: %v", err)
+	}
+	// The model has no ABAC, so the catalog's attribute is skipped rather than
+	// failing Init.
+	if err := mod.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if err := mod.UpsertRole(ctx, RoleScopeGrant{Role: "reader", Context: "docs", Scopes: []string{"docs:document:read"}}); err != nil {
+		t.Fatalf("UpsertRole with replayed scope: %v", err)
+	}
+	if relations := mod.namespaces.list(); len(relations) != 1 || relations[0].GetObjectType() != "document" {
+		t.Fatalf("relation namespaces = %v, want document#owner", relations)
+	}
+
+	if _, err := catalog.InvokeMethod("RegisterScopes", map[string]any{
+		"owner_plugin": "plugin-docs",
+		"scopes": []any{
+			map[string]any{"name": "docs:document:write", "context": "docs", "resource": "document", "actions": []any{"write"}},
+		},
+	}); err != nil {
+		t.Fatalf("RegisterScopes: %v", err)
+	}
+	if err := mod.UpsertRole(ctx, RoleScopeGrant{Role: "writer", Context: "docs", Scopes: []string{"docs:document:write"}}); err != nil {
+		t.Fatalf("UpsertRole with pushed scope: %v", err)
+	}
+}
+
+func TestKetoModuleFollowsScopeCatalog(t *testing.T) {
+	catalog := subscriptionTestCatalog(t, "keto-catalog")
+	mod, err := newKetoModule("catalog-keto", map[string]any{"catalog": "keto-catalog"})
+	if err != nil {
+		t.Fatalf("newKetoModule: %v", err)
+	}
+	if err := mod.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if _, err := catalog.InvokeMethod("RegisterDeclarations", map[string]any{
+		"declarations": map[string]any{
+			"relations": []any{
+				map[string]any{"name": "viewer", "context": "docs", "object_type": "document", "subject_type": "user"},
+			},
+		},
+	}); err != nil {
+		t.Fatalf("RegisterDeclarations: %v", err)
+	}
+	relations := mod.namespaces.list()
+	if len(relations) != 2 || relations[0].GetName() != "owner" || relations[1].GetName() != "viewer" {
+		t.Fatalf("relation namespaces = %v, want owner and viewer", relations)
+	}
+	if scopes := mod.scopeRoleStore().declaredScopes(); len(scopes) != 1 {
+		t.Fatalf("declared scopes = %v, want the replayed scope", scopes)
+	}
+}
+
+func TestProviderInitFailsForUnknownCatalog(t *testing.T) {
+	mod, err := newKetoModule("orphan-keto", map[string]any{"catalog": "no-such-catalog"})
+	if err != nil {
+		t.Fatalf("newKetoModule: %v", err)
+	}
+	if err := mod.Init(); err == nil {
+		t.Fatal("expected Init error for an unknown catalog")
+	}
+}
+
+func TestRegisterReportsSubscriberFailures(t *testing.T) {
+	catalog := newScopeCatalogModule("failing-subscriber-catalog", nil)
+	catalog.subscribers = map[string]ScopeRoleProvider{"rejecting": rejectingProvider{}}
+
+	out, err := catalog.InvokeMethod("RegisterScopes", map[string]any{
+		"scopes": []any{map[string]any{"name": "docs:document:read", "context": "docs", "resource": "document", "actions": []any{"read"}}},
+	})
+	if err != nil {
+		t.Fatalf("RegisterScopes: %v", err)
+	}
+	if errs, _ := out["subscriber_errors"].([]string); len(errs) != 1 || out["registered"] != 1 {
+		t.Fatalf("RegisterScopes output = %v, want the scope registered and one subscriber error", out)
+	}
+
+	out, err = catalog.InvokeMethod("RegisterDeclarations", map[string]any{
+		"declarations": map[string]any{
+			"scopes": []any{map[string]any{"name": "docs:document:write", "context": "docs", "resource": "document", "actions": []any{"write"}}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("RegisterDeclarations: %v", err)
+	}
+	if errs, _ := out["subscriber_errors"].([]string); len(errs) != 1 || out["registered"] != 1 {
+		t.Fatalf("RegisterDeclarations output = %v, want the scope registered and one subscriber error", out)
+	}
+	if scopes := catalog.listScopes(nil); len(scopes) != 2 {
+		t.Fatalf("scopes = %v, want both registrations kept", scopes)
+	}
+}
@@ -0,0 +1,166 @@
+package internal
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ChangeEventType identifies the kind of authorization state mutation carried
+// by a ChangeEvent.
+type ChangeEventType string
+
+const (
+	ChangePolicyAdded              ChangeEventType = "policy.added"
+	ChangePolicyRemoved            ChangeEventType = "policy.removed"
+	ChangeRoleUpserted             ChangeEventType = "role.upserted"
+	ChangeRoleAssigned             ChangeEventType = "role.assigned"
+	ChangeRoleUnassigned           ChangeEventType = "role.unassigned"
+	ChangeRelationWritten          ChangeEventType = "relation.written"
+	ChangeRelationDeleted          ChangeEventType = "relation.deleted"
+	ChangeAttributePolicyUpserted  ChangeEventType = "abac_policy.upserted"
+	ChangeAttributePolicyRemoved   ChangeEventType = "abac_policy.removed"
+	ChangeDeclarationsRegistered   ChangeEventType = "declarations.registered"
+	ChangeDeclarationsUnregistered ChangeEventType = "declarations.unregistered"
+	// ChangeProviderDrift reports that a provider's local state had diverged
+	// from its backend when it was reconciled.
+	ChangeProviderDrift ChangeEventType = "provider.drift"
+	// ChangeDataUpdated reports a write to the data document of an authz.opa
+	// module.
+	ChangeDataUpdated ChangeEventType = "data.updated"
+)
+
+const (
+	defaultChangeFeedCapacity   = 1024
+	defaultChangeSubscriberSize = 64
+)
+
+// ChangeEvent describes one successful mutation of a provider or catalog.
+// IDs are assigned sequentially per process so subscribers can resume after
+// the last event they observed. Epoch identifies the process that assigned
+// them, since the sequence restarts at 1 on every boot.
+type ChangeEvent struct {
+	ID       uint64
+	Epoch    string
+	Type     ChangeEventType
+	Source   string
+	Provider string
+	Time     time.Time
+	Data     map[string]any
+}
+
+// changeFeed keeps a bounded ring of recent events for resume and fans new
+// events out to live subscribers. Subscribers that fall behind are dropped
+// instead of blocking publishers; they can reconnect with their last ID.
+type changeFeed struct {
+	mu          sync.Mutex
+	capacity    int
+	epoch       string
+	nextID      uint64
+	events      []ChangeEvent
+	subscribers map[uint64]chan ChangeEvent
+	nextSub     uint64
+}
+
+var globalChangeFeed = newChangeFeed(defaultChangeFeedCapacity)
+
+func newChangeFeed(capacity int) *changeFeed {
+	if capacity <= 0 {
+		capacity = defaultChangeFeedCapacity
+	}
+	return &changeFeed{
+		capacity:    capacity,
+		epoch:       strconv.FormatInt(time.Now().UnixNano(), 36),
+		subscribers: map[uint64]chan ChangeEvent{},
+	}
+}
+
+// EventID renders the event's resumable ID as "<epoch>-<sequence>".
+func (e ChangeEvent) EventID() string {
+	return e.Epoch + "-" + strconv.FormatUint(e.ID, 10)
+}
+
+// parseEventID returns the sequence of an ID rendered by EventID. current is
+// false when the ID was assigned by another process, such as before a restart
+// or by another replica; its sequence says nothing about this feed.
+func (f *changeFeed) parseEventID(id string) (sequence uint64, current bool, err error) {
+	epoch, rest, ok := strings.Cut(id, "-")
+	if !ok {
+		epoch, rest = "", id
+	}
+	sequence, err = strconv.ParseUint(rest, 10, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid event id %q", id)
+	}
+	return sequence, epoch == f.epoch, nil
+}
+
+func publishChange(source, provider string, eventType ChangeEventType, data map[string]any) {
+	globalChangeFeed.publish(source, provider, eventType, data)
+}
+
+func (f *changeFeed) publish(source, provider string, eventType ChangeEventType, data map[string]any) ChangeEvent {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.nextID++
+	event := ChangeEvent{ID: f.nextID, Epoch: f.epoch, Type: eventType, Source: source, Provider: provider, Time: time.Now().UTC(), Data: data}
+	if len(f.events) == f.capacity {
+		copy(f.events, f.events[1:])
+		f.events[len(f.events)-1] = event
+	} else {
+		f.events = append(f.events, event)
+	}
+	for id, ch := range f.subscribers {
+		select {
+		case ch <- event:
+		default:
+			close(ch)
+			delete(f.subscribers, id)
+		}
+	}
+	return event
+}
+
+// subscribe returns the buffered events newer than afterID together with a
+// channel of live events. complete is false when afterID is older than the
+// retained window, meaning the caller missed events and should resync.
+func (f *changeFeed) subscribe(afterID uint64, size int) (backlog []ChangeEvent, events <-chan ChangeEvent, cancel func(), complete bool) {
+	if size <= 0 {
+		size = defaultChangeSubscriberSize
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	complete = true
+	if afterID > 0 {
+		if len(f.events) > 0 && afterID+1 < f.events[0].ID {
+			complete = false
+		}
+		if afterID > f.nextID {
+			complete = false
+			afterID = 0
+		}
+		for _, event := range f.events {
+			if event.ID > afterID {
+				backlog = append(backlog, event)
+			}
+		}
+	}
+	ch := make(chan ChangeEvent, size)
+	f.nextSub++
+	id := f.nextSub
+	f.subscribers[id] = ch
+	var once sync.Once
+	cancel = func() {
+		once.Do(func() {
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			if current, ok := f.subscribers[id]; ok {
+				delete(f.subscribers, id)
+				close(current)
+			}
+		})
+	}
+	return backlog, ch, cancel, complete
+}
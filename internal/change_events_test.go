@@ -0,0 +1,124 @@
+package internal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/GoCodeAlone/workflow-plugin-authz/internal/contracts"
+)
+
+func TestChangeFeedResumesAfterEventID(t *testing.T) {
+	feed := newChangeFeed(4)
+	first := feed.publish("authz", "casbin", ChangePolicyAdded, nil)
+	feed.publish("authz", "casbin", ChangePolicyRemoved, nil)
+
+	backlog, events, cancel, complete := feed.subscribe(first.ID, 8)
+	defer cancel()
+	if !complete {
+		t.Fatal("resume inside retained window should be complete")
+	}
+	if len(backlog) != 1 || backlog[0].Type != ChangePolicyRemoved {
+		t.Fatalf("backlog = %#v, want only policy.removed", backlog)
+	}
+
+	live := feed.publish("authz", "casbin", ChangeRoleAssigned, nil)
+	select {
+	case event := <-events:
+		if event.ID != live.ID {
+			t.Fatalf("live event id = %d, want %d", event.ID, live.ID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected live event")
+	}
+}
+
+func TestChangeFeedReportsMissedEvents(t *testing.T) {
+	feed := newChangeFeed(2)
+	for i := 0; i < 5; i++ {
+		feed.publish("authz", "casbin", ChangePolicyAdded, nil)
+	}
+	backlog, _, cancel, complete := feed.subscribe(1, 8)
+	defer cancel()
+	if complete {
+		t.Fatal("resume from an evicted event id should be incomplete")
+	}
+	if len(backlog) != 2 || backlog[0].ID != 4 || backlog[1].ID != 5 {
+		t.Fatalf("backlog = %#v, want retained events 4 and 5", backlog)
+	}
+
+	_, _, cancel, complete = feed.subscribe(99, 8)
+	defer cancel()
+	if complete {
+		t.Fatal("resume from an unknown future event id should be incomplete")
+	}
+}
+
+func TestChangeFeedRejectsEventIDsFromAnotherEpoch(t *testing.T) {
+	feed := newChangeFeed(4)
+	event := feed.publish("authz", "casbin", ChangePolicyAdded, nil)
+	sequence, current, err := feed.parseEventID(event.EventID())
+	if err != nil || !current || sequence != event.ID {
+		t.Fatalf("parseEventID(%q) = %d, %v, %v", event.EventID(), sequence, current, err)
+	}
+
+	// After a restart the sequence starts over, so an ID minted by the previous
+	// process must not be resumed as if it were this one.
+	restarted := newChangeFeed(4)
+	restarted.epoch = feed.epoch + "x"
+	for _, id := range []string{event.EventID(), "1"} {
+		if _, current, err := restarted.parseEventID(id); err != nil || current {
+			t.Fatalf("parseEventID(%q) after restart = %v, %v; want another epoch", id, current, err)
+		}
+	}
+	if _, _, err := feed.parseEventID("bogus"); err == nil {
+		t.Fatal("expected an error for a malformed event id")
+	}
+}
+
+func TestChangeFeedDropsSlowSubscribers(t *testing.T) {
+	feed := newChangeFeed(8)
+	_, events, cancel, _ := feed.subscribe(0, 1)
+	defer cancel()
+	feed.publish("authz", "casbin", ChangePolicyAdded, nil)
+	feed.publish("authz", "casbin", ChangePolicyAdded, nil)
+
+	<-events
+	if _, ok := <-events; ok {
+		t.Fatal("slow subscriber channel should be closed")
+	}
+}
+
+func TestProvidersPublishChangeEvents(t *testing.T) {
+	ctx := context.Background()
+	_, events, cancel, _ := SubscribeChangeEvents(0)
+	defer cancel()
+
+	mod := buildModule(t, nil, nil)
+	if _, err := mod.AddPolicy([]string{"editor", "/docs", "write"}); err != nil {
+		t.Fatalf("AddPolicy: %v", err)
+	}
+	if _, err := mod.AddGroupingPolicy([]string{"alice", "editor"}); err != nil {
+		t.Fatalf("AddGroupingPolicy: %v", err)
+	}
+	catalog := newScopeCatalogModule("catalog", nil)
+	if _, err := catalog.registerScopes(&contracts.RegisterScopesInput{Scopes: []*contracts.ScopeDeclaration{{Name: "cms.page.read"}}}); err != nil {
+		t.Fatalf("registerScopes: %v", err)
+	}
+	keto := newKetoScopeProvider("keto", &fakeKetoClient{})
+	if err := keto.UpsertRelationTuple(ctx, RelationTuple{Subject: "alice", Relation: "viewer", Object: "doc-1", Context: "cms"}); err != nil {
+		t.Fatalf("UpsertRelationTuple: %v", err)
+	}
+
+	want := []ChangeEventType{ChangePolicyAdded, ChangeRoleAssigned, ChangeDeclarationsRegistered, ChangeRelationWritten}
+	for _, wantType := range want {
+		select {
+		case event := <-events:
+			if event.Type != wantType {
+				t.Fatalf("event type = %s, want %s", event.Type, wantType)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("missing %s event", wantType)
+		}
+	}
+}
@@ -338,6 +338,8 @@ type CasbinModuleConfig struct {
 	RoleAssignments []*StringList          `protobuf:"bytes,3,rep,name=role_assignments,json=roleAssignments,proto3" json:"role_assignments,omitempty"`
 	Adapter         *AdapterConfig         `protobuf:"bytes,4,opt,name=adapter,proto3" json:"adapter,omitempty"`
 	Watcher         *WatcherConfig         `protobuf:"bytes,5,opt,name=watcher,proto3" json:"watcher,omitempty"`
+	Catalog         string                 `protobuf:"bytes,6,opt,name=catalog,proto3" json:"catalog,omitempty"`
+	RelationModel   *RelationModelConfig   `protobuf:"bytes,7,opt,name=relation_model,json=relationModel,proto3" json:"relation_model,omitempty"`
 	unknownFields   protoimpl.UnknownFields
 	sizeCache       protoimpl.SizeCache
 }
@@ -407,6 +409,224 @@ func (x *CasbinModuleConfig) GetWatcher() *WatcherConfig {
 	return nil
 }
 
+func (x *CasbinModuleConfig) GetCatalog() string {
+	if x != nil {
+		return x.Catalog
+	}
+	return ""
+}
+
+func (x *CasbinModuleConfig) GetRelationModel() *RelationModelConfig {
+	if x != nil {
+		return x.RelationModel
+	}
+	return nil
+}
+
+type RelationModelConfig struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Format        string                 `protobuf:"bytes,1,opt,name=format,proto3" json:"format,omitempty"`
+	Schema        string                 `protobuf:"bytes,2,opt,name=schema,proto3" json:"schema,omitempty"`
+	File          string                 `protobuf:"bytes,3,opt,name=file,proto3" json:"file,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RelationModelConfig) Reset() {
+	*x = RelationModelConfig{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RelationModelConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RelationModelConfig) ProtoMessage() {}
+
+func (x *RelationModelConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RelationModelConfig.ProtoReflect.Descriptor instead.
+func (*RelationModelConfig) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *RelationModelConfig) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *RelationModelConfig) GetSchema() string {
+	if x != nil {
+		return x.Schema
+	}
+	return ""
+}
+
+func (x *RelationModelConfig) GetFile() string {
+	if x != nil {
+		return x.File
+	}
+	return ""
+}
+
+type TransportConfig struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Timeout          string                 `protobuf:"bytes,1,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	MaxRetries       *int32                 `protobuf:"varint,2,opt,name=max_retries,json=maxRetries,proto3,oneof" json:"max_retries,omitempty"`
+	Backoff          string                 `protobuf:"bytes,3,opt,name=backoff,proto3" json:"backoff,omitempty"`
+	MaxBackoff       string                 `protobuf:"bytes,4,opt,name=max_backoff,json=maxBackoff,proto3" json:"max_backoff,omitempty"`
+	BreakerThreshold int32                  `protobuf:"varint,5,opt,name=breaker_threshold,json=breakerThreshold,proto3" json:"breaker_threshold,omitempty"`
+	BreakerCooldown  string                 `protobuf:"bytes,6,opt,name=breaker_cooldown,json=breakerCooldown,proto3" json:"breaker_cooldown,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *TransportConfig) Reset() {
+	*x = TransportConfig{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransportConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransportConfig) ProtoMessage() {}
+
+func (x *TransportConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransportConfig.ProtoReflect.Descriptor instead.
+func (*TransportConfig) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *TransportConfig) GetTimeout() string {
+	if x != nil {
+		return x.Timeout
+	}
+	return ""
+}
+
+func (x *TransportConfig) GetMaxRetries() int32 {
+	if x != nil && x.MaxRetries != nil {
+		return *x.MaxRetries
+	}
+	return 0
+}
+
+func (x *TransportConfig) GetBackoff() string {
+	if x != nil {
+		return x.Backoff
+	}
+	return ""
+}
+
+func (x *TransportConfig) GetMaxBackoff() string {
+	if x != nil {
+		return x.MaxBackoff
+	}
+	return ""
+}
+
+func (x *TransportConfig) GetBreakerThreshold() int32 {
+	if x != nil {
+		return x.BreakerThreshold
+	}
+	return 0
+}
+
+func (x *TransportConfig) GetBreakerCooldown() string {
+	if x != nil {
+		return x.BreakerCooldown
+	}
+	return ""
+}
+
+type LocalPDPConfig struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Enabled       bool                   `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	Interval      string                 `protobuf:"bytes,2,opt,name=interval,proto3" json:"interval,omitempty"`
+	MaxStaleness  string                 `protobuf:"bytes,3,opt,name=max_staleness,json=maxStaleness,proto3" json:"max_staleness,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LocalPDPConfig) Reset() {
+	*x = LocalPDPConfig{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LocalPDPConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LocalPDPConfig) ProtoMessage() {}
+
+func (x *LocalPDPConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LocalPDPConfig.ProtoReflect.Descriptor instead.
+func (*LocalPDPConfig) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *LocalPDPConfig) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *LocalPDPConfig) GetInterval() string {
+	if x != nil {
+		return x.Interval
+	}
+	return ""
+}
+
+func (x *LocalPDPConfig) GetMaxStaleness() string {
+	if x != nil {
+		return x.MaxStaleness
+	}
+	return ""
+}
+
 type PermitModuleConfig struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ApiKey        string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
@@ -414,13 +634,17 @@ type PermitModuleConfig struct {
 	ApiUrl        string                 `protobuf:"bytes,3,opt,name=api_url,json=apiUrl,proto3" json:"api_url,omitempty"`
 	Project       string                 `protobuf:"bytes,4,opt,name=project,proto3" json:"project,omitempty"`
 	Environment   string                 `protobuf:"bytes,5,opt,name=environment,proto3" json:"environment,omitempty"`
+	Catalog       string                 `protobuf:"bytes,6,opt,name=catalog,proto3" json:"catalog,omitempty"`
+	Transport     *TransportConfig       `protobuf:"bytes,7,opt,name=transport,proto3" json:"transport,omitempty"`
+	OnUnavailable string                 `protobuf:"bytes,8,opt,name=on_unavailable,json=onUnavailable,proto3" json:"on_unavailable,omitempty"`
+	LocalPdp      *LocalPDPConfig        `protobuf:"bytes,9,opt,name=local_pdp,json=localPdp,proto3" json:"local_pdp,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *PermitModuleConfig) Reset() {
 	*x = PermitModuleConfig{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[4]
+	mi := &file_internal_contracts_authz_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -432,7 +656,7 @@ func (x *PermitModuleConfig) String() string {
 func (*PermitModuleConfig) ProtoMessage() {}
 
 func (x *PermitModuleConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[4]
+	mi := &file_internal_contracts_authz_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -445,7 +669,7 @@ func (x *PermitModuleConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PermitModuleConfig.ProtoReflect.Descriptor instead.
 func (*PermitModuleConfig) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{4}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *PermitModuleConfig) GetApiKey() string {
@@ -483,17 +707,49 @@ func (x *PermitModuleConfig) GetEnvironment() string {
 	return ""
 }
 
+func (x *PermitModuleConfig) GetCatalog() string {
+	if x != nil {
+		return x.Catalog
+	}
+	return ""
+}
+
+func (x *PermitModuleConfig) GetTransport() *TransportConfig {
+	if x != nil {
+		return x.Transport
+	}
+	return nil
+}
+
+func (x *PermitModuleConfig) GetOnUnavailable() string {
+	if x != nil {
+		return x.OnUnavailable
+	}
+	return ""
+}
+
+func (x *PermitModuleConfig) GetLocalPdp() *LocalPDPConfig {
+	if x != nil {
+		return x.LocalPdp
+	}
+	return nil
+}
+
 type KetoModuleConfig struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ReadUrl       string                 `protobuf:"bytes,1,opt,name=read_url,json=readUrl,proto3" json:"read_url,omitempty"`
 	WriteUrl      string                 `protobuf:"bytes,2,opt,name=write_url,json=writeUrl,proto3" json:"write_url,omitempty"`
+	Catalog       string                 `protobuf:"bytes,3,opt,name=catalog,proto3" json:"catalog,omitempty"`
+	Watcher       *WatcherConfig         `protobuf:"bytes,4,opt,name=watcher,proto3" json:"watcher,omitempty"`
+	Transport     *TransportConfig       `protobuf:"bytes,5,opt,name=transport,proto3" json:"transport,omitempty"`
+	OnUnavailable string                 `protobuf:"bytes,6,opt,name=on_unavailable,json=onUnavailable,proto3" json:"on_unavailable,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *KetoModuleConfig) Reset() {
 	*x = KetoModuleConfig{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[5]
+	mi := &file_internal_contracts_authz_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -505,7 +761,7 @@ func (x *KetoModuleConfig) String() string {
 func (*KetoModuleConfig) ProtoMessage() {}
 
 func (x *KetoModuleConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[5]
+	mi := &file_internal_contracts_authz_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -518,7 +774,7 @@ func (x *KetoModuleConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use KetoModuleConfig.ProtoReflect.Descriptor instead.
 func (*KetoModuleConfig) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{5}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *KetoModuleConfig) GetReadUrl() string {
@@ -535,6 +791,34 @@ func (x *KetoModuleConfig) GetWriteUrl() string {
 	return ""
 }
 
+func (x *KetoModuleConfig) GetCatalog() string {
+	if x != nil {
+		return x.Catalog
+	}
+	return ""
+}
+
+func (x *KetoModuleConfig) GetWatcher() *WatcherConfig {
+	if x != nil {
+		return x.Watcher
+	}
+	return nil
+}
+
+func (x *KetoModuleConfig) GetTransport() *TransportConfig {
+	if x != nil {
+		return x.Transport
+	}
+	return nil
+}
+
+func (x *KetoModuleConfig) GetOnUnavailable() string {
+	if x != nil {
+		return x.OnUnavailable
+	}
+	return ""
+}
+
 type ExtraField struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
@@ -545,7 +829,7 @@ type ExtraField struct {
 
 func (x *ExtraField) Reset() {
 	*x = ExtraField{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[6]
+	mi := &file_internal_contracts_authz_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -557,7 +841,7 @@ func (x *ExtraField) String() string {
 func (*ExtraField) ProtoMessage() {}
 
 func (x *ExtraField) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[6]
+	mi := &file_internal_contracts_authz_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -570,7 +854,7 @@ func (x *ExtraField) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ExtraField.ProtoReflect.Descriptor instead.
 func (*ExtraField) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{6}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *ExtraField) GetKey() string {
@@ -601,7 +885,7 @@ type AuthzCheckConfig struct {
 
 func (x *AuthzCheckConfig) Reset() {
 	*x = AuthzCheckConfig{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[7]
+	mi := &file_internal_contracts_authz_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -613,7 +897,7 @@ func (x *AuthzCheckConfig) String() string {
 func (*AuthzCheckConfig) ProtoMessage() {}
 
 func (x *AuthzCheckConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[7]
+	mi := &file_internal_contracts_authz_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -626,7 +910,7 @@ func (x *AuthzCheckConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AuthzCheckConfig.ProtoReflect.Descriptor instead.
 func (*AuthzCheckConfig) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{7}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *AuthzCheckConfig) GetModule() string {
@@ -685,7 +969,7 @@ type AuthzCheckInput struct {
 
 func (x *AuthzCheckInput) Reset() {
 	*x = AuthzCheckInput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[8]
+	mi := &file_internal_contracts_authz_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -697,7 +981,7 @@ func (x *AuthzCheckInput) String() string {
 func (*AuthzCheckInput) ProtoMessage() {}
 
 func (x *AuthzCheckInput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[8]
+	mi := &file_internal_contracts_authz_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -710,7 +994,7 @@ func (x *AuthzCheckInput) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AuthzCheckInput.ProtoReflect.Descriptor instead.
 func (*AuthzCheckInput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{8}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *AuthzCheckInput) GetModule() string {
@@ -771,7 +1055,7 @@ type AuthzCheckOutput struct {
 
 func (x *AuthzCheckOutput) Reset() {
 	*x = AuthzCheckOutput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[9]
+	mi := &file_internal_contracts_authz_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -783,7 +1067,7 @@ func (x *AuthzCheckOutput) String() string {
 func (*AuthzCheckOutput) ProtoMessage() {}
 
 func (x *AuthzCheckOutput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[9]
+	mi := &file_internal_contracts_authz_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -796,7 +1080,7 @@ func (x *AuthzCheckOutput) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AuthzCheckOutput.ProtoReflect.Descriptor instead.
 func (*AuthzCheckOutput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{9}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *AuthzCheckOutput) GetSubject() string {
@@ -859,13 +1143,16 @@ type PolicyRuleConfig struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Module        string                 `protobuf:"bytes,1,opt,name=module,proto3" json:"module,omitempty"`
 	Rule          []string               `protobuf:"bytes,2,rep,name=rule,proto3" json:"rule,omitempty"`
+	Actor         string                 `protobuf:"bytes,10,opt,name=actor,proto3" json:"actor,omitempty"`
+	Reason        string                 `protobuf:"bytes,11,opt,name=reason,proto3" json:"reason,omitempty"`
+	AuditModule   string                 `protobuf:"bytes,12,opt,name=audit_module,json=auditModule,proto3" json:"audit_module,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *PolicyRuleConfig) Reset() {
 	*x = PolicyRuleConfig{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[10]
+	mi := &file_internal_contracts_authz_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -877,7 +1164,7 @@ func (x *PolicyRuleConfig) String() string {
 func (*PolicyRuleConfig) ProtoMessage() {}
 
 func (x *PolicyRuleConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[10]
+	mi := &file_internal_contracts_authz_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -890,7 +1177,7 @@ func (x *PolicyRuleConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PolicyRuleConfig.ProtoReflect.Descriptor instead.
 func (*PolicyRuleConfig) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{10}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *PolicyRuleConfig) GetModule() string {
@@ -907,17 +1194,41 @@ func (x *PolicyRuleConfig) GetRule() []string {
 	return nil
 }
 
+func (x *PolicyRuleConfig) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *PolicyRuleConfig) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *PolicyRuleConfig) GetAuditModule() string {
+	if x != nil {
+		return x.AuditModule
+	}
+	return ""
+}
+
 type PolicyRuleInput struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Module        string                 `protobuf:"bytes,1,opt,name=module,proto3" json:"module,omitempty"`
 	Rule          []string               `protobuf:"bytes,2,rep,name=rule,proto3" json:"rule,omitempty"`
+	Actor         string                 `protobuf:"bytes,10,opt,name=actor,proto3" json:"actor,omitempty"`
+	Reason        string                 `protobuf:"bytes,11,opt,name=reason,proto3" json:"reason,omitempty"`
+	AuditModule   string                 `protobuf:"bytes,12,opt,name=audit_module,json=auditModule,proto3" json:"audit_module,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *PolicyRuleInput) Reset() {
 	*x = PolicyRuleInput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[11]
+	mi := &file_internal_contracts_authz_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -929,7 +1240,7 @@ func (x *PolicyRuleInput) String() string {
 func (*PolicyRuleInput) ProtoMessage() {}
 
 func (x *PolicyRuleInput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[11]
+	mi := &file_internal_contracts_authz_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -942,7 +1253,7 @@ func (x *PolicyRuleInput) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PolicyRuleInput.ProtoReflect.Descriptor instead.
 func (*PolicyRuleInput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{11}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *PolicyRuleInput) GetModule() string {
@@ -959,6 +1270,27 @@ func (x *PolicyRuleInput) GetRule() []string {
 	return nil
 }
 
+func (x *PolicyRuleInput) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *PolicyRuleInput) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *PolicyRuleInput) GetAuditModule() string {
+	if x != nil {
+		return x.AuditModule
+	}
+	return ""
+}
+
 type PolicyRuleOutput struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Changed       bool                   `protobuf:"varint,1,opt,name=changed,proto3" json:"changed,omitempty"`
@@ -970,7 +1302,7 @@ type PolicyRuleOutput struct {
 
 func (x *PolicyRuleOutput) Reset() {
 	*x = PolicyRuleOutput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[12]
+	mi := &file_internal_contracts_authz_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -982,7 +1314,7 @@ func (x *PolicyRuleOutput) String() string {
 func (*PolicyRuleOutput) ProtoMessage() {}
 
 func (x *PolicyRuleOutput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[12]
+	mi := &file_internal_contracts_authz_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -995,7 +1327,7 @@ func (x *PolicyRuleOutput) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PolicyRuleOutput.ProtoReflect.Descriptor instead.
 func (*PolicyRuleOutput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{12}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *PolicyRuleOutput) GetChanged() bool {
@@ -1024,13 +1356,16 @@ type RoleAssignConfig struct {
 	Module        string                 `protobuf:"bytes,1,opt,name=module,proto3" json:"module,omitempty"`
 	Action        string                 `protobuf:"bytes,2,opt,name=action,proto3" json:"action,omitempty"`
 	Assignments   []*StringList          `protobuf:"bytes,3,rep,name=assignments,proto3" json:"assignments,omitempty"`
+	Actor         string                 `protobuf:"bytes,10,opt,name=actor,proto3" json:"actor,omitempty"`
+	Reason        string                 `protobuf:"bytes,11,opt,name=reason,proto3" json:"reason,omitempty"`
+	AuditModule   string                 `protobuf:"bytes,12,opt,name=audit_module,json=auditModule,proto3" json:"audit_module,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *RoleAssignConfig) Reset() {
 	*x = RoleAssignConfig{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[13]
+	mi := &file_internal_contracts_authz_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1042,7 +1377,7 @@ func (x *RoleAssignConfig) String() string {
 func (*RoleAssignConfig) ProtoMessage() {}
 
 func (x *RoleAssignConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[13]
+	mi := &file_internal_contracts_authz_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1055,7 +1390,7 @@ func (x *RoleAssignConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RoleAssignConfig.ProtoReflect.Descriptor instead.
 func (*RoleAssignConfig) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{13}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *RoleAssignConfig) GetModule() string {
@@ -1079,18 +1414,42 @@ func (x *RoleAssignConfig) GetAssignments() []*StringList {
 	return nil
 }
 
+func (x *RoleAssignConfig) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *RoleAssignConfig) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *RoleAssignConfig) GetAuditModule() string {
+	if x != nil {
+		return x.AuditModule
+	}
+	return ""
+}
+
 type RoleAssignInput struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Module        string                 `protobuf:"bytes,1,opt,name=module,proto3" json:"module,omitempty"`
 	Action        string                 `protobuf:"bytes,2,opt,name=action,proto3" json:"action,omitempty"`
 	Assignments   []*StringList          `protobuf:"bytes,3,rep,name=assignments,proto3" json:"assignments,omitempty"`
+	Actor         string                 `protobuf:"bytes,10,opt,name=actor,proto3" json:"actor,omitempty"`
+	Reason        string                 `protobuf:"bytes,11,opt,name=reason,proto3" json:"reason,omitempty"`
+	AuditModule   string                 `protobuf:"bytes,12,opt,name=audit_module,json=auditModule,proto3" json:"audit_module,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *RoleAssignInput) Reset() {
 	*x = RoleAssignInput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[14]
+	mi := &file_internal_contracts_authz_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1102,7 +1461,7 @@ func (x *RoleAssignInput) String() string {
 func (*RoleAssignInput) ProtoMessage() {}
 
 func (x *RoleAssignInput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[14]
+	mi := &file_internal_contracts_authz_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1115,7 +1474,7 @@ func (x *RoleAssignInput) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RoleAssignInput.ProtoReflect.Descriptor instead.
 func (*RoleAssignInput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{14}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *RoleAssignInput) GetModule() string {
@@ -1139,6 +1498,27 @@ func (x *RoleAssignInput) GetAssignments() []*StringList {
 	return nil
 }
 
+func (x *RoleAssignInput) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *RoleAssignInput) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *RoleAssignInput) GetAuditModule() string {
+	if x != nil {
+		return x.AuditModule
+	}
+	return ""
+}
+
 type RoleAssignOutput struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Action        string                 `protobuf:"bytes,1,opt,name=action,proto3" json:"action,omitempty"`
@@ -1150,7 +1530,7 @@ type RoleAssignOutput struct {
 
 func (x *RoleAssignOutput) Reset() {
 	*x = RoleAssignOutput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[15]
+	mi := &file_internal_contracts_authz_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1162,7 +1542,7 @@ func (x *RoleAssignOutput) String() string {
 func (*RoleAssignOutput) ProtoMessage() {}
 
 func (x *RoleAssignOutput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[15]
+	mi := &file_internal_contracts_authz_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1175,7 +1555,7 @@ func (x *RoleAssignOutput) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RoleAssignOutput.ProtoReflect.Descriptor instead.
 func (*RoleAssignOutput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{15}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{18}
 }
 
 func (x *RoleAssignOutput) GetAction() string {
@@ -1209,7 +1589,7 @@ type CapabilitiesConfig struct {
 
 func (x *CapabilitiesConfig) Reset() {
 	*x = CapabilitiesConfig{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[16]
+	mi := &file_internal_contracts_authz_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1221,7 +1601,7 @@ func (x *CapabilitiesConfig) String() string {
 func (*CapabilitiesConfig) ProtoMessage() {}
 
 func (x *CapabilitiesConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[16]
+	mi := &file_internal_contracts_authz_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1234,7 +1614,7 @@ func (x *CapabilitiesConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CapabilitiesConfig.ProtoReflect.Descriptor instead.
 func (*CapabilitiesConfig) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{16}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *CapabilitiesConfig) GetModule() string {
@@ -1261,7 +1641,7 @@ type CapabilitiesInput struct {
 
 func (x *CapabilitiesInput) Reset() {
 	*x = CapabilitiesInput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[17]
+	mi := &file_internal_contracts_authz_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1273,7 +1653,7 @@ func (x *CapabilitiesInput) String() string {
 func (*CapabilitiesInput) ProtoMessage() {}
 
 func (x *CapabilitiesInput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[17]
+	mi := &file_internal_contracts_authz_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1286,7 +1666,7 @@ func (x *CapabilitiesInput) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CapabilitiesInput.ProtoReflect.Descriptor instead.
 func (*CapabilitiesInput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{17}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *CapabilitiesInput) GetModule() string {
@@ -1318,7 +1698,7 @@ type CapabilitiesOutput struct {
 
 func (x *CapabilitiesOutput) Reset() {
 	*x = CapabilitiesOutput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[18]
+	mi := &file_internal_contracts_authz_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1330,7 +1710,7 @@ func (x *CapabilitiesOutput) String() string {
 func (*CapabilitiesOutput) ProtoMessage() {}
 
 func (x *CapabilitiesOutput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[18]
+	mi := &file_internal_contracts_authz_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1343,7 +1723,7 @@ func (x *CapabilitiesOutput) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CapabilitiesOutput.ProtoReflect.Descriptor instead.
 func (*CapabilitiesOutput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{18}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{21}
 }
 
 func (x *CapabilitiesOutput) GetModule() string {
@@ -1409,7 +1789,7 @@ type CapabilityDescriptor struct {
 
 func (x *CapabilityDescriptor) Reset() {
 	*x = CapabilityDescriptor{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[19]
+	mi := &file_internal_contracts_authz_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1421,7 +1801,7 @@ func (x *CapabilityDescriptor) String() string {
 func (*CapabilityDescriptor) ProtoMessage() {}
 
 func (x *CapabilityDescriptor) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[19]
+	mi := &file_internal_contracts_authz_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1434,7 +1814,7 @@ func (x *CapabilityDescriptor) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CapabilityDescriptor.ProtoReflect.Descriptor instead.
 func (*CapabilityDescriptor) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{19}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{22}
 }
 
 func (x *CapabilityDescriptor) GetMode() AuthzMode {
@@ -1489,7 +1869,7 @@ type CapabilityRequirement struct {
 
 func (x *CapabilityRequirement) Reset() {
 	*x = CapabilityRequirement{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[20]
+	mi := &file_internal_contracts_authz_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1501,7 +1881,7 @@ func (x *CapabilityRequirement) String() string {
 func (*CapabilityRequirement) ProtoMessage() {}
 
 func (x *CapabilityRequirement) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[20]
+	mi := &file_internal_contracts_authz_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1514,7 +1894,7 @@ func (x *CapabilityRequirement) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CapabilityRequirement.ProtoReflect.Descriptor instead.
 func (*CapabilityRequirement) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{20}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{23}
 }
 
 func (x *CapabilityRequirement) GetMode() AuthzMode {
@@ -1542,7 +1922,7 @@ type ProviderCapabilitiesInput struct {
 
 func (x *ProviderCapabilitiesInput) Reset() {
 	*x = ProviderCapabilitiesInput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[21]
+	mi := &file_internal_contracts_authz_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1554,7 +1934,7 @@ func (x *ProviderCapabilitiesInput) String() string {
 func (*ProviderCapabilitiesInput) ProtoMessage() {}
 
 func (x *ProviderCapabilitiesInput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[21]
+	mi := &file_internal_contracts_authz_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1567,7 +1947,7 @@ func (x *ProviderCapabilitiesInput) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProviderCapabilitiesInput.ProtoReflect.Descriptor instead.
 func (*ProviderCapabilitiesInput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{21}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{24}
 }
 
 func (x *ProviderCapabilitiesInput) GetModule() string {
@@ -1606,7 +1986,7 @@ type ProviderCapabilitiesOutput struct {
 
 func (x *ProviderCapabilitiesOutput) Reset() {
 	*x = ProviderCapabilitiesOutput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[22]
+	mi := &file_internal_contracts_authz_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1618,7 +1998,7 @@ func (x *ProviderCapabilitiesOutput) String() string {
 func (*ProviderCapabilitiesOutput) ProtoMessage() {}
 
 func (x *ProviderCapabilitiesOutput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[22]
+	mi := &file_internal_contracts_authz_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1631,7 +2011,7 @@ func (x *ProviderCapabilitiesOutput) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProviderCapabilitiesOutput.ProtoReflect.Descriptor instead.
 func (*ProviderCapabilitiesOutput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{22}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{25}
 }
 
 func (x *ProviderCapabilitiesOutput) GetModule() string {
@@ -1695,13 +2075,14 @@ type AuthorizationDecisionConfig struct {
 	Scope         string                 `protobuf:"bytes,8,opt,name=scope,proto3" json:"scope,omitempty"`
 	Relation      string                 `protobuf:"bytes,9,opt,name=relation,proto3" json:"relation,omitempty"`
 	Explain       bool                   `protobuf:"varint,10,opt,name=explain,proto3" json:"explain,omitempty"`
+	OnUnavailable string                 `protobuf:"bytes,11,opt,name=on_unavailable,json=onUnavailable,proto3" json:"on_unavailable,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *AuthorizationDecisionConfig) Reset() {
 	*x = AuthorizationDecisionConfig{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[23]
+	mi := &file_internal_contracts_authz_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1713,7 +2094,7 @@ func (x *AuthorizationDecisionConfig) String() string {
 func (*AuthorizationDecisionConfig) ProtoMessage() {}
 
 func (x *AuthorizationDecisionConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[23]
+	mi := &file_internal_contracts_authz_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1726,7 +2107,7 @@ func (x *AuthorizationDecisionConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AuthorizationDecisionConfig.ProtoReflect.Descriptor instead.
 func (*AuthorizationDecisionConfig) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{23}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{26}
 }
 
 func (x *AuthorizationDecisionConfig) GetModule() string {
@@ -1799,6 +2180,13 @@ func (x *AuthorizationDecisionConfig) GetExplain() bool {
 	return false
 }
 
+func (x *AuthorizationDecisionConfig) GetOnUnavailable() string {
+	if x != nil {
+		return x.OnUnavailable
+	}
+	return ""
+}
+
 type AuthorizationDecisionInput struct {
 	state                 protoimpl.MessageState `protogen:"open.v1"`
 	Module                string                 `protobuf:"bytes,1,opt,name=module,proto3" json:"module,omitempty"`
@@ -1820,7 +2208,7 @@ type AuthorizationDecisionInput struct {
 
 func (x *AuthorizationDecisionInput) Reset() {
 	*x = AuthorizationDecisionInput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[24]
+	mi := &file_internal_contracts_authz_proto_msgTypes[27]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1832,7 +2220,7 @@ func (x *AuthorizationDecisionInput) String() string {
 func (*AuthorizationDecisionInput) ProtoMessage() {}
 
 func (x *AuthorizationDecisionInput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[24]
+	mi := &file_internal_contracts_authz_proto_msgTypes[27]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1845,7 +2233,7 @@ func (x *AuthorizationDecisionInput) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AuthorizationDecisionInput.ProtoReflect.Descriptor instead.
 func (*AuthorizationDecisionInput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{24}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{27}
 }
 
 func (x *AuthorizationDecisionInput) GetModule() string {
@@ -1954,7 +2342,7 @@ type AuthorizationDecisionOutput struct {
 
 func (x *AuthorizationDecisionOutput) Reset() {
 	*x = AuthorizationDecisionOutput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[25]
+	mi := &file_internal_contracts_authz_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1966,7 +2354,7 @@ func (x *AuthorizationDecisionOutput) String() string {
 func (*AuthorizationDecisionOutput) ProtoMessage() {}
 
 func (x *AuthorizationDecisionOutput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[25]
+	mi := &file_internal_contracts_authz_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1979,7 +2367,7 @@ func (x *AuthorizationDecisionOutput) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AuthorizationDecisionOutput.ProtoReflect.Descriptor instead.
 func (*AuthorizationDecisionOutput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{25}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{28}
 }
 
 func (x *AuthorizationDecisionOutput) GetAllowed() bool {
@@ -2042,7 +2430,7 @@ type RequireCapabilitiesConfig struct {
 
 func (x *RequireCapabilitiesConfig) Reset() {
 	*x = RequireCapabilitiesConfig{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[26]
+	mi := &file_internal_contracts_authz_proto_msgTypes[29]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2054,7 +2442,7 @@ func (x *RequireCapabilitiesConfig) String() string {
 func (*RequireCapabilitiesConfig) ProtoMessage() {}
 
 func (x *RequireCapabilitiesConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[26]
+	mi := &file_internal_contracts_authz_proto_msgTypes[29]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2067,7 +2455,7 @@ func (x *RequireCapabilitiesConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RequireCapabilitiesConfig.ProtoReflect.Descriptor instead.
 func (*RequireCapabilitiesConfig) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{26}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{29}
 }
 
 func (x *RequireCapabilitiesConfig) GetModule() string {
@@ -2102,7 +2490,7 @@ type RequireCapabilitiesInput struct {
 
 func (x *RequireCapabilitiesInput) Reset() {
 	*x = RequireCapabilitiesInput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[27]
+	mi := &file_internal_contracts_authz_proto_msgTypes[30]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2114,7 +2502,7 @@ func (x *RequireCapabilitiesInput) String() string {
 func (*RequireCapabilitiesInput) ProtoMessage() {}
 
 func (x *RequireCapabilitiesInput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[27]
+	mi := &file_internal_contracts_authz_proto_msgTypes[30]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2127,7 +2515,7 @@ func (x *RequireCapabilitiesInput) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RequireCapabilitiesInput.ProtoReflect.Descriptor instead.
 func (*RequireCapabilitiesInput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{27}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{30}
 }
 
 func (x *RequireCapabilitiesInput) GetModule() string {
@@ -2157,13 +2545,16 @@ type SubjectObjectActionConfig struct {
 	Subject       string                 `protobuf:"bytes,2,opt,name=subject,proto3" json:"subject,omitempty"`
 	Object        string                 `protobuf:"bytes,3,opt,name=object,proto3" json:"object,omitempty"`
 	Action        string                 `protobuf:"bytes,4,opt,name=action,proto3" json:"action,omitempty"`
+	Actor         string                 `protobuf:"bytes,10,opt,name=actor,proto3" json:"actor,omitempty"`
+	Reason        string                 `protobuf:"bytes,11,opt,name=reason,proto3" json:"reason,omitempty"`
+	AuditModule   string                 `protobuf:"bytes,12,opt,name=audit_module,json=auditModule,proto3" json:"audit_module,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *SubjectObjectActionConfig) Reset() {
 	*x = SubjectObjectActionConfig{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[28]
+	mi := &file_internal_contracts_authz_proto_msgTypes[31]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2175,7 +2566,7 @@ func (x *SubjectObjectActionConfig) String() string {
 func (*SubjectObjectActionConfig) ProtoMessage() {}
 
 func (x *SubjectObjectActionConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[28]
+	mi := &file_internal_contracts_authz_proto_msgTypes[31]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2188,7 +2579,7 @@ func (x *SubjectObjectActionConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SubjectObjectActionConfig.ProtoReflect.Descriptor instead.
 func (*SubjectObjectActionConfig) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{28}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{31}
 }
 
 func (x *SubjectObjectActionConfig) GetModule() string {
@@ -2219,19 +2610,43 @@ func (x *SubjectObjectActionConfig) GetAction() string {
 	return ""
 }
 
+func (x *SubjectObjectActionConfig) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *SubjectObjectActionConfig) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *SubjectObjectActionConfig) GetAuditModule() string {
+	if x != nil {
+		return x.AuditModule
+	}
+	return ""
+}
+
 type SubjectObjectActionInput struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Module        string                 `protobuf:"bytes,1,opt,name=module,proto3" json:"module,omitempty"`
 	Subject       string                 `protobuf:"bytes,2,opt,name=subject,proto3" json:"subject,omitempty"`
 	Object        string                 `protobuf:"bytes,3,opt,name=object,proto3" json:"object,omitempty"`
 	Action        string                 `protobuf:"bytes,4,opt,name=action,proto3" json:"action,omitempty"`
+	Actor         string                 `protobuf:"bytes,10,opt,name=actor,proto3" json:"actor,omitempty"`
+	Reason        string                 `protobuf:"bytes,11,opt,name=reason,proto3" json:"reason,omitempty"`
+	AuditModule   string                 `protobuf:"bytes,12,opt,name=audit_module,json=auditModule,proto3" json:"audit_module,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *SubjectObjectActionInput) Reset() {
 	*x = SubjectObjectActionInput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[29]
+	mi := &file_internal_contracts_authz_proto_msgTypes[32]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2243,7 +2658,7 @@ func (x *SubjectObjectActionInput) String() string {
 func (*SubjectObjectActionInput) ProtoMessage() {}
 
 func (x *SubjectObjectActionInput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[29]
+	mi := &file_internal_contracts_authz_proto_msgTypes[32]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2256,7 +2671,7 @@ func (x *SubjectObjectActionInput) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SubjectObjectActionInput.ProtoReflect.Descriptor instead.
 func (*SubjectObjectActionInput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{29}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{32}
 }
 
 func (x *SubjectObjectActionInput) GetModule() string {
@@ -2287,6 +2702,27 @@ func (x *SubjectObjectActionInput) GetAction() string {
 	return ""
 }
 
+func (x *SubjectObjectActionInput) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *SubjectObjectActionInput) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *SubjectObjectActionInput) GetAuditModule() string {
+	if x != nil {
+		return x.AuditModule
+	}
+	return ""
+}
+
 type SubjectObjectActionOutput struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Allowed       bool                   `protobuf:"varint,1,opt,name=allowed,proto3" json:"allowed,omitempty"`
@@ -2301,7 +2737,7 @@ type SubjectObjectActionOutput struct {
 
 func (x *SubjectObjectActionOutput) Reset() {
 	*x = SubjectObjectActionOutput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[30]
+	mi := &file_internal_contracts_authz_proto_msgTypes[33]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2313,7 +2749,7 @@ func (x *SubjectObjectActionOutput) String() string {
 func (*SubjectObjectActionOutput) ProtoMessage() {}
 
 func (x *SubjectObjectActionOutput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[30]
+	mi := &file_internal_contracts_authz_proto_msgTypes[33]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2326,7 +2762,7 @@ func (x *SubjectObjectActionOutput) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SubjectObjectActionOutput.ProtoReflect.Descriptor instead.
 func (*SubjectObjectActionOutput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{30}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{33}
 }
 
 func (x *SubjectObjectActionOutput) GetAllowed() bool {
@@ -2382,7 +2818,7 @@ type ListConfig struct {
 
 func (x *ListConfig) Reset() {
 	*x = ListConfig{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[31]
+	mi := &file_internal_contracts_authz_proto_msgTypes[34]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2394,7 +2830,7 @@ func (x *ListConfig) String() string {
 func (*ListConfig) ProtoMessage() {}
 
 func (x *ListConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[31]
+	mi := &file_internal_contracts_authz_proto_msgTypes[34]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2407,7 +2843,7 @@ func (x *ListConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListConfig.ProtoReflect.Descriptor instead.
 func (*ListConfig) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{31}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{34}
 }
 
 func (x *ListConfig) GetModule() string {
@@ -2442,7 +2878,7 @@ type ListInput struct {
 
 func (x *ListInput) Reset() {
 	*x = ListInput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[32]
+	mi := &file_internal_contracts_authz_proto_msgTypes[35]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2454,7 +2890,7 @@ func (x *ListInput) String() string {
 func (*ListInput) ProtoMessage() {}
 
 func (x *ListInput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[32]
+	mi := &file_internal_contracts_authz_proto_msgTypes[35]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2467,7 +2903,7 @@ func (x *ListInput) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListInput.ProtoReflect.Descriptor instead.
 func (*ListInput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{32}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{35}
 }
 
 func (x *ListInput) GetModule() string {
@@ -2501,7 +2937,7 @@ type GenericStepOutput struct {
 
 func (x *GenericStepOutput) Reset() {
 	*x = GenericStepOutput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[33]
+	mi := &file_internal_contracts_authz_proto_msgTypes[36]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2513,7 +2949,7 @@ func (x *GenericStepOutput) String() string {
 func (*GenericStepOutput) ProtoMessage() {}
 
 func (x *GenericStepOutput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[33]
+	mi := &file_internal_contracts_authz_proto_msgTypes[36]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2526,7 +2962,7 @@ func (x *GenericStepOutput) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GenericStepOutput.ProtoReflect.Descriptor instead.
 func (*GenericStepOutput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{33}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{36}
 }
 
 func (x *GenericStepOutput) GetOutput() *structpb.Struct {
@@ -2549,13 +2985,16 @@ type RelationConfig struct {
 	Subject       string                 `protobuf:"bytes,2,opt,name=subject,proto3" json:"subject,omitempty"`
 	Relation      string                 `protobuf:"bytes,3,opt,name=relation,proto3" json:"relation,omitempty"`
 	Object        string                 `protobuf:"bytes,4,opt,name=object,proto3" json:"object,omitempty"`
+	Actor         string                 `protobuf:"bytes,10,opt,name=actor,proto3" json:"actor,omitempty"`
+	Reason        string                 `protobuf:"bytes,11,opt,name=reason,proto3" json:"reason,omitempty"`
+	AuditModule   string                 `protobuf:"bytes,12,opt,name=audit_module,json=auditModule,proto3" json:"audit_module,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *RelationConfig) Reset() {
 	*x = RelationConfig{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[34]
+	mi := &file_internal_contracts_authz_proto_msgTypes[37]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2567,7 +3006,7 @@ func (x *RelationConfig) String() string {
 func (*RelationConfig) ProtoMessage() {}
 
 func (x *RelationConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[34]
+	mi := &file_internal_contracts_authz_proto_msgTypes[37]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2580,7 +3019,7 @@ func (x *RelationConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RelationConfig.ProtoReflect.Descriptor instead.
 func (*RelationConfig) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{34}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{37}
 }
 
 func (x *RelationConfig) GetModule() string {
@@ -2611,19 +3050,43 @@ func (x *RelationConfig) GetObject() string {
 	return ""
 }
 
+func (x *RelationConfig) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *RelationConfig) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *RelationConfig) GetAuditModule() string {
+	if x != nil {
+		return x.AuditModule
+	}
+	return ""
+}
+
 type RelationInput struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Module        string                 `protobuf:"bytes,1,opt,name=module,proto3" json:"module,omitempty"`
 	Subject       string                 `protobuf:"bytes,2,opt,name=subject,proto3" json:"subject,omitempty"`
 	Relation      string                 `protobuf:"bytes,3,opt,name=relation,proto3" json:"relation,omitempty"`
 	Object        string                 `protobuf:"bytes,4,opt,name=object,proto3" json:"object,omitempty"`
+	Actor         string                 `protobuf:"bytes,10,opt,name=actor,proto3" json:"actor,omitempty"`
+	Reason        string                 `protobuf:"bytes,11,opt,name=reason,proto3" json:"reason,omitempty"`
+	AuditModule   string                 `protobuf:"bytes,12,opt,name=audit_module,json=auditModule,proto3" json:"audit_module,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *RelationInput) Reset() {
 	*x = RelationInput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[35]
+	mi := &file_internal_contracts_authz_proto_msgTypes[38]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2635,7 +3098,7 @@ func (x *RelationInput) String() string {
 func (*RelationInput) ProtoMessage() {}
 
 func (x *RelationInput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[35]
+	mi := &file_internal_contracts_authz_proto_msgTypes[38]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2648,7 +3111,7 @@ func (x *RelationInput) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RelationInput.ProtoReflect.Descriptor instead.
 func (*RelationInput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{35}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{38}
 }
 
 func (x *RelationInput) GetModule() string {
@@ -2679,20 +3142,41 @@ func (x *RelationInput) GetObject() string {
 	return ""
 }
 
-type RelationOutput struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Changed       bool                   `protobuf:"varint,1,opt,name=changed,proto3" json:"changed,omitempty"`
-	Subject       string                 `protobuf:"bytes,2,opt,name=subject,proto3" json:"subject,omitempty"`
-	Relation      string                 `protobuf:"bytes,3,opt,name=relation,proto3" json:"relation,omitempty"`
-	Object        string                 `protobuf:"bytes,4,opt,name=object,proto3" json:"object,omitempty"`
-	Error         string                 `protobuf:"bytes,100,opt,name=error,proto3" json:"error,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
-}
+func (x *RelationInput) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *RelationInput) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *RelationInput) GetAuditModule() string {
+	if x != nil {
+		return x.AuditModule
+	}
+	return ""
+}
+
+type RelationOutput struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Changed       bool                   `protobuf:"varint,1,opt,name=changed,proto3" json:"changed,omitempty"`
+	Subject       string                 `protobuf:"bytes,2,opt,name=subject,proto3" json:"subject,omitempty"`
+	Relation      string                 `protobuf:"bytes,3,opt,name=relation,proto3" json:"relation,omitempty"`
+	Object        string                 `protobuf:"bytes,4,opt,name=object,proto3" json:"object,omitempty"`
+	Error         string                 `protobuf:"bytes,100,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
 func (x *RelationOutput) Reset() {
 	*x = RelationOutput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[36]
+	mi := &file_internal_contracts_authz_proto_msgTypes[39]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2704,7 +3188,7 @@ func (x *RelationOutput) String() string {
 func (*RelationOutput) ProtoMessage() {}
 
 func (x *RelationOutput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[36]
+	mi := &file_internal_contracts_authz_proto_msgTypes[39]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2717,7 +3201,7 @@ func (x *RelationOutput) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RelationOutput.ProtoReflect.Descriptor instead.
 func (*RelationOutput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{36}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{39}
 }
 
 func (x *RelationOutput) GetChanged() bool {
@@ -2765,7 +3249,7 @@ type PermitStepConfig struct {
 
 func (x *PermitStepConfig) Reset() {
 	*x = PermitStepConfig{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[37]
+	mi := &file_internal_contracts_authz_proto_msgTypes[40]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2777,7 +3261,7 @@ func (x *PermitStepConfig) String() string {
 func (*PermitStepConfig) ProtoMessage() {}
 
 func (x *PermitStepConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[37]
+	mi := &file_internal_contracts_authz_proto_msgTypes[40]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2790,7 +3274,7 @@ func (x *PermitStepConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PermitStepConfig.ProtoReflect.Descriptor instead.
 func (*PermitStepConfig) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{37}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{40}
 }
 
 func (x *PermitStepConfig) GetModule() string {
@@ -2817,7 +3301,7 @@ type PermitStepInput struct {
 
 func (x *PermitStepInput) Reset() {
 	*x = PermitStepInput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[38]
+	mi := &file_internal_contracts_authz_proto_msgTypes[41]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2829,7 +3313,7 @@ func (x *PermitStepInput) String() string {
 func (*PermitStepInput) ProtoMessage() {}
 
 func (x *PermitStepInput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[38]
+	mi := &file_internal_contracts_authz_proto_msgTypes[41]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2842,7 +3326,7 @@ func (x *PermitStepInput) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PermitStepInput.ProtoReflect.Descriptor instead.
 func (*PermitStepInput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{38}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{41}
 }
 
 func (x *PermitStepInput) GetModule() string {
@@ -2869,13 +3353,16 @@ type ScopeDeclaration struct {
 	OwnerPlugin   string                 `protobuf:"bytes,6,opt,name=owner_plugin,json=ownerPlugin,proto3" json:"owner_plugin,omitempty"`
 	OwnerModule   string                 `protobuf:"bytes,7,opt,name=owner_module,json=ownerModule,proto3" json:"owner_module,omitempty"`
 	Category      string                 `protobuf:"bytes,8,opt,name=category,proto3" json:"category,omitempty"`
+	Deprecated    bool                   `protobuf:"varint,9,opt,name=deprecated,proto3" json:"deprecated,omitempty"`
+	ReplacedBy    string                 `protobuf:"bytes,10,opt,name=replaced_by,json=replacedBy,proto3" json:"replaced_by,omitempty"`
+	Version       string                 `protobuf:"bytes,11,opt,name=version,proto3" json:"version,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ScopeDeclaration) Reset() {
 	*x = ScopeDeclaration{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[39]
+	mi := &file_internal_contracts_authz_proto_msgTypes[42]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2887,7 +3374,7 @@ func (x *ScopeDeclaration) String() string {
 func (*ScopeDeclaration) ProtoMessage() {}
 
 func (x *ScopeDeclaration) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[39]
+	mi := &file_internal_contracts_authz_proto_msgTypes[42]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2900,7 +3387,7 @@ func (x *ScopeDeclaration) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ScopeDeclaration.ProtoReflect.Descriptor instead.
 func (*ScopeDeclaration) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{39}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{42}
 }
 
 func (x *ScopeDeclaration) GetName() string {
@@ -2959,18 +3446,44 @@ func (x *ScopeDeclaration) GetCategory() string {
 	return ""
 }
 
+func (x *ScopeDeclaration) GetDeprecated() bool {
+	if x != nil {
+		return x.Deprecated
+	}
+	return false
+}
+
+func (x *ScopeDeclaration) GetReplacedBy() string {
+	if x != nil {
+		return x.ReplacedBy
+	}
+	return ""
+}
+
+func (x *ScopeDeclaration) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
 type ScopeCatalogConfig struct {
 	state                    protoimpl.MessageState `protogen:"open.v1"`
 	Scopes                   []*ScopeDeclaration    `protobuf:"bytes,1,rep,name=scopes,proto3" json:"scopes,omitempty"`
 	AllowRuntimeRegistration bool                   `protobuf:"varint,2,opt,name=allow_runtime_registration,json=allowRuntimeRegistration,proto3" json:"allow_runtime_registration,omitempty"`
 	Declarations             *AuthzDeclarationSet   `protobuf:"bytes,3,opt,name=declarations,proto3" json:"declarations,omitempty"`
+	ProjectionSigningKey     string                 `protobuf:"bytes,4,opt,name=projection_signing_key,json=projectionSigningKey,proto3" json:"projection_signing_key,omitempty"`
+	ProjectionTtl            string                 `protobuf:"bytes,5,opt,name=projection_ttl,json=projectionTtl,proto3" json:"projection_ttl,omitempty"`
+	OwnershipConflicts       string                 `protobuf:"bytes,6,opt,name=ownership_conflicts,json=ownershipConflicts,proto3" json:"ownership_conflicts,omitempty"`
+	Adapter                  *AdapterConfig         `protobuf:"bytes,7,opt,name=adapter,proto3" json:"adapter,omitempty"`
+	Watcher                  *WatcherConfig         `protobuf:"bytes,8,opt,name=watcher,proto3" json:"watcher,omitempty"`
 	unknownFields            protoimpl.UnknownFields
 	sizeCache                protoimpl.SizeCache
 }
 
 func (x *ScopeCatalogConfig) Reset() {
 	*x = ScopeCatalogConfig{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[40]
+	mi := &file_internal_contracts_authz_proto_msgTypes[43]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2982,7 +3495,7 @@ func (x *ScopeCatalogConfig) String() string {
 func (*ScopeCatalogConfig) ProtoMessage() {}
 
 func (x *ScopeCatalogConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[40]
+	mi := &file_internal_contracts_authz_proto_msgTypes[43]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2995,7 +3508,7 @@ func (x *ScopeCatalogConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ScopeCatalogConfig.ProtoReflect.Descriptor instead.
 func (*ScopeCatalogConfig) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{40}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{43}
 }
 
 func (x *ScopeCatalogConfig) GetScopes() []*ScopeDeclaration {
@@ -3019,6 +3532,41 @@ func (x *ScopeCatalogConfig) GetDeclarations() *AuthzDeclarationSet {
 	return nil
 }
 
+func (x *ScopeCatalogConfig) GetProjectionSigningKey() string {
+	if x != nil {
+		return x.ProjectionSigningKey
+	}
+	return ""
+}
+
+func (x *ScopeCatalogConfig) GetProjectionTtl() string {
+	if x != nil {
+		return x.ProjectionTtl
+	}
+	return ""
+}
+
+func (x *ScopeCatalogConfig) GetOwnershipConflicts() string {
+	if x != nil {
+		return x.OwnershipConflicts
+	}
+	return ""
+}
+
+func (x *ScopeCatalogConfig) GetAdapter() *AdapterConfig {
+	if x != nil {
+		return x.Adapter
+	}
+	return nil
+}
+
+func (x *ScopeCatalogConfig) GetWatcher() *WatcherConfig {
+	if x != nil {
+		return x.Watcher
+	}
+	return nil
+}
+
 type RegisterScopesInput struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Scopes        []*ScopeDeclaration    `protobuf:"bytes,1,rep,name=scopes,proto3" json:"scopes,omitempty"`
@@ -3030,7 +3578,7 @@ type RegisterScopesInput struct {
 
 func (x *RegisterScopesInput) Reset() {
 	*x = RegisterScopesInput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[41]
+	mi := &file_internal_contracts_authz_proto_msgTypes[44]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3042,7 +3590,7 @@ func (x *RegisterScopesInput) String() string {
 func (*RegisterScopesInput) ProtoMessage() {}
 
 func (x *RegisterScopesInput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[41]
+	mi := &file_internal_contracts_authz_proto_msgTypes[44]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3055,7 +3603,7 @@ func (x *RegisterScopesInput) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RegisterScopesInput.ProtoReflect.Descriptor instead.
 func (*RegisterScopesInput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{41}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{44}
 }
 
 func (x *RegisterScopesInput) GetScopes() []*ScopeDeclaration {
@@ -3080,17 +3628,19 @@ func (x *RegisterScopesInput) GetOwnerModule() string {
 }
 
 type RegisterScopesOutput struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Registered    int32                  `protobuf:"varint,1,opt,name=registered,proto3" json:"registered,omitempty"`
-	Scopes        []*ScopeDeclaration    `protobuf:"bytes,2,rep,name=scopes,proto3" json:"scopes,omitempty"`
-	Error         string                 `protobuf:"bytes,100,opt,name=error,proto3" json:"error,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Registered       int32                  `protobuf:"varint,1,opt,name=registered,proto3" json:"registered,omitempty"`
+	Scopes           []*ScopeDeclaration    `protobuf:"bytes,2,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	Conflicts        []*DeclarationConflict `protobuf:"bytes,3,rep,name=conflicts,proto3" json:"conflicts,omitempty"`
+	SubscriberErrors []string               `protobuf:"bytes,4,rep,name=subscriber_errors,json=subscriberErrors,proto3" json:"subscriber_errors,omitempty"`
+	Error            string                 `protobuf:"bytes,100,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
 func (x *RegisterScopesOutput) Reset() {
 	*x = RegisterScopesOutput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[42]
+	mi := &file_internal_contracts_authz_proto_msgTypes[45]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3102,7 +3652,7 @@ func (x *RegisterScopesOutput) String() string {
 func (*RegisterScopesOutput) ProtoMessage() {}
 
 func (x *RegisterScopesOutput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[42]
+	mi := &file_internal_contracts_authz_proto_msgTypes[45]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3115,7 +3665,7 @@ func (x *RegisterScopesOutput) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RegisterScopesOutput.ProtoReflect.Descriptor instead.
 func (*RegisterScopesOutput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{42}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{45}
 }
 
 func (x *RegisterScopesOutput) GetRegistered() int32 {
@@ -3132,6 +3682,20 @@ func (x *RegisterScopesOutput) GetScopes() []*ScopeDeclaration {
 	return nil
 }
 
+func (x *RegisterScopesOutput) GetConflicts() []*DeclarationConflict {
+	if x != nil {
+		return x.Conflicts
+	}
+	return nil
+}
+
+func (x *RegisterScopesOutput) GetSubscriberErrors() []string {
+	if x != nil {
+		return x.SubscriberErrors
+	}
+	return nil
+}
+
 func (x *RegisterScopesOutput) GetError() string {
 	if x != nil {
 		return x.Error
@@ -3150,7 +3714,7 @@ type ListScopesInput struct {
 
 func (x *ListScopesInput) Reset() {
 	*x = ListScopesInput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[43]
+	mi := &file_internal_contracts_authz_proto_msgTypes[46]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3162,7 +3726,7 @@ func (x *ListScopesInput) String() string {
 func (*ListScopesInput) ProtoMessage() {}
 
 func (x *ListScopesInput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[43]
+	mi := &file_internal_contracts_authz_proto_msgTypes[46]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3175,7 +3739,7 @@ func (x *ListScopesInput) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListScopesInput.ProtoReflect.Descriptor instead.
 func (*ListScopesInput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{43}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{46}
 }
 
 func (x *ListScopesInput) GetContext() string {
@@ -3209,7 +3773,7 @@ type ListScopesOutput struct {
 
 func (x *ListScopesOutput) Reset() {
 	*x = ListScopesOutput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[44]
+	mi := &file_internal_contracts_authz_proto_msgTypes[47]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3221,7 +3785,7 @@ func (x *ListScopesOutput) String() string {
 func (*ListScopesOutput) ProtoMessage() {}
 
 func (x *ListScopesOutput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[44]
+	mi := &file_internal_contracts_authz_proto_msgTypes[47]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3234,7 +3798,7 @@ func (x *ListScopesOutput) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListScopesOutput.ProtoReflect.Descriptor instead.
 func (*ListScopesOutput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{44}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{47}
 }
 
 func (x *ListScopesOutput) GetScopes() []*ScopeDeclaration {
@@ -3261,13 +3825,16 @@ type ResourceDeclaration struct {
 	OwnerModule    string                 `protobuf:"bytes,6,opt,name=owner_module,json=ownerModule,proto3" json:"owner_module,omitempty"`
 	Category       string                 `protobuf:"bytes,7,opt,name=category,proto3" json:"category,omitempty"`
 	LookupSourceId string                 `protobuf:"bytes,8,opt,name=lookup_source_id,json=lookupSourceId,proto3" json:"lookup_source_id,omitempty"`
+	Deprecated     bool                   `protobuf:"varint,9,opt,name=deprecated,proto3" json:"deprecated,omitempty"`
+	ReplacedBy     string                 `protobuf:"bytes,10,opt,name=replaced_by,json=replacedBy,proto3" json:"replaced_by,omitempty"`
+	Version        string                 `protobuf:"bytes,11,opt,name=version,proto3" json:"version,omitempty"`
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
 }
 
 func (x *ResourceDeclaration) Reset() {
 	*x = ResourceDeclaration{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[45]
+	mi := &file_internal_contracts_authz_proto_msgTypes[48]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3279,7 +3846,7 @@ func (x *ResourceDeclaration) String() string {
 func (*ResourceDeclaration) ProtoMessage() {}
 
 func (x *ResourceDeclaration) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[45]
+	mi := &file_internal_contracts_authz_proto_msgTypes[48]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3292,7 +3859,7 @@ func (x *ResourceDeclaration) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ResourceDeclaration.ProtoReflect.Descriptor instead.
 func (*ResourceDeclaration) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{45}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{48}
 }
 
 func (x *ResourceDeclaration) GetName() string {
@@ -3351,6 +3918,27 @@ func (x *ResourceDeclaration) GetLookupSourceId() string {
 	return ""
 }
 
+func (x *ResourceDeclaration) GetDeprecated() bool {
+	if x != nil {
+		return x.Deprecated
+	}
+	return false
+}
+
+func (x *ResourceDeclaration) GetReplacedBy() string {
+	if x != nil {
+		return x.ReplacedBy
+	}
+	return ""
+}
+
+func (x *ResourceDeclaration) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
 type ActionDeclaration struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
@@ -3366,7 +3954,7 @@ type ActionDeclaration struct {
 
 func (x *ActionDeclaration) Reset() {
 	*x = ActionDeclaration{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[46]
+	mi := &file_internal_contracts_authz_proto_msgTypes[49]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3378,7 +3966,7 @@ func (x *ActionDeclaration) String() string {
 func (*ActionDeclaration) ProtoMessage() {}
 
 func (x *ActionDeclaration) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[46]
+	mi := &file_internal_contracts_authz_proto_msgTypes[49]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3391,7 +3979,7 @@ func (x *ActionDeclaration) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ActionDeclaration.ProtoReflect.Descriptor instead.
 func (*ActionDeclaration) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{46}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{49}
 }
 
 func (x *ActionDeclaration) GetName() string {
@@ -3453,7 +4041,7 @@ type AttributeValue struct {
 
 func (x *AttributeValue) Reset() {
 	*x = AttributeValue{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[47]
+	mi := &file_internal_contracts_authz_proto_msgTypes[50]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3465,7 +4053,7 @@ func (x *AttributeValue) String() string {
 func (*AttributeValue) ProtoMessage() {}
 
 func (x *AttributeValue) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[47]
+	mi := &file_internal_contracts_authz_proto_msgTypes[50]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3478,7 +4066,7 @@ func (x *AttributeValue) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AttributeValue.ProtoReflect.Descriptor instead.
 func (*AttributeValue) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{47}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{50}
 }
 
 func (x *AttributeValue) GetValue() string {
@@ -3513,7 +4101,7 @@ type AttributeDeclaration struct {
 
 func (x *AttributeDeclaration) Reset() {
 	*x = AttributeDeclaration{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[48]
+	mi := &file_internal_contracts_authz_proto_msgTypes[51]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3525,7 +4113,7 @@ func (x *AttributeDeclaration) String() string {
 func (*AttributeDeclaration) ProtoMessage() {}
 
 func (x *AttributeDeclaration) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[48]
+	mi := &file_internal_contracts_authz_proto_msgTypes[51]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3538,7 +4126,7 @@ func (x *AttributeDeclaration) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AttributeDeclaration.ProtoReflect.Descriptor instead.
 func (*AttributeDeclaration) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{48}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{51}
 }
 
 func (x *AttributeDeclaration) GetName() string {
@@ -3623,7 +4211,7 @@ type AttributeCondition struct {
 
 func (x *AttributeCondition) Reset() {
 	*x = AttributeCondition{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[49]
+	mi := &file_internal_contracts_authz_proto_msgTypes[52]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3635,7 +4223,7 @@ func (x *AttributeCondition) String() string {
 func (*AttributeCondition) ProtoMessage() {}
 
 func (x *AttributeCondition) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[49]
+	mi := &file_internal_contracts_authz_proto_msgTypes[52]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3648,7 +4236,7 @@ func (x *AttributeCondition) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AttributeCondition.ProtoReflect.Descriptor instead.
 func (*AttributeCondition) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{49}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{52}
 }
 
 func (x *AttributeCondition) GetTarget() string {
@@ -3696,7 +4284,7 @@ type AttributePolicy struct {
 
 func (x *AttributePolicy) Reset() {
 	*x = AttributePolicy{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[50]
+	mi := &file_internal_contracts_authz_proto_msgTypes[53]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3708,7 +4296,7 @@ func (x *AttributePolicy) String() string {
 func (*AttributePolicy) ProtoMessage() {}
 
 func (x *AttributePolicy) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[50]
+	mi := &file_internal_contracts_authz_proto_msgTypes[53]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3721,7 +4309,7 @@ func (x *AttributePolicy) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AttributePolicy.ProtoReflect.Descriptor instead.
 func (*AttributePolicy) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{50}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{53}
 }
 
 func (x *AttributePolicy) GetId() string {
@@ -3801,7 +4389,7 @@ type AttributePolicyFilter struct {
 
 func (x *AttributePolicyFilter) Reset() {
 	*x = AttributePolicyFilter{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[51]
+	mi := &file_internal_contracts_authz_proto_msgTypes[54]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3813,7 +4401,7 @@ func (x *AttributePolicyFilter) String() string {
 func (*AttributePolicyFilter) ProtoMessage() {}
 
 func (x *AttributePolicyFilter) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[51]
+	mi := &file_internal_contracts_authz_proto_msgTypes[54]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3826,7 +4414,7 @@ func (x *AttributePolicyFilter) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AttributePolicyFilter.ProtoReflect.Descriptor instead.
 func (*AttributePolicyFilter) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{51}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{54}
 }
 
 func (x *AttributePolicyFilter) GetId() string {
@@ -3886,7 +4474,7 @@ type AttributeCheckInput struct {
 
 func (x *AttributeCheckInput) Reset() {
 	*x = AttributeCheckInput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[52]
+	mi := &file_internal_contracts_authz_proto_msgTypes[55]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3898,7 +4486,7 @@ func (x *AttributeCheckInput) String() string {
 func (*AttributeCheckInput) ProtoMessage() {}
 
 func (x *AttributeCheckInput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[52]
+	mi := &file_internal_contracts_authz_proto_msgTypes[55]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3911,7 +4499,7 @@ func (x *AttributeCheckInput) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AttributeCheckInput.ProtoReflect.Descriptor instead.
 func (*AttributeCheckInput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{52}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{55}
 }
 
 func (x *AttributeCheckInput) GetSubject() string {
@@ -3979,7 +4567,7 @@ type AttributeCheckOutput struct {
 
 func (x *AttributeCheckOutput) Reset() {
 	*x = AttributeCheckOutput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[53]
+	mi := &file_internal_contracts_authz_proto_msgTypes[56]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3991,7 +4579,7 @@ func (x *AttributeCheckOutput) String() string {
 func (*AttributeCheckOutput) ProtoMessage() {}
 
 func (x *AttributeCheckOutput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[53]
+	mi := &file_internal_contracts_authz_proto_msgTypes[56]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4004,7 +4592,7 @@ func (x *AttributeCheckOutput) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AttributeCheckOutput.ProtoReflect.Descriptor instead.
 func (*AttributeCheckOutput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{53}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{56}
 }
 
 func (x *AttributeCheckOutput) GetAllowed() bool {
@@ -4074,7 +4662,7 @@ type DeclareAttributesInput struct {
 
 func (x *DeclareAttributesInput) Reset() {
 	*x = DeclareAttributesInput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[54]
+	mi := &file_internal_contracts_authz_proto_msgTypes[57]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4086,7 +4674,7 @@ func (x *DeclareAttributesInput) String() string {
 func (*DeclareAttributesInput) ProtoMessage() {}
 
 func (x *DeclareAttributesInput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[54]
+	mi := &file_internal_contracts_authz_proto_msgTypes[57]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4099,7 +4687,7 @@ func (x *DeclareAttributesInput) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeclareAttributesInput.ProtoReflect.Descriptor instead.
 func (*DeclareAttributesInput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{54}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{57}
 }
 
 func (x *DeclareAttributesInput) GetAttributes() []*AttributeDeclaration {
@@ -4134,7 +4722,7 @@ type DeclareAttributesOutput struct {
 
 func (x *DeclareAttributesOutput) Reset() {
 	*x = DeclareAttributesOutput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[55]
+	mi := &file_internal_contracts_authz_proto_msgTypes[58]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4146,7 +4734,7 @@ func (x *DeclareAttributesOutput) String() string {
 func (*DeclareAttributesOutput) ProtoMessage() {}
 
 func (x *DeclareAttributesOutput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[55]
+	mi := &file_internal_contracts_authz_proto_msgTypes[58]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4159,7 +4747,7 @@ func (x *DeclareAttributesOutput) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeclareAttributesOutput.ProtoReflect.Descriptor instead.
 func (*DeclareAttributesOutput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{55}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{58}
 }
 
 func (x *DeclareAttributesOutput) GetRegistered() int32 {
@@ -4192,7 +4780,7 @@ type UpsertAttributePolicyInput struct {
 
 func (x *UpsertAttributePolicyInput) Reset() {
 	*x = UpsertAttributePolicyInput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[56]
+	mi := &file_internal_contracts_authz_proto_msgTypes[59]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4204,7 +4792,7 @@ func (x *UpsertAttributePolicyInput) String() string {
 func (*UpsertAttributePolicyInput) ProtoMessage() {}
 
 func (x *UpsertAttributePolicyInput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[56]
+	mi := &file_internal_contracts_authz_proto_msgTypes[59]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4217,7 +4805,7 @@ func (x *UpsertAttributePolicyInput) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpsertAttributePolicyInput.ProtoReflect.Descriptor instead.
 func (*UpsertAttributePolicyInput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{56}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{59}
 }
 
 func (x *UpsertAttributePolicyInput) GetPolicy() *AttributePolicy {
@@ -4238,7 +4826,7 @@ type UpsertAttributePolicyOutput struct {
 
 func (x *UpsertAttributePolicyOutput) Reset() {
 	*x = UpsertAttributePolicyOutput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[57]
+	mi := &file_internal_contracts_authz_proto_msgTypes[60]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4250,7 +4838,7 @@ func (x *UpsertAttributePolicyOutput) String() string {
 func (*UpsertAttributePolicyOutput) ProtoMessage() {}
 
 func (x *UpsertAttributePolicyOutput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[57]
+	mi := &file_internal_contracts_authz_proto_msgTypes[60]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4263,7 +4851,7 @@ func (x *UpsertAttributePolicyOutput) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpsertAttributePolicyOutput.ProtoReflect.Descriptor instead.
 func (*UpsertAttributePolicyOutput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{57}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{60}
 }
 
 func (x *UpsertAttributePolicyOutput) GetChanged() bool {
@@ -4296,7 +4884,7 @@ type ListAttributePoliciesInput struct {
 
 func (x *ListAttributePoliciesInput) Reset() {
 	*x = ListAttributePoliciesInput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[58]
+	mi := &file_internal_contracts_authz_proto_msgTypes[61]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4308,7 +4896,7 @@ func (x *ListAttributePoliciesInput) String() string {
 func (*ListAttributePoliciesInput) ProtoMessage() {}
 
 func (x *ListAttributePoliciesInput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[58]
+	mi := &file_internal_contracts_authz_proto_msgTypes[61]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4321,7 +4909,7 @@ func (x *ListAttributePoliciesInput) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListAttributePoliciesInput.ProtoReflect.Descriptor instead.
 func (*ListAttributePoliciesInput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{58}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{61}
 }
 
 func (x *ListAttributePoliciesInput) GetFilter() *AttributePolicyFilter {
@@ -4341,7 +4929,7 @@ type ListAttributePoliciesOutput struct {
 
 func (x *ListAttributePoliciesOutput) Reset() {
 	*x = ListAttributePoliciesOutput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[59]
+	mi := &file_internal_contracts_authz_proto_msgTypes[62]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4353,7 +4941,7 @@ func (x *ListAttributePoliciesOutput) String() string {
 func (*ListAttributePoliciesOutput) ProtoMessage() {}
 
 func (x *ListAttributePoliciesOutput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[59]
+	mi := &file_internal_contracts_authz_proto_msgTypes[62]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4366,7 +4954,7 @@ func (x *ListAttributePoliciesOutput) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListAttributePoliciesOutput.ProtoReflect.Descriptor instead.
 func (*ListAttributePoliciesOutput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{59}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{62}
 }
 
 func (x *ListAttributePoliciesOutput) GetPolicies() []*AttributePolicy {
@@ -4392,7 +4980,7 @@ type RemoveAttributePolicyInput struct {
 
 func (x *RemoveAttributePolicyInput) Reset() {
 	*x = RemoveAttributePolicyInput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[60]
+	mi := &file_internal_contracts_authz_proto_msgTypes[63]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4404,7 +4992,7 @@ func (x *RemoveAttributePolicyInput) String() string {
 func (*RemoveAttributePolicyInput) ProtoMessage() {}
 
 func (x *RemoveAttributePolicyInput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[60]
+	mi := &file_internal_contracts_authz_proto_msgTypes[63]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4417,7 +5005,7 @@ func (x *RemoveAttributePolicyInput) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RemoveAttributePolicyInput.ProtoReflect.Descriptor instead.
 func (*RemoveAttributePolicyInput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{60}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{63}
 }
 
 func (x *RemoveAttributePolicyInput) GetFilter() *AttributePolicyFilter {
@@ -4437,7 +5025,7 @@ type RemoveAttributePolicyOutput struct {
 
 func (x *RemoveAttributePolicyOutput) Reset() {
 	*x = RemoveAttributePolicyOutput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[61]
+	mi := &file_internal_contracts_authz_proto_msgTypes[64]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4449,7 +5037,7 @@ func (x *RemoveAttributePolicyOutput) String() string {
 func (*RemoveAttributePolicyOutput) ProtoMessage() {}
 
 func (x *RemoveAttributePolicyOutput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[61]
+	mi := &file_internal_contracts_authz_proto_msgTypes[64]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4462,7 +5050,7 @@ func (x *RemoveAttributePolicyOutput) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RemoveAttributePolicyOutput.ProtoReflect.Descriptor instead.
 func (*RemoveAttributePolicyOutput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{61}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{64}
 }
 
 func (x *RemoveAttributePolicyOutput) GetChanged() bool {
@@ -4495,7 +5083,7 @@ type RelationDeclaration struct {
 
 func (x *RelationDeclaration) Reset() {
 	*x = RelationDeclaration{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[62]
+	mi := &file_internal_contracts_authz_proto_msgTypes[65]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4507,7 +5095,7 @@ func (x *RelationDeclaration) String() string {
 func (*RelationDeclaration) ProtoMessage() {}
 
 func (x *RelationDeclaration) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[62]
+	mi := &file_internal_contracts_authz_proto_msgTypes[65]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4520,7 +5108,7 @@ func (x *RelationDeclaration) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RelationDeclaration.ProtoReflect.Descriptor instead.
 func (*RelationDeclaration) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{62}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{65}
 }
 
 func (x *RelationDeclaration) GetName() string {
@@ -4591,7 +5179,7 @@ type RelationTuple struct {
 
 func (x *RelationTuple) Reset() {
 	*x = RelationTuple{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[63]
+	mi := &file_internal_contracts_authz_proto_msgTypes[66]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4603,7 +5191,7 @@ func (x *RelationTuple) String() string {
 func (*RelationTuple) ProtoMessage() {}
 
 func (x *RelationTuple) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[63]
+	mi := &file_internal_contracts_authz_proto_msgTypes[66]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4616,7 +5204,7 @@ func (x *RelationTuple) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RelationTuple.ProtoReflect.Descriptor instead.
 func (*RelationTuple) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{63}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{66}
 }
 
 func (x *RelationTuple) GetSubject() string {
@@ -4659,7 +5247,7 @@ type RelationTupleFilter struct {
 
 func (x *RelationTupleFilter) Reset() {
 	*x = RelationTupleFilter{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[64]
+	mi := &file_internal_contracts_authz_proto_msgTypes[67]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4671,7 +5259,7 @@ func (x *RelationTupleFilter) String() string {
 func (*RelationTupleFilter) ProtoMessage() {}
 
 func (x *RelationTupleFilter) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[64]
+	mi := &file_internal_contracts_authz_proto_msgTypes[67]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4684,7 +5272,7 @@ func (x *RelationTupleFilter) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RelationTupleFilter.ProtoReflect.Descriptor instead.
 func (*RelationTupleFilter) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{64}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{67}
 }
 
 func (x *RelationTupleFilter) GetSubject() string {
@@ -4727,7 +5315,7 @@ type RelationCheckInput struct {
 
 func (x *RelationCheckInput) Reset() {
 	*x = RelationCheckInput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[65]
+	mi := &file_internal_contracts_authz_proto_msgTypes[68]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4739,7 +5327,7 @@ func (x *RelationCheckInput) String() string {
 func (*RelationCheckInput) ProtoMessage() {}
 
 func (x *RelationCheckInput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[65]
+	mi := &file_internal_contracts_authz_proto_msgTypes[68]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4752,7 +5340,7 @@ func (x *RelationCheckInput) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RelationCheckInput.ProtoReflect.Descriptor instead.
 func (*RelationCheckInput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{65}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{68}
 }
 
 func (x *RelationCheckInput) GetSubject() string {
@@ -4798,7 +5386,7 @@ type RelationCheckOutput struct {
 
 func (x *RelationCheckOutput) Reset() {
 	*x = RelationCheckOutput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[66]
+	mi := &file_internal_contracts_authz_proto_msgTypes[69]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4810,7 +5398,7 @@ func (x *RelationCheckOutput) String() string {
 func (*RelationCheckOutput) ProtoMessage() {}
 
 func (x *RelationCheckOutput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[66]
+	mi := &file_internal_contracts_authz_proto_msgTypes[69]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4823,7 +5411,7 @@ func (x *RelationCheckOutput) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RelationCheckOutput.ProtoReflect.Descriptor instead.
 func (*RelationCheckOutput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{66}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{69}
 }
 
 func (x *RelationCheckOutput) GetAllowed() bool {
@@ -4884,7 +5472,7 @@ type UpsertRelationTupleInput struct {
 
 func (x *UpsertRelationTupleInput) Reset() {
 	*x = UpsertRelationTupleInput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[67]
+	mi := &file_internal_contracts_authz_proto_msgTypes[70]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4896,7 +5484,7 @@ func (x *UpsertRelationTupleInput) String() string {
 func (*UpsertRelationTupleInput) ProtoMessage() {}
 
 func (x *UpsertRelationTupleInput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[67]
+	mi := &file_internal_contracts_authz_proto_msgTypes[70]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4909,7 +5497,7 @@ func (x *UpsertRelationTupleInput) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpsertRelationTupleInput.ProtoReflect.Descriptor instead.
 func (*UpsertRelationTupleInput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{67}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{70}
 }
 
 func (x *UpsertRelationTupleInput) GetTuple() *RelationTuple {
@@ -4930,7 +5518,7 @@ type UpsertRelationTupleOutput struct {
 
 func (x *UpsertRelationTupleOutput) Reset() {
 	*x = UpsertRelationTupleOutput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[68]
+	mi := &file_internal_contracts_authz_proto_msgTypes[71]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4942,7 +5530,7 @@ func (x *UpsertRelationTupleOutput) String() string {
 func (*UpsertRelationTupleOutput) ProtoMessage() {}
 
 func (x *UpsertRelationTupleOutput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[68]
+	mi := &file_internal_contracts_authz_proto_msgTypes[71]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4955,7 +5543,7 @@ func (x *UpsertRelationTupleOutput) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpsertRelationTupleOutput.ProtoReflect.Descriptor instead.
 func (*UpsertRelationTupleOutput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{68}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{71}
 }
 
 func (x *UpsertRelationTupleOutput) GetChanged() bool {
@@ -4988,7 +5576,7 @@ type ListRelationTuplesInput struct {
 
 func (x *ListRelationTuplesInput) Reset() {
 	*x = ListRelationTuplesInput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[69]
+	mi := &file_internal_contracts_authz_proto_msgTypes[72]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -5000,7 +5588,7 @@ func (x *ListRelationTuplesInput) String() string {
 func (*ListRelationTuplesInput) ProtoMessage() {}
 
 func (x *ListRelationTuplesInput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[69]
+	mi := &file_internal_contracts_authz_proto_msgTypes[72]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5013,7 +5601,7 @@ func (x *ListRelationTuplesInput) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListRelationTuplesInput.ProtoReflect.Descriptor instead.
 func (*ListRelationTuplesInput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{69}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{72}
 }
 
 func (x *ListRelationTuplesInput) GetFilter() *RelationTupleFilter {
@@ -5033,7 +5621,7 @@ type ListRelationTuplesOutput struct {
 
 func (x *ListRelationTuplesOutput) Reset() {
 	*x = ListRelationTuplesOutput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[70]
+	mi := &file_internal_contracts_authz_proto_msgTypes[73]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -5045,7 +5633,7 @@ func (x *ListRelationTuplesOutput) String() string {
 func (*ListRelationTuplesOutput) ProtoMessage() {}
 
 func (x *ListRelationTuplesOutput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[70]
+	mi := &file_internal_contracts_authz_proto_msgTypes[73]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5058,7 +5646,7 @@ func (x *ListRelationTuplesOutput) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListRelationTuplesOutput.ProtoReflect.Descriptor instead.
 func (*ListRelationTuplesOutput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{70}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{73}
 }
 
 func (x *ListRelationTuplesOutput) GetTuples() []*RelationTuple {
@@ -5084,7 +5672,7 @@ type RemoveRelationTupleInput struct {
 
 func (x *RemoveRelationTupleInput) Reset() {
 	*x = RemoveRelationTupleInput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[71]
+	mi := &file_internal_contracts_authz_proto_msgTypes[74]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -5096,7 +5684,7 @@ func (x *RemoveRelationTupleInput) String() string {
 func (*RemoveRelationTupleInput) ProtoMessage() {}
 
 func (x *RemoveRelationTupleInput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[71]
+	mi := &file_internal_contracts_authz_proto_msgTypes[74]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5109,7 +5697,7 @@ func (x *RemoveRelationTupleInput) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RemoveRelationTupleInput.ProtoReflect.Descriptor instead.
 func (*RemoveRelationTupleInput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{71}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{74}
 }
 
 func (x *RemoveRelationTupleInput) GetTuple() *RelationTuple {
@@ -5129,7 +5717,7 @@ type RemoveRelationTupleOutput struct {
 
 func (x *RemoveRelationTupleOutput) Reset() {
 	*x = RemoveRelationTupleOutput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[72]
+	mi := &file_internal_contracts_authz_proto_msgTypes[75]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -5141,7 +5729,7 @@ func (x *RemoveRelationTupleOutput) String() string {
 func (*RemoveRelationTupleOutput) ProtoMessage() {}
 
 func (x *RemoveRelationTupleOutput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[72]
+	mi := &file_internal_contracts_authz_proto_msgTypes[75]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5154,7 +5742,7 @@ func (x *RemoveRelationTupleOutput) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RemoveRelationTupleOutput.ProtoReflect.Descriptor instead.
 func (*RemoveRelationTupleOutput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{72}
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{75}
 }
 
 func (x *RemoveRelationTupleOutput) GetChanged() bool {
@@ -5171,37 +5759,31 @@ func (x *RemoveRelationTupleOutput) GetError() string {
 	return ""
 }
 
-type UIActionDeclaration struct {
-	state                protoimpl.MessageState   `protogen:"open.v1"`
-	Id                   string                   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Context              string                   `protobuf:"bytes,2,opt,name=context,proto3" json:"context,omitempty"`
-	Label                string                   `protobuf:"bytes,3,opt,name=label,proto3" json:"label,omitempty"`
-	Route                string                   `protobuf:"bytes,4,opt,name=route,proto3" json:"route,omitempty"`
-	RequiredScopes       []string                 `protobuf:"bytes,5,rep,name=required_scopes,json=requiredScopes,proto3" json:"required_scopes,omitempty"`
-	RequiredCapabilities []*CapabilityRequirement `protobuf:"bytes,6,rep,name=required_capabilities,json=requiredCapabilities,proto3" json:"required_capabilities,omitempty"`
-	Description          string                   `protobuf:"bytes,7,opt,name=description,proto3" json:"description,omitempty"`
-	OwnerPlugin          string                   `protobuf:"bytes,8,opt,name=owner_plugin,json=ownerPlugin,proto3" json:"owner_plugin,omitempty"`
-	OwnerModule          string                   `protobuf:"bytes,9,opt,name=owner_module,json=ownerModule,proto3" json:"owner_module,omitempty"`
-	Category             string                   `protobuf:"bytes,10,opt,name=category,proto3" json:"category,omitempty"`
-	unknownFields        protoimpl.UnknownFields
-	sizeCache            protoimpl.SizeCache
+type ExpandRelationInput struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Relation      string                 `protobuf:"bytes,1,opt,name=relation,proto3" json:"relation,omitempty"`
+	Object        string                 `protobuf:"bytes,2,opt,name=object,proto3" json:"object,omitempty"`
+	Context       string                 `protobuf:"bytes,3,opt,name=context,proto3" json:"context,omitempty"`
+	MaxDepth      int32                  `protobuf:"varint,4,opt,name=max_depth,json=maxDepth,proto3" json:"max_depth,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UIActionDeclaration) Reset() {
-	*x = UIActionDeclaration{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[73]
+func (x *ExpandRelationInput) Reset() {
+	*x = ExpandRelationInput{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[76]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UIActionDeclaration) String() string {
+func (x *ExpandRelationInput) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UIActionDeclaration) ProtoMessage() {}
+func (*ExpandRelationInput) ProtoMessage() {}
 
-func (x *UIActionDeclaration) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[73]
+func (x *ExpandRelationInput) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[76]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5212,110 +5794,122 @@ func (x *UIActionDeclaration) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UIActionDeclaration.ProtoReflect.Descriptor instead.
-func (*UIActionDeclaration) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{73}
+// Deprecated: Use ExpandRelationInput.ProtoReflect.Descriptor instead.
+func (*ExpandRelationInput) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{76}
 }
 
-func (x *UIActionDeclaration) GetId() string {
+func (x *ExpandRelationInput) GetRelation() string {
 	if x != nil {
-		return x.Id
+		return x.Relation
 	}
 	return ""
 }
 
-func (x *UIActionDeclaration) GetContext() string {
+func (x *ExpandRelationInput) GetObject() string {
 	if x != nil {
-		return x.Context
+		return x.Object
 	}
 	return ""
 }
 
-func (x *UIActionDeclaration) GetLabel() string {
+func (x *ExpandRelationInput) GetContext() string {
 	if x != nil {
-		return x.Label
+		return x.Context
 	}
 	return ""
 }
 
-func (x *UIActionDeclaration) GetRoute() string {
+func (x *ExpandRelationInput) GetMaxDepth() int32 {
 	if x != nil {
-		return x.Route
+		return x.MaxDepth
 	}
-	return ""
+	return 0
 }
 
-func (x *UIActionDeclaration) GetRequiredScopes() []string {
-	if x != nil {
-		return x.RequiredScopes
-	}
-	return nil
+type UsersetTree struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Subject       string                 `protobuf:"bytes,2,opt,name=subject,proto3" json:"subject,omitempty"`
+	Children      []*UsersetTree         `protobuf:"bytes,3,rep,name=children,proto3" json:"children,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UIActionDeclaration) GetRequiredCapabilities() []*CapabilityRequirement {
-	if x != nil {
-		return x.RequiredCapabilities
-	}
-	return nil
+func (x *UsersetTree) Reset() {
+	*x = UsersetTree{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[77]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *UIActionDeclaration) GetDescription() string {
+func (x *UsersetTree) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UsersetTree) ProtoMessage() {}
+
+func (x *UsersetTree) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[77]
 	if x != nil {
-		return x.Description
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *UIActionDeclaration) GetOwnerPlugin() string {
+// Deprecated: Use UsersetTree.ProtoReflect.Descriptor instead.
+func (*UsersetTree) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{77}
+}
+
+func (x *UsersetTree) GetType() string {
 	if x != nil {
-		return x.OwnerPlugin
+		return x.Type
 	}
 	return ""
 }
 
-func (x *UIActionDeclaration) GetOwnerModule() string {
+func (x *UsersetTree) GetSubject() string {
 	if x != nil {
-		return x.OwnerModule
+		return x.Subject
 	}
 	return ""
 }
 
-func (x *UIActionDeclaration) GetCategory() string {
+func (x *UsersetTree) GetChildren() []*UsersetTree {
 	if x != nil {
-		return x.Category
+		return x.Children
 	}
-	return ""
+	return nil
 }
 
-type AuthzDeclarationSet struct {
-	state         protoimpl.MessageState  `protogen:"open.v1"`
-	Scopes        []*ScopeDeclaration     `protobuf:"bytes,1,rep,name=scopes,proto3" json:"scopes,omitempty"`
-	Resources     []*ResourceDeclaration  `protobuf:"bytes,2,rep,name=resources,proto3" json:"resources,omitempty"`
-	Actions       []*ActionDeclaration    `protobuf:"bytes,3,rep,name=actions,proto3" json:"actions,omitempty"`
-	Attributes    []*AttributeDeclaration `protobuf:"bytes,4,rep,name=attributes,proto3" json:"attributes,omitempty"`
-	Relations     []*RelationDeclaration  `protobuf:"bytes,5,rep,name=relations,proto3" json:"relations,omitempty"`
-	UiActions     []*UIActionDeclaration  `protobuf:"bytes,6,rep,name=ui_actions,json=uiActions,proto3" json:"ui_actions,omitempty"`
-	OwnerPlugin   string                  `protobuf:"bytes,7,opt,name=owner_plugin,json=ownerPlugin,proto3" json:"owner_plugin,omitempty"`
-	OwnerModule   string                  `protobuf:"bytes,8,opt,name=owner_module,json=ownerModule,proto3" json:"owner_module,omitempty"`
+type ExpandRelationOutput struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tree          *UsersetTree           `protobuf:"bytes,1,opt,name=tree,proto3" json:"tree,omitempty"`
+	Error         string                 `protobuf:"bytes,100,opt,name=error,proto3" json:"error,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AuthzDeclarationSet) Reset() {
-	*x = AuthzDeclarationSet{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[74]
+func (x *ExpandRelationOutput) Reset() {
+	*x = ExpandRelationOutput{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[78]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AuthzDeclarationSet) String() string {
+func (x *ExpandRelationOutput) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AuthzDeclarationSet) ProtoMessage() {}
+func (*ExpandRelationOutput) ProtoMessage() {}
 
-func (x *AuthzDeclarationSet) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[74]
+func (x *ExpandRelationOutput) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[78]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5326,91 +5920,85 @@ func (x *AuthzDeclarationSet) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AuthzDeclarationSet.ProtoReflect.Descriptor instead.
-func (*AuthzDeclarationSet) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{74}
+// Deprecated: Use ExpandRelationOutput.ProtoReflect.Descriptor instead.
+func (*ExpandRelationOutput) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{78}
 }
 
-func (x *AuthzDeclarationSet) GetScopes() []*ScopeDeclaration {
+func (x *ExpandRelationOutput) GetTree() *UsersetTree {
 	if x != nil {
-		return x.Scopes
+		return x.Tree
 	}
 	return nil
 }
 
-func (x *AuthzDeclarationSet) GetResources() []*ResourceDeclaration {
+func (x *ExpandRelationOutput) GetError() string {
 	if x != nil {
-		return x.Resources
+		return x.Error
 	}
-	return nil
+	return ""
 }
 
-func (x *AuthzDeclarationSet) GetActions() []*ActionDeclaration {
-	if x != nil {
-		return x.Actions
-	}
-	return nil
+type GenerateNamespacesInput struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AuthzDeclarationSet) GetAttributes() []*AttributeDeclaration {
-	if x != nil {
-		return x.Attributes
-	}
-	return nil
+func (x *GenerateNamespacesInput) Reset() {
+	*x = GenerateNamespacesInput{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[79]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *AuthzDeclarationSet) GetRelations() []*RelationDeclaration {
-	if x != nil {
-		return x.Relations
-	}
-	return nil
+func (x *GenerateNamespacesInput) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *AuthzDeclarationSet) GetUiActions() []*UIActionDeclaration {
-	if x != nil {
-		return x.UiActions
-	}
-	return nil
-}
+func (*GenerateNamespacesInput) ProtoMessage() {}
 
-func (x *AuthzDeclarationSet) GetOwnerPlugin() string {
+func (x *GenerateNamespacesInput) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[79]
 	if x != nil {
-		return x.OwnerPlugin
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *AuthzDeclarationSet) GetOwnerModule() string {
-	if x != nil {
-		return x.OwnerModule
-	}
-	return ""
+// Deprecated: Use GenerateNamespacesInput.ProtoReflect.Descriptor instead.
+func (*GenerateNamespacesInput) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{79}
 }
 
-type RegisterDeclarationsInput struct {
+type GenerateNamespacesOutput struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Declarations  *AuthzDeclarationSet   `protobuf:"bytes,1,opt,name=declarations,proto3" json:"declarations,omitempty"`
-	OwnerPlugin   string                 `protobuf:"bytes,2,opt,name=owner_plugin,json=ownerPlugin,proto3" json:"owner_plugin,omitempty"`
-	OwnerModule   string                 `protobuf:"bytes,3,opt,name=owner_module,json=ownerModule,proto3" json:"owner_module,omitempty"`
+	Opl           string                 `protobuf:"bytes,1,opt,name=opl,proto3" json:"opl,omitempty"`
+	Namespaces    []string               `protobuf:"bytes,2,rep,name=namespaces,proto3" json:"namespaces,omitempty"`
+	Error         string                 `protobuf:"bytes,100,opt,name=error,proto3" json:"error,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RegisterDeclarationsInput) Reset() {
-	*x = RegisterDeclarationsInput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[75]
+func (x *GenerateNamespacesOutput) Reset() {
+	*x = GenerateNamespacesOutput{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[80]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RegisterDeclarationsInput) String() string {
+func (x *GenerateNamespacesOutput) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RegisterDeclarationsInput) ProtoMessage() {}
+func (*GenerateNamespacesOutput) ProtoMessage() {}
 
-func (x *RegisterDeclarationsInput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[75]
+func (x *GenerateNamespacesOutput) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[80]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5421,56 +6009,177 @@ func (x *RegisterDeclarationsInput) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RegisterDeclarationsInput.ProtoReflect.Descriptor instead.
-func (*RegisterDeclarationsInput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{75}
+// Deprecated: Use GenerateNamespacesOutput.ProtoReflect.Descriptor instead.
+func (*GenerateNamespacesOutput) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{80}
 }
 
-func (x *RegisterDeclarationsInput) GetDeclarations() *AuthzDeclarationSet {
+func (x *GenerateNamespacesOutput) GetOpl() string {
 	if x != nil {
-		return x.Declarations
+		return x.Opl
+	}
+	return ""
+}
+
+func (x *GenerateNamespacesOutput) GetNamespaces() []string {
+	if x != nil {
+		return x.Namespaces
 	}
 	return nil
 }
 
-func (x *RegisterDeclarationsInput) GetOwnerPlugin() string {
+func (x *GenerateNamespacesOutput) GetError() string {
 	if x != nil {
-		return x.OwnerPlugin
+		return x.Error
 	}
 	return ""
 }
 
-func (x *RegisterDeclarationsInput) GetOwnerModule() string {
+type UIActionDeclaration struct {
+	state                protoimpl.MessageState   `protogen:"open.v1"`
+	Id                   string                   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Context              string                   `protobuf:"bytes,2,opt,name=context,proto3" json:"context,omitempty"`
+	Label                string                   `protobuf:"bytes,3,opt,name=label,proto3" json:"label,omitempty"`
+	Route                string                   `protobuf:"bytes,4,opt,name=route,proto3" json:"route,omitempty"`
+	RequiredScopes       []string                 `protobuf:"bytes,5,rep,name=required_scopes,json=requiredScopes,proto3" json:"required_scopes,omitempty"`
+	RequiredCapabilities []*CapabilityRequirement `protobuf:"bytes,6,rep,name=required_capabilities,json=requiredCapabilities,proto3" json:"required_capabilities,omitempty"`
+	Description          string                   `protobuf:"bytes,7,opt,name=description,proto3" json:"description,omitempty"`
+	OwnerPlugin          string                   `protobuf:"bytes,8,opt,name=owner_plugin,json=ownerPlugin,proto3" json:"owner_plugin,omitempty"`
+	OwnerModule          string                   `protobuf:"bytes,9,opt,name=owner_module,json=ownerModule,proto3" json:"owner_module,omitempty"`
+	Category             string                   `protobuf:"bytes,10,opt,name=category,proto3" json:"category,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *UIActionDeclaration) Reset() {
+	*x = UIActionDeclaration{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[81]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UIActionDeclaration) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UIActionDeclaration) ProtoMessage() {}
+
+func (x *UIActionDeclaration) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[81]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UIActionDeclaration.ProtoReflect.Descriptor instead.
+func (*UIActionDeclaration) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{81}
+}
+
+func (x *UIActionDeclaration) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UIActionDeclaration) GetContext() string {
+	if x != nil {
+		return x.Context
+	}
+	return ""
+}
+
+func (x *UIActionDeclaration) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *UIActionDeclaration) GetRoute() string {
+	if x != nil {
+		return x.Route
+	}
+	return ""
+}
+
+func (x *UIActionDeclaration) GetRequiredScopes() []string {
+	if x != nil {
+		return x.RequiredScopes
+	}
+	return nil
+}
+
+func (x *UIActionDeclaration) GetRequiredCapabilities() []*CapabilityRequirement {
+	if x != nil {
+		return x.RequiredCapabilities
+	}
+	return nil
+}
+
+func (x *UIActionDeclaration) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *UIActionDeclaration) GetOwnerPlugin() string {
+	if x != nil {
+		return x.OwnerPlugin
+	}
+	return ""
+}
+
+func (x *UIActionDeclaration) GetOwnerModule() string {
 	if x != nil {
 		return x.OwnerModule
 	}
 	return ""
 }
 
-type RegisterDeclarationsOutput struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Registered    int32                  `protobuf:"varint,1,opt,name=registered,proto3" json:"registered,omitempty"`
-	Declarations  *AuthzDeclarationSet   `protobuf:"bytes,2,opt,name=declarations,proto3" json:"declarations,omitempty"`
-	Error         string                 `protobuf:"bytes,100,opt,name=error,proto3" json:"error,omitempty"`
+func (x *UIActionDeclaration) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+type AuthzDeclarationSet struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	Scopes        []*ScopeDeclaration     `protobuf:"bytes,1,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	Resources     []*ResourceDeclaration  `protobuf:"bytes,2,rep,name=resources,proto3" json:"resources,omitempty"`
+	Actions       []*ActionDeclaration    `protobuf:"bytes,3,rep,name=actions,proto3" json:"actions,omitempty"`
+	Attributes    []*AttributeDeclaration `protobuf:"bytes,4,rep,name=attributes,proto3" json:"attributes,omitempty"`
+	Relations     []*RelationDeclaration  `protobuf:"bytes,5,rep,name=relations,proto3" json:"relations,omitempty"`
+	UiActions     []*UIActionDeclaration  `protobuf:"bytes,6,rep,name=ui_actions,json=uiActions,proto3" json:"ui_actions,omitempty"`
+	OwnerPlugin   string                  `protobuf:"bytes,7,opt,name=owner_plugin,json=ownerPlugin,proto3" json:"owner_plugin,omitempty"`
+	OwnerModule   string                  `protobuf:"bytes,8,opt,name=owner_module,json=ownerModule,proto3" json:"owner_module,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RegisterDeclarationsOutput) Reset() {
-	*x = RegisterDeclarationsOutput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[76]
+func (x *AuthzDeclarationSet) Reset() {
+	*x = AuthzDeclarationSet{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[82]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RegisterDeclarationsOutput) String() string {
+func (x *AuthzDeclarationSet) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RegisterDeclarationsOutput) ProtoMessage() {}
+func (*AuthzDeclarationSet) ProtoMessage() {}
 
-func (x *RegisterDeclarationsOutput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[76]
+func (x *AuthzDeclarationSet) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[82]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5481,56 +6190,91 @@ func (x *RegisterDeclarationsOutput) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RegisterDeclarationsOutput.ProtoReflect.Descriptor instead.
-func (*RegisterDeclarationsOutput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{76}
+// Deprecated: Use AuthzDeclarationSet.ProtoReflect.Descriptor instead.
+func (*AuthzDeclarationSet) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{82}
 }
 
-func (x *RegisterDeclarationsOutput) GetRegistered() int32 {
+func (x *AuthzDeclarationSet) GetScopes() []*ScopeDeclaration {
 	if x != nil {
-		return x.Registered
+		return x.Scopes
 	}
-	return 0
+	return nil
 }
 
-func (x *RegisterDeclarationsOutput) GetDeclarations() *AuthzDeclarationSet {
+func (x *AuthzDeclarationSet) GetResources() []*ResourceDeclaration {
 	if x != nil {
-		return x.Declarations
+		return x.Resources
 	}
 	return nil
 }
 
-func (x *RegisterDeclarationsOutput) GetError() string {
+func (x *AuthzDeclarationSet) GetActions() []*ActionDeclaration {
 	if x != nil {
-		return x.Error
+		return x.Actions
+	}
+	return nil
+}
+
+func (x *AuthzDeclarationSet) GetAttributes() []*AttributeDeclaration {
+	if x != nil {
+		return x.Attributes
+	}
+	return nil
+}
+
+func (x *AuthzDeclarationSet) GetRelations() []*RelationDeclaration {
+	if x != nil {
+		return x.Relations
+	}
+	return nil
+}
+
+func (x *AuthzDeclarationSet) GetUiActions() []*UIActionDeclaration {
+	if x != nil {
+		return x.UiActions
+	}
+	return nil
+}
+
+func (x *AuthzDeclarationSet) GetOwnerPlugin() string {
+	if x != nil {
+		return x.OwnerPlugin
 	}
 	return ""
 }
 
-type ListDeclarationsInput struct {
+func (x *AuthzDeclarationSet) GetOwnerModule() string {
+	if x != nil {
+		return x.OwnerModule
+	}
+	return ""
+}
+
+type RegisterDeclarationsInput struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Context       string                 `protobuf:"bytes,1,opt,name=context,proto3" json:"context,omitempty"`
+	Declarations  *AuthzDeclarationSet   `protobuf:"bytes,1,opt,name=declarations,proto3" json:"declarations,omitempty"`
 	OwnerPlugin   string                 `protobuf:"bytes,2,opt,name=owner_plugin,json=ownerPlugin,proto3" json:"owner_plugin,omitempty"`
 	OwnerModule   string                 `protobuf:"bytes,3,opt,name=owner_module,json=ownerModule,proto3" json:"owner_module,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListDeclarationsInput) Reset() {
-	*x = ListDeclarationsInput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[77]
+func (x *RegisterDeclarationsInput) Reset() {
+	*x = RegisterDeclarationsInput{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[83]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListDeclarationsInput) String() string {
+func (x *RegisterDeclarationsInput) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListDeclarationsInput) ProtoMessage() {}
+func (*RegisterDeclarationsInput) ProtoMessage() {}
 
-func (x *ListDeclarationsInput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[77]
+func (x *RegisterDeclarationsInput) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[83]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5541,55 +6285,2448 @@ func (x *ListDeclarationsInput) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListDeclarationsInput.ProtoReflect.Descriptor instead.
-func (*ListDeclarationsInput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{77}
+// Deprecated: Use RegisterDeclarationsInput.ProtoReflect.Descriptor instead.
+func (*RegisterDeclarationsInput) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{83}
 }
 
-func (x *ListDeclarationsInput) GetContext() string {
+func (x *RegisterDeclarationsInput) GetDeclarations() *AuthzDeclarationSet {
 	if x != nil {
-		return x.Context
+		return x.Declarations
+	}
+	return nil
+}
+
+func (x *RegisterDeclarationsInput) GetOwnerPlugin() string {
+	if x != nil {
+		return x.OwnerPlugin
+	}
+	return ""
+}
+
+func (x *RegisterDeclarationsInput) GetOwnerModule() string {
+	if x != nil {
+		return x.OwnerModule
 	}
 	return ""
 }
 
-func (x *ListDeclarationsInput) GetOwnerPlugin() string {
+type RegisterDeclarationsOutput struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Registered       int32                  `protobuf:"varint,1,opt,name=registered,proto3" json:"registered,omitempty"`
+	Declarations     *AuthzDeclarationSet   `protobuf:"bytes,2,opt,name=declarations,proto3" json:"declarations,omitempty"`
+	Conflicts        []*DeclarationConflict `protobuf:"bytes,3,rep,name=conflicts,proto3" json:"conflicts,omitempty"`
+	SubscriberErrors []string               `protobuf:"bytes,4,rep,name=subscriber_errors,json=subscriberErrors,proto3" json:"subscriber_errors,omitempty"`
+	Error            string                 `protobuf:"bytes,100,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *RegisterDeclarationsOutput) Reset() {
+	*x = RegisterDeclarationsOutput{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[84]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterDeclarationsOutput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterDeclarationsOutput) ProtoMessage() {}
+
+func (x *RegisterDeclarationsOutput) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[84]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterDeclarationsOutput.ProtoReflect.Descriptor instead.
+func (*RegisterDeclarationsOutput) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{84}
+}
+
+func (x *RegisterDeclarationsOutput) GetRegistered() int32 {
+	if x != nil {
+		return x.Registered
+	}
+	return 0
+}
+
+func (x *RegisterDeclarationsOutput) GetDeclarations() *AuthzDeclarationSet {
+	if x != nil {
+		return x.Declarations
+	}
+	return nil
+}
+
+func (x *RegisterDeclarationsOutput) GetConflicts() []*DeclarationConflict {
+	if x != nil {
+		return x.Conflicts
+	}
+	return nil
+}
+
+func (x *RegisterDeclarationsOutput) GetSubscriberErrors() []string {
+	if x != nil {
+		return x.SubscriberErrors
+	}
+	return nil
+}
+
+func (x *RegisterDeclarationsOutput) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type DeclarationConflict struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Kind                string                 `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	Key                 string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	ExistingOwnerPlugin string                 `protobuf:"bytes,3,opt,name=existing_owner_plugin,json=existingOwnerPlugin,proto3" json:"existing_owner_plugin,omitempty"`
+	ExistingOwnerModule string                 `protobuf:"bytes,4,opt,name=existing_owner_module,json=existingOwnerModule,proto3" json:"existing_owner_module,omitempty"`
+	OwnerPlugin         string                 `protobuf:"bytes,5,opt,name=owner_plugin,json=ownerPlugin,proto3" json:"owner_plugin,omitempty"`
+	OwnerModule         string                 `protobuf:"bytes,6,opt,name=owner_module,json=ownerModule,proto3" json:"owner_module,omitempty"`
+	Resolution          string                 `protobuf:"bytes,7,opt,name=resolution,proto3" json:"resolution,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *DeclarationConflict) Reset() {
+	*x = DeclarationConflict{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[85]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeclarationConflict) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeclarationConflict) ProtoMessage() {}
+
+func (x *DeclarationConflict) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[85]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeclarationConflict.ProtoReflect.Descriptor instead.
+func (*DeclarationConflict) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{85}
+}
+
+func (x *DeclarationConflict) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *DeclarationConflict) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *DeclarationConflict) GetExistingOwnerPlugin() string {
+	if x != nil {
+		return x.ExistingOwnerPlugin
+	}
+	return ""
+}
+
+func (x *DeclarationConflict) GetExistingOwnerModule() string {
+	if x != nil {
+		return x.ExistingOwnerModule
+	}
+	return ""
+}
+
+func (x *DeclarationConflict) GetOwnerPlugin() string {
+	if x != nil {
+		return x.OwnerPlugin
+	}
+	return ""
+}
+
+func (x *DeclarationConflict) GetOwnerModule() string {
+	if x != nil {
+		return x.OwnerModule
+	}
+	return ""
+}
+
+func (x *DeclarationConflict) GetResolution() string {
+	if x != nil {
+		return x.Resolution
+	}
+	return ""
+}
+
+type UnregisterDeclarationsInput struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OwnerPlugin   string                 `protobuf:"bytes,1,opt,name=owner_plugin,json=ownerPlugin,proto3" json:"owner_plugin,omitempty"`
+	OwnerModule   string                 `protobuf:"bytes,2,opt,name=owner_module,json=ownerModule,proto3" json:"owner_module,omitempty"`
+	Force         bool                   `protobuf:"varint,3,opt,name=force,proto3" json:"force,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnregisterDeclarationsInput) Reset() {
+	*x = UnregisterDeclarationsInput{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[86]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnregisterDeclarationsInput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnregisterDeclarationsInput) ProtoMessage() {}
+
+func (x *UnregisterDeclarationsInput) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[86]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnregisterDeclarationsInput.ProtoReflect.Descriptor instead.
+func (*UnregisterDeclarationsInput) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{86}
+}
+
+func (x *UnregisterDeclarationsInput) GetOwnerPlugin() string {
+	if x != nil {
+		return x.OwnerPlugin
+	}
+	return ""
+}
+
+func (x *UnregisterDeclarationsInput) GetOwnerModule() string {
+	if x != nil {
+		return x.OwnerModule
+	}
+	return ""
+}
+
+func (x *UnregisterDeclarationsInput) GetForce() bool {
+	if x != nil {
+		return x.Force
+	}
+	return false
+}
+
+type UnregisterDeclarationsOutput struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Removed       int32                  `protobuf:"varint,1,opt,name=removed,proto3" json:"removed,omitempty"`
+	Declarations  *AuthzDeclarationSet   `protobuf:"bytes,2,opt,name=declarations,proto3" json:"declarations,omitempty"`
+	ForcedScopes  []string               `protobuf:"bytes,3,rep,name=forced_scopes,json=forcedScopes,proto3" json:"forced_scopes,omitempty"`
+	Error         string                 `protobuf:"bytes,100,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnregisterDeclarationsOutput) Reset() {
+	*x = UnregisterDeclarationsOutput{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[87]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnregisterDeclarationsOutput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnregisterDeclarationsOutput) ProtoMessage() {}
+
+func (x *UnregisterDeclarationsOutput) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[87]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnregisterDeclarationsOutput.ProtoReflect.Descriptor instead.
+func (*UnregisterDeclarationsOutput) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{87}
+}
+
+func (x *UnregisterDeclarationsOutput) GetRemoved() int32 {
+	if x != nil {
+		return x.Removed
+	}
+	return 0
+}
+
+func (x *UnregisterDeclarationsOutput) GetDeclarations() *AuthzDeclarationSet {
+	if x != nil {
+		return x.Declarations
+	}
+	return nil
+}
+
+func (x *UnregisterDeclarationsOutput) GetForcedScopes() []string {
+	if x != nil {
+		return x.ForcedScopes
+	}
+	return nil
+}
+
+func (x *UnregisterDeclarationsOutput) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type ListDeclarationsInput struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Context       string                 `protobuf:"bytes,1,opt,name=context,proto3" json:"context,omitempty"`
+	OwnerPlugin   string                 `protobuf:"bytes,2,opt,name=owner_plugin,json=ownerPlugin,proto3" json:"owner_plugin,omitempty"`
+	OwnerModule   string                 `protobuf:"bytes,3,opt,name=owner_module,json=ownerModule,proto3" json:"owner_module,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDeclarationsInput) Reset() {
+	*x = ListDeclarationsInput{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[88]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDeclarationsInput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDeclarationsInput) ProtoMessage() {}
+
+func (x *ListDeclarationsInput) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[88]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDeclarationsInput.ProtoReflect.Descriptor instead.
+func (*ListDeclarationsInput) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{88}
+}
+
+func (x *ListDeclarationsInput) GetContext() string {
+	if x != nil {
+		return x.Context
+	}
+	return ""
+}
+
+func (x *ListDeclarationsInput) GetOwnerPlugin() string {
+	if x != nil {
+		return x.OwnerPlugin
+	}
+	return ""
+}
+
+func (x *ListDeclarationsInput) GetOwnerModule() string {
+	if x != nil {
+		return x.OwnerModule
+	}
+	return ""
+}
+
+type ListDeclarationsOutput struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Declarations  *AuthzDeclarationSet   `protobuf:"bytes,1,opt,name=declarations,proto3" json:"declarations,omitempty"`
+	Error         string                 `protobuf:"bytes,100,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDeclarationsOutput) Reset() {
+	*x = ListDeclarationsOutput{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[89]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDeclarationsOutput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDeclarationsOutput) ProtoMessage() {}
+
+func (x *ListDeclarationsOutput) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[89]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDeclarationsOutput.ProtoReflect.Descriptor instead.
+func (*ListDeclarationsOutput) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{89}
+}
+
+func (x *ListDeclarationsOutput) GetDeclarations() *AuthzDeclarationSet {
+	if x != nil {
+		return x.Declarations
+	}
+	return nil
+}
+
+func (x *ListDeclarationsOutput) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type ResolveProjectionInputsInput struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Context       string                 `protobuf:"bytes,1,opt,name=context,proto3" json:"context,omitempty"`
+	OwnerPlugin   string                 `protobuf:"bytes,2,opt,name=owner_plugin,json=ownerPlugin,proto3" json:"owner_plugin,omitempty"`
+	OwnerModule   string                 `protobuf:"bytes,3,opt,name=owner_module,json=ownerModule,proto3" json:"owner_module,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResolveProjectionInputsInput) Reset() {
+	*x = ResolveProjectionInputsInput{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[90]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolveProjectionInputsInput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveProjectionInputsInput) ProtoMessage() {}
+
+func (x *ResolveProjectionInputsInput) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[90]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveProjectionInputsInput.ProtoReflect.Descriptor instead.
+func (*ResolveProjectionInputsInput) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{90}
+}
+
+func (x *ResolveProjectionInputsInput) GetContext() string {
+	if x != nil {
+		return x.Context
+	}
+	return ""
+}
+
+func (x *ResolveProjectionInputsInput) GetOwnerPlugin() string {
+	if x != nil {
+		return x.OwnerPlugin
+	}
+	return ""
+}
+
+func (x *ResolveProjectionInputsInput) GetOwnerModule() string {
+	if x != nil {
+		return x.OwnerModule
+	}
+	return ""
+}
+
+type ProjectionInputs struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ScopeNames      []string               `protobuf:"bytes,1,rep,name=scope_names,json=scopeNames,proto3" json:"scope_names,omitempty"`
+	ResourceNames   []string               `protobuf:"bytes,2,rep,name=resource_names,json=resourceNames,proto3" json:"resource_names,omitempty"`
+	ActionNames     []string               `protobuf:"bytes,3,rep,name=action_names,json=actionNames,proto3" json:"action_names,omitempty"`
+	AttributeNames  []string               `protobuf:"bytes,4,rep,name=attribute_names,json=attributeNames,proto3" json:"attribute_names,omitempty"`
+	RelationNames   []string               `protobuf:"bytes,5,rep,name=relation_names,json=relationNames,proto3" json:"relation_names,omitempty"`
+	UiActionIds     []string               `protobuf:"bytes,6,rep,name=ui_action_ids,json=uiActionIds,proto3" json:"ui_action_ids,omitempty"`
+	LookupSourceIds []string               `protobuf:"bytes,7,rep,name=lookup_source_ids,json=lookupSourceIds,proto3" json:"lookup_source_ids,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ProjectionInputs) Reset() {
+	*x = ProjectionInputs{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[91]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProjectionInputs) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProjectionInputs) ProtoMessage() {}
+
+func (x *ProjectionInputs) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[91]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProjectionInputs.ProtoReflect.Descriptor instead.
+func (*ProjectionInputs) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{91}
+}
+
+func (x *ProjectionInputs) GetScopeNames() []string {
+	if x != nil {
+		return x.ScopeNames
+	}
+	return nil
+}
+
+func (x *ProjectionInputs) GetResourceNames() []string {
+	if x != nil {
+		return x.ResourceNames
+	}
+	return nil
+}
+
+func (x *ProjectionInputs) GetActionNames() []string {
+	if x != nil {
+		return x.ActionNames
+	}
+	return nil
+}
+
+func (x *ProjectionInputs) GetAttributeNames() []string {
+	if x != nil {
+		return x.AttributeNames
+	}
+	return nil
+}
+
+func (x *ProjectionInputs) GetRelationNames() []string {
+	if x != nil {
+		return x.RelationNames
+	}
+	return nil
+}
+
+func (x *ProjectionInputs) GetUiActionIds() []string {
+	if x != nil {
+		return x.UiActionIds
+	}
+	return nil
+}
+
+func (x *ProjectionInputs) GetLookupSourceIds() []string {
+	if x != nil {
+		return x.LookupSourceIds
+	}
+	return nil
+}
+
+type ResolveProjectionInputsOutput struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Projection    *ProjectionInputs      `protobuf:"bytes,1,opt,name=projection,proto3" json:"projection,omitempty"`
+	Error         string                 `protobuf:"bytes,100,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResolveProjectionInputsOutput) Reset() {
+	*x = ResolveProjectionInputsOutput{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[92]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolveProjectionInputsOutput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveProjectionInputsOutput) ProtoMessage() {}
+
+func (x *ResolveProjectionInputsOutput) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[92]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveProjectionInputsOutput.ProtoReflect.Descriptor instead.
+func (*ResolveProjectionInputsOutput) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{92}
+}
+
+func (x *ResolveProjectionInputsOutput) GetProjection() *ProjectionInputs {
+	if x != nil {
+		return x.Projection
+	}
+	return nil
+}
+
+func (x *ResolveProjectionInputsOutput) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type ResolveSubjectProjectionInput struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subject       string                 `protobuf:"bytes,1,opt,name=subject,proto3" json:"subject,omitempty"`
+	Module        string                 `protobuf:"bytes,2,opt,name=module,proto3" json:"module,omitempty"`
+	Provider      string                 `protobuf:"bytes,3,opt,name=provider,proto3" json:"provider,omitempty"`
+	Context       string                 `protobuf:"bytes,4,opt,name=context,proto3" json:"context,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResolveSubjectProjectionInput) Reset() {
+	*x = ResolveSubjectProjectionInput{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[93]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolveSubjectProjectionInput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveSubjectProjectionInput) ProtoMessage() {}
+
+func (x *ResolveSubjectProjectionInput) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[93]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveSubjectProjectionInput.ProtoReflect.Descriptor instead.
+func (*ResolveSubjectProjectionInput) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{93}
+}
+
+func (x *ResolveSubjectProjectionInput) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *ResolveSubjectProjectionInput) GetModule() string {
+	if x != nil {
+		return x.Module
+	}
+	return ""
+}
+
+func (x *ResolveSubjectProjectionInput) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *ResolveSubjectProjectionInput) GetContext() string {
+	if x != nil {
+		return x.Context
+	}
+	return ""
+}
+
+type PermittedResource struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Context       string                 `protobuf:"bytes,1,opt,name=context,proto3" json:"context,omitempty"`
+	Resource      string                 `protobuf:"bytes,2,opt,name=resource,proto3" json:"resource,omitempty"`
+	Actions       []string               `protobuf:"bytes,3,rep,name=actions,proto3" json:"actions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PermittedResource) Reset() {
+	*x = PermittedResource{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[94]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PermittedResource) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PermittedResource) ProtoMessage() {}
+
+func (x *PermittedResource) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[94]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PermittedResource.ProtoReflect.Descriptor instead.
+func (*PermittedResource) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{94}
+}
+
+func (x *PermittedResource) GetContext() string {
+	if x != nil {
+		return x.Context
+	}
+	return ""
+}
+
+func (x *PermittedResource) GetResource() string {
+	if x != nil {
+		return x.Resource
+	}
+	return ""
+}
+
+func (x *PermittedResource) GetActions() []string {
+	if x != nil {
+		return x.Actions
+	}
+	return nil
+}
+
+type SubjectProjection struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subject       string                 `protobuf:"bytes,1,opt,name=subject,proto3" json:"subject,omitempty"`
+	Module        string                 `protobuf:"bytes,2,opt,name=module,proto3" json:"module,omitempty"`
+	Provider      string                 `protobuf:"bytes,3,opt,name=provider,proto3" json:"provider,omitempty"`
+	Context       string                 `protobuf:"bytes,4,opt,name=context,proto3" json:"context,omitempty"`
+	GrantedScopes []string               `protobuf:"bytes,5,rep,name=granted_scopes,json=grantedScopes,proto3" json:"granted_scopes,omitempty"`
+	UiActionIds   []string               `protobuf:"bytes,6,rep,name=ui_action_ids,json=uiActionIds,proto3" json:"ui_action_ids,omitempty"`
+	Resources     []*PermittedResource   `protobuf:"bytes,7,rep,name=resources,proto3" json:"resources,omitempty"`
+	IssuedAt      string                 `protobuf:"bytes,8,opt,name=issued_at,json=issuedAt,proto3" json:"issued_at,omitempty"`
+	ExpiresAt     string                 `protobuf:"bytes,9,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	Signature     string                 `protobuf:"bytes,10,opt,name=signature,proto3" json:"signature,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubjectProjection) Reset() {
+	*x = SubjectProjection{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[95]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubjectProjection) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubjectProjection) ProtoMessage() {}
+
+func (x *SubjectProjection) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[95]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubjectProjection.ProtoReflect.Descriptor instead.
+func (*SubjectProjection) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{95}
+}
+
+func (x *SubjectProjection) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *SubjectProjection) GetModule() string {
+	if x != nil {
+		return x.Module
+	}
+	return ""
+}
+
+func (x *SubjectProjection) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *SubjectProjection) GetContext() string {
+	if x != nil {
+		return x.Context
+	}
+	return ""
+}
+
+func (x *SubjectProjection) GetGrantedScopes() []string {
+	if x != nil {
+		return x.GrantedScopes
+	}
+	return nil
+}
+
+func (x *SubjectProjection) GetUiActionIds() []string {
+	if x != nil {
+		return x.UiActionIds
+	}
+	return nil
+}
+
+func (x *SubjectProjection) GetResources() []*PermittedResource {
+	if x != nil {
+		return x.Resources
+	}
+	return nil
+}
+
+func (x *SubjectProjection) GetIssuedAt() string {
+	if x != nil {
+		return x.IssuedAt
+	}
+	return ""
+}
+
+func (x *SubjectProjection) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
+}
+
+func (x *SubjectProjection) GetSignature() string {
+	if x != nil {
+		return x.Signature
+	}
+	return ""
+}
+
+type ResolveSubjectProjectionOutput struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Projection    *SubjectProjection     `protobuf:"bytes,1,opt,name=projection,proto3" json:"projection,omitempty"`
+	Error         string                 `protobuf:"bytes,100,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResolveSubjectProjectionOutput) Reset() {
+	*x = ResolveSubjectProjectionOutput{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[96]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolveSubjectProjectionOutput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveSubjectProjectionOutput) ProtoMessage() {}
+
+func (x *ResolveSubjectProjectionOutput) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[96]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveSubjectProjectionOutput.ProtoReflect.Descriptor instead.
+func (*ResolveSubjectProjectionOutput) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{96}
+}
+
+func (x *ResolveSubjectProjectionOutput) GetProjection() *SubjectProjection {
+	if x != nil {
+		return x.Projection
+	}
+	return nil
+}
+
+func (x *ResolveSubjectProjectionOutput) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type SubjectProjectionConfig struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Catalog       string                 `protobuf:"bytes,1,opt,name=catalog,proto3" json:"catalog,omitempty"`
+	Module        string                 `protobuf:"bytes,2,opt,name=module,proto3" json:"module,omitempty"`
+	Provider      string                 `protobuf:"bytes,3,opt,name=provider,proto3" json:"provider,omitempty"`
+	Subject       string                 `protobuf:"bytes,4,opt,name=subject,proto3" json:"subject,omitempty"`
+	Context       string                 `protobuf:"bytes,5,opt,name=context,proto3" json:"context,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubjectProjectionConfig) Reset() {
+	*x = SubjectProjectionConfig{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[97]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubjectProjectionConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubjectProjectionConfig) ProtoMessage() {}
+
+func (x *SubjectProjectionConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[97]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubjectProjectionConfig.ProtoReflect.Descriptor instead.
+func (*SubjectProjectionConfig) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{97}
+}
+
+func (x *SubjectProjectionConfig) GetCatalog() string {
+	if x != nil {
+		return x.Catalog
+	}
+	return ""
+}
+
+func (x *SubjectProjectionConfig) GetModule() string {
+	if x != nil {
+		return x.Module
+	}
+	return ""
+}
+
+func (x *SubjectProjectionConfig) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *SubjectProjectionConfig) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *SubjectProjectionConfig) GetContext() string {
+	if x != nil {
+		return x.Context
+	}
+	return ""
+}
+
+type SubjectProjectionInput struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Catalog       string                 `protobuf:"bytes,1,opt,name=catalog,proto3" json:"catalog,omitempty"`
+	Module        string                 `protobuf:"bytes,2,opt,name=module,proto3" json:"module,omitempty"`
+	Provider      string                 `protobuf:"bytes,3,opt,name=provider,proto3" json:"provider,omitempty"`
+	Subject       string                 `protobuf:"bytes,4,opt,name=subject,proto3" json:"subject,omitempty"`
+	Context       string                 `protobuf:"bytes,5,opt,name=context,proto3" json:"context,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubjectProjectionInput) Reset() {
+	*x = SubjectProjectionInput{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[98]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubjectProjectionInput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubjectProjectionInput) ProtoMessage() {}
+
+func (x *SubjectProjectionInput) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[98]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubjectProjectionInput.ProtoReflect.Descriptor instead.
+func (*SubjectProjectionInput) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{98}
+}
+
+func (x *SubjectProjectionInput) GetCatalog() string {
+	if x != nil {
+		return x.Catalog
+	}
+	return ""
+}
+
+func (x *SubjectProjectionInput) GetModule() string {
+	if x != nil {
+		return x.Module
+	}
+	return ""
+}
+
+func (x *SubjectProjectionInput) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *SubjectProjectionInput) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *SubjectProjectionInput) GetContext() string {
+	if x != nil {
+		return x.Context
+	}
+	return ""
+}
+
+type ResolveSubjectScopesInput struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subject       string                 `protobuf:"bytes,1,opt,name=subject,proto3" json:"subject,omitempty"`
+	DirectScopes  []string               `protobuf:"bytes,2,rep,name=direct_scopes,json=directScopes,proto3" json:"direct_scopes,omitempty"`
+	RoleScopes    []string               `protobuf:"bytes,3,rep,name=role_scopes,json=roleScopes,proto3" json:"role_scopes,omitempty"`
+	Context       string                 `protobuf:"bytes,4,opt,name=context,proto3" json:"context,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResolveSubjectScopesInput) Reset() {
+	*x = ResolveSubjectScopesInput{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[99]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolveSubjectScopesInput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveSubjectScopesInput) ProtoMessage() {}
+
+func (x *ResolveSubjectScopesInput) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[99]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveSubjectScopesInput.ProtoReflect.Descriptor instead.
+func (*ResolveSubjectScopesInput) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{99}
+}
+
+func (x *ResolveSubjectScopesInput) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *ResolveSubjectScopesInput) GetDirectScopes() []string {
+	if x != nil {
+		return x.DirectScopes
+	}
+	return nil
+}
+
+func (x *ResolveSubjectScopesInput) GetRoleScopes() []string {
+	if x != nil {
+		return x.RoleScopes
+	}
+	return nil
+}
+
+func (x *ResolveSubjectScopesInput) GetContext() string {
+	if x != nil {
+		return x.Context
+	}
+	return ""
+}
+
+type ResolveSubjectScopesOutput struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Subject        string                 `protobuf:"bytes,1,opt,name=subject,proto3" json:"subject,omitempty"`
+	Scopes         []string               `protobuf:"bytes,2,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	DeclaredScopes []*ScopeDeclaration    `protobuf:"bytes,3,rep,name=declared_scopes,json=declaredScopes,proto3" json:"declared_scopes,omitempty"`
+	Deprecations   []string               `protobuf:"bytes,4,rep,name=deprecations,proto3" json:"deprecations,omitempty"`
+	Error          string                 `protobuf:"bytes,100,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ResolveSubjectScopesOutput) Reset() {
+	*x = ResolveSubjectScopesOutput{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[100]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolveSubjectScopesOutput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveSubjectScopesOutput) ProtoMessage() {}
+
+func (x *ResolveSubjectScopesOutput) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[100]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveSubjectScopesOutput.ProtoReflect.Descriptor instead.
+func (*ResolveSubjectScopesOutput) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{100}
+}
+
+func (x *ResolveSubjectScopesOutput) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *ResolveSubjectScopesOutput) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *ResolveSubjectScopesOutput) GetDeclaredScopes() []*ScopeDeclaration {
+	if x != nil {
+		return x.DeclaredScopes
+	}
+	return nil
+}
+
+func (x *ResolveSubjectScopesOutput) GetDeprecations() []string {
+	if x != nil {
+		return x.Deprecations
+	}
+	return nil
+}
+
+func (x *ResolveSubjectScopesOutput) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type MigrateScopesInput struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Context       string                 `protobuf:"bytes,1,opt,name=context,proto3" json:"context,omitempty"`
+	DryRun        bool                   `protobuf:"varint,2,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MigrateScopesInput) Reset() {
+	*x = MigrateScopesInput{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[101]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MigrateScopesInput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MigrateScopesInput) ProtoMessage() {}
+
+func (x *MigrateScopesInput) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[101]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MigrateScopesInput.ProtoReflect.Descriptor instead.
+func (*MigrateScopesInput) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{101}
+}
+
+func (x *MigrateScopesInput) GetContext() string {
+	if x != nil {
+		return x.Context
+	}
+	return ""
+}
+
+func (x *MigrateScopesInput) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type ScopeMigration struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Provider      string                 `protobuf:"bytes,1,opt,name=provider,proto3" json:"provider,omitempty"`
+	Context       string                 `protobuf:"bytes,2,opt,name=context,proto3" json:"context,omitempty"`
+	Role          string                 `protobuf:"bytes,3,opt,name=role,proto3" json:"role,omitempty"`
+	Subject       string                 `protobuf:"bytes,4,opt,name=subject,proto3" json:"subject,omitempty"`
+	From          string                 `protobuf:"bytes,5,opt,name=from,proto3" json:"from,omitempty"`
+	To            string                 `protobuf:"bytes,6,opt,name=to,proto3" json:"to,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScopeMigration) Reset() {
+	*x = ScopeMigration{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[102]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScopeMigration) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScopeMigration) ProtoMessage() {}
+
+func (x *ScopeMigration) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[102]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScopeMigration.ProtoReflect.Descriptor instead.
+func (*ScopeMigration) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{102}
+}
+
+func (x *ScopeMigration) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *ScopeMigration) GetContext() string {
+	if x != nil {
+		return x.Context
+	}
+	return ""
+}
+
+func (x *ScopeMigration) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *ScopeMigration) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *ScopeMigration) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *ScopeMigration) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+type MigrateScopesOutput struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Migrated      int32                  `protobuf:"varint,1,opt,name=migrated,proto3" json:"migrated,omitempty"`
+	Migrations    []*ScopeMigration      `protobuf:"bytes,2,rep,name=migrations,proto3" json:"migrations,omitempty"`
+	Error         string                 `protobuf:"bytes,100,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MigrateScopesOutput) Reset() {
+	*x = MigrateScopesOutput{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[103]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MigrateScopesOutput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MigrateScopesOutput) ProtoMessage() {}
+
+func (x *MigrateScopesOutput) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[103]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MigrateScopesOutput.ProtoReflect.Descriptor instead.
+func (*MigrateScopesOutput) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{103}
+}
+
+func (x *MigrateScopesOutput) GetMigrated() int32 {
+	if x != nil {
+		return x.Migrated
+	}
+	return 0
+}
+
+func (x *MigrateScopesOutput) GetMigrations() []*ScopeMigration {
+	if x != nil {
+		return x.Migrations
+	}
+	return nil
+}
+
+func (x *MigrateScopesOutput) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type RoleScopeGrant struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Role          string                 `protobuf:"bytes,1,opt,name=role,proto3" json:"role,omitempty"`
+	Context       string                 `protobuf:"bytes,2,opt,name=context,proto3" json:"context,omitempty"`
+	Scopes        []string               `protobuf:"bytes,3,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RoleScopeGrant) Reset() {
+	*x = RoleScopeGrant{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[104]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoleScopeGrant) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoleScopeGrant) ProtoMessage() {}
+
+func (x *RoleScopeGrant) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[104]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoleScopeGrant.ProtoReflect.Descriptor instead.
+func (*RoleScopeGrant) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{104}
+}
+
+func (x *RoleScopeGrant) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *RoleScopeGrant) GetContext() string {
+	if x != nil {
+		return x.Context
+	}
+	return ""
+}
+
+func (x *RoleScopeGrant) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+type SubjectRoleAssignment struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subject       string                 `protobuf:"bytes,1,opt,name=subject,proto3" json:"subject,omitempty"`
+	Role          string                 `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`
+	Context       string                 `protobuf:"bytes,3,opt,name=context,proto3" json:"context,omitempty"`
+	DirectScopes  []string               `protobuf:"bytes,4,rep,name=direct_scopes,json=directScopes,proto3" json:"direct_scopes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubjectRoleAssignment) Reset() {
+	*x = SubjectRoleAssignment{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[105]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubjectRoleAssignment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubjectRoleAssignment) ProtoMessage() {}
+
+func (x *SubjectRoleAssignment) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[105]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubjectRoleAssignment.ProtoReflect.Descriptor instead.
+func (*SubjectRoleAssignment) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{105}
+}
+
+func (x *SubjectRoleAssignment) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *SubjectRoleAssignment) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *SubjectRoleAssignment) GetContext() string {
+	if x != nil {
+		return x.Context
+	}
+	return ""
+}
+
+func (x *SubjectRoleAssignment) GetDirectScopes() []string {
+	if x != nil {
+		return x.DirectScopes
+	}
+	return nil
+}
+
+type AssignmentFilter struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subject       string                 `protobuf:"bytes,1,opt,name=subject,proto3" json:"subject,omitempty"`
+	Role          string                 `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`
+	Context       string                 `protobuf:"bytes,3,opt,name=context,proto3" json:"context,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AssignmentFilter) Reset() {
+	*x = AssignmentFilter{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[106]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssignmentFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssignmentFilter) ProtoMessage() {}
+
+func (x *AssignmentFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[106]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssignmentFilter.ProtoReflect.Descriptor instead.
+func (*AssignmentFilter) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{106}
+}
+
+func (x *AssignmentFilter) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *AssignmentFilter) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *AssignmentFilter) GetContext() string {
+	if x != nil {
+		return x.Context
+	}
+	return ""
+}
+
+type ScopeCheckInput struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subject       string                 `protobuf:"bytes,1,opt,name=subject,proto3" json:"subject,omitempty"`
+	Context       string                 `protobuf:"bytes,2,opt,name=context,proto3" json:"context,omitempty"`
+	Scope         string                 `protobuf:"bytes,3,opt,name=scope,proto3" json:"scope,omitempty"`
+	Resource      string                 `protobuf:"bytes,4,opt,name=resource,proto3" json:"resource,omitempty"`
+	Action        string                 `protobuf:"bytes,5,opt,name=action,proto3" json:"action,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScopeCheckInput) Reset() {
+	*x = ScopeCheckInput{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[107]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScopeCheckInput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScopeCheckInput) ProtoMessage() {}
+
+func (x *ScopeCheckInput) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[107]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScopeCheckInput.ProtoReflect.Descriptor instead.
+func (*ScopeCheckInput) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{107}
+}
+
+func (x *ScopeCheckInput) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *ScopeCheckInput) GetContext() string {
+	if x != nil {
+		return x.Context
+	}
+	return ""
+}
+
+func (x *ScopeCheckInput) GetScope() string {
+	if x != nil {
+		return x.Scope
+	}
+	return ""
+}
+
+func (x *ScopeCheckInput) GetResource() string {
+	if x != nil {
+		return x.Resource
+	}
+	return ""
+}
+
+func (x *ScopeCheckInput) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+type ScopeCheckOutput struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Allowed       bool                   `protobuf:"varint,1,opt,name=allowed,proto3" json:"allowed,omitempty"`
+	Provider      string                 `protobuf:"bytes,2,opt,name=provider,proto3" json:"provider,omitempty"`
+	Subject       string                 `protobuf:"bytes,3,opt,name=subject,proto3" json:"subject,omitempty"`
+	Context       string                 `protobuf:"bytes,4,opt,name=context,proto3" json:"context,omitempty"`
+	Scope         string                 `protobuf:"bytes,5,opt,name=scope,proto3" json:"scope,omitempty"`
+	MatchedRole   string                 `protobuf:"bytes,6,opt,name=matched_role,json=matchedRole,proto3" json:"matched_role,omitempty"`
+	MatchedScopes []string               `protobuf:"bytes,7,rep,name=matched_scopes,json=matchedScopes,proto3" json:"matched_scopes,omitempty"`
+	Reason        string                 `protobuf:"bytes,8,opt,name=reason,proto3" json:"reason,omitempty"`
+	Error         string                 `protobuf:"bytes,100,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScopeCheckOutput) Reset() {
+	*x = ScopeCheckOutput{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[108]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScopeCheckOutput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScopeCheckOutput) ProtoMessage() {}
+
+func (x *ScopeCheckOutput) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[108]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScopeCheckOutput.ProtoReflect.Descriptor instead.
+func (*ScopeCheckOutput) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{108}
+}
+
+func (x *ScopeCheckOutput) GetAllowed() bool {
+	if x != nil {
+		return x.Allowed
+	}
+	return false
+}
+
+func (x *ScopeCheckOutput) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *ScopeCheckOutput) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *ScopeCheckOutput) GetContext() string {
+	if x != nil {
+		return x.Context
+	}
+	return ""
+}
+
+func (x *ScopeCheckOutput) GetScope() string {
+	if x != nil {
+		return x.Scope
+	}
+	return ""
+}
+
+func (x *ScopeCheckOutput) GetMatchedRole() string {
+	if x != nil {
+		return x.MatchedRole
+	}
+	return ""
+}
+
+func (x *ScopeCheckOutput) GetMatchedScopes() []string {
+	if x != nil {
+		return x.MatchedScopes
+	}
+	return nil
+}
+
+func (x *ScopeCheckOutput) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ScopeCheckOutput) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type UpsertRoleInput struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Grant         *RoleScopeGrant        `protobuf:"bytes,1,opt,name=grant,proto3" json:"grant,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpsertRoleInput) Reset() {
+	*x = UpsertRoleInput{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[109]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpsertRoleInput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpsertRoleInput) ProtoMessage() {}
+
+func (x *UpsertRoleInput) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[109]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpsertRoleInput.ProtoReflect.Descriptor instead.
+func (*UpsertRoleInput) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{109}
+}
+
+func (x *UpsertRoleInput) GetGrant() *RoleScopeGrant {
+	if x != nil {
+		return x.Grant
+	}
+	return nil
+}
+
+type UpsertRoleOutput struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Changed       bool                   `protobuf:"varint,1,opt,name=changed,proto3" json:"changed,omitempty"`
+	Grant         *RoleScopeGrant        `protobuf:"bytes,2,opt,name=grant,proto3" json:"grant,omitempty"`
+	Error         string                 `protobuf:"bytes,100,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpsertRoleOutput) Reset() {
+	*x = UpsertRoleOutput{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[110]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpsertRoleOutput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpsertRoleOutput) ProtoMessage() {}
+
+func (x *UpsertRoleOutput) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[110]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpsertRoleOutput.ProtoReflect.Descriptor instead.
+func (*UpsertRoleOutput) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{110}
+}
+
+func (x *UpsertRoleOutput) GetChanged() bool {
+	if x != nil {
+		return x.Changed
+	}
+	return false
+}
+
+func (x *UpsertRoleOutput) GetGrant() *RoleScopeGrant {
+	if x != nil {
+		return x.Grant
+	}
+	return nil
+}
+
+func (x *UpsertRoleOutput) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type AssignRoleInput struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Assignment    *SubjectRoleAssignment `protobuf:"bytes,1,opt,name=assignment,proto3" json:"assignment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AssignRoleInput) Reset() {
+	*x = AssignRoleInput{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[111]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssignRoleInput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssignRoleInput) ProtoMessage() {}
+
+func (x *AssignRoleInput) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[111]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssignRoleInput.ProtoReflect.Descriptor instead.
+func (*AssignRoleInput) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{111}
+}
+
+func (x *AssignRoleInput) GetAssignment() *SubjectRoleAssignment {
+	if x != nil {
+		return x.Assignment
+	}
+	return nil
+}
+
+type AssignRoleOutput struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Changed       bool                   `protobuf:"varint,1,opt,name=changed,proto3" json:"changed,omitempty"`
+	Assignment    *SubjectRoleAssignment `protobuf:"bytes,2,opt,name=assignment,proto3" json:"assignment,omitempty"`
+	Error         string                 `protobuf:"bytes,100,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AssignRoleOutput) Reset() {
+	*x = AssignRoleOutput{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[112]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssignRoleOutput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssignRoleOutput) ProtoMessage() {}
+
+func (x *AssignRoleOutput) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[112]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssignRoleOutput.ProtoReflect.Descriptor instead.
+func (*AssignRoleOutput) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{112}
+}
+
+func (x *AssignRoleOutput) GetChanged() bool {
+	if x != nil {
+		return x.Changed
+	}
+	return false
+}
+
+func (x *AssignRoleOutput) GetAssignment() *SubjectRoleAssignment {
+	if x != nil {
+		return x.Assignment
+	}
+	return nil
+}
+
+func (x *AssignRoleOutput) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type ListRoleAssignmentsInput struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filter        *AssignmentFilter      `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRoleAssignmentsInput) Reset() {
+	*x = ListRoleAssignmentsInput{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[113]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRoleAssignmentsInput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRoleAssignmentsInput) ProtoMessage() {}
+
+func (x *ListRoleAssignmentsInput) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[113]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRoleAssignmentsInput.ProtoReflect.Descriptor instead.
+func (*ListRoleAssignmentsInput) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{113}
+}
+
+func (x *ListRoleAssignmentsInput) GetFilter() *AssignmentFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+type ListRoleAssignmentsOutput struct {
+	state         protoimpl.MessageState   `protogen:"open.v1"`
+	Assignments   []*SubjectRoleAssignment `protobuf:"bytes,1,rep,name=assignments,proto3" json:"assignments,omitempty"`
+	Error         string                   `protobuf:"bytes,100,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRoleAssignmentsOutput) Reset() {
+	*x = ListRoleAssignmentsOutput{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[114]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRoleAssignmentsOutput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRoleAssignmentsOutput) ProtoMessage() {}
+
+func (x *ListRoleAssignmentsOutput) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[114]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRoleAssignmentsOutput.ProtoReflect.Descriptor instead.
+func (*ListRoleAssignmentsOutput) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{114}
+}
+
+func (x *ListRoleAssignmentsOutput) GetAssignments() []*SubjectRoleAssignment {
+	if x != nil {
+		return x.Assignments
+	}
+	return nil
+}
+
+func (x *ListRoleAssignmentsOutput) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type RemoveRoleAssignmentInput struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Assignment    *SubjectRoleAssignment `protobuf:"bytes,1,opt,name=assignment,proto3" json:"assignment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveRoleAssignmentInput) Reset() {
+	*x = RemoveRoleAssignmentInput{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[115]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveRoleAssignmentInput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveRoleAssignmentInput) ProtoMessage() {}
+
+func (x *RemoveRoleAssignmentInput) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[115]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveRoleAssignmentInput.ProtoReflect.Descriptor instead.
+func (*RemoveRoleAssignmentInput) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{115}
+}
+
+func (x *RemoveRoleAssignmentInput) GetAssignment() *SubjectRoleAssignment {
+	if x != nil {
+		return x.Assignment
+	}
+	return nil
+}
+
+type RemoveRoleAssignmentOutput struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Changed       bool                   `protobuf:"varint,1,opt,name=changed,proto3" json:"changed,omitempty"`
+	Error         string                 `protobuf:"bytes,100,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveRoleAssignmentOutput) Reset() {
+	*x = RemoveRoleAssignmentOutput{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[116]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveRoleAssignmentOutput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveRoleAssignmentOutput) ProtoMessage() {}
+
+func (x *RemoveRoleAssignmentOutput) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[116]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveRoleAssignmentOutput.ProtoReflect.Descriptor instead.
+func (*RemoveRoleAssignmentOutput) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{116}
+}
+
+func (x *RemoveRoleAssignmentOutput) GetChanged() bool {
+	if x != nil {
+		return x.Changed
+	}
+	return false
+}
+
+func (x *RemoveRoleAssignmentOutput) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type SimulationChange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Op            string                 `protobuf:"bytes,1,opt,name=op,proto3" json:"op,omitempty"`
+	Kind          string                 `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	Rule          []string               `protobuf:"bytes,3,rep,name=rule,proto3" json:"rule,omitempty"`
+	Grant         *RoleScopeGrant        `protobuf:"bytes,4,opt,name=grant,proto3" json:"grant,omitempty"`
+	Assignment    *SubjectRoleAssignment `protobuf:"bytes,5,opt,name=assignment,proto3" json:"assignment,omitempty"`
+	Tuple         *RelationTuple         `protobuf:"bytes,6,opt,name=tuple,proto3" json:"tuple,omitempty"`
+	Policy        *AttributePolicy       `protobuf:"bytes,7,opt,name=policy,proto3" json:"policy,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SimulationChange) Reset() {
+	*x = SimulationChange{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[117]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SimulationChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SimulationChange) ProtoMessage() {}
+
+func (x *SimulationChange) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[117]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SimulationChange.ProtoReflect.Descriptor instead.
+func (*SimulationChange) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{117}
+}
+
+func (x *SimulationChange) GetOp() string {
+	if x != nil {
+		return x.Op
+	}
+	return ""
+}
+
+func (x *SimulationChange) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *SimulationChange) GetRule() []string {
+	if x != nil {
+		return x.Rule
+	}
+	return nil
+}
+
+func (x *SimulationChange) GetGrant() *RoleScopeGrant {
+	if x != nil {
+		return x.Grant
+	}
+	return nil
+}
+
+func (x *SimulationChange) GetAssignment() *SubjectRoleAssignment {
+	if x != nil {
+		return x.Assignment
+	}
+	return nil
+}
+
+func (x *SimulationChange) GetTuple() *RelationTuple {
+	if x != nil {
+		return x.Tuple
+	}
+	return nil
+}
+
+func (x *SimulationChange) GetPolicy() *AttributePolicy {
+	if x != nil {
+		return x.Policy
+	}
+	return nil
+}
+
+type SimulationProbe struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	Kind                  string                 `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	Mode                  AuthzMode              `protobuf:"varint,2,opt,name=mode,proto3,enum=workflow.plugins.authz.v1.AuthzMode" json:"mode,omitempty"`
+	Subject               string                 `protobuf:"bytes,3,opt,name=subject,proto3" json:"subject,omitempty"`
+	Context               string                 `protobuf:"bytes,4,opt,name=context,proto3" json:"context,omitempty"`
+	Resource              string                 `protobuf:"bytes,5,opt,name=resource,proto3" json:"resource,omitempty"`
+	Action                string                 `protobuf:"bytes,6,opt,name=action,proto3" json:"action,omitempty"`
+	Scope                 string                 `protobuf:"bytes,7,opt,name=scope,proto3" json:"scope,omitempty"`
+	Relation              string                 `protobuf:"bytes,8,opt,name=relation,proto3" json:"relation,omitempty"`
+	SubjectAttributes     *structpb.Struct       `protobuf:"bytes,9,opt,name=subject_attributes,json=subjectAttributes,proto3" json:"subject_attributes,omitempty"`
+	ResourceAttributes    *structpb.Struct       `protobuf:"bytes,10,opt,name=resource_attributes,json=resourceAttributes,proto3" json:"resource_attributes,omitempty"`
+	EnvironmentAttributes *structpb.Struct       `protobuf:"bytes,11,opt,name=environment_attributes,json=environmentAttributes,proto3" json:"environment_attributes,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *SimulationProbe) Reset() {
+	*x = SimulationProbe{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[118]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SimulationProbe) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SimulationProbe) ProtoMessage() {}
+
+func (x *SimulationProbe) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[118]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SimulationProbe.ProtoReflect.Descriptor instead.
+func (*SimulationProbe) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{118}
+}
+
+func (x *SimulationProbe) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *SimulationProbe) GetMode() AuthzMode {
+	if x != nil {
+		return x.Mode
+	}
+	return AuthzMode_AUTHZ_MODE_UNSPECIFIED
+}
+
+func (x *SimulationProbe) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *SimulationProbe) GetContext() string {
+	if x != nil {
+		return x.Context
+	}
+	return ""
+}
+
+func (x *SimulationProbe) GetResource() string {
+	if x != nil {
+		return x.Resource
+	}
+	return ""
+}
+
+func (x *SimulationProbe) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *SimulationProbe) GetScope() string {
+	if x != nil {
+		return x.Scope
+	}
+	return ""
+}
+
+func (x *SimulationProbe) GetRelation() string {
+	if x != nil {
+		return x.Relation
+	}
+	return ""
+}
+
+func (x *SimulationProbe) GetSubjectAttributes() *structpb.Struct {
+	if x != nil {
+		return x.SubjectAttributes
+	}
+	return nil
+}
+
+func (x *SimulationProbe) GetResourceAttributes() *structpb.Struct {
 	if x != nil {
-		return x.OwnerPlugin
+		return x.ResourceAttributes
 	}
-	return ""
+	return nil
 }
 
-func (x *ListDeclarationsInput) GetOwnerModule() string {
+func (x *SimulationProbe) GetEnvironmentAttributes() *structpb.Struct {
 	if x != nil {
-		return x.OwnerModule
+		return x.EnvironmentAttributes
 	}
-	return ""
+	return nil
 }
 
-type ListDeclarationsOutput struct {
+type SimulationResult struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Declarations  *AuthzDeclarationSet   `protobuf:"bytes,1,opt,name=declarations,proto3" json:"declarations,omitempty"`
-	Error         string                 `protobuf:"bytes,100,opt,name=error,proto3" json:"error,omitempty"`
+	Probe         *SimulationProbe       `protobuf:"bytes,1,opt,name=probe,proto3" json:"probe,omitempty"`
+	Before        bool                   `protobuf:"varint,2,opt,name=before,proto3" json:"before,omitempty"`
+	After         bool                   `protobuf:"varint,3,opt,name=after,proto3" json:"after,omitempty"`
+	BeforeReason  string                 `protobuf:"bytes,4,opt,name=before_reason,json=beforeReason,proto3" json:"before_reason,omitempty"`
+	AfterReason   string                 `protobuf:"bytes,5,opt,name=after_reason,json=afterReason,proto3" json:"after_reason,omitempty"`
+	Change        string                 `protobuf:"bytes,6,opt,name=change,proto3" json:"change,omitempty"`
+	Error         string                 `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListDeclarationsOutput) Reset() {
-	*x = ListDeclarationsOutput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[78]
+func (x *SimulationResult) Reset() {
+	*x = SimulationResult{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[119]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListDeclarationsOutput) String() string {
+func (x *SimulationResult) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListDeclarationsOutput) ProtoMessage() {}
+func (*SimulationResult) ProtoMessage() {}
 
-func (x *ListDeclarationsOutput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[78]
+func (x *SimulationResult) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[119]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5600,113 +8737,86 @@ func (x *ListDeclarationsOutput) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListDeclarationsOutput.ProtoReflect.Descriptor instead.
-func (*ListDeclarationsOutput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{78}
+// Deprecated: Use SimulationResult.ProtoReflect.Descriptor instead.
+func (*SimulationResult) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{119}
 }
 
-func (x *ListDeclarationsOutput) GetDeclarations() *AuthzDeclarationSet {
+func (x *SimulationResult) GetProbe() *SimulationProbe {
 	if x != nil {
-		return x.Declarations
+		return x.Probe
 	}
 	return nil
 }
 
-func (x *ListDeclarationsOutput) GetError() string {
+func (x *SimulationResult) GetBefore() bool {
 	if x != nil {
-		return x.Error
+		return x.Before
 	}
-	return ""
-}
-
-type ResolveProjectionInputsInput struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Context       string                 `protobuf:"bytes,1,opt,name=context,proto3" json:"context,omitempty"`
-	OwnerPlugin   string                 `protobuf:"bytes,2,opt,name=owner_plugin,json=ownerPlugin,proto3" json:"owner_plugin,omitempty"`
-	OwnerModule   string                 `protobuf:"bytes,3,opt,name=owner_module,json=ownerModule,proto3" json:"owner_module,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
-}
-
-func (x *ResolveProjectionInputsInput) Reset() {
-	*x = ResolveProjectionInputsInput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[79]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
-}
-
-func (x *ResolveProjectionInputsInput) String() string {
-	return protoimpl.X.MessageStringOf(x)
+	return false
 }
 
-func (*ResolveProjectionInputsInput) ProtoMessage() {}
-
-func (x *ResolveProjectionInputsInput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[79]
+func (x *SimulationResult) GetAfter() bool {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.After
 	}
-	return mi.MessageOf(x)
+	return false
 }
 
-// Deprecated: Use ResolveProjectionInputsInput.ProtoReflect.Descriptor instead.
-func (*ResolveProjectionInputsInput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{79}
+func (x *SimulationResult) GetBeforeReason() string {
+	if x != nil {
+		return x.BeforeReason
+	}
+	return ""
 }
 
-func (x *ResolveProjectionInputsInput) GetContext() string {
+func (x *SimulationResult) GetAfterReason() string {
 	if x != nil {
-		return x.Context
+		return x.AfterReason
 	}
 	return ""
 }
 
-func (x *ResolveProjectionInputsInput) GetOwnerPlugin() string {
+func (x *SimulationResult) GetChange() string {
 	if x != nil {
-		return x.OwnerPlugin
+		return x.Change
 	}
 	return ""
 }
 
-func (x *ResolveProjectionInputsInput) GetOwnerModule() string {
+func (x *SimulationResult) GetError() string {
 	if x != nil {
-		return x.OwnerModule
+		return x.Error
 	}
 	return ""
 }
 
-type ProjectionInputs struct {
-	state           protoimpl.MessageState `protogen:"open.v1"`
-	ScopeNames      []string               `protobuf:"bytes,1,rep,name=scope_names,json=scopeNames,proto3" json:"scope_names,omitempty"`
-	ResourceNames   []string               `protobuf:"bytes,2,rep,name=resource_names,json=resourceNames,proto3" json:"resource_names,omitempty"`
-	ActionNames     []string               `protobuf:"bytes,3,rep,name=action_names,json=actionNames,proto3" json:"action_names,omitempty"`
-	AttributeNames  []string               `protobuf:"bytes,4,rep,name=attribute_names,json=attributeNames,proto3" json:"attribute_names,omitempty"`
-	RelationNames   []string               `protobuf:"bytes,5,rep,name=relation_names,json=relationNames,proto3" json:"relation_names,omitempty"`
-	UiActionIds     []string               `protobuf:"bytes,6,rep,name=ui_action_ids,json=uiActionIds,proto3" json:"ui_action_ids,omitempty"`
-	LookupSourceIds []string               `protobuf:"bytes,7,rep,name=lookup_source_ids,json=lookupSourceIds,proto3" json:"lookup_source_ids,omitempty"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+type SimulateConfig struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Module           string                 `protobuf:"bytes,1,opt,name=module,proto3" json:"module,omitempty"`
+	Provider         string                 `protobuf:"bytes,2,opt,name=provider,proto3" json:"provider,omitempty"`
+	Changes          []*SimulationChange    `protobuf:"bytes,3,rep,name=changes,proto3" json:"changes,omitempty"`
+	Probes           []*SimulationProbe     `protobuf:"bytes,4,rep,name=probes,proto3" json:"probes,omitempty"`
+	AllSubjectScopes bool                   `protobuf:"varint,5,opt,name=all_subject_scopes,json=allSubjectScopes,proto3" json:"all_subject_scopes,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
-func (x *ProjectionInputs) Reset() {
-	*x = ProjectionInputs{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[80]
+func (x *SimulateConfig) Reset() {
+	*x = SimulateConfig{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[120]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ProjectionInputs) String() string {
+func (x *SimulateConfig) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ProjectionInputs) ProtoMessage() {}
+func (*SimulateConfig) ProtoMessage() {}
 
-func (x *ProjectionInputs) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[80]
+func (x *SimulateConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[120]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5717,83 +8827,72 @@ func (x *ProjectionInputs) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ProjectionInputs.ProtoReflect.Descriptor instead.
-func (*ProjectionInputs) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{80}
-}
-
-func (x *ProjectionInputs) GetScopeNames() []string {
-	if x != nil {
-		return x.ScopeNames
-	}
-	return nil
-}
-
-func (x *ProjectionInputs) GetResourceNames() []string {
-	if x != nil {
-		return x.ResourceNames
-	}
-	return nil
+// Deprecated: Use SimulateConfig.ProtoReflect.Descriptor instead.
+func (*SimulateConfig) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{120}
 }
 
-func (x *ProjectionInputs) GetActionNames() []string {
+func (x *SimulateConfig) GetModule() string {
 	if x != nil {
-		return x.ActionNames
+		return x.Module
 	}
-	return nil
+	return ""
 }
 
-func (x *ProjectionInputs) GetAttributeNames() []string {
+func (x *SimulateConfig) GetProvider() string {
 	if x != nil {
-		return x.AttributeNames
+		return x.Provider
 	}
-	return nil
+	return ""
 }
 
-func (x *ProjectionInputs) GetRelationNames() []string {
+func (x *SimulateConfig) GetChanges() []*SimulationChange {
 	if x != nil {
-		return x.RelationNames
+		return x.Changes
 	}
 	return nil
 }
 
-func (x *ProjectionInputs) GetUiActionIds() []string {
+func (x *SimulateConfig) GetProbes() []*SimulationProbe {
 	if x != nil {
-		return x.UiActionIds
+		return x.Probes
 	}
 	return nil
 }
 
-func (x *ProjectionInputs) GetLookupSourceIds() []string {
+func (x *SimulateConfig) GetAllSubjectScopes() bool {
 	if x != nil {
-		return x.LookupSourceIds
+		return x.AllSubjectScopes
 	}
-	return nil
+	return false
 }
 
-type ResolveProjectionInputsOutput struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Projection    *ProjectionInputs      `protobuf:"bytes,1,opt,name=projection,proto3" json:"projection,omitempty"`
-	Error         string                 `protobuf:"bytes,100,opt,name=error,proto3" json:"error,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+type SimulateInput struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Module           string                 `protobuf:"bytes,1,opt,name=module,proto3" json:"module,omitempty"`
+	Provider         string                 `protobuf:"bytes,2,opt,name=provider,proto3" json:"provider,omitempty"`
+	Changes          []*SimulationChange    `protobuf:"bytes,3,rep,name=changes,proto3" json:"changes,omitempty"`
+	Probes           []*SimulationProbe     `protobuf:"bytes,4,rep,name=probes,proto3" json:"probes,omitempty"`
+	AllSubjectScopes bool                   `protobuf:"varint,5,opt,name=all_subject_scopes,json=allSubjectScopes,proto3" json:"all_subject_scopes,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
-func (x *ResolveProjectionInputsOutput) Reset() {
-	*x = ResolveProjectionInputsOutput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[81]
+func (x *SimulateInput) Reset() {
+	*x = SimulateInput{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[121]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ResolveProjectionInputsOutput) String() string {
+func (x *SimulateInput) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ResolveProjectionInputsOutput) ProtoMessage() {}
+func (*SimulateInput) ProtoMessage() {}
 
-func (x *ResolveProjectionInputsOutput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[81]
+func (x *SimulateInput) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[121]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5804,50 +8903,73 @@ func (x *ResolveProjectionInputsOutput) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ResolveProjectionInputsOutput.ProtoReflect.Descriptor instead.
-func (*ResolveProjectionInputsOutput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{81}
+// Deprecated: Use SimulateInput.ProtoReflect.Descriptor instead.
+func (*SimulateInput) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{121}
 }
 
-func (x *ResolveProjectionInputsOutput) GetProjection() *ProjectionInputs {
+func (x *SimulateInput) GetModule() string {
 	if x != nil {
-		return x.Projection
+		return x.Module
 	}
-	return nil
+	return ""
 }
 
-func (x *ResolveProjectionInputsOutput) GetError() string {
+func (x *SimulateInput) GetProvider() string {
 	if x != nil {
-		return x.Error
+		return x.Provider
 	}
 	return ""
 }
 
-type ResolveSubjectScopesInput struct {
+func (x *SimulateInput) GetChanges() []*SimulationChange {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+func (x *SimulateInput) GetProbes() []*SimulationProbe {
+	if x != nil {
+		return x.Probes
+	}
+	return nil
+}
+
+func (x *SimulateInput) GetAllSubjectScopes() bool {
+	if x != nil {
+		return x.AllSubjectScopes
+	}
+	return false
+}
+
+type SimulateOutput struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Subject       string                 `protobuf:"bytes,1,opt,name=subject,proto3" json:"subject,omitempty"`
-	DirectScopes  []string               `protobuf:"bytes,2,rep,name=direct_scopes,json=directScopes,proto3" json:"direct_scopes,omitempty"`
-	RoleScopes    []string               `protobuf:"bytes,3,rep,name=role_scopes,json=roleScopes,proto3" json:"role_scopes,omitempty"`
-	Context       string                 `protobuf:"bytes,4,opt,name=context,proto3" json:"context,omitempty"`
+	Evaluated     int32                  `protobuf:"varint,1,opt,name=evaluated,proto3" json:"evaluated,omitempty"`
+	Granted       int32                  `protobuf:"varint,2,opt,name=granted,proto3" json:"granted,omitempty"`
+	Revoked       int32                  `protobuf:"varint,3,opt,name=revoked,proto3" json:"revoked,omitempty"`
+	Flips         []*SimulationResult    `protobuf:"bytes,4,rep,name=flips,proto3" json:"flips,omitempty"`
+	Errors        []*SimulationResult    `protobuf:"bytes,5,rep,name=errors,proto3" json:"errors,omitempty"`
+	Error         string                 `protobuf:"bytes,100,opt,name=error,proto3" json:"error,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ResolveSubjectScopesInput) Reset() {
-	*x = ResolveSubjectScopesInput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[82]
+func (x *SimulateOutput) Reset() {
+	*x = SimulateOutput{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[122]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ResolveSubjectScopesInput) String() string {
+func (x *SimulateOutput) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ResolveSubjectScopesInput) ProtoMessage() {}
+func (*SimulateOutput) ProtoMessage() {}
 
-func (x *ResolveSubjectScopesInput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[82]
+func (x *SimulateOutput) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[122]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5858,64 +8980,80 @@ func (x *ResolveSubjectScopesInput) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ResolveSubjectScopesInput.ProtoReflect.Descriptor instead.
-func (*ResolveSubjectScopesInput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{82}
+// Deprecated: Use SimulateOutput.ProtoReflect.Descriptor instead.
+func (*SimulateOutput) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{122}
 }
 
-func (x *ResolveSubjectScopesInput) GetSubject() string {
+func (x *SimulateOutput) GetEvaluated() int32 {
 	if x != nil {
-		return x.Subject
+		return x.Evaluated
 	}
-	return ""
+	return 0
 }
 
-func (x *ResolveSubjectScopesInput) GetDirectScopes() []string {
+func (x *SimulateOutput) GetGranted() int32 {
 	if x != nil {
-		return x.DirectScopes
+		return x.Granted
+	}
+	return 0
+}
+
+func (x *SimulateOutput) GetRevoked() int32 {
+	if x != nil {
+		return x.Revoked
+	}
+	return 0
+}
+
+func (x *SimulateOutput) GetFlips() []*SimulationResult {
+	if x != nil {
+		return x.Flips
 	}
 	return nil
 }
 
-func (x *ResolveSubjectScopesInput) GetRoleScopes() []string {
+func (x *SimulateOutput) GetErrors() []*SimulationResult {
 	if x != nil {
-		return x.RoleScopes
+		return x.Errors
 	}
 	return nil
 }
 
-func (x *ResolveSubjectScopesInput) GetContext() string {
+func (x *SimulateOutput) GetError() string {
 	if x != nil {
-		return x.Context
+		return x.Error
 	}
 	return ""
 }
 
-type ResolveSubjectScopesOutput struct {
+type CompositeRoute struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
-	Subject        string                 `protobuf:"bytes,1,opt,name=subject,proto3" json:"subject,omitempty"`
-	Scopes         []string               `protobuf:"bytes,2,rep,name=scopes,proto3" json:"scopes,omitempty"`
-	DeclaredScopes []*ScopeDeclaration    `protobuf:"bytes,3,rep,name=declared_scopes,json=declaredScopes,proto3" json:"declared_scopes,omitempty"`
-	Error          string                 `protobuf:"bytes,100,opt,name=error,proto3" json:"error,omitempty"`
+	Context        string                 `protobuf:"bytes,1,opt,name=context,proto3" json:"context,omitempty"`
+	Mode           AuthzMode              `protobuf:"varint,2,opt,name=mode,proto3,enum=workflow.plugins.authz.v1.AuthzMode" json:"mode,omitempty"`
+	ResourcePrefix string                 `protobuf:"bytes,3,opt,name=resource_prefix,json=resourcePrefix,proto3" json:"resource_prefix,omitempty"`
+	Providers      []string               `protobuf:"bytes,4,rep,name=providers,proto3" json:"providers,omitempty"`
+	Combine        string                 `protobuf:"bytes,5,opt,name=combine,proto3" json:"combine,omitempty"`
+	Fallback       []string               `protobuf:"bytes,6,rep,name=fallback,proto3" json:"fallback,omitempty"`
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
 }
 
-func (x *ResolveSubjectScopesOutput) Reset() {
-	*x = ResolveSubjectScopesOutput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[83]
+func (x *CompositeRoute) Reset() {
+	*x = CompositeRoute{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[123]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ResolveSubjectScopesOutput) String() string {
+func (x *CompositeRoute) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ResolveSubjectScopesOutput) ProtoMessage() {}
+func (*CompositeRoute) ProtoMessage() {}
 
-func (x *ResolveSubjectScopesOutput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[83]
+func (x *CompositeRoute) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[123]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5926,63 +9064,77 @@ func (x *ResolveSubjectScopesOutput) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ResolveSubjectScopesOutput.ProtoReflect.Descriptor instead.
-func (*ResolveSubjectScopesOutput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{83}
+// Deprecated: Use CompositeRoute.ProtoReflect.Descriptor instead.
+func (*CompositeRoute) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{123}
 }
 
-func (x *ResolveSubjectScopesOutput) GetSubject() string {
+func (x *CompositeRoute) GetContext() string {
 	if x != nil {
-		return x.Subject
+		return x.Context
 	}
 	return ""
 }
 
-func (x *ResolveSubjectScopesOutput) GetScopes() []string {
+func (x *CompositeRoute) GetMode() AuthzMode {
 	if x != nil {
-		return x.Scopes
+		return x.Mode
 	}
-	return nil
+	return AuthzMode_AUTHZ_MODE_UNSPECIFIED
 }
 
-func (x *ResolveSubjectScopesOutput) GetDeclaredScopes() []*ScopeDeclaration {
+func (x *CompositeRoute) GetResourcePrefix() string {
 	if x != nil {
-		return x.DeclaredScopes
+		return x.ResourcePrefix
+	}
+	return ""
+}
+
+func (x *CompositeRoute) GetProviders() []string {
+	if x != nil {
+		return x.Providers
 	}
 	return nil
 }
 
-func (x *ResolveSubjectScopesOutput) GetError() string {
+func (x *CompositeRoute) GetCombine() string {
 	if x != nil {
-		return x.Error
+		return x.Combine
 	}
 	return ""
 }
 
-type RoleScopeGrant struct {
+func (x *CompositeRoute) GetFallback() []string {
+	if x != nil {
+		return x.Fallback
+	}
+	return nil
+}
+
+type CompositeModuleConfig struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Role          string                 `protobuf:"bytes,1,opt,name=role,proto3" json:"role,omitempty"`
-	Context       string                 `protobuf:"bytes,2,opt,name=context,proto3" json:"context,omitempty"`
-	Scopes        []string               `protobuf:"bytes,3,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	Routes        []*CompositeRoute      `protobuf:"bytes,1,rep,name=routes,proto3" json:"routes,omitempty"`
+	Combine       string                 `protobuf:"bytes,2,opt,name=combine,proto3" json:"combine,omitempty"`
+	Fallback      []string               `protobuf:"bytes,3,rep,name=fallback,proto3" json:"fallback,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RoleScopeGrant) Reset() {
-	*x = RoleScopeGrant{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[84]
+func (x *CompositeModuleConfig) Reset() {
+	*x = CompositeModuleConfig{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[124]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RoleScopeGrant) String() string {
+func (x *CompositeModuleConfig) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RoleScopeGrant) ProtoMessage() {}
+func (*CompositeModuleConfig) ProtoMessage() {}
 
-func (x *RoleScopeGrant) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[84]
+func (x *CompositeModuleConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[124]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5993,57 +9145,58 @@ func (x *RoleScopeGrant) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RoleScopeGrant.ProtoReflect.Descriptor instead.
-func (*RoleScopeGrant) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{84}
+// Deprecated: Use CompositeModuleConfig.ProtoReflect.Descriptor instead.
+func (*CompositeModuleConfig) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{124}
 }
 
-func (x *RoleScopeGrant) GetRole() string {
+func (x *CompositeModuleConfig) GetRoutes() []*CompositeRoute {
 	if x != nil {
-		return x.Role
+		return x.Routes
 	}
-	return ""
+	return nil
 }
 
-func (x *RoleScopeGrant) GetContext() string {
+func (x *CompositeModuleConfig) GetCombine() string {
 	if x != nil {
-		return x.Context
+		return x.Combine
 	}
 	return ""
 }
 
-func (x *RoleScopeGrant) GetScopes() []string {
+func (x *CompositeModuleConfig) GetFallback() []string {
 	if x != nil {
-		return x.Scopes
+		return x.Fallback
 	}
 	return nil
 }
 
-type SubjectRoleAssignment struct {
+type OPAModuleConfig struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Subject       string                 `protobuf:"bytes,1,opt,name=subject,proto3" json:"subject,omitempty"`
-	Role          string                 `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`
-	Context       string                 `protobuf:"bytes,3,opt,name=context,proto3" json:"context,omitempty"`
-	DirectScopes  []string               `protobuf:"bytes,4,rep,name=direct_scopes,json=directScopes,proto3" json:"direct_scopes,omitempty"`
+	Modules       map[string]string      `protobuf:"bytes,1,rep,name=modules,proto3" json:"modules,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	PolicyDir     string                 `protobuf:"bytes,2,opt,name=policy_dir,json=policyDir,proto3" json:"policy_dir,omitempty"`
+	Data          *structpb.Struct       `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	Query         string                 `protobuf:"bytes,4,opt,name=query,proto3" json:"query,omitempty"`
+	Catalog       string                 `protobuf:"bytes,5,opt,name=catalog,proto3" json:"catalog,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SubjectRoleAssignment) Reset() {
-	*x = SubjectRoleAssignment{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[85]
+func (x *OPAModuleConfig) Reset() {
+	*x = OPAModuleConfig{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[125]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SubjectRoleAssignment) String() string {
+func (x *OPAModuleConfig) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SubjectRoleAssignment) ProtoMessage() {}
+func (*OPAModuleConfig) ProtoMessage() {}
 
-func (x *SubjectRoleAssignment) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[85]
+func (x *OPAModuleConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[125]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -6054,63 +9207,69 @@ func (x *SubjectRoleAssignment) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SubjectRoleAssignment.ProtoReflect.Descriptor instead.
-func (*SubjectRoleAssignment) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{85}
+// Deprecated: Use OPAModuleConfig.ProtoReflect.Descriptor instead.
+func (*OPAModuleConfig) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{125}
 }
 
-func (x *SubjectRoleAssignment) GetSubject() string {
+func (x *OPAModuleConfig) GetModules() map[string]string {
 	if x != nil {
-		return x.Subject
+		return x.Modules
 	}
-	return ""
+	return nil
 }
 
-func (x *SubjectRoleAssignment) GetRole() string {
+func (x *OPAModuleConfig) GetPolicyDir() string {
 	if x != nil {
-		return x.Role
+		return x.PolicyDir
 	}
 	return ""
 }
 
-func (x *SubjectRoleAssignment) GetContext() string {
+func (x *OPAModuleConfig) GetData() *structpb.Struct {
 	if x != nil {
-		return x.Context
+		return x.Data
+	}
+	return nil
+}
+
+func (x *OPAModuleConfig) GetQuery() string {
+	if x != nil {
+		return x.Query
 	}
 	return ""
 }
 
-func (x *SubjectRoleAssignment) GetDirectScopes() []string {
+func (x *OPAModuleConfig) GetCatalog() string {
 	if x != nil {
-		return x.DirectScopes
+		return x.Catalog
 	}
-	return nil
+	return ""
 }
 
-type AssignmentFilter struct {
+type OPADataInput struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Subject       string                 `protobuf:"bytes,1,opt,name=subject,proto3" json:"subject,omitempty"`
-	Role          string                 `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`
-	Context       string                 `protobuf:"bytes,3,opt,name=context,proto3" json:"context,omitempty"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Value         *structpb.Value        `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AssignmentFilter) Reset() {
-	*x = AssignmentFilter{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[86]
+func (x *OPADataInput) Reset() {
+	*x = OPADataInput{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[126]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AssignmentFilter) String() string {
+func (x *OPADataInput) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AssignmentFilter) ProtoMessage() {}
+func (*OPADataInput) ProtoMessage() {}
 
-func (x *AssignmentFilter) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[86]
+func (x *OPADataInput) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[126]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -6121,58 +9280,49 @@ func (x *AssignmentFilter) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AssignmentFilter.ProtoReflect.Descriptor instead.
-func (*AssignmentFilter) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{86}
-}
-
-func (x *AssignmentFilter) GetSubject() string {
-	if x != nil {
-		return x.Subject
-	}
-	return ""
+// Deprecated: Use OPADataInput.ProtoReflect.Descriptor instead.
+func (*OPADataInput) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{126}
 }
 
-func (x *AssignmentFilter) GetRole() string {
+func (x *OPADataInput) GetPath() string {
 	if x != nil {
-		return x.Role
+		return x.Path
 	}
 	return ""
 }
 
-func (x *AssignmentFilter) GetContext() string {
+func (x *OPADataInput) GetValue() *structpb.Value {
 	if x != nil {
-		return x.Context
+		return x.Value
 	}
-	return ""
+	return nil
 }
 
-type ScopeCheckInput struct {
+type OPADataOutput struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Subject       string                 `protobuf:"bytes,1,opt,name=subject,proto3" json:"subject,omitempty"`
-	Context       string                 `protobuf:"bytes,2,opt,name=context,proto3" json:"context,omitempty"`
-	Scope         string                 `protobuf:"bytes,3,opt,name=scope,proto3" json:"scope,omitempty"`
-	Resource      string                 `protobuf:"bytes,4,opt,name=resource,proto3" json:"resource,omitempty"`
-	Action        string                 `protobuf:"bytes,5,opt,name=action,proto3" json:"action,omitempty"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Removed       bool                   `protobuf:"varint,2,opt,name=removed,proto3" json:"removed,omitempty"`
+	Error         string                 `protobuf:"bytes,100,opt,name=error,proto3" json:"error,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ScopeCheckInput) Reset() {
-	*x = ScopeCheckInput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[87]
+func (x *OPADataOutput) Reset() {
+	*x = OPADataOutput{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[127]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ScopeCheckInput) String() string {
+func (x *OPADataOutput) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ScopeCheckInput) ProtoMessage() {}
+func (*OPADataOutput) ProtoMessage() {}
 
-func (x *ScopeCheckInput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[87]
+func (x *OPADataOutput) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[127]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -6183,76 +9333,63 @@ func (x *ScopeCheckInput) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ScopeCheckInput.ProtoReflect.Descriptor instead.
-func (*ScopeCheckInput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{87}
-}
-
-func (x *ScopeCheckInput) GetSubject() string {
-	if x != nil {
-		return x.Subject
-	}
-	return ""
-}
-
-func (x *ScopeCheckInput) GetContext() string {
-	if x != nil {
-		return x.Context
-	}
-	return ""
+// Deprecated: Use OPADataOutput.ProtoReflect.Descriptor instead.
+func (*OPADataOutput) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{127}
 }
 
-func (x *ScopeCheckInput) GetScope() string {
+func (x *OPADataOutput) GetPath() string {
 	if x != nil {
-		return x.Scope
+		return x.Path
 	}
 	return ""
 }
 
-func (x *ScopeCheckInput) GetResource() string {
+func (x *OPADataOutput) GetRemoved() bool {
 	if x != nil {
-		return x.Resource
+		return x.Removed
 	}
-	return ""
+	return false
 }
 
-func (x *ScopeCheckInput) GetAction() string {
+func (x *OPADataOutput) GetError() string {
 	if x != nil {
-		return x.Action
+		return x.Error
 	}
 	return ""
 }
 
-type ScopeCheckOutput struct {
+type CedarModuleConfig struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Allowed       bool                   `protobuf:"varint,1,opt,name=allowed,proto3" json:"allowed,omitempty"`
-	Provider      string                 `protobuf:"bytes,2,opt,name=provider,proto3" json:"provider,omitempty"`
-	Subject       string                 `protobuf:"bytes,3,opt,name=subject,proto3" json:"subject,omitempty"`
-	Context       string                 `protobuf:"bytes,4,opt,name=context,proto3" json:"context,omitempty"`
-	Scope         string                 `protobuf:"bytes,5,opt,name=scope,proto3" json:"scope,omitempty"`
-	MatchedRole   string                 `protobuf:"bytes,6,opt,name=matched_role,json=matchedRole,proto3" json:"matched_role,omitempty"`
-	MatchedScopes []string               `protobuf:"bytes,7,rep,name=matched_scopes,json=matchedScopes,proto3" json:"matched_scopes,omitempty"`
-	Reason        string                 `protobuf:"bytes,8,opt,name=reason,proto3" json:"reason,omitempty"`
-	Error         string                 `protobuf:"bytes,100,opt,name=error,proto3" json:"error,omitempty"`
+	Policies      string                 `protobuf:"bytes,1,opt,name=policies,proto3" json:"policies,omitempty"`
+	PolicyFile    string                 `protobuf:"bytes,2,opt,name=policy_file,json=policyFile,proto3" json:"policy_file,omitempty"`
+	Schema        string                 `protobuf:"bytes,3,opt,name=schema,proto3" json:"schema,omitempty"`
+	SchemaFile    string                 `protobuf:"bytes,4,opt,name=schema_file,json=schemaFile,proto3" json:"schema_file,omitempty"`
+	Validation    string                 `protobuf:"bytes,5,opt,name=validation,proto3" json:"validation,omitempty"`
+	Entities      *structpb.ListValue    `protobuf:"bytes,6,opt,name=entities,proto3" json:"entities,omitempty"`
+	EntitiesFile  string                 `protobuf:"bytes,7,opt,name=entities_file,json=entitiesFile,proto3" json:"entities_file,omitempty"`
+	PrincipalType string                 `protobuf:"bytes,8,opt,name=principal_type,json=principalType,proto3" json:"principal_type,omitempty"`
+	ResourceType  string                 `protobuf:"bytes,9,opt,name=resource_type,json=resourceType,proto3" json:"resource_type,omitempty"`
+	Catalog       string                 `protobuf:"bytes,10,opt,name=catalog,proto3" json:"catalog,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ScopeCheckOutput) Reset() {
-	*x = ScopeCheckOutput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[88]
+func (x *CedarModuleConfig) Reset() {
+	*x = CedarModuleConfig{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[128]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ScopeCheckOutput) String() string {
+func (x *CedarModuleConfig) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ScopeCheckOutput) ProtoMessage() {}
+func (*CedarModuleConfig) ProtoMessage() {}
 
-func (x *ScopeCheckOutput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[88]
+func (x *CedarModuleConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[128]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -6263,96 +9400,103 @@ func (x *ScopeCheckOutput) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ScopeCheckOutput.ProtoReflect.Descriptor instead.
-func (*ScopeCheckOutput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{88}
+// Deprecated: Use CedarModuleConfig.ProtoReflect.Descriptor instead.
+func (*CedarModuleConfig) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{128}
 }
 
-func (x *ScopeCheckOutput) GetAllowed() bool {
+func (x *CedarModuleConfig) GetPolicies() string {
 	if x != nil {
-		return x.Allowed
+		return x.Policies
 	}
-	return false
+	return ""
 }
 
-func (x *ScopeCheckOutput) GetProvider() string {
+func (x *CedarModuleConfig) GetPolicyFile() string {
 	if x != nil {
-		return x.Provider
+		return x.PolicyFile
 	}
 	return ""
 }
 
-func (x *ScopeCheckOutput) GetSubject() string {
+func (x *CedarModuleConfig) GetSchema() string {
 	if x != nil {
-		return x.Subject
+		return x.Schema
 	}
 	return ""
 }
 
-func (x *ScopeCheckOutput) GetContext() string {
+func (x *CedarModuleConfig) GetSchemaFile() string {
 	if x != nil {
-		return x.Context
+		return x.SchemaFile
 	}
 	return ""
 }
 
-func (x *ScopeCheckOutput) GetScope() string {
+func (x *CedarModuleConfig) GetValidation() string {
 	if x != nil {
-		return x.Scope
+		return x.Validation
 	}
 	return ""
 }
 
-func (x *ScopeCheckOutput) GetMatchedRole() string {
+func (x *CedarModuleConfig) GetEntities() *structpb.ListValue {
 	if x != nil {
-		return x.MatchedRole
+		return x.Entities
+	}
+	return nil
+}
+
+func (x *CedarModuleConfig) GetEntitiesFile() string {
+	if x != nil {
+		return x.EntitiesFile
 	}
 	return ""
 }
 
-func (x *ScopeCheckOutput) GetMatchedScopes() []string {
+func (x *CedarModuleConfig) GetPrincipalType() string {
 	if x != nil {
-		return x.MatchedScopes
+		return x.PrincipalType
 	}
-	return nil
+	return ""
 }
 
-func (x *ScopeCheckOutput) GetReason() string {
+func (x *CedarModuleConfig) GetResourceType() string {
 	if x != nil {
-		return x.Reason
+		return x.ResourceType
 	}
 	return ""
 }
 
-func (x *ScopeCheckOutput) GetError() string {
+func (x *CedarModuleConfig) GetCatalog() string {
 	if x != nil {
-		return x.Error
+		return x.Catalog
 	}
 	return ""
 }
 
-type UpsertRoleInput struct {
+type CedarSchemaInput struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Grant         *RoleScopeGrant        `protobuf:"bytes,1,opt,name=grant,proto3" json:"grant,omitempty"`
+	Context       string                 `protobuf:"bytes,1,opt,name=context,proto3" json:"context,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpsertRoleInput) Reset() {
-	*x = UpsertRoleInput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[89]
+func (x *CedarSchemaInput) Reset() {
+	*x = CedarSchemaInput{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[129]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpsertRoleInput) String() string {
+func (x *CedarSchemaInput) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpsertRoleInput) ProtoMessage() {}
+func (*CedarSchemaInput) ProtoMessage() {}
 
-func (x *UpsertRoleInput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[89]
+func (x *CedarSchemaInput) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[129]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -6363,42 +9507,42 @@ func (x *UpsertRoleInput) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpsertRoleInput.ProtoReflect.Descriptor instead.
-func (*UpsertRoleInput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{89}
+// Deprecated: Use CedarSchemaInput.ProtoReflect.Descriptor instead.
+func (*CedarSchemaInput) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{129}
 }
 
-func (x *UpsertRoleInput) GetGrant() *RoleScopeGrant {
+func (x *CedarSchemaInput) GetContext() string {
 	if x != nil {
-		return x.Grant
+		return x.Context
 	}
-	return nil
+	return ""
 }
 
-type UpsertRoleOutput struct {
+type CedarSchemaOutput struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Changed       bool                   `protobuf:"varint,1,opt,name=changed,proto3" json:"changed,omitempty"`
-	Grant         *RoleScopeGrant        `protobuf:"bytes,2,opt,name=grant,proto3" json:"grant,omitempty"`
+	Context       string                 `protobuf:"bytes,1,opt,name=context,proto3" json:"context,omitempty"`
+	Schema        string                 `protobuf:"bytes,2,opt,name=schema,proto3" json:"schema,omitempty"`
 	Error         string                 `protobuf:"bytes,100,opt,name=error,proto3" json:"error,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpsertRoleOutput) Reset() {
-	*x = UpsertRoleOutput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[90]
+func (x *CedarSchemaOutput) Reset() {
+	*x = CedarSchemaOutput{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[130]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpsertRoleOutput) String() string {
+func (x *CedarSchemaOutput) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpsertRoleOutput) ProtoMessage() {}
+func (*CedarSchemaOutput) ProtoMessage() {}
 
-func (x *UpsertRoleOutput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[90]
+func (x *CedarSchemaOutput) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[130]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -6409,54 +9553,144 @@ func (x *UpsertRoleOutput) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpsertRoleOutput.ProtoReflect.Descriptor instead.
-func (*UpsertRoleOutput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{90}
+// Deprecated: Use CedarSchemaOutput.ProtoReflect.Descriptor instead.
+func (*CedarSchemaOutput) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{130}
 }
 
-func (x *UpsertRoleOutput) GetChanged() bool {
+func (x *CedarSchemaOutput) GetContext() string {
 	if x != nil {
-		return x.Changed
+		return x.Context
 	}
-	return false
+	return ""
 }
 
-func (x *UpsertRoleOutput) GetGrant() *RoleScopeGrant {
+func (x *CedarSchemaOutput) GetSchema() string {
 	if x != nil {
-		return x.Grant
+		return x.Schema
 	}
-	return nil
+	return ""
 }
 
-func (x *UpsertRoleOutput) GetError() string {
+func (x *CedarSchemaOutput) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type AuditModuleConfig struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sink          string                 `protobuf:"bytes,1,opt,name=sink,proto3" json:"sink,omitempty"`
+	Path          string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	Driver        string                 `protobuf:"bytes,3,opt,name=driver,proto3" json:"driver,omitempty"`
+	Dsn           string                 `protobuf:"bytes,4,opt,name=dsn,proto3" json:"dsn,omitempty"`
+	TableName     string                 `protobuf:"bytes,5,opt,name=table_name,json=tableName,proto3" json:"table_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuditModuleConfig) Reset() {
+	*x = AuditModuleConfig{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[131]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuditModuleConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditModuleConfig) ProtoMessage() {}
+
+func (x *AuditModuleConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[131]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditModuleConfig.ProtoReflect.Descriptor instead.
+func (*AuditModuleConfig) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{131}
+}
+
+func (x *AuditModuleConfig) GetSink() string {
+	if x != nil {
+		return x.Sink
+	}
+	return ""
+}
+
+func (x *AuditModuleConfig) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *AuditModuleConfig) GetDriver() string {
+	if x != nil {
+		return x.Driver
+	}
+	return ""
+}
+
+func (x *AuditModuleConfig) GetDsn() string {
+	if x != nil {
+		return x.Dsn
+	}
+	return ""
+}
+
+func (x *AuditModuleConfig) GetTableName() string {
 	if x != nil {
-		return x.Error
+		return x.TableName
 	}
 	return ""
 }
 
-type AssignRoleInput struct {
+type AuditEntry struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Assignment    *SubjectRoleAssignment `protobuf:"bytes,1,opt,name=assignment,proto3" json:"assignment,omitempty"`
+	Seq           uint64                 `protobuf:"varint,1,opt,name=seq,proto3" json:"seq,omitempty"`
+	Time          string                 `protobuf:"bytes,2,opt,name=time,proto3" json:"time,omitempty"`
+	Actor         string                 `protobuf:"bytes,3,opt,name=actor,proto3" json:"actor,omitempty"`
+	ActorEmail    string                 `protobuf:"bytes,4,opt,name=actor_email,json=actorEmail,proto3" json:"actor_email,omitempty"`
+	Source        string                 `protobuf:"bytes,5,opt,name=source,proto3" json:"source,omitempty"`
+	Operation     string                 `protobuf:"bytes,6,opt,name=operation,proto3" json:"operation,omitempty"`
+	Module        string                 `protobuf:"bytes,7,opt,name=module,proto3" json:"module,omitempty"`
+	Resource      string                 `protobuf:"bytes,8,opt,name=resource,proto3" json:"resource,omitempty"`
+	Action        string                 `protobuf:"bytes,9,opt,name=action,proto3" json:"action,omitempty"`
+	Context       string                 `protobuf:"bytes,10,opt,name=context,proto3" json:"context,omitempty"`
+	Before        *structpb.Struct       `protobuf:"bytes,11,opt,name=before,proto3" json:"before,omitempty"`
+	After         *structpb.Struct       `protobuf:"bytes,12,opt,name=after,proto3" json:"after,omitempty"`
+	Reason        string                 `protobuf:"bytes,13,opt,name=reason,proto3" json:"reason,omitempty"`
+	PrevHash      string                 `protobuf:"bytes,14,opt,name=prev_hash,json=prevHash,proto3" json:"prev_hash,omitempty"`
+	Hash          string                 `protobuf:"bytes,15,opt,name=hash,proto3" json:"hash,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AssignRoleInput) Reset() {
-	*x = AssignRoleInput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[91]
+func (x *AuditEntry) Reset() {
+	*x = AuditEntry{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[132]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AssignRoleInput) String() string {
+func (x *AuditEntry) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AssignRoleInput) ProtoMessage() {}
+func (*AuditEntry) ProtoMessage() {}
 
-func (x *AssignRoleInput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[91]
+func (x *AuditEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[132]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -6467,100 +9701,144 @@ func (x *AssignRoleInput) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AssignRoleInput.ProtoReflect.Descriptor instead.
-func (*AssignRoleInput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{91}
+// Deprecated: Use AuditEntry.ProtoReflect.Descriptor instead.
+func (*AuditEntry) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{132}
 }
 
-func (x *AssignRoleInput) GetAssignment() *SubjectRoleAssignment {
+func (x *AuditEntry) GetSeq() uint64 {
 	if x != nil {
-		return x.Assignment
+		return x.Seq
 	}
-	return nil
+	return 0
 }
 
-type AssignRoleOutput struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Changed       bool                   `protobuf:"varint,1,opt,name=changed,proto3" json:"changed,omitempty"`
-	Assignment    *SubjectRoleAssignment `protobuf:"bytes,2,opt,name=assignment,proto3" json:"assignment,omitempty"`
-	Error         string                 `protobuf:"bytes,100,opt,name=error,proto3" json:"error,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *AuditEntry) GetTime() string {
+	if x != nil {
+		return x.Time
+	}
+	return ""
 }
 
-func (x *AssignRoleOutput) Reset() {
-	*x = AssignRoleOutput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[92]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *AuditEntry) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
 }
 
-func (x *AssignRoleOutput) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *AuditEntry) GetActorEmail() string {
+	if x != nil {
+		return x.ActorEmail
+	}
+	return ""
 }
 
-func (*AssignRoleOutput) ProtoMessage() {}
+func (x *AuditEntry) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
 
-func (x *AssignRoleOutput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[92]
+func (x *AuditEntry) GetOperation() string {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Operation
 	}
-	return mi.MessageOf(x)
+	return ""
 }
 
-// Deprecated: Use AssignRoleOutput.ProtoReflect.Descriptor instead.
-func (*AssignRoleOutput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{92}
+func (x *AuditEntry) GetModule() string {
+	if x != nil {
+		return x.Module
+	}
+	return ""
 }
 
-func (x *AssignRoleOutput) GetChanged() bool {
+func (x *AuditEntry) GetResource() string {
 	if x != nil {
-		return x.Changed
+		return x.Resource
 	}
-	return false
+	return ""
 }
 
-func (x *AssignRoleOutput) GetAssignment() *SubjectRoleAssignment {
+func (x *AuditEntry) GetAction() string {
 	if x != nil {
-		return x.Assignment
+		return x.Action
+	}
+	return ""
+}
+
+func (x *AuditEntry) GetContext() string {
+	if x != nil {
+		return x.Context
+	}
+	return ""
+}
+
+func (x *AuditEntry) GetBefore() *structpb.Struct {
+	if x != nil {
+		return x.Before
 	}
 	return nil
 }
 
-func (x *AssignRoleOutput) GetError() string {
+func (x *AuditEntry) GetAfter() *structpb.Struct {
 	if x != nil {
-		return x.Error
+		return x.After
+	}
+	return nil
+}
+
+func (x *AuditEntry) GetReason() string {
+	if x != nil {
+		return x.Reason
 	}
 	return ""
 }
 
-type ListRoleAssignmentsInput struct {
+func (x *AuditEntry) GetPrevHash() string {
+	if x != nil {
+		return x.PrevHash
+	}
+	return ""
+}
+
+func (x *AuditEntry) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+type ListAuditEntriesInput struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Filter        *AssignmentFilter      `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	Actor         string                 `protobuf:"bytes,1,opt,name=actor,proto3" json:"actor,omitempty"`
+	Operation     string                 `protobuf:"bytes,2,opt,name=operation,proto3" json:"operation,omitempty"`
+	Resource      string                 `protobuf:"bytes,3,opt,name=resource,proto3" json:"resource,omitempty"`
+	Context       string                 `protobuf:"bytes,4,opt,name=context,proto3" json:"context,omitempty"`
+	Since         string                 `protobuf:"bytes,5,opt,name=since,proto3" json:"since,omitempty"`
+	Until         string                 `protobuf:"bytes,6,opt,name=until,proto3" json:"until,omitempty"`
+	Limit         int32                  `protobuf:"varint,7,opt,name=limit,proto3" json:"limit,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListRoleAssignmentsInput) Reset() {
-	*x = ListRoleAssignmentsInput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[93]
+func (x *ListAuditEntriesInput) Reset() {
+	*x = ListAuditEntriesInput{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[133]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListRoleAssignmentsInput) String() string {
+func (x *ListAuditEntriesInput) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListRoleAssignmentsInput) ProtoMessage() {}
+func (*ListAuditEntriesInput) ProtoMessage() {}
 
-func (x *ListRoleAssignmentsInput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[93]
+func (x *ListAuditEntriesInput) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[133]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -6571,41 +9849,82 @@ func (x *ListRoleAssignmentsInput) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListRoleAssignmentsInput.ProtoReflect.Descriptor instead.
-func (*ListRoleAssignmentsInput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{93}
+// Deprecated: Use ListAuditEntriesInput.ProtoReflect.Descriptor instead.
+func (*ListAuditEntriesInput) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{133}
 }
 
-func (x *ListRoleAssignmentsInput) GetFilter() *AssignmentFilter {
+func (x *ListAuditEntriesInput) GetActor() string {
 	if x != nil {
-		return x.Filter
+		return x.Actor
 	}
-	return nil
+	return ""
 }
 
-type ListRoleAssignmentsOutput struct {
-	state         protoimpl.MessageState   `protogen:"open.v1"`
-	Assignments   []*SubjectRoleAssignment `protobuf:"bytes,1,rep,name=assignments,proto3" json:"assignments,omitempty"`
-	Error         string                   `protobuf:"bytes,100,opt,name=error,proto3" json:"error,omitempty"`
+func (x *ListAuditEntriesInput) GetOperation() string {
+	if x != nil {
+		return x.Operation
+	}
+	return ""
+}
+
+func (x *ListAuditEntriesInput) GetResource() string {
+	if x != nil {
+		return x.Resource
+	}
+	return ""
+}
+
+func (x *ListAuditEntriesInput) GetContext() string {
+	if x != nil {
+		return x.Context
+	}
+	return ""
+}
+
+func (x *ListAuditEntriesInput) GetSince() string {
+	if x != nil {
+		return x.Since
+	}
+	return ""
+}
+
+func (x *ListAuditEntriesInput) GetUntil() string {
+	if x != nil {
+		return x.Until
+	}
+	return ""
+}
+
+func (x *ListAuditEntriesInput) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ListAuditEntriesOutput struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entries       []*AuditEntry          `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListRoleAssignmentsOutput) Reset() {
-	*x = ListRoleAssignmentsOutput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[94]
+func (x *ListAuditEntriesOutput) Reset() {
+	*x = ListAuditEntriesOutput{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[134]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListRoleAssignmentsOutput) String() string {
+func (x *ListAuditEntriesOutput) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListRoleAssignmentsOutput) ProtoMessage() {}
+func (*ListAuditEntriesOutput) ProtoMessage() {}
 
-func (x *ListRoleAssignmentsOutput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[94]
+func (x *ListAuditEntriesOutput) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[134]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -6616,47 +9935,39 @@ func (x *ListRoleAssignmentsOutput) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListRoleAssignmentsOutput.ProtoReflect.Descriptor instead.
-func (*ListRoleAssignmentsOutput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{94}
+// Deprecated: Use ListAuditEntriesOutput.ProtoReflect.Descriptor instead.
+func (*ListAuditEntriesOutput) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{134}
 }
 
-func (x *ListRoleAssignmentsOutput) GetAssignments() []*SubjectRoleAssignment {
+func (x *ListAuditEntriesOutput) GetEntries() []*AuditEntry {
 	if x != nil {
-		return x.Assignments
+		return x.Entries
 	}
 	return nil
 }
 
-func (x *ListRoleAssignmentsOutput) GetError() string {
-	if x != nil {
-		return x.Error
-	}
-	return ""
-}
-
-type RemoveRoleAssignmentInput struct {
+type VerifyAuditChainInput struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Assignment    *SubjectRoleAssignment `protobuf:"bytes,1,opt,name=assignment,proto3" json:"assignment,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RemoveRoleAssignmentInput) Reset() {
-	*x = RemoveRoleAssignmentInput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[95]
+func (x *VerifyAuditChainInput) Reset() {
+	*x = VerifyAuditChainInput{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[135]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RemoveRoleAssignmentInput) String() string {
+func (x *VerifyAuditChainInput) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RemoveRoleAssignmentInput) ProtoMessage() {}
+func (*VerifyAuditChainInput) ProtoMessage() {}
 
-func (x *RemoveRoleAssignmentInput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[95]
+func (x *VerifyAuditChainInput) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[135]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -6667,41 +9978,36 @@ func (x *RemoveRoleAssignmentInput) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RemoveRoleAssignmentInput.ProtoReflect.Descriptor instead.
-func (*RemoveRoleAssignmentInput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{95}
-}
-
-func (x *RemoveRoleAssignmentInput) GetAssignment() *SubjectRoleAssignment {
-	if x != nil {
-		return x.Assignment
-	}
-	return nil
+// Deprecated: Use VerifyAuditChainInput.ProtoReflect.Descriptor instead.
+func (*VerifyAuditChainInput) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{135}
 }
 
-type RemoveRoleAssignmentOutput struct {
+type VerifyAuditChainOutput struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Changed       bool                   `protobuf:"varint,1,opt,name=changed,proto3" json:"changed,omitempty"`
-	Error         string                 `protobuf:"bytes,100,opt,name=error,proto3" json:"error,omitempty"`
+	Valid         bool                   `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+	Checked       int32                  `protobuf:"varint,2,opt,name=checked,proto3" json:"checked,omitempty"`
+	BrokenSeq     uint64                 `protobuf:"varint,3,opt,name=broken_seq,json=brokenSeq,proto3" json:"broken_seq,omitempty"`
+	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RemoveRoleAssignmentOutput) Reset() {
-	*x = RemoveRoleAssignmentOutput{}
-	mi := &file_internal_contracts_authz_proto_msgTypes[96]
+func (x *VerifyAuditChainOutput) Reset() {
+	*x = VerifyAuditChainOutput{}
+	mi := &file_internal_contracts_authz_proto_msgTypes[136]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RemoveRoleAssignmentOutput) String() string {
+func (x *VerifyAuditChainOutput) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RemoveRoleAssignmentOutput) ProtoMessage() {}
+func (*VerifyAuditChainOutput) ProtoMessage() {}
 
-func (x *RemoveRoleAssignmentOutput) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_contracts_authz_proto_msgTypes[96]
+func (x *VerifyAuditChainOutput) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_contracts_authz_proto_msgTypes[136]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -6712,21 +10018,35 @@ func (x *RemoveRoleAssignmentOutput) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RemoveRoleAssignmentOutput.ProtoReflect.Descriptor instead.
-func (*RemoveRoleAssignmentOutput) Descriptor() ([]byte, []int) {
-	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{96}
+// Deprecated: Use VerifyAuditChainOutput.ProtoReflect.Descriptor instead.
+func (*VerifyAuditChainOutput) Descriptor() ([]byte, []int) {
+	return file_internal_contracts_authz_proto_rawDescGZIP(), []int{136}
 }
 
-func (x *RemoveRoleAssignmentOutput) GetChanged() bool {
+func (x *VerifyAuditChainOutput) GetValid() bool {
 	if x != nil {
-		return x.Changed
+		return x.Valid
 	}
 	return false
 }
 
-func (x *RemoveRoleAssignmentOutput) GetError() string {
+func (x *VerifyAuditChainOutput) GetChecked() int32 {
 	if x != nil {
-		return x.Error
+		return x.Checked
+	}
+	return 0
+}
+
+func (x *VerifyAuditChainOutput) GetBrokenSeq() uint64 {
+	if x != nil {
+		return x.BrokenSeq
+	}
+	return 0
+}
+
+func (x *VerifyAuditChainOutput) GetReason() string {
+	if x != nil {
+		return x.Reason
 	}
 	return ""
 }
@@ -6751,22 +10071,50 @@ const file_internal_contracts_authz_proto_rawDesc = "" +
 	"\ffilter_value\x18\b \x01(\tR\vfilterValue\"?\n" +
 	"\rWatcherConfig\x12\x12\n" +
 	"\x04type\x18\x01 \x01(\tR\x04type\x12\x1a\n" +
-	"\binterval\x18\x02 \x01(\tR\binterval\"\xc7\x02\n" +
+	"\binterval\x18\x02 \x01(\tR\binterval\"\xb8\x03\n" +
 	"\x12CasbinModuleConfig\x12\x14\n" +
 	"\x05model\x18\x01 \x01(\tR\x05model\x12A\n" +
 	"\bpolicies\x18\x02 \x03(\v2%.workflow.plugins.authz.v1.StringListR\bpolicies\x12P\n" +
 	"\x10role_assignments\x18\x03 \x03(\v2%.workflow.plugins.authz.v1.StringListR\x0froleAssignments\x12B\n" +
 	"\aadapter\x18\x04 \x01(\v2(.workflow.plugins.authz.v1.AdapterConfigR\aadapter\x12B\n" +
-	"\awatcher\x18\x05 \x01(\v2(.workflow.plugins.authz.v1.WatcherConfigR\awatcher\"\x9b\x01\n" +
+	"\awatcher\x18\x05 \x01(\v2(.workflow.plugins.authz.v1.WatcherConfigR\awatcher\x12\x18\n" +
+	"\acatalog\x18\x06 \x01(\tR\acatalog\x12U\n" +
+	"\x0erelation_model\x18\a \x01(\v2..workflow.plugins.authz.v1.RelationModelConfigR\rrelationModel\"Y\n" +
+	"\x13RelationModelConfig\x12\x16\n" +
+	"\x06format\x18\x01 \x01(\tR\x06format\x12\x16\n" +
+	"\x06schema\x18\x02 \x01(\tR\x06schema\x12\x12\n" +
+	"\x04file\x18\x03 \x01(\tR\x04file\"\xf4\x01\n" +
+	"\x0fTransportConfig\x12\x18\n" +
+	"\atimeout\x18\x01 \x01(\tR\atimeout\x12$\n" +
+	"\vmax_retries\x18\x02 \x01(\x05H\x00R\n" +
+	"maxRetries\x88\x01\x01\x12\x18\n" +
+	"\abackoff\x18\x03 \x01(\tR\abackoff\x12\x1f\n" +
+	"\vmax_backoff\x18\x04 \x01(\tR\n" +
+	"maxBackoff\x12+\n" +
+	"\x11breaker_threshold\x18\x05 \x01(\x05R\x10breakerThreshold\x12)\n" +
+	"\x10breaker_cooldown\x18\x06 \x01(\tR\x0fbreakerCooldownB\x0e\n" +
+	"\f_max_retries\"k\n" +
+	"\x0eLocalPDPConfig\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12\x1a\n" +
+	"\binterval\x18\x02 \x01(\tR\binterval\x12#\n" +
+	"\rmax_staleness\x18\x03 \x01(\tR\fmaxStaleness\"\xee\x02\n" +
 	"\x12PermitModuleConfig\x12\x17\n" +
 	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\x12\x17\n" +
 	"\apdp_url\x18\x02 \x01(\tR\x06pdpUrl\x12\x17\n" +
 	"\aapi_url\x18\x03 \x01(\tR\x06apiUrl\x12\x18\n" +
 	"\aproject\x18\x04 \x01(\tR\aproject\x12 \n" +
-	"\venvironment\x18\x05 \x01(\tR\venvironment\"J\n" +
+	"\venvironment\x18\x05 \x01(\tR\venvironment\x12\x18\n" +
+	"\acatalog\x18\x06 \x01(\tR\acatalog\x12H\n" +
+	"\ttransport\x18\a \x01(\v2*.workflow.plugins.authz.v1.TransportConfigR\ttransport\x12%\n" +
+	"\x0eon_unavailable\x18\b \x01(\tR\ronUnavailable\x12F\n" +
+	"\tlocal_pdp\x18\t \x01(\v2).workflow.plugins.authz.v1.LocalPDPConfigR\blocalPdp\"\x99\x02\n" +
 	"\x10KetoModuleConfig\x12\x19\n" +
 	"\bread_url\x18\x01 \x01(\tR\areadUrl\x12\x1b\n" +
-	"\twrite_url\x18\x02 \x01(\tR\bwriteUrl\"4\n" +
+	"\twrite_url\x18\x02 \x01(\tR\bwriteUrl\x12\x18\n" +
+	"\acatalog\x18\x03 \x01(\tR\acatalog\x12B\n" +
+	"\awatcher\x18\x04 \x01(\v2(.workflow.plugins.authz.v1.WatcherConfigR\awatcher\x12H\n" +
+	"\ttransport\x18\x05 \x01(\v2*.workflow.plugins.authz.v1.TransportConfigR\ttransport\x12%\n" +
+	"\x0eon_unavailable\x18\x06 \x01(\tR\ronUnavailable\"4\n" +
 	"\n" +
 	"ExtraField\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
@@ -6795,25 +10143,41 @@ const file_internal_contracts_authz_proto_rawDesc = "" +
 	"\x0fresponse_status\x18\x05 \x01(\x05R\x0eresponseStatus\x12#\n" +
 	"\rresponse_body\x18\x06 \x01(\tR\fresponseBody\x12B\n" +
 	"\x10response_headers\x18\a \x01(\v2\x17.google.protobuf.StructR\x0fresponseHeaders\x12\x14\n" +
-	"\x05error\x18d \x01(\tR\x05error\">\n" +
+	"\x05error\x18d \x01(\tR\x05error\"\x8f\x01\n" +
 	"\x10PolicyRuleConfig\x12\x16\n" +
 	"\x06module\x18\x01 \x01(\tR\x06module\x12\x12\n" +
-	"\x04rule\x18\x02 \x03(\tR\x04rule\"=\n" +
+	"\x04rule\x18\x02 \x03(\tR\x04rule\x12\x14\n" +
+	"\x05actor\x18\n" +
+	" \x01(\tR\x05actor\x12\x16\n" +
+	"\x06reason\x18\v \x01(\tR\x06reason\x12!\n" +
+	"\faudit_module\x18\f \x01(\tR\vauditModule\"\x8e\x01\n" +
 	"\x0fPolicyRuleInput\x12\x16\n" +
 	"\x06module\x18\x01 \x01(\tR\x06module\x12\x12\n" +
-	"\x04rule\x18\x02 \x03(\tR\x04rule\"V\n" +
+	"\x04rule\x18\x02 \x03(\tR\x04rule\x12\x14\n" +
+	"\x05actor\x18\n" +
+	" \x01(\tR\x05actor\x12\x16\n" +
+	"\x06reason\x18\v \x01(\tR\x06reason\x12!\n" +
+	"\faudit_module\x18\f \x01(\tR\vauditModule\"V\n" +
 	"\x10PolicyRuleOutput\x12\x18\n" +
 	"\achanged\x18\x01 \x01(\bR\achanged\x12\x12\n" +
 	"\x04rule\x18\x02 \x03(\tR\x04rule\x12\x14\n" +
-	"\x05error\x18d \x01(\tR\x05error\"\x8b\x01\n" +
+	"\x05error\x18d \x01(\tR\x05error\"\xdc\x01\n" +
 	"\x10RoleAssignConfig\x12\x16\n" +
 	"\x06module\x18\x01 \x01(\tR\x06module\x12\x16\n" +
 	"\x06action\x18\x02 \x01(\tR\x06action\x12G\n" +
-	"\vassignments\x18\x03 \x03(\v2%.workflow.plugins.authz.v1.StringListR\vassignments\"\x8a\x01\n" +
+	"\vassignments\x18\x03 \x03(\v2%.workflow.plugins.authz.v1.StringListR\vassignments\x12\x14\n" +
+	"\x05actor\x18\n" +
+	" \x01(\tR\x05actor\x12\x16\n" +
+	"\x06reason\x18\v \x01(\tR\x06reason\x12!\n" +
+	"\faudit_module\x18\f \x01(\tR\vauditModule\"\xdb\x01\n" +
 	"\x0fRoleAssignInput\x12\x16\n" +
 	"\x06module\x18\x01 \x01(\tR\x06module\x12\x16\n" +
 	"\x06action\x18\x02 \x01(\tR\x06action\x12G\n" +
-	"\vassignments\x18\x03 \x03(\v2%.workflow.plugins.authz.v1.StringListR\vassignments\"\x89\x01\n" +
+	"\vassignments\x18\x03 \x03(\v2%.workflow.plugins.authz.v1.StringListR\vassignments\x12\x14\n" +
+	"\x05actor\x18\n" +
+	" \x01(\tR\x05actor\x12\x16\n" +
+	"\x06reason\x18\v \x01(\tR\x06reason\x12!\n" +
+	"\faudit_module\x18\f \x01(\tR\vauditModule\"\x89\x01\n" +
 	"\x10RoleAssignOutput\x12\x16\n" +
 	"\x06action\x18\x01 \x01(\tR\x06action\x12G\n" +
 	"\vassignments\x18\x02 \x03(\v2%.workflow.plugins.authz.v1.StringListR\vassignments\x12\x14\n" +
@@ -6859,7 +10223,7 @@ const file_internal_contracts_authz_proto_rawDesc = "" +
 	"\vdescriptors\x18\x04 \x03(\v2/.workflow.plugins.authz.v1.CapabilityDescriptorR\vdescriptors\x12\x16\n" +
 	"\x06health\x18\x05 \x01(\tR\x06health\x121\n" +
 	"\x14missing_requirements\x18\x06 \x03(\tR\x13missingRequirements\x12\x14\n" +
-	"\x05error\x18d \x01(\tR\x05error\"\xbf\x02\n" +
+	"\x05error\x18d \x01(\tR\x05error\"\xe6\x02\n" +
 	"\x1bAuthorizationDecisionConfig\x12\x16\n" +
 	"\x06module\x18\x01 \x01(\tR\x06module\x12\x1a\n" +
 	"\bprovider\x18\x02 \x01(\tR\bprovider\x128\n" +
@@ -6871,7 +10235,8 @@ const file_internal_contracts_authz_proto_rawDesc = "" +
 	"\x05scope\x18\b \x01(\tR\x05scope\x12\x1a\n" +
 	"\brelation\x18\t \x01(\tR\brelation\x12\x18\n" +
 	"\aexplain\x18\n" +
-	" \x01(\bR\aexplain\"\xa0\x04\n" +
+	" \x01(\bR\aexplain\x12%\n" +
+	"\x0eon_unavailable\x18\v \x01(\tR\ronUnavailable\"\xa0\x04\n" +
 	"\x1aAuthorizationDecisionInput\x12\x16\n" +
 	"\x06module\x18\x01 \x01(\tR\x06module\x12\x1a\n" +
 	"\bprovider\x18\x02 \x01(\tR\bprovider\x128\n" +
@@ -6902,17 +10267,25 @@ const file_internal_contracts_authz_proto_rawDesc = "" +
 	"\x18RequireCapabilitiesInput\x12\x16\n" +
 	"\x06module\x18\x01 \x01(\tR\x06module\x12\x1a\n" +
 	"\bprovider\x18\x02 \x01(\tR\bprovider\x12T\n" +
-	"\frequirements\x18\x03 \x03(\v20.workflow.plugins.authz.v1.CapabilityRequirementR\frequirements\"}\n" +
+	"\frequirements\x18\x03 \x03(\v20.workflow.plugins.authz.v1.CapabilityRequirementR\frequirements\"\xce\x01\n" +
 	"\x19SubjectObjectActionConfig\x12\x16\n" +
 	"\x06module\x18\x01 \x01(\tR\x06module\x12\x18\n" +
 	"\asubject\x18\x02 \x01(\tR\asubject\x12\x16\n" +
 	"\x06object\x18\x03 \x01(\tR\x06object\x12\x16\n" +
-	"\x06action\x18\x04 \x01(\tR\x06action\"|\n" +
+	"\x06action\x18\x04 \x01(\tR\x06action\x12\x14\n" +
+	"\x05actor\x18\n" +
+	" \x01(\tR\x05actor\x12\x16\n" +
+	"\x06reason\x18\v \x01(\tR\x06reason\x12!\n" +
+	"\faudit_module\x18\f \x01(\tR\vauditModule\"\xcd\x01\n" +
 	"\x18SubjectObjectActionInput\x12\x16\n" +
 	"\x06module\x18\x01 \x01(\tR\x06module\x12\x18\n" +
 	"\asubject\x18\x02 \x01(\tR\asubject\x12\x16\n" +
 	"\x06object\x18\x03 \x01(\tR\x06object\x12\x16\n" +
-	"\x06action\x18\x04 \x01(\tR\x06action\"\xaf\x01\n" +
+	"\x06action\x18\x04 \x01(\tR\x06action\x12\x14\n" +
+	"\x05actor\x18\n" +
+	" \x01(\tR\x05actor\x12\x16\n" +
+	"\x06reason\x18\v \x01(\tR\x06reason\x12!\n" +
+	"\faudit_module\x18\f \x01(\tR\vauditModule\"\xaf\x01\n" +
 	"\x19SubjectObjectActionOutput\x12\x18\n" +
 	"\aallowed\x18\x01 \x01(\bR\aallowed\x12\x18\n" +
 	"\achanged\x18\x02 \x01(\bR\achanged\x12\x18\n" +
@@ -6931,17 +10304,25 @@ const file_internal_contracts_authz_proto_rawDesc = "" +
 	"\x05value\x18\x03 \x01(\tR\x05value\"Z\n" +
 	"\x11GenericStepOutput\x12/\n" +
 	"\x06output\x18\x01 \x01(\v2\x17.google.protobuf.StructR\x06output\x12\x14\n" +
-	"\x05error\x18d \x01(\tR\x05error\"v\n" +
+	"\x05error\x18d \x01(\tR\x05error\"\xc7\x01\n" +
 	"\x0eRelationConfig\x12\x16\n" +
 	"\x06module\x18\x01 \x01(\tR\x06module\x12\x18\n" +
 	"\asubject\x18\x02 \x01(\tR\asubject\x12\x1a\n" +
 	"\brelation\x18\x03 \x01(\tR\brelation\x12\x16\n" +
-	"\x06object\x18\x04 \x01(\tR\x06object\"u\n" +
+	"\x06object\x18\x04 \x01(\tR\x06object\x12\x14\n" +
+	"\x05actor\x18\n" +
+	" \x01(\tR\x05actor\x12\x16\n" +
+	"\x06reason\x18\v \x01(\tR\x06reason\x12!\n" +
+	"\faudit_module\x18\f \x01(\tR\vauditModule\"\xc6\x01\n" +
 	"\rRelationInput\x12\x16\n" +
 	"\x06module\x18\x01 \x01(\tR\x06module\x12\x18\n" +
 	"\asubject\x18\x02 \x01(\tR\asubject\x12\x1a\n" +
 	"\brelation\x18\x03 \x01(\tR\brelation\x12\x16\n" +
-	"\x06object\x18\x04 \x01(\tR\x06object\"\x8e\x01\n" +
+	"\x06object\x18\x04 \x01(\tR\x06object\x12\x14\n" +
+	"\x05actor\x18\n" +
+	" \x01(\tR\x05actor\x12\x16\n" +
+	"\x06reason\x18\v \x01(\tR\x06reason\x12!\n" +
+	"\faudit_module\x18\f \x01(\tR\vauditModule\"\x8e\x01\n" +
 	"\x0eRelationOutput\x12\x18\n" +
 	"\achanged\x18\x01 \x01(\bR\achanged\x12\x18\n" +
 	"\asubject\x18\x02 \x01(\tR\asubject\x12\x1a\n" +
@@ -6953,7 +10334,7 @@ const file_internal_contracts_authz_proto_rawDesc = "" +
 	"\x06values\x18\x02 \x01(\v2\x17.google.protobuf.StructR\x06values\"Z\n" +
 	"\x0fPermitStepInput\x12\x16\n" +
 	"\x06module\x18\x01 \x01(\tR\x06module\x12/\n" +
-	"\x06values\x18\x02 \x01(\v2\x17.google.protobuf.StructR\x06values\"\xfa\x01\n" +
+	"\x06values\x18\x02 \x01(\v2\x17.google.protobuf.StructR\x06values\"\xd5\x02\n" +
 	"\x10ScopeDeclaration\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
 	"\acontext\x18\x02 \x01(\tR\acontext\x12\x1a\n" +
@@ -6962,20 +10343,34 @@ const file_internal_contracts_authz_proto_rawDesc = "" +
 	"\vdescription\x18\x05 \x01(\tR\vdescription\x12!\n" +
 	"\fowner_plugin\x18\x06 \x01(\tR\vownerPlugin\x12!\n" +
 	"\fowner_module\x18\a \x01(\tR\vownerModule\x12\x1a\n" +
-	"\bcategory\x18\b \x01(\tR\bcategory\"\xeb\x01\n" +
+	"\bcategory\x18\b \x01(\tR\bcategory\x12\x1e\n" +
+	"\n" +
+	"deprecated\x18\t \x01(\bR\n" +
+	"deprecated\x12\x1f\n" +
+	"\vreplaced_by\x18\n" +
+	" \x01(\tR\n" +
+	"replacedBy\x12\x18\n" +
+	"\aversion\x18\v \x01(\tR\aversion\"\x81\x04\n" +
 	"\x12ScopeCatalogConfig\x12C\n" +
 	"\x06scopes\x18\x01 \x03(\v2+.workflow.plugins.authz.v1.ScopeDeclarationR\x06scopes\x12<\n" +
 	"\x1aallow_runtime_registration\x18\x02 \x01(\bR\x18allowRuntimeRegistration\x12R\n" +
-	"\fdeclarations\x18\x03 \x01(\v2..workflow.plugins.authz.v1.AuthzDeclarationSetR\fdeclarations\"\xa0\x01\n" +
+	"\fdeclarations\x18\x03 \x01(\v2..workflow.plugins.authz.v1.AuthzDeclarationSetR\fdeclarations\x124\n" +
+	"\x16projection_signing_key\x18\x04 \x01(\tR\x14projectionSigningKey\x12%\n" +
+	"\x0eprojection_ttl\x18\x05 \x01(\tR\rprojectionTtl\x12/\n" +
+	"\x13ownership_conflicts\x18\x06 \x01(\tR\x12ownershipConflicts\x12B\n" +
+	"\aadapter\x18\a \x01(\v2(.workflow.plugins.authz.v1.AdapterConfigR\aadapter\x12B\n" +
+	"\awatcher\x18\b \x01(\v2(.workflow.plugins.authz.v1.WatcherConfigR\awatcher\"\xa0\x01\n" +
 	"\x13RegisterScopesInput\x12C\n" +
 	"\x06scopes\x18\x01 \x03(\v2+.workflow.plugins.authz.v1.ScopeDeclarationR\x06scopes\x12!\n" +
 	"\fowner_plugin\x18\x02 \x01(\tR\vownerPlugin\x12!\n" +
-	"\fowner_module\x18\x03 \x01(\tR\vownerModule\"\x91\x01\n" +
+	"\fowner_module\x18\x03 \x01(\tR\vownerModule\"\x8c\x02\n" +
 	"\x14RegisterScopesOutput\x12\x1e\n" +
 	"\n" +
 	"registered\x18\x01 \x01(\x05R\n" +
 	"registered\x12C\n" +
-	"\x06scopes\x18\x02 \x03(\v2+.workflow.plugins.authz.v1.ScopeDeclarationR\x06scopes\x12\x14\n" +
+	"\x06scopes\x18\x02 \x03(\v2+.workflow.plugins.authz.v1.ScopeDeclarationR\x06scopes\x12L\n" +
+	"\tconflicts\x18\x03 \x03(\v2..workflow.plugins.authz.v1.DeclarationConflictR\tconflicts\x12+\n" +
+	"\x11subscriber_errors\x18\x04 \x03(\tR\x10subscriberErrors\x12\x14\n" +
 	"\x05error\x18d \x01(\tR\x05error\"q\n" +
 	"\x0fListScopesInput\x12\x18\n" +
 	"\acontext\x18\x01 \x01(\tR\acontext\x12!\n" +
@@ -6983,7 +10378,7 @@ const file_internal_contracts_authz_proto_rawDesc = "" +
 	"\fowner_module\x18\x03 \x01(\tR\vownerModule\"m\n" +
 	"\x10ListScopesOutput\x12C\n" +
 	"\x06scopes\x18\x01 \x03(\v2+.workflow.plugins.authz.v1.ScopeDeclarationR\x06scopes\x12\x14\n" +
-	"\x05error\x18d \x01(\tR\x05error\"\x94\x02\n" +
+	"\x05error\x18d \x01(\tR\x05error\"\xef\x02\n" +
 	"\x13ResourceDeclaration\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
 	"\acontext\x18\x02 \x01(\tR\acontext\x12!\n" +
@@ -6992,7 +10387,14 @@ const file_internal_contracts_authz_proto_rawDesc = "" +
 	"\fowner_plugin\x18\x05 \x01(\tR\vownerPlugin\x12!\n" +
 	"\fowner_module\x18\x06 \x01(\tR\vownerModule\x12\x1a\n" +
 	"\bcategory\x18\a \x01(\tR\bcategory\x12(\n" +
-	"\x10lookup_source_id\x18\b \x01(\tR\x0elookupSourceId\"\xe1\x01\n" +
+	"\x10lookup_source_id\x18\b \x01(\tR\x0elookupSourceId\x12\x1e\n" +
+	"\n" +
+	"deprecated\x18\t \x01(\bR\n" +
+	"deprecated\x12\x1f\n" +
+	"\vreplaced_by\x18\n" +
+	" \x01(\tR\n" +
+	"replacedBy\x12\x18\n" +
+	"\aversion\x18\v \x01(\tR\aversion\"\xe1\x01\n" +
 	"\x11ActionDeclaration\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
 	"\acontext\x18\x02 \x01(\tR\acontext\x12\x1a\n" +
@@ -7135,6 +10537,25 @@ const file_internal_contracts_authz_proto_rawDesc = "" +
 	"\x05tuple\x18\x01 \x01(\v2(.workflow.plugins.authz.v1.RelationTupleR\x05tuple\"K\n" +
 	"\x19RemoveRelationTupleOutput\x12\x18\n" +
 	"\achanged\x18\x01 \x01(\bR\achanged\x12\x14\n" +
+	"\x05error\x18d \x01(\tR\x05error\"\x80\x01\n" +
+	"\x13ExpandRelationInput\x12\x1a\n" +
+	"\brelation\x18\x01 \x01(\tR\brelation\x12\x16\n" +
+	"\x06object\x18\x02 \x01(\tR\x06object\x12\x18\n" +
+	"\acontext\x18\x03 \x01(\tR\acontext\x12\x1b\n" +
+	"\tmax_depth\x18\x04 \x01(\x05R\bmaxDepth\"\x7f\n" +
+	"\vUsersetTree\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x18\n" +
+	"\asubject\x18\x02 \x01(\tR\asubject\x12B\n" +
+	"\bchildren\x18\x03 \x03(\v2&.workflow.plugins.authz.v1.UsersetTreeR\bchildren\"h\n" +
+	"\x14ExpandRelationOutput\x12:\n" +
+	"\x04tree\x18\x01 \x01(\v2&.workflow.plugins.authz.v1.UsersetTreeR\x04tree\x12\x14\n" +
+	"\x05error\x18d \x01(\tR\x05error\"\x19\n" +
+	"\x17GenerateNamespacesInput\"b\n" +
+	"\x18GenerateNamespacesOutput\x12\x10\n" +
+	"\x03opl\x18\x01 \x01(\tR\x03opl\x12\x1e\n" +
+	"\n" +
+	"namespaces\x18\x02 \x03(\tR\n" +
+	"namespaces\x12\x14\n" +
 	"\x05error\x18d \x01(\tR\x05error\"\xff\x02\n" +
 	"\x13UIActionDeclaration\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x18\n" +
@@ -7163,12 +10584,33 @@ const file_internal_contracts_authz_proto_rawDesc = "" +
 	"\x19RegisterDeclarationsInput\x12R\n" +
 	"\fdeclarations\x18\x01 \x01(\v2..workflow.plugins.authz.v1.AuthzDeclarationSetR\fdeclarations\x12!\n" +
 	"\fowner_plugin\x18\x02 \x01(\tR\vownerPlugin\x12!\n" +
-	"\fowner_module\x18\x03 \x01(\tR\vownerModule\"\xa6\x01\n" +
+	"\fowner_module\x18\x03 \x01(\tR\vownerModule\"\xa1\x02\n" +
 	"\x1aRegisterDeclarationsOutput\x12\x1e\n" +
 	"\n" +
 	"registered\x18\x01 \x01(\x05R\n" +
 	"registered\x12R\n" +
-	"\fdeclarations\x18\x02 \x01(\v2..workflow.plugins.authz.v1.AuthzDeclarationSetR\fdeclarations\x12\x14\n" +
+	"\fdeclarations\x18\x02 \x01(\v2..workflow.plugins.authz.v1.AuthzDeclarationSetR\fdeclarations\x12L\n" +
+	"\tconflicts\x18\x03 \x03(\v2..workflow.plugins.authz.v1.DeclarationConflictR\tconflicts\x12+\n" +
+	"\x11subscriber_errors\x18\x04 \x03(\tR\x10subscriberErrors\x12\x14\n" +
+	"\x05error\x18d \x01(\tR\x05error\"\x89\x02\n" +
+	"\x13DeclarationConflict\x12\x12\n" +
+	"\x04kind\x18\x01 \x01(\tR\x04kind\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\x122\n" +
+	"\x15existing_owner_plugin\x18\x03 \x01(\tR\x13existingOwnerPlugin\x122\n" +
+	"\x15existing_owner_module\x18\x04 \x01(\tR\x13existingOwnerModule\x12!\n" +
+	"\fowner_plugin\x18\x05 \x01(\tR\vownerPlugin\x12!\n" +
+	"\fowner_module\x18\x06 \x01(\tR\vownerModule\x12\x1e\n" +
+	"\n" +
+	"resolution\x18\a \x01(\tR\n" +
+	"resolution\"y\n" +
+	"\x1bUnregisterDeclarationsInput\x12!\n" +
+	"\fowner_plugin\x18\x01 \x01(\tR\vownerPlugin\x12!\n" +
+	"\fowner_module\x18\x02 \x01(\tR\vownerModule\x12\x14\n" +
+	"\x05force\x18\x03 \x01(\bR\x05force\"\xc7\x01\n" +
+	"\x1cUnregisterDeclarationsOutput\x12\x18\n" +
+	"\aremoved\x18\x01 \x01(\x05R\aremoved\x12R\n" +
+	"\fdeclarations\x18\x02 \x01(\v2..workflow.plugins.authz.v1.AuthzDeclarationSetR\fdeclarations\x12#\n" +
+	"\rforced_scopes\x18\x03 \x03(\tR\fforcedScopes\x12\x14\n" +
 	"\x05error\x18d \x01(\tR\x05error\"w\n" +
 	"\x15ListDeclarationsInput\x12\x18\n" +
 	"\acontext\x18\x01 \x01(\tR\acontext\x12!\n" +
@@ -7194,17 +10636,73 @@ const file_internal_contracts_authz_proto_rawDesc = "" +
 	"\n" +
 	"projection\x18\x01 \x01(\v2+.workflow.plugins.authz.v1.ProjectionInputsR\n" +
 	"projection\x12\x14\n" +
-	"\x05error\x18d \x01(\tR\x05error\"\x95\x01\n" +
+	"\x05error\x18d \x01(\tR\x05error\"\x87\x01\n" +
+	"\x1dResolveSubjectProjectionInput\x12\x18\n" +
+	"\asubject\x18\x01 \x01(\tR\asubject\x12\x16\n" +
+	"\x06module\x18\x02 \x01(\tR\x06module\x12\x1a\n" +
+	"\bprovider\x18\x03 \x01(\tR\bprovider\x12\x18\n" +
+	"\acontext\x18\x04 \x01(\tR\acontext\"c\n" +
+	"\x11PermittedResource\x12\x18\n" +
+	"\acontext\x18\x01 \x01(\tR\acontext\x12\x1a\n" +
+	"\bresource\x18\x02 \x01(\tR\bresource\x12\x18\n" +
+	"\aactions\x18\x03 \x03(\tR\aactions\"\xec\x02\n" +
+	"\x11SubjectProjection\x12\x18\n" +
+	"\asubject\x18\x01 \x01(\tR\asubject\x12\x16\n" +
+	"\x06module\x18\x02 \x01(\tR\x06module\x12\x1a\n" +
+	"\bprovider\x18\x03 \x01(\tR\bprovider\x12\x18\n" +
+	"\acontext\x18\x04 \x01(\tR\acontext\x12%\n" +
+	"\x0egranted_scopes\x18\x05 \x03(\tR\rgrantedScopes\x12\"\n" +
+	"\rui_action_ids\x18\x06 \x03(\tR\vuiActionIds\x12J\n" +
+	"\tresources\x18\a \x03(\v2,.workflow.plugins.authz.v1.PermittedResourceR\tresources\x12\x1b\n" +
+	"\tissued_at\x18\b \x01(\tR\bissuedAt\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\t \x01(\tR\texpiresAt\x12\x1c\n" +
+	"\tsignature\x18\n" +
+	" \x01(\tR\tsignature\"\x84\x01\n" +
+	"\x1eResolveSubjectProjectionOutput\x12L\n" +
+	"\n" +
+	"projection\x18\x01 \x01(\v2,.workflow.plugins.authz.v1.SubjectProjectionR\n" +
+	"projection\x12\x14\n" +
+	"\x05error\x18d \x01(\tR\x05error\"\x9b\x01\n" +
+	"\x17SubjectProjectionConfig\x12\x18\n" +
+	"\acatalog\x18\x01 \x01(\tR\acatalog\x12\x16\n" +
+	"\x06module\x18\x02 \x01(\tR\x06module\x12\x1a\n" +
+	"\bprovider\x18\x03 \x01(\tR\bprovider\x12\x18\n" +
+	"\asubject\x18\x04 \x01(\tR\asubject\x12\x18\n" +
+	"\acontext\x18\x05 \x01(\tR\acontext\"\x9a\x01\n" +
+	"\x16SubjectProjectionInput\x12\x18\n" +
+	"\acatalog\x18\x01 \x01(\tR\acatalog\x12\x16\n" +
+	"\x06module\x18\x02 \x01(\tR\x06module\x12\x1a\n" +
+	"\bprovider\x18\x03 \x01(\tR\bprovider\x12\x18\n" +
+	"\asubject\x18\x04 \x01(\tR\asubject\x12\x18\n" +
+	"\acontext\x18\x05 \x01(\tR\acontext\"\x95\x01\n" +
 	"\x19ResolveSubjectScopesInput\x12\x18\n" +
 	"\asubject\x18\x01 \x01(\tR\asubject\x12#\n" +
 	"\rdirect_scopes\x18\x02 \x03(\tR\fdirectScopes\x12\x1f\n" +
 	"\vrole_scopes\x18\x03 \x03(\tR\n" +
 	"roleScopes\x12\x18\n" +
-	"\acontext\x18\x04 \x01(\tR\acontext\"\xba\x01\n" +
+	"\acontext\x18\x04 \x01(\tR\acontext\"\xde\x01\n" +
 	"\x1aResolveSubjectScopesOutput\x12\x18\n" +
 	"\asubject\x18\x01 \x01(\tR\asubject\x12\x16\n" +
 	"\x06scopes\x18\x02 \x03(\tR\x06scopes\x12T\n" +
-	"\x0fdeclared_scopes\x18\x03 \x03(\v2+.workflow.plugins.authz.v1.ScopeDeclarationR\x0edeclaredScopes\x12\x14\n" +
+	"\x0fdeclared_scopes\x18\x03 \x03(\v2+.workflow.plugins.authz.v1.ScopeDeclarationR\x0edeclaredScopes\x12\"\n" +
+	"\fdeprecations\x18\x04 \x03(\tR\fdeprecations\x12\x14\n" +
+	"\x05error\x18d \x01(\tR\x05error\"G\n" +
+	"\x12MigrateScopesInput\x12\x18\n" +
+	"\acontext\x18\x01 \x01(\tR\acontext\x12\x17\n" +
+	"\adry_run\x18\x02 \x01(\bR\x06dryRun\"\x98\x01\n" +
+	"\x0eScopeMigration\x12\x1a\n" +
+	"\bprovider\x18\x01 \x01(\tR\bprovider\x12\x18\n" +
+	"\acontext\x18\x02 \x01(\tR\acontext\x12\x12\n" +
+	"\x04role\x18\x03 \x01(\tR\x04role\x12\x18\n" +
+	"\asubject\x18\x04 \x01(\tR\asubject\x12\x12\n" +
+	"\x04from\x18\x05 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x06 \x01(\tR\x02to\"\x92\x01\n" +
+	"\x13MigrateScopesOutput\x12\x1a\n" +
+	"\bmigrated\x18\x01 \x01(\x05R\bmigrated\x12I\n" +
+	"\n" +
+	"migrations\x18\x02 \x03(\v2).workflow.plugins.authz.v1.ScopeMigrationR\n" +
+	"migrations\x12\x14\n" +
 	"\x05error\x18d \x01(\tR\x05error\"V\n" +
 	"\x0eRoleScopeGrant\x12\x12\n" +
 	"\x04role\x18\x01 \x01(\tR\x04role\x12\x18\n" +
@@ -7262,7 +10760,150 @@ const file_internal_contracts_authz_proto_rawDesc = "" +
 	"assignment\"L\n" +
 	"\x1aRemoveRoleAssignmentOutput\x12\x18\n" +
 	"\achanged\x18\x01 \x01(\bR\achanged\x12\x14\n" +
-	"\x05error\x18d \x01(\tR\x05error*{\n" +
+	"\x05error\x18d \x01(\tR\x05error\"\xe1\x02\n" +
+	"\x10SimulationChange\x12\x0e\n" +
+	"\x02op\x18\x01 \x01(\tR\x02op\x12\x12\n" +
+	"\x04kind\x18\x02 \x01(\tR\x04kind\x12\x12\n" +
+	"\x04rule\x18\x03 \x03(\tR\x04rule\x12?\n" +
+	"\x05grant\x18\x04 \x01(\v2).workflow.plugins.authz.v1.RoleScopeGrantR\x05grant\x12P\n" +
+	"\n" +
+	"assignment\x18\x05 \x01(\v20.workflow.plugins.authz.v1.SubjectRoleAssignmentR\n" +
+	"assignment\x12>\n" +
+	"\x05tuple\x18\x06 \x01(\v2(.workflow.plugins.authz.v1.RelationTupleR\x05tuple\x12B\n" +
+	"\x06policy\x18\a \x01(\v2*.workflow.plugins.authz.v1.AttributePolicyR\x06policy\"\xdb\x03\n" +
+	"\x0fSimulationProbe\x12\x12\n" +
+	"\x04kind\x18\x01 \x01(\tR\x04kind\x128\n" +
+	"\x04mode\x18\x02 \x01(\x0e2$.workflow.plugins.authz.v1.AuthzModeR\x04mode\x12\x18\n" +
+	"\asubject\x18\x03 \x01(\tR\asubject\x12\x18\n" +
+	"\acontext\x18\x04 \x01(\tR\acontext\x12\x1a\n" +
+	"\bresource\x18\x05 \x01(\tR\bresource\x12\x16\n" +
+	"\x06action\x18\x06 \x01(\tR\x06action\x12\x14\n" +
+	"\x05scope\x18\a \x01(\tR\x05scope\x12\x1a\n" +
+	"\brelation\x18\b \x01(\tR\brelation\x12F\n" +
+	"\x12subject_attributes\x18\t \x01(\v2\x17.google.protobuf.StructR\x11subjectAttributes\x12H\n" +
+	"\x13resource_attributes\x18\n" +
+	" \x01(\v2\x17.google.protobuf.StructR\x12resourceAttributes\x12N\n" +
+	"\x16environment_attributes\x18\v \x01(\v2\x17.google.protobuf.StructR\x15environmentAttributes\"\xf8\x01\n" +
+	"\x10SimulationResult\x12@\n" +
+	"\x05probe\x18\x01 \x01(\v2*.workflow.plugins.authz.v1.SimulationProbeR\x05probe\x12\x16\n" +
+	"\x06before\x18\x02 \x01(\bR\x06before\x12\x14\n" +
+	"\x05after\x18\x03 \x01(\bR\x05after\x12#\n" +
+	"\rbefore_reason\x18\x04 \x01(\tR\fbeforeReason\x12!\n" +
+	"\fafter_reason\x18\x05 \x01(\tR\vafterReason\x12\x16\n" +
+	"\x06change\x18\x06 \x01(\tR\x06change\x12\x14\n" +
+	"\x05error\x18\a \x01(\tR\x05error\"\xfd\x01\n" +
+	"\x0eSimulateConfig\x12\x16\n" +
+	"\x06module\x18\x01 \x01(\tR\x06module\x12\x1a\n" +
+	"\bprovider\x18\x02 \x01(\tR\bprovider\x12E\n" +
+	"\achanges\x18\x03 \x03(\v2+.workflow.plugins.authz.v1.SimulationChangeR\achanges\x12B\n" +
+	"\x06probes\x18\x04 \x03(\v2*.workflow.plugins.authz.v1.SimulationProbeR\x06probes\x12,\n" +
+	"\x12all_subject_scopes\x18\x05 \x01(\bR\x10allSubjectScopes\"\xfc\x01\n" +
+	"\rSimulateInput\x12\x16\n" +
+	"\x06module\x18\x01 \x01(\tR\x06module\x12\x1a\n" +
+	"\bprovider\x18\x02 \x01(\tR\bprovider\x12E\n" +
+	"\achanges\x18\x03 \x03(\v2+.workflow.plugins.authz.v1.SimulationChangeR\achanges\x12B\n" +
+	"\x06probes\x18\x04 \x03(\v2*.workflow.plugins.authz.v1.SimulationProbeR\x06probes\x12,\n" +
+	"\x12all_subject_scopes\x18\x05 \x01(\bR\x10allSubjectScopes\"\x80\x02\n" +
+	"\x0eSimulateOutput\x12\x1c\n" +
+	"\tevaluated\x18\x01 \x01(\x05R\tevaluated\x12\x18\n" +
+	"\agranted\x18\x02 \x01(\x05R\agranted\x12\x18\n" +
+	"\arevoked\x18\x03 \x01(\x05R\arevoked\x12A\n" +
+	"\x05flips\x18\x04 \x03(\v2+.workflow.plugins.authz.v1.SimulationResultR\x05flips\x12C\n" +
+	"\x06errors\x18\x05 \x03(\v2+.workflow.plugins.authz.v1.SimulationResultR\x06errors\x12\x14\n" +
+	"\x05error\x18d \x01(\tR\x05error\"\xe1\x01\n" +
+	"\x0eCompositeRoute\x12\x18\n" +
+	"\acontext\x18\x01 \x01(\tR\acontext\x128\n" +
+	"\x04mode\x18\x02 \x01(\x0e2$.workflow.plugins.authz.v1.AuthzModeR\x04mode\x12'\n" +
+	"\x0fresource_prefix\x18\x03 \x01(\tR\x0eresourcePrefix\x12\x1c\n" +
+	"\tproviders\x18\x04 \x03(\tR\tproviders\x12\x18\n" +
+	"\acombine\x18\x05 \x01(\tR\acombine\x12\x1a\n" +
+	"\bfallback\x18\x06 \x03(\tR\bfallback\"\x90\x01\n" +
+	"\x15CompositeModuleConfig\x12A\n" +
+	"\x06routes\x18\x01 \x03(\v2).workflow.plugins.authz.v1.CompositeRouteR\x06routes\x12\x18\n" +
+	"\acombine\x18\x02 \x01(\tR\acombine\x12\x1a\n" +
+	"\bfallback\x18\x03 \x03(\tR\bfallback\"\x9c\x02\n" +
+	"\x0fOPAModuleConfig\x12Q\n" +
+	"\amodules\x18\x01 \x03(\v27.workflow.plugins.authz.v1.OPAModuleConfig.ModulesEntryR\amodules\x12\x1d\n" +
+	"\n" +
+	"policy_dir\x18\x02 \x01(\tR\tpolicyDir\x12+\n" +
+	"\x04data\x18\x03 \x01(\v2\x17.google.protobuf.StructR\x04data\x12\x14\n" +
+	"\x05query\x18\x04 \x01(\tR\x05query\x12\x18\n" +
+	"\acatalog\x18\x05 \x01(\tR\acatalog\x1a:\n" +
+	"\fModulesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"P\n" +
+	"\fOPADataInput\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12,\n" +
+	"\x05value\x18\x02 \x01(\v2\x16.google.protobuf.ValueR\x05value\"S\n" +
+	"\rOPADataOutput\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x18\n" +
+	"\aremoved\x18\x02 \x01(\bR\aremoved\x12\x14\n" +
+	"\x05error\x18d \x01(\tR\x05error\"\xec\x02\n" +
+	"\x11CedarModuleConfig\x12\x1a\n" +
+	"\bpolicies\x18\x01 \x01(\tR\bpolicies\x12\x1f\n" +
+	"\vpolicy_file\x18\x02 \x01(\tR\n" +
+	"policyFile\x12\x16\n" +
+	"\x06schema\x18\x03 \x01(\tR\x06schema\x12\x1f\n" +
+	"\vschema_file\x18\x04 \x01(\tR\n" +
+	"schemaFile\x12\x1e\n" +
+	"\n" +
+	"validation\x18\x05 \x01(\tR\n" +
+	"validation\x126\n" +
+	"\bentities\x18\x06 \x01(\v2\x1a.google.protobuf.ListValueR\bentities\x12#\n" +
+	"\rentities_file\x18\a \x01(\tR\fentitiesFile\x12%\n" +
+	"\x0eprincipal_type\x18\b \x01(\tR\rprincipalType\x12#\n" +
+	"\rresource_type\x18\t \x01(\tR\fresourceType\x12\x18\n" +
+	"\acatalog\x18\n" +
+	" \x01(\tR\acatalog\",\n" +
+	"\x10CedarSchemaInput\x12\x18\n" +
+	"\acontext\x18\x01 \x01(\tR\acontext\"[\n" +
+	"\x11CedarSchemaOutput\x12\x18\n" +
+	"\acontext\x18\x01 \x01(\tR\acontext\x12\x16\n" +
+	"\x06schema\x18\x02 \x01(\tR\x06schema\x12\x14\n" +
+	"\x05error\x18d \x01(\tR\x05error\"\x84\x01\n" +
+	"\x11AuditModuleConfig\x12\x12\n" +
+	"\x04sink\x18\x01 \x01(\tR\x04sink\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x12\x16\n" +
+	"\x06driver\x18\x03 \x01(\tR\x06driver\x12\x10\n" +
+	"\x03dsn\x18\x04 \x01(\tR\x03dsn\x12\x1d\n" +
+	"\n" +
+	"table_name\x18\x05 \x01(\tR\ttableName\"\xae\x03\n" +
+	"\n" +
+	"AuditEntry\x12\x10\n" +
+	"\x03seq\x18\x01 \x01(\x04R\x03seq\x12\x12\n" +
+	"\x04time\x18\x02 \x01(\tR\x04time\x12\x14\n" +
+	"\x05actor\x18\x03 \x01(\tR\x05actor\x12\x1f\n" +
+	"\vactor_email\x18\x04 \x01(\tR\n" +
+	"actorEmail\x12\x16\n" +
+	"\x06source\x18\x05 \x01(\tR\x06source\x12\x1c\n" +
+	"\toperation\x18\x06 \x01(\tR\toperation\x12\x16\n" +
+	"\x06module\x18\a \x01(\tR\x06module\x12\x1a\n" +
+	"\bresource\x18\b \x01(\tR\bresource\x12\x16\n" +
+	"\x06action\x18\t \x01(\tR\x06action\x12\x18\n" +
+	"\acontext\x18\n" +
+	" \x01(\tR\acontext\x12/\n" +
+	"\x06before\x18\v \x01(\v2\x17.google.protobuf.StructR\x06before\x12-\n" +
+	"\x05after\x18\f \x01(\v2\x17.google.protobuf.StructR\x05after\x12\x16\n" +
+	"\x06reason\x18\r \x01(\tR\x06reason\x12\x1b\n" +
+	"\tprev_hash\x18\x0e \x01(\tR\bprevHash\x12\x12\n" +
+	"\x04hash\x18\x0f \x01(\tR\x04hash\"\xc3\x01\n" +
+	"\x15ListAuditEntriesInput\x12\x14\n" +
+	"\x05actor\x18\x01 \x01(\tR\x05actor\x12\x1c\n" +
+	"\toperation\x18\x02 \x01(\tR\toperation\x12\x1a\n" +
+	"\bresource\x18\x03 \x01(\tR\bresource\x12\x18\n" +
+	"\acontext\x18\x04 \x01(\tR\acontext\x12\x14\n" +
+	"\x05since\x18\x05 \x01(\tR\x05since\x12\x14\n" +
+	"\x05until\x18\x06 \x01(\tR\x05until\x12\x14\n" +
+	"\x05limit\x18\a \x01(\x05R\x05limit\"Y\n" +
+	"\x16ListAuditEntriesOutput\x12?\n" +
+	"\aentries\x18\x01 \x03(\v2%.workflow.plugins.authz.v1.AuditEntryR\aentries\"\x17\n" +
+	"\x15VerifyAuditChainInput\"\x7f\n" +
+	"\x16VerifyAuditChainOutput\x12\x14\n" +
+	"\x05valid\x18\x01 \x01(\bR\x05valid\x12\x18\n" +
+	"\achecked\x18\x02 \x01(\x05R\achecked\x12\x1d\n" +
+	"\n" +
+	"broken_seq\x18\x03 \x01(\x04R\tbrokenSeq\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason*{\n" +
 	"\tAuthzMode\x12\x1a\n" +
 	"\x16AUTHZ_MODE_UNSPECIFIED\x10\x00\x12\x13\n" +
 	"\x0fAUTHZ_MODE_RBAC\x10\x01\x12\x13\n" +
@@ -7290,184 +10931,266 @@ func file_internal_contracts_authz_proto_rawDescGZIP() []byte {
 }
 
 var file_internal_contracts_authz_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_internal_contracts_authz_proto_msgTypes = make([]protoimpl.MessageInfo, 97)
+var file_internal_contracts_authz_proto_msgTypes = make([]protoimpl.MessageInfo, 138)
 var file_internal_contracts_authz_proto_goTypes = []any{
-	(AuthzMode)(0),                        // 0: workflow.plugins.authz.v1.AuthzMode
-	(AuthzOperation)(0),                   // 1: workflow.plugins.authz.v1.AuthzOperation
-	(*StringList)(nil),                    // 2: workflow.plugins.authz.v1.StringList
-	(*AdapterConfig)(nil),                 // 3: workflow.plugins.authz.v1.AdapterConfig
-	(*WatcherConfig)(nil),                 // 4: workflow.plugins.authz.v1.WatcherConfig
-	(*CasbinModuleConfig)(nil),            // 5: workflow.plugins.authz.v1.CasbinModuleConfig
-	(*PermitModuleConfig)(nil),            // 6: workflow.plugins.authz.v1.PermitModuleConfig
-	(*KetoModuleConfig)(nil),              // 7: workflow.plugins.authz.v1.KetoModuleConfig
-	(*ExtraField)(nil),                    // 8: workflow.plugins.authz.v1.ExtraField
-	(*AuthzCheckConfig)(nil),              // 9: workflow.plugins.authz.v1.AuthzCheckConfig
-	(*AuthzCheckInput)(nil),               // 10: workflow.plugins.authz.v1.AuthzCheckInput
-	(*AuthzCheckOutput)(nil),              // 11: workflow.plugins.authz.v1.AuthzCheckOutput
-	(*PolicyRuleConfig)(nil),              // 12: workflow.plugins.authz.v1.PolicyRuleConfig
-	(*PolicyRuleInput)(nil),               // 13: workflow.plugins.authz.v1.PolicyRuleInput
-	(*PolicyRuleOutput)(nil),              // 14: workflow.plugins.authz.v1.PolicyRuleOutput
-	(*RoleAssignConfig)(nil),              // 15: workflow.plugins.authz.v1.RoleAssignConfig
-	(*RoleAssignInput)(nil),               // 16: workflow.plugins.authz.v1.RoleAssignInput
-	(*RoleAssignOutput)(nil),              // 17: workflow.plugins.authz.v1.RoleAssignOutput
-	(*CapabilitiesConfig)(nil),            // 18: workflow.plugins.authz.v1.CapabilitiesConfig
-	(*CapabilitiesInput)(nil),             // 19: workflow.plugins.authz.v1.CapabilitiesInput
-	(*CapabilitiesOutput)(nil),            // 20: workflow.plugins.authz.v1.CapabilitiesOutput
-	(*CapabilityDescriptor)(nil),          // 21: workflow.plugins.authz.v1.CapabilityDescriptor
-	(*CapabilityRequirement)(nil),         // 22: workflow.plugins.authz.v1.CapabilityRequirement
-	(*ProviderCapabilitiesInput)(nil),     // 23: workflow.plugins.authz.v1.ProviderCapabilitiesInput
-	(*ProviderCapabilitiesOutput)(nil),    // 24: workflow.plugins.authz.v1.ProviderCapabilitiesOutput
-	(*AuthorizationDecisionConfig)(nil),   // 25: workflow.plugins.authz.v1.AuthorizationDecisionConfig
-	(*AuthorizationDecisionInput)(nil),    // 26: workflow.plugins.authz.v1.AuthorizationDecisionInput
-	(*AuthorizationDecisionOutput)(nil),   // 27: workflow.plugins.authz.v1.AuthorizationDecisionOutput
-	(*RequireCapabilitiesConfig)(nil),     // 28: workflow.plugins.authz.v1.RequireCapabilitiesConfig
-	(*RequireCapabilitiesInput)(nil),      // 29: workflow.plugins.authz.v1.RequireCapabilitiesInput
-	(*SubjectObjectActionConfig)(nil),     // 30: workflow.plugins.authz.v1.SubjectObjectActionConfig
-	(*SubjectObjectActionInput)(nil),      // 31: workflow.plugins.authz.v1.SubjectObjectActionInput
-	(*SubjectObjectActionOutput)(nil),     // 32: workflow.plugins.authz.v1.SubjectObjectActionOutput
-	(*ListConfig)(nil),                    // 33: workflow.plugins.authz.v1.ListConfig
-	(*ListInput)(nil),                     // 34: workflow.plugins.authz.v1.ListInput
-	(*GenericStepOutput)(nil),             // 35: workflow.plugins.authz.v1.GenericStepOutput
-	(*RelationConfig)(nil),                // 36: workflow.plugins.authz.v1.RelationConfig
-	(*RelationInput)(nil),                 // 37: workflow.plugins.authz.v1.RelationInput
-	(*RelationOutput)(nil),                // 38: workflow.plugins.authz.v1.RelationOutput
-	(*PermitStepConfig)(nil),              // 39: workflow.plugins.authz.v1.PermitStepConfig
-	(*PermitStepInput)(nil),               // 40: workflow.plugins.authz.v1.PermitStepInput
-	(*ScopeDeclaration)(nil),              // 41: workflow.plugins.authz.v1.ScopeDeclaration
-	(*ScopeCatalogConfig)(nil),            // 42: workflow.plugins.authz.v1.ScopeCatalogConfig
-	(*RegisterScopesInput)(nil),           // 43: workflow.plugins.authz.v1.RegisterScopesInput
-	(*RegisterScopesOutput)(nil),          // 44: workflow.plugins.authz.v1.RegisterScopesOutput
-	(*ListScopesInput)(nil),               // 45: workflow.plugins.authz.v1.ListScopesInput
-	(*ListScopesOutput)(nil),              // 46: workflow.plugins.authz.v1.ListScopesOutput
-	(*ResourceDeclaration)(nil),           // 47: workflow.plugins.authz.v1.ResourceDeclaration
-	(*ActionDeclaration)(nil),             // 48: workflow.plugins.authz.v1.ActionDeclaration
-	(*AttributeValue)(nil),                // 49: workflow.plugins.authz.v1.AttributeValue
-	(*AttributeDeclaration)(nil),          // 50: workflow.plugins.authz.v1.AttributeDeclaration
-	(*AttributeCondition)(nil),            // 51: workflow.plugins.authz.v1.AttributeCondition
-	(*AttributePolicy)(nil),               // 52: workflow.plugins.authz.v1.AttributePolicy
-	(*AttributePolicyFilter)(nil),         // 53: workflow.plugins.authz.v1.AttributePolicyFilter
-	(*AttributeCheckInput)(nil),           // 54: workflow.plugins.authz.v1.AttributeCheckInput
-	(*AttributeCheckOutput)(nil),          // 55: workflow.plugins.authz.v1.AttributeCheckOutput
-	(*DeclareAttributesInput)(nil),        // 56: workflow.plugins.authz.v1.DeclareAttributesInput
-	(*DeclareAttributesOutput)(nil),       // 57: workflow.plugins.authz.v1.DeclareAttributesOutput
-	(*UpsertAttributePolicyInput)(nil),    // 58: workflow.plugins.authz.v1.UpsertAttributePolicyInput
-	(*UpsertAttributePolicyOutput)(nil),   // 59: workflow.plugins.authz.v1.UpsertAttributePolicyOutput
-	(*ListAttributePoliciesInput)(nil),    // 60: workflow.plugins.authz.v1.ListAttributePoliciesInput
-	(*ListAttributePoliciesOutput)(nil),   // 61: workflow.plugins.authz.v1.ListAttributePoliciesOutput
-	(*RemoveAttributePolicyInput)(nil),    // 62: workflow.plugins.authz.v1.RemoveAttributePolicyInput
-	(*RemoveAttributePolicyOutput)(nil),   // 63: workflow.plugins.authz.v1.RemoveAttributePolicyOutput
-	(*RelationDeclaration)(nil),           // 64: workflow.plugins.authz.v1.RelationDeclaration
-	(*RelationTuple)(nil),                 // 65: workflow.plugins.authz.v1.RelationTuple
-	(*RelationTupleFilter)(nil),           // 66: workflow.plugins.authz.v1.RelationTupleFilter
-	(*RelationCheckInput)(nil),            // 67: workflow.plugins.authz.v1.RelationCheckInput
-	(*RelationCheckOutput)(nil),           // 68: workflow.plugins.authz.v1.RelationCheckOutput
-	(*UpsertRelationTupleInput)(nil),      // 69: workflow.plugins.authz.v1.UpsertRelationTupleInput
-	(*UpsertRelationTupleOutput)(nil),     // 70: workflow.plugins.authz.v1.UpsertRelationTupleOutput
-	(*ListRelationTuplesInput)(nil),       // 71: workflow.plugins.authz.v1.ListRelationTuplesInput
-	(*ListRelationTuplesOutput)(nil),      // 72: workflow.plugins.authz.v1.ListRelationTuplesOutput
-	(*RemoveRelationTupleInput)(nil),      // 73: workflow.plugins.authz.v1.RemoveRelationTupleInput
-	(*RemoveRelationTupleOutput)(nil),     // 74: workflow.plugins.authz.v1.RemoveRelationTupleOutput
-	(*UIActionDeclaration)(nil),           // 75: workflow.plugins.authz.v1.UIActionDeclaration
-	(*AuthzDeclarationSet)(nil),           // 76: workflow.plugins.authz.v1.AuthzDeclarationSet
-	(*RegisterDeclarationsInput)(nil),     // 77: workflow.plugins.authz.v1.RegisterDeclarationsInput
-	(*RegisterDeclarationsOutput)(nil),    // 78: workflow.plugins.authz.v1.RegisterDeclarationsOutput
-	(*ListDeclarationsInput)(nil),         // 79: workflow.plugins.authz.v1.ListDeclarationsInput
-	(*ListDeclarationsOutput)(nil),        // 80: workflow.plugins.authz.v1.ListDeclarationsOutput
-	(*ResolveProjectionInputsInput)(nil),  // 81: workflow.plugins.authz.v1.ResolveProjectionInputsInput
-	(*ProjectionInputs)(nil),              // 82: workflow.plugins.authz.v1.ProjectionInputs
-	(*ResolveProjectionInputsOutput)(nil), // 83: workflow.plugins.authz.v1.ResolveProjectionInputsOutput
-	(*ResolveSubjectScopesInput)(nil),     // 84: workflow.plugins.authz.v1.ResolveSubjectScopesInput
-	(*ResolveSubjectScopesOutput)(nil),    // 85: workflow.plugins.authz.v1.ResolveSubjectScopesOutput
-	(*RoleScopeGrant)(nil),                // 86: workflow.plugins.authz.v1.RoleScopeGrant
-	(*SubjectRoleAssignment)(nil),         // 87: workflow.plugins.authz.v1.SubjectRoleAssignment
-	(*AssignmentFilter)(nil),              // 88: workflow.plugins.authz.v1.AssignmentFilter
-	(*ScopeCheckInput)(nil),               // 89: workflow.plugins.authz.v1.ScopeCheckInput
-	(*ScopeCheckOutput)(nil),              // 90: workflow.plugins.authz.v1.ScopeCheckOutput
-	(*UpsertRoleInput)(nil),               // 91: workflow.plugins.authz.v1.UpsertRoleInput
-	(*UpsertRoleOutput)(nil),              // 92: workflow.plugins.authz.v1.UpsertRoleOutput
-	(*AssignRoleInput)(nil),               // 93: workflow.plugins.authz.v1.AssignRoleInput
-	(*AssignRoleOutput)(nil),              // 94: workflow.plugins.authz.v1.AssignRoleOutput
-	(*ListRoleAssignmentsInput)(nil),      // 95: workflow.plugins.authz.v1.ListRoleAssignmentsInput
-	(*ListRoleAssignmentsOutput)(nil),     // 96: workflow.plugins.authz.v1.ListRoleAssignmentsOutput
-	(*RemoveRoleAssignmentInput)(nil),     // 97: workflow.plugins.authz.v1.RemoveRoleAssignmentInput
-	(*RemoveRoleAssignmentOutput)(nil),    // 98: workflow.plugins.authz.v1.RemoveRoleAssignmentOutput
-	(*structpb.Struct)(nil),               // 99: google.protobuf.Struct
+	(AuthzMode)(0),                         // 0: workflow.plugins.authz.v1.AuthzMode
+	(AuthzOperation)(0),                    // 1: workflow.plugins.authz.v1.AuthzOperation
+	(*StringList)(nil),                     // 2: workflow.plugins.authz.v1.StringList
+	(*AdapterConfig)(nil),                  // 3: workflow.plugins.authz.v1.AdapterConfig
+	(*WatcherConfig)(nil),                  // 4: workflow.plugins.authz.v1.WatcherConfig
+	(*CasbinModuleConfig)(nil),             // 5: workflow.plugins.authz.v1.CasbinModuleConfig
+	(*RelationModelConfig)(nil),            // 6: workflow.plugins.authz.v1.RelationModelConfig
+	(*TransportConfig)(nil),                // 7: workflow.plugins.authz.v1.TransportConfig
+	(*LocalPDPConfig)(nil),                 // 8: workflow.plugins.authz.v1.LocalPDPConfig
+	(*PermitModuleConfig)(nil),             // 9: workflow.plugins.authz.v1.PermitModuleConfig
+	(*KetoModuleConfig)(nil),               // 10: workflow.plugins.authz.v1.KetoModuleConfig
+	(*ExtraField)(nil),                     // 11: workflow.plugins.authz.v1.ExtraField
+	(*AuthzCheckConfig)(nil),               // 12: workflow.plugins.authz.v1.AuthzCheckConfig
+	(*AuthzCheckInput)(nil),                // 13: workflow.plugins.authz.v1.AuthzCheckInput
+	(*AuthzCheckOutput)(nil),               // 14: workflow.plugins.authz.v1.AuthzCheckOutput
+	(*PolicyRuleConfig)(nil),               // 15: workflow.plugins.authz.v1.PolicyRuleConfig
+	(*PolicyRuleInput)(nil),                // 16: workflow.plugins.authz.v1.PolicyRuleInput
+	(*PolicyRuleOutput)(nil),               // 17: workflow.plugins.authz.v1.PolicyRuleOutput
+	(*RoleAssignConfig)(nil),               // 18: workflow.plugins.authz.v1.RoleAssignConfig
+	(*RoleAssignInput)(nil),                // 19: workflow.plugins.authz.v1.RoleAssignInput
+	(*RoleAssignOutput)(nil),               // 20: workflow.plugins.authz.v1.RoleAssignOutput
+	(*CapabilitiesConfig)(nil),             // 21: workflow.plugins.authz.v1.CapabilitiesConfig
+	(*CapabilitiesInput)(nil),              // 22: workflow.plugins.authz.v1.CapabilitiesInput
+	(*CapabilitiesOutput)(nil),             // 23: workflow.plugins.authz.v1.CapabilitiesOutput
+	(*CapabilityDescriptor)(nil),           // 24: workflow.plugins.authz.v1.CapabilityDescriptor
+	(*CapabilityRequirement)(nil),          // 25: workflow.plugins.authz.v1.CapabilityRequirement
+	(*ProviderCapabilitiesInput)(nil),      // 26: workflow.plugins.authz.v1.ProviderCapabilitiesInput
+	(*ProviderCapabilitiesOutput)(nil),     // 27: workflow.plugins.authz.v1.ProviderCapabilitiesOutput
+	(*AuthorizationDecisionConfig)(nil),    // 28: workflow.plugins.authz.v1.AuthorizationDecisionConfig
+	(*AuthorizationDecisionInput)(nil),     // 29: workflow.plugins.authz.v1.AuthorizationDecisionInput
+	(*AuthorizationDecisionOutput)(nil),    // 30: workflow.plugins.authz.v1.AuthorizationDecisionOutput
+	(*RequireCapabilitiesConfig)(nil),      // 31: workflow.plugins.authz.v1.RequireCapabilitiesConfig
+	(*RequireCapabilitiesInput)(nil),       // 32: workflow.plugins.authz.v1.RequireCapabilitiesInput
+	(*SubjectObjectActionConfig)(nil),      // 33: workflow.plugins.authz.v1.SubjectObjectActionConfig
+	(*SubjectObjectActionInput)(nil),       // 34: workflow.plugins.authz.v1.SubjectObjectActionInput
+	(*SubjectObjectActionOutput)(nil),      // 35: workflow.plugins.authz.v1.SubjectObjectActionOutput
+	(*ListConfig)(nil),                     // 36: workflow.plugins.authz.v1.ListConfig
+	(*ListInput)(nil),                      // 37: workflow.plugins.authz.v1.ListInput
+	(*GenericStepOutput)(nil),              // 38: workflow.plugins.authz.v1.GenericStepOutput
+	(*RelationConfig)(nil),                 // 39: workflow.plugins.authz.v1.RelationConfig
+	(*RelationInput)(nil),                  // 40: workflow.plugins.authz.v1.RelationInput
+	(*RelationOutput)(nil),                 // 41: workflow.plugins.authz.v1.RelationOutput
+	(*PermitStepConfig)(nil),               // 42: workflow.plugins.authz.v1.PermitStepConfig
+	(*PermitStepInput)(nil),                // 43: workflow.plugins.authz.v1.PermitStepInput
+	(*ScopeDeclaration)(nil),               // 44: workflow.plugins.authz.v1.ScopeDeclaration
+	(*ScopeCatalogConfig)(nil),             // 45: workflow.plugins.authz.v1.ScopeCatalogConfig
+	(*RegisterScopesInput)(nil),            // 46: workflow.plugins.authz.v1.RegisterScopesInput
+	(*RegisterScopesOutput)(nil),           // 47: workflow.plugins.authz.v1.RegisterScopesOutput
+	(*ListScopesInput)(nil),                // 48: workflow.plugins.authz.v1.ListScopesInput
+	(*ListScopesOutput)(nil),               // 49: workflow.plugins.authz.v1.ListScopesOutput
+	(*ResourceDeclaration)(nil),            // 50: workflow.plugins.authz.v1.ResourceDeclaration
+	(*ActionDeclaration)(nil),              // 51: workflow.plugins.authz.v1.ActionDeclaration
+	(*AttributeValue)(nil),                 // 52: workflow.plugins.authz.v1.AttributeValue
+	(*AttributeDeclaration)(nil),           // 53: workflow.plugins.authz.v1.AttributeDeclaration
+	(*AttributeCondition)(nil),             // 54: workflow.plugins.authz.v1.AttributeCondition
+	(*AttributePolicy)(nil),                // 55: workflow.plugins.authz.v1.AttributePolicy
+	(*AttributePolicyFilter)(nil),          // 56: workflow.plugins.authz.v1.AttributePolicyFilter
+	(*AttributeCheckInput)(nil),            // 57: workflow.plugins.authz.v1.AttributeCheckInput
+	(*AttributeCheckOutput)(nil),           // 58: workflow.plugins.authz.v1.AttributeCheckOutput
+	(*DeclareAttributesInput)(nil),         // 59: workflow.plugins.authz.v1.DeclareAttributesInput
+	(*DeclareAttributesOutput)(nil),        // 60: workflow.plugins.authz.v1.DeclareAttributesOutput
+	(*UpsertAttributePolicyInput)(nil),     // 61: workflow.plugins.authz.v1.UpsertAttributePolicyInput
+	(*UpsertAttributePolicyOutput)(nil),    // 62: workflow.plugins.authz.v1.UpsertAttributePolicyOutput
+	(*ListAttributePoliciesInput)(nil),     // 63: workflow.plugins.authz.v1.ListAttributePoliciesInput
+	(*ListAttributePoliciesOutput)(nil),    // 64: workflow.plugins.authz.v1.ListAttributePoliciesOutput
+	(*RemoveAttributePolicyInput)(nil),     // 65: workflow.plugins.authz.v1.RemoveAttributePolicyInput
+	(*RemoveAttributePolicyOutput)(nil),    // 66: workflow.plugins.authz.v1.RemoveAttributePolicyOutput
+	(*RelationDeclaration)(nil),            // 67: workflow.plugins.authz.v1.RelationDeclaration
+	(*RelationTuple)(nil),                  // 68: workflow.plugins.authz.v1.RelationTuple
+	(*RelationTupleFilter)(nil),            // 69: workflow.plugins.authz.v1.RelationTupleFilter
+	(*RelationCheckInput)(nil),             // 70: workflow.plugins.authz.v1.RelationCheckInput
+	(*RelationCheckOutput)(nil),            // 71: workflow.plugins.authz.v1.RelationCheckOutput
+	(*UpsertRelationTupleInput)(nil),       // 72: workflow.plugins.authz.v1.UpsertRelationTupleInput
+	(*UpsertRelationTupleOutput)(nil),      // 73: workflow.plugins.authz.v1.UpsertRelationTupleOutput
+	(*ListRelationTuplesInput)(nil),        // 74: workflow.plugins.authz.v1.ListRelationTuplesInput
+	(*ListRelationTuplesOutput)(nil),       // 75: workflow.plugins.authz.v1.ListRelationTuplesOutput
+	(*RemoveRelationTupleInput)(nil),       // 76: workflow.plugins.authz.v1.RemoveRelationTupleInput
+	(*RemoveRelationTupleOutput)(nil),      // 77: workflow.plugins.authz.v1.RemoveRelationTupleOutput
+	(*ExpandRelationInput)(nil),            // 78: workflow.plugins.authz.v1.ExpandRelationInput
+	(*UsersetTree)(nil),                    // 79: workflow.plugins.authz.v1.UsersetTree
+	(*ExpandRelationOutput)(nil),           // 80: workflow.plugins.authz.v1.ExpandRelationOutput
+	(*GenerateNamespacesInput)(nil),        // 81: workflow.plugins.authz.v1.GenerateNamespacesInput
+	(*GenerateNamespacesOutput)(nil),       // 82: workflow.plugins.authz.v1.GenerateNamespacesOutput
+	(*UIActionDeclaration)(nil),            // 83: workflow.plugins.authz.v1.UIActionDeclaration
+	(*AuthzDeclarationSet)(nil),            // 84: workflow.plugins.authz.v1.AuthzDeclarationSet
+	(*RegisterDeclarationsInput)(nil),      // 85: workflow.plugins.authz.v1.RegisterDeclarationsInput
+	(*RegisterDeclarationsOutput)(nil),     // 86: workflow.plugins.authz.v1.RegisterDeclarationsOutput
+	(*DeclarationConflict)(nil),            // 87: workflow.plugins.authz.v1.DeclarationConflict
+	(*UnregisterDeclarationsInput)(nil),    // 88: workflow.plugins.authz.v1.UnregisterDeclarationsInput
+	(*UnregisterDeclarationsOutput)(nil),   // 89: workflow.plugins.authz.v1.UnregisterDeclarationsOutput
+	(*ListDeclarationsInput)(nil),          // 90: workflow.plugins.authz.v1.ListDeclarationsInput
+	(*ListDeclarationsOutput)(nil),         // 91: workflow.plugins.authz.v1.ListDeclarationsOutput
+	(*ResolveProjectionInputsInput)(nil),   // 92: workflow.plugins.authz.v1.ResolveProjectionInputsInput
+	(*ProjectionInputs)(nil),               // 93: workflow.plugins.authz.v1.ProjectionInputs
+	(*ResolveProjectionInputsOutput)(nil),  // 94: workflow.plugins.authz.v1.ResolveProjectionInputsOutput
+	(*ResolveSubjectProjectionInput)(nil),  // 95: workflow.plugins.authz.v1.ResolveSubjectProjectionInput
+	(*PermittedResource)(nil),              // 96: workflow.plugins.authz.v1.PermittedResource
+	(*SubjectProjection)(nil),              // 97: workflow.plugins.authz.v1.SubjectProjection
+	(*ResolveSubjectProjectionOutput)(nil), // 98: workflow.plugins.authz.v1.ResolveSubjectProjectionOutput
+	(*SubjectProjectionConfig)(nil),        // 99: workflow.plugins.authz.v1.SubjectProjectionConfig
+	(*SubjectProjectionInput)(nil),         // 100: workflow.plugins.authz.v1.SubjectProjectionInput
+	(*ResolveSubjectScopesInput)(nil),      // 101: workflow.plugins.authz.v1.ResolveSubjectScopesInput
+	(*ResolveSubjectScopesOutput)(nil),     // 102: workflow.plugins.authz.v1.ResolveSubjectScopesOutput
+	(*MigrateScopesInput)(nil),             // 103: workflow.plugins.authz.v1.MigrateScopesInput
+	(*ScopeMigration)(nil),                 // 104: workflow.plugins.authz.v1.ScopeMigration
+	(*MigrateScopesOutput)(nil),            // 105: workflow.plugins.authz.v1.MigrateScopesOutput
+	(*RoleScopeGrant)(nil),                 // 106: workflow.plugins.authz.v1.RoleScopeGrant
+	(*SubjectRoleAssignment)(nil),          // 107: workflow.plugins.authz.v1.SubjectRoleAssignment
+	(*AssignmentFilter)(nil),               // 108: workflow.plugins.authz.v1.AssignmentFilter
+	(*ScopeCheckInput)(nil),                // 109: workflow.plugins.authz.v1.ScopeCheckInput
+	(*ScopeCheckOutput)(nil),               // 110: workflow.plugins.authz.v1.ScopeCheckOutput
+	(*UpsertRoleInput)(nil),                // 111: workflow.plugins.authz.v1.UpsertRoleInput
+	(*UpsertRoleOutput)(nil),               // 112: workflow.plugins.authz.v1.UpsertRoleOutput
+	(*AssignRoleInput)(nil),                // 113: workflow.plugins.authz.v1.AssignRoleInput
+	(*AssignRoleOutput)(nil),               // 114: workflow.plugins.authz.v1.AssignRoleOutput
+	(*ListRoleAssignmentsInput)(nil),       // 115: workflow.plugins.authz.v1.ListRoleAssignmentsInput
+	(*ListRoleAssignmentsOutput)(nil),      // 116: workflow.plugins.authz.v1.ListRoleAssignmentsOutput
+	(*RemoveRoleAssignmentInput)(nil),      // 117: workflow.plugins.authz.v1.RemoveRoleAssignmentInput
+	(*RemoveRoleAssignmentOutput)(nil),     // 118: workflow.plugins.authz.v1.RemoveRoleAssignmentOutput
+	(*SimulationChange)(nil),               // 119: workflow.plugins.authz.v1.SimulationChange
+	(*SimulationProbe)(nil),                // 120: workflow.plugins.authz.v1.SimulationProbe
+	(*SimulationResult)(nil),               // 121: workflow.plugins.authz.v1.SimulationResult
+	(*SimulateConfig)(nil),                 // 122: workflow.plugins.authz.v1.SimulateConfig
+	(*SimulateInput)(nil),                  // 123: workflow.plugins.authz.v1.SimulateInput
+	(*SimulateOutput)(nil),                 // 124: workflow.plugins.authz.v1.SimulateOutput
+	(*CompositeRoute)(nil),                 // 125: workflow.plugins.authz.v1.CompositeRoute
+	(*CompositeModuleConfig)(nil),          // 126: workflow.plugins.authz.v1.CompositeModuleConfig
+	(*OPAModuleConfig)(nil),                // 127: workflow.plugins.authz.v1.OPAModuleConfig
+	(*OPADataInput)(nil),                   // 128: workflow.plugins.authz.v1.OPADataInput
+	(*OPADataOutput)(nil),                  // 129: workflow.plugins.authz.v1.OPADataOutput
+	(*CedarModuleConfig)(nil),              // 130: workflow.plugins.authz.v1.CedarModuleConfig
+	(*CedarSchemaInput)(nil),               // 131: workflow.plugins.authz.v1.CedarSchemaInput
+	(*CedarSchemaOutput)(nil),              // 132: workflow.plugins.authz.v1.CedarSchemaOutput
+	(*AuditModuleConfig)(nil),              // 133: workflow.plugins.authz.v1.AuditModuleConfig
+	(*AuditEntry)(nil),                     // 134: workflow.plugins.authz.v1.AuditEntry
+	(*ListAuditEntriesInput)(nil),          // 135: workflow.plugins.authz.v1.ListAuditEntriesInput
+	(*ListAuditEntriesOutput)(nil),         // 136: workflow.plugins.authz.v1.ListAuditEntriesOutput
+	(*VerifyAuditChainInput)(nil),          // 137: workflow.plugins.authz.v1.VerifyAuditChainInput
+	(*VerifyAuditChainOutput)(nil),         // 138: workflow.plugins.authz.v1.VerifyAuditChainOutput
+	nil,                                    // 139: workflow.plugins.authz.v1.OPAModuleConfig.ModulesEntry
+	(*structpb.Struct)(nil),                // 140: google.protobuf.Struct
+	(*structpb.Value)(nil),                 // 141: google.protobuf.Value
+	(*structpb.ListValue)(nil),             // 142: google.protobuf.ListValue
 }
 var file_internal_contracts_authz_proto_depIdxs = []int32{
-	2,  // 0: workflow.plugins.authz.v1.CasbinModuleConfig.policies:type_name -> workflow.plugins.authz.v1.StringList
-	2,  // 1: workflow.plugins.authz.v1.CasbinModuleConfig.role_assignments:type_name -> workflow.plugins.authz.v1.StringList
-	3,  // 2: workflow.plugins.authz.v1.CasbinModuleConfig.adapter:type_name -> workflow.plugins.authz.v1.AdapterConfig
-	4,  // 3: workflow.plugins.authz.v1.CasbinModuleConfig.watcher:type_name -> workflow.plugins.authz.v1.WatcherConfig
-	8,  // 4: workflow.plugins.authz.v1.AuthzCheckConfig.extra_fields:type_name -> workflow.plugins.authz.v1.ExtraField
-	8,  // 5: workflow.plugins.authz.v1.AuthzCheckInput.extra_fields:type_name -> workflow.plugins.authz.v1.ExtraField
-	99, // 6: workflow.plugins.authz.v1.AuthzCheckOutput.response_headers:type_name -> google.protobuf.Struct
-	2,  // 7: workflow.plugins.authz.v1.RoleAssignConfig.assignments:type_name -> workflow.plugins.authz.v1.StringList
-	2,  // 8: workflow.plugins.authz.v1.RoleAssignInput.assignments:type_name -> workflow.plugins.authz.v1.StringList
-	2,  // 9: workflow.plugins.authz.v1.RoleAssignOutput.assignments:type_name -> workflow.plugins.authz.v1.StringList
-	21, // 10: workflow.plugins.authz.v1.CapabilitiesOutput.descriptors:type_name -> workflow.plugins.authz.v1.CapabilityDescriptor
-	0,  // 11: workflow.plugins.authz.v1.CapabilityDescriptor.mode:type_name -> workflow.plugins.authz.v1.AuthzMode
-	1,  // 12: workflow.plugins.authz.v1.CapabilityDescriptor.operations:type_name -> workflow.plugins.authz.v1.AuthzOperation
-	0,  // 13: workflow.plugins.authz.v1.CapabilityRequirement.mode:type_name -> workflow.plugins.authz.v1.AuthzMode
-	1,  // 14: workflow.plugins.authz.v1.CapabilityRequirement.operations:type_name -> workflow.plugins.authz.v1.AuthzOperation
-	22, // 15: workflow.plugins.authz.v1.ProviderCapabilitiesInput.requirements:type_name -> workflow.plugins.authz.v1.CapabilityRequirement
-	21, // 16: workflow.plugins.authz.v1.ProviderCapabilitiesOutput.descriptors:type_name -> workflow.plugins.authz.v1.CapabilityDescriptor
-	0,  // 17: workflow.plugins.authz.v1.AuthorizationDecisionConfig.mode:type_name -> workflow.plugins.authz.v1.AuthzMode
-	0,  // 18: workflow.plugins.authz.v1.AuthorizationDecisionInput.mode:type_name -> workflow.plugins.authz.v1.AuthzMode
-	99, // 19: workflow.plugins.authz.v1.AuthorizationDecisionInput.subject_attributes:type_name -> google.protobuf.Struct
-	99, // 20: workflow.plugins.authz.v1.AuthorizationDecisionInput.resource_attributes:type_name -> google.protobuf.Struct
-	99, // 21: workflow.plugins.authz.v1.AuthorizationDecisionInput.environment_attributes:type_name -> google.protobuf.Struct
-	0,  // 22: workflow.plugins.authz.v1.AuthorizationDecisionOutput.mode:type_name -> workflow.plugins.authz.v1.AuthzMode
-	22, // 23: workflow.plugins.authz.v1.RequireCapabilitiesConfig.requirements:type_name -> workflow.plugins.authz.v1.CapabilityRequirement
-	22, // 24: workflow.plugins.authz.v1.RequireCapabilitiesInput.requirements:type_name -> workflow.plugins.authz.v1.CapabilityRequirement
-	99, // 25: workflow.plugins.authz.v1.GenericStepOutput.output:type_name -> google.protobuf.Struct
-	99, // 26: workflow.plugins.authz.v1.PermitStepConfig.values:type_name -> google.protobuf.Struct
-	99, // 27: workflow.plugins.authz.v1.PermitStepInput.values:type_name -> google.protobuf.Struct
-	41, // 28: workflow.plugins.authz.v1.ScopeCatalogConfig.scopes:type_name -> workflow.plugins.authz.v1.ScopeDeclaration
-	76, // 29: workflow.plugins.authz.v1.ScopeCatalogConfig.declarations:type_name -> workflow.plugins.authz.v1.AuthzDeclarationSet
-	41, // 30: workflow.plugins.authz.v1.RegisterScopesInput.scopes:type_name -> workflow.plugins.authz.v1.ScopeDeclaration
-	41, // 31: workflow.plugins.authz.v1.RegisterScopesOutput.scopes:type_name -> workflow.plugins.authz.v1.ScopeDeclaration
-	41, // 32: workflow.plugins.authz.v1.ListScopesOutput.scopes:type_name -> workflow.plugins.authz.v1.ScopeDeclaration
-	49, // 33: workflow.plugins.authz.v1.AttributeDeclaration.allowed_values:type_name -> workflow.plugins.authz.v1.AttributeValue
-	51, // 34: workflow.plugins.authz.v1.AttributePolicy.conditions:type_name -> workflow.plugins.authz.v1.AttributeCondition
-	99, // 35: workflow.plugins.authz.v1.AttributeCheckInput.subject_attributes:type_name -> google.protobuf.Struct
-	99, // 36: workflow.plugins.authz.v1.AttributeCheckInput.resource_attributes:type_name -> google.protobuf.Struct
-	99, // 37: workflow.plugins.authz.v1.AttributeCheckInput.environment_attributes:type_name -> google.protobuf.Struct
-	50, // 38: workflow.plugins.authz.v1.DeclareAttributesInput.attributes:type_name -> workflow.plugins.authz.v1.AttributeDeclaration
-	50, // 39: workflow.plugins.authz.v1.DeclareAttributesOutput.attributes:type_name -> workflow.plugins.authz.v1.AttributeDeclaration
-	52, // 40: workflow.plugins.authz.v1.UpsertAttributePolicyInput.policy:type_name -> workflow.plugins.authz.v1.AttributePolicy
-	52, // 41: workflow.plugins.authz.v1.UpsertAttributePolicyOutput.policy:type_name -> workflow.plugins.authz.v1.AttributePolicy
-	53, // 42: workflow.plugins.authz.v1.ListAttributePoliciesInput.filter:type_name -> workflow.plugins.authz.v1.AttributePolicyFilter
-	52, // 43: workflow.plugins.authz.v1.ListAttributePoliciesOutput.policies:type_name -> workflow.plugins.authz.v1.AttributePolicy
-	53, // 44: workflow.plugins.authz.v1.RemoveAttributePolicyInput.filter:type_name -> workflow.plugins.authz.v1.AttributePolicyFilter
-	65, // 45: workflow.plugins.authz.v1.UpsertRelationTupleInput.tuple:type_name -> workflow.plugins.authz.v1.RelationTuple
-	65, // 46: workflow.plugins.authz.v1.UpsertRelationTupleOutput.tuple:type_name -> workflow.plugins.authz.v1.RelationTuple
-	66, // 47: workflow.plugins.authz.v1.ListRelationTuplesInput.filter:type_name -> workflow.plugins.authz.v1.RelationTupleFilter
-	65, // 48: workflow.plugins.authz.v1.ListRelationTuplesOutput.tuples:type_name -> workflow.plugins.authz.v1.RelationTuple
-	65, // 49: workflow.plugins.authz.v1.RemoveRelationTupleInput.tuple:type_name -> workflow.plugins.authz.v1.RelationTuple
-	22, // 50: workflow.plugins.authz.v1.UIActionDeclaration.required_capabilities:type_name -> workflow.plugins.authz.v1.CapabilityRequirement
-	41, // 51: workflow.plugins.authz.v1.AuthzDeclarationSet.scopes:type_name -> workflow.plugins.authz.v1.ScopeDeclaration
-	47, // 52: workflow.plugins.authz.v1.AuthzDeclarationSet.resources:type_name -> workflow.plugins.authz.v1.ResourceDeclaration
-	48, // 53: workflow.plugins.authz.v1.AuthzDeclarationSet.actions:type_name -> workflow.plugins.authz.v1.ActionDeclaration
-	50, // 54: workflow.plugins.authz.v1.AuthzDeclarationSet.attributes:type_name -> workflow.plugins.authz.v1.AttributeDeclaration
-	64, // 55: workflow.plugins.authz.v1.AuthzDeclarationSet.relations:type_name -> workflow.plugins.authz.v1.RelationDeclaration
-	75, // 56: workflow.plugins.authz.v1.AuthzDeclarationSet.ui_actions:type_name -> workflow.plugins.authz.v1.UIActionDeclaration
-	76, // 57: workflow.plugins.authz.v1.RegisterDeclarationsInput.declarations:type_name -> workflow.plugins.authz.v1.AuthzDeclarationSet
-	76, // 58: workflow.plugins.authz.v1.RegisterDeclarationsOutput.declarations:type_name -> workflow.plugins.authz.v1.AuthzDeclarationSet
-	76, // 59: workflow.plugins.authz.v1.ListDeclarationsOutput.declarations:type_name -> workflow.plugins.authz.v1.AuthzDeclarationSet
-	82, // 60: workflow.plugins.authz.v1.ResolveProjectionInputsOutput.projection:type_name -> workflow.plugins.authz.v1.ProjectionInputs
-	41, // 61: workflow.plugins.authz.v1.ResolveSubjectScopesOutput.declared_scopes:type_name -> workflow.plugins.authz.v1.ScopeDeclaration
-	86, // 62: workflow.plugins.authz.v1.UpsertRoleInput.grant:type_name -> workflow.plugins.authz.v1.RoleScopeGrant
-	86, // 63: workflow.plugins.authz.v1.UpsertRoleOutput.grant:type_name -> workflow.plugins.authz.v1.RoleScopeGrant
-	87, // 64: workflow.plugins.authz.v1.AssignRoleInput.assignment:type_name -> workflow.plugins.authz.v1.SubjectRoleAssignment
-	87, // 65: workflow.plugins.authz.v1.AssignRoleOutput.assignment:type_name -> workflow.plugins.authz.v1.SubjectRoleAssignment
-	88, // 66: workflow.plugins.authz.v1.ListRoleAssignmentsInput.filter:type_name -> workflow.plugins.authz.v1.AssignmentFilter
-	87, // 67: workflow.plugins.authz.v1.ListRoleAssignmentsOutput.assignments:type_name -> workflow.plugins.authz.v1.SubjectRoleAssignment
-	87, // 68: workflow.plugins.authz.v1.RemoveRoleAssignmentInput.assignment:type_name -> workflow.plugins.authz.v1.SubjectRoleAssignment
-	69, // [69:69] is the sub-list for method output_type
-	69, // [69:69] is the sub-list for method input_type
-	69, // [69:69] is the sub-list for extension type_name
-	69, // [69:69] is the sub-list for extension extendee
-	0,  // [0:69] is the sub-list for field type_name
+	2,   // 0: workflow.plugins.authz.v1.CasbinModuleConfig.policies:type_name -> workflow.plugins.authz.v1.StringList
+	2,   // 1: workflow.plugins.authz.v1.CasbinModuleConfig.role_assignments:type_name -> workflow.plugins.authz.v1.StringList
+	3,   // 2: workflow.plugins.authz.v1.CasbinModuleConfig.adapter:type_name -> workflow.plugins.authz.v1.AdapterConfig
+	4,   // 3: workflow.plugins.authz.v1.CasbinModuleConfig.watcher:type_name -> workflow.plugins.authz.v1.WatcherConfig
+	6,   // 4: workflow.plugins.authz.v1.CasbinModuleConfig.relation_model:type_name -> workflow.plugins.authz.v1.RelationModelConfig
+	7,   // 5: workflow.plugins.authz.v1.PermitModuleConfig.transport:type_name -> workflow.plugins.authz.v1.TransportConfig
+	8,   // 6: workflow.plugins.authz.v1.PermitModuleConfig.local_pdp:type_name -> workflow.plugins.authz.v1.LocalPDPConfig
+	4,   // 7: workflow.plugins.authz.v1.KetoModuleConfig.watcher:type_name -> workflow.plugins.authz.v1.WatcherConfig
+	7,   // 8: workflow.plugins.authz.v1.KetoModuleConfig.transport:type_name -> workflow.plugins.authz.v1.TransportConfig
+	11,  // 9: workflow.plugins.authz.v1.AuthzCheckConfig.extra_fields:type_name -> workflow.plugins.authz.v1.ExtraField
+	11,  // 10: workflow.plugins.authz.v1.AuthzCheckInput.extra_fields:type_name -> workflow.plugins.authz.v1.ExtraField
+	140, // 11: workflow.plugins.authz.v1.AuthzCheckOutput.response_headers:type_name -> google.protobuf.Struct
+	2,   // 12: workflow.plugins.authz.v1.RoleAssignConfig.assignments:type_name -> workflow.plugins.authz.v1.StringList
+	2,   // 13: workflow.plugins.authz.v1.RoleAssignInput.assignments:type_name -> workflow.plugins.authz.v1.StringList
+	2,   // 14: workflow.plugins.authz.v1.RoleAssignOutput.assignments:type_name -> workflow.plugins.authz.v1.StringList
+	24,  // 15: workflow.plugins.authz.v1.CapabilitiesOutput.descriptors:type_name -> workflow.plugins.authz.v1.CapabilityDescriptor
+	0,   // 16: workflow.plugins.authz.v1.CapabilityDescriptor.mode:type_name -> workflow.plugins.authz.v1.AuthzMode
+	1,   // 17: workflow.plugins.authz.v1.CapabilityDescriptor.operations:type_name -> workflow.plugins.authz.v1.AuthzOperation
+	0,   // 18: workflow.plugins.authz.v1.CapabilityRequirement.mode:type_name -> workflow.plugins.authz.v1.AuthzMode
+	1,   // 19: workflow.plugins.authz.v1.CapabilityRequirement.operations:type_name -> workflow.plugins.authz.v1.AuthzOperation
+	25,  // 20: workflow.plugins.authz.v1.ProviderCapabilitiesInput.requirements:type_name -> workflow.plugins.authz.v1.CapabilityRequirement
+	24,  // 21: workflow.plugins.authz.v1.ProviderCapabilitiesOutput.descriptors:type_name -> workflow.plugins.authz.v1.CapabilityDescriptor
+	0,   // 22: workflow.plugins.authz.v1.AuthorizationDecisionConfig.mode:type_name -> workflow.plugins.authz.v1.AuthzMode
+	0,   // 23: workflow.plugins.authz.v1.AuthorizationDecisionInput.mode:type_name -> workflow.plugins.authz.v1.AuthzMode
+	140, // 24: workflow.plugins.authz.v1.AuthorizationDecisionInput.subject_attributes:type_name -> google.protobuf.Struct
+	140, // 25: workflow.plugins.authz.v1.AuthorizationDecisionInput.resource_attributes:type_name -> google.protobuf.Struct
+	140, // 26: workflow.plugins.authz.v1.AuthorizationDecisionInput.environment_attributes:type_name -> google.protobuf.Struct
+	0,   // 27: workflow.plugins.authz.v1.AuthorizationDecisionOutput.mode:type_name -> workflow.plugins.authz.v1.AuthzMode
+	25,  // 28: workflow.plugins.authz.v1.RequireCapabilitiesConfig.requirements:type_name -> workflow.plugins.authz.v1.CapabilityRequirement
+	25,  // 29: workflow.plugins.authz.v1.RequireCapabilitiesInput.requirements:type_name -> workflow.plugins.authz.v1.CapabilityRequirement
+	140, // 30: workflow.plugins.authz.v1.GenericStepOutput.output:type_name -> google.protobuf.Struct
+	140, // 31: workflow.plugins.authz.v1.PermitStepConfig.values:type_name -> google.protobuf.Struct
+	140, // 32: workflow.plugins.authz.v1.PermitStepInput.values:type_name -> google.protobuf.Struct
+	44,  // 33: workflow.plugins.authz.v1.ScopeCatalogConfig.scopes:type_name -> workflow.plugins.authz.v1.ScopeDeclaration
+	84,  // 34: workflow.plugins.authz.v1.ScopeCatalogConfig.declarations:type_name -> workflow.plugins.authz.v1.AuthzDeclarationSet
+	3,   // 35: workflow.plugins.authz.v1.ScopeCatalogConfig.adapter:type_name -> workflow.plugins.authz.v1.AdapterConfig
+	4,   // 36: workflow.plugins.authz.v1.ScopeCatalogConfig.watcher:type_name -> workflow.plugins.authz.v1.WatcherConfig
+	44,  // 37: workflow.plugins.authz.v1.RegisterScopesInput.scopes:type_name -> workflow.plugins.authz.v1.ScopeDeclaration
+	44,  // 38: workflow.plugins.authz.v1.RegisterScopesOutput.scopes:type_name -> workflow.plugins.authz.v1.ScopeDeclaration
+	87,  // 39: workflow.plugins.authz.v1.RegisterScopesOutput.conflicts:type_name -> workflow.plugins.authz.v1.DeclarationConflict
+	44,  // 40: workflow.plugins.authz.v1.ListScopesOutput.scopes:type_name -> workflow.plugins.authz.v1.ScopeDeclaration
+	52,  // 41: workflow.plugins.authz.v1.AttributeDeclaration.allowed_values:type_name -> workflow.plugins.authz.v1.AttributeValue
+	54,  // 42: workflow.plugins.authz.v1.AttributePolicy.conditions:type_name -> workflow.plugins.authz.v1.AttributeCondition
+	140, // 43: workflow.plugins.authz.v1.AttributeCheckInput.subject_attributes:type_name -> google.protobuf.Struct
+	140, // 44: workflow.plugins.authz.v1.AttributeCheckInput.resource_attributes:type_name -> google.protobuf.Struct
+	140, // 45: workflow.plugins.authz.v1.AttributeCheckInput.environment_attributes:type_name -> google.protobuf.Struct
+	53,  // 46: workflow.plugins.authz.v1.DeclareAttributesInput.attributes:type_name -> workflow.plugins.authz.v1.AttributeDeclaration
+	53,  // 47: workflow.plugins.authz.v1.DeclareAttributesOutput.attributes:type_name -> workflow.plugins.authz.v1.AttributeDeclaration
+	55,  // 48: workflow.plugins.authz.v1.UpsertAttributePolicyInput.policy:type_name -> workflow.plugins.authz.v1.AttributePolicy
+	55,  // 49: workflow.plugins.authz.v1.UpsertAttributePolicyOutput.policy:type_name -> workflow.plugins.authz.v1.AttributePolicy
+	56,  // 50: workflow.plugins.authz.v1.ListAttributePoliciesInput.filter:type_name -> workflow.plugins.authz.v1.AttributePolicyFilter
+	55,  // 51: workflow.plugins.authz.v1.ListAttributePoliciesOutput.policies:type_name -> workflow.plugins.authz.v1.AttributePolicy
+	56,  // 52: workflow.plugins.authz.v1.RemoveAttributePolicyInput.filter:type_name -> workflow.plugins.authz.v1.AttributePolicyFilter
+	68,  // 53: workflow.plugins.authz.v1.UpsertRelationTupleInput.tuple:type_name -> workflow.plugins.authz.v1.RelationTuple
+	68,  // 54: workflow.plugins.authz.v1.UpsertRelationTupleOutput.tuple:type_name -> workflow.plugins.authz.v1.RelationTuple
+	69,  // 55: workflow.plugins.authz.v1.ListRelationTuplesInput.filter:type_name -> workflow.plugins.authz.v1.RelationTupleFilter
+	68,  // 56: workflow.plugins.authz.v1.ListRelationTuplesOutput.tuples:type_name -> workflow.plugins.authz.v1.RelationTuple
+	68,  // 57: workflow.plugins.authz.v1.RemoveRelationTupleInput.tuple:type_name -> workflow.plugins.authz.v1.RelationTuple
+	79,  // 58: workflow.plugins.authz.v1.UsersetTree.children:type_name -> workflow.plugins.authz.v1.UsersetTree
+	79,  // 59: workflow.plugins.authz.v1.ExpandRelationOutput.tree:type_name -> workflow.plugins.authz.v1.UsersetTree
+	25,  // 60: workflow.plugins.authz.v1.UIActionDeclaration.required_capabilities:type_name -> workflow.plugins.authz.v1.CapabilityRequirement
+	44,  // 61: workflow.plugins.authz.v1.AuthzDeclarationSet.scopes:type_name -> workflow.plugins.authz.v1.ScopeDeclaration
+	50,  // 62: workflow.plugins.authz.v1.AuthzDeclarationSet.resources:type_name -> workflow.plugins.authz.v1.ResourceDeclaration
+	51,  // 63: workflow.plugins.authz.v1.AuthzDeclarationSet.actions:type_name -> workflow.plugins.authz.v1.ActionDeclaration
+	53,  // 64: workflow.plugins.authz.v1.AuthzDeclarationSet.attributes:type_name -> workflow.plugins.authz.v1.AttributeDeclaration
+	67,  // 65: workflow.plugins.authz.v1.AuthzDeclarationSet.relations:type_name -> workflow.plugins.authz.v1.RelationDeclaration
+	83,  // 66: workflow.plugins.authz.v1.AuthzDeclarationSet.ui_actions:type_name -> workflow.plugins.authz.v1.UIActionDeclaration
+	84,  // 67: workflow.plugins.authz.v1.RegisterDeclarationsInput.declarations:type_name -> workflow.plugins.authz.v1.AuthzDeclarationSet
+	84,  // 68: workflow.plugins.authz.v1.RegisterDeclarationsOutput.declarations:type_name -> workflow.plugins.authz.v1.AuthzDeclarationSet
+	87,  // 69: workflow.plugins.authz.v1.RegisterDeclarationsOutput.conflicts:type_name -> workflow.plugins.authz.v1.DeclarationConflict
+	84,  // 70: workflow.plugins.authz.v1.UnregisterDeclarationsOutput.declarations:type_name -> workflow.plugins.authz.v1.AuthzDeclarationSet
+	84,  // 71: workflow.plugins.authz.v1.ListDeclarationsOutput.declarations:type_name -> workflow.plugins.authz.v1.AuthzDeclarationSet
+	93,  // 72: workflow.plugins.authz.v1.ResolveProjectionInputsOutput.projection:type_name -> workflow.plugins.authz.v1.ProjectionInputs
+	96,  // 73: workflow.plugins.authz.v1.SubjectProjection.resources:type_name -> workflow.plugins.authz.v1.PermittedResource
+	97,  // 74: workflow.plugins.authz.v1.ResolveSubjectProjectionOutput.projection:type_name -> workflow.plugins.authz.v1.SubjectProjection
+	44,  // 75: workflow.plugins.authz.v1.ResolveSubjectScopesOutput.declared_scopes:type_name -> workflow.plugins.authz.v1.ScopeDeclaration
+	104, // 76: workflow.plugins.authz.v1.MigrateScopesOutput.migrations:type_name -> workflow.plugins.authz.v1.ScopeMigration
+	106, // 77: workflow.plugins.authz.v1.UpsertRoleInput.grant:type_name -> workflow.plugins.authz.v1.RoleScopeGrant
+	106, // 78: workflow.plugins.authz.v1.UpsertRoleOutput.grant:type_name -> workflow.plugins.authz.v1.RoleScopeGrant
+	107, // 79: workflow.plugins.authz.v1.AssignRoleInput.assignment:type_name -> workflow.plugins.authz.v1.SubjectRoleAssignment
+	107, // 80: workflow.plugins.authz.v1.AssignRoleOutput.assignment:type_name -> workflow.plugins.authz.v1.SubjectRoleAssignment
+	108, // 81: workflow.plugins.authz.v1.ListRoleAssignmentsInput.filter:type_name -> workflow.plugins.authz.v1.AssignmentFilter
+	107, // 82: workflow.plugins.authz.v1.ListRoleAssignmentsOutput.assignments:type_name -> workflow.plugins.authz.v1.SubjectRoleAssignment
+	107, // 83: workflow.plugins.authz.v1.RemoveRoleAssignmentInput.assignment:type_name -> workflow.plugins.authz.v1.SubjectRoleAssignment
+	106, // 84: workflow.plugins.authz.v1.SimulationChange.grant:type_name -> workflow.plugins.authz.v1.RoleScopeGrant
+	107, // 85: workflow.plugins.authz.v1.SimulationChange.assignment:type_name -> workflow.plugins.authz.v1.SubjectRoleAssignment
+	68,  // 86: workflow.plugins.authz.v1.SimulationChange.tuple:type_name -> workflow.plugins.authz.v1.RelationTuple
+	55,  // 87: workflow.plugins.authz.v1.SimulationChange.policy:type_name -> workflow.plugins.authz.v1.AttributePolicy
+	0,   // 88: workflow.plugins.authz.v1.SimulationProbe.mode:type_name -> workflow.plugins.authz.v1.AuthzMode
+	140, // 89: workflow.plugins.authz.v1.SimulationProbe.subject_attributes:type_name -> google.protobuf.Struct
+	140, // 90: workflow.plugins.authz.v1.SimulationProbe.resource_attributes:type_name -> google.protobuf.Struct
+	140, // 91: workflow.plugins.authz.v1.SimulationProbe.environment_attributes:type_name -> google.protobuf.Struct
+	120, // 92: workflow.plugins.authz.v1.SimulationResult.probe:type_name -> workflow.plugins.authz.v1.SimulationProbe
+	119, // 93: workflow.plugins.authz.v1.SimulateConfig.changes:type_name -> workflow.plugins.authz.v1.SimulationChange
+	120, // 94: workflow.plugins.authz.v1.SimulateConfig.probes:type_name -> workflow.plugins.authz.v1.SimulationProbe
+	119, // 95: workflow.plugins.authz.v1.SimulateInput.changes:type_name -> workflow.plugins.authz.v1.SimulationChange
+	120, // 96: workflow.plugins.authz.v1.SimulateInput.probes:type_name -> workflow.plugins.authz.v1.SimulationProbe
+	121, // 97: workflow.plugins.authz.v1.SimulateOutput.flips:type_name -> workflow.plugins.authz.v1.SimulationResult
+	121, // 98: workflow.plugins.authz.v1.SimulateOutput.errors:type_name -> workflow.plugins.authz.v1.SimulationResult
+	0,   // 99: workflow.plugins.authz.v1.CompositeRoute.mode:type_name -> workflow.plugins.authz.v1.AuthzMode
+	125, // 100: workflow.plugins.authz.v1.CompositeModuleConfig.routes:type_name -> workflow.plugins.authz.v1.CompositeRoute
+	139, // 101: workflow.plugins.authz.v1.OPAModuleConfig.modules:type_name -> workflow.plugins.authz.v1.OPAModuleConfig.ModulesEntry
+	140, // 102: workflow.plugins.authz.v1.OPAModuleConfig.data:type_name -> google.protobuf.Struct
+	141, // 103: workflow.plugins.authz.v1.OPADataInput.value:type_name -> google.protobuf.Value
+	142, // 104: workflow.plugins.authz.v1.CedarModuleConfig.entities:type_name -> google.protobuf.ListValue
+	140, // 105: workflow.plugins.authz.v1.AuditEntry.before:type_name -> google.protobuf.Struct
+	140, // 106: workflow.plugins.authz.v1.AuditEntry.after:type_name -> google.protobuf.Struct
+	134, // 107: workflow.plugins.authz.v1.ListAuditEntriesOutput.entries:type_name -> workflow.plugins.authz.v1.AuditEntry
+	108, // [108:108] is the sub-list for method output_type
+	108, // [108:108] is the sub-list for method input_type
+	108, // [108:108] is the sub-list for extension type_name
+	108, // [108:108] is the sub-list for extension extendee
+	0,   // [0:108] is the sub-list for field type_name
 }
 
 func init() { file_internal_contracts_authz_proto_init() }
@@ -7475,13 +11198,14 @@ func file_internal_contracts_authz_proto_init() {
 	if File_internal_contracts_authz_proto != nil {
 		return
 	}
+	file_internal_contracts_authz_proto_msgTypes[5].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_internal_contracts_authz_proto_rawDesc), len(file_internal_contracts_authz_proto_rawDesc)),
 			NumEnums:      2,
-			NumMessages:   97,
+			NumMessages:   138,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -29,7 +29,17 @@ type AuthorizationDecisionOutput struct {
 	Explain string
 }
 
+// AuthorizationDecider is implemented by providers that make the whole
+// decision themselves, such as authz.composite routing it to other providers.
+// DecideAuthorization passes them the input unchanged.
+type AuthorizationDecider interface {
+	Decide(context.Context, AuthorizationDecisionInput) (AuthorizationDecisionOutput, error)
+}
+
 func DecideAuthorization(ctx context.Context, provider any, input AuthorizationDecisionInput) (AuthorizationDecisionOutput, error) {
+	if decider, ok := provider.(AuthorizationDecider); ok {
+		return decider.Decide(ctx, input)
+	}
 	mode, err := selectDecisionMode(provider, input)
 	if err != nil {
 		return AuthorizationDecisionOutput{}, err
@@ -0,0 +1,208 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/GoCodeAlone/workflow-plugin-authz/internal/contracts"
+)
+
+const (
+	// ownershipConflictsReject keeps the existing declaration and drops the
+	// incoming one.
+	ownershipConflictsReject = "reject"
+	// ownershipConflictsWarn lets the incoming declaration replace the existing
+	// one; the takeover is still reported as a conflict.
+	ownershipConflictsWarn = "warn"
+
+	conflictRejected    = "rejected"
+	conflictOverwritten = "overwritten"
+)
+
+// ErrScopeInUse is returned by UnregisterDeclarations when a scope it would
+// remove is still granted by a role or direct assignment and force is unset.
+var ErrScopeInUse = errors.New("scope is still granted")
+
+type ownedDeclaration interface {
+	GetOwnerPlugin() string
+	GetOwnerModule() string
+}
+
+// authzProviderLister is implemented by registries that can enumerate their
+// providers; the catalog uses it to count scope references.
+type authzProviderLister interface {
+	authzProviders() []AuthzProvider
+}
+
+func validOwnershipConflictPolicy(policy string) bool {
+	return policy == "" || policy == ownershipConflictsReject || policy == ownershipConflictsWarn
+}
+
+// claimLocked reports whether incoming may be stored over existing. A
+// declaration owned by a different plugin/module is a conflict, resolved by
+// the catalog's ownership_conflicts policy and appended to conflicts.
+// Unowned declarations (for example those from module config) can be claimed
+// by anyone.
+func (m *scopeCatalogModule) claimLocked(kind, key string, existing ownedDeclaration, exists bool, incoming ownedDeclaration, conflicts *[]*contracts.DeclarationConflict) bool {
+	if !exists || (existing.GetOwnerPlugin() == "" && existing.GetOwnerModule() == "") {
+		return true
+	}
+	if existing.GetOwnerPlugin() == incoming.GetOwnerPlugin() && existing.GetOwnerModule() == incoming.GetOwnerModule() {
+		return true
+	}
+	conflict := &contracts.DeclarationConflict{
+		Kind:                kind,
+		Key:                 key,
+		ExistingOwnerPlugin: existing.GetOwnerPlugin(),
+		ExistingOwnerModule: existing.GetOwnerModule(),
+		OwnerPlugin:         incoming.GetOwnerPlugin(),
+		OwnerModule:         incoming.GetOwnerModule(),
+		Resolution:          conflictRejected,
+	}
+	if m.ownershipConflicts == ownershipConflictsWarn {
+		conflict.Resolution = conflictOverwritten
+	}
+	*conflicts = append(*conflicts, conflict)
+	return conflict.Resolution == conflictOverwritten
+}
+
+// scopeReferences counts the role grants and direct assignments that still
+// grant scopeName across every provider the catalog's registry knows about.
+func (m *scopeCatalogModule) scopeReferences(scopeName string) int {
+	lister, ok := m.registry.(authzProviderLister)
+	if !ok {
+		return 0
+	}
+	count := 0
+	for _, provider := range lister.authzProviders() {
+		if source, ok := provider.(scopeRoleStoreProvider); ok {
+			if store := source.scopeRoleStore(); store != nil {
+				count += store.scopeReferences(scopeName)
+			}
+		}
+	}
+	return count
+}
+
+// unregisterDeclarations removes every declaration owned by the input's
+// plugin/module. Scopes that are still granted block the whole call with
+// ErrScopeInUse unless input.Force is set.
+func (m *scopeCatalogModule) unregisterDeclarations(input *contracts.UnregisterDeclarationsInput) (*contracts.UnregisterDeclarationsOutput, error) {
+	if input == nil || (input.GetOwnerPlugin() == "" && input.GetOwnerModule() == "") {
+		return nil, fmt.Errorf("owner_plugin or owner_module is required")
+	}
+	owner := &contracts.ListDeclarationsInput{OwnerPlugin: input.GetOwnerPlugin(), OwnerModule: input.GetOwnerModule()}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var inUse, forced []string
+	for name, scope := range m.scopes {
+		if !declarationMatches(owner, "", scope.GetOwnerPlugin(), scope.GetOwnerModule()) {
+			continue
+		}
+		if refs := m.scopeReferences(name); refs > 0 {
+			inUse = append(inUse, fmt.Sprintf("%s (%d)", name, refs))
+			forced = append(forced, name)
+		}
+	}
+	sort.Strings(inUse)
+	sort.Strings(forced)
+	if len(inUse) > 0 && !input.GetForce() {
+		return nil, fmt.Errorf("%w: %s; set force to remove anyway", ErrScopeInUse, strings.Join(inUse, ", "))
+	}
+
+	removed := &contracts.AuthzDeclarationSet{OwnerPlugin: input.GetOwnerPlugin(), OwnerModule: input.GetOwnerModule()}
+	for key, scope := range m.scopes {
+		if declarationMatches(owner, "", scope.GetOwnerPlugin(), scope.GetOwnerModule()) {
+			removed.Scopes = append(removed.Scopes, scope)
+			delete(m.scopes, key)
+		}
+	}
+	for key, resource := range m.resources {
+		if declarationMatches(owner, "", resource.GetOwnerPlugin(), resource.GetOwnerModule()) {
+			removed.Resources = append(removed.Resources, resource)
+			delete(m.resources, key)
+		}
+	}
+	for key, action := range m.actions {
+		if declarationMatches(owner, "", action.GetOwnerPlugin(), action.GetOwnerModule()) {
+			removed.Actions = append(removed.Actions, action)
+			delete(m.actions, key)
+		}
+	}
+	for key, attribute := range m.attributes {
+		if declarationMatches(owner, "", attribute.GetOwnerPlugin(), attribute.GetOwnerModule()) {
+			removed.Attributes = append(removed.Attributes, attribute)
+			delete(m.attributes, key)
+		}
+	}
+	for key, relation := range m.relations {
+		if declarationMatches(owner, "", relation.GetOwnerPlugin(), relation.GetOwnerModule()) {
+			removed.Relations = append(removed.Relations, relation)
+			delete(m.relations, key)
+		}
+	}
+	for key, action := range m.uiActions {
+		if declarationMatches(owner, "", action.GetOwnerPlugin(), action.GetOwnerModule()) {
+			removed.UiActions = append(removed.UiActions, action)
+			delete(m.uiActions, key)
+		}
+	}
+	sortDeclarationSet(removed)
+	out := &contracts.UnregisterDeclarationsOutput{
+		Removed:      int32(declarationCount(removed)),
+		Declarations: removed,
+		ForcedScopes: forced,
+	}
+	if out.GetRemoved() > 0 {
+		if m.store != nil {
+			if err := m.store.Delete(context.Background(), removed); err != nil {
+				m.putLocked(removed)
+				return nil, fmt.Errorf("persist unregistration: %w", err)
+			}
+		}
+		m.undeclareInSubscribersLocked(removed)
+		publishChange(m.name, "scope_catalog", ChangeDeclarationsUnregistered, unregisterDeclarationsOutputToMap(out))
+	}
+	return out, nil
+}
+
+func declarationCount(set *contracts.AuthzDeclarationSet) int {
+	return len(set.GetScopes()) + len(set.GetResources()) + len(set.GetActions()) +
+		len(set.GetAttributes()) + len(set.GetRelations()) + len(set.GetUiActions())
+}
+
+func unregisterDeclarationsInputFromMap(values map[string]any) *contracts.UnregisterDeclarationsInput {
+	return &contracts.UnregisterDeclarationsInput{
+		OwnerPlugin: stringValue(values["owner_plugin"]),
+		OwnerModule: stringValue(values["owner_module"]),
+		Force:       boolValue(values["force"]),
+	}
+}
+
+func unregisterDeclarationsOutputToMap(out *contracts.UnregisterDeclarationsOutput) map[string]any {
+	return map[string]any{
+		"removed":       int(out.GetRemoved()),
+		"declarations":  declarationSetToMap(out.GetDeclarations()),
+		"forced_scopes": append([]string{}, out.GetForcedScopes()...),
+	}
+}
+
+func declarationConflictsToMaps(conflicts []*contracts.DeclarationConflict) []map[string]any {
+	out := make([]map[string]any, 0, len(conflicts))
+	for _, conflict := range conflicts {
+		out = append(out, map[string]any{
+			"kind":                  conflict.GetKind(),
+			"key":                   conflict.GetKey(),
+			"existing_owner_plugin": conflict.GetExistingOwnerPlugin(),
+			"existing_owner_module": conflict.GetExistingOwnerModule(),
+			"owner_plugin":          conflict.GetOwnerPlugin(),
+			"owner_module":          conflict.GetOwnerModule(),
+			"resolution":            conflict.GetResolution(),
+		})
+	}
+	return out
+}
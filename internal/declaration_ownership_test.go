@@ -0,0 +1,127 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/GoCodeAlone/workflow-plugin-authz/internal/contracts"
+)
+
+type ownershipTestRegistry struct {
+	testRegistry
+}
+
+func (r *ownershipTestRegistry) authzProviders() []AuthzProvider {
+	return []AuthzProvider{r.mod}
+}
+
+func ordersDeclarations(ownerPlugin string) map[string]any {
+	return map[string]any{
+		"owner_plugin": ownerPlugin,
+		"declarations": map[string]any{
+			"scopes": []any{
+				map[string]any{"name": "shop:orders:read", "context": "shop", "resource": "orders", "actions": []any{"read"}},
+			},
+			"resources": []any{
+				map[string]any{"name": "orders", "context": "shop", "description": ownerPlugin},
+			},
+		},
+	}
+}
+
+func TestRegisterDeclarationsRejectsCrossOwnerConflicts(t *testing.T) {
+	catalog := newScopeCatalogModule("catalog", nil)
+	if _, err := catalog.InvokeMethod("RegisterDeclarations", ordersDeclarations("plugin-orders")); err != nil {
+		t.Fatalf("RegisterDeclarations: %v", err)
+	}
+	// Re-registering under the same owner is not a conflict.
+	out, err := catalog.InvokeMethod("RegisterDeclarations", ordersDeclarations("plugin-orders"))
+	if err != nil {
+		t.Fatalf("RegisterDeclarations: %v", err)
+	}
+	if conflicts := out["conflicts"].([]map[string]any); len(conflicts) != 0 {
+		t.Fatalf("conflicts = %v, want none for the same owner", conflicts)
+	}
+
+	out, err = catalog.InvokeMethod("RegisterDeclarations", ordersDeclarations("plugin-rogue"))
+	if err != nil {
+		t.Fatalf("RegisterDeclarations: %v", err)
+	}
+	conflicts := out["conflicts"].([]map[string]any)
+	if len(conflicts) != 2 || conflicts[0]["kind"] != "scope" || conflicts[0]["existing_owner_plugin"] != "plugin-orders" ||
+		conflicts[0]["owner_plugin"] != "plugin-rogue" || conflicts[1]["resolution"] != "rejected" {
+		t.Fatalf("conflicts = %v, want rejected scope and resource conflicts", conflicts)
+	}
+	if out["registered"] != 0 {
+		t.Fatalf("registered = %v, want 0", out["registered"])
+	}
+	set := catalog.listDeclarations(nil)
+	if set.GetScopes()[0].GetOwnerPlugin() != "plugin-orders" || set.GetResources()[0].GetDescription() != "plugin-orders" {
+		t.Fatalf("declarations = %v, want the original owner kept", set)
+	}
+}
+
+func TestRegisterScopesWarnPolicyOverwrites(t *testing.T) {
+	catalog := newScopeCatalogModule("catalog", map[string]any{"ownership_conflicts": "warn"})
+	if err := catalog.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	scope := map[string]any{"name": "shop:orders:read", "context": "shop", "resource": "orders", "actions": []any{"read"}}
+	if _, err := catalog.InvokeMethod("RegisterScopes", map[string]any{"owner_plugin": "plugin-orders", "scopes": []any{scope}}); err != nil {
+		t.Fatalf("RegisterScopes: %v", err)
+	}
+	out, err := catalog.InvokeMethod("RegisterScopes", map[string]any{"owner_plugin": "plugin-shop", "scopes": []any{scope}})
+	if err != nil {
+		t.Fatalf("RegisterScopes: %v", err)
+	}
+	conflicts := out["conflicts"].([]map[string]any)
+	if len(conflicts) != 1 || conflicts[0]["resolution"] != "overwritten" {
+		t.Fatalf("conflicts = %v, want one overwritten conflict", conflicts)
+	}
+	if owner := catalog.listScopes(nil)[0].GetOwnerPlugin(); owner != "plugin-shop" {
+		t.Fatalf("owner = %q, want plugin-shop", owner)
+	}
+
+	if err := newScopeCatalogModule("bad", map[string]any{"ownership_conflicts": "ignore"}).Init(); err == nil {
+		t.Fatal("expected error for unknown ownership_conflicts policy")
+	}
+}
+
+func TestUnregisterDeclarationsHonoursScopeReferences(t *testing.T) {
+	ctx := context.Background()
+	mod := rbacTestModule(t, nil, nil)
+	if err := mod.DeclareScopes(ctx, []*contracts.ScopeDeclaration{{Name: "shop:orders:read"}}); err != nil {
+		t.Fatalf("DeclareScopes: %v", err)
+	}
+	if err := mod.UpsertRole(ctx, RoleScopeGrant{Role: "clerk", Context: "shop", Scopes: []string{"shop:orders:read"}}); err != nil {
+		t.Fatalf("UpsertRole: %v", err)
+	}
+	catalog := newScopeCatalogModule("catalog", nil)
+	catalog.registry = &ownershipTestRegistry{testRegistry{mod: mod}}
+	if _, err := catalog.InvokeMethod("RegisterDeclarations", ordersDeclarations("plugin-orders")); err != nil {
+		t.Fatalf("RegisterDeclarations: %v", err)
+	}
+
+	if _, err := catalog.InvokeMethod("UnregisterDeclarations", nil); err == nil {
+		t.Fatal("expected error without an owner")
+	}
+	_, err := catalog.unregisterDeclarations(&contracts.UnregisterDeclarationsInput{OwnerPlugin: "plugin-orders"})
+	if !errors.Is(err, ErrScopeInUse) {
+		t.Fatalf("unregister = %v, want ErrScopeInUse", err)
+	}
+	if len(catalog.listScopes(nil)) != 1 {
+		t.Fatal("blocked unregistration removed declarations")
+	}
+
+	out, err := catalog.InvokeMethod("UnregisterDeclarations", map[string]any{"owner_plugin": "plugin-orders", "force": true})
+	if err != nil {
+		t.Fatalf("UnregisterDeclarations: %v", err)
+	}
+	if out["removed"] != 2 || len(out["forced_scopes"].([]string)) != 1 {
+		t.Fatalf("unregister = %v, want 2 removed with one forced scope", out)
+	}
+	if set := catalog.listDeclarations(nil); len(set.GetScopes()) != 0 || len(set.GetResources()) != 0 {
+		t.Fatalf("declarations = %v, want none left", set)
+	}
+}
@@ -0,0 +1,110 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// resolveScopeRoles returns the ScopeRoleProvider of the named module and its
+// local scope/role store. The store is nil when the provider keeps no local
+// copy of role grants, as for custom providers; callers then ask the provider
+// itself.
+func resolveScopeRoles(registry moduleRegistry, moduleName, providerName string) (ScopeRoleProvider, *scopeRoleStore, error) {
+	provider, err := resolveDecisionProvider(registry, moduleName, providerName)
+	if err != nil {
+		return nil, nil, err
+	}
+	roles, ok := provider.(ScopeRoleProvider)
+	if !ok {
+		return nil, nil, fmt.Errorf("%s module %q does not implement scope roles", providerName, moduleName)
+	}
+	var store *scopeRoleStore
+	if stores, ok := provider.(scopeRoleStoreProvider); ok {
+		store = stores.scopeRoleStore()
+	}
+	return roles, store, nil
+}
+
+// checkModuleScope reports whether subject holds scope in contextName. A
+// wildcard pattern is never declared, so it is checked against the subject's
+// own grants instead: a delegated admin holding "billing:*" may grant
+// "billing:*" or "billing:invoice:*".
+func checkModuleScope(ctx context.Context, registry moduleRegistry, moduleName, providerName, subject, contextName, scope string) (bool, error) {
+	roles, store, err := resolveScopeRoles(registry, moduleName, providerName)
+	if err != nil {
+		return false, err
+	}
+	if store != nil && isScopePattern(scope) {
+		if err := validateScopePattern(scope); err != nil {
+			return false, err
+		}
+		return store.holdsScopePattern(strings.TrimSpace(subject), strings.TrimSpace(contextName), strings.TrimSpace(scope)), nil
+	}
+	result, err := roles.CheckScope(ctx, ScopeCheck{Subject: subject, Context: contextName, Scope: scope})
+	if err != nil {
+		return false, err
+	}
+	return result.Allowed, nil
+}
+
+// moduleRoleScopes returns the scopes role grants in contextName. Providers
+// without a local store are asked through RoleLister; those that cannot list
+// roles report none.
+func moduleRoleScopes(ctx context.Context, registry moduleRegistry, moduleName, providerName, contextName, role string) ([]string, error) {
+	roles, store, err := resolveScopeRoles(registry, moduleName, providerName)
+	if err != nil {
+		return nil, err
+	}
+	if store != nil {
+		return store.roleScopes(contextName, role), nil
+	}
+	lister, ok := roles.(RoleLister)
+	if !ok {
+		return nil, nil
+	}
+	grants, err := lister.ListRoles(ctx, contextName)
+	if err != nil {
+		return nil, err
+	}
+	for _, grant := range grants {
+		if grant.Context == contextName && grant.Role == role {
+			return append([]string(nil), grant.Scopes...), nil
+		}
+	}
+	return nil, nil
+}
+
+// moduleScopeContexts returns the contexts with declared scopes or roles.
+// Providers without a local store report the contexts of their roles and
+// assignments.
+func moduleScopeContexts(ctx context.Context, registry moduleRegistry, moduleName, providerName string) ([]string, error) {
+	roles, store, err := resolveScopeRoles(registry, moduleName, providerName)
+	if err != nil {
+		return nil, err
+	}
+	if store != nil {
+		return store.contexts(), nil
+	}
+	var contexts []string
+	if lister, ok := roles.(RoleLister); ok {
+		grants, err := lister.ListRoles(ctx, "")
+		if err != nil {
+			return nil, err
+		}
+		for _, grant := range grants {
+			contexts = append(contexts, grant.Context)
+		}
+	}
+	assignments, err := roles.ListAssignments(ctx, AssignmentFilter{})
+	if err != nil {
+		return nil, err
+	}
+	for _, assignment := range assignments {
+		contexts = append(contexts, assignment.Context)
+	}
+	contexts = uniqueStrings(contexts)
+	sort.Strings(contexts)
+	return contexts, nil
+}
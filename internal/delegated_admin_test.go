@@ -0,0 +1,173 @@
+package internal
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/GoCodeAlone/workflow-plugin-authz/internal/contracts"
+)
+
+func TestModuleScopeRolesBackDelegatedAdmin(t *testing.T) {
+	ctx := context.Background()
+	mod := rbacTestModule(t, nil, nil)
+	if err := mod.DeclareScopes(ctx, []*contracts.ScopeDeclaration{
+		{Name: "billing:authz.roles:update"},
+		{Name: "billing:invoice:read"},
+		{Name: "platform:cluster:read"},
+	}); err != nil {
+		t.Fatalf("DeclareScopes: %v", err)
+	}
+	if err := mod.UpsertRole(ctx, RoleScopeGrant{Role: "billing_admin", Context: "billing", Scopes: []string{"billing:authz.roles:update", "billing:invoice:read"}}); err != nil {
+		t.Fatalf("UpsertRole: %v", err)
+	}
+	if err := mod.AssignRole(ctx, SubjectRoleAssignment{Subject: "alice", Role: "billing_admin", Context: "billing"}); err != nil {
+		t.Fatalf("AssignRole: %v", err)
+	}
+	registry := &testRegistry{mod: mod}
+
+	ok, err := checkModuleScope(ctx, registry, "authz", "casbin", "alice", "billing", "billing:authz.roles:update")
+	if err != nil || !ok {
+		t.Fatalf("checkModuleScope = %v, %v; want true", ok, err)
+	}
+	scopes, err := moduleRoleScopes(ctx, registry, "authz", "casbin", "billing", "billing_admin")
+	if err != nil || strings.Join(scopes, ",") != "billing:authz.roles:update,billing:invoice:read" {
+		t.Fatalf("moduleRoleScopes = %v, %v", scopes, err)
+	}
+	contexts, err := moduleScopeContexts(ctx, registry, "authz", "casbin")
+	if err != nil || strings.Join(contexts, ",") != "billing,platform" {
+		t.Fatalf("moduleScopeContexts = %v, %v", contexts, err)
+	}
+	if _, err := moduleScopeContexts(ctx, registry, "authz", "opa"); err == nil {
+		t.Fatal("expected unknown provider error")
+	}
+}
+
+func TestModuleScopeRolesLetDelegatedAdminsGrantHeldPatterns(t *testing.T) {
+	ctx := context.Background()
+	mod := rbacTestModule(t, nil, nil)
+	if err := mod.DeclareScopes(ctx, []*contracts.ScopeDeclaration{
+		{Name: "billing:invoice:read"},
+		{Name: "billing:invoice:refund"},
+		{Name: "billing:payout:read"},
+	}); err != nil {
+		t.Fatalf("DeclareScopes: %v", err)
+	}
+	if err := mod.UpsertRole(ctx, RoleScopeGrant{Role: "billing_admin", Context: "billing", Scopes: []string{"billing:*"}}); err != nil {
+		t.Fatalf("UpsertRole: %v", err)
+	}
+	if err := mod.UpsertRole(ctx, RoleScopeGrant{Role: "invoice_reader", Context: "billing", Scopes: []string{"billing:invoice:read"}}); err != nil {
+		t.Fatalf("UpsertRole: %v", err)
+	}
+	for _, assignment := range []SubjectRoleAssignment{
+		{Subject: "alice", Role: "billing_admin", Context: "billing"},
+		{Subject: "bob", Role: "invoice_reader", Context: "billing"},
+	} {
+		if err := mod.AssignRole(ctx, assignment); err != nil {
+			t.Fatalf("AssignRole: %v", err)
+		}
+	}
+	registry := &testRegistry{mod: mod}
+
+	for _, tc := range []struct {
+		subject, scope string
+		want           bool
+	}{
+		{"alice", "billing:invoice:read", true},
+		{"alice", "billing:*", true},
+		{"alice", "billing:invoice:*", true},
+		{"bob", "billing:invoice:read", true},
+		{"bob", "billing:invoice:*", false},
+		{"bob", "billing:*", false},
+	} {
+		ok, err := checkModuleScope(ctx, registry, "authz", "casbin", tc.subject, "billing", tc.scope)
+		if err != nil || ok != tc.want {
+			t.Fatalf("checkModuleScope(%s, %s) = %v, %v; want %v", tc.subject, tc.scope, ok, err, tc.want)
+		}
+	}
+	if ok, err := checkModuleScope(ctx, registry, "authz", "casbin", "alice", "platform", "platform:*"); err != nil || ok {
+		t.Fatalf("pattern in another context = %v, %v; want false", ok, err)
+	}
+	if _, err := checkModuleScope(ctx, registry, "authz", "casbin", "alice", "billing", "billing:inv*"); err == nil {
+		t.Fatal("expected malformed pattern error")
+	}
+}
+
+// storelessProvider hides the local store of a MemoryProvider, like a custom
+// provider that keeps its roles elsewhere, and lists roles itself.
+type storelessProvider struct {
+	AuthzProvider
+	ScopeRoleProvider
+	roles []RoleScopeGrant
+}
+
+func (p *storelessProvider) ListRoles(_ context.Context, contextName string) ([]RoleScopeGrant, error) {
+	var out []RoleScopeGrant
+	for _, grant := range p.roles {
+		if contextName == "" || grant.Context == contextName {
+			out = append(out, grant)
+		}
+	}
+	return out, nil
+}
+
+func TestModuleScopeRolesAskProvidersWithoutStore(t *testing.T) {
+	ctx := context.Background()
+	memory := NewMemoryProvider("storeless")
+	if err := memory.DeclareScopes(ctx, []*contracts.ScopeDeclaration{{Name: "shop:order:read"}}); err != nil {
+		t.Fatalf("DeclareScopes: %v", err)
+	}
+	if err := memory.AssignRole(ctx, SubjectRoleAssignment{Subject: "alice", Context: "shop", DirectScopes: []string{"shop:order:read"}}); err != nil {
+		t.Fatalf("AssignRole: %v", err)
+	}
+	provider := &storelessProvider{
+		AuthzProvider:     memory,
+		ScopeRoleProvider: memory,
+		roles:             []RoleScopeGrant{{Role: "auditor", Context: "audit", Scopes: []string{"audit:log:read"}}},
+	}
+	registry := &defaultRegistry{}
+	if err := registry.setCustomProvider("remote", "storeless", provider); err != nil {
+		t.Fatalf("setCustomProvider: %v", err)
+	}
+
+	scopes, err := moduleRoleScopes(ctx, registry, "storeless", "remote", "audit", "auditor")
+	if err != nil || strings.Join(scopes, ",") != "audit:log:read" {
+		t.Fatalf("moduleRoleScopes = %v, %v; want the listed role's scopes", scopes, err)
+	}
+	contexts, err := moduleScopeContexts(ctx, registry, "storeless", "remote")
+	if err != nil || strings.Join(contexts, ",") != "audit,shop" {
+		t.Fatalf("moduleScopeContexts = %v, %v; want the role and assignment contexts", contexts, err)
+	}
+}
+
+func TestCustomProvidersDoNotCollideWithModules(t *testing.T) {
+	memory := NewMemoryProvider("custom")
+	registry := &defaultRegistry{}
+	registry.setAuthzProvider("keto", memory)
+	if err := registry.setCustomProvider("remote", "keto", memory); err == nil {
+		t.Fatal("expected error registering a custom provider over a module")
+	}
+	if err := registry.setCustomProvider("remote", "custom", memory); err != nil {
+		t.Fatalf("setCustomProvider: %v", err)
+	}
+	if err := registry.setCustomProvider("other", "custom", memory); err == nil {
+		t.Fatal("expected error registering a name under a second kind")
+	}
+	if err := registry.setCustomProvider("remote", "custom", memory); err != nil {
+		t.Fatalf("re-registering the same kind: %v", err)
+	}
+
+	// A module registered later under the custom name does not pick up its
+	// kind.
+	module := NewMemoryProvider("module")
+	registry.setAuthzProvider("custom", module)
+	if provider, ok := registry.GetCustomProvider("remote", "custom"); !ok || provider != memory {
+		t.Fatalf("GetCustomProvider = %v, %v; want the custom provider", provider, ok)
+	}
+	if provider, ok := registry.GetAuthzProvider("custom"); !ok || provider != module {
+		t.Fatalf("GetAuthzProvider = %v, %v; want the module", provider, ok)
+	}
+	if providers := registry.authzProviders(); len(providers) != 2 {
+		t.Fatalf("authzProviders = %v, want the module and keto", providers)
+	}
+}
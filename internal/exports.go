@@ -2,7 +2,9 @@ package internal
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/GoCodeAlone/workflow-plugin-authz/internal/contracts"
 	sdk "github.com/GoCodeAlone/workflow/plugin/external/sdk"
 )
 
@@ -20,6 +22,35 @@ type StepExecutor interface {
 	) (*sdk.StepResult, error)
 }
 
+// ModuleInstance is the lifecycle every plugin module implements. It matches
+// sdk.ModuleInstance but is defined here for the same reason as StepExecutor.
+type ModuleInstance interface {
+	Init() error
+	Start(ctx context.Context) error
+	Stop(ctx context.Context) error
+}
+
+// ModuleTypes returns every module type the plugin provides.
+func ModuleTypes() []string {
+	return append([]string(nil), moduleTypes...)
+}
+
+// NewModule creates a module of typeName and registers it for steps exactly
+// as the external plugin does. Init has not been called yet.
+func NewModule(typeName, name string, config map[string]any) (ModuleInstance, error) {
+	return (&authzPlugin{}).CreateModule(typeName, name, config)
+}
+
+// StepTypes returns every step type the plugin provides.
+func StepTypes() []string {
+	return (&authzPlugin{}).StepTypes()
+}
+
+// NewStep creates a step of typeName.
+func NewStep(typeName, name string, config map[string]any) (StepExecutor, error) {
+	return (&authzPlugin{}).CreateStep(typeName, name, config)
+}
+
 // NewCasbinModuleFromConfig creates a CasbinModule from raw config.
 // Exported for use by the public authz/ package.
 func NewCasbinModuleFromConfig(name string, config map[string]any) (*CasbinModule, error) {
@@ -54,12 +85,12 @@ func NewPermitModuleFromConfig(name string, config map[string]any) (*PermitModul
 
 // NewPermitCheckStep creates a step.permit_check step instance.
 func NewPermitCheckStep(name string, config map[string]any) (StepExecutor, error) {
-	return newPermitCheckStep(name, config)
+	return createPermitStep("step.permit_check", name, config)
 }
 
 // NewPermitCheckBulkStep creates a step.permit_check_bulk step instance.
 func NewPermitCheckBulkStep(name string, config map[string]any) (StepExecutor, error) {
-	return newPermitCheckBulkStep(name, config)
+	return createPermitStep("step.permit_check_bulk", name, config)
 }
 
 // NewAuthzCapabilitiesStep creates a step.authz_capabilities step instance.
@@ -131,3 +162,147 @@ func NewPermitRoleAssignStep(name string, config map[string]any) (StepExecutor,
 func NewPermitRoleUnassignStep(name string, config map[string]any) (StepExecutor, error) {
 	return newPermitRoleUnassignStep(name, config)
 }
+
+// SubscribeChangeEvents subscribes to provider and catalog change events.
+// Events newer than afterID are returned as a backlog; complete is false when
+// afterID fell outside the retained window. Call cancel to unsubscribe.
+func SubscribeChangeEvents(afterID uint64) (backlog []ChangeEvent, events <-chan ChangeEvent, cancel func(), complete bool) {
+	return globalChangeFeed.subscribe(afterID, defaultChangeSubscriberSize)
+}
+
+// ResumeChangeEvents subscribes after a Last-Event-ID rendered by
+// ChangeEvent.EventID. An ID from another process epoch cannot be resumed, so
+// it subscribes to new events and reports complete as false.
+func ResumeChangeEvents(lastEventID string) (backlog []ChangeEvent, events <-chan ChangeEvent, cancel func(), complete bool, err error) {
+	if lastEventID == "" {
+		backlog, events, cancel, complete = SubscribeChangeEvents(0)
+		return backlog, events, cancel, complete, nil
+	}
+	afterID, current, err := globalChangeFeed.parseEventID(lastEventID)
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+	if !current {
+		_, events, cancel, _ = SubscribeChangeEvents(0)
+		return nil, events, cancel, false, nil
+	}
+	backlog, events, cancel, complete = SubscribeChangeEvents(afterID)
+	return backlog, events, cancel, complete, nil
+}
+
+// SimulationInputFromMap decodes a simulation request in the JSON shape of the
+// adminapi simulate route, as step.authz_simulate and the Simulate service
+// method do.
+func SimulationInputFromMap(values map[string]any) SimulationInput {
+	return simulationInputFromMap(values)
+}
+
+// SimulateModule runs a what-if simulation against the registered module with
+// the given name. provider is "casbin", "keto", or "permit".
+func SimulateModule(ctx context.Context, moduleName, provider string, input SimulationInput) (SimulationOutput, error) {
+	resolved, err := resolveDecisionProvider(globalRegistry, moduleName, provider)
+	if err != nil {
+		return SimulationOutput{}, err
+	}
+	return Simulate(ctx, resolved, input)
+}
+
+// CheckModuleScope reports whether subject holds scope in contextName
+// according to the ScopeRoleProvider of the named module.
+func CheckModuleScope(ctx context.Context, moduleName, provider, subject, contextName, scope string) (bool, error) {
+	return checkModuleScope(ctx, globalRegistry, moduleName, provider, subject, contextName, scope)
+}
+
+// ModuleRoleScopes returns the scopes granted by role in contextName within
+// the named module, or nil when the role is not defined.
+func ModuleRoleScopes(ctx context.Context, moduleName, provider, contextName, role string) ([]string, error) {
+	return moduleRoleScopes(ctx, globalRegistry, moduleName, provider, contextName, role)
+}
+
+// ModuleScopeContexts returns the contexts with declared scopes or roles in
+// the named module.
+func ModuleScopeContexts(ctx context.Context, moduleName, provider string) ([]string, error) {
+	return moduleScopeContexts(ctx, globalRegistry, moduleName, provider)
+}
+
+// RecordAuditEntry appends entry to the hash-chained log of the named
+// authz.audit module (the default module when moduleName is empty) and returns
+// it with its sequence number and hashes.
+func RecordAuditEntry(ctx context.Context, moduleName string, entry AuditEntry) (AuditEntry, error) {
+	log, ok := lookupAuditLog(moduleName)
+	if !ok {
+		return AuditEntry{}, fmt.Errorf("audit module %q not found", moduleName)
+	}
+	return log.Record(ctx, entry)
+}
+
+// ListAuditEntries returns the entries of the named authz.audit module that
+// match filter, in sequence order.
+func ListAuditEntries(ctx context.Context, moduleName string, filter AuditFilter) ([]AuditEntry, error) {
+	log, ok := lookupAuditLog(moduleName)
+	if !ok {
+		return nil, fmt.Errorf("audit module %q not found", moduleName)
+	}
+	return log.List(ctx, filter)
+}
+
+// ResolveSubjectProjection resolves the signed SPA access projection of
+// subject from the named authz.scope_catalog module, evaluated against the
+// provider module moduleName.
+func ResolveSubjectProjection(ctx context.Context, catalogName, moduleName, provider, subject, contextName string) (SubjectProjection, error) {
+	catalog, err := lookupScopeCatalog(globalRegistry, catalogName)
+	if err != nil {
+		return SubjectProjection{}, err
+	}
+	return catalog.resolveSubjectProjection(ctx, globalRegistry, &contracts.ResolveSubjectProjectionInput{
+		Subject:  subject,
+		Module:   moduleName,
+		Provider: provider,
+		Context:  contextName,
+	})
+}
+
+// VerifySubjectProjection checks that projection was issued by the named
+// catalog, is unmodified, and has not expired.
+func VerifySubjectProjection(catalogName string, projection SubjectProjection) error {
+	catalog, err := lookupScopeCatalog(globalRegistry, catalogName)
+	if err != nil {
+		return err
+	}
+	return catalog.verifySubjectProjection(projection)
+}
+
+// EnforceModule checks sub, obj, and act, with extra request dimensions
+// between sub and obj, against the authz.casbin module registered as
+// moduleName, as step.authz_check_casbin does.
+func EnforceModule(moduleName, sub, obj, act string, extra ...string) (bool, error) {
+	mod, ok := globalRegistry.GetEnforcer(moduleName)
+	if !ok {
+		return false, fmt.Errorf("authz module %q not found", moduleName)
+	}
+	return mod.Enforce(sub, obj, act, extra...)
+}
+
+// DecideModuleAuthorization resolves the named module the way step.authz_check
+// does and decides input against it. provider is "casbin", "keto", "permit",
+// "composite", "opa", "cedar", or the kind of a provider registered with
+// RegisterCustomProvider.
+func DecideModuleAuthorization(ctx context.Context, moduleName, provider string, input AuthorizationDecisionInput) (AuthorizationDecisionOutput, error) {
+	resolved, err := resolveDecisionProvider(globalRegistry, moduleName, provider)
+	if err != nil {
+		return AuthorizationDecisionOutput{}, err
+	}
+	input.Provider = provider
+	return DecideAuthorization(ctx, resolved, input)
+}
+
+// NewCapabilityDescriptor returns a configured, healthy descriptor for mode.
+func NewCapabilityDescriptor(mode AuthzCapability, operations []AuthzOperation, source string) CapabilityDescriptor {
+	return newCapabilityDescriptor(mode, operations, source)
+}
+
+// RequireDescribedCapabilities fails when descriptors do not satisfy every
+// requirement, with the same errors as the built-in providers.
+func RequireDescribedCapabilities(providerName string, descriptors []CapabilityDescriptor, requirements []CapabilityRequirement) error {
+	return requireCapabilities(providerName, descriptors, requirements)
+}
@@ -0,0 +1,102 @@
+package internal
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/GoCodeAlone/workflow-plugin-authz/internal/contracts"
+)
+
+// ketoNamespaceNames are the OPL namespaces generateKetoNamespaces emits, in
+// order.
+var ketoNamespaceNames = []string{"User", "role", "scope", "resource"}
+
+var oplIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// generateKetoNamespaces renders an Ory Permission Language file describing
+// the namespaces authz.keto writes: role membership, scope grants, and the
+// declared relations on resources. Every resource shares the "resource"
+// namespace, so a relation declared for several object types is rendered
+// once with the union of its subject types. A subject type written
+// "type#relation" becomes a subject set of that relation; any other subject
+// type is a User.
+func generateKetoNamespaces(relations []*contracts.RelationDeclaration) (string, error) {
+	type resourceRelation struct {
+		subjects map[string]bool
+		comments []string
+	}
+	resource := map[string]*resourceRelation{}
+	for _, relation := range relations {
+		name := strings.TrimSpace(relation.GetName())
+		if !oplIdentifier.MatchString(name) {
+			return "", fmt.Errorf("relation %q is not a valid OPL identifier", name)
+		}
+		subject := "User"
+		if _, setRelation, ok := strings.Cut(relation.GetSubjectType(), "#"); ok {
+			setRelation = strings.TrimSpace(setRelation)
+			if !oplIdentifier.MatchString(setRelation) {
+				return "", fmt.Errorf("relation %q: subject set relation %q is not a valid OPL identifier", name, setRelation)
+			}
+			subject = fmt.Sprintf("SubjectSet<resource, %q>", setRelation)
+		}
+		if resource[name] == nil {
+			resource[name] = &resourceRelation{subjects: map[string]bool{}}
+		}
+		resource[name].subjects[subject] = true
+		comment := strings.TrimSpace(relation.GetContext() + " " + relation.GetObjectType())
+		if description := strings.TrimSpace(relation.GetDescription()); description != "" {
+			comment += ": " + description
+		}
+		resource[name].comments = append(resource[name].comments, comment)
+	}
+
+	var b strings.Builder
+	b.WriteString("import { Namespace, SubjectSet } from \"@ory/keto-namespace-types\"\n\n")
+	b.WriteString("class User implements Namespace {}\n\n")
+	writeOPLClass(&b, "role", []string{"member: User[]"})
+	writeOPLClass(&b, "scope", []string{`granted: (User | SubjectSet<role, "member">)[]`})
+	var lines []string
+	for _, name := range sortedKeys(resource) {
+		relation := resource[name]
+		for _, comment := range relation.comments {
+			lines = append(lines, "// "+comment)
+		}
+		lines = append(lines, name+": "+oplSubjectTypes(relation.subjects))
+	}
+	writeOPLClass(&b, "resource", lines)
+	return strings.TrimSuffix(b.String(), "\n"), nil
+}
+
+func writeOPLClass(b *strings.Builder, name string, related []string) {
+	fmt.Fprintf(b, "class %s implements Namespace {\n", name)
+	if len(related) == 0 {
+		b.WriteString("}\n\n")
+		return
+	}
+	b.WriteString("  related: {\n")
+	for _, line := range related {
+		b.WriteString("    " + line + "\n")
+	}
+	b.WriteString("  }\n}\n\n")
+}
+
+// oplSubjectTypes renders the array type of a relation's subjects, with User
+// first.
+func oplSubjectTypes(subjects map[string]bool) string {
+	types := make([]string, 0, len(subjects))
+	for subject := range subjects {
+		types = append(types, subject)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		if (types[i] == "User") != (types[j] == "User") {
+			return types[i] == "User"
+		}
+		return types[i] < types[j]
+	})
+	if len(types) == 1 {
+		return types[0] + "[]"
+	}
+	return "(" + strings.Join(types, " | ") + ")[]"
+}
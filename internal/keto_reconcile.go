@@ -0,0 +1,181 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ketoManagedNamespaces are the namespaces authz.keto writes to, and so the
+// ones it reads back when reconciling.
+var ketoManagedNamespaces = []string{"scope", "role", "resource"}
+
+// ketoDrift lists the tuples that differed between the local state and Keto
+// when the provider was reconciled.
+type ketoDrift struct {
+	// Missing tuples were held locally but not found in Keto, such as writes
+	// that failed part way or tuples deleted out of band.
+	Missing []string
+	// Unexpected tuples were found in Keto but not held locally, such as
+	// writes made by another replica.
+	Unexpected []string
+}
+
+func (d ketoDrift) empty() bool { return len(d.Missing) == 0 && len(d.Unexpected) == 0 }
+
+func (d ketoDrift) toMap() map[string]any {
+	return map[string]any{"missing": d.Missing, "unexpected": d.Unexpected}
+}
+
+// ketoState is the authorization state decoded from Keto tuples.
+type ketoState struct {
+	roles     []RoleScopeGrant
+	assigns   []SubjectRoleAssignment
+	relations []RelationTuple
+}
+
+// Reconcile replaces the local roles, assignments, and relation tuples with
+// the tuples stored in Keto, and reports how the two differed. Declared scopes
+// are kept, since Keto does not store them.
+func (p *ketoScopeProvider) Reconcile(ctx context.Context) (ketoDrift, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var remote []ketoTuple
+	for _, namespace := range ketoManagedNamespaces {
+		tuples, err := p.client.ListRelationships(ctx, ketoTuple{Namespace: namespace})
+		if err != nil {
+			return ketoDrift{}, fmt.Errorf("list %s tuples: %w", namespace, err)
+		}
+		remote = append(remote, tuples...)
+	}
+	roles, assigns := p.store.grants()
+	local := encodeKetoState(ketoState{roles: roles, assigns: assigns, relations: p.relations.List(RelationTupleFilter{})})
+	drift := diffKetoTuples(local, remote)
+	state := decodeKetoTuples(remote, p.store.scopeContext)
+	p.store.replaceGrants(state.roles, state.assigns)
+	p.relations.replace(state.relations)
+	return drift, nil
+}
+
+// encodeKetoState returns the tuples authz.keto writes for state.
+func encodeKetoState(state ketoState) []ketoTuple {
+	var out []ketoTuple
+	for _, grant := range state.roles {
+		for _, scope := range grant.Scopes {
+			out = append(out, ketoRoleScopeTuple(grant.Context, grant.Role, scope))
+		}
+	}
+	for _, assignment := range state.assigns {
+		if assignment.Role != "" {
+			out = append(out, ketoSubjectRoleTuple(assignment.Context, assignment.Role, assignment.Subject))
+		}
+		for _, scope := range assignment.DirectScopes {
+			out = append(out, ketoDirectScopeTuple(assignment.Subject, scope))
+		}
+	}
+	for _, tuple := range state.relations {
+		out = append(out, ketoRelationshipTuple(tuple))
+	}
+	return out
+}
+
+// decodeKetoTuples rebuilds the state authz.keto wrote from its tuples.
+// Tuples in other namespaces or shapes are ignored. Keto does not record
+// which assignment granted a direct scope, so the direct scopes of a subject
+// in a context are decoded into an assignment without a role, separate from
+// its role assignments. Removing a role assignment then leaves them granted.
+// scopeContext resolves the context of a direct scope from its declaration;
+// undeclared scopes fall back to their "<context>:" prefix.
+func decodeKetoTuples(tuples []ketoTuple, scopeContext func(string) (string, bool)) ketoState {
+	type assignmentKey struct{ subject, context, role string }
+	roles := map[string]*RoleScopeGrant{}
+	assigns := map[assignmentKey]*SubjectRoleAssignment{}
+	direct := map[assignmentKey][]string{}
+	relations := map[string]RelationTuple{}
+	for _, tuple := range tuples {
+		switch {
+		case tuple.Namespace == "scope" && tuple.Relation == "granted" && tuple.SubjectSet != nil:
+			set := tuple.SubjectSet
+			contextName, role, ok := strings.Cut(set.Object, ":")
+			if set.Namespace != "role" || set.Relation != "member" || !ok {
+				continue
+			}
+			key := roleKey(contextName, role)
+			if roles[key] == nil {
+				roles[key] = &RoleScopeGrant{Role: role, Context: contextName}
+			}
+			roles[key].Scopes = append(roles[key].Scopes, tuple.Object)
+		case tuple.Namespace == "scope" && tuple.Relation == "granted" && tuple.SubjectID != "":
+			contextName, ok := scopeContext(tuple.Object)
+			if !ok {
+				contextName, _, ok = strings.Cut(tuple.Object, ":")
+			}
+			if !ok {
+				continue
+			}
+			key := assignmentKey{subject: tuple.SubjectID, context: contextName}
+			direct[key] = append(direct[key], tuple.Object)
+		case tuple.Namespace == "role" && tuple.Relation == "member" && tuple.SubjectID != "":
+			contextName, role, ok := strings.Cut(tuple.Object, ":")
+			if !ok {
+				continue
+			}
+			key := assignmentKey{subject: tuple.SubjectID, context: contextName, role: role}
+			assigns[key] = &SubjectRoleAssignment{Subject: tuple.SubjectID, Role: role, Context: contextName}
+		case tuple.Namespace == "resource":
+			contextName, object, ok := strings.Cut(tuple.Object, ":")
+			if !ok || tuple.SubjectSet != nil && tuple.SubjectSet.Namespace != "resource" {
+				continue
+			}
+			subject := ketoSubjectString(tuple.SubjectID, tuple.SubjectSet)
+			if subject == "" {
+				continue
+			}
+			relation := RelationTuple{Subject: subject, Relation: tuple.Relation, Object: object, Context: contextName}
+			relations[relationTupleKey(relation)] = relation
+		}
+	}
+
+	state := ketoState{}
+	for _, key := range sortedKeys(roles) {
+		grant := *roles[key]
+		grant.Scopes = uniqueStrings(grant.Scopes)
+		state.roles = append(state.roles, grant)
+	}
+	for _, assignment := range assigns {
+		state.assigns = append(state.assigns, *assignment)
+	}
+	sortAssignments(state.assigns)
+	for key, scopes := range direct {
+		state.assigns = append(state.assigns, SubjectRoleAssignment{Subject: key.subject, Context: key.context, DirectScopes: uniqueStrings(scopes)})
+	}
+	sortAssignments(state.assigns)
+	for _, key := range sortedKeys(relations) {
+		state.relations = append(state.relations, relations[key])
+	}
+	return state
+}
+
+// diffKetoTuples compares the tuples held locally with those in Keto.
+func diffKetoTuples(local, remote []ketoTuple) ketoDrift {
+	localSet := map[string]bool{}
+	for _, tuple := range local {
+		localSet[tuple.String()] = true
+	}
+	remoteSet := map[string]bool{}
+	for _, tuple := range remote {
+		remoteSet[tuple.String()] = true
+	}
+	var drift ketoDrift
+	for _, tuple := range sortedKeys(localSet) {
+		if !remoteSet[tuple] {
+			drift.Missing = append(drift.Missing, tuple)
+		}
+	}
+	for _, tuple := range sortedKeys(remoteSet) {
+		if !localSet[tuple] {
+			drift.Unexpected = append(drift.Unexpected, tuple)
+		}
+	}
+	return drift
+}
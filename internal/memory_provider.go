@@ -0,0 +1,132 @@
+package internal
+
+import (
+	"context"
+
+	"github.com/GoCodeAlone/workflow-plugin-authz/internal/contracts"
+)
+
+// MemoryProvider is an in-memory RBAC, ABAC, and ReBAC provider with the
+// semantics of the built-in stores. It backs the authztest fakes.
+type MemoryProvider struct {
+	name      string
+	store     *scopeRoleStore
+	abac      *attributePolicyStore
+	relations *relationTupleStore
+}
+
+// NewMemoryProvider returns an empty MemoryProvider named name.
+func NewMemoryProvider(name string) *MemoryProvider {
+	return &MemoryProvider{
+		name:      name,
+		store:     newScopeRoleStore("memory"),
+		abac:      newAttributePolicyStore("memory", nil),
+		relations: newRelationTupleStore(),
+	}
+}
+
+func (p *MemoryProvider) Name() string { return p.name }
+
+func (p *MemoryProvider) Capabilities() []AuthzCapability {
+	return capabilitiesFromDescriptors(p.CapabilityDescriptors())
+}
+
+func (p *MemoryProvider) SupportsCapability(capability AuthzCapability) bool {
+	for _, c := range p.Capabilities() {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *MemoryProvider) CapabilityDescriptors() []CapabilityDescriptor {
+	return []CapabilityDescriptor{
+		newCapabilityDescriptor(CapabilityRBAC, []AuthzOperation{OperationCheck, OperationManageRoles, OperationList}, "memory"),
+		newCapabilityDescriptor(CapabilityABAC, []AuthzOperation{OperationCheck, OperationManagePolicies, OperationList}, "memory"),
+		newCapabilityDescriptor(CapabilityReBAC, []AuthzOperation{OperationCheck, OperationManageRelations, OperationList}, "memory"),
+	}
+}
+
+func (p *MemoryProvider) RequireCapabilities(requirements []CapabilityRequirement) error {
+	return requireCapabilities("memory", p.CapabilityDescriptors(), requirements)
+}
+
+func (p *MemoryProvider) DeclareScopes(ctx context.Context, scopes []*contracts.ScopeDeclaration) error {
+	return p.store.DeclareScopes(ctx, scopes)
+}
+
+func (p *MemoryProvider) UpsertRole(ctx context.Context, grant RoleScopeGrant) error {
+	return p.store.UpsertRole(ctx, grant)
+}
+
+func (p *MemoryProvider) AssignRole(ctx context.Context, assignment SubjectRoleAssignment) error {
+	return p.store.AssignRole(ctx, assignment)
+}
+
+func (p *MemoryProvider) ListAssignments(ctx context.Context, filter AssignmentFilter) ([]SubjectRoleAssignment, error) {
+	return p.store.ListAssignments(ctx, filter)
+}
+
+func (p *MemoryProvider) RemoveAssignment(ctx context.Context, assignment SubjectRoleAssignment) error {
+	return p.store.RemoveAssignment(ctx, assignment)
+}
+
+func (p *MemoryProvider) CheckScope(ctx context.Context, check ScopeCheck) (ScopeCheckResult, error) {
+	return p.store.CheckScope(ctx, check)
+}
+
+func (p *MemoryProvider) scopeRoleStore() *scopeRoleStore { return p.store }
+
+func (p *MemoryProvider) DeclareAttributes(ctx context.Context, attrs []*contracts.AttributeDeclaration) error {
+	return p.abac.DeclareAttributes(ctx, attrs)
+}
+
+func (p *MemoryProvider) UpsertAttributePolicy(ctx context.Context, policy AttributePolicy) error {
+	return p.abac.UpsertAttributePolicy(ctx, policy)
+}
+
+func (p *MemoryProvider) ListAttributePolicies(ctx context.Context, filter AttributePolicyFilter) ([]AttributePolicy, error) {
+	return p.abac.ListAttributePolicies(ctx, filter)
+}
+
+func (p *MemoryProvider) RemoveAttributePolicy(ctx context.Context, filter AttributePolicyFilter) error {
+	return p.abac.RemoveAttributePolicy(ctx, filter)
+}
+
+func (p *MemoryProvider) CheckAttributes(ctx context.Context, check AttributeCheck) (AttributeCheckResult, error) {
+	return p.abac.CheckAttributes(ctx, check)
+}
+
+func (p *MemoryProvider) UpsertRelationTuple(_ context.Context, tuple RelationTuple) error {
+	return p.relations.Upsert(tuple)
+}
+
+func (p *MemoryProvider) RemoveRelationTuple(_ context.Context, tuple RelationTuple) error {
+	return p.relations.Remove(tuple)
+}
+
+func (p *MemoryProvider) ListRelationTuples(_ context.Context, filter RelationTupleFilter) ([]RelationTuple, error) {
+	return p.relations.List(filter), nil
+}
+
+func (p *MemoryProvider) CheckRelation(_ context.Context, check RelationCheck) (RelationCheckResult, error) {
+	tuple := normalizeRelationTuple(RelationTuple{Subject: check.Subject, Relation: check.Relation, Object: check.Object, Context: check.Context})
+	result := RelationCheckResult{Subject: tuple.Subject, Relation: tuple.Relation, Object: tuple.Object, Context: tuple.Context}
+	if err := validateRelationTuple(tuple); err != nil {
+		result.Reason = err.Error()
+		return result, nil
+	}
+	result.Allowed = p.relations.Contains(RelationCheck{Subject: tuple.Subject, Relation: tuple.Relation, Object: tuple.Object, Context: tuple.Context})
+	if !result.Allowed {
+		result.Reason = "relation tuple not found"
+	}
+	return result, nil
+}
+
+var (
+	_ AuthzProvider           = (*MemoryProvider)(nil)
+	_ ScopeRoleProvider       = (*MemoryProvider)(nil)
+	_ AttributePolicyProvider = (*MemoryProvider)(nil)
+	_ RelationshipProvider    = (*MemoryProvider)(nil)
+)
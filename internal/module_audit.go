@@ -0,0 +1,197 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// auditModule implements sdk.ModuleInstance for authz.audit. It owns the
+// audit sink and registers the hash-chained log used by mutating steps and
+// by the adminapi bridge.
+//
+// Config:
+//
+//	sink: "jsonl"              # "memory" (default), "jsonl", or "gorm"
+//	path: "/var/log/authz.jsonl" # jsonl file
+//	driver: "postgres"         # gorm: "postgres", "mysql", or "sqlite3"
+//	dsn: "..."                 # gorm DSN
+//	table_name: "authz_audit"  # gorm table (default: "authz_audit")
+type auditModule struct {
+	name   string
+	config auditModuleConfig
+	log    *auditLog
+}
+
+type auditModuleConfig struct {
+	Sink      string
+	Path      string
+	Driver    string
+	DSN       string
+	TableName string
+}
+
+func newAuditModule(name string, config map[string]any) (*auditModule, error) {
+	cfg := auditModuleConfig{
+		Sink:      strings.ToLower(stringValue(config["sink"])),
+		Path:      stringValue(config["path"]),
+		Driver:    stringValue(config["driver"]),
+		DSN:       stringValue(config["dsn"]),
+		TableName: stringValue(config["table_name"]),
+	}
+	switch cfg.Sink {
+	case "", "memory":
+	case "jsonl":
+		if cfg.Path == "" {
+			return nil, fmt.Errorf("authz.audit %q: config.path is required for jsonl sink", name)
+		}
+	case "gorm":
+		if cfg.DSN == "" {
+			return nil, fmt.Errorf("authz.audit %q: config.dsn is required for gorm sink", name)
+		}
+	default:
+		return nil, fmt.Errorf("authz.audit %q: unknown sink %q", name, cfg.Sink)
+	}
+	return &auditModule{name: name, config: cfg}, nil
+}
+
+// Init opens the sink and registers the audit log.
+func (m *auditModule) Init() error {
+	sink, err := m.openSink()
+	if err != nil {
+		return fmt.Errorf("authz.audit %q: %w", m.name, err)
+	}
+	m.log = newAuditLog(sink)
+	registerAuditLog(m.name, m.log)
+	return nil
+}
+
+func (m *auditModule) openSink() (AuditSink, error) {
+	switch m.config.Sink {
+	case "jsonl":
+		return newJSONLAuditSink(m.config.Path)
+	case "gorm":
+		db, err := openGORM(m.config.Driver, m.config.DSN)
+		if err != nil {
+			return nil, err
+		}
+		return newGORMAuditSink(db, m.config.TableName)
+	default:
+		return &memoryAuditSink{}, nil
+	}
+}
+
+func (m *auditModule) Start(_ context.Context) error { return nil }
+
+// Stop unregisters the log and closes the sink.
+func (m *auditModule) Stop(_ context.Context) error {
+	if m.log == nil {
+		return nil
+	}
+	unregisterAuditLog(m.name)
+	return m.log.sink.Close()
+}
+
+// Name returns the module name.
+func (m *auditModule) Name() string { return m.name }
+
+func (m *auditModule) InvokeMethod(method string, input map[string]any) (map[string]any, error) {
+	if m.log == nil {
+		return nil, fmt.Errorf("authz.audit %q: not initialized", m.name)
+	}
+	ctx := context.Background()
+	switch method {
+	case "ListAuditEntries":
+		filter, err := auditFilterFromMap(input)
+		if err != nil {
+			return nil, fmt.Errorf("authz.audit %q: %w", m.name, err)
+		}
+		entries, err := m.log.List(ctx, filter)
+		if err != nil {
+			return nil, fmt.Errorf("authz.audit %q: %w", m.name, err)
+		}
+		return map[string]any{"entries": auditEntriesToAny(entries)}, nil
+	case "VerifyAuditChain":
+		checked, err := m.log.Verify(ctx)
+		return verifyAuditChainOutputToMap(checked, err)
+	default:
+		return nil, fmt.Errorf("authz.audit method %q is not supported", method)
+	}
+}
+
+func auditFilterFromMap(values map[string]any) (AuditFilter, error) {
+	filter := AuditFilter{
+		Actor:     stringValue(values["actor"]),
+		Operation: stringValue(values["operation"]),
+		Resource:  stringValue(values["resource"]),
+		Context:   stringValue(values["context"]),
+		Limit:     intValue(values["limit"]),
+	}
+	var err error
+	if filter.Since, err = auditTimeFromString("since", stringValue(values["since"])); err != nil {
+		return filter, err
+	}
+	if filter.Until, err = auditTimeFromString("until", stringValue(values["until"])); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
+
+func auditTimeFromString(field, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s must be RFC 3339: %w", field, err)
+	}
+	return t, nil
+}
+
+func auditEntryToMap(entry AuditEntry) map[string]any {
+	out := compactMap(map[string]any{
+		"seq":         entry.Seq,
+		"time":        entry.Time.UTC().Format(time.RFC3339Nano),
+		"actor":       entry.Actor,
+		"actor_email": entry.ActorEmail,
+		"source":      entry.Source,
+		"operation":   entry.Operation,
+		"module":      entry.Module,
+		"resource":    entry.Resource,
+		"action":      entry.Action,
+		"context":     entry.Context,
+		"reason":      entry.Reason,
+		"prev_hash":   entry.PrevHash,
+		"hash":        entry.Hash,
+	})
+	if entry.Before != nil {
+		out["before"] = entry.Before
+	}
+	if entry.After != nil {
+		out["after"] = entry.After
+	}
+	return out
+}
+
+func auditEntriesToAny(entries []AuditEntry) []any {
+	out := make([]any, 0, len(entries))
+	for _, entry := range entries {
+		out = append(out, auditEntryToMap(entry))
+	}
+	return out
+}
+
+func verifyAuditChainOutputToMap(checked int, err error) (map[string]any, error) {
+	out := map[string]any{"valid": err == nil, "checked": checked}
+	var chainErr *AuditChainError
+	switch {
+	case errors.As(err, &chainErr):
+		out["broken_seq"] = chainErr.Seq
+		out["reason"] = chainErr.Reason
+	case err != nil:
+		return nil, err
+	}
+	return out, nil
+}
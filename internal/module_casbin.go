@@ -31,6 +31,14 @@ type CasbinModule struct {
 	scopeRoles *scopeRoleStore
 	abac       *attributePolicyStore
 	relations  *relationTupleStore
+	namespaces *relationNamespaces
+	// relationModel, when configured, validates relation tuples and decides
+	// CheckRelation by its rewrite rules.
+	relationModel *relationModel
+
+	// simulated marks an isolated what-if copy whose mutations must not be
+	// published to the change feed.
+	simulated bool
 
 	// polling watcher fields
 	stopCh chan struct{}
@@ -71,6 +79,17 @@ type watcherConfig struct {
 	Interval time.Duration `yaml:"interval"`
 }
 
+// relationModelConfig describes an OpenFGA authorization model or SpiceDB
+// schema for the module's relation tuples.
+type relationModelConfig struct {
+	// Format is "openfga" or "spicedb"; detected from the schema when empty.
+	Format string `yaml:"format"`
+	// Schema is the model text: the OpenFGA DSL or JSON, or a SpiceDB schema.
+	Schema string `yaml:"schema"`
+	// File reads the model text from disk when Schema is empty.
+	File string `yaml:"file"`
+}
+
 // casbinConfig holds the parsed configuration for an authz.casbin module.
 type casbinConfig struct {
 	// Model is a PERM model definition (ini-style text).
@@ -83,6 +102,12 @@ type casbinConfig struct {
 	Adapter adapterConfig `yaml:"adapter"`
 	// Watcher describes the optional polling watcher.
 	Watcher watcherConfig `yaml:"watcher"`
+	// Catalog names an authz.scope_catalog whose declarations are replayed
+	// into this module and pushed as they are registered.
+	Catalog string `yaml:"catalog"`
+	// RelationModel describes the optional relation model; a bare string is
+	// the schema.
+	RelationModel relationModelConfig `yaml:"relation_model"`
 }
 
 // newCasbinModule parses the config map and returns a CasbinModule.
@@ -97,6 +122,7 @@ func newCasbinModule(name string, config map[string]any) (*CasbinModule, error)
 		scopeRoles: newScopeRoleStore("casbin"),
 		abac:       newAttributePolicyStore("casbin", nil),
 		relations:  newRelationTupleStore(),
+		namespaces: newRelationNamespaces(),
 	}, nil
 }
 
@@ -146,6 +172,17 @@ func parseCasbinConfig(raw map[string]any) (casbinConfig, error) {
 		cfg.Watcher = parseWatcherConfig(watcherRaw)
 	}
 
+	cfg.Catalog, _ = raw["catalog"].(string)
+
+	switch relationModel := raw["relation_model"].(type) {
+	case string:
+		cfg.RelationModel.Schema = relationModel
+	case map[string]any:
+		cfg.RelationModel.Format, _ = relationModel["format"].(string)
+		cfg.RelationModel.Schema, _ = relationModel["schema"].(string)
+		cfg.RelationModel.File, _ = relationModel["file"].(string)
+	}
+
 	return cfg, nil
 }
 
@@ -221,24 +258,9 @@ func (m *CasbinModule) buildGORMAdapter() (persist.Adapter, error) {
 		return nil, fmt.Errorf("authz.casbin %q: adapter.dsn is required for gorm adapter", m.name)
 	}
 
-	silentLogger := gormlogger.Default.LogMode(gormlogger.Silent)
-	gormCfg := &gorm.Config{Logger: silentLogger}
-
-	var db *gorm.DB
-	var err error
-
-	switch strings.ToLower(m.config.Adapter.Driver) {
-	case "postgres", "postgresql":
-		db, err = gorm.Open(postgres.Open(m.config.Adapter.DSN), gormCfg)
-	case "mysql":
-		db, err = gorm.Open(mysql.Open(m.config.Adapter.DSN), gormCfg)
-	case "sqlite3", "sqlite":
-		db, err = gorm.Open(openSQLite(m.config.Adapter.DSN), gormCfg)
-	default:
-		return nil, fmt.Errorf("authz.casbin %q: unsupported gorm driver %q (supported: postgres, mysql, sqlite3)", m.name, m.config.Adapter.Driver)
-	}
+	db, err := openGORM(m.config.Adapter.Driver, m.config.Adapter.DSN)
 	if err != nil {
-		return nil, fmt.Errorf("authz.casbin %q: open gorm db: %w", m.name, err)
+		return nil, fmt.Errorf("authz.casbin %q: %w", m.name, err)
 	}
 
 	// Option B: resolve table_name template (e.g. "casbin_rule_{{.Tenant}}").
@@ -255,6 +277,28 @@ func (m *CasbinModule) buildGORMAdapter() (persist.Adapter, error) {
 	return a, nil
 }
 
+// openGORM opens a silent GORM connection for driver ("postgres", "mysql" or
+// "sqlite3").
+func openGORM(driver, dsn string) (*gorm.DB, error) {
+	gormCfg := &gorm.Config{Logger: gormlogger.Default.LogMode(gormlogger.Silent)}
+	var db *gorm.DB
+	var err error
+	switch strings.ToLower(driver) {
+	case "postgres", "postgresql":
+		db, err = gorm.Open(postgres.Open(dsn), gormCfg)
+	case "mysql":
+		db, err = gorm.Open(mysql.Open(dsn), gormCfg)
+	case "sqlite3", "sqlite":
+		db, err = gorm.Open(openSQLite(dsn), gormCfg)
+	default:
+		return nil, fmt.Errorf("unsupported gorm driver %q (supported: postgres, mysql, sqlite3)", driver)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open gorm db: %w", err)
+	}
+	return db, nil
+}
+
 // resolveTableNameTemplate resolves a Go template in tableName.
 // The template data is the adapterConfig, so {{.Tenant}} expands to cfg.Tenant.
 // If tableName contains no template markers it is returned unchanged.
@@ -273,8 +317,22 @@ func resolveTableNameTemplate(tableName string, cfg adapterConfig) (string, erro
 	return buf.String(), nil
 }
 
-// Init builds the Casbin enforcer from the configured adapter.
+// Init builds the Casbin enforcer from the configured adapter, loads the
+// relation model and, when a catalog is configured, replays its declarations.
 func (m *CasbinModule) Init() error {
+	if err := m.initEnforcer(); err != nil {
+		return err
+	}
+	if err := m.loadRelationModel(); err != nil {
+		return fmt.Errorf("authz.casbin %q: relation_model: %w", m.name, err)
+	}
+	if err := subscribeToScopeCatalog(globalRegistry, m.config.Catalog, m); err != nil {
+		return fmt.Errorf("authz.casbin %q: %w", m.name, err)
+	}
+	return nil
+}
+
+func (m *CasbinModule) initEnforcer() error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -297,6 +355,28 @@ func (m *CasbinModule) Init() error {
 	return nil
 }
 
+// loadRelationModel parses the configured relation model. Tuples are stored
+// as g2 grouping rows, so the Casbin model must define g2.
+func (m *CasbinModule) loadRelationModel() error {
+	cfg := m.config.RelationModel
+	if m.relationModel != nil || (cfg.Schema == "" && cfg.File == "") {
+		return nil
+	}
+	if !m.SupportsCapability(CapabilityReBAC) {
+		return fmt.Errorf("the casbin model must define g2 to store relation tuples")
+	}
+	text, err := readInlineOrFile(cfg.Schema, cfg.File)
+	if err != nil {
+		return err
+	}
+	model, err := parseRelationModel(cfg.Format, text)
+	if err != nil {
+		return err
+	}
+	m.relationModel = model
+	return nil
+}
+
 // Start begins the polling watcher goroutine if watcher.type is "polling".
 func (m *CasbinModule) Start(_ context.Context) error {
 	if strings.ToLower(m.config.Watcher.Type) != "polling" {
@@ -395,6 +475,9 @@ func (m *CasbinModule) AddPolicy(rule []string) (bool, error) {
 			return false, err
 		}
 	}
+	if ok {
+		m.emitChange(ChangePolicyAdded, map[string]any{"rule": stringsToAny(rule)})
+	}
 	return ok, nil
 }
 
@@ -415,6 +498,9 @@ func (m *CasbinModule) RemovePolicy(rule []string) (bool, error) {
 			return false, err
 		}
 	}
+	if ok {
+		m.emitChange(ChangePolicyRemoved, map[string]any{"rule": stringsToAny(rule)})
+	}
 	return ok, nil
 }
 
@@ -435,6 +521,9 @@ func (m *CasbinModule) AddGroupingPolicy(rule []string) (bool, error) {
 			return false, err
 		}
 	}
+	if ok {
+		m.emitChange(ChangeRoleAssigned, map[string]any{"rule": stringsToAny(rule)})
+	}
 	return ok, nil
 }
 
@@ -455,6 +544,9 @@ func (m *CasbinModule) RemoveGroupingPolicy(rule []string) (bool, error) {
 			return false, err
 		}
 	}
+	if ok {
+		m.emitChange(ChangeRoleUnassigned, map[string]any{"rule": stringsToAny(rule)})
+	}
 	return ok, nil
 }
 
@@ -470,6 +562,13 @@ func toInterfaceSlice(ss []string) []interface{} {
 // Name returns the module name.
 func (m *CasbinModule) Name() string { return m.name }
 
+func (m *CasbinModule) emitChange(eventType ChangeEventType, data map[string]any) {
+	if m.simulated {
+		return
+	}
+	publishChange(m.name, "casbin", eventType, data)
+}
+
 func (m *CasbinModule) scopeRoleStore() *scopeRoleStore {
 	if m.scopeRoles == nil {
 		m.scopeRoles = newScopeRoleStore("casbin")
@@ -482,11 +581,19 @@ func (m *CasbinModule) DeclareScopes(ctx context.Context, scopes []*contracts.Sc
 }
 
 func (m *CasbinModule) UpsertRole(ctx context.Context, grant RoleScopeGrant) error {
-	return m.scopeRoleStore().UpsertRole(ctx, grant)
+	if err := m.scopeRoleStore().UpsertRole(ctx, grant); err != nil {
+		return err
+	}
+	m.emitChange(ChangeRoleUpserted, roleScopeGrantToMap(grant))
+	return nil
 }
 
 func (m *CasbinModule) AssignRole(ctx context.Context, assignment SubjectRoleAssignment) error {
-	return m.scopeRoleStore().AssignRole(ctx, assignment)
+	if err := m.scopeRoleStore().AssignRole(ctx, assignment); err != nil {
+		return err
+	}
+	m.emitChange(ChangeRoleAssigned, subjectRoleAssignmentToMap(assignment))
+	return nil
 }
 
 func (m *CasbinModule) ListAssignments(ctx context.Context, filter AssignmentFilter) ([]SubjectRoleAssignment, error) {
@@ -494,7 +601,11 @@ func (m *CasbinModule) ListAssignments(ctx context.Context, filter AssignmentFil
 }
 
 func (m *CasbinModule) RemoveAssignment(ctx context.Context, assignment SubjectRoleAssignment) error {
-	return m.scopeRoleStore().RemoveAssignment(ctx, assignment)
+	if err := m.scopeRoleStore().RemoveAssignment(ctx, assignment); err != nil {
+		return err
+	}
+	m.emitChange(ChangeRoleUnassigned, subjectRoleAssignmentToMap(assignment))
+	return nil
 }
 
 func (m *CasbinModule) CheckScope(ctx context.Context, check ScopeCheck) (ScopeCheckResult, error) {
@@ -512,7 +623,11 @@ func (m *CasbinModule) UpsertAttributePolicy(ctx context.Context, policy Attribu
 	if !m.SupportsCapability(CapabilityABAC) {
 		return errUnsupportedABAC
 	}
-	return m.abac.UpsertAttributePolicy(ctx, policy)
+	if err := m.abac.UpsertAttributePolicy(ctx, policy); err != nil {
+		return err
+	}
+	m.emitChange(ChangeAttributePolicyUpserted, attributePolicyToMap(policy))
+	return nil
 }
 
 func (m *CasbinModule) ListAttributePolicies(ctx context.Context, filter AttributePolicyFilter) ([]AttributePolicy, error) {
@@ -526,7 +641,11 @@ func (m *CasbinModule) RemoveAttributePolicy(ctx context.Context, filter Attribu
 	if !m.SupportsCapability(CapabilityABAC) {
 		return errUnsupportedABAC
 	}
-	return m.abac.RemoveAttributePolicy(ctx, filter)
+	if err := m.abac.RemoveAttributePolicy(ctx, filter); err != nil {
+		return err
+	}
+	m.emitChange(ChangeAttributePolicyRemoved, compactMap(map[string]any{"id": filter.ID, "context": filter.Context}))
+	return nil
 }
 
 func (m *CasbinModule) CheckAttributes(ctx context.Context, check AttributeCheck) (AttributeCheckResult, error) {
@@ -536,6 +655,14 @@ func (m *CasbinModule) CheckAttributes(ctx context.Context, check AttributeCheck
 	return m.abac.CheckAttributes(ctx, check)
 }
 
+// DeclareRelations records relation namespaces declared in the scope catalog.
+func (m *CasbinModule) DeclareRelations(_ context.Context, relations []*contracts.RelationDeclaration) error {
+	if !m.SupportsCapability(CapabilityReBAC) {
+		return errUnsupportedReBAC
+	}
+	return m.namespaces.declare(relations)
+}
+
 func (m *CasbinModule) UpsertRelationTuple(_ context.Context, tuple RelationTuple) error {
 	if !m.SupportsCapability(CapabilityReBAC) {
 		return errUnsupportedReBAC
@@ -544,6 +671,11 @@ func (m *CasbinModule) UpsertRelationTuple(_ context.Context, tuple RelationTupl
 	if err := validateRelationTuple(tuple); err != nil {
 		return err
 	}
+	if m.relationModel != nil {
+		if err := m.relationModel.validateTuple(tuple); err != nil {
+			return err
+		}
+	}
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	if m.enforcer == nil {
@@ -552,7 +684,11 @@ func (m *CasbinModule) UpsertRelationTuple(_ context.Context, tuple RelationTupl
 	if _, err := m.enforcer.AddNamedGroupingPolicy("g2", tuple.Subject, tuple.Relation, tuple.Object); err != nil {
 		return err
 	}
-	return m.relations.Upsert(tuple)
+	if err := m.relations.Upsert(tuple); err != nil {
+		return err
+	}
+	m.emitChange(ChangeRelationWritten, relationTupleToMap(tuple))
+	return nil
 }
 
 func (m *CasbinModule) RemoveRelationTuple(_ context.Context, tuple RelationTuple) error {
@@ -571,7 +707,11 @@ func (m *CasbinModule) RemoveRelationTuple(_ context.Context, tuple RelationTupl
 	if _, err := m.enforcer.RemoveNamedGroupingPolicy("g2", tuple.Subject, tuple.Relation, tuple.Object); err != nil {
 		return err
 	}
-	return m.relations.Remove(tuple)
+	if err := m.relations.Remove(tuple); err != nil {
+		return err
+	}
+	m.emitChange(ChangeRelationDeleted, relationTupleToMap(tuple))
+	return nil
 }
 
 func (m *CasbinModule) ListRelationTuples(_ context.Context, filter RelationTupleFilter) ([]RelationTuple, error) {
@@ -581,15 +721,32 @@ func (m *CasbinModule) ListRelationTuples(_ context.Context, filter RelationTupl
 	return m.relations.List(filter), nil
 }
 
+// CheckRelation reports whether the tuple is stored or, with a relation
+// model, whether the model's rewrite rules grant the relation.
 func (m *CasbinModule) CheckRelation(ctx context.Context, check RelationCheck) (RelationCheckResult, error) {
 	if !m.SupportsCapability(CapabilityReBAC) {
 		return RelationCheckResult{}, errUnsupportedReBAC
 	}
-	tuples, err := m.ListRelationTuples(ctx, RelationTupleFilter{Subject: check.Subject, Relation: check.Relation, Object: check.Object, Context: check.Context})
+	tuple := normalizeRelationTuple(RelationTuple{Subject: check.Subject, Relation: check.Relation, Object: check.Object, Context: check.Context})
+	result := RelationCheckResult{Subject: tuple.Subject, Relation: tuple.Relation, Object: tuple.Object, Context: tuple.Context}
+	if err := validateRelationTuple(tuple); err != nil {
+		result.Reason = err.Error()
+		return result, nil
+	}
+	if m.relationModel != nil {
+		allowed, err := relationModelCheck(m.relationModel, m.relations.List, RelationCheck{Subject: tuple.Subject, Relation: tuple.Relation, Object: tuple.Object, Context: tuple.Context})
+		result.Allowed = allowed
+		if err != nil {
+			result.Reason = err.Error()
+		} else if !allowed {
+			result.Reason = "relation is not granted by the relation model"
+		}
+		return result, nil
+	}
+	tuples, err := m.ListRelationTuples(ctx, RelationTupleFilter{Subject: tuple.Subject, Relation: tuple.Relation, Object: tuple.Object, Context: tuple.Context})
 	if err != nil {
 		return RelationCheckResult{}, err
 	}
-	result := RelationCheckResult{Subject: check.Subject, Relation: check.Relation, Object: check.Object, Context: check.Context}
 	result.Allowed = len(tuples) > 0
 	if !result.Allowed {
 		result.Reason = "relation tuple not found"
@@ -657,6 +814,8 @@ func (m *CasbinModule) InvokeMethod(method string, input map[string]any) (map[st
 		return removeRelationTupleInvoke(ctx, m, input)
 	case "CheckRelation":
 		return checkRelationInvoke(ctx, m, input)
+	case "Simulate":
+		return simulateInvoke(ctx, m, input)
 	default:
 		return nil, fmt.Errorf("authz casbin method %q is not supported", method)
 	}
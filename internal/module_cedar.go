@@ -0,0 +1,880 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/cedar-policy/cedar-go"
+	cedarast "github.com/cedar-policy/cedar-go/ast"
+	"github.com/cedar-policy/cedar-go/types"
+	xast "github.com/cedar-policy/cedar-go/x/exp/ast"
+	"github.com/cedar-policy/cedar-go/x/exp/schema"
+	"github.com/cedar-policy/cedar-go/x/exp/schema/resolved"
+	"github.com/cedar-policy/cedar-go/x/exp/schema/validate"
+
+	"github.com/GoCodeAlone/workflow-plugin-authz/internal/contracts"
+)
+
+// Entity types the module maps RBAC and ReBAC state onto. Role assignments
+// make the principal a member of Role::"<context>:<role>", relation tuples
+// make the subject a member of Relation::"<context>:<object>#<relation>", and
+// RBAC checks without a resource are made against Context::"<context>".
+const (
+	cedarRoleType     = types.EntityType("Role")
+	cedarRelationType = types.EntityType("Relation")
+	cedarContextType  = types.EntityType("Context")
+	cedarActionType   = types.EntityType("Action")
+
+	// cedarRelationPolicyID permits a relation check when the principal is a
+	// member of the checked relation, directly or through a subject set.
+	cedarRelationPolicyID = "authz.relation"
+)
+
+// CedarModule is an AuthzProvider that evaluates AWS Cedar policies and
+// entity data in-process.
+//
+//	config:
+//	  policies: |                # Cedar policy text; policy_file reads it from disk
+//	    permit(principal, action == Action::"read", resource)
+//	    when { principal.department == resource.department };
+//	  schema: ""                 # Cedar or JSON schema; schema_file reads it from disk
+//	  validation: strict         # strict (default) or permissive schema validation
+//	  entities: []               # Cedar entity JSON; entities_file reads it from disk
+//	  principal_type: User       # entity type of bare subject ids (default User)
+//	  resource_type: Resource    # entity type of bare resource ids (default Resource)
+//	  catalog: scopes            # optional authz.scope_catalog to replay
+//
+// Subjects and resources written as Cedar entity references, such as
+// Team::"platform", are used as-is; bare ids take principal_type or
+// resource_type. Role grants become generated policies and role assignments
+// and relation tuples become entity parents, so RBAC and ReBAC checks are
+// decided by the same policy set as ABAC checks and a forbid policy applies
+// to all three.
+type CedarModule struct {
+	name      string
+	config    cedarModuleConfig
+	store     *scopeRoleStore
+	attrs     *attributePolicyStore
+	relations *relationTupleStore
+	schema    *resolved.Schema
+
+	mu       sync.RWMutex
+	base     types.EntityMap
+	user     []*cedarPolicy
+	policies *cedar.PolicySet
+	entities types.EntityMap
+}
+
+type cedarModuleConfig struct {
+	Policies      string           `yaml:"policies"`
+	PolicyFile    string           `yaml:"policy_file"`
+	Schema        string           `yaml:"schema"`
+	SchemaFile    string           `yaml:"schema_file"`
+	Validation    string           `yaml:"validation"`
+	Entities      []any            `yaml:"entities"`
+	EntitiesFile  string           `yaml:"entities_file"`
+	PrincipalType types.EntityType `yaml:"principal_type"`
+	ResourceType  types.EntityType `yaml:"resource_type"`
+	// Catalog names an authz.scope_catalog whose declarations are replayed
+	// into this provider.
+	Catalog string `yaml:"catalog"`
+}
+
+type cedarPolicy struct {
+	id     cedar.PolicyID
+	policy *cedar.Policy
+}
+
+func newCedarModule(name string, config map[string]any) (*CedarModule, error) {
+	cfg := cedarModuleConfig{
+		Policies:      stringValue(config["policies"]),
+		PolicyFile:    stringValue(firstNonNil(config["policy_file"], config["policyFile"])),
+		Schema:        stringValue(config["schema"]),
+		SchemaFile:    stringValue(firstNonNil(config["schema_file"], config["schemaFile"])),
+		Validation:    strings.ToLower(defaultString(stringValue(config["validation"]), "strict")),
+		EntitiesFile:  stringValue(firstNonNil(config["entities_file"], config["entitiesFile"])),
+		PrincipalType: types.EntityType(defaultString(stringValue(firstNonNil(config["principal_type"], config["principalType"])), "User")),
+		ResourceType:  types.EntityType(defaultString(stringValue(firstNonNil(config["resource_type"], config["resourceType"])), "Resource")),
+		Catalog:       stringValue(config["catalog"]),
+	}
+	if entities, ok := config["entities"].([]any); ok {
+		cfg.Entities = entities
+	}
+	if cfg.Policies == "" && cfg.PolicyFile == "" {
+		return nil, fmt.Errorf("authz.cedar %q: config.policies or config.policy_file is required", name)
+	}
+	if cfg.Validation != "strict" && cfg.Validation != "permissive" {
+		return nil, fmt.Errorf("authz.cedar %q: validation must be strict or permissive, got %q", name, cfg.Validation)
+	}
+	return &CedarModule{
+		name:      name,
+		config:    cfg,
+		store:     newScopeRoleStore("cedar"),
+		attrs:     newAttributePolicyStore("cedar", nil),
+		relations: newRelationTupleStore(),
+		base:      types.EntityMap{},
+	}, nil
+}
+
+// Init parses the policies, schema and entities, and validates every policy
+// and entity against the schema when one is configured, so a policy that does
+// not type-check fails the module at startup rather than on the first check.
+func (m *CedarModule) Init() error {
+	policyText, err := readInlineOrFile(m.config.Policies, m.config.PolicyFile)
+	if err != nil {
+		return fmt.Errorf("authz.cedar %q: %w", m.name, err)
+	}
+	policies, err := parseCedarPolicies(policyText)
+	if err != nil {
+		return fmt.Errorf("authz.cedar %q: parse policies: %w", m.name, err)
+	}
+
+	schemaText, err := readInlineOrFile(m.config.Schema, m.config.SchemaFile)
+	if err != nil {
+		return fmt.Errorf("authz.cedar %q: %w", m.name, err)
+	}
+	if strings.TrimSpace(schemaText) != "" {
+		m.schema, err = parseCedarSchema(schemaText)
+		if err != nil {
+			return fmt.Errorf("authz.cedar %q: schema: %w", m.name, err)
+		}
+	}
+
+	entities := types.EntityMap{}
+	if m.config.EntitiesFile != "" {
+		raw, err := os.ReadFile(m.config.EntitiesFile)
+		if err != nil {
+			return fmt.Errorf("authz.cedar %q: read %s: %w", m.name, m.config.EntitiesFile, err)
+		}
+		if err := json.Unmarshal(raw, &entities); err != nil {
+			return fmt.Errorf("authz.cedar %q: parse %s: %w", m.name, m.config.EntitiesFile, err)
+		}
+	}
+	if len(m.config.Entities) > 0 {
+		raw, err := json.Marshal(m.config.Entities)
+		if err != nil {
+			return fmt.Errorf("authz.cedar %q: entities: %w", m.name, err)
+		}
+		var inline types.EntityMap
+		if err := json.Unmarshal(raw, &inline); err != nil {
+			return fmt.Errorf("authz.cedar %q: parse entities: %w", m.name, err)
+		}
+		for uid, entity := range inline {
+			entities[uid] = entity
+		}
+	}
+
+	if m.schema != nil {
+		option := validate.WithStrict()
+		if m.config.Validation == "permissive" {
+			option = validate.WithPermissive()
+		}
+		validator := validate.New(m.schema, option)
+		var errs []error
+		for _, policy := range policies {
+			if err := validator.Policy(string(policy.id), (*xast.Policy)(policy.policy.AST())); err != nil {
+				errs = append(errs, fmt.Errorf("policy %s: %w", policy.id, err))
+			}
+		}
+		if err := validator.Entities(entities); err != nil {
+			errs = append(errs, fmt.Errorf("entities: %w", err))
+		}
+		if err := errors.Join(errs...); err != nil {
+			return fmt.Errorf("authz.cedar %q: validate: %w", m.name, err)
+		}
+	}
+
+	m.mu.Lock()
+	m.user = policies
+	m.base = entities
+	m.rebuildLocked()
+	m.mu.Unlock()
+	if err := subscribeToScopeCatalog(globalRegistry, m.config.Catalog, m); err != nil {
+		return fmt.Errorf("authz.cedar %q: %w", m.name, err)
+	}
+	return nil
+}
+
+// readInlineOrFile returns inline when set, and otherwise the contents of file.
+func readInlineOrFile(inline, file string) (string, error) {
+	if inline != "" || file == "" {
+		return inline, nil
+	}
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("read %s: %w", file, err)
+	}
+	return string(raw), nil
+}
+
+// parseCedarPolicies parses a policy document. A policy's ID is its @id
+// annotation when present and policy0, policy1, ... by position otherwise.
+func parseCedarPolicies(text string) ([]*cedarPolicy, error) {
+	list, err := cedar.NewPolicyListFromBytes("policies.cedar", []byte(text))
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*cedarPolicy, 0, len(list))
+	seen := map[cedar.PolicyID]bool{}
+	for i, policy := range list {
+		id := cedar.PolicyID(fmt.Sprintf("policy%d", i))
+		if annotated, ok := policy.Annotations()["id"]; ok && annotated != "" {
+			id = cedar.PolicyID(annotated)
+		}
+		if seen[id] || strings.HasPrefix(string(id), "authz.") {
+			return nil, fmt.Errorf("policy id %q is duplicated or reserved", id)
+		}
+		seen[id] = true
+		out = append(out, &cedarPolicy{id: id, policy: policy})
+	}
+	return out, nil
+}
+
+// parseCedarSchema parses and resolves a schema written in the Cedar schema
+// language or, when it starts with {, in Cedar's JSON schema format.
+func parseCedarSchema(text string) (*resolved.Schema, error) {
+	var s schema.Schema
+	var err error
+	if strings.HasPrefix(strings.TrimSpace(text), "{") {
+		err = s.UnmarshalJSON([]byte(text))
+	} else {
+		err = s.UnmarshalCedar([]byte(text))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.Resolve()
+}
+
+func (m *CedarModule) Start(context.Context) error { return nil }
+
+func (m *CedarModule) Stop(context.Context) error { return nil }
+
+func (m *CedarModule) Name() string { return m.name }
+
+func (m *CedarModule) Capabilities() []AuthzCapability {
+	return capabilitiesFromDescriptors(m.CapabilityDescriptors())
+}
+
+func (m *CedarModule) SupportsCapability(capability AuthzCapability) bool {
+	for _, c := range m.Capabilities() {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
+
+// CapabilityDescriptors always reports ABAC checks. Without a schema RBAC and
+// ReBAC are reported too; with one, RBAC requires principal_type to be
+// declared in Role and ReBAC requires it to be declared in Relation, since
+// strictly validated policies could not refer to those hierarchies otherwise.
+func (m *CedarModule) CapabilityDescriptors() []CapabilityDescriptor {
+	var out []CapabilityDescriptor
+	if m.supportsParentType(cedarRoleType) {
+		out = append(out, newCapabilityDescriptor(CapabilityRBAC, []AuthzOperation{OperationCheck, OperationManageRoles, OperationList}, "policy"))
+	}
+	if m.supportsParentType(cedarRelationType) {
+		out = append(out, newCapabilityDescriptor(CapabilityReBAC, []AuthzOperation{OperationCheck, OperationManageRelations, OperationList}, "policy"))
+	}
+	return append(out, newCapabilityDescriptor(CapabilityABAC, []AuthzOperation{OperationCheck}, "policy"))
+}
+
+func (m *CedarModule) supportsParentType(parent types.EntityType) bool {
+	if m.schema == nil {
+		return true
+	}
+	entity, ok := m.schema.Entities[m.config.PrincipalType]
+	if !ok {
+		return false
+	}
+	for _, candidate := range entity.ParentTypes {
+		if candidate == parent {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *CedarModule) RequireCapabilities(requirements []CapabilityRequirement) error {
+	return requireCapabilities("cedar", m.CapabilityDescriptors(), requirements)
+}
+
+func (m *CedarModule) DeclareScopes(ctx context.Context, scopes []*contracts.ScopeDeclaration) error {
+	if err := m.store.DeclareScopes(ctx, scopes); err != nil {
+		return err
+	}
+	// Declaring scopes changes what wildcard grants expand to.
+	m.rebuild()
+	return nil
+}
+
+func (m *CedarModule) UpsertRole(ctx context.Context, grant RoleScopeGrant) error {
+	if !m.SupportsCapability(CapabilityRBAC) {
+		return errUnsupportedRBAC
+	}
+	grant = normalizeRoleScopeGrant(grant)
+	if err := m.store.UpsertRole(ctx, grant); err != nil {
+		return err
+	}
+	m.rebuild()
+	publishChange(m.name, "cedar", ChangeRoleUpserted, roleScopeGrantToMap(grant))
+	return nil
+}
+
+func (m *CedarModule) AssignRole(ctx context.Context, assignment SubjectRoleAssignment) error {
+	if !m.SupportsCapability(CapabilityRBAC) {
+		return errUnsupportedRBAC
+	}
+	assignment = normalizeSubjectRoleAssignment(assignment)
+	if err := m.store.AssignRole(ctx, assignment); err != nil {
+		return err
+	}
+	m.rebuild()
+	publishChange(m.name, "cedar", ChangeRoleAssigned, subjectRoleAssignmentToMap(assignment))
+	return nil
+}
+
+func (m *CedarModule) ListAssignments(ctx context.Context, filter AssignmentFilter) ([]SubjectRoleAssignment, error) {
+	if !m.SupportsCapability(CapabilityRBAC) {
+		return nil, errUnsupportedRBAC
+	}
+	return m.store.ListAssignments(ctx, filter)
+}
+
+func (m *CedarModule) RemoveAssignment(ctx context.Context, assignment SubjectRoleAssignment) error {
+	if !m.SupportsCapability(CapabilityRBAC) {
+		return errUnsupportedRBAC
+	}
+	assignment = normalizeSubjectRoleAssignment(assignment)
+	if err := m.store.RemoveAssignment(ctx, assignment); err != nil {
+		return err
+	}
+	m.rebuild()
+	publishChange(m.name, "cedar", ChangeRoleUnassigned, subjectRoleAssignmentToMap(assignment))
+	return nil
+}
+
+// CheckScope authorizes the subject to perform Action::"<scope>" on the
+// resource, or on Context::"<context>" when the check names no resource.
+func (m *CedarModule) CheckScope(ctx context.Context, check ScopeCheck) (ScopeCheckResult, error) {
+	if !m.SupportsCapability(CapabilityRBAC) {
+		return ScopeCheckResult{}, errUnsupportedRBAC
+	}
+	granted, err := m.store.CheckScope(ctx, check)
+	if err != nil {
+		return ScopeCheckResult{}, err
+	}
+	result := ScopeCheckResult{
+		Provider:     "cedar",
+		Subject:      granted.Subject,
+		Context:      granted.Context,
+		Scope:        granted.Scope,
+		Deprecations: granted.Deprecations,
+	}
+	if result.Subject == "" || result.Context == "" || result.Scope == "" {
+		result.Reason = granted.Reason
+		return result, nil
+	}
+	resource := types.NewEntityUID(cedarContextType, types.String(result.Context))
+	if strings.TrimSpace(check.Resource) != "" {
+		resource = m.entityRef(check.Resource, m.config.ResourceType)
+	}
+	decision, diagnostic := m.authorize(cedar.Request{
+		Principal: m.entityRef(result.Subject, m.config.PrincipalType),
+		Action:    types.NewEntityUID(cedarActionType, types.String(result.Scope)),
+		Resource:  resource,
+		Context:   types.NewRecord(types.RecordMap{"context": types.String(result.Context)}),
+	}, nil)
+	result.Allowed = decision == cedar.Allow
+	result.Reason = cedarReason(decision, diagnostic)
+	if result.Allowed {
+		for _, reason := range diagnostic.Reasons {
+			if role, ok := strings.CutPrefix(string(reason.PolicyID), "authz.role:"+result.Context+":"); ok {
+				result.MatchedRole = role
+				break
+			}
+		}
+		if granted.Allowed {
+			result.MatchedScopes = granted.MatchedScopes
+		}
+	}
+	return result, nil
+}
+
+func (m *CedarModule) DeclareAttributes(ctx context.Context, attrs []*contracts.AttributeDeclaration) error {
+	return m.attrs.DeclareAttributes(ctx, attrs)
+}
+
+func (m *CedarModule) UpsertAttributePolicy(context.Context, AttributePolicy) error {
+	return fmt.Errorf("authz.cedar %q: attribute policies are written in Cedar", m.name)
+}
+
+// ListAttributePolicies returns no policies; the Cedar policy set is the
+// policy.
+func (m *CedarModule) ListAttributePolicies(context.Context, AttributePolicyFilter) ([]AttributePolicy, error) {
+	return []AttributePolicy{}, nil
+}
+
+func (m *CedarModule) RemoveAttributePolicy(context.Context, AttributePolicyFilter) error {
+	return fmt.Errorf("authz.cedar %q: attribute policies are written in Cedar", m.name)
+}
+
+// CheckAttributes authorizes the subject to perform Action::"<action>" on the
+// resource. Subject and resource attributes are set on the principal and
+// resource entities, over any attributes from the entity data, and
+// environment attributes are set on the request context alongside context.
+// Values are typed by the matching AttributeDeclaration; see cedarAttributeValue.
+func (m *CedarModule) CheckAttributes(ctx context.Context, check AttributeCheck) (AttributeCheckResult, error) {
+	result := AttributeCheckResult{
+		Subject:  strings.TrimSpace(check.Subject),
+		Context:  strings.TrimSpace(check.Context),
+		Resource: strings.TrimSpace(check.Resource),
+		Action:   strings.TrimSpace(check.Action),
+	}
+	if result.Subject == "" || result.Action == "" {
+		result.Reason = "subject and action are required"
+		return result, nil
+	}
+	declared := m.attrs.declarations()
+	principal := m.entityRef(result.Subject, m.config.PrincipalType)
+	resource := types.NewEntityUID(cedarContextType, types.String(result.Context))
+	if result.Resource != "" {
+		resource = m.entityRef(result.Resource, m.config.ResourceType)
+	}
+	environment := cedarAttributes(declared, result.Context, "environment", check.EnvironmentAttributes)
+	environment["context"] = types.String(result.Context)
+	overlay := map[types.EntityUID]types.RecordMap{
+		principal: cedarAttributes(declared, result.Context, "subject", check.SubjectAttributes),
+	}
+	if resource != principal {
+		overlay[resource] = cedarAttributes(declared, result.Context, "resource", check.ResourceAttributes)
+	}
+	decision, diagnostic := m.authorize(cedar.Request{
+		Principal: principal,
+		Action:    types.NewEntityUID(cedarActionType, types.String(result.Action)),
+		Resource:  resource,
+		Context:   types.NewRecord(environment),
+	}, overlay)
+	result.Allowed = decision == cedar.Allow
+	result.Reason = cedarReason(decision, diagnostic)
+	if len(diagnostic.Reasons) > 0 {
+		result.MatchedPolicyID = string(diagnostic.Reasons[0].PolicyID)
+	}
+	return result, nil
+}
+
+// cedarAttributes converts the attributes of one check target to Cedar values.
+func cedarAttributes(declared map[string]*contracts.AttributeDeclaration, contextName, target string, attrs map[string]string) types.RecordMap {
+	out := types.RecordMap{}
+	for name, value := range attrs {
+		dataType := ""
+		if declaration, ok := declared[attributeDeclarationKey(contextName, target, name)]; ok {
+			dataType = declaration.GetDataType()
+		}
+		out[types.String(name)] = cedarAttributeValue(dataType, value)
+	}
+	return out
+}
+
+// cedarAttributeValue converts an attribute value to the Cedar type of its
+// declared data type: int to Long, number and float to decimal, bool and
+// boolean to Bool, string_list to a Set of comma-separated Strings, and
+// anything else to String. A value that does not parse is passed as a String,
+// so policies comparing it as a number fail to match rather than erroring the
+// check.
+func cedarAttributeValue(dataType, value string) types.Value {
+	switch dataType {
+	case "int":
+		if n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64); err == nil {
+			return types.Long(n)
+		}
+	case "number", "float":
+		if d, err := types.ParseDecimal(strings.TrimSpace(value)); err == nil {
+			return d
+		}
+		if f, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+			if d, err := types.NewDecimalFromFloat(f); err == nil {
+				return d
+			}
+		}
+	case "bool", "boolean":
+		if b, err := strconv.ParseBool(strings.TrimSpace(value)); err == nil {
+			return types.Boolean(b)
+		}
+	case "string_list":
+		var items []types.Value
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, types.String(item))
+			}
+		}
+		return types.NewSet(items...)
+	}
+	return types.String(value)
+}
+
+func (m *CedarModule) DeclareRelations(context.Context, []*contracts.RelationDeclaration) error {
+	if !m.SupportsCapability(CapabilityReBAC) {
+		return errUnsupportedReBAC
+	}
+	return nil
+}
+
+func (m *CedarModule) UpsertRelationTuple(_ context.Context, tuple RelationTuple) error {
+	if !m.SupportsCapability(CapabilityReBAC) {
+		return errUnsupportedReBAC
+	}
+	tuple = normalizeRelationTuple(tuple)
+	if err := validateRelationTuple(tuple); err != nil {
+		return err
+	}
+	if err := m.relations.Upsert(tuple); err != nil {
+		return err
+	}
+	m.rebuild()
+	publishChange(m.name, "cedar", ChangeRelationWritten, relationTupleToMap(tuple))
+	return nil
+}
+
+func (m *CedarModule) RemoveRelationTuple(_ context.Context, tuple RelationTuple) error {
+	if !m.SupportsCapability(CapabilityReBAC) {
+		return errUnsupportedReBAC
+	}
+	tuple = normalizeRelationTuple(tuple)
+	if err := validateRelationTuple(tuple); err != nil {
+		return err
+	}
+	if err := m.relations.Remove(tuple); err != nil {
+		return err
+	}
+	m.rebuild()
+	publishChange(m.name, "cedar", ChangeRelationDeleted, relationTupleToMap(tuple))
+	return nil
+}
+
+func (m *CedarModule) ListRelationTuples(_ context.Context, filter RelationTupleFilter) ([]RelationTuple, error) {
+	if !m.SupportsCapability(CapabilityReBAC) {
+		return nil, errUnsupportedReBAC
+	}
+	return m.relations.List(filter), nil
+}
+
+// CheckRelation authorizes the subject to perform Action::"<relation>" on
+// the object, with the object's Relation entity in context.relation_set. The
+// generated authz.relation policy permits members of that set, and user
+// policies may permit or forbid the same request.
+func (m *CedarModule) CheckRelation(_ context.Context, check RelationCheck) (RelationCheckResult, error) {
+	if !m.SupportsCapability(CapabilityReBAC) {
+		return RelationCheckResult{}, errUnsupportedReBAC
+	}
+	tuple := normalizeRelationTuple(RelationTuple{Subject: check.Subject, Relation: check.Relation, Object: check.Object, Context: check.Context})
+	result := RelationCheckResult{Subject: tuple.Subject, Relation: tuple.Relation, Object: tuple.Object, Context: tuple.Context}
+	if err := validateRelationTuple(tuple); err != nil {
+		result.Reason = err.Error()
+		return result, nil
+	}
+	decision, diagnostic := m.authorize(cedar.Request{
+		Principal: m.relationSubjectRef(tuple.Subject),
+		Action:    types.NewEntityUID(cedarActionType, types.String(tuple.Relation)),
+		Resource:  m.entityRef(tuple.Context+":"+tuple.Object, m.config.ResourceType),
+		Context: types.NewRecord(types.RecordMap{
+			"context":      types.String(tuple.Context),
+			"relation_set": cedarRelationUID(tuple.Context, tuple.Object, tuple.Relation),
+		}),
+	}, nil)
+	result.Allowed = decision == cedar.Allow
+	if !result.Allowed {
+		result.Reason = cedarReason(decision, diagnostic)
+	}
+	return result, nil
+}
+
+func cedarRelationUID(contextName, object, relation string) types.EntityUID {
+	return types.NewEntityUID(cedarRelationType, types.String(contextName+":"+object+"#"+relation))
+}
+
+// relationSubjectRef maps a tuple subject to an entity. Subject sets, written
+// context:object#relation, are Relation entities, so holders of the subject
+// set inherit the tuple through the entity hierarchy.
+func (m *CedarModule) relationSubjectRef(subject string) types.EntityUID {
+	if !strings.Contains(subject, "::") && strings.Contains(subject, "#") {
+		return types.NewEntityUID(cedarRelationType, types.String(subject))
+	}
+	return m.entityRef(subject, m.config.PrincipalType)
+}
+
+// entityRef parses ref as a Cedar entity reference such as Team::"platform",
+// falling back to an entity of defaultType whose id is ref.
+func (m *CedarModule) entityRef(ref string, defaultType types.EntityType) types.EntityUID {
+	ref = strings.TrimSpace(ref)
+	if strings.Contains(ref, "::\"") {
+		var uid types.EntityUID
+		if err := uid.UnmarshalCedar([]byte(ref)); err == nil {
+			return uid
+		}
+	}
+	return types.NewEntityUID(defaultType, types.String(ref))
+}
+
+// authorize evaluates req against the policy set. overlay replaces the
+// attributes of the listed entities, keeping their parents.
+func (m *CedarModule) authorize(req cedar.Request, overlay map[types.EntityUID]types.RecordMap) (cedar.Decision, cedar.Diagnostic) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var entities types.EntityGetter = m.entities
+	if len(overlay) > 0 {
+		merged := make(types.EntityMap, len(overlay))
+		for uid, attrs := range overlay {
+			entity, ok := m.entities[uid]
+			if !ok {
+				entity = types.Entity{UID: uid}
+			}
+			record := entity.Attributes.Map()
+			if record == nil {
+				record = types.RecordMap{}
+			}
+			for name, value := range attrs {
+				record[name] = value
+			}
+			entity.Attributes = types.NewRecord(record)
+			merged[uid] = entity
+		}
+		entities = cedarEntityOverlay{overlay: merged, base: m.entities}
+	}
+	return m.policies.IsAuthorized(entities, req)
+}
+
+// cedarEntityOverlay looks entities up in overlay before base.
+type cedarEntityOverlay struct {
+	overlay types.EntityMap
+	base    types.EntityMap
+}
+
+func (o cedarEntityOverlay) Get(uid types.EntityUID) (types.Entity, bool) {
+	if entity, ok := o.overlay[uid]; ok {
+		return entity, true
+	}
+	return o.base.Get(uid)
+}
+
+// cedarReason explains a decision from the policies that determined it.
+func cedarReason(decision cedar.Decision, diagnostic cedar.Diagnostic) string {
+	ids := make([]string, 0, len(diagnostic.Reasons))
+	for _, reason := range diagnostic.Reasons {
+		ids = append(ids, string(reason.PolicyID))
+	}
+	var reason string
+	switch {
+	case decision == cedar.Allow:
+		reason = "permitted by " + strings.Join(ids, ", ")
+	case len(ids) > 0:
+		reason = "forbidden by " + strings.Join(ids, ", ")
+	default:
+		reason = "no permit policy applies"
+	}
+	if len(diagnostic.Errors) > 0 {
+		errs := make([]string, 0, len(diagnostic.Errors))
+		for _, err := range diagnostic.Errors {
+			errs = append(errs, fmt.Sprintf("%s: %s", err.PolicyID, err.Message))
+		}
+		reason += "; errors: " + strings.Join(errs, "; ")
+	}
+	return reason
+}
+
+func (m *CedarModule) rebuild() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rebuildLocked()
+}
+
+// rebuildLocked regenerates the policy set and entities from the user
+// policies, the entity data, role grants and assignments, and relation tuples.
+func (m *CedarModule) rebuildLocked() {
+	policies := cedar.NewPolicySet()
+	for _, policy := range m.user {
+		policies.Add(policy.id, policy.policy)
+	}
+	entities := m.base.Clone()
+	addParent := func(child, parent types.EntityUID) {
+		entity, ok := entities[child]
+		if !ok {
+			entity = types.Entity{UID: child}
+		}
+		entity.Parents = types.NewEntityUIDSet(append(entity.Parents.Slice(), parent)...)
+		entities[child] = entity
+	}
+
+	roles, assignments := m.store.grants()
+	for _, grant := range roles {
+		actions := m.cedarActions(grant.Context, grant.Scopes)
+		if len(actions) == 0 {
+			continue
+		}
+		role := types.NewEntityUID(cedarRoleType, types.String(grant.Context+":"+grant.Role))
+		policies.Add(cedar.PolicyID("authz.role:"+grant.Context+":"+grant.Role), cedar.NewPolicyFromAST(
+			cedarast.Permit().PrincipalIn(role).ActionInSet(actions...).When(cedarContextIs(grant.Context))))
+	}
+	direct := map[string][]string{}
+	for _, assignment := range assignments {
+		principal := m.entityRef(assignment.Subject, m.config.PrincipalType)
+		if assignment.Role != "" {
+			addParent(principal, types.NewEntityUID(cedarRoleType, types.String(assignment.Context+":"+assignment.Role)))
+		}
+		if len(assignment.DirectScopes) > 0 {
+			key := assignment.Context + "\x00" + assignment.Subject
+			direct[key] = append(direct[key], assignment.DirectScopes...)
+		}
+	}
+	for _, key := range sortedKeys(direct) {
+		contextName, subject, _ := strings.Cut(key, "\x00")
+		actions := m.cedarActions(contextName, direct[key])
+		if len(actions) == 0 {
+			continue
+		}
+		policies.Add(cedar.PolicyID("authz.direct:"+contextName+":"+subject), cedar.NewPolicyFromAST(
+			cedarast.Permit().PrincipalEq(m.entityRef(subject, m.config.PrincipalType)).ActionInSet(actions...).When(cedarContextIs(contextName))))
+	}
+
+	for _, tuple := range m.relations.List(RelationTupleFilter{}) {
+		addParent(m.relationSubjectRef(tuple.Subject), cedarRelationUID(tuple.Context, tuple.Object, tuple.Relation))
+	}
+	policies.Add(cedarRelationPolicyID, cedar.NewPolicyFromAST(cedarast.Permit().When(
+		cedarast.Context().Has("relation_set").And(cedarast.Principal().In(cedarast.Context().Access("relation_set"))))))
+
+	m.policies = policies
+	m.entities = entities
+}
+
+// cedarActions expands scopes, including wildcard patterns, to the actions of
+// a generated role policy.
+func (m *CedarModule) cedarActions(contextName string, scopes []string) []types.EntityUID {
+	expanded := m.store.expandScopes(contextName, scopes)
+	sort.Strings(expanded)
+	out := make([]types.EntityUID, 0, len(expanded))
+	for _, scope := range expanded {
+		out = append(out, types.NewEntityUID(cedarActionType, types.String(scope)))
+	}
+	return out
+}
+
+func cedarContextIs(contextName string) cedarast.Node {
+	return cedarast.Context().Has("context").And(cedarast.Context().Access("context").Equal(cedarast.String(contextName)))
+}
+
+// GenerateSchema renders the declared scopes and attributes of contextName,
+// or of every context when it is empty, as a Cedar schema: principal_type
+// and resource_type carry the subject and resource attributes, each declared
+// scope becomes an action, and the request context carries the environment
+// attributes. Attributes are optional, since a check may omit them.
+func (m *CedarModule) GenerateSchema(contextName string) string {
+	declared := m.attrs.declarations()
+	shape := func(target string) []string {
+		var fields []string
+		for _, key := range sortedKeys(declared) {
+			attr := declared[key]
+			if attr.GetTarget() != target || (contextName != "" && attr.GetContext() != contextName) {
+				continue
+			}
+			fields = append(fields, fmt.Sprintf("%q?: %s", attr.GetName(), cedarSchemaType(attr.GetDataType())))
+		}
+		return uniqueStrings(fields)
+	}
+	record := func(fields []string) string {
+		if len(fields) == 0 {
+			return "{}"
+		}
+		return "{\n  " + strings.Join(fields, ",\n  ") + ",\n}"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "entity %s;\n", cedarRoleType)
+	fmt.Fprintf(&b, "entity %s in [%s];\n", cedarRelationType, cedarRelationType)
+	fmt.Fprintf(&b, "entity %s;\n", cedarContextType)
+	fmt.Fprintf(&b, "entity %s in [%s, %s] %s;\n", m.config.PrincipalType, cedarRoleType, cedarRelationType, record(shape("subject")))
+	if m.config.ResourceType != m.config.PrincipalType {
+		fmt.Fprintf(&b, "entity %s %s;\n", m.config.ResourceType, record(shape("resource")))
+	}
+	contextShape := record(append([]string{`"context": String`}, shape("environment")...))
+	for _, scope := range m.store.declaredScopes() {
+		if contextName != "" && scope.GetContext() != contextName {
+			continue
+		}
+		fmt.Fprintf(&b, "action %q appliesTo {\n  principal: [%s],\n  resource: [%s, %s],\n  context: %s,\n};\n",
+			scope.GetName(), m.config.PrincipalType, m.config.ResourceType, cedarContextType, strings.ReplaceAll(contextShape, "\n", "\n  "))
+	}
+	return b.String()
+}
+
+func cedarSchemaType(dataType string) string {
+	switch dataType {
+	case "int":
+		return "Long"
+	case "number", "float":
+		return "decimal"
+	case "bool", "boolean":
+		return "Bool"
+	case "string_list":
+		return "Set<String>"
+	default:
+		return "String"
+	}
+}
+
+func (m *CedarModule) scopeRoleStore() *scopeRoleStore { return m.store }
+
+func (m *CedarModule) InvokeMethod(method string, input map[string]any) (map[string]any, error) {
+	ctx := context.Background()
+	switch method {
+	case "GetCapabilities":
+		return providerCapabilitiesInvoke(m.name, "cedar", m, input, false)
+	case "RequireCapabilities":
+		return providerCapabilitiesInvoke(m.name, "cedar", m, input, true)
+	case "DeclareAttributes":
+		return declareAttributesInvoke(ctx, m, input)
+	case "CheckAttributes":
+		return checkAttributesInvoke(ctx, m, input)
+	case "UpsertRelationTuple":
+		return upsertRelationTupleInvoke(ctx, m, input)
+	case "ListRelationTuples":
+		return listRelationTuplesInvoke(ctx, m, input)
+	case "RemoveRelationTuple":
+		return removeRelationTupleInvoke(ctx, m, input)
+	case "CheckRelation":
+		return checkRelationInvoke(ctx, m, input)
+	case "GenerateSchema":
+		contextName := stringValue(input["context"])
+		return map[string]any{"context": contextName, "schema": m.GenerateSchema(contextName)}, nil
+	default:
+		return nil, fmt.Errorf("authz cedar method %q is not supported", method)
+	}
+}
+
+func cedarModuleConfigToMap(cfg *contracts.CedarModuleConfig) map[string]any {
+	if cfg == nil {
+		return nil
+	}
+	out := compactMap(map[string]any{
+		"policies":       cfg.GetPolicies(),
+		"policy_file":    cfg.GetPolicyFile(),
+		"schema":         cfg.GetSchema(),
+		"schema_file":    cfg.GetSchemaFile(),
+		"validation":     cfg.GetValidation(),
+		"entities_file":  cfg.GetEntitiesFile(),
+		"principal_type": cfg.GetPrincipalType(),
+		"resource_type":  cfg.GetResourceType(),
+		"catalog":        cfg.GetCatalog(),
+	})
+	if entities := cfg.GetEntities(); entities != nil && len(entities.GetValues()) > 0 {
+		out["entities"] = entities.AsSlice()
+	}
+	return out
+}
@@ -0,0 +1,223 @@
+package internal
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/GoCodeAlone/workflow-plugin-authz/internal/contracts"
+)
+
+const testCedarPolicies = `@id("department-read")
+permit(principal, action == Action::"read", resource)
+when { principal has department && resource has department && principal.department == resource.department };
+
+@id("suspended")
+forbid(principal, action, resource)
+when { principal has suspended && principal.suspended };
+
+@id("clearance")
+permit(principal, action == Action::"approve", resource)
+when { principal has clearance && principal.clearance >= 3 };
+`
+
+const testCedarSchema = `entity Role;
+entity Relation in [Relation];
+entity Context;
+entity User in [Role] { department?: String, clearance?: Long, suspended?: Bool };
+entity Resource { department?: String };
+action "read", "approve" appliesTo { principal: [User], resource: [Resource] };
+`
+
+func newTestCedarModule(t *testing.T, config map[string]any) *CedarModule {
+	t.Helper()
+	module, err := newCedarModule("cedar", config)
+	if err != nil {
+		t.Fatalf("newCedarModule: %v", err)
+	}
+	if err := module.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	return module
+}
+
+func TestCedarModuleCheckAttributes(t *testing.T) {
+	ctx := context.Background()
+	module := newTestCedarModule(t, map[string]any{"policies": testCedarPolicies, "schema": testCedarSchema})
+	if err := module.DeclareAttributes(ctx, []*contracts.AttributeDeclaration{
+		{Name: "clearance", Context: "docs", Target: "subject", DataType: "int"},
+		{Name: "suspended", Context: "docs", Target: "subject", DataType: "bool"},
+	}); err != nil {
+		t.Fatalf("DeclareAttributes: %v", err)
+	}
+
+	decision, err := DecideAuthorization(ctx, module, AuthorizationDecisionInput{
+		Mode:               CapabilityABAC,
+		Subject:            "alice",
+		Context:            "docs",
+		Resource:           "budget",
+		Action:             "read",
+		SubjectAttributes:  map[string]string{"department": "finance"},
+		ResourceAttributes: map[string]string{"department": "finance"},
+	})
+	if err != nil || !decision.Allowed {
+		t.Fatalf("DecideAuthorization = %#v, %v; want allowed", decision, err)
+	}
+
+	// Declared data types make clearance a Long, so >= compares numbers.
+	result, err := module.CheckAttributes(ctx, AttributeCheck{Subject: "alice", Context: "docs", Action: "approve", SubjectAttributes: map[string]string{"clearance": "4"}})
+	if err != nil || !result.Allowed || result.MatchedPolicyID != "clearance" {
+		t.Fatalf("CheckAttributes(approve) = %#v, %v; want allowed by clearance", result, err)
+	}
+	result, _ = module.CheckAttributes(ctx, AttributeCheck{
+		Subject:           "alice",
+		Context:           "docs",
+		Action:            "approve",
+		SubjectAttributes: map[string]string{"clearance": "4", "suspended": "true"},
+	})
+	if result.Allowed || result.Reason != "forbidden by suspended" {
+		t.Fatalf("CheckAttributes(suspended) = %#v, want forbidden", result)
+	}
+	result, _ = module.CheckAttributes(ctx, AttributeCheck{Subject: "alice", Context: "docs", Action: "approve", SubjectAttributes: map[string]string{"clearance": "2"}})
+	if result.Allowed || result.Reason != "no permit policy applies" {
+		t.Fatalf("CheckAttributes(clearance 2) = %#v, want denied", result)
+	}
+	if err := module.UpsertAttributePolicy(ctx, AttributePolicy{ID: "p"}); err == nil {
+		t.Fatal("expected UpsertAttributePolicy to be rejected")
+	}
+}
+
+func TestCedarModuleCheckScope(t *testing.T) {
+	ctx := context.Background()
+	module := newTestCedarModule(t, map[string]any{
+		"policies": testCedarPolicies,
+		"entities": []any{map[string]any{
+			"uid":   map[string]any{"type": "User", "id": "mallory"},
+			"attrs": map[string]any{"suspended": true},
+		}},
+	})
+	if err := module.DeclareScopes(ctx, []*contracts.ScopeDeclaration{
+		{Name: "billing:invoice:read", Context: "billing", Resource: "invoice", Actions: []string{"read"}},
+		{Name: "billing:invoice:pay", Context: "billing", Resource: "invoice", Actions: []string{"pay"}},
+	}); err != nil {
+		t.Fatalf("DeclareScopes: %v", err)
+	}
+	if err := module.UpsertRole(ctx, RoleScopeGrant{Role: "viewer", Context: "billing", Scopes: []string{"billing:invoice:*"}}); err != nil {
+		t.Fatalf("UpsertRole: %v", err)
+	}
+	for _, subject := range []string{"alice", "mallory"} {
+		if err := module.AssignRole(ctx, SubjectRoleAssignment{Subject: subject, Role: "viewer", Context: "billing"}); err != nil {
+			t.Fatalf("AssignRole(%s): %v", subject, err)
+		}
+	}
+
+	result, err := module.CheckScope(ctx, ScopeCheck{Subject: "alice", Context: "billing", Scope: "billing:invoice:pay"})
+	if err != nil || !result.Allowed || result.MatchedRole != "viewer" || result.Provider != "cedar" {
+		t.Fatalf("CheckScope = %#v, %v; want allowed through viewer", result, err)
+	}
+	// A forbid policy over the entity data overrides the role grant.
+	if result, _ := module.CheckScope(ctx, ScopeCheck{Subject: "mallory", Context: "billing", Scope: "billing:invoice:read"}); result.Allowed || result.Reason != "forbidden by suspended" {
+		t.Fatalf("CheckScope for mallory = %#v, want forbidden", result)
+	}
+	if err := module.RemoveAssignment(ctx, SubjectRoleAssignment{Subject: "alice", Role: "viewer", Context: "billing"}); err != nil {
+		t.Fatalf("RemoveAssignment: %v", err)
+	}
+	if result, _ := module.CheckScope(ctx, ScopeCheck{Subject: "alice", Context: "billing", Scope: "billing:invoice:read"}); result.Allowed {
+		t.Fatalf("CheckScope after RemoveAssignment = %#v, want denied", result)
+	}
+}
+
+func TestCedarModuleCheckRelation(t *testing.T) {
+	ctx := context.Background()
+	module := newTestCedarModule(t, map[string]any{"policies": testCedarPolicies})
+	for _, tuple := range []RelationTuple{
+		{Subject: "alice", Relation: "viewer", Object: "folder:plans", Context: "docs"},
+		{Subject: "docs:folder:plans#viewer", Relation: "viewer", Object: "doc:roadmap", Context: "docs"},
+	} {
+		if err := module.UpsertRelationTuple(ctx, tuple); err != nil {
+			t.Fatalf("UpsertRelationTuple(%v): %v", tuple, err)
+		}
+	}
+
+	result, err := module.CheckRelation(ctx, RelationCheck{Subject: "alice", Relation: "viewer", Object: "doc:roadmap", Context: "docs"})
+	if err != nil || !result.Allowed {
+		t.Fatalf("CheckRelation through the folder = %#v, %v; want allowed", result, err)
+	}
+	if result, _ := module.CheckRelation(ctx, RelationCheck{Subject: "bob", Relation: "viewer", Object: "doc:roadmap", Context: "docs"}); result.Allowed {
+		t.Fatalf("CheckRelation for bob = %#v, want denied", result)
+	}
+	if err := module.RemoveRelationTuple(ctx, RelationTuple{Subject: "alice", Relation: "viewer", Object: "folder:plans", Context: "docs"}); err != nil {
+		t.Fatalf("RemoveRelationTuple: %v", err)
+	}
+	if result, _ := module.CheckRelation(ctx, RelationCheck{Subject: "alice", Relation: "viewer", Object: "doc:roadmap", Context: "docs"}); result.Allowed {
+		t.Fatalf("CheckRelation after RemoveRelationTuple = %#v, want denied", result)
+	}
+}
+
+func TestCedarModuleSchemaCapabilities(t *testing.T) {
+	module := newTestCedarModule(t, map[string]any{"policies": testCedarPolicies, "schema": testCedarSchema})
+	if !module.SupportsCapability(CapabilityRBAC) || !module.SupportsCapability(CapabilityABAC) {
+		t.Fatalf("Capabilities = %v, want rbac and abac", module.Capabilities())
+	}
+	if module.SupportsCapability(CapabilityReBAC) {
+		t.Fatalf("Capabilities = %v, want no rebac: User is not declared in Relation", module.Capabilities())
+	}
+	if err := module.UpsertRelationTuple(context.Background(), RelationTuple{Subject: "a", Relation: "r", Object: "o", Context: "c"}); err != errUnsupportedReBAC {
+		t.Fatalf("UpsertRelationTuple = %v, want errUnsupportedReBAC", err)
+	}
+
+	out, err := module.InvokeMethod("GenerateSchema", map[string]any{})
+	if err != nil {
+		t.Fatalf("GenerateSchema: %v", err)
+	}
+	if _, err := parseCedarSchema(stringValue(out["schema"])); err != nil {
+		t.Fatalf("generated schema does not parse: %v\n%s", err, out["schema"])
+	}
+}
+
+func TestCedarModuleValidationErrors(t *testing.T) {
+	dir := t.TempDir()
+	schemaFile := filepath.Join(dir, "schema.cedarschema")
+	if err := os.WriteFile(schemaFile, []byte(testCedarSchema), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	module, err := newCedarModule("cedar", map[string]any{
+		"policies":    `permit(principal, action == Action::"read", resource) when { principal.team == "x" };`,
+		"schema_file": schemaFile,
+	})
+	if err != nil {
+		t.Fatalf("newCedarModule: %v", err)
+	}
+	if err := module.Init(); err == nil || !strings.Contains(err.Error(), "validate") {
+		t.Fatalf("Init = %v, want a validation error for the undeclared attribute", err)
+	}
+
+	if _, err := newCedarModule("cedar", map[string]any{}); err == nil {
+		t.Fatal("expected an error without policies or policy_file")
+	}
+	if _, err := newCedarModule("cedar", map[string]any{"policies": "permit(principal, action, resource);", "validation": "loose"}); err == nil {
+		t.Fatal("expected an error for an unknown validation mode")
+	}
+}
+
+func TestCedarModuleConfigToMap(t *testing.T) {
+	entities, err := structpb.NewList([]any{map[string]any{"uid": map[string]any{"type": "User", "id": "alice"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := cedarModuleConfigToMap(&contracts.CedarModuleConfig{
+		Policies:      testCedarPolicies,
+		PrincipalType: "Employee",
+		Entities:      entities,
+	})
+	if got["policies"] != testCedarPolicies || got["principal_type"] != "Employee" || len(got["entities"].([]any)) != 1 {
+		t.Fatalf("cedarModuleConfigToMap = %#v", got)
+	}
+	if _, ok := got["validation"]; ok {
+		t.Fatalf("empty validation was mapped: %#v", got)
+	}
+}
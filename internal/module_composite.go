@@ -0,0 +1,378 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Combination rules for the decisions of an authz.composite route.
+const (
+	// combineAllMustAllow allows when every provider that answers allows.
+	combineAllMustAllow = "all_must_allow"
+	// combineAnyAllows allows when any provider allows.
+	combineAnyAllows = "any_allows"
+	// combineFirstDecisive takes the decision of the first provider that
+	// answers.
+	combineFirstDecisive = "first_decisive"
+)
+
+// compositeModule implements sdk.ModuleInstance for authz.composite. It is an
+// AuthzProvider that decides each check by routing it to the provider
+// modules of the first matching route and combining their decisions.
+//
+// Config:
+//
+//	combine: "first_decisive"     # default rule: "all_must_allow", "any_allows", or "first_decisive"
+//	fallback: ["authz-casbin"]    # default fallback modules
+//	routes:
+//	  - context: "docs"           # optional: match the check's context
+//	    mode: "rebac"             # optional: match "rbac", "abac", or "rebac"
+//	    resource_prefix: "doc:"   # optional: match the resource or object
+//	    providers: ["authz-keto"] # modules to ask, in order
+//	    combine: "any_allows"     # optional: overrides the default rule
+//	    fallback: ["authz-casbin"] # optional: overrides the default fallback
+type compositeModule struct {
+	name     string
+	routes   []compositeRoute
+	fallback []string
+	registry authzProviderRegistry
+}
+
+type compositeRoute struct {
+	Context        string
+	Mode           AuthzCapability
+	ResourcePrefix string
+	Providers      []string
+	Combine        string
+	Fallback       []string
+}
+
+func newCompositeModule(name string, config map[string]any) (*compositeModule, error) {
+	combine := defaultString(stringValue(config["combine"]), combineFirstDecisive)
+	if err := validateCompositeCombine(combine); err != nil {
+		return nil, fmt.Errorf("authz.composite %q: %w", name, err)
+	}
+	m := &compositeModule{name: name, fallback: stringSliceValue(config["fallback"]), registry: globalRegistry}
+	for i, raw := range compositeRoutesFromAny(config["routes"]) {
+		route := compositeRoute{
+			Context:        stringValue(raw["context"]),
+			Mode:           AuthzCapability(strings.ToLower(stringValue(raw["mode"]))),
+			ResourcePrefix: stringValue(firstNonNil(raw["resourcePrefix"], raw["resource_prefix"])),
+			Providers:      stringSliceValue(raw["providers"]),
+			Combine:        defaultString(stringValue(raw["combine"]), combine),
+			Fallback:       stringSliceValue(raw["fallback"]),
+		}
+		if len(route.Fallback) == 0 {
+			route.Fallback = m.fallback
+		}
+		switch {
+		case len(route.Providers) == 0:
+			return nil, fmt.Errorf("authz.composite %q: routes[%d].providers is required", name, i)
+		case route.Mode != "" && route.Mode != CapabilityRBAC && route.Mode != CapabilityABAC && route.Mode != CapabilityReBAC:
+			return nil, fmt.Errorf("authz.composite %q: routes[%d].mode must be rbac, abac, or rebac, got %q", name, i, route.Mode)
+		}
+		if err := validateCompositeCombine(route.Combine); err != nil {
+			return nil, fmt.Errorf("authz.composite %q: routes[%d]: %w", name, i, err)
+		}
+		m.routes = append(m.routes, route)
+	}
+	if len(m.routes) == 0 && len(m.fallback) == 0 {
+		return nil, fmt.Errorf("authz.composite %q: config.routes or config.fallback is required", name)
+	}
+	if containsString(m.children(), name) {
+		return nil, fmt.Errorf("authz.composite %q: cannot route to the module itself", name)
+	}
+	return m, nil
+}
+
+func validateCompositeCombine(combine string) error {
+	switch combine {
+	case combineAllMustAllow, combineAnyAllows, combineFirstDecisive:
+		return nil
+	default:
+		return fmt.Errorf("combine must be %s, %s, or %s, got %q", combineAllMustAllow, combineAnyAllows, combineFirstDecisive, combine)
+	}
+}
+
+func compositeRoutesFromAny(value any) []map[string]any {
+	return mapListValue(value)
+}
+
+// Init rejects composites that route back to themselves through other
+// composites. Child modules are otherwise looked up when a check is decided,
+// so they may be created after the composite; a cycle through one of those is
+// caught when the last composite in it initializes.
+func (m *compositeModule) Init() error {
+	if err := m.checkCycles(m.name, []string{m.name}); err != nil {
+		return fmt.Errorf("authz.composite %q: %w", m.name, err)
+	}
+	return nil
+}
+
+// checkCycles walks the composite children of the module at the end of path
+// and fails when one of them is already on it.
+func (m *compositeModule) checkCycles(name string, path []string) error {
+	provider, ok := m.registry.GetAuthzProvider(name)
+	if name == m.name {
+		provider, ok = m, true
+	}
+	composite, isComposite := provider.(*compositeModule)
+	if !ok || !isComposite {
+		return nil
+	}
+	for _, child := range composite.children() {
+		if containsString(path, child) {
+			return fmt.Errorf("routes form a cycle: %s -> %s", strings.Join(path, " -> "), child)
+		}
+		if err := m.checkCycles(child, append(path[:len(path):len(path)], child)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *compositeModule) Start(_ context.Context) error { return nil }
+
+func (m *compositeModule) Stop(_ context.Context) error { return nil }
+
+// Name returns the module name.
+func (m *compositeModule) Name() string { return m.name }
+
+// children returns every module the composite routes or falls back to.
+func (m *compositeModule) children() []string {
+	names := append([]string(nil), m.fallback...)
+	for _, route := range m.routes {
+		names = append(names, route.Providers...)
+		names = append(names, route.Fallback...)
+	}
+	return uniqueStrings(names)
+}
+
+// Decide routes input to the first matching route. When the route's
+// providers reach no decision, because none supports the mode or their
+// errors leave the rule open, its fallback modules are asked in order and
+// the first answer is used. Under all_must_allow a failing or unregistered
+// provider denies, so an outage or a typo never leaves the decision to a more
+// permissive fallback.
+func (m *compositeModule) Decide(ctx context.Context, input AuthorizationDecisionInput) (AuthorizationDecisionOutput, error) {
+	mode, err := selectDecisionMode(m, input)
+	if err != nil {
+		return AuthorizationDecisionOutput{}, fmt.Errorf("authz.composite %q: %w", m.name, err)
+	}
+	input.Mode = mode
+	route, ok := m.route(input)
+	switch {
+	case ok:
+		decision, decided, routeErr := m.evaluate(ctx, route.Combine, route.Providers, input)
+		if decided {
+			return decision, nil
+		}
+		err = routeErr
+	case len(m.fallback) > 0:
+		route.Fallback = m.fallback
+	default:
+		return AuthorizationDecisionOutput{}, fmt.Errorf("authz.composite %q: no route for mode %q in context %q on resource %q", m.name, mode, input.Context, input.Resource)
+	}
+	if len(route.Fallback) > 0 {
+		fallback, decided, fallbackErr := m.evaluate(ctx, combineFirstDecisive, route.Fallback, input)
+		if decided {
+			fallback.Reason = "fallback " + fallback.Reason
+			return fallback, nil
+		}
+		err = errors.Join(err, fallbackErr)
+	}
+	return AuthorizationDecisionOutput{}, fmt.Errorf("authz.composite %q: no provider decided: %w", m.name, err)
+}
+
+// route returns the first route matching input's mode, context and
+// resource.
+func (m *compositeModule) route(input AuthorizationDecisionInput) (compositeRoute, bool) {
+	for _, route := range m.routes {
+		if (route.Mode == "" || route.Mode == input.Mode) &&
+			(route.Context == "" || route.Context == input.Context) &&
+			strings.HasPrefix(input.Resource, route.ResourcePrefix) {
+			return route, true
+		}
+	}
+	return compositeRoute{}, false
+}
+
+// evaluate decides input against providers under combine. Providers that do
+// not support the mode are skipped. A provider that fails or is not
+// registered denies under all_must_allow. Otherwise decided is false when no
+// provider answered, or when failed providers could have changed the outcome;
+// err then says why.
+func (m *compositeModule) evaluate(ctx context.Context, combine string, providers []string, input AuthorizationDecisionInput) (AuthorizationDecisionOutput, bool, error) {
+	deny := func(name string, err error) (AuthorizationDecisionOutput, bool, error) {
+		return AuthorizationDecisionOutput{
+			Mode:    input.Mode,
+			Subject: input.Subject,
+			Context: input.Context,
+			Reason:  fmt.Sprintf("denied by %s: %v", name, err),
+		}, true, nil
+	}
+	var errs []error
+	var answered []string
+	for _, name := range providers {
+		provider, ok := m.registry.GetAuthzProvider(name)
+		if !ok {
+			err := fmt.Errorf("module %q not found", name)
+			if combine == combineAllMustAllow {
+				return deny(name, err)
+			}
+			errs = append(errs, err)
+			continue
+		}
+		if !provider.SupportsCapability(input.Mode) {
+			continue
+		}
+		decision, err := DecideAuthorization(ctx, provider, input)
+		if err != nil && combine == combineAllMustAllow {
+			return deny(name, err)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		if combine == combineFirstDecisive ||
+			(combine == combineAnyAllows && decision.Allowed) ||
+			(combine == combineAllMustAllow && !decision.Allowed) {
+			decision.Reason = compositeReason(name, decision)
+			return decision, true, nil
+		}
+		answered = append(answered, name)
+	}
+	if len(errs) > 0 {
+		return AuthorizationDecisionOutput{}, false, errors.Join(errs...)
+	}
+	if len(answered) == 0 {
+		return AuthorizationDecisionOutput{}, false, fmt.Errorf("no module of %s supports mode %q", strings.Join(providers, ", "), input.Mode)
+	}
+	// Every provider that answered denied under any_allows, or allowed under
+	// all_must_allow.
+	allowed := combine == combineAllMustAllow
+	verb := "denied"
+	if allowed {
+		verb = "allowed"
+	}
+	return AuthorizationDecisionOutput{
+		Allowed: allowed,
+		Mode:    input.Mode,
+		Subject: input.Subject,
+		Context: input.Context,
+		Reason:  fmt.Sprintf("%s by %s", verb, strings.Join(answered, ", ")),
+	}, true, nil
+}
+
+func compositeReason(name string, decision AuthorizationDecisionOutput) string {
+	verb := "denied"
+	if decision.Allowed {
+		verb = "allowed"
+	}
+	if decision.Reason == "" {
+		return fmt.Sprintf("%s by %s", verb, name)
+	}
+	return fmt.Sprintf("%s by %s: %s", verb, name, decision.Reason)
+}
+
+// Capabilities returns the authorization models at least one child module
+// can check.
+func (m *compositeModule) Capabilities() []AuthzCapability {
+	return capabilitiesFromDescriptors(m.CapabilityDescriptors())
+}
+
+// SupportsCapability reports whether a child module can check the given
+// authorization model.
+func (m *compositeModule) SupportsCapability(cap AuthzCapability) bool {
+	for _, c := range m.Capabilities() {
+		if c == cap {
+			return true
+		}
+	}
+	return false
+}
+
+// CapabilityDescriptors merges the descriptors of the child modules. A mode
+// is described when any child checks it; the composite only routes checks,
+// so check is its only operation. A mode is degraded when a child checking
+// it is, and every mode is degraded while a child module is not registered.
+func (m *compositeModule) CapabilityDescriptors() []CapabilityDescriptor {
+	health := map[AuthzCapability]string{}
+	missing := false
+	for _, name := range m.children() {
+		provider, ok := m.registry.GetAuthzProvider(name)
+		if !ok {
+			missing = true
+			continue
+		}
+		for _, descriptor := range provider.CapabilityDescriptors() {
+			if !descriptor.Configured || descriptor.UnsupportedReason != "" || !containsAuthzOperation(descriptor.Operations, OperationCheck) {
+				continue
+			}
+			if health[descriptor.Mode] == "" || descriptor.Health != "ok" {
+				health[descriptor.Mode] = descriptor.Health
+			}
+		}
+	}
+	descriptors := make([]CapabilityDescriptor, 0, len(health))
+	for _, mode := range []AuthzCapability{CapabilityRBAC, CapabilityABAC, CapabilityReBAC} {
+		if health[mode] == "" {
+			continue
+		}
+		descriptor := newCapabilityDescriptor(mode, []AuthzOperation{OperationCheck}, "composite")
+		if missing || health[mode] != "ok" {
+			descriptor.Health = "degraded"
+		}
+		descriptors = append(descriptors, descriptor)
+	}
+	return descriptors
+}
+
+func (m *compositeModule) RequireCapabilities(requirements []CapabilityRequirement) error {
+	return requireCapabilities("composite", m.CapabilityDescriptors(), requirements)
+}
+
+func containsAuthzOperation(operations []AuthzOperation, want AuthzOperation) bool {
+	for _, operation := range operations {
+		if operation == want {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *compositeModule) InvokeMethod(method string, input map[string]any) (map[string]any, error) {
+	switch method {
+	case "GetCapabilities":
+		return providerCapabilitiesInvoke(m.name, "composite", m, input, false)
+	case "RequireCapabilities":
+		return providerCapabilitiesInvoke(m.name, "composite", m, input, true)
+	case "Decide":
+		decision, err := m.Decide(context.Background(), authorizationDecisionInputFromMap(input))
+		if err != nil {
+			return nil, err
+		}
+		return authorizationDecisionOutputToMap(decision), nil
+	default:
+		return nil, fmt.Errorf("authz.composite method %q is not supported", method)
+	}
+}
+
+func authorizationDecisionInputFromMap(values map[string]any) AuthorizationDecisionInput {
+	return AuthorizationDecisionInput{
+		Provider:              stringValue(values["provider"]),
+		Mode:                  AuthzCapability(stringValue(values["mode"])),
+		Subject:               stringValue(values["subject"]),
+		Context:               stringValue(values["context"]),
+		Resource:              stringValue(values["resource"]),
+		Action:                stringValue(values["action"]),
+		Scope:                 stringValue(values["scope"]),
+		Relation:              stringValue(values["relation"]),
+		SubjectAttributes:     stringMapFromAny(values["subject_attributes"]),
+		ResourceAttributes:    stringMapFromAny(values["resource_attributes"]),
+		EnvironmentAttributes: stringMapFromAny(values["environment_attributes"]),
+		Explain:               boolValue(values["explain"]),
+	}
+}
@@ -0,0 +1,359 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/GoCodeAlone/workflow-plugin-authz/internal/contracts"
+)
+
+// compositeStub is a child provider that decides every check the same way.
+type compositeStub struct {
+	modes   []AuthzCapability
+	allowed bool
+	err     error
+	health  string
+	calls   int
+}
+
+func (p *compositeStub) Capabilities() []AuthzCapability {
+	return capabilitiesFromDescriptors(p.CapabilityDescriptors())
+}
+
+func (p *compositeStub) SupportsCapability(cap AuthzCapability) bool {
+	for _, c := range p.Capabilities() {
+		if c == cap {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *compositeStub) CapabilityDescriptors() []CapabilityDescriptor {
+	descriptors := make([]CapabilityDescriptor, 0, len(p.modes))
+	for _, mode := range p.modes {
+		descriptor := newCapabilityDescriptor(mode, []AuthzOperation{OperationCheck}, "stub")
+		if p.health != "" {
+			descriptor.Health = p.health
+		}
+		descriptors = append(descriptors, descriptor)
+	}
+	return descriptors
+}
+
+func (p *compositeStub) RequireCapabilities(requirements []CapabilityRequirement) error {
+	return requireCapabilities("stub", p.CapabilityDescriptors(), requirements)
+}
+
+func (p *compositeStub) Decide(_ context.Context, input AuthorizationDecisionInput) (AuthorizationDecisionOutput, error) {
+	p.calls++
+	if p.err != nil {
+		return AuthorizationDecisionOutput{}, p.err
+	}
+	return AuthorizationDecisionOutput{Allowed: p.allowed, Mode: input.Mode, Subject: input.Subject, Context: input.Context}, nil
+}
+
+func newTestCompositeModule(t *testing.T, providers map[string]AuthzProvider, config map[string]any) *compositeModule {
+	t.Helper()
+	m, err := newCompositeModule("composite", config)
+	if err != nil {
+		t.Fatalf("newCompositeModule: %v", err)
+	}
+	m.registry = &testCapabilityRegistry{providers: providers}
+	return m
+}
+
+func TestCompositeModuleRoutesDecisions(t *testing.T) {
+	internalTools := &compositeStub{modes: []AuthzCapability{CapabilityRBAC}, allowed: true}
+	documents := &compositeStub{modes: []AuthzCapability{CapabilityReBAC}, allowed: true}
+	customers := &compositeStub{modes: []AuthzCapability{CapabilityRBAC, CapabilityReBAC}}
+	m := newTestCompositeModule(t, map[string]AuthzProvider{
+		"casbin": internalTools,
+		"keto":   documents,
+		"permit": customers,
+	}, map[string]any{
+		"routes": []any{
+			map[string]any{"context": "internal", "providers": []any{"casbin"}},
+			map[string]any{"mode": "rebac", "resource_prefix": "doc:", "providers": []any{"keto"}},
+			map[string]any{"providers": []any{"permit"}},
+		},
+	})
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		input   AuthorizationDecisionInput
+		allowed bool
+		reason  string
+	}{
+		{AuthorizationDecisionInput{Subject: "alice", Context: "internal", Scope: "tools:deploy"}, true, "allowed by casbin"},
+		{AuthorizationDecisionInput{Subject: "alice", Context: "docs", Resource: "doc:1", Relation: "viewer"}, true, "allowed by keto"},
+		{AuthorizationDecisionInput{Subject: "alice", Context: "docs", Resource: "folder:1", Relation: "viewer"}, false, "denied by permit"},
+		{AuthorizationDecisionInput{Subject: "alice", Context: "billing", Scope: "billing:read"}, false, "denied by permit"},
+	} {
+		decision, err := m.Decide(ctx, tc.input)
+		if err != nil {
+			t.Fatalf("Decide(%+v): %v", tc.input, err)
+		}
+		if decision.Allowed != tc.allowed || decision.Reason != tc.reason {
+			t.Errorf("Decide(%+v) = %v %q, want %v %q", tc.input, decision.Allowed, decision.Reason, tc.allowed, tc.reason)
+		}
+	}
+	if internalTools.calls != 1 || documents.calls != 1 || customers.calls != 2 {
+		t.Fatalf("calls = casbin %d, keto %d, permit %d", internalTools.calls, documents.calls, customers.calls)
+	}
+
+	// DecideAuthorization hands the whole decision to the composite.
+	decision, err := DecideAuthorization(ctx, m, AuthorizationDecisionInput{Subject: "alice", Context: "internal", Scope: "tools:deploy"})
+	if err != nil || !decision.Allowed || decision.Mode != CapabilityRBAC {
+		t.Fatalf("DecideAuthorization = %+v, %v", decision, err)
+	}
+}
+
+func TestCompositeModuleCombinationRules(t *testing.T) {
+	unavailable := fmt.Errorf("keto: %w", ErrProviderUnavailable)
+	for _, tc := range []struct {
+		combine string
+		results []*compositeStub
+		allowed bool
+		decided bool
+	}{
+		{combineAllMustAllow, []*compositeStub{{allowed: true}, {allowed: true}}, true, true},
+		{combineAllMustAllow, []*compositeStub{{allowed: true}, {allowed: false}}, false, true},
+		{combineAllMustAllow, []*compositeStub{{err: unavailable}, {allowed: false}}, false, true},
+		{combineAllMustAllow, []*compositeStub{{err: unavailable}, {allowed: true}}, false, true},
+		{combineAnyAllows, []*compositeStub{{allowed: false}, {allowed: true}}, true, true},
+		{combineAnyAllows, []*compositeStub{{allowed: false}, {allowed: false}}, false, true},
+		{combineAnyAllows, []*compositeStub{{err: unavailable}, {allowed: true}}, true, true},
+		{combineAnyAllows, []*compositeStub{{err: unavailable}, {allowed: false}}, false, false},
+		{combineFirstDecisive, []*compositeStub{{allowed: false}, {allowed: true}}, false, true},
+		{combineFirstDecisive, []*compositeStub{{err: unavailable}, {allowed: true}}, true, true},
+		// A provider without the mode is skipped.
+		{combineAllMustAllow, []*compositeStub{{allowed: true}, {allowed: false, modes: []AuthzCapability{CapabilityReBAC}}}, true, true},
+	} {
+		providers := map[string]AuthzProvider{}
+		names := make([]any, 0, len(tc.results))
+		for i, stub := range tc.results {
+			if stub.modes == nil {
+				stub.modes = []AuthzCapability{CapabilityRBAC}
+			}
+			name := fmt.Sprintf("p%d", i)
+			providers[name] = stub
+			names = append(names, name)
+		}
+		m := newTestCompositeModule(t, providers, map[string]any{
+			"combine": tc.combine,
+			"routes":  []any{map[string]any{"providers": names}},
+		})
+		decision, err := m.Decide(context.Background(), AuthorizationDecisionInput{Subject: "alice", Scope: "s"})
+		if !tc.decided {
+			if !errors.Is(err, ErrProviderUnavailable) {
+				t.Errorf("%s: err = %v, want ErrProviderUnavailable", tc.combine, err)
+			}
+			continue
+		}
+		if err != nil || decision.Allowed != tc.allowed {
+			t.Errorf("%s: Decide = %+v, %v; want allowed %v", tc.combine, decision, err, tc.allowed)
+		}
+	}
+}
+
+func TestCompositeModuleFallback(t *testing.T) {
+	down := &compositeStub{modes: []AuthzCapability{CapabilityRBAC}, err: fmt.Errorf("permit: %w", ErrProviderUnavailable)}
+	local := &compositeStub{modes: []AuthzCapability{CapabilityRBAC, CapabilityReBAC}, allowed: true}
+	providers := map[string]AuthzProvider{"permit": down, "casbin": local}
+	ctx := context.Background()
+
+	m := newTestCompositeModule(t, providers, map[string]any{
+		"routes":   []any{map[string]any{"context": "customers", "providers": []any{"permit"}}},
+		"fallback": []any{"casbin"},
+	})
+	decision, err := m.Decide(ctx, AuthorizationDecisionInput{Subject: "alice", Context: "customers", Scope: "orders:read"})
+	if err != nil || !decision.Allowed || decision.Reason != "fallback allowed by casbin" {
+		t.Fatalf("Decide with a failing route = %+v, %v", decision, err)
+	}
+	// Checks no route matches go to the fallback.
+	decision, err = m.Decide(ctx, AuthorizationDecisionInput{Subject: "alice", Context: "docs", Resource: "doc:1", Relation: "viewer"})
+	if err != nil || !decision.Allowed || decision.Mode != CapabilityReBAC {
+		t.Fatalf("Decide without a route = %+v, %v", decision, err)
+	}
+
+	m = newTestCompositeModule(t, providers, map[string]any{
+		"routes": []any{map[string]any{"context": "customers", "providers": []any{"permit"}}},
+	})
+	if _, err := m.Decide(ctx, AuthorizationDecisionInput{Subject: "alice", Context: "customers", Scope: "orders:read"}); !errors.Is(err, ErrProviderUnavailable) {
+		t.Fatalf("Decide without fallback = %v, want ErrProviderUnavailable", err)
+	}
+	if _, err := m.Decide(ctx, AuthorizationDecisionInput{Subject: "alice", Context: "internal", Scope: "tools:deploy"}); err == nil || !strings.Contains(err.Error(), "no route") {
+		t.Fatalf("Decide without a route or fallback = %v", err)
+	}
+}
+
+func TestCompositeModuleMergesCapabilityDescriptors(t *testing.T) {
+	providers := map[string]AuthzProvider{
+		"casbin": &compositeStub{modes: []AuthzCapability{CapabilityRBAC}},
+		"keto":   &compositeStub{modes: []AuthzCapability{CapabilityRBAC, CapabilityReBAC}, health: "degraded"},
+	}
+	m := newTestCompositeModule(t, providers, map[string]any{
+		"routes":   []any{map[string]any{"providers": []any{"casbin"}}},
+		"fallback": []any{"keto"},
+	})
+	descriptors := m.CapabilityDescriptors()
+	if len(descriptors) != 2 || descriptors[0].Mode != CapabilityRBAC || descriptors[1].Mode != CapabilityReBAC {
+		t.Fatalf("descriptors = %+v, want rbac and rebac", descriptors)
+	}
+	for _, descriptor := range descriptors {
+		if len(descriptor.Operations) != 1 || descriptor.Operations[0] != OperationCheck || descriptor.Source != "composite" {
+			t.Fatalf("descriptor = %+v, want check from composite", descriptor)
+		}
+		if descriptor.Health != "degraded" {
+			t.Fatalf("%s health = %q, want degraded from keto", descriptor.Mode, descriptor.Health)
+		}
+	}
+	if m.SupportsCapability(CapabilityABAC) {
+		t.Fatal("composite should not support abac without an abac child")
+	}
+	if err := m.RequireCapabilities([]CapabilityRequirement{{Mode: CapabilityReBAC, Operations: []AuthzOperation{OperationManageRelations}}}); err == nil {
+		t.Fatal("composite should not advertise relation management")
+	}
+
+	providers["keto"] = &compositeStub{modes: []AuthzCapability{CapabilityReBAC}}
+	if health := providerHealth(m.CapabilityDescriptors()); health != "ok" {
+		t.Fatalf("health = %q, want ok", health)
+	}
+	delete(providers, "keto")
+	if health := providerHealth(m.CapabilityDescriptors()); health != "degraded" {
+		t.Fatalf("health with a missing child = %q, want degraded", health)
+	}
+}
+
+func TestCompositeModuleConfigValidation(t *testing.T) {
+	for name, config := range map[string]map[string]any{
+		"empty":          {},
+		"no providers":   {"routes": []any{map[string]any{"context": "docs"}}},
+		"bad combine":    {"combine": "majority", "fallback": []any{"casbin"}},
+		"bad mode":       {"routes": []any{map[string]any{"mode": "acl", "providers": []any{"casbin"}}}},
+		"routes to self": {"routes": []any{map[string]any{"providers": []any{"composite"}}}},
+	} {
+		if _, err := newCompositeModule("composite", config); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestCompositeModuleRejectsRouteCycles(t *testing.T) {
+	registry := &testCapabilityRegistry{providers: map[string]AuthzProvider{"casbin": &compositeStub{modes: []AuthzCapability{CapabilityRBAC}}}}
+	modules := map[string]map[string]any{
+		"outer":  {"routes": []any{map[string]any{"providers": []any{"middle"}}}},
+		"middle": {"routes": []any{map[string]any{"providers": []any{"casbin"}}}, "fallback": []any{"inner"}},
+		"inner":  {"fallback": []any{"outer"}},
+	}
+	for name, config := range modules {
+		m, err := newCompositeModule(name, config)
+		if err != nil {
+			t.Fatalf("newCompositeModule(%s): %v", name, err)
+		}
+		m.registry = registry
+		registry.providers[name] = m
+	}
+	err := registry.providers["outer"].(*compositeModule).Init()
+	if err == nil || !strings.Contains(err.Error(), "outer -> middle -> inner -> outer") {
+		t.Fatalf("Init with a cycle = %v", err)
+	}
+
+	registry.providers["inner"].(*compositeModule).fallback = []string{"casbin"}
+	if err := registry.providers["outer"].(*compositeModule).Init(); err != nil {
+		t.Fatalf("Init without a cycle: %v", err)
+	}
+}
+
+func TestCompositeModuleAllMustAllowDeniesOnChildError(t *testing.T) {
+	down := &compositeStub{modes: []AuthzCapability{CapabilityRBAC}, err: fmt.Errorf("permit: %w", ErrProviderUnavailable)}
+	permissive := &compositeStub{modes: []AuthzCapability{CapabilityRBAC}, allowed: true}
+	m := newTestCompositeModule(t, map[string]AuthzProvider{"permit": down, "open": permissive}, map[string]any{
+		"combine":  combineAllMustAllow,
+		"routes":   []any{map[string]any{"providers": []any{"permit", "open"}}},
+		"fallback": []any{"open"},
+	})
+	decision, err := m.Decide(context.Background(), AuthorizationDecisionInput{Subject: "alice", Scope: "orders:read"})
+	if err != nil || decision.Allowed || !strings.Contains(decision.Reason, "denied by permit") {
+		t.Fatalf("Decide with a failing child = %+v, %v; want denied by permit", decision, err)
+	}
+	if permissive.calls != 0 {
+		t.Fatalf("fallback was asked %d times after a failing child", permissive.calls)
+	}
+}
+
+func TestCompositeModuleAllMustAllowDeniesOnMissingChild(t *testing.T) {
+	permissive := &compositeStub{modes: []AuthzCapability{CapabilityRBAC}, allowed: true}
+	m := newTestCompositeModule(t, map[string]AuthzProvider{"open": permissive}, map[string]any{
+		"combine":  combineAllMustAllow,
+		"routes":   []any{map[string]any{"providers": []any{"open", "permit"}}},
+		"fallback": []any{"open"},
+	})
+	decision, err := m.Decide(context.Background(), AuthorizationDecisionInput{Subject: "alice", Scope: "orders:read"})
+	if err != nil || decision.Allowed || !strings.Contains(decision.Reason, `denied by permit: module "permit" not found`) {
+		t.Fatalf("Decide with a missing child = %+v, %v; want denied by permit", decision, err)
+	}
+	if permissive.calls != 1 {
+		t.Fatalf("open was asked %d times, want once by the route and never as fallback", permissive.calls)
+	}
+}
+
+func TestAuthzCheckStepUsesCompositeProvider(t *testing.T) {
+	ctx := context.Background()
+	docs := NewMemoryProvider("docs")
+	if err := docs.UpsertRelationTuple(ctx, RelationTuple{Subject: "alice", Relation: "viewer", Object: "doc:1", Context: "docs"}); err != nil {
+		t.Fatalf("UpsertRelationTuple: %v", err)
+	}
+	registry := &testCapabilityRegistry{providers: map[string]AuthzProvider{"docs": docs}}
+	m, err := newCompositeModule("authz", map[string]any{
+		"routes": []any{map[string]any{"context": "docs", "providers": []any{"docs"}}},
+	})
+	if err != nil {
+		t.Fatalf("newCompositeModule: %v", err)
+	}
+	m.registry = registry
+	registry.providers["authz"] = m
+
+	step, err := newAuthzDecisionStep("check", map[string]any{
+		"provider": "composite",
+		"subject":  "alice",
+		"context":  "docs",
+		"resource": "doc:1",
+		"relation": "viewer",
+	})
+	if err != nil {
+		t.Fatalf("newAuthzDecisionStep: %v", err)
+	}
+	step.registry = registry
+	result, err := step.Execute(ctx, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if result.Output["allowed"] != true || result.Output["mode"] != "rebac" {
+		t.Fatalf("output = %#v, want an allowed rebac decision", result.Output)
+	}
+}
+
+func TestCompositeModuleConfigToMap(t *testing.T) {
+	m, err := newCompositeModule("composite", compositeModuleConfigToMap(&contracts.CompositeModuleConfig{
+		Combine:  combineAnyAllows,
+		Fallback: []string{"casbin"},
+		Routes: []*contracts.CompositeRoute{{
+			Context:        "docs",
+			Mode:           contracts.AuthzMode_AUTHZ_MODE_REBAC,
+			ResourcePrefix: "doc:",
+			Providers:      []string{"keto", "permit"},
+		}},
+	}))
+	if err != nil {
+		t.Fatalf("newCompositeModule: %v", err)
+	}
+	want := compositeRoute{Context: "docs", Mode: CapabilityReBAC, ResourcePrefix: "doc:", Providers: []string{"keto", "permit"}, Combine: combineAnyAllows, Fallback: []string{"casbin"}}
+	if len(m.routes) != 1 || fmt.Sprint(m.routes[0]) != fmt.Sprint(want) {
+		t.Fatalf("routes = %+v, want %+v", m.routes, want)
+	}
+}
@@ -3,39 +3,130 @@ package internal
 import (
 	"context"
 	"fmt"
+	"log"
+	"strings"
+	"time"
 
 	"github.com/GoCodeAlone/workflow-plugin-authz/internal/contracts"
 )
 
 type KetoModule struct {
-	name     string
-	config   ketoModuleConfig
-	provider *ketoScopeProvider
+	name       string
+	config     ketoModuleConfig
+	provider   *ketoScopeProvider
+	namespaces *relationNamespaces
+
+	// polling watcher fields
+	stopCh chan struct{}
+	doneCh chan struct{}
 }
 
 type ketoModuleConfig struct {
 	ReadURL  string `yaml:"readUrl"`
 	WriteURL string `yaml:"writeUrl"`
+	// Catalog names an authz.scope_catalog whose declarations are replayed
+	// into this provider.
+	Catalog string `yaml:"catalog"`
+	// Watcher, when its type is "polling", reconciles the local state with
+	// Keto on each interval and reports drift.
+	Watcher watcherConfig `yaml:"watcher"`
+	// Transport configures timeouts, retries, and circuit breaking for
+	// requests to Keto.
+	Transport transportConfig `yaml:"transport"`
+	// OnUnavailable decides scope and relation checks while Keto is
+	// unavailable.
+	OnUnavailable unavailablePolicy `yaml:"on_unavailable"`
 }
 
 func newKetoModule(name string, config map[string]any) (*KetoModule, error) {
-	return &KetoModule{name: name, config: ketoModuleConfig{
+	cfg := ketoModuleConfig{
 		ReadURL:  stringValue(firstNonNil(config["readUrl"], config["read_url"])),
 		WriteURL: stringValue(firstNonNil(config["writeUrl"], config["write_url"])),
-	}}, nil
+		Catalog:  stringValue(config["catalog"]),
+	}
+	if watcherRaw, ok := config["watcher"].(map[string]any); ok {
+		cfg.Watcher = parseWatcherConfig(watcherRaw)
+	}
+	cfg.Transport = parseTransportConfig(mapValue(config["transport"]))
+	policy, err := parseUnavailablePolicy(stringValue(firstNonNil(config["onUnavailable"], config["on_unavailable"])))
+	if err != nil {
+		return nil, fmt.Errorf("authz.keto %q: %w", name, err)
+	}
+	if policy.Fallback == name {
+		return nil, fmt.Errorf("authz.keto %q: on_unavailable cannot fall back to the module itself", name)
+	}
+	cfg.OnUnavailable = policy
+	return &KetoModule{name: name, config: cfg, namespaces: newRelationNamespaces()}, nil
 }
 
 func (m *KetoModule) Init() error {
-	m.provider = newKetoScopeProvider(m.name, newKetoSDKClient(m.config.ReadURL, m.config.WriteURL))
+	if err := validateFallbackChain(globalRegistry, m.name, m.config.OnUnavailable); err != nil {
+		return fmt.Errorf("authz.keto %q: %w", m.name, err)
+	}
+	transport := newRemoteTransport(m.name, m.config.Transport, ketoDefaultTimeout)
+	m.provider = newKetoScopeProvider(m.name, newKetoSDKClient(m.config.ReadURL, m.config.WriteURL, transport.client()))
+	if err := subscribeToScopeCatalog(globalRegistry, m.config.Catalog, m); err != nil {
+		return fmt.Errorf("authz.keto %q: %w", m.name, err)
+	}
+	return nil
+}
+
+// Start rebuilds the local state from Keto, so checks made before any role is
+// re-upserted see grants written by earlier runs or other replicas, and
+// begins the polling watcher if watcher.type is "polling". An unreachable Keto
+// does not stop the module from starting: the failure is logged, checks follow
+// on_unavailable, and the polling watcher retries the rebuild on its next tick.
+func (m *KetoModule) Start(ctx context.Context) error {
+	if _, err := m.provider.Reconcile(ctx); err != nil {
+		log.Printf("authz.keto %q: initial reconcile failed, serving checks per on_unavailable: %v", m.name, err)
+	}
+	if strings.ToLower(m.config.Watcher.Type) != "polling" {
+		return nil
+	}
+	interval := m.config.Watcher.Interval
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	m.stopCh = make(chan struct{})
+	m.doneCh = make(chan struct{})
+	go m.pollLoop(interval)
 	return nil
 }
 
-func (m *KetoModule) Start(_ context.Context) error { return nil }
+// pollLoop reconciles with Keto on each tick and publishes a provider.drift
+// event when the local state had diverged.
+func (m *KetoModule) pollLoop(interval time.Duration) {
+	defer close(m.doneCh)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.stopCh:
+			return
+		case <-ticker.C:
+			drift, err := m.provider.Reconcile(context.Background())
+			if err == nil && !drift.empty() {
+				publishChange(m.name, "keto", ChangeProviderDrift, drift.toMap())
+			}
+		}
+	}
+}
 
-func (m *KetoModule) Stop(_ context.Context) error { return nil }
+// Stop shuts down the polling watcher if running.
+func (m *KetoModule) Stop(_ context.Context) error {
+	if m.stopCh != nil {
+		close(m.stopCh)
+		<-m.doneCh
+		m.stopCh = nil
+		m.doneCh = nil
+	}
+	return nil
+}
 
 func (m *KetoModule) Name() string { return m.name }
 
+func (m *KetoModule) onUnavailable() unavailablePolicy { return m.config.OnUnavailable }
+
 func (m *KetoModule) DeclareScopes(ctx context.Context, scopes []*contracts.ScopeDeclaration) error {
 	return m.provider.DeclareScopes(ctx, scopes)
 }
@@ -56,8 +147,24 @@ func (m *KetoModule) RemoveAssignment(ctx context.Context, assignment SubjectRol
 	return m.provider.RemoveAssignment(ctx, assignment)
 }
 
+// CheckScope checks the scope through Keto, applying on_unavailable when
+// Keto cannot be reached.
 func (m *KetoModule) CheckScope(ctx context.Context, check ScopeCheck) (ScopeCheckResult, error) {
-	return m.provider.CheckScope(ctx, check)
+	callCtx, call := withRemoteCall(ctx)
+	result, err := m.provider.CheckScope(callCtx, check)
+	return m.config.OnUnavailable.checkScope(ctx, check, result, call.unavailable(err))
+}
+
+func (m *KetoModule) scopeRoleStore() *scopeRoleStore {
+	if m.provider == nil {
+		return nil
+	}
+	return m.provider.store
+}
+
+// DeclareRelations records relation namespaces declared in the scope catalog.
+func (m *KetoModule) DeclareRelations(_ context.Context, relations []*contracts.RelationDeclaration) error {
+	return m.namespaces.declare(relations)
 }
 
 func (m *KetoModule) UpsertRelationTuple(ctx context.Context, tuple RelationTuple) error {
@@ -72,8 +179,24 @@ func (m *KetoModule) ListRelationTuples(ctx context.Context, filter RelationTupl
 	return m.provider.ListRelationTuples(ctx, filter)
 }
 
+// CheckRelation checks the relation through Keto, applying on_unavailable
+// when Keto cannot be reached.
 func (m *KetoModule) CheckRelation(ctx context.Context, check RelationCheck) (RelationCheckResult, error) {
-	return m.provider.CheckRelation(ctx, check)
+	callCtx, call := withRemoteCall(ctx)
+	result, err := m.provider.CheckRelation(callCtx, check)
+	return m.config.OnUnavailable.checkRelation(ctx, check, result, call.unavailable(err))
+}
+
+// ExpandRelation returns the subjects holding a relation, following subject
+// sets in Keto.
+func (m *KetoModule) ExpandRelation(ctx context.Context, expand RelationExpand) (UsersetTree, error) {
+	return m.provider.ExpandRelation(ctx, expand)
+}
+
+// GenerateNamespaces renders the Keto OPL namespace file for the role and
+// scope model and the relations declared in the scope catalog.
+func (m *KetoModule) GenerateNamespaces() (string, error) {
+	return generateKetoNamespaces(m.namespaces.list())
 }
 
 func (m *KetoModule) InvokeMethod(method string, input map[string]any) (map[string]any, error) {
@@ -90,6 +213,16 @@ func (m *KetoModule) InvokeMethod(method string, input map[string]any) (map[stri
 		return removeRelationTupleInvoke(context.Background(), m, input)
 	case "CheckRelation":
 		return checkRelationInvoke(context.Background(), m, input)
+	case "ExpandRelation":
+		return expandRelationInvoke(context.Background(), m, input)
+	case "GenerateNamespaces":
+		opl, err := m.GenerateNamespaces()
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"opl": opl, "namespaces": append([]string(nil), ketoNamespaceNames...)}, nil
+	case "Simulate":
+		return simulateInvoke(context.Background(), m, input)
 	default:
 		return nil, fmt.Errorf("authz keto method %q is not supported", method)
 	}
@@ -99,8 +232,17 @@ func ketoModuleConfigToMap(cfg *contracts.KetoModuleConfig) map[string]any {
 	if cfg == nil {
 		return nil
 	}
-	return compactMap(map[string]any{
-		"readUrl":  cfg.GetReadUrl(),
-		"writeUrl": cfg.GetWriteUrl(),
+	out := compactMap(map[string]any{
+		"readUrl":       cfg.GetReadUrl(),
+		"writeUrl":      cfg.GetWriteUrl(),
+		"catalog":       cfg.GetCatalog(),
+		"onUnavailable": cfg.GetOnUnavailable(),
 	})
+	if watcher := cfg.GetWatcher(); watcher != nil {
+		out["watcher"] = watcherConfigToMap(watcher)
+	}
+	if transport := cfg.GetTransport(); transport != nil {
+		out["transport"] = transportConfigToMap(transport)
+	}
+	return out
 }
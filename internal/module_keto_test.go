@@ -0,0 +1,200 @@
+package internal_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/GoCodeAlone/workflow-plugin-authz/authztest"
+	authzplugin "github.com/GoCodeAlone/workflow-plugin-authz/internal"
+	"github.com/GoCodeAlone/workflow-plugin-authz/internal/contracts"
+)
+
+var ketoModuleSeq atomic.Int64
+
+// invoker is implemented by modules that serve InvokeMethod calls.
+type invoker interface {
+	InvokeMethod(method string, input map[string]any) (map[string]any, error)
+}
+
+// newKetoModule creates and initializes a uniquely named authz.keto module.
+func newKetoModule(t *testing.T, config map[string]any) authzplugin.ModuleInstance {
+	t.Helper()
+	module, err := authzplugin.NewModule("authz.keto", fmt.Sprintf("keto-module-test-%d", ketoModuleSeq.Add(1)), config)
+	if err != nil {
+		t.Fatalf("NewModule(authz.keto): %v", err)
+	}
+	if err := module.Init(); err != nil {
+		t.Fatalf("Init(authz.keto): %v", err)
+	}
+	return module
+}
+
+func TestKetoModuleRebuildsStateOnStart(t *testing.T) {
+	server := authztest.NewKetoServer(t)
+	config := map[string]any{"read_url": server.URL, "write_url": server.URL}
+	ctx := t.Context()
+	scopes := []*contracts.ScopeDeclaration{{Name: "billing:invoice:read"}}
+
+	first := newKetoModule(t, config)
+	roles := first.(authzplugin.ScopeRoleProvider)
+	if err := roles.DeclareScopes(ctx, scopes); err != nil {
+		t.Fatalf("DeclareScopes: %v", err)
+	}
+	if err := roles.UpsertRole(ctx, authzplugin.RoleScopeGrant{Role: "viewer", Context: "billing", Scopes: []string{"billing:invoice:read"}}); err != nil {
+		t.Fatalf("UpsertRole: %v", err)
+	}
+	if err := roles.AssignRole(ctx, authzplugin.SubjectRoleAssignment{Subject: "alice", Role: "viewer", Context: "billing"}); err != nil {
+		t.Fatalf("AssignRole: %v", err)
+	}
+	// More tuples than one page, so the rebuild has to follow pagination.
+	relations := first.(authzplugin.RelationshipProvider)
+	for i := range 600 {
+		tuple := authzplugin.RelationTuple{Subject: "alice", Relation: "owner", Object: fmt.Sprintf("doc-%d", i), Context: "docs"}
+		if err := relations.UpsertRelationTuple(ctx, tuple); err != nil {
+			t.Fatalf("UpsertRelationTuple: %v", err)
+		}
+	}
+
+	// A restarted process, or a second replica, starts with empty local state.
+	second := newKetoModule(t, config)
+	if err := second.(authzplugin.ScopeRoleProvider).DeclareScopes(ctx, scopes); err != nil {
+		t.Fatalf("DeclareScopes: %v", err)
+	}
+	if err := second.Start(ctx); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	t.Cleanup(func() { _ = second.Stop(context.Background()) })
+	result, err := second.(authzplugin.ScopeRoleProvider).CheckScope(ctx, authzplugin.ScopeCheck{Subject: "alice", Context: "billing", Scope: "billing:invoice:read"})
+	if err != nil || !result.Allowed {
+		t.Fatalf("CheckScope after restart = %+v, %v; want allowed", result, err)
+	}
+	tuples, err := second.(authzplugin.RelationshipProvider).ListRelationTuples(ctx, authzplugin.RelationTupleFilter{Context: "docs"})
+	if err != nil || len(tuples) != 600 {
+		t.Fatalf("ListRelationTuples after restart = %d tuples, %v; want 600", len(tuples), err)
+	}
+}
+
+func TestKetoModuleReportsDrift(t *testing.T) {
+	server := authztest.NewKetoServer(t)
+	config := map[string]any{"read_url": server.URL, "write_url": server.URL}
+	ctx := t.Context()
+	watched := newKetoModule(t, map[string]any{
+		"read_url":  server.URL,
+		"write_url": server.URL,
+		"watcher":   map[string]any{"type": "polling", "interval": "10ms"},
+	})
+	if err := watched.Start(ctx); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	t.Cleanup(func() { _ = watched.Stop(context.Background()) })
+	_, events, cancel, _ := authzplugin.SubscribeChangeEvents(0)
+	defer cancel()
+
+	replica := newKetoModule(t, config).(authzplugin.RelationshipProvider)
+	if err := replica.UpsertRelationTuple(ctx, authzplugin.RelationTuple{Subject: "bob", Relation: "viewer", Object: "doc-1", Context: "docs"}); err != nil {
+		t.Fatalf("UpsertRelationTuple: %v", err)
+	}
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case event := <-events:
+			if event.Type != authzplugin.ChangeProviderDrift || event.Source != watched.(authzplugin.RelationshipProvider).Name() {
+				continue
+			}
+			if unexpected, _ := event.Data["unexpected"].([]string); len(unexpected) != 1 || unexpected[0] != "resource:docs:doc-1#viewer@bob" {
+				t.Fatalf("drift event = %+v", event.Data)
+			}
+			return
+		case <-timeout:
+			t.Fatal("no provider.drift event was published")
+		}
+	}
+}
+
+func TestKetoModuleChecksAndExpandsSubjectSets(t *testing.T) {
+	server := authztest.NewKetoServer(t)
+	module := newKetoModule(t, map[string]any{"read_url": server.URL, "write_url": server.URL})
+	relations := module.(authzplugin.RelationshipProvider)
+	ctx := t.Context()
+	for _, tuple := range []authzplugin.RelationTuple{
+		{Subject: "alice", Relation: "member", Object: "eng", Context: "groups"},
+		{Subject: "groups:eng#member", Relation: "viewer", Object: "doc-1", Context: "docs"},
+		{Subject: "bob", Relation: "viewer", Object: "doc-1", Context: "docs"},
+	} {
+		if err := relations.UpsertRelationTuple(ctx, tuple); err != nil {
+			t.Fatalf("UpsertRelationTuple: %v", err)
+		}
+	}
+	if got := server.Tuples()[1]; got != "resource:docs:doc-1#viewer@resource:groups:eng#member" {
+		t.Fatalf("subject set tuple = %q", got)
+	}
+
+	for _, subject := range []string{"alice", "groups:eng#member"} {
+		result, err := relations.CheckRelation(ctx, authzplugin.RelationCheck{Subject: subject, Relation: "viewer", Object: "doc-1", Context: "docs"})
+		if err != nil || !result.Allowed {
+			t.Fatalf("CheckRelation(%s) = %+v, %v; want allowed", subject, result, err)
+		}
+	}
+	result, err := relations.CheckRelation(ctx, authzplugin.RelationCheck{Subject: "groups:ops#member", Relation: "viewer", Object: "doc-1", Context: "docs"})
+	if err != nil || result.Allowed {
+		t.Fatalf("CheckRelation(groups:ops#member) = %+v, %v; want denied", result, err)
+	}
+	tuples, err := relations.ListRelationTuples(ctx, authzplugin.RelationTupleFilter{Subject: "groups:eng#member"})
+	if err != nil || len(tuples) != 1 || tuples[0].Object != "doc-1" {
+		t.Fatalf("ListRelationTuples by subject set = %+v, %v", tuples, err)
+	}
+
+	tree, err := module.(authzplugin.RelationExpander).ExpandRelation(ctx, authzplugin.RelationExpand{Relation: "viewer", Object: "doc-1", Context: "docs"})
+	if err != nil {
+		t.Fatalf("ExpandRelation: %v", err)
+	}
+	want := authzplugin.UsersetTree{Type: "union", Subject: "docs:doc-1#viewer", Children: []authzplugin.UsersetTree{
+		{Type: "union", Subject: "groups:eng#member", Children: []authzplugin.UsersetTree{{Type: "leaf", Subject: "alice"}}},
+		{Type: "leaf", Subject: "bob"},
+	}}
+	if fmt.Sprint(tree) != fmt.Sprint(want) {
+		t.Fatalf("tree = %+v, want %+v", tree, want)
+	}
+	out, err := module.(invoker).InvokeMethod("ExpandRelation", map[string]any{"relation": "viewer", "object": "doc-1", "context": "docs", "max_depth": 1})
+	if err != nil {
+		t.Fatalf("InvokeMethod(ExpandRelation): %v", err)
+	}
+	children, _ := out["tree"].(map[string]any)["children"].([]map[string]any)
+	if len(children) != 2 || children[0]["type"] != "leaf" || children[0]["subject"] != "groups:eng#member" {
+		t.Fatalf("depth-limited tree = %+v", out["tree"])
+	}
+}
+
+func TestKetoModuleGeneratesNamespacesFromCatalog(t *testing.T) {
+	server := authztest.NewKetoServer(t)
+	const catalogName = "authztest-namespaces-catalog"
+	catalog, err := authzplugin.NewModule("authz.scope_catalog", catalogName, map[string]any{
+		"declarations": map[string]any{
+			"relations": []any{
+				map[string]any{"name": "viewer", "context": "docs", "object_type": "document", "subject_type": "group#member"},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewModule(authz.scope_catalog): %v", err)
+	}
+	if err := catalog.Init(); err != nil {
+		t.Fatalf("Init(authz.scope_catalog): %v", err)
+	}
+	module := newKetoModule(t, map[string]any{"read_url": server.URL, "write_url": server.URL, "catalog": catalogName})
+	out, err := module.(invoker).InvokeMethod("GenerateNamespaces", map[string]any{})
+	if err != nil {
+		t.Fatalf("InvokeMethod(GenerateNamespaces): %v", err)
+	}
+	opl, _ := out["opl"].(string)
+	if !strings.Contains(opl, `viewer: SubjectSet<resource, "member">[]`) {
+		t.Fatalf("OPL does not declare the catalog relation:\n%s", opl)
+	}
+	if namespaces, _ := out["namespaces"].([]string); fmt.Sprint(namespaces) != "[User role scope resource]" {
+		t.Fatalf("namespaces = %v", out["namespaces"])
+	}
+}
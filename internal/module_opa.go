@@ -0,0 +1,429 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/loader"
+	"github.com/open-policy-agent/opa/v1/rego"
+	"github.com/open-policy-agent/opa/v1/storage"
+	"github.com/open-policy-agent/opa/v1/storage/inmem"
+
+	"github.com/GoCodeAlone/workflow-plugin-authz/internal/contracts"
+)
+
+// opaDefaultQuery is the rule evaluated for every check unless query is set.
+const opaDefaultQuery = "data.authz.allow"
+
+// OPAModule is an AuthzProvider that decides RBAC scope checks and ABAC
+// attribute checks by evaluating Rego policies in-process.
+//
+//	config:
+//	  query: data.authz.allow   # rule deciding every check (default)
+//	  policy_dir: ./policies    # *.rego modules and data.json/data.yaml documents
+//	  modules:                  # inline Rego modules keyed by file name
+//	    authz.rego: |
+//	      package authz
+//	      allow if input.granted
+//	  data: {}                  # base document, merged over policy_dir data
+//	  catalog: scopes           # optional authz.scope_catalog to replay
+//
+// Each check evaluates query with an input document describing it; see
+// opaScopeInput and opaAttributeInput. The rule may yield a boolean or an
+// object with allow and an optional reason. Role grants and assignments are
+// kept in the module and passed in as input.roles and input.granted, so
+// policies can build on them or ignore them.
+type OPAModule struct {
+	name   string
+	config opaModuleConfig
+	store  *scopeRoleStore
+	attrs  *attributePolicyStore
+	data   storage.Store
+	query  rego.PreparedEvalQuery
+}
+
+type opaModuleConfig struct {
+	Modules   map[string]string `yaml:"modules"`
+	PolicyDir string            `yaml:"policy_dir"`
+	Data      map[string]any    `yaml:"data"`
+	Query     string            `yaml:"query"`
+	// Catalog names an authz.scope_catalog whose declarations are replayed
+	// into this provider.
+	Catalog string `yaml:"catalog"`
+}
+
+func newOPAModule(name string, config map[string]any) (*OPAModule, error) {
+	cfg := opaModuleConfig{
+		Modules:   map[string]string{},
+		PolicyDir: stringValue(firstNonNil(config["policy_dir"], config["policyDir"])),
+		Data:      mapValue(config["data"]),
+		Query:     defaultString(stringValue(config["query"]), opaDefaultQuery),
+		Catalog:   stringValue(config["catalog"]),
+	}
+	for file, source := range mapValue(config["modules"]) {
+		cfg.Modules[file] = stringValue(source)
+	}
+	if len(cfg.Modules) == 0 && cfg.PolicyDir == "" {
+		return nil, fmt.Errorf("authz.opa %q: config.modules or config.policy_dir is required", name)
+	}
+	if _, err := ast.ParseBody(cfg.Query); err != nil {
+		return nil, fmt.Errorf("authz.opa %q: query: %w", name, err)
+	}
+	return &OPAModule{
+		name:   name,
+		config: cfg,
+		store:  newScopeRoleStore("opa"),
+		attrs:  newAttributePolicyStore("opa", nil),
+	}, nil
+}
+
+// Init loads and compiles the policies and data documents, so a policy that
+// does not compile fails the module at startup rather than on the first check.
+func (m *OPAModule) Init() error {
+	modules := map[string]string{}
+	data := map[string]any{}
+	if m.config.PolicyDir != "" {
+		loaded, err := loader.NewFileLoader().All([]string{m.config.PolicyDir})
+		if err != nil {
+			return fmt.Errorf("authz.opa %q: load %s: %w", m.name, m.config.PolicyDir, err)
+		}
+		for file, module := range loaded.Modules {
+			modules[file] = string(module.Raw)
+		}
+		data = loaded.Documents
+	}
+	for file, source := range m.config.Modules {
+		modules[file] = source
+	}
+	for key, value := range m.config.Data {
+		data[key] = value
+	}
+	m.data = inmem.NewFromObject(data)
+
+	options := []func(*rego.Rego){rego.Query(m.config.Query), rego.Store(m.data)}
+	for _, file := range sortedKeys(modules) {
+		options = append(options, rego.Module(file, modules[file]))
+	}
+	query, err := rego.New(options...).PrepareForEval(context.Background())
+	if err != nil {
+		return fmt.Errorf("authz.opa %q: compile: %w", m.name, err)
+	}
+	m.query = query
+	if err := subscribeToScopeCatalog(globalRegistry, m.config.Catalog, m); err != nil {
+		return fmt.Errorf("authz.opa %q: %w", m.name, err)
+	}
+	return nil
+}
+
+func (m *OPAModule) Start(context.Context) error { return nil }
+
+func (m *OPAModule) Stop(context.Context) error { return nil }
+
+func (m *OPAModule) Name() string { return m.name }
+
+func (m *OPAModule) Capabilities() []AuthzCapability {
+	return capabilitiesFromDescriptors(m.CapabilityDescriptors())
+}
+
+func (m *OPAModule) SupportsCapability(capability AuthzCapability) bool {
+	for _, c := range m.Capabilities() {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
+
+// CapabilityDescriptors reports RBAC with role management, since grants are
+// held by the module, and ABAC checks only: attribute policies are written in
+// Rego rather than through UpsertAttributePolicy.
+func (m *OPAModule) CapabilityDescriptors() []CapabilityDescriptor {
+	return []CapabilityDescriptor{
+		newCapabilityDescriptor(CapabilityRBAC, []AuthzOperation{OperationCheck, OperationManageRoles, OperationList}, "policy"),
+		newCapabilityDescriptor(CapabilityABAC, []AuthzOperation{OperationCheck}, "policy"),
+	}
+}
+
+func (m *OPAModule) RequireCapabilities(requirements []CapabilityRequirement) error {
+	return requireCapabilities("opa", m.CapabilityDescriptors(), requirements)
+}
+
+func (m *OPAModule) DeclareScopes(ctx context.Context, scopes []*contracts.ScopeDeclaration) error {
+	return m.store.DeclareScopes(ctx, scopes)
+}
+
+func (m *OPAModule) UpsertRole(ctx context.Context, grant RoleScopeGrant) error {
+	grant = normalizeRoleScopeGrant(grant)
+	if err := m.store.UpsertRole(ctx, grant); err != nil {
+		return err
+	}
+	publishChange(m.name, "opa", ChangeRoleUpserted, roleScopeGrantToMap(grant))
+	return nil
+}
+
+func (m *OPAModule) AssignRole(ctx context.Context, assignment SubjectRoleAssignment) error {
+	assignment = normalizeSubjectRoleAssignment(assignment)
+	if err := m.store.AssignRole(ctx, assignment); err != nil {
+		return err
+	}
+	publishChange(m.name, "opa", ChangeRoleAssigned, subjectRoleAssignmentToMap(assignment))
+	return nil
+}
+
+func (m *OPAModule) ListAssignments(ctx context.Context, filter AssignmentFilter) ([]SubjectRoleAssignment, error) {
+	return m.store.ListAssignments(ctx, filter)
+}
+
+func (m *OPAModule) RemoveAssignment(ctx context.Context, assignment SubjectRoleAssignment) error {
+	assignment = normalizeSubjectRoleAssignment(assignment)
+	if err := m.store.RemoveAssignment(ctx, assignment); err != nil {
+		return err
+	}
+	publishChange(m.name, "opa", ChangeRoleUnassigned, subjectRoleAssignmentToMap(assignment))
+	return nil
+}
+
+// CheckScope evaluates the policy with an opaScopeInput. The module's own
+// grant check is passed in as input.granted rather than deciding the check.
+func (m *OPAModule) CheckScope(ctx context.Context, check ScopeCheck) (ScopeCheckResult, error) {
+	granted, err := m.store.CheckScope(ctx, check)
+	if err != nil {
+		return ScopeCheckResult{}, err
+	}
+	roles := []string{}
+	if granted.Subject != "" && granted.Context != "" {
+		assignments, err := m.store.ListAssignments(ctx, AssignmentFilter{Subject: granted.Subject, Context: granted.Context})
+		if err != nil {
+			return ScopeCheckResult{}, err
+		}
+		for _, assignment := range assignments {
+			if assignment.Role != "" {
+				roles = append(roles, assignment.Role)
+			}
+		}
+	}
+	allowed, reason, err := m.decide(ctx, opaScopeInput(check, granted, uniqueStrings(roles)))
+	if err != nil {
+		return ScopeCheckResult{}, err
+	}
+	result := ScopeCheckResult{
+		Allowed:      allowed,
+		Provider:     "opa",
+		Subject:      granted.Subject,
+		Context:      granted.Context,
+		Scope:        granted.Scope,
+		Reason:       reason,
+		Deprecations: granted.Deprecations,
+	}
+	if allowed && granted.Allowed {
+		result.MatchedRole = granted.MatchedRole
+		result.MatchedScopes = granted.MatchedScopes
+	}
+	return result, nil
+}
+
+// opaScopeInput is the input document of an RBAC check. granted and
+// matched_role report whether the module's role grants cover the scope.
+func opaScopeInput(check ScopeCheck, granted ScopeCheckResult, roles []string) map[string]any {
+	return map[string]any{
+		"mode":         string(CapabilityRBAC),
+		"subject":      granted.Subject,
+		"context":      granted.Context,
+		"scope":        granted.Scope,
+		"resource":     check.Resource,
+		"action":       check.Action,
+		"roles":        stringsToAny(roles),
+		"granted":      granted.Allowed,
+		"matched_role": granted.MatchedRole,
+	}
+}
+
+func (m *OPAModule) DeclareAttributes(ctx context.Context, attrs []*contracts.AttributeDeclaration) error {
+	return m.attrs.DeclareAttributes(ctx, attrs)
+}
+
+func (m *OPAModule) UpsertAttributePolicy(context.Context, AttributePolicy) error {
+	return fmt.Errorf("authz.opa %q: attribute policies are written in Rego", m.name)
+}
+
+// ListAttributePolicies returns no policies; the Rego modules are the policy.
+func (m *OPAModule) ListAttributePolicies(context.Context, AttributePolicyFilter) ([]AttributePolicy, error) {
+	return []AttributePolicy{}, nil
+}
+
+func (m *OPAModule) RemoveAttributePolicy(context.Context, AttributePolicyFilter) error {
+	return fmt.Errorf("authz.opa %q: attribute policies are written in Rego", m.name)
+}
+
+// CheckAttributes evaluates the policy with an opaAttributeInput.
+func (m *OPAModule) CheckAttributes(ctx context.Context, check AttributeCheck) (AttributeCheckResult, error) {
+	allowed, reason, err := m.decide(ctx, opaAttributeInput(check))
+	if err != nil {
+		return AttributeCheckResult{}, err
+	}
+	return AttributeCheckResult{
+		Allowed:  allowed,
+		Subject:  check.Subject,
+		Context:  check.Context,
+		Resource: check.Resource,
+		Action:   check.Action,
+		Reason:   reason,
+	}, nil
+}
+
+// opaAttributeInput is the input document of an ABAC check.
+func opaAttributeInput(check AttributeCheck) map[string]any {
+	return map[string]any{
+		"mode":                   string(CapabilityABAC),
+		"subject":                check.Subject,
+		"context":                check.Context,
+		"resource":               check.Resource,
+		"action":                 check.Action,
+		"subject_attributes":     stringMapToAnyMap(check.SubjectAttributes),
+		"resource_attributes":    stringMapToAnyMap(check.ResourceAttributes),
+		"environment_attributes": stringMapToAnyMap(check.EnvironmentAttributes),
+	}
+}
+
+// decide evaluates the query against input. An undefined result denies.
+func (m *OPAModule) decide(ctx context.Context, input map[string]any) (bool, string, error) {
+	results, err := m.query.Eval(ctx, rego.EvalInput(input))
+	if err != nil {
+		return false, "", fmt.Errorf("authz.opa %q: evaluate %s: %w", m.name, m.config.Query, err)
+	}
+	if len(results) == 0 || len(results[0].Expressions) == 0 {
+		return false, fmt.Sprintf("%s is undefined", m.config.Query), nil
+	}
+	switch value := results[0].Expressions[0].Value.(type) {
+	case bool:
+		if value {
+			return true, "allowed by " + m.config.Query, nil
+		}
+		return false, "denied by " + m.config.Query, nil
+	case map[string]any:
+		allowed, ok := value["allow"].(bool)
+		if !ok {
+			return false, "", fmt.Errorf("authz.opa %q: %s returned an object without a boolean allow", m.name, m.config.Query)
+		}
+		reason := stringValue(value["reason"])
+		if reason == "" && allowed {
+			reason = "allowed by " + m.config.Query
+		} else if reason == "" {
+			reason = "denied by " + m.config.Query
+		}
+		return allowed, reason, nil
+	default:
+		return false, "", fmt.Errorf("authz.opa %q: %s returned %T, want a boolean or an object with allow", m.name, m.config.Query, value)
+	}
+}
+
+// PutData writes value at path, a slash-separated path under data such as
+// /tenants/acme, creating missing parent documents.
+func (m *OPAModule) PutData(ctx context.Context, path string, value any) error {
+	parsed, err := parseOPADataPath(path)
+	if err != nil {
+		return err
+	}
+	err = storage.Txn(ctx, m.data, storage.WriteParams, func(txn storage.Transaction) error {
+		if len(parsed) == 0 {
+			if _, ok := value.(map[string]any); !ok {
+				return fmt.Errorf("the root data document must be an object")
+			}
+			return m.data.Write(ctx, txn, storage.ReplaceOp, parsed, value)
+		}
+		if err := storage.MakeDir(ctx, m.data, txn, parsed[:len(parsed)-1]); err != nil {
+			return err
+		}
+		return m.data.Write(ctx, txn, storage.AddOp, parsed, value)
+	})
+	if err != nil {
+		return fmt.Errorf("authz.opa %q: put %s: %w", m.name, path, err)
+	}
+	publishChange(m.name, "opa", ChangeDataUpdated, map[string]any{"path": path})
+	return nil
+}
+
+// RemoveData deletes the document at path. Removing a missing document is not
+// an error.
+func (m *OPAModule) RemoveData(ctx context.Context, path string) error {
+	parsed, err := parseOPADataPath(path)
+	if err != nil {
+		return err
+	}
+	if len(parsed) == 0 {
+		return fmt.Errorf("authz.opa %q: cannot remove the root data document", m.name)
+	}
+	err = storage.WriteOne(ctx, m.data, storage.RemoveOp, parsed, nil)
+	if err != nil && !storage.IsNotFound(err) {
+		return fmt.Errorf("authz.opa %q: remove %s: %w", m.name, path, err)
+	}
+	publishChange(m.name, "opa", ChangeDataUpdated, map[string]any{"path": path, "removed": true})
+	return nil
+}
+
+func parseOPADataPath(path string) (storage.Path, error) {
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	parsed, ok := storage.ParsePathEscaped(strings.TrimSuffix(path, "/"))
+	if !ok {
+		if path == "/" {
+			return storage.Path{}, nil
+		}
+		return nil, fmt.Errorf("data path %q is not valid", path)
+	}
+	return parsed, nil
+}
+
+func (m *OPAModule) scopeRoleStore() *scopeRoleStore { return m.store }
+
+func (m *OPAModule) InvokeMethod(method string, input map[string]any) (map[string]any, error) {
+	switch method {
+	case "GetCapabilities":
+		return providerCapabilitiesInvoke(m.name, "opa", m, input, false)
+	case "RequireCapabilities":
+		return providerCapabilitiesInvoke(m.name, "opa", m, input, true)
+	case "DeclareAttributes":
+		return declareAttributesInvoke(context.Background(), m, input)
+	case "CheckAttributes":
+		return checkAttributesInvoke(context.Background(), m, input)
+	case "PutData":
+		path := stringValue(input["path"])
+		if err := m.PutData(context.Background(), path, input["value"]); err != nil {
+			return nil, err
+		}
+		return map[string]any{"path": path}, nil
+	case "RemoveData":
+		path := stringValue(input["path"])
+		if err := m.RemoveData(context.Background(), path); err != nil {
+			return nil, err
+		}
+		return map[string]any{"path": path, "removed": true}, nil
+	default:
+		return nil, fmt.Errorf("authz opa method %q is not supported", method)
+	}
+}
+
+func opaModuleConfigToMap(cfg *contracts.OPAModuleConfig) map[string]any {
+	if cfg == nil {
+		return nil
+	}
+	out := compactMap(map[string]any{
+		"policy_dir": cfg.GetPolicyDir(),
+		"query":      cfg.GetQuery(),
+		"catalog":    cfg.GetCatalog(),
+	})
+	if len(cfg.GetModules()) > 0 {
+		modules := make(map[string]any, len(cfg.GetModules()))
+		for _, file := range sortedKeys(cfg.GetModules()) {
+			modules[file] = cfg.GetModules()[file]
+		}
+		out["modules"] = modules
+	}
+	if data := structToMapProto(cfg.GetData()); len(data) > 0 {
+		out["data"] = data
+	}
+	return out
+}
@@ -0,0 +1,163 @@
+package internal
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/GoCodeAlone/workflow-plugin-authz/internal/contracts"
+)
+
+const testOPAPolicy = `package authz
+
+default allow := false
+
+# RBAC: the module's role grants, unless the subject is suspended.
+allow if {
+	input.mode == "rbac"
+	input.granted
+	not data.suspended[input.subject]
+}
+
+# ABAC: members of the resource's department may take department actions.
+allow if {
+	input.mode == "abac"
+	input.subject_attributes.department == input.resource_attributes.department
+	input.action in data.department_actions
+}
+`
+
+func newTestOPAModule(t *testing.T, config map[string]any) *OPAModule {
+	t.Helper()
+	module, err := newOPAModule("opa", config)
+	if err != nil {
+		t.Fatalf("newOPAModule: %v", err)
+	}
+	if err := module.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	return module
+}
+
+func TestOPAModuleCheckScope(t *testing.T) {
+	ctx := context.Background()
+	module := newTestOPAModule(t, map[string]any{
+		"modules": map[string]any{"authz.rego": testOPAPolicy},
+		"data":    map[string]any{"suspended": map[string]any{}},
+	})
+	if err := module.DeclareScopes(ctx, []*contracts.ScopeDeclaration{{Name: "billing:invoice:read"}}); err != nil {
+		t.Fatalf("DeclareScopes: %v", err)
+	}
+	if err := module.UpsertRole(ctx, RoleScopeGrant{Role: "viewer", Context: "billing", Scopes: []string{"billing:invoice:read"}}); err != nil {
+		t.Fatalf("UpsertRole: %v", err)
+	}
+	if err := module.AssignRole(ctx, SubjectRoleAssignment{Subject: "alice", Role: "viewer", Context: "billing"}); err != nil {
+		t.Fatalf("AssignRole: %v", err)
+	}
+	check := ScopeCheck{Subject: "alice", Context: "billing", Scope: "billing:invoice:read"}
+
+	result, err := module.CheckScope(ctx, check)
+	if err != nil || !result.Allowed || result.MatchedRole != "viewer" || result.Provider != "opa" {
+		t.Fatalf("CheckScope = %#v, %v; want allowed through viewer", result, err)
+	}
+
+	// Data written through InvokeMethod is visible to the next check.
+	if _, err := module.InvokeMethod("PutData", map[string]any{"path": "/suspended/alice", "value": true}); err != nil {
+		t.Fatalf("PutData: %v", err)
+	}
+	if result, _ := module.CheckScope(ctx, check); result.Allowed || result.Reason != "denied by data.authz.allow" {
+		t.Fatalf("CheckScope after suspension = %#v", result)
+	}
+	if _, err := module.InvokeMethod("RemoveData", map[string]any{"path": "/suspended/alice"}); err != nil {
+		t.Fatalf("RemoveData: %v", err)
+	}
+	if result, _ := module.CheckScope(ctx, check); !result.Allowed {
+		t.Fatalf("CheckScope after removing the suspension = %#v", result)
+	}
+
+	if result, _ := module.CheckScope(ctx, ScopeCheck{Subject: "bob", Context: "billing", Scope: "billing:invoice:read"}); result.Allowed {
+		t.Fatalf("CheckScope for bob = %#v, want denied", result)
+	}
+}
+
+func TestOPAModuleCheckAttributesFromPolicyDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "authz.rego"), []byte(testOPAPolicy), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "data.json"), []byte(`{"department_actions": ["read"]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	module := newTestOPAModule(t, map[string]any{"policy_dir": dir})
+
+	decision, err := DecideAuthorization(context.Background(), module, AuthorizationDecisionInput{
+		Mode:               CapabilityABAC,
+		Subject:            "alice",
+		Resource:           "document:budget",
+		Action:             "read",
+		SubjectAttributes:  map[string]string{"department": "finance"},
+		ResourceAttributes: map[string]string{"department": "finance"},
+	})
+	if err != nil || !decision.Allowed {
+		t.Fatalf("DecideAuthorization = %#v, %v; want allowed", decision, err)
+	}
+	result, err := module.CheckAttributes(context.Background(), AttributeCheck{
+		Subject:            "alice",
+		Action:             "delete",
+		SubjectAttributes:  map[string]string{"department": "finance"},
+		ResourceAttributes: map[string]string{"department": "finance"},
+	})
+	if err != nil || result.Allowed {
+		t.Fatalf("CheckAttributes(delete) = %#v, %v; want denied", result, err)
+	}
+	if err := module.UpsertAttributePolicy(context.Background(), AttributePolicy{ID: "p"}); err == nil {
+		t.Fatal("expected UpsertAttributePolicy to be rejected")
+	}
+}
+
+func TestOPAModuleDecisionObjects(t *testing.T) {
+	module := newTestOPAModule(t, map[string]any{
+		"query": "data.authz.decision",
+		"modules": map[string]any{"authz.rego": `package authz
+
+decision := {"allow": false, "reason": "read only"} if input.action != "read"
+decision := {"allow": true} if input.action == "read"
+`},
+	})
+	result, err := module.CheckAttributes(context.Background(), AttributeCheck{Subject: "alice", Action: "write"})
+	if err != nil || result.Allowed || result.Reason != "read only" {
+		t.Fatalf("CheckAttributes(write) = %#v, %v", result, err)
+	}
+	result, err = module.CheckAttributes(context.Background(), AttributeCheck{Subject: "alice", Action: "read"})
+	if err != nil || !result.Allowed || result.Reason != "allowed by data.authz.decision" {
+		t.Fatalf("CheckAttributes(read) = %#v, %v", result, err)
+	}
+}
+
+func TestOPAModuleConfigErrors(t *testing.T) {
+	if _, err := newOPAModule("opa", map[string]any{}); err == nil {
+		t.Fatal("expected an error without modules or policy_dir")
+	}
+	module, err := newOPAModule("opa", map[string]any{"modules": map[string]any{"bad.rego": "package authz\nallow if {"}})
+	if err != nil {
+		t.Fatalf("newOPAModule: %v", err)
+	}
+	if err := module.Init(); err == nil || !strings.Contains(err.Error(), "compile") {
+		t.Fatalf("Init = %v, want a compile error", err)
+	}
+}
+
+func TestOPAModuleConfigToMap(t *testing.T) {
+	got := opaModuleConfigToMap(&contracts.OPAModuleConfig{
+		Modules:   map[string]string{"authz.rego": testOPAPolicy},
+		PolicyDir: "./policies",
+	})
+	if got["policy_dir"] != "./policies" || mapValue(got["modules"])["authz.rego"] != testOPAPolicy {
+		t.Fatalf("opaModuleConfigToMap = %#v", got)
+	}
+	if _, ok := got["query"]; ok {
+		t.Fatalf("empty query was mapped: %#v", got)
+	}
+}
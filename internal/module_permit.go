@@ -3,7 +3,6 @@ package internal
 import (
 	"context"
 	"fmt"
-	"net/http"
 	"time"
 
 	"github.com/GoCodeAlone/workflow-plugin-authz/internal/contracts"
@@ -12,6 +11,9 @@ import (
 const (
 	defaultPermitAPIURL = "https://api.permit.io"
 	defaultPermitPDPURL = "https://cloudpdp.api.permit.io"
+	// permitDefaultTimeout bounds each Permit request unless
+	// transport.timeout is set.
+	permitDefaultTimeout = 30 * time.Second
 )
 
 // PermitModule implements sdk.ModuleInstance for the permit.provider module type.
@@ -22,6 +24,7 @@ type PermitModule struct {
 	config        permitModuleConfig
 	client        *permitClient
 	scopeProvider *permitScopeProvider
+	localPDP      *permitLocalPDP
 }
 
 // permitModuleConfig holds parsed configuration for a permit.provider module.
@@ -31,6 +34,16 @@ type permitModuleConfig struct {
 	APIURL      string `yaml:"apiUrl"`
 	Project     string `yaml:"project"`
 	Environment string `yaml:"environment"`
+	// Catalog names an authz.scope_catalog whose scopes are replayed into
+	// this provider.
+	Catalog string `yaml:"catalog"`
+	// Transport configures timeouts, retries, and circuit breaking for
+	// requests to the Permit API and PDP.
+	Transport transportConfig `yaml:"transport"`
+	// OnUnavailable decides scope checks while Permit is unavailable.
+	OnUnavailable unavailablePolicy `yaml:"on_unavailable"`
+	// LocalPDP answers checks from policy data synced from the Permit API.
+	LocalPDP localPDPConfig `yaml:"local_pdp"`
 }
 
 // newPermitModule parses the config map and returns a PermitModule.
@@ -52,41 +65,73 @@ func newPermitModule(name string, config map[string]any) (*PermitModule, error)
 
 	project, _ := config["project"].(string)
 	environment, _ := config["environment"].(string)
+	catalog, _ := config["catalog"].(string)
+	policy, err := parseUnavailablePolicy(stringValue(firstNonNil(config["onUnavailable"], config["on_unavailable"])))
+	if err != nil {
+		return nil, fmt.Errorf("permit.provider %q: %w", name, err)
+	}
+	if policy.Fallback == name {
+		return nil, fmt.Errorf("permit.provider %q: on_unavailable cannot fall back to the module itself", name)
+	}
 
 	return &PermitModule{
 		name: name,
 		config: permitModuleConfig{
-			APIKey:      apiKey,
-			PDPURL:      pdpURL,
-			APIURL:      apiURL,
-			Project:     project,
-			Environment: environment,
+			APIKey:        apiKey,
+			PDPURL:        pdpURL,
+			APIURL:        apiURL,
+			Project:       project,
+			Environment:   environment,
+			Catalog:       catalog,
+			Transport:     parseTransportConfig(mapValue(config["transport"])),
+			OnUnavailable: policy,
+			LocalPDP:      parseLocalPDPConfig(mapValue(firstNonNil(config["localPdp"], config["local_pdp"]))),
 		},
 	}, nil
 }
 
 // Init creates the HTTP client and registers it in the global permit registry.
 func (m *PermitModule) Init() error {
-	m.scopeProvider = newPermitScopeProvider(m.name, newPermitSDKScopeClient(m.config))
+	if err := validateFallbackChain(globalRegistry, m.name, m.config.OnUnavailable); err != nil {
+		return fmt.Errorf("permit.provider %q: %w", m.name, err)
+	}
+	httpClient := newRemoteTransport(m.name, m.config.Transport, permitDefaultTimeout).client()
+	sdkClient := newPermitSDKScopeClient(m.config, httpClient)
+	var scopeClient permitScopeClient = sdkClient
+	if m.config.LocalPDP.Enabled {
+		m.localPDP = newPermitLocalPDP(m.name, sdkClient, sdkClient, m.config.LocalPDP)
+		scopeClient = m.localPDP
+	}
+	m.scopeProvider = newPermitScopeProvider(m.name, scopeClient)
 	m.client = &permitClient{
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		httpClient:  httpClient,
 		apiURL:      m.config.APIURL,
 		pdpURL:      m.config.PDPURL,
 		apiKey:      m.config.APIKey,
 		project:     m.config.Project,
 		environment: m.config.Environment,
+		localPDP:    m.localPDP,
 	}
 	RegisterPermitClient(m.name, m.client)
+	if err := subscribeToScopeCatalog(globalRegistry, m.config.Catalog, m); err != nil {
+		return fmt.Errorf("permit.provider %q: %w", m.name, err)
+	}
 	return nil
 }
 
-// Start is a no-op for the permit module.
-func (m *PermitModule) Start(_ context.Context) error { return nil }
+// Start syncs the local PDP, when enabled, and keeps it refreshed.
+func (m *PermitModule) Start(ctx context.Context) error {
+	if m.localPDP != nil {
+		m.localPDP.start(ctx)
+	}
+	return nil
+}
 
-// Stop removes the client from the registry.
+// Stop stops the local PDP refresh and removes the client from the registry.
 func (m *PermitModule) Stop(_ context.Context) error {
+	if m.localPDP != nil {
+		m.localPDP.stop()
+	}
 	UnregisterPermitClient(m.name)
 	return nil
 }
@@ -94,6 +139,8 @@ func (m *PermitModule) Stop(_ context.Context) error {
 // Name returns the module name.
 func (m *PermitModule) Name() string { return m.name }
 
+func (m *PermitModule) onUnavailable() unavailablePolicy { return m.config.OnUnavailable }
+
 func (m *PermitModule) DeclareScopes(ctx context.Context, scopes []*contracts.ScopeDeclaration) error {
 	return m.scopeProvider.DeclareScopes(ctx, scopes)
 }
@@ -114,8 +161,19 @@ func (m *PermitModule) RemoveAssignment(ctx context.Context, assignment SubjectR
 	return m.scopeProvider.RemoveAssignment(ctx, assignment)
 }
 
+// CheckScope checks the scope through the Permit PDP, or the local PDP while
+// its data is fresh, applying on_unavailable when Permit cannot be reached.
 func (m *PermitModule) CheckScope(ctx context.Context, check ScopeCheck) (ScopeCheckResult, error) {
-	return m.scopeProvider.CheckScope(ctx, check)
+	callCtx, call := withRemoteCall(ctx)
+	result, err := m.scopeProvider.CheckScope(callCtx, check)
+	return m.config.OnUnavailable.checkScope(ctx, check, result, call.unavailable(err))
+}
+
+func (m *PermitModule) scopeRoleStore() *scopeRoleStore {
+	if m.scopeProvider == nil {
+		return nil
+	}
+	return m.scopeProvider.store
 }
 
 func (m *PermitModule) DeclareAttributes(context.Context, []*contracts.AttributeDeclaration) error {
@@ -190,6 +248,8 @@ func (m *PermitModule) InvokeMethod(method string, input map[string]any) (map[st
 		return removeRelationTupleInvoke(context.Background(), m, input)
 	case "CheckRelation":
 		return checkRelationInvoke(context.Background(), m, input)
+	case "Simulate":
+		return simulateInvoke(context.Background(), m, input)
 	default:
 		return nil, fmt.Errorf("permit provider method %q is not supported", method)
 	}
@@ -3,8 +3,11 @@ package internal
 import (
 	"context"
 	"fmt"
+	"log"
 	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/GoCodeAlone/workflow-plugin-authz/internal/contracts"
 	sdk "github.com/GoCodeAlone/workflow/plugin/external/sdk"
@@ -19,6 +22,36 @@ type scopeCatalogModule struct {
 	attributes map[string]*contracts.AttributeDeclaration
 	relations  map[string]*contracts.RelationDeclaration
 	uiActions  map[string]*contracts.UIActionDeclaration
+
+	// ownershipConflicts decides what happens when a declaration is registered
+	// over one owned by another plugin/module: "reject" (default) or "warn".
+	ownershipConflicts string
+
+	// subscribers are provider modules configured with `catalog: <name>`;
+	// newly registered declarations are pushed into them.
+	subscribers map[string]ScopeRoleProvider
+
+	// adapter selects where declarations are persisted, using the same keys
+	// as authz.casbin; store is nil for the default in-memory catalog. A
+	// polling watcher reloads the store so replicas see each other's
+	// registrations.
+	adapter adapterConfig
+	watcher watcherConfig
+	store   catalogStore
+	stopCh  chan struct{}
+	doneCh  chan struct{}
+
+	// projectionKey signs subject projections; projectionTTL is their
+	// lifetime.
+	projectionKey    []byte
+	projectionTTL    time.Duration
+	projectionTTLRaw string
+	registry         moduleRegistry
+	now              func() time.Time
+
+	// configErr is the error from registering the config declarations,
+	// reported by Init.
+	configErr error
 }
 
 func newScopeCatalogModule(name string, config map[string]any) *scopeCatalogModule {
@@ -30,21 +63,113 @@ func newScopeCatalogModule(name string, config map[string]any) *scopeCatalogModu
 		attributes: map[string]*contracts.AttributeDeclaration{},
 		relations:  map[string]*contracts.RelationDeclaration{},
 		uiActions:  map[string]*contracts.UIActionDeclaration{},
+
+		ownershipConflicts: stringValue(config["ownership_conflicts"]),
+		projectionKey:      newProjectionSigningKey(stringValue(config["projection_signing_key"])),
+		projectionTTL:      defaultProjectionTTL,
+		projectionTTLRaw:   stringValue(config["projection_ttl"]),
+		registry:           globalRegistry,
+		now:                time.Now,
+	}
+	if adapterRaw, ok := config["adapter"].(map[string]any); ok {
+		m.adapter = parseAdapterConfig(adapterRaw)
+	}
+	if watcherRaw, ok := config["watcher"].(map[string]any); ok {
+		m.watcher = parseWatcherConfig(watcherRaw)
 	}
 	for _, scope := range scopeDeclarationsFromAny(config["scopes"], "", "") {
 		m.upsert(scope)
 	}
 	if declarations := declarationSetFromAny(config["declarations"], "", ""); declarations != nil {
-		_, _ = m.registerDeclarations(&contracts.RegisterDeclarationsInput{Declarations: declarations})
+		_, m.configErr = m.registerDeclarations(&contracts.RegisterDeclarationsInput{Declarations: declarations})
 	}
 	return m
 }
 
-func (m *scopeCatalogModule) Init() error { return nil }
+// Init validates the config declarations, the ownership conflict policy and
+// the projection TTL, and opens the configured storage.
+func (m *scopeCatalogModule) Init() error {
+	if m.configErr != nil {
+		return fmt.Errorf("authz.scope_catalog %q: %w", m.name, m.configErr)
+	}
+	m.mu.Lock()
+	m.inheritResourceLifecycleLocked()
+	err := m.validateReplacementsLocked(nil)
+	m.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("authz.scope_catalog %q: %w", m.name, err)
+	}
+	if !validOwnershipConflictPolicy(m.ownershipConflicts) {
+		return fmt.Errorf("authz.scope_catalog %q: ownership_conflicts must be %q or %q", m.name, ownershipConflictsReject, ownershipConflictsWarn)
+	}
+	if m.projectionTTLRaw != "" {
+		ttl, err := time.ParseDuration(m.projectionTTLRaw)
+		if err != nil || ttl <= 0 {
+			return fmt.Errorf("authz.scope_catalog %q: invalid projection_ttl %q", m.name, m.projectionTTLRaw)
+		}
+		m.projectionTTL = ttl
+	}
+	store, err := openCatalogStore(m.adapter)
+	if err != nil {
+		return fmt.Errorf("authz.scope_catalog %q: %w", m.name, err)
+	}
+	if store == nil {
+		return nil
+	}
+	m.store = store
+	if err := m.openStore(context.Background()); err != nil {
+		return fmt.Errorf("authz.scope_catalog %q: %w", m.name, err)
+	}
+	return nil
+}
 
-func (m *scopeCatalogModule) Start(_ context.Context) error { return nil }
+// Start begins the polling watcher goroutine if watcher.type is "polling".
+func (m *scopeCatalogModule) Start(_ context.Context) error {
+	if m.store == nil || strings.ToLower(m.watcher.Type) != "polling" {
+		return nil
+	}
+	interval := m.watcher.Interval
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	m.stopCh = make(chan struct{})
+	m.doneCh = make(chan struct{})
+	go m.pollLoop(interval)
+	return nil
+}
+
+// pollLoop reloads declarations from the store on each tick.
+func (m *scopeCatalogModule) pollLoop(interval time.Duration) {
+	defer close(m.doneCh)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.stopCh:
+			return
+		case <-ticker.C:
+			if err := m.reload(context.Background()); err != nil {
+				log.Printf("authz.scope_catalog %q: reload: %v", m.name, err)
+			}
+		}
+	}
+}
 
-func (m *scopeCatalogModule) Stop(_ context.Context) error { return nil }
+// Stop shuts down the polling watcher and closes the store.
+func (m *scopeCatalogModule) Stop(_ context.Context) error {
+	if m.stopCh != nil {
+		close(m.stopCh)
+		<-m.doneCh
+		m.stopCh = nil
+		m.doneCh = nil
+	}
+	if m.store == nil {
+		return nil
+	}
+	err := m.store.Close()
+	m.store = nil
+	return err
+}
 
 func (m *scopeCatalogModule) InvokeMethod(method string, input map[string]any) (map[string]any, error) {
 	switch method {
@@ -65,23 +190,70 @@ func (m *scopeCatalogModule) InvokeMethod(method string, input map[string]any) (
 			return nil, err
 		}
 		return registerDeclarationsOutputToMap(out), nil
+	case "UnregisterDeclarations":
+		out, err := m.unregisterDeclarations(unregisterDeclarationsInputFromMap(input))
+		if err != nil {
+			return nil, fmt.Errorf("authz.scope_catalog %q: %w", m.name, err)
+		}
+		return unregisterDeclarationsOutputToMap(out), nil
+	case "MigrateScopes":
+		out, err := m.migrateScopes(context.Background(), migrateScopesInputFromMap(input))
+		if err != nil {
+			return nil, fmt.Errorf("authz.scope_catalog %q: %w", m.name, err)
+		}
+		return migrateScopesOutputToMap(out), nil
 	case "ListDeclarations":
 		return map[string]any{"declarations": declarationSetToMap(m.listDeclarations(listDeclarationsInputFromMap(input)))}, nil
 	case "ResolveProjectionInputs":
 		return map[string]any{"projection": projectionInputsToMap(m.resolveProjectionInputs(resolveProjectionInputsInputFromMap(input)))}, nil
+	case "ResolveSubjectProjection":
+		projection, err := m.resolveSubjectProjection(context.Background(), m.registry, resolveSubjectProjectionInputFromMap(input))
+		if err != nil {
+			return nil, fmt.Errorf("authz.scope_catalog %q: %w", m.name, err)
+		}
+		return map[string]any{"projection": subjectProjectionToMap(projection)}, nil
 	default:
 		return nil, fmt.Errorf("authz scope catalog method %q is not supported", method)
 	}
 }
 
 func (m *scopeCatalogModule) registerScopes(input *contracts.RegisterScopesInput) (*contracts.RegisterScopesOutput, error) {
+	out, err := m.storeScopes(input)
+	if err != nil {
+		return nil, err
+	}
+	if len(out.GetScopes()) > 0 {
+		set := &contracts.AuthzDeclarationSet{OwnerPlugin: input.GetOwnerPlugin(), OwnerModule: input.GetOwnerModule(), Scopes: out.GetScopes()}
+		if err := m.persist(context.Background(), set); err != nil {
+			return nil, fmt.Errorf("authz.scope_catalog %q: persist scopes: %w", m.name, err)
+		}
+		publishChange(m.name, "scope_catalog", ChangeDeclarationsRegistered, map[string]any{
+			"registered":   int(out.GetRegistered()),
+			"declarations": declarationSetToMap(set),
+		})
+		out.SubscriberErrors = errorStrings(m.pushToSubscribers(context.Background(), set))
+	}
+	return out, nil
+}
+
+func (m *scopeCatalogModule) storeScopes(input *contracts.RegisterScopesInput) (*contracts.RegisterScopesOutput, error) {
 	if input == nil {
 		return &contracts.RegisterScopesOutput{}, nil
 	}
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	if err := validateScopeReplacements(m.scopes, input.GetScopes()); err != nil {
+		return nil, err
+	}
+	out := m.storeScopesLocked(input)
+	out.Scopes = mergeScopes(out.GetScopes(), m.inheritResourceLifecycleLocked())
+	return out, nil
+}
+
+func (m *scopeCatalogModule) storeScopesLocked(input *contracts.RegisterScopesInput) *contracts.RegisterScopesOutput {
 	registered := int32(0)
 	out := make([]*contracts.ScopeDeclaration, 0, len(input.GetScopes()))
+	var conflicts []*contracts.DeclarationConflict
 	for _, incoming := range input.GetScopes() {
 		scope := cloneScopeDeclaration(incoming)
 		if scope.GetName() == "" {
@@ -93,13 +265,17 @@ func (m *scopeCatalogModule) registerScopes(input *contracts.RegisterScopesInput
 		if scope.OwnerModule == "" {
 			scope.OwnerModule = input.GetOwnerModule()
 		}
-		if _, exists := m.scopes[scope.GetName()]; !exists {
+		existing, exists := m.scopes[scope.GetName()]
+		if !m.claimLocked("scope", scope.GetName(), existing, exists, scope, &conflicts) {
+			continue
+		}
+		if !exists {
 			registered++
 		}
 		m.upsertLocked(scope)
 		out = append(out, cloneScopeDeclaration(scope))
 	}
-	return &contracts.RegisterScopesOutput{Registered: registered, Scopes: out}, nil
+	return &contracts.RegisterScopesOutput{Registered: registered, Scopes: out, Conflicts: conflicts}
 }
 
 func (m *scopeCatalogModule) listScopes(input *contracts.ListScopesInput) []*contracts.ScopeDeclaration {
@@ -130,11 +306,14 @@ func (m *scopeCatalogModule) resolveSubjectScopes(input *contracts.ResolveSubjec
 	}
 	m.mu.RLock()
 	defer m.mu.RUnlock()
+	// Replaced scopes resolve to their current name so callers never see the
+	// alias, only a deprecation warning.
 	names := map[string]struct{}{}
-	for _, scope := range append(input.GetRoleScopes(), input.GetDirectScopes()...) {
-		if scope != "" {
-			names[scope] = struct{}{}
-		}
+	var deprecations []string
+	for _, scope := range uniqueStrings(append(input.GetRoleScopes(), input.GetDirectScopes()...)) {
+		target := resolveScopeAlias(m.scopes, scope)
+		deprecations = append(deprecations, scopeDeprecations(m.scopes, scope, target)...)
+		names[target] = struct{}{}
 	}
 	resolved := make([]string, 0, len(names))
 	declared := make([]*contracts.ScopeDeclaration, 0, len(names))
@@ -154,6 +333,7 @@ func (m *scopeCatalogModule) resolveSubjectScopes(input *contracts.ResolveSubjec
 		Subject:        input.GetSubject(),
 		Scopes:         resolved,
 		DeclaredScopes: declared,
+		Deprecations:   deprecations,
 	}
 }
 
@@ -184,6 +364,21 @@ func scopeCatalogConfigToMap(cfg *contracts.ScopeCatalogConfig) map[string]any {
 	if cfg.GetDeclarations() != nil {
 		out["declarations"] = declarationSetToMap(cfg.GetDeclarations())
 	}
+	if cfg.GetOwnershipConflicts() != "" {
+		out["ownership_conflicts"] = cfg.GetOwnershipConflicts()
+	}
+	if cfg.GetProjectionSigningKey() != "" {
+		out["projection_signing_key"] = cfg.GetProjectionSigningKey()
+	}
+	if cfg.GetProjectionTtl() != "" {
+		out["projection_ttl"] = cfg.GetProjectionTtl()
+	}
+	if adapter := cfg.GetAdapter(); adapter != nil {
+		out["adapter"] = adapterConfigToMap(adapter)
+	}
+	if watcher := cfg.GetWatcher(); watcher != nil {
+		out["watcher"] = watcherConfigToMap(watcher)
+	}
 	return out
 }
 
@@ -244,9 +439,23 @@ func resolveSubjectScopesInputFromMap(values map[string]any) *contracts.ResolveS
 
 func registerScopesOutputToMap(out *contracts.RegisterScopesOutput) map[string]any {
 	if out == nil {
-		return map[string]any{"registered": 0, "scopes": []map[string]any{}}
+		return map[string]any{"registered": 0, "scopes": []map[string]any{}, "conflicts": []map[string]any{}, "subscriber_errors": []string{}}
 	}
-	return map[string]any{"registered": int(out.GetRegistered()), "scopes": scopeDeclarationsToMaps(out.GetScopes())}
+	return map[string]any{
+		"registered":        int(out.GetRegistered()),
+		"scopes":            scopeDeclarationsToMaps(out.GetScopes()),
+		"conflicts":         declarationConflictsToMaps(out.GetConflicts()),
+		"subscriber_errors": append([]string{}, out.GetSubscriberErrors()...),
+	}
+}
+
+// errorStrings returns the messages of errs.
+func errorStrings(errs []error) []string {
+	out := make([]string, 0, len(errs))
+	for _, err := range errs {
+		out = append(out, err.Error())
+	}
+	return out
 }
 
 func resolveSubjectScopesOutputToMap(out *contracts.ResolveSubjectScopesOutput) map[string]any {
@@ -257,6 +466,7 @@ func resolveSubjectScopesOutputToMap(out *contracts.ResolveSubjectScopesOutput)
 		"subject":         out.GetSubject(),
 		"scopes":          append([]string(nil), out.GetScopes()...),
 		"declared_scopes": scopeDeclarationsToMaps(out.GetDeclaredScopes()),
+		"deprecations":    append([]string(nil), out.GetDeprecations()...),
 	}
 }
 
@@ -307,6 +517,9 @@ func scopeDeclarationFromMap(values map[string]any, ownerPlugin, ownerModule str
 		OwnerPlugin: defaultString(stringValue(values["owner_plugin"]), ownerPlugin),
 		OwnerModule: defaultString(stringValue(values["owner_module"]), ownerModule),
 		Category:    stringValue(values["category"]),
+		Deprecated:  boolValue(values["deprecated"]),
+		ReplacedBy:  stringValue(values["replaced_by"]),
+		Version:     stringValue(values["version"]),
 	}
 }
 
@@ -327,6 +540,22 @@ func mapValue(value any) map[string]any {
 	}
 }
 
+// mapListValue returns the maps in a []map[string]any or []any config value.
+func mapListValue(value any) []map[string]any {
+	switch items := value.(type) {
+	case []map[string]any:
+		return items
+	case []any:
+		out := make([]map[string]any, 0, len(items))
+		for _, item := range items {
+			out = append(out, mapValue(item))
+		}
+		return out
+	default:
+		return nil
+	}
+}
+
 func defaultString(value, fallback string) string {
 	if value != "" {
 		return value
@@ -340,7 +569,7 @@ func scopeDeclarationsToMaps(scopes []*contracts.ScopeDeclaration) []map[string]
 		if scope == nil {
 			continue
 		}
-		out = append(out, compactMap(map[string]any{
+		item := compactMap(map[string]any{
 			"name":         scope.GetName(),
 			"context":      scope.GetContext(),
 			"resource":     scope.GetResource(),
@@ -349,7 +578,13 @@ func scopeDeclarationsToMaps(scopes []*contracts.ScopeDeclaration) []map[string]
 			"owner_plugin": scope.GetOwnerPlugin(),
 			"owner_module": scope.GetOwnerModule(),
 			"category":     scope.GetCategory(),
-		}))
+			"replaced_by":  scope.GetReplacedBy(),
+			"version":      scope.GetVersion(),
+		})
+		if scope.GetDeprecated() {
+			item["deprecated"] = true
+		}
+		out = append(out, item)
 	}
 	return out
 }
@@ -367,6 +602,9 @@ func cloneScopeDeclaration(scope *contracts.ScopeDeclaration) *contracts.ScopeDe
 		OwnerPlugin: scope.GetOwnerPlugin(),
 		OwnerModule: scope.GetOwnerModule(),
 		Category:    scope.GetCategory(),
+		Deprecated:  scope.GetDeprecated(),
+		ReplacedBy:  scope.GetReplacedBy(),
+		Version:     scope.GetVersion(),
 	}
 }
 
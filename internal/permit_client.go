@@ -4,11 +4,33 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 )
 
+// ErrPermitClientNotFound is returned by Permit steps whose module has no
+// registered client.
+var ErrPermitClientNotFound = errors.New("permit client not found")
+
+// PermitError is a Permit API or PDP call that Permit answered with an error
+// status.
+type PermitError struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Body       string
+}
+
+func (e *PermitError) Error() string {
+	return fmt.Sprintf("permit: %s %s: status %d: %s", e.Method, e.URL, e.StatusCode, e.Body)
+}
+
+func permitClientNotFound(moduleName string) error {
+	return fmt.Errorf("%w: %s", ErrPermitClientNotFound, moduleName)
+}
+
 // permitClient is an HTTP client for the Permit.io REST API and PDP API.
 type permitClient struct {
 	httpClient  *http.Client
@@ -17,12 +39,18 @@ type permitClient struct {
 	apiKey      string
 	project     string
 	environment string
+	// localPDP, when set, answers step.permit_check while its data is fresh.
+	localPDP *permitLocalPDP
 }
 
 // doAPI performs an authenticated request to the Permit.io management API.
 // Returns the parsed JSON response body as map[string]any, or an error.
 func (c *permitClient) doAPI(ctx context.Context, method, path string, body any) (map[string]any, error) {
-	return c.doRequest(ctx, c.apiURL, method, path, body)
+	result, err := c.doRequest(ctx, c.apiURL, method, path, body)
+	if err == nil {
+		c.wrote(ctx, method, path, body)
+	}
+	return result, err
 }
 
 // doPDP performs an authenticated request to the Permit.io PDP (policy decision point) API.
@@ -32,44 +60,51 @@ func (c *permitClient) doPDP(ctx context.Context, method, path string, body any)
 
 // doAPIList performs an authenticated request that returns an array response.
 func (c *permitClient) doAPIList(ctx context.Context, method, path string, body any) ([]any, error) {
-	return c.doRequestList(ctx, c.apiURL, method, path, body)
+	items, err := c.doRequestList(ctx, c.apiURL, method, path, body)
+	if err == nil {
+		c.wrote(ctx, method, path, body)
+	}
+	return items, err
 }
 
-// doRequest is the shared HTTP request helper. baseURL + path form the full URL.
-func (c *permitClient) doRequest(ctx context.Context, baseURL, method, path string, body any) (map[string]any, error) {
-	var reqBody io.Reader
-	if body != nil {
-		b, err := json.Marshal(body)
-		if err != nil {
-			return nil, fmt.Errorf("permit: marshal request body: %w", err)
-		}
-		reqBody = bytes.NewReader(b)
-	}
+// permitWrite is one successful write to the Permit management API.
+type permitWrite struct {
+	Method string
+	Path   string
+	Body   any
+}
 
-	url := baseURL + path
-	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
-	if err != nil {
-		return nil, fmt.Errorf("permit: build request: %w", err)
+// permitWriteLog collects the writes made while one step executes, so the
+// step can audit them.
+type permitWriteLog struct {
+	writes []permitWrite
+}
+
+type permitWriteLogKey struct{}
+
+func withPermitWriteLog(ctx context.Context, log *permitWriteLog) context.Context {
+	return context.WithValue(ctx, permitWriteLogKey{}, log)
+}
+
+// wrote records a successful management API call that changed Permit state,
+// and marks the local PDP's data stale so checks stop answering from it.
+func (c *permitClient) wrote(ctx context.Context, method, path string, body any) {
+	if method == http.MethodGet {
+		return
 	}
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
-	if body != nil {
-		req.Header.Set("Content-Type", "application/json")
+	if c.localPDP != nil {
+		c.localPDP.invalidate()
 	}
-	req.Header.Set("Accept", "application/json")
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("permit: %s %s: %w", method, url, err)
+	if log, ok := ctx.Value(permitWriteLogKey{}).(*permitWriteLog); ok {
+		log.writes = append(log.writes, permitWrite{Method: method, Path: path, Body: body})
 	}
-	defer resp.Body.Close()
+}
 
-	respBytes, err := io.ReadAll(resp.Body)
+// doRequest is the shared HTTP request helper. baseURL + path form the full URL.
+func (c *permitClient) doRequest(ctx context.Context, baseURL, method, path string, body any) (map[string]any, error) {
+	respBytes, err := c.doRequestBytes(ctx, baseURL, method, path, body)
 	if err != nil {
-		return nil, fmt.Errorf("permit: read response body: %w", err)
-	}
-
-	if resp.StatusCode >= 400 {
-		return nil, fmt.Errorf("permit: %s %s: status %d: %s", method, url, resp.StatusCode, string(respBytes))
+		return nil, err
 	}
 
 	if len(respBytes) == 0 {
@@ -85,6 +120,20 @@ func (c *permitClient) doRequest(ctx context.Context, baseURL, method, path stri
 
 // doRequestList performs a request that expects a JSON array response.
 func (c *permitClient) doRequestList(ctx context.Context, baseURL, method, path string, body any) ([]any, error) {
+	respBytes, err := c.doRequestBytes(ctx, baseURL, method, path, body)
+	if err != nil {
+		return nil, err
+	}
+	page, err := decodePermitPage(respBytes)
+	if err != nil {
+		return nil, err
+	}
+	return page.Items, nil
+}
+
+// doRequestBytes sends an authenticated request and returns the response
+// body, or a *PermitError when Permit answers with an error status.
+func (c *permitClient) doRequestBytes(ctx context.Context, baseURL, method, path string, body any) ([]byte, error) {
 	var reqBody io.Reader
 	if body != nil {
 		b, err := json.Marshal(body)
@@ -117,27 +166,59 @@ func (c *permitClient) doRequestList(ctx context.Context, baseURL, method, path
 	}
 
 	if resp.StatusCode >= 400 {
-		return nil, fmt.Errorf("permit: %s %s: status %d: %s", method, url, resp.StatusCode, string(respBytes))
+		return nil, &PermitError{Method: method, URL: url, StatusCode: resp.StatusCode, Body: string(respBytes)}
 	}
+	return respBytes, nil
+}
 
+// permitPage is one page of a Permit list response.
+type permitPage struct {
+	Items []any
+	// TotalCount and PageCount are -1 when the response does not carry them,
+	// as with endpoints that return a bare array.
+	TotalCount int
+	PageCount  int
+}
+
+// decodePermitPage decodes a bare array or a paginated {"data": [...]}
+// object. Any other object is returned as a single item.
+func decodePermitPage(respBytes []byte) (permitPage, error) {
+	page := permitPage{Items: []any{}, TotalCount: -1, PageCount: -1}
 	if len(respBytes) == 0 {
-		return []any{}, nil
+		return page, nil
+	}
+	if err := json.Unmarshal(respBytes, &page.Items); err == nil {
+		return page, nil
+	}
+	var obj map[string]any
+	if err := json.Unmarshal(respBytes, &obj); err != nil {
+		return page, fmt.Errorf("permit: decode list response: %w", err)
 	}
+	data, ok := obj["data"].([]any)
+	if !ok {
+		page.Items = []any{obj}
+		return page, nil
+	}
+	page.Items = data
+	if total, ok := obj["total_count"].(float64); ok {
+		page.TotalCount = int(total)
+	}
+	if count, ok := obj["page_count"].(float64); ok {
+		page.PageCount = int(count)
+	}
+	return page, nil
+}
 
-	var result []any
-	if err := json.Unmarshal(respBytes, &result); err != nil {
-		// Try to decode as object with data field (paginated responses)
-		var obj map[string]any
-		if err2 := json.Unmarshal(respBytes, &obj); err2 == nil {
-			if data, ok := obj["data"].([]any); ok {
-				return data, nil
-			}
-			// Return as single-element list
-			return []any{obj}, nil
-		}
-		return nil, fmt.Errorf("permit: decode list response: %w", err)
+// hasNext reports whether a page follows page number n of size perPage.
+func (p permitPage) hasNext(n, perPage int) bool {
+	switch {
+	case p.PageCount >= 0:
+		return n < p.PageCount
+	case p.TotalCount >= 0:
+		return n*perPage < p.TotalCount
+	default:
+		return len(p.Items) == perPage
 	}
-	return result, nil
 }
 
 // resolvePermitValue resolves a config/current value by key, preferring current over config.
@@ -0,0 +1,520 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/GoCodeAlone/workflow-plugin-authz/internal/contracts"
+	permitapi "github.com/permitio/permit-golang/pkg/api"
+)
+
+const (
+	defaultLocalPDPInterval = 30 * time.Second
+	// permitMaxDerivationDepth bounds how many relationship tuples a derived
+	// resource role is followed through, so cyclic tuples terminate.
+	permitMaxDerivationDepth = 16
+)
+
+// localPDPConfig enables answering Permit checks from a local copy of the
+// environment's policy data.
+type localPDPConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Interval is how often the policy data is pulled from the Permit API.
+	Interval time.Duration `yaml:"interval"`
+	// MaxStaleness is how old the local data may be before checks go to the
+	// remote PDP again. Defaults to three intervals.
+	MaxStaleness time.Duration `yaml:"maxStaleness"`
+}
+
+func parseLocalPDPConfig(raw map[string]any) localPDPConfig {
+	cfg := localPDPConfig{
+		Enabled:      boolValue(raw["enabled"]),
+		Interval:     parseDurationValue(raw["interval"]),
+		MaxStaleness: parseDurationValue(firstNonNil(raw["maxStaleness"], raw["max_staleness"])),
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaultLocalPDPInterval
+	}
+	if cfg.MaxStaleness <= 0 {
+		cfg.MaxStaleness = 3 * cfg.Interval
+	}
+	return cfg
+}
+
+// permitPolicy is the policy data of a Permit environment that the local PDP
+// evaluates: roles, resource roles, role assignments, and relationship tuples.
+type permitPolicy struct {
+	Roles         []permitPolicyRole
+	ResourceRoles []permitPolicyRole
+	Assignments   []permitPolicyAssignment
+	Tuples        []permitPolicyTuple
+	// HasConditionSets reports that the environment defines ABAC condition
+	// sets, which the local evaluator cannot apply.
+	HasConditionSets bool
+}
+
+type permitPolicyRole struct {
+	// Resource is the resource type of a resource role, empty for top-level
+	// roles.
+	Resource    string
+	Key         string
+	Permissions []string
+	Extends     []string
+	// GrantedTo derives the role on an instance from a role on a related
+	// instance.
+	GrantedTo []permitRoleDerivation
+}
+
+type permitRoleDerivation struct {
+	Role             string
+	OnResource       string
+	LinkedByRelation string
+}
+
+type permitPolicyAssignment struct {
+	User   string
+	Role   string
+	Tenant string
+	// ResourceInstance is "type:key" for a resource role assignment.
+	ResourceInstance string
+}
+
+type permitPolicyTuple struct {
+	Subject  string
+	Relation string
+	Object   string
+	Tenant   string
+}
+
+// permitPolicySource loads the policy data the local PDP evaluates.
+type permitPolicySource interface {
+	FetchPolicy(context.Context) (permitPolicy, error)
+}
+
+// permitLocalEvaluator answers checks from a policy snapshot. Top-level roles
+// live in a scopeRoleStore whose contexts are tenants; resource role
+// assignments and relationship tuples live in a relationTupleStore.
+type permitLocalEvaluator struct {
+	roles     *scopeRoleStore
+	relations *relationTupleStore
+	// resourceRoles is keyed by "resource#role".
+	resourceRoles map[string]permitPolicyRole
+	// implied lists, per "resource#role", the roles of the same resource it
+	// extends, including itself.
+	implied map[string][]string
+	// conditional is set when the policy has condition sets, so its checks
+	// must go to the remote PDP.
+	conditional bool
+}
+
+func newPermitLocalEvaluator(policy permitPolicy) *permitLocalEvaluator {
+	e := &permitLocalEvaluator{
+		roles:         newScopeRoleStore("permit"),
+		relations:     newRelationTupleStore(),
+		resourceRoles: map[string]permitPolicyRole{},
+		implied:       map[string][]string{},
+		conditional:   policy.HasConditionSets,
+	}
+
+	rolePermissions := map[string][]string{}
+	roleExtends := map[string][]string{}
+	for _, role := range policy.Roles {
+		rolePermissions[role.Key] = role.Permissions
+		roleExtends[role.Key] = role.Extends
+	}
+	tenants := map[string]bool{}
+	var assigns []SubjectRoleAssignment
+	for _, assignment := range policy.Assignments {
+		if assignment.ResourceInstance != "" {
+			_ = e.relations.Upsert(RelationTuple{Subject: assignment.User, Relation: assignment.Role, Object: assignment.ResourceInstance, Context: assignment.Tenant})
+			continue
+		}
+		tenants[assignment.Tenant] = true
+		assigns = append(assigns, SubjectRoleAssignment{Subject: assignment.User, Role: assignment.Role, Context: assignment.Tenant})
+	}
+	var scopes []*contracts.ScopeDeclaration
+	var grants []RoleScopeGrant
+	for tenant := range tenants {
+		for _, role := range policy.Roles {
+			grant := RoleScopeGrant{Role: role.Key, Context: tenant}
+			for _, permission := range expandPermitRole(role.Key, roleExtends, rolePermissions) {
+				resource, action, ok := strings.Cut(permission, ":")
+				if !ok {
+					continue
+				}
+				name := tenant + ":" + resource + ":" + action
+				scopes = append(scopes, &contracts.ScopeDeclaration{Name: name, Context: tenant, Resource: resource, Actions: []string{action}})
+				grant.Scopes = append(grant.Scopes, name)
+			}
+			grants = append(grants, grant)
+		}
+	}
+	_ = e.roles.DeclareScopes(context.Background(), scopes)
+	e.roles.replaceGrants(grants, assigns)
+
+	resourceExtends := map[string][]string{}
+	for _, role := range policy.ResourceRoles {
+		key := role.Resource + "#" + role.Key
+		e.resourceRoles[key] = role
+		for _, extended := range role.Extends {
+			resourceExtends[key] = append(resourceExtends[key], role.Resource+"#"+extended)
+		}
+	}
+	for key := range e.resourceRoles {
+		e.implied[key] = expandPermitRole(key, resourceExtends, nil)
+	}
+	for _, tuple := range policy.Tuples {
+		_ = e.relations.Upsert(RelationTuple{Subject: tuple.Subject, Relation: tuple.Relation, Object: tuple.Object, Context: tuple.Tenant})
+	}
+	return e
+}
+
+// expandPermitRole follows a role's extends. With permissions it returns the
+// role's permissions including inherited ones, otherwise the extended roles
+// including the role itself.
+func expandPermitRole(role string, extends map[string][]string, permissions map[string][]string) []string {
+	var out []string
+	seen := map[string]bool{}
+	var visit func(string)
+	visit = func(key string) {
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		if permissions == nil {
+			out = append(out, key)
+		} else {
+			out = append(out, permissions[key]...)
+		}
+		for _, extended := range extends[key] {
+			visit(extended)
+		}
+	}
+	visit(role)
+	return uniqueStrings(out)
+}
+
+// allowed reports whether user may perform action on the resource, through a
+// top-level role in tenant or, when resourceKey is set, a resource role on
+// the instance. The reason names the role that granted it.
+func (e *permitLocalEvaluator) allowed(user, action, resourceType, resourceKey, tenant string) (bool, string) {
+	result, _ := e.roles.CheckScope(context.Background(), ScopeCheck{Subject: user, Context: tenant, Scope: tenant + ":" + resourceType + ":" + action})
+	if result.Allowed {
+		return true, "role " + result.MatchedRole
+	}
+	if resourceKey == "" {
+		return false, "no role grants " + permitPermission(resourceType, action)
+	}
+	instance := resourceType + ":" + resourceKey
+	for key, role := range e.resourceRoles {
+		if role.Resource != resourceType || !permitRoleHasAction(role, action) {
+			continue
+		}
+		if e.hasInstanceRole(user, key, instance, tenant, 0) {
+			return true, "resource role " + key + " on " + instance
+		}
+	}
+	return false, "no role grants " + permitPermission(resourceType, action) + " on " + instance
+}
+
+// hasInstanceRole reports whether user holds roleKey ("resource#role") on
+// instance, directly, through a role that extends it, or derived from a role
+// on a related instance.
+func (e *permitLocalEvaluator) hasInstanceRole(user, roleKey, instance, tenant string, depth int) bool {
+	if depth > permitMaxDerivationDepth {
+		return false
+	}
+	for key, implied := range e.implied {
+		if !containsString(implied, roleKey) {
+			continue
+		}
+		role := e.resourceRoles[key]
+		if e.relations.Contains(RelationCheck{Subject: user, Relation: role.Key, Object: instance, Context: tenant}) {
+			return true
+		}
+		for _, derivation := range role.GrantedTo {
+			for _, tuple := range e.relations.List(RelationTupleFilter{Relation: derivation.LinkedByRelation, Object: instance, Context: tenant}) {
+				if !strings.HasPrefix(tuple.Subject, derivation.OnResource+":") {
+					continue
+				}
+				if e.hasInstanceRole(user, derivation.OnResource+"#"+derivation.Role, tuple.Subject, tenant, depth+1) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// permitRoleHasAction accepts resource role permissions written either as the
+// bare action or as "resource:action".
+func permitRoleHasAction(role permitPolicyRole, action string) bool {
+	return containsString(role.Permissions, action) || containsString(role.Permissions, permitPermission(role.Resource, action))
+}
+
+// permitLocalPDP keeps a permitLocalEvaluator in sync with the Permit API and
+// answers checks from it while its data is fresh. It wraps the module's
+// permitScopeClient, sending checks to the remote PDP when the data is stale
+// and writes through unchanged.
+type permitLocalPDP struct {
+	name   string
+	source permitPolicySource
+	remote permitScopeClient
+	tenant string
+	config localPDPConfig
+	now    func() time.Time
+
+	mu        sync.RWMutex
+	evaluator *permitLocalEvaluator
+	syncedAt  time.Time
+	// generation counts invalidations, so a sync that fetched before one
+	// does not install the older data as fresh.
+	generation uint64
+
+	refreshCh chan struct{}
+	stopCh    chan struct{}
+	doneCh    chan struct{}
+}
+
+func newPermitLocalPDP(name string, source permitPolicySource, remote permitScopeClient, config localPDPConfig) *permitLocalPDP {
+	return &permitLocalPDP{
+		name:      name,
+		source:    source,
+		remote:    remote,
+		tenant:    "default",
+		config:    config,
+		now:       time.Now,
+		refreshCh: make(chan struct{}, 1),
+	}
+}
+
+// sync replaces the local policy data with the current data from Permit. The
+// data is dropped when the local PDP was invalidated while it was fetched;
+// the invalidation has queued another sync.
+func (p *permitLocalPDP) sync(ctx context.Context) error {
+	p.mu.RLock()
+	generation := p.generation
+	p.mu.RUnlock()
+	policy, err := p.source.FetchPolicy(ctx)
+	if err != nil {
+		return fmt.Errorf("permit.provider %q: local pdp sync: %w", p.name, err)
+	}
+	evaluator := newPermitLocalEvaluator(policy)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.generation != generation {
+		return nil
+	}
+	p.evaluator = evaluator
+	p.syncedAt = p.now()
+	return nil
+}
+
+// fresh returns the evaluator when its data is younger than max_staleness and
+// has no condition sets it would ignore.
+func (p *permitLocalPDP) fresh() (*permitLocalEvaluator, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.evaluator == nil || p.evaluator.conditional || p.syncedAt.IsZero() || p.now().Sub(p.syncedAt) > p.config.MaxStaleness {
+		return nil, false
+	}
+	return p.evaluator, true
+}
+
+// check answers a check locally. ok is false when the local data is stale and
+// the caller should ask the remote PDP.
+func (p *permitLocalPDP) check(user, action, resourceType, resourceKey, tenant string) (allowed bool, reason string, ok bool) {
+	evaluator, ok := p.fresh()
+	if !ok {
+		return false, "", false
+	}
+	allowed, reason = evaluator.allowed(user, action, resourceType, resourceKey, defaultString(tenant, p.tenant))
+	return allowed, reason, true
+}
+
+// invalidate marks the local data stale after this module changed Permit, so
+// checks go to the remote PDP until the next sync picks the change up.
+func (p *permitLocalPDP) invalidate() {
+	p.mu.Lock()
+	p.syncedAt = time.Time{}
+	p.generation++
+	p.mu.Unlock()
+	select {
+	case p.refreshCh <- struct{}{}:
+	default:
+	}
+}
+
+// start syncs once and then on every interval. A failed sync is not fatal:
+// checks go to the remote PDP until a sync succeeds.
+func (p *permitLocalPDP) start(ctx context.Context) {
+	_ = p.sync(ctx)
+	p.stopCh = make(chan struct{})
+	p.doneCh = make(chan struct{})
+	go p.pollLoop()
+}
+
+func (p *permitLocalPDP) pollLoop() {
+	defer close(p.doneCh)
+	ticker := time.NewTicker(p.config.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.stopCh:
+			return
+		case <-ticker.C:
+		case <-p.refreshCh:
+		}
+		_ = p.sync(context.Background())
+	}
+}
+
+func (p *permitLocalPDP) stop() {
+	if p.stopCh != nil {
+		close(p.stopCh)
+		<-p.doneCh
+		p.stopCh = nil
+		p.doneCh = nil
+	}
+}
+
+func (p *permitLocalPDP) DeclareResource(ctx context.Context, resource string, actions []string) error {
+	return p.remote.DeclareResource(ctx, resource, actions)
+}
+
+func (p *permitLocalPDP) UpsertRole(ctx context.Context, role string, permissions []string) error {
+	defer p.invalidate()
+	return p.remote.UpsertRole(ctx, role, permissions)
+}
+
+func (p *permitLocalPDP) AssignRole(ctx context.Context, subject, role, tenant string) error {
+	defer p.invalidate()
+	return p.remote.AssignRole(ctx, subject, role, tenant)
+}
+
+func (p *permitLocalPDP) UnassignRole(ctx context.Context, subject, role, tenant string) error {
+	defer p.invalidate()
+	return p.remote.UnassignRole(ctx, subject, role, tenant)
+}
+
+// Check answers from the local data while it is fresh, otherwise from the
+// remote PDP.
+func (p *permitLocalPDP) Check(ctx context.Context, subject, action, resource string) (bool, error) {
+	if allowed, _, ok := p.check(subject, action, resource, "", p.tenant); ok {
+		return allowed, nil
+	}
+	return p.remote.Check(ctx, subject, action, resource)
+}
+
+// FetchPolicy lists the environment's roles, resource roles, role
+// assignments, and relationship tuples, following every page.
+func (c *permitSDKScopeClient) FetchPolicy(ctx context.Context) (permitPolicy, error) {
+	var policy permitPolicy
+	roles, err := permitListAll(func(page, perPage int) ([]permitPolicyRole, error) {
+		items, err := c.client.Api.Roles.List(ctx, page, perPage)
+		out := make([]permitPolicyRole, 0, len(items))
+		for _, item := range items {
+			out = append(out, permitPolicyRole{Key: item.Key, Permissions: item.Permissions, Extends: item.Extends})
+		}
+		return out, err
+	})
+	if err != nil {
+		return policy, fmt.Errorf("list roles: %w", err)
+	}
+	policy.Roles = roles
+
+	resources, err := permitListAll(func(page, perPage int) ([]string, error) {
+		items, err := c.client.Api.Resources.List(ctx, page, perPage)
+		out := make([]string, 0, len(items))
+		for _, item := range items {
+			out = append(out, item.Key)
+		}
+		return out, err
+	})
+	if err != nil {
+		return policy, fmt.Errorf("list resources: %w", err)
+	}
+	for _, resource := range resources {
+		resourceRoles, err := permitListAll(func(page, perPage int) ([]permitPolicyRole, error) {
+			items, err := c.client.Api.ResourceRoles.List(ctx, page, perPage, resource)
+			if items == nil {
+				return nil, err
+			}
+			out := make([]permitPolicyRole, 0, len(*items))
+			for _, item := range *items {
+				role := permitPolicyRole{Resource: resource, Key: item.Key, Permissions: item.Permissions, Extends: item.Extends}
+				if item.GrantedTo != nil {
+					for _, rule := range item.GrantedTo.UsersWithRole {
+						role.GrantedTo = append(role.GrantedTo, permitRoleDerivation{Role: rule.Role, OnResource: rule.OnResource, LinkedByRelation: rule.LinkedByRelation})
+					}
+				}
+				out = append(out, role)
+			}
+			return out, err
+		})
+		if err != nil {
+			return policy, fmt.Errorf("list roles of resource %q: %w", resource, err)
+		}
+		policy.ResourceRoles = append(policy.ResourceRoles, resourceRoles...)
+	}
+
+	assignments, err := permitListAll(func(page, perPage int) ([]permitPolicyAssignment, error) {
+		items, err := c.client.Api.RoleAssignments.List(ctx, page, perPage, "", "", "")
+		if items == nil {
+			return nil, err
+		}
+		out := make([]permitPolicyAssignment, 0, len(*items))
+		for _, item := range *items {
+			out = append(out, permitPolicyAssignment{User: item.User, Role: item.Role, Tenant: item.Tenant, ResourceInstance: item.GetResourceInstance()})
+		}
+		return out, err
+	})
+	if err != nil {
+		return policy, fmt.Errorf("list role assignments: %w", err)
+	}
+	policy.Assignments = assignments
+
+	tuples, err := permitListAll(func(page, perPage int) ([]permitPolicyTuple, error) {
+		items, err := c.client.Api.RelationshipTuples.List(ctx, page, perPage, "", "", "", "")
+		if items == nil {
+			return nil, err
+		}
+		out := make([]permitPolicyTuple, 0, len(*items))
+		for _, item := range *items {
+			out = append(out, permitPolicyTuple{Subject: item.Subject, Relation: item.Relation, Object: item.Object, Tenant: item.Tenant})
+		}
+		return out, err
+	})
+	if err != nil {
+		return policy, fmt.Errorf("list relationship tuples: %w", err)
+	}
+	policy.Tuples = tuples
+
+	conditionSets, err := c.client.Api.ConditionSets.List(ctx, 1, 1)
+	if err != nil {
+		return policy, fmt.Errorf("list condition sets: %w", err)
+	}
+	policy.HasConditionSets = len(conditionSets) > 0
+	return policy, nil
+}
+
+// permitListAll calls list for each page until a page comes back short.
+func permitListAll[T any](list func(page, perPage int) ([]T, error)) ([]T, error) {
+	var out []T
+	for page := 1; ; page++ {
+		items, err := list(page, permitapi.DefaultPerPageLimit)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, items...)
+		if len(items) < permitapi.DefaultPerPageLimit {
+			return out, nil
+		}
+	}
+}
+
+var _ permitScopeClient = (*permitLocalPDP)(nil)
+var _ permitPolicySource = (*permitSDKScopeClient)(nil)
@@ -0,0 +1,239 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type fakePermitPolicySource struct {
+	policy permitPolicy
+	err    error
+	calls  atomic.Int32
+	// fetching, when set, runs while the policy is being fetched.
+	fetching func()
+}
+
+func (f *fakePermitPolicySource) FetchPolicy(context.Context) (permitPolicy, error) {
+	f.calls.Add(1)
+	if f.fetching != nil {
+		f.fetching()
+	}
+	return f.policy, f.err
+}
+
+func testPermitPolicy() permitPolicy {
+	return permitPolicy{
+		Roles: []permitPolicyRole{
+			{Key: "viewer", Permissions: []string{"document:read"}},
+			{Key: "editor", Permissions: []string{"document:update"}, Extends: []string{"viewer"}},
+		},
+		ResourceRoles: []permitPolicyRole{
+			{Resource: "folder", Key: "owner", Permissions: []string{"read"}, Extends: []string{"member"}},
+			{Resource: "folder", Key: "member", Permissions: []string{"folder:read"}},
+			{Resource: "document", Key: "writer", Permissions: []string{"write"}, GrantedTo: []permitRoleDerivation{
+				{Role: "member", OnResource: "folder", LinkedByRelation: "parent"},
+			}},
+		},
+		Assignments: []permitPolicyAssignment{
+			{User: "alice", Role: "editor", Tenant: "default"},
+			{User: "bob", Role: "viewer", Tenant: "acme"},
+			{User: "carol", Role: "owner", Tenant: "default", ResourceInstance: "folder:finance"},
+		},
+		Tuples: []permitPolicyTuple{
+			{Subject: "folder:finance", Relation: "parent", Object: "document:budget", Tenant: "default"},
+		},
+	}
+}
+
+func TestPermitLocalEvaluator(t *testing.T) {
+	evaluator := newPermitLocalEvaluator(testPermitPolicy())
+	for _, tc := range []struct {
+		user, action, resource, key, tenant string
+		want                                bool
+	}{
+		{"alice", "update", "document", "", "default", true},
+		{"alice", "read", "document", "", "default", true}, // editor extends viewer
+		{"alice", "read", "document", "", "acme", false},
+		{"bob", "read", "document", "any", "acme", true},
+		{"bob", "update", "document", "", "acme", false},
+		{"carol", "read", "folder", "finance", "default", true},   // owner extends member
+		{"carol", "write", "document", "budget", "default", true}, // derived through folder:finance
+		{"carol", "write", "document", "plans", "default", false},
+		{"carol", "read", "folder", "", "default", false},
+	} {
+		got, reason := evaluator.allowed(tc.user, tc.action, tc.resource, tc.key, tc.tenant)
+		if got != tc.want {
+			t.Errorf("allowed(%s, %s, %s:%s, %s) = %v (%s), want %v", tc.user, tc.action, tc.resource, tc.key, tc.tenant, got, reason, tc.want)
+		}
+	}
+}
+
+func TestPermitLocalPDPFallsBackWhenStale(t *testing.T) {
+	ctx := context.Background()
+	source := &fakePermitPolicySource{policy: testPermitPolicy()}
+	remote := &fakePermitScopeClient{allowed: map[string]bool{"alice|delete|document": true}}
+	pdp := newPermitLocalPDP("permit", source, remote, parseLocalPDPConfig(map[string]any{"enabled": true, "interval": "10s"}))
+	if pdp.config.MaxStaleness != 30*time.Second {
+		t.Fatalf("max staleness = %v, want three intervals", pdp.config.MaxStaleness)
+	}
+	now := time.Now()
+	pdp.now = func() time.Time { return now }
+
+	// Before any sync every check goes to the remote PDP.
+	if allowed, err := pdp.Check(ctx, "alice", "delete", "document"); err != nil || !allowed {
+		t.Fatalf("Check before sync = %v, %v; want the remote allow", allowed, err)
+	}
+	if err := pdp.sync(ctx); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if allowed, _ := pdp.Check(ctx, "alice", "delete", "document"); allowed {
+		t.Fatal("Check with fresh data reached the remote PDP")
+	}
+	if allowed, _ := pdp.Check(ctx, "alice", "update", "document"); !allowed {
+		t.Fatal("Check with fresh data did not allow alice's editor role")
+	}
+
+	now = now.Add(31 * time.Second)
+	if allowed, _ := pdp.Check(ctx, "alice", "delete", "document"); !allowed {
+		t.Fatal("Check with stale data did not go to the remote PDP")
+	}
+
+	// A failed sync keeps the data stale.
+	source.err = errors.New("permit down")
+	if err := pdp.sync(ctx); err == nil {
+		t.Fatal("sync succeeded despite the source error")
+	}
+	if _, _, ok := pdp.check("alice", "update", "document", "", ""); ok {
+		t.Fatal("check answered locally after a failed sync")
+	}
+
+	// A write through the local PDP marks the data stale until the next sync.
+	source.err = nil
+	if err := pdp.sync(ctx); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if err := pdp.AssignRole(ctx, "dave", "editor", "default"); err != nil {
+		t.Fatalf("AssignRole: %v", err)
+	}
+	if !remote.assignments["dave"]["editor"] {
+		t.Fatal("AssignRole did not reach the remote client")
+	}
+	if _, _, ok := pdp.check("alice", "update", "document", "", ""); ok {
+		t.Fatal("check answered locally after a write")
+	}
+}
+
+func TestPermitLocalPDPSyncKeepsNewerInvalidation(t *testing.T) {
+	ctx := context.Background()
+	source := &fakePermitPolicySource{policy: testPermitPolicy()}
+	pdp := newPermitLocalPDP("permit", source, &fakePermitScopeClient{}, parseLocalPDPConfig(map[string]any{"enabled": true}))
+	source.fetching = pdp.invalidate
+	if err := pdp.sync(ctx); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if _, _, ok := pdp.check("alice", "update", "document", "", ""); ok {
+		t.Fatal("a sync fetched before an invalidation was installed as fresh")
+	}
+	source.fetching = nil
+	if err := pdp.sync(ctx); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if _, _, ok := pdp.check("alice", "update", "document", "", ""); !ok {
+		t.Fatal("check did not answer locally after a clean sync")
+	}
+}
+
+func TestPermitLocalPDPDefersConditionSetsToRemote(t *testing.T) {
+	ctx := context.Background()
+	policy := testPermitPolicy()
+	policy.HasConditionSets = true
+	remote := &fakePermitScopeClient{allowed: map[string]bool{"bob|update|document": true}}
+	pdp := newPermitLocalPDP("permit", &fakePermitPolicySource{policy: policy}, remote, parseLocalPDPConfig(map[string]any{"enabled": true}))
+	if err := pdp.sync(ctx); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if allowed, err := pdp.Check(ctx, "bob", "update", "document"); err != nil || !allowed {
+		t.Fatalf("Check with condition sets = %v, %v; want the remote allow", allowed, err)
+	}
+}
+
+func TestPermitClientWritesInvalidateLocalPDP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	pdp := newPermitLocalPDP("permit", &fakePermitPolicySource{policy: testPermitPolicy()}, &fakePermitScopeClient{}, parseLocalPDPConfig(map[string]any{"enabled": true}))
+	if err := pdp.sync(context.Background()); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	client := &permitClient{httpClient: server.Client(), apiURL: server.URL, localPDP: pdp}
+	if _, err := client.doAPI(context.Background(), http.MethodGet, "/v2/facts/p/e/users", nil); err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	if _, _, ok := pdp.check("alice", "update", "document", "", ""); !ok {
+		t.Fatal("a read invalidated the local PDP")
+	}
+	if _, err := client.doAPI(context.Background(), http.MethodDelete, "/v2/facts/p/e/role_assignments", map[string]any{"user": "alice"}); err != nil {
+		t.Fatalf("DELETE: %v", err)
+	}
+	if _, _, ok := pdp.check("alice", "update", "document", "", ""); ok {
+		t.Fatal("check answered locally after a write through the Permit client")
+	}
+}
+
+func TestPermitLocalPDPPollsAndStops(t *testing.T) {
+	source := &fakePermitPolicySource{policy: testPermitPolicy()}
+	pdp := newPermitLocalPDP("permit", source, &fakePermitScopeClient{}, parseLocalPDPConfig(map[string]any{"enabled": true, "interval": "5ms"}))
+	pdp.start(context.Background())
+	deadline := time.Now().Add(2 * time.Second)
+	for source.calls.Load() < 3 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	pdp.stop()
+	if source.calls.Load() < 3 {
+		t.Fatalf("policy fetched %d times, want at least 3", source.calls.Load())
+	}
+}
+
+func TestPermitCheckStepUsesLocalPDP(t *testing.T) {
+	source := &fakePermitPolicySource{policy: testPermitPolicy()}
+	pdp := newPermitLocalPDP("permit-local", source, &fakePermitScopeClient{}, parseLocalPDPConfig(map[string]any{"enabled": true}))
+	if err := pdp.sync(context.Background()); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	RegisterPermitClient("permit-local", &permitClient{localPDP: pdp})
+	defer UnregisterPermitClient("permit-local")
+
+	step, err := newPermitCheckStep("check", map[string]any{"module": "permit-local"})
+	if err != nil {
+		t.Fatalf("newPermitCheckStep: %v", err)
+	}
+	result, err := step.Execute(context.Background(), nil, nil, map[string]any{
+		"user": "carol", "action": "write", "resource_type": "document", "resource_key": "budget", "tenant": "default",
+	}, nil, map[string]any{})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if result.Output["allow"] != true || result.Output["source"] != "local" {
+		t.Fatalf("output = %#v, want a local allow", result.Output)
+	}
+}
+
+func TestPermitModuleLocalPDPConfig(t *testing.T) {
+	module, err := newPermitModule("permit-cfg", map[string]any{
+		"apiKey":    "key",
+		"local_pdp": map[string]any{"enabled": true, "interval": "1m", "max_staleness": "90s"},
+	})
+	if err != nil {
+		t.Fatalf("newPermitModule: %v", err)
+	}
+	want := localPDPConfig{Enabled: true, Interval: time.Minute, MaxStaleness: 90 * time.Second}
+	if module.config.LocalPDP != want {
+		t.Fatalf("local pdp config = %#v, want %#v", module.config.LocalPDP, want)
+	}
+}
@@ -0,0 +1,154 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+const (
+	// permitDefaultPerPage matches Permit's own default page size.
+	permitDefaultPerPage = 30
+	// permitMaxPerPage is the largest page Permit serves; all_pages uses it
+	// unless per_page is set.
+	permitMaxPerPage = 100
+	// permitDefaultMaxPages caps how many pages all_pages follows.
+	permitDefaultMaxPages = 100
+)
+
+// permitListOptions are the pagination settings of a Permit list step:
+//
+//	page:       first page to fetch, starting at 1 (default 1)
+//	per_page:   page size, at most 100 (default 30, or 100 with all_pages)
+//	page_token: next_page_token from an earlier run; overrides page
+//	all_pages:  follow pages until the last one or max_pages
+//	max_pages:  safety cap for all_pages (default 100)
+type permitListOptions struct {
+	Page     int
+	PerPage  int
+	AllPages bool
+	MaxPages int
+}
+
+func parsePermitListOptions(current, config map[string]any) (permitListOptions, error) {
+	value := func(key string) any {
+		if v, ok := current[key]; ok && v != nil && v != "" {
+			return v
+		}
+		return config[key]
+	}
+	opts := permitListOptions{AllPages: permitBool(value("all_pages"))}
+	var err error
+	if opts.Page, err = permitInt(value("page"), 1); err != nil {
+		return opts, fmt.Errorf("page: %w", err)
+	}
+	if token := stringValue(value("page_token")); token != "" {
+		if opts.Page, err = strconv.Atoi(token); err != nil {
+			return opts, fmt.Errorf("page_token %q is not valid", token)
+		}
+	}
+	defaultPerPage := permitDefaultPerPage
+	if opts.AllPages {
+		defaultPerPage = permitMaxPerPage
+	}
+	if opts.PerPage, err = permitInt(value("per_page"), defaultPerPage); err != nil {
+		return opts, fmt.Errorf("per_page: %w", err)
+	}
+	if opts.MaxPages, err = permitInt(value("max_pages"), permitDefaultMaxPages); err != nil {
+		return opts, fmt.Errorf("max_pages: %w", err)
+	}
+	switch {
+	case opts.Page < 1:
+		return opts, fmt.Errorf("page must be at least 1")
+	case opts.PerPage < 1 || opts.PerPage > permitMaxPerPage:
+		return opts, fmt.Errorf("per_page must be between 1 and %d", permitMaxPerPage)
+	case opts.MaxPages < 1:
+		return opts, fmt.Errorf("max_pages must be at least 1")
+	}
+	return opts, nil
+}
+
+// listPages fetches the pages opts asks for from the management API list at
+// path and returns the step output, with the items under key. The output has
+// the page numbers fetched, total_count when it is known, and
+// next_page_token, empty once the last page was read. truncated is set when
+// all_pages stopped at max_pages.
+func (c *permitClient) listPages(ctx context.Context, path, key string, opts permitListOptions) (map[string]any, error) {
+	items := []any{}
+	totalCount := -1
+	page, fetched, nextPage := opts.Page, 0, 0
+	for {
+		query := url.Values{"page": {strconv.Itoa(page)}, "per_page": {strconv.Itoa(opts.PerPage)}}
+		respBytes, err := c.doRequestBytes(ctx, c.apiURL, http.MethodGet, path+"?"+query.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+		result, err := decodePermitPage(respBytes)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, result.Items...)
+		totalCount = result.TotalCount
+		fetched++
+		if !result.hasNext(page, opts.PerPage) {
+			break
+		}
+		page++
+		if !opts.AllPages || fetched >= opts.MaxPages {
+			nextPage = page
+			break
+		}
+	}
+	if totalCount < 0 && nextPage == 0 {
+		totalCount = (opts.Page-1)*opts.PerPage + len(items)
+	}
+	out := map[string]any{
+		key:               items,
+		"page":            opts.Page,
+		"per_page":        opts.PerPage,
+		"pages_fetched":   fetched,
+		"next_page_token": "",
+	}
+	if totalCount >= 0 {
+		out["total_count"] = totalCount
+	}
+	if nextPage > 0 {
+		out["next_page_token"] = strconv.Itoa(nextPage)
+		out["truncated"] = opts.AllPages
+	}
+	return out, nil
+}
+
+// permitInt reads an integer that may arrive as a number or, after template
+// rendering, as a string.
+func permitInt(value any, fallback int) (int, error) {
+	switch v := value.(type) {
+	case nil:
+		return fallback, nil
+	case string:
+		if v == "" {
+			return fallback, nil
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return 0, fmt.Errorf("%q is not a number", v)
+		}
+		return n, nil
+	case int, int32, int64, float64:
+		return intValue(v), nil
+	default:
+		return 0, fmt.Errorf("%v is not a number", v)
+	}
+}
+
+// permitBool reads a boolean that may arrive as a string after template
+// rendering.
+func permitBool(value any) bool {
+	if s, ok := value.(string); ok {
+		b, _ := strconv.ParseBool(s)
+		return b
+	}
+	return boolValue(value)
+}
@@ -0,0 +1,76 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// servePermitList serves n items from a list endpoint, as a paginated
+// {"data": ...} object when paginated is set and as a bare array otherwise.
+func servePermitList(n int, paginated bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+		items := []any{}
+		for i := (page - 1) * perPage; i < page*perPage && i < n; i++ {
+			items = append(items, map[string]any{"key": fmt.Sprintf("item-%d", i)})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if paginated {
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": items, "total_count": n, "page_count": (n + perPage - 1) / perPage})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(items)
+	}
+}
+
+func TestPermitListStepsPaginate(t *testing.T) {
+	newTestPermitPDP(t, "permit-users", servePermitList(250, true))
+	newTestPermitPDP(t, "permit-tuples", servePermitList(250, false))
+
+	// One page by default, with a token for the next one.
+	result, err := executePermitStep(t, "step.permit_user_list", map[string]any{"module": "permit-users"}, nil)
+	if err != nil {
+		t.Fatalf("user list: %v", err)
+	}
+	if users := result.Output["users"].([]any); len(users) != 30 || result.Output["total_count"] != 250 || result.Output["next_page_token"] != "2" {
+		t.Fatalf("first page output = %d users, total %v, token %v", len(users), result.Output["total_count"], result.Output["next_page_token"])
+	}
+	result, err = executePermitStep(t, "step.permit_user_list", map[string]any{"module": "permit-users", "per_page": "100"}, map[string]any{"page_token": "3"})
+	if err != nil {
+		t.Fatalf("user list page 3: %v", err)
+	}
+	if users := result.Output["users"].([]any); len(users) != 50 || result.Output["next_page_token"] != "" {
+		t.Fatalf("last page output = %d users, token %v", len(users), result.Output["next_page_token"])
+	}
+
+	// all_pages follows bare-array pages until a short page.
+	result, err = executePermitStep(t, "step.permit_relationship_tuple_list", map[string]any{"module": "permit-tuples", "all_pages": true}, nil)
+	if err != nil {
+		t.Fatalf("tuple list: %v", err)
+	}
+	if tuples := result.Output["relationship_tuples"].([]any); len(tuples) != 250 || result.Output["total_count"] != 250 || result.Output["pages_fetched"] != 3 {
+		t.Fatalf("all pages output = %d tuples, total %v, pages %v", len(tuples), result.Output["total_count"], result.Output["pages_fetched"])
+	}
+
+	// max_pages stops early and leaves a token to resume from.
+	result, err = executePermitStep(t, "step.permit_relationship_tuple_list", map[string]any{"module": "permit-tuples", "all_pages": true, "max_pages": 2}, nil)
+	if err != nil {
+		t.Fatalf("capped tuple list: %v", err)
+	}
+	if tuples := result.Output["relationship_tuples"].([]any); len(tuples) != 200 || result.Output["truncated"] != true || result.Output["next_page_token"] != "3" {
+		t.Fatalf("capped output = %d tuples, truncated %v, token %v", len(tuples), result.Output["truncated"], result.Output["next_page_token"])
+	}
+	if _, ok := result.Output["total_count"]; ok {
+		t.Fatal("total_count reported for a truncated bare-array listing")
+	}
+
+	_, err = executePermitStep(t, "step.permit_role_assignment_list", map[string]any{"module": "permit-tuples", "per_page": 500}, nil)
+	if err == nil || !strings.Contains(err.Error(), "per_page") {
+		t.Fatalf("per_page 500 = %v, want a per_page error", err)
+	}
+}
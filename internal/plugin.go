@@ -21,7 +21,7 @@ var Version = "0.0.0"
 // authzPlugin implements sdk.PluginProvider, sdk.ModuleProvider, and sdk.StepProvider.
 type authzPlugin struct{}
 
-var moduleTypes = []string{"authz.casbin", "permit.provider", "authz.keto", "authz.scope_catalog"}
+var moduleTypes = []string{"authz.casbin", "permit.provider", "authz.keto", "authz.scope_catalog", "authz.audit", "authz.composite", "authz.opa", "authz.cedar"}
 
 var casbinStepTypes = []string{
 	"step.authz_check",
@@ -41,6 +41,8 @@ var casbinStepTypes = []string{
 	"step.authz_rebac_remove_relation",
 	"step.authz_rebac_check",
 	"step.authz_rebac_list_relations",
+	"step.authz_simulate",
+	"step.authz_subject_projection",
 }
 
 // NewAuthzPlugin returns a new authzPlugin instance.
@@ -94,7 +96,32 @@ func (p *authzPlugin) CreateModule(typeName, name string, config map[string]any)
 		RegisterAuthzProvider(name, m)
 		return m, nil
 	case "authz.scope_catalog":
-		return newScopeCatalogModule(name, config), nil
+		m := newScopeCatalogModule(name, config)
+		registerScopeCatalog(m)
+		return m, nil
+	case "authz.audit":
+		return newAuditModule(name, config)
+	case "authz.composite":
+		m, err := newCompositeModule(name, config)
+		if err != nil {
+			return nil, err
+		}
+		RegisterAuthzProvider(name, m)
+		return m, nil
+	case "authz.opa":
+		m, err := newOPAModule(name, config)
+		if err != nil {
+			return nil, err
+		}
+		RegisterAuthzProvider(name, m)
+		return m, nil
+	case "authz.cedar":
+		m, err := newCedarModule(name, config)
+		if err != nil {
+			return nil, err
+		}
+		RegisterAuthzProvider(name, m)
+		return m, nil
 	default:
 		return nil, fmt.Errorf("authz plugin: unknown module type %q", typeName)
 	}
@@ -135,7 +162,44 @@ func (p *authzPlugin) CreateTypedModule(typeName, name string, config *anypb.Any
 		return factory.CreateTypedModule(typeName, name, config)
 	case "authz.scope_catalog":
 		factory := sdk.NewTypedModuleFactory(typeName, &contracts.ScopeCatalogConfig{}, func(name string, cfg *contracts.ScopeCatalogConfig) (sdk.ModuleInstance, error) {
-			return newScopeCatalogModule(name, scopeCatalogConfigToMap(cfg)), nil
+			m := newScopeCatalogModule(name, scopeCatalogConfigToMap(cfg))
+			registerScopeCatalog(m)
+			return m, nil
+		})
+		return factory.CreateTypedModule(typeName, name, config)
+	case "authz.audit":
+		factory := sdk.NewTypedModuleFactory(typeName, &contracts.AuditModuleConfig{}, func(name string, cfg *contracts.AuditModuleConfig) (sdk.ModuleInstance, error) {
+			return newAuditModule(name, auditModuleConfigToMap(cfg))
+		})
+		return factory.CreateTypedModule(typeName, name, config)
+	case "authz.composite":
+		factory := sdk.NewTypedModuleFactory(typeName, &contracts.CompositeModuleConfig{}, func(name string, cfg *contracts.CompositeModuleConfig) (sdk.ModuleInstance, error) {
+			m, err := newCompositeModule(name, compositeModuleConfigToMap(cfg))
+			if err != nil {
+				return nil, err
+			}
+			RegisterAuthzProvider(name, m)
+			return m, nil
+		})
+		return factory.CreateTypedModule(typeName, name, config)
+	case "authz.opa":
+		factory := sdk.NewTypedModuleFactory(typeName, &contracts.OPAModuleConfig{}, func(name string, cfg *contracts.OPAModuleConfig) (sdk.ModuleInstance, error) {
+			m, err := newOPAModule(name, opaModuleConfigToMap(cfg))
+			if err != nil {
+				return nil, err
+			}
+			RegisterAuthzProvider(name, m)
+			return m, nil
+		})
+		return factory.CreateTypedModule(typeName, name, config)
+	case "authz.cedar":
+		factory := sdk.NewTypedModuleFactory(typeName, &contracts.CedarModuleConfig{}, func(name string, cfg *contracts.CedarModuleConfig) (sdk.ModuleInstance, error) {
+			m, err := newCedarModule(name, cedarModuleConfigToMap(cfg))
+			if err != nil {
+				return nil, err
+			}
+			RegisterAuthzProvider(name, m)
+			return m, nil
 		})
 		return factory.CreateTypedModule(typeName, name, config)
 	default:
@@ -194,10 +258,14 @@ func (p *authzPlugin) CreateStep(typeName, name string, config map[string]any) (
 		return newAuthzReBACCheckStep(name, config)
 	case "step.authz_rebac_list_relations":
 		return newAuthzReBACListRelationsStep(name, config)
+	case "step.authz_simulate":
+		return newAuthzSimulateStep(name, config)
+	case "step.authz_subject_projection":
+		return newAuthzSubjectProjectionStep(name, config)
 	default:
 		// Delegate to permit step registry for all step.permit_* types.
-		if step, err := createPermitStep(typeName, name, config); err == nil {
-			return step, nil
+		if isPermitStepType(typeName) {
+			return createPermitStep(typeName, name, config)
 		}
 		return nil, fmt.Errorf("authz plugin: unknown step type %q", typeName)
 	}
@@ -240,6 +308,10 @@ func (p *authzPlugin) CreateTypedStep(typeName, name string, config *anypb.Any)
 		return sdk.NewTypedStepFactory(typeName, &contracts.SubjectObjectActionConfig{}, &contracts.SubjectObjectActionInput{}, typedSubjectObjectAction(wrapStepConstructor(newAuthzReBACCheckStep), globalRegistry)).CreateTypedStep(typeName, name, config)
 	case "step.authz_rebac_list_relations":
 		return sdk.NewTypedStepFactory(typeName, &contracts.ListConfig{}, &contracts.ListInput{}, typedList(wrapStepConstructor(newAuthzReBACListRelationsStep), globalRegistry)).CreateTypedStep(typeName, name, config)
+	case "step.authz_simulate":
+		return sdk.NewTypedStepFactory(typeName, &contracts.SimulateConfig{}, &contracts.SimulateInput{}, typedAuthzSimulate(globalRegistry)).CreateTypedStep(typeName, name, config)
+	case "step.authz_subject_projection":
+		return sdk.NewTypedStepFactory(typeName, &contracts.SubjectProjectionConfig{}, &contracts.SubjectProjectionInput{}, typedAuthzSubjectProjection(globalRegistry)).CreateTypedStep(typeName, name, config)
 	default:
 		if isPermitStepType(typeName) {
 			return sdk.NewTypedStepFactory(typeName, &contracts.PermitStepConfig{}, &contracts.PermitStepInput{}, typedPermitStep(typeName)).CreateTypedStep(typeName, name, config)
@@ -255,6 +327,10 @@ func (p *authzPlugin) ContractRegistry() *pb.ContractRegistry {
 		moduleContract("permit.provider", "PermitModuleConfig"),
 		moduleContract("authz.keto", "KetoModuleConfig"),
 		moduleContract("authz.scope_catalog", "ScopeCatalogConfig"),
+		moduleContract("authz.audit", "AuditModuleConfig"),
+		moduleContract("authz.composite", "CompositeModuleConfig"),
+		moduleContract("authz.opa", "OPAModuleConfig"),
+		moduleContract("authz.cedar", "CedarModuleConfig"),
 		stepContract("step.authz_check", "AuthorizationDecisionConfig", "AuthorizationDecisionInput", "AuthorizationDecisionOutput"),
 		stepContract("step.authz_require_capabilities", "RequireCapabilitiesConfig", "RequireCapabilitiesInput", "ProviderCapabilitiesOutput"),
 		stepContract("step.authz_check_casbin", "AuthzCheckConfig", "AuthzCheckInput", "AuthzCheckOutput"),
@@ -272,12 +348,17 @@ func (p *authzPlugin) ContractRegistry() *pb.ContractRegistry {
 		stepContract("step.authz_rebac_remove_relation", "RelationConfig", "RelationInput", "RelationOutput"),
 		stepContract("step.authz_rebac_check", "SubjectObjectActionConfig", "SubjectObjectActionInput", "SubjectObjectActionOutput"),
 		stepContract("step.authz_rebac_list_relations", "ListConfig", "ListInput", "GenericStepOutput"),
+		stepContract("step.authz_simulate", "SimulateConfig", "SimulateInput", "SimulateOutput"),
+		stepContract("step.authz_subject_projection", "SubjectProjectionConfig", "SubjectProjectionInput", "ResolveSubjectProjectionOutput"),
 		serviceContract("authz.scope_catalog", "ScopeCatalog", "RegisterScopes", "RegisterScopesInput", "RegisterScopesOutput"),
 		serviceContract("authz.scope_catalog", "ScopeCatalog", "ListScopes", "ListScopesInput", "ListScopesOutput"),
 		serviceContract("authz.scope_catalog", "ScopeCatalog", "ResolveSubjectScopes", "ResolveSubjectScopesInput", "ResolveSubjectScopesOutput"),
 		serviceContract("authz.scope_catalog", "ScopeCatalog", "RegisterDeclarations", "RegisterDeclarationsInput", "RegisterDeclarationsOutput"),
+		serviceContract("authz.scope_catalog", "ScopeCatalog", "UnregisterDeclarations", "UnregisterDeclarationsInput", "UnregisterDeclarationsOutput"),
+		serviceContract("authz.scope_catalog", "ScopeCatalog", "MigrateScopes", "MigrateScopesInput", "MigrateScopesOutput"),
 		serviceContract("authz.scope_catalog", "ScopeCatalog", "ListDeclarations", "ListDeclarationsInput", "ListDeclarationsOutput"),
 		serviceContract("authz.scope_catalog", "ScopeCatalog", "ResolveProjectionInputs", "ResolveProjectionInputsInput", "ResolveProjectionInputsOutput"),
+		serviceContract("authz.scope_catalog", "ScopeCatalog", "ResolveSubjectProjection", "ResolveSubjectProjectionInput", "ResolveSubjectProjectionOutput"),
 		serviceContract("authz.casbin", "ProviderCapabilities", "GetCapabilities", "ProviderCapabilitiesInput", "ProviderCapabilitiesOutput"),
 		serviceContract("authz.casbin", "ProviderCapabilities", "RequireCapabilities", "ProviderCapabilitiesInput", "ProviderCapabilitiesOutput"),
 		serviceContract("authz.casbin", "AttributePolicyProvider", "DeclareAttributes", "DeclareAttributesInput", "DeclareAttributesOutput"),
@@ -295,6 +376,15 @@ func (p *authzPlugin) ContractRegistry() *pb.ContractRegistry {
 		serviceContract("authz.casbin", "ScopeRoleProvider", "ListAssignments", "ListRoleAssignmentsInput", "ListRoleAssignmentsOutput"),
 		serviceContract("authz.casbin", "ScopeRoleProvider", "RemoveAssignment", "RemoveRoleAssignmentInput", "RemoveRoleAssignmentOutput"),
 		serviceContract("authz.casbin", "ScopeRoleProvider", "CheckScope", "ScopeCheckInput", "ScopeCheckOutput"),
+		serviceContract("authz.casbin", "AuthzSimulator", "Simulate", "SimulateInput", "SimulateOutput"),
+		serviceContract("authz.keto", "RelationshipExpander", "ExpandRelation", "ExpandRelationInput", "ExpandRelationOutput"),
+		serviceContract("authz.keto", "NamespaceGenerator", "GenerateNamespaces", "GenerateNamespacesInput", "GenerateNamespacesOutput"),
+		serviceContract("authz.audit", "AuditLog", "ListAuditEntries", "ListAuditEntriesInput", "ListAuditEntriesOutput"),
+		serviceContract("authz.audit", "AuditLog", "VerifyAuditChain", "VerifyAuditChainInput", "VerifyAuditChainOutput"),
+		serviceContract("authz.composite", "AuthorizationDecider", "Decide", "AuthorizationDecisionInput", "AuthorizationDecisionOutput"),
+		serviceContract("authz.opa", "PolicyData", "PutData", "OPADataInput", "OPADataOutput"),
+		serviceContract("authz.opa", "PolicyData", "RemoveData", "OPADataInput", "OPADataOutput"),
+		serviceContract("authz.cedar", "SchemaGenerator", "GenerateSchema", "CedarSchemaInput", "CedarSchemaOutput"),
 	}
 	for _, stepType := range permitStepTypes() {
 		contractsList = append(contractsList, stepContract(stepType, "PermitStepConfig", "PermitStepInput", "GenericStepOutput"))
@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/GoCodeAlone/workflow-plugin-authz/internal/contracts"
@@ -15,10 +17,19 @@ type ketoClient interface {
 	CreateRelationship(context.Context, ketoTuple) error
 	DeleteRelationship(context.Context, ketoTuple) error
 	Check(context.Context, ketoTuple) (bool, error)
+	// ListRelationships returns every tuple matching query, following
+	// pagination. Empty query fields match anything.
+	ListRelationships(ctx context.Context, query ketoTuple) ([]ketoTuple, error)
+	// Expand returns the tree of subjects holding the relation of
+	// set.Namespace:set.Object, following subject sets up to maxDepth.
+	Expand(ctx context.Context, set ketoSubjectSet, maxDepth int) (UsersetTree, error)
 }
 
 type ketoScopeProvider struct {
-	name      string
+	name string
+	// mu serializes writes with Reconcile, so a reconcile cannot drop a
+	// write made while it was listing tuples.
+	mu        sync.Mutex
 	store     *scopeRoleStore
 	relations *relationTupleStore
 	client    ketoClient
@@ -49,6 +60,10 @@ func (p *ketoScopeProvider) DeclareScopes(ctx context.Context, scopes []*contrac
 }
 
 func (p *ketoScopeProvider) UpsertRole(ctx context.Context, grant RoleScopeGrant) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	grant = normalizeRoleScopeGrant(grant)
+	previous := p.store.roleScopes(grant.Context, grant.Role)
 	if err := p.store.UpsertRole(ctx, grant); err != nil {
 		return err
 	}
@@ -57,10 +72,25 @@ func (p *ketoScopeProvider) UpsertRole(ctx context.Context, grant RoleScopeGrant
 			return err
 		}
 	}
+	// Scopes the role no longer grants, such as the old names MigrateScopes
+	// rewrites, would otherwise stay granted in Keto.
+	for _, scope := range previous {
+		if slices.Contains(grant.Scopes, scope) {
+			continue
+		}
+		if err := p.client.DeleteRelationship(ctx, ketoRoleScopeTuple(grant.Context, grant.Role, scope)); err != nil {
+			return err
+		}
+	}
+	publishChange(p.name, "keto", ChangeRoleUpserted, roleScopeGrantToMap(grant))
 	return nil
 }
 
 func (p *ketoScopeProvider) AssignRole(ctx context.Context, assignment SubjectRoleAssignment) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	assignment = normalizeSubjectRoleAssignment(assignment)
+	previous := p.directScopes(assignment)
 	if err := p.store.AssignRole(ctx, assignment); err != nil {
 		return err
 	}
@@ -74,19 +104,92 @@ func (p *ketoScopeProvider) AssignRole(ctx context.Context, assignment SubjectRo
 			return err
 		}
 	}
+	if err := p.deleteUnusedDirectScopes(ctx, assignment.Subject, previous); err != nil {
+		return err
+	}
+	publishChange(p.name, "keto", ChangeRoleAssigned, subjectRoleAssignmentToMap(assignment))
 	return nil
 }
 
+// ListAssignments reads the assignments from Keto, so it includes those made
+// by other replicas since the last reconcile.
 func (p *ketoScopeProvider) ListAssignments(ctx context.Context, filter AssignmentFilter) ([]SubjectRoleAssignment, error) {
-	return p.store.ListAssignments(ctx, filter)
+	filter = AssignmentFilter{Subject: strings.TrimSpace(filter.Subject), Role: strings.TrimSpace(filter.Role), Context: strings.TrimSpace(filter.Context)}
+	roleQuery := ketoTuple{Namespace: "role", Relation: "member", SubjectID: filter.Subject}
+	if filter.Role != "" && filter.Context != "" {
+		roleQuery.Object = ketoRoleObject(filter.Context, filter.Role)
+	}
+	tuples, err := p.client.ListRelationships(ctx, roleQuery)
+	if err != nil {
+		return nil, err
+	}
+	direct, err := p.client.ListRelationships(ctx, ketoTuple{Namespace: "scope", Relation: "granted", SubjectID: filter.Subject})
+	if err != nil {
+		return nil, err
+	}
+	out := []SubjectRoleAssignment{}
+	for _, assignment := range decodeKetoTuples(append(tuples, direct...), p.store.scopeContext).assigns {
+		if (filter.Subject == "" || assignment.Subject == filter.Subject) &&
+			(filter.Role == "" || assignment.Role == filter.Role) &&
+			(filter.Context == "" || assignment.Context == filter.Context) {
+			out = append(out, assignment)
+		}
+	}
+	return out, nil
 }
 
 func (p *ketoScopeProvider) RemoveAssignment(ctx context.Context, assignment SubjectRoleAssignment) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	assignment = normalizeSubjectRoleAssignment(assignment)
+	removed := p.directScopes(assignment)
 	if err := p.store.RemoveAssignment(ctx, assignment); err != nil {
 		return err
 	}
 	if assignment.Role != "" {
-		return p.client.DeleteRelationship(ctx, ketoSubjectRoleTuple(assignment.Context, assignment.Role, assignment.Subject))
+		if err := p.client.DeleteRelationship(ctx, ketoSubjectRoleTuple(assignment.Context, assignment.Role, assignment.Subject)); err != nil {
+			return err
+		}
+	}
+	if err := p.deleteUnusedDirectScopes(ctx, assignment.Subject, removed); err != nil {
+		return err
+	}
+	publishChange(p.name, "keto", ChangeRoleUnassigned, subjectRoleAssignmentToMap(assignment))
+	return nil
+}
+
+// deleteUnusedDirectScopes deletes the direct scope tuples of subject for
+// scopes. Direct scope tuples are shared by the subject's assignments, so only
+// those no stored assignment still grants are deleted.
+func (p *ketoScopeProvider) deleteUnusedDirectScopes(ctx context.Context, subject string, scopes []string) error {
+	remaining := map[string]bool{}
+	_, assigns := p.store.grants()
+	for _, other := range assigns {
+		if other.Subject == subject {
+			for _, scope := range other.DirectScopes {
+				remaining[scope] = true
+			}
+		}
+	}
+	for _, scope := range scopes {
+		if remaining[scope] {
+			continue
+		}
+		if err := p.client.DeleteRelationship(ctx, ketoDirectScopeTuple(subject, scope)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// directScopes returns the direct scopes of the stored assignment with the
+// identity of target.
+func (p *ketoScopeProvider) directScopes(target SubjectRoleAssignment) []string {
+	_, assigns := p.store.grants()
+	for _, assignment := range assigns {
+		if sameAssignmentIdentity(assignment, target) {
+			return assignment.DirectScopes
+		}
 	}
 	return nil
 }
@@ -103,17 +206,39 @@ func (p *ketoScopeProvider) CheckScope(ctx context.Context, check ScopeCheck) (S
 	if err != nil {
 		return result, err
 	}
+	if !local.Allowed && local.Reason != reasonNoScopeGrant {
+		local.Provider = p.store.provider
+		return local, nil
+	}
 	if !local.Allowed {
+		// Grants written by another replica since the last reconcile are only
+		// in Keto, so a local miss is checked there before it is denied.
+		allowed, err := p.client.Check(ctx, ketoDirectScopeTuple(result.Subject, local.Scope))
+		if err != nil {
+			return result, err
+		}
 		local.Provider = p.store.provider
+		if allowed {
+			local.Allowed = true
+			local.Reason = ""
+			local.MatchedScopes = []string{local.Scope}
+		}
 		return local, nil
 	}
-	allowed, err := p.client.Check(ctx, ketoDirectScopeTuple(result.Subject, scopeName))
+	// Role and direct grants are written to Keto as the granted name, which
+	// may be a wildcard pattern, so check against the grant that matched.
+	granted := scopeName
+	if len(local.MatchedScopes) > 0 {
+		granted = local.MatchedScopes[0]
+	}
+	allowed, err := p.client.Check(ctx, ketoDirectScopeTuple(result.Subject, granted))
 	if err != nil {
 		return result, err
 	}
 	result.Allowed = allowed
 	result.MatchedRole = local.MatchedRole
 	result.MatchedScopes = local.MatchedScopes
+	result.Deprecations = local.Deprecations
 	if !allowed {
 		result.Reason = "keto denied"
 	}
@@ -156,27 +281,59 @@ func ketoDirectScopeTuple(subject, scope string) ketoTuple {
 }
 
 func (p *ketoScopeProvider) UpsertRelationTuple(ctx context.Context, tuple RelationTuple) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
 	if err := p.relations.Upsert(tuple); err != nil {
 		return err
 	}
-	return p.client.CreateRelationship(ctx, ketoRelationshipTuple(tuple))
+	if err := p.client.CreateRelationship(ctx, ketoRelationshipTuple(tuple)); err != nil {
+		return err
+	}
+	publishChange(p.name, "keto", ChangeRelationWritten, relationTupleToMap(tuple))
+	return nil
 }
 
 func (p *ketoScopeProvider) RemoveRelationTuple(ctx context.Context, tuple RelationTuple) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
 	if err := p.relations.Remove(tuple); err != nil {
 		return err
 	}
-	return p.client.DeleteRelationship(ctx, ketoRelationshipTuple(tuple))
+	if err := p.client.DeleteRelationship(ctx, ketoRelationshipTuple(tuple)); err != nil {
+		return err
+	}
+	publishChange(p.name, "keto", ChangeRelationDeleted, relationTupleToMap(tuple))
+	return nil
 }
 
-func (p *ketoScopeProvider) ListRelationTuples(_ context.Context, filter RelationTupleFilter) ([]RelationTuple, error) {
-	return p.relations.List(filter), nil
+// ListRelationTuples reads the tuples from Keto, so it includes those
+// written by other replicas since the last reconcile.
+func (p *ketoScopeProvider) ListRelationTuples(ctx context.Context, filter RelationTupleFilter) ([]RelationTuple, error) {
+	filter = RelationTupleFilter(normalizeRelationTuple(RelationTuple(filter)))
+	query := ketoTuple{Namespace: "resource", Relation: filter.Relation}
+	query.SubjectID, query.SubjectSet = ketoSubject(filter.Subject)
+	if filter.Context != "" && filter.Object != "" {
+		query.Object = ketoResourceObject(filter.Context, filter.Object)
+	}
+	tuples, err := p.client.ListRelationships(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	out := []RelationTuple{}
+	for _, tuple := range decodeKetoTuples(tuples, p.store.scopeContext).relations {
+		if relationTupleMatches(tuple, filter) {
+			out = append(out, tuple)
+		}
+	}
+	return out, nil
 }
 
+// CheckRelation asks Keto whether the subject, or every member of a subject
+// set subject, holds the relation.
 func (p *ketoScopeProvider) CheckRelation(ctx context.Context, check RelationCheck) (RelationCheckResult, error) {
-	tuple := RelationTuple{Subject: check.Subject, Relation: check.Relation, Object: check.Object, Context: check.Context}
-	result := RelationCheckResult{Subject: check.Subject, Relation: check.Relation, Object: check.Object, Context: check.Context}
-	if err := validateRelationTuple(normalizeRelationTuple(tuple)); err != nil {
+	tuple := normalizeRelationTuple(RelationTuple{Subject: check.Subject, Relation: check.Relation, Object: check.Object, Context: check.Context})
+	result := RelationCheckResult{Subject: tuple.Subject, Relation: tuple.Relation, Object: tuple.Object, Context: tuple.Context}
+	if err := validateRelationTuple(tuple); err != nil {
 		result.Reason = err.Error()
 		return result, nil
 	}
@@ -191,14 +348,63 @@ func (p *ketoScopeProvider) CheckRelation(ctx context.Context, check RelationChe
 	return result, nil
 }
 
+// ExpandRelation returns the tree of subjects that hold the relation on the
+// object, as Keto resolves it through subject sets.
+func (p *ketoScopeProvider) ExpandRelation(ctx context.Context, expand RelationExpand) (UsersetTree, error) {
+	expand = normalizeRelationExpand(expand)
+	if expand.Relation == "" || expand.Object == "" || expand.Context == "" {
+		return UsersetTree{}, fmt.Errorf("relation expand requires relation, object, and context")
+	}
+	set := ketoSubjectSet{Namespace: "resource", Object: ketoResourceObject(expand.Context, expand.Object), Relation: expand.Relation}
+	return p.client.Expand(ctx, set, expand.MaxDepth)
+}
+
 func ketoRelationshipTuple(tuple RelationTuple) ketoTuple {
 	tuple = normalizeRelationTuple(tuple)
-	return ketoTuple{
+	out := ketoTuple{
 		Namespace: "resource",
-		Object:    tuple.Context + ":" + tuple.Object,
+		Object:    ketoResourceObject(tuple.Context, tuple.Object),
 		Relation:  tuple.Relation,
-		SubjectID: tuple.Subject,
 	}
+	out.SubjectID, out.SubjectSet = ketoSubject(tuple.Subject)
+	return out
+}
+
+func ketoResourceObject(contextName, object string) string {
+	return contextName + ":" + object
+}
+
+// ketoSubject maps a relation subject onto Keto. A subject written as
+// "context:object#relation", such as "group:eng#member", is the subject set of
+// everyone holding that relation tuple; any other subject is a subject ID.
+func ketoSubject(subject string) (string, *ketoSubjectSet) {
+	object, relation, ok := strings.Cut(subject, "#")
+	if !ok || relation == "" || !strings.Contains(object, ":") {
+		return subject, nil
+	}
+	return "", &ketoSubjectSet{Namespace: "resource", Object: object, Relation: relation}
+}
+
+// ketoSubjectString is the inverse of ketoSubject. Subject sets outside the
+// resource namespace keep their namespace prefix.
+func ketoSubjectString(subjectID string, set *ketoSubjectSet) string {
+	if set == nil {
+		return subjectID
+	}
+	if set.Namespace == "resource" {
+		return set.Object + "#" + set.Relation
+	}
+	return set.Namespace + ":" + set.Object + "#" + set.Relation
+}
+
+// String formats the tuple in Keto's namespace:object#relation@subject
+// notation.
+func (t ketoTuple) String() string {
+	subject := t.SubjectID
+	if t.SubjectSet != nil {
+		subject = t.SubjectSet.Namespace + ":" + t.SubjectSet.Object + "#" + t.SubjectSet.Relation
+	}
+	return t.Namespace + ":" + t.Object + "#" + t.Relation + "@" + subject
 }
 
 func (t ketoTuple) equal(other ketoTuple) bool {
@@ -213,18 +419,31 @@ func (t ketoTuple) equal(other ketoTuple) bool {
 
 type ketoSDKClient struct {
 	relationships keto.RelationshipAPI
+	reader        keto.RelationshipAPI
 	permissions   keto.PermissionAPI
 }
 
-func newKetoSDKClient(readURL, writeURL string) *ketoSDKClient {
+// ketoListPageSize is the page size used when listing relationships.
+const ketoListPageSize = 500
+
+// ketoMaxDepth bounds subject set traversal in checks, and in expansions that
+// do not set their own depth.
+const ketoMaxDepth = 32
+
+// ketoDefaultTimeout bounds each Keto request unless transport.timeout is set.
+const ketoDefaultTimeout = 15 * time.Second
+
+func newKetoSDKClient(readURL, writeURL string, httpClient *http.Client) *ketoSDKClient {
 	readCfg := keto.NewConfiguration()
 	readCfg.Servers = keto.ServerConfigurations{{URL: defaultString(readURL, "http://localhost:4466")}}
-	readCfg.HTTPClient = &http.Client{Timeout: 15 * time.Second}
+	readCfg.HTTPClient = httpClient
 	writeCfg := keto.NewConfiguration()
 	writeCfg.Servers = keto.ServerConfigurations{{URL: defaultString(writeURL, defaultString(readURL, "http://localhost:4467"))}}
-	writeCfg.HTTPClient = &http.Client{Timeout: 15 * time.Second}
+	writeCfg.HTTPClient = httpClient
+	reader := keto.NewAPIClient(readCfg)
 	return &ketoSDKClient{
-		permissions:   keto.NewAPIClient(readCfg).PermissionAPI,
+		permissions:   reader.PermissionAPI,
+		reader:        reader.RelationshipAPI,
 		relationships: keto.NewAPIClient(writeCfg).RelationshipAPI,
 	}
 }
@@ -255,7 +474,12 @@ func (c *ketoSDKClient) DeleteRelationship(ctx context.Context, tuple ketoTuple)
 }
 
 func (c *ketoSDKClient) Check(ctx context.Context, tuple ketoTuple) (bool, error) {
-	req := c.permissions.CheckPermission(ctx).Namespace(tuple.Namespace).Object(tuple.Object).Relation(tuple.Relation).SubjectId(tuple.SubjectID).MaxDepth(32)
+	req := c.permissions.CheckPermission(ctx).Namespace(tuple.Namespace).Object(tuple.Object).Relation(tuple.Relation).MaxDepth(ketoMaxDepth)
+	if tuple.SubjectSet != nil {
+		req = req.SubjectSetNamespace(tuple.SubjectSet.Namespace).SubjectSetObject(tuple.SubjectSet.Object).SubjectSetRelation(tuple.SubjectSet.Relation)
+	} else {
+		req = req.SubjectId(tuple.SubjectID)
+	}
 	result, _, err := req.Execute()
 	if err != nil {
 		return false, err
@@ -266,6 +490,88 @@ func (c *ketoSDKClient) Check(ctx context.Context, tuple ketoTuple) (bool, error
 	return result.GetAllowed(), nil
 }
 
+func (c *ketoSDKClient) ListRelationships(ctx context.Context, query ketoTuple) ([]ketoTuple, error) {
+	var out []ketoTuple
+	token := ""
+	for {
+		req := c.reader.GetRelationships(ctx).PageSize(ketoListPageSize)
+		if token != "" {
+			req = req.PageToken(token)
+		}
+		if query.Namespace != "" {
+			req = req.Namespace(query.Namespace)
+		}
+		if query.Object != "" {
+			req = req.Object(query.Object)
+		}
+		if query.Relation != "" {
+			req = req.Relation(query.Relation)
+		}
+		if query.SubjectID != "" {
+			req = req.SubjectId(query.SubjectID)
+		}
+		if query.SubjectSet != nil {
+			req = req.SubjectSetNamespace(query.SubjectSet.Namespace).SubjectSetObject(query.SubjectSet.Object).SubjectSetRelation(query.SubjectSet.Relation)
+		}
+		page, _, err := req.Execute()
+		if err != nil {
+			return nil, fmt.Errorf("keto sdk: %w", err)
+		}
+		if page == nil {
+			return out, nil
+		}
+		for _, relationship := range page.GetRelationTuples() {
+			tuple := ketoTuple{
+				Namespace: relationship.GetNamespace(),
+				Object:    relationship.GetObject(),
+				Relation:  relationship.GetRelation(),
+				SubjectID: relationship.GetSubjectId(),
+			}
+			if set, ok := relationship.GetSubjectSetOk(); ok && set != nil {
+				tuple.SubjectSet = &ketoSubjectSet{Namespace: set.GetNamespace(), Object: set.GetObject(), Relation: set.GetRelation()}
+			}
+			out = append(out, tuple)
+		}
+		token = page.GetNextPageToken()
+		if token == "" {
+			return out, nil
+		}
+	}
+}
+
+func (c *ketoSDKClient) Expand(ctx context.Context, set ketoSubjectSet, maxDepth int) (UsersetTree, error) {
+	if maxDepth <= 0 {
+		maxDepth = ketoMaxDepth
+	}
+	tree, _, err := c.permissions.ExpandPermissions(ctx).Namespace(set.Namespace).Object(set.Object).Relation(set.Relation).MaxDepth(int64(maxDepth)).Execute()
+	if err != nil {
+		return UsersetTree{}, fmt.Errorf("keto sdk: %w", err)
+	}
+	if tree == nil {
+		return UsersetTree{Type: "union", Subject: ketoSubjectString("", &set)}, nil
+	}
+	return usersetTreeFromKeto(*tree), nil
+}
+
+func usersetTreeFromKeto(node keto.ExpandedPermissionTree) UsersetTree {
+	out := UsersetTree{Type: node.GetType()}
+	if tuple, ok := node.GetTupleOk(); ok && tuple != nil {
+		var set *ketoSubjectSet
+		if subjectSet, ok := tuple.GetSubjectSetOk(); ok && subjectSet != nil {
+			set = &ketoSubjectSet{Namespace: subjectSet.GetNamespace(), Object: subjectSet.GetObject(), Relation: subjectSet.GetRelation()}
+		} else if tuple.GetSubjectId() == "" {
+			// Inner nodes carry the subject set they expand as the tuple's
+			// own namespace, object, and relation.
+			set = &ketoSubjectSet{Namespace: tuple.GetNamespace(), Object: tuple.GetObject(), Relation: tuple.GetRelation()}
+		}
+		out.Subject = ketoSubjectString(tuple.GetSubjectId(), set)
+	}
+	for _, child := range node.GetChildren() {
+		out.Children = append(out.Children, usersetTreeFromKeto(child))
+	}
+	return out
+}
+
 func ignoreKetoConflict(err error) error {
 	if err == nil {
 		return nil
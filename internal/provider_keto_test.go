@@ -2,7 +2,9 @@ package internal
 
 import (
 	"context"
+	"net/http"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/GoCodeAlone/workflow-plugin-authz/internal/contracts"
@@ -54,6 +56,282 @@ func TestKetoProviderWritesRoleScopeTuplesAndChecks(t *testing.T) {
 	}
 }
 
+func TestKetoProviderChecksWildcardGrantTuple(t *testing.T) {
+	ctx := context.Background()
+	client := &fakeKetoClient{checks: map[ketoTuple]bool{}}
+	provider := newKetoScopeProvider("keto", client)
+	if err := provider.DeclareScopes(ctx, []*contracts.ScopeDeclaration{scopeDeclarationFromName("admin:authz.roles:update")}); err != nil {
+		t.Fatalf("DeclareScopes: %v", err)
+	}
+	if err := provider.UpsertRole(ctx, RoleScopeGrant{Role: "authz-admin", Context: "admin", Scopes: []string{"admin:*"}}); err != nil {
+		t.Fatalf("UpsertRole: %v", err)
+	}
+	if err := provider.AssignRole(ctx, SubjectRoleAssignment{Subject: "alice", Role: "authz-admin", Context: "admin"}); err != nil {
+		t.Fatalf("AssignRole: %v", err)
+	}
+
+	// The role-scope tuple is written for the pattern, so Keto resolves the
+	// subject through it rather than through the concrete scope.
+	client.checks[ketoTuple{Namespace: "scope", Object: "admin:*", Relation: "granted", SubjectID: "alice"}] = true
+	result, err := provider.CheckScope(ctx, ScopeCheck{Subject: "alice", Context: "admin", Scope: "admin:authz.roles:update"})
+	if err != nil {
+		t.Fatalf("CheckScope: %v", err)
+	}
+	if !result.Allowed {
+		t.Fatalf("expected wildcard grant to allow, result=%#v", result)
+	}
+}
+
+func TestKetoReconcileRebuildsLocalStateAndReportsDrift(t *testing.T) {
+	ctx := context.Background()
+	client := &fakeKetoClient{checks: map[ketoTuple]bool{}, tuples: []ketoTuple{
+		ketoRoleScopeTuple("billing", "viewer", "billing:invoice:read"),
+		ketoSubjectRoleTuple("billing", "viewer", "alice"),
+		ketoDirectScopeTuple("alice", "billing:invoice:update"),
+		ketoRelationshipTuple(RelationTuple{Subject: "alice", Relation: "owner", Object: "doc1", Context: "docs"}),
+		{Namespace: "other", Object: "x", Relation: "y", SubjectID: "z"},
+	}}
+	provider := newKetoScopeProvider("keto", client)
+	if err := provider.DeclareScopes(ctx, []*contracts.ScopeDeclaration{
+		scopeDeclarationFromName("billing:invoice:read"),
+		scopeDeclarationFromName("billing:invoice:update"),
+	}); err != nil {
+		t.Fatalf("DeclareScopes: %v", err)
+	}
+
+	drift, err := provider.Reconcile(ctx)
+	if err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if len(drift.Missing) != 0 || len(drift.Unexpected) != 4 {
+		t.Fatalf("drift on first reconcile = %#v, want the four authz tuples unexpected", drift)
+	}
+	if got := provider.store.roleScopes("billing", "viewer"); len(got) != 1 || got[0] != "billing:invoice:read" {
+		t.Fatalf("rebuilt role scopes = %v", got)
+	}
+	client.checks[ketoDirectScopeTuple("alice", "billing:invoice:read")] = true
+	result, err := provider.CheckScope(ctx, ScopeCheck{Subject: "alice", Context: "billing", Scope: "billing:invoice:read"})
+	if err != nil || !result.Allowed || result.MatchedRole != "viewer" {
+		t.Fatalf("CheckScope after reconcile = %#v, %v; want allowed through viewer", result, err)
+	}
+	assignments, err := provider.ListAssignments(ctx, AssignmentFilter{Subject: "alice"})
+	if err != nil || len(assignments) != 2 {
+		t.Fatalf("ListAssignments = %#v, %v; want a direct scope grant and viewer", assignments, err)
+	}
+	// Direct scopes are subject-wide in Keto, so they decode into their own
+	// role-less assignment rather than onto whichever role came first.
+	for _, assignment := range assignments {
+		switch assignment.Role {
+		case "":
+			if len(assignment.DirectScopes) != 1 || assignment.DirectScopes[0] != "billing:invoice:update" {
+				t.Fatalf("direct scope assignment = %#v", assignment)
+			}
+		case "viewer":
+			if len(assignment.DirectScopes) != 0 {
+				t.Fatalf("viewer assignment carries direct scopes %v", assignment.DirectScopes)
+			}
+		default:
+			t.Fatalf("unexpected assignment %#v", assignment)
+		}
+	}
+
+	// A grant held locally but lost in Keto, and one written by another
+	// replica, are both reported and Keto wins.
+	if err := provider.store.AssignRole(ctx, SubjectRoleAssignment{Subject: "bob", Role: "viewer", Context: "billing"}); err != nil {
+		t.Fatalf("AssignRole in store: %v", err)
+	}
+	client.tuples = append(client.tuples, ketoSubjectRoleTuple("billing", "viewer", "carol"))
+	drift, err = provider.Reconcile(ctx)
+	if err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if len(drift.Missing) != 1 || drift.Missing[0] != "role:billing:viewer#member@bob" {
+		t.Fatalf("missing drift = %v", drift.Missing)
+	}
+	if len(drift.Unexpected) != 1 || drift.Unexpected[0] != "role:billing:viewer#member@carol" {
+		t.Fatalf("unexpected drift = %v", drift.Unexpected)
+	}
+	local, _ := provider.store.ListAssignments(ctx, AssignmentFilter{Role: "viewer"})
+	if len(local) != 2 || local[0].Subject != "alice" || local[1].Subject != "carol" {
+		t.Fatalf("local assignments after reconcile = %#v", local)
+	}
+
+	drift, err = provider.Reconcile(ctx)
+	if err != nil || !drift.empty() {
+		t.Fatalf("reconcile of converged state = %#v, %v; want no drift", drift, err)
+	}
+}
+
+func TestKetoReconcileResolvesDirectScopeContextFromDeclaration(t *testing.T) {
+	ctx := context.Background()
+	client := &fakeKetoClient{checks: map[ketoTuple]bool{}, tuples: []ketoTuple{
+		ketoDirectScopeTuple("alice", "billing.invoice.read"),
+		ketoDirectScopeTuple("alice", "billing.payout.*"),
+	}}
+	provider := newKetoScopeProvider("keto", client)
+	if err := provider.DeclareScopes(ctx, []*contracts.ScopeDeclaration{
+		{Name: "billing.invoice.read", Context: "billing", Resource: "invoice", Actions: []string{"read"}},
+		{Name: "billing.payout.read", Context: "billing", Resource: "payout", Actions: []string{"read"}},
+	}); err != nil {
+		t.Fatalf("DeclareScopes: %v", err)
+	}
+
+	if _, err := provider.Reconcile(ctx); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	local, _ := provider.store.ListAssignments(ctx, AssignmentFilter{Subject: "alice"})
+	if len(local) != 1 || local[0].Context != "billing" || strings.Join(local[0].DirectScopes, ",") != "billing.invoice.read,billing.payout.*" {
+		t.Fatalf("local assignments after reconcile = %#v", local)
+	}
+	assignments, err := provider.ListAssignments(ctx, AssignmentFilter{Subject: "alice", Context: "billing"})
+	if err != nil || len(assignments) != 1 || len(assignments[0].DirectScopes) != 2 {
+		t.Fatalf("ListAssignments = %#v, %v; want the dotted direct scopes in billing", assignments, err)
+	}
+	drift, err := provider.Reconcile(ctx)
+	if err != nil || !drift.empty() {
+		t.Fatalf("second reconcile = %#v, %v; want no drift", drift, err)
+	}
+}
+
+func TestKetoCheckScopeFallsBackToKetoOnLocalMiss(t *testing.T) {
+	ctx := context.Background()
+	client := &fakeKetoClient{checks: map[ketoTuple]bool{}}
+	provider := newKetoScopeProvider("keto", client)
+	if err := provider.DeclareScopes(ctx, []*contracts.ScopeDeclaration{scopeDeclarationFromName("billing:invoice:read")}); err != nil {
+		t.Fatalf("DeclareScopes: %v", err)
+	}
+	check := ScopeCheck{Subject: "dave", Context: "billing", Scope: "billing:invoice:read"}
+	result, err := provider.CheckScope(ctx, check)
+	if err != nil || result.Allowed {
+		t.Fatalf("CheckScope without any grant = %#v, %v; want denied", result, err)
+	}
+
+	// Another replica granted the scope; the local store has not reconciled yet.
+	client.checks[ketoDirectScopeTuple("dave", "billing:invoice:read")] = true
+	result, err = provider.CheckScope(ctx, check)
+	if err != nil || !result.Allowed || len(result.MatchedScopes) != 1 {
+		t.Fatalf("CheckScope after remote grant = %#v, %v; want allowed from Keto", result, err)
+	}
+}
+
+func TestKetoRemoveAssignmentDeletesDirectScopeTuples(t *testing.T) {
+	ctx := context.Background()
+	client := &fakeKetoClient{checks: map[ketoTuple]bool{}}
+	provider := newKetoScopeProvider("keto", client)
+	if err := provider.DeclareScopes(ctx, []*contracts.ScopeDeclaration{scopeDeclarationFromName("billing:invoice:read")}); err != nil {
+		t.Fatalf("DeclareScopes: %v", err)
+	}
+	assignment := SubjectRoleAssignment{Subject: "alice", Context: "billing", DirectScopes: []string{"billing:invoice:read"}}
+	if err := provider.AssignRole(ctx, assignment); err != nil {
+		t.Fatalf("AssignRole: %v", err)
+	}
+	if err := provider.RemoveAssignment(ctx, SubjectRoleAssignment{Subject: "alice", Context: "billing"}); err != nil {
+		t.Fatalf("RemoveAssignment: %v", err)
+	}
+	if client.wrote(ketoDirectScopeTuple("alice", "billing:invoice:read")) {
+		t.Fatalf("direct scope tuple was left in Keto: %#v", client.tuples)
+	}
+}
+
+func TestKetoRelationTuplesMapSubjectSets(t *testing.T) {
+	ctx := context.Background()
+	client := &fakeKetoClient{checks: map[ketoTuple]bool{}}
+	provider := newKetoScopeProvider("keto", client)
+	tuple := RelationTuple{Subject: "groups:eng#member", Relation: "viewer", Object: "doc1", Context: "docs"}
+	if err := provider.UpsertRelationTuple(ctx, tuple); err != nil {
+		t.Fatalf("UpsertRelationTuple: %v", err)
+	}
+	want := ketoTuple{
+		Namespace:  "resource",
+		Object:     "docs:doc1",
+		Relation:   "viewer",
+		SubjectSet: &ketoSubjectSet{Namespace: "resource", Object: "groups:eng", Relation: "member"},
+	}
+	if !client.wrote(want) {
+		t.Fatalf("missing subject set tuple, wrote %#v", client.tuples)
+	}
+	tuples, err := provider.ListRelationTuples(ctx, RelationTupleFilter{Subject: "groups:eng#member"})
+	if err != nil || len(tuples) != 1 || tuples[0] != tuple {
+		t.Fatalf("ListRelationTuples = %#v, %v; want %#v", tuples, err, tuple)
+	}
+
+	client.checks[want] = true
+	result, err := provider.CheckRelation(ctx, RelationCheck{Subject: tuple.Subject, Relation: "viewer", Object: "doc1", Context: "docs"})
+	if err != nil || !result.Allowed {
+		t.Fatalf("CheckRelation for subject set = %#v, %v; want allowed", result, err)
+	}
+
+	// A "#" without a context-qualified object is an ordinary subject ID.
+	if subjectID, set := ketoSubject("alice#1"); set != nil || subjectID != "alice#1" {
+		t.Fatalf("ketoSubject(alice#1) = %q, %#v", subjectID, set)
+	}
+}
+
+func TestKetoExpandRelation(t *testing.T) {
+	ctx := context.Background()
+	client := &fakeKetoClient{checks: map[ketoTuple]bool{}}
+	provider := newKetoScopeProvider("keto", client)
+	for _, subject := range []string{"alice", "groups:eng#member"} {
+		if err := provider.UpsertRelationTuple(ctx, RelationTuple{Subject: subject, Relation: "viewer", Object: "doc1", Context: "docs"}); err != nil {
+			t.Fatalf("UpsertRelationTuple: %v", err)
+		}
+	}
+	tree, err := provider.ExpandRelation(ctx, RelationExpand{Relation: "viewer", Object: "doc1", Context: "docs"})
+	if err != nil {
+		t.Fatalf("ExpandRelation: %v", err)
+	}
+	if tree.Subject != "docs:doc1#viewer" || len(tree.Children) != 2 ||
+		tree.Children[0].Subject != "alice" || tree.Children[1].Subject != "groups:eng#member" {
+		t.Fatalf("tree = %#v", tree)
+	}
+	if _, err := provider.ExpandRelation(ctx, RelationExpand{Relation: "viewer", Context: "docs"}); err == nil {
+		t.Fatal("expected an error for an expand without an object")
+	}
+}
+
+func TestGenerateKetoNamespaces(t *testing.T) {
+	opl, err := generateKetoNamespaces([]*contracts.RelationDeclaration{
+		{Name: "member", Context: "groups", SubjectType: "user", ObjectType: "group"},
+		{Name: "viewer", Context: "docs", SubjectType: "user", ObjectType: "document", Description: "Can read the document"},
+		{Name: "viewer", Context: "docs", SubjectType: "group#member", ObjectType: "document"},
+	})
+	if err != nil {
+		t.Fatalf("generateKetoNamespaces: %v", err)
+	}
+	want := `import { Namespace, SubjectSet } from "@ory/keto-namespace-types"
+
+class User implements Namespace {}
+
+class role implements Namespace {
+  related: {
+    member: User[]
+  }
+}
+
+class scope implements Namespace {
+  related: {
+    granted: (User | SubjectSet<role, "member">)[]
+  }
+}
+
+class resource implements Namespace {
+  related: {
+    // groups group
+    member: User[]
+    // docs document: Can read the document
+    // docs document
+    viewer: (User | SubjectSet<resource, "member">)[]
+  }
+}
+`
+	if opl != want {
+		t.Fatalf("OPL =\n%s\nwant\n%s", opl, want)
+	}
+	if _, err := generateKetoNamespaces([]*contracts.RelationDeclaration{{Name: "can-view", Context: "docs", SubjectType: "user", ObjectType: "document"}}); err == nil {
+		t.Fatal("expected an error for a relation name that is not an OPL identifier")
+	}
+}
+
 func TestKetoRealIntegration(t *testing.T) {
 	if os.Getenv("KETO_INTEGRATION") != "1" {
 		t.Skip("KETO_INTEGRATION=1 not set; skipping real Ory Keto SDK integration")
@@ -63,7 +341,7 @@ func TestKetoRealIntegration(t *testing.T) {
 	if readURL == "" || writeURL == "" {
 		t.Skip("missing KETO_READ_URL or KETO_WRITE_URL for real Ory Keto SDK integration")
 	}
-	provider := newKetoScopeProvider("keto", newKetoSDKClient(readURL, writeURL))
+	provider := newKetoScopeProvider("keto", newKetoSDKClient(readURL, writeURL, &http.Client{Timeout: ketoDefaultTimeout}))
 	ctx := context.Background()
 	scope := scopeDeclarationFromName("admin:authz.roles:read")
 	if err := provider.DeclareScopes(ctx, []*contracts.ScopeDeclaration{scope}); err != nil {
@@ -88,11 +366,48 @@ func (f *fakeKetoClient) CreateRelationship(_ context.Context, tuple ketoTuple)
 }
 
 func (f *fakeKetoClient) DeleteRelationship(_ context.Context, tuple ketoTuple) error {
+	kept := f.tuples[:0]
+	for _, existing := range f.tuples {
+		if !existing.equal(tuple) {
+			kept = append(kept, existing)
+		}
+	}
+	f.tuples = kept
 	return nil
 }
 
 func (f *fakeKetoClient) Check(_ context.Context, tuple ketoTuple) (bool, error) {
-	return f.checks[tuple], nil
+	for check, allowed := range f.checks {
+		if check.equal(tuple) {
+			return allowed, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *fakeKetoClient) ListRelationships(_ context.Context, query ketoTuple) ([]ketoTuple, error) {
+	var out []ketoTuple
+	for _, tuple := range f.tuples {
+		if (query.Namespace == "" || tuple.Namespace == query.Namespace) &&
+			(query.Object == "" || tuple.Object == query.Object) &&
+			(query.Relation == "" || tuple.Relation == query.Relation) &&
+			(query.SubjectID == "" || tuple.SubjectSet == nil && tuple.SubjectID == query.SubjectID) &&
+			(query.SubjectSet == nil || tuple.SubjectSet != nil && *tuple.SubjectSet == *query.SubjectSet) {
+			out = append(out, tuple)
+		}
+	}
+	return out, nil
+}
+
+// Expand lists the direct subjects of set without following subject sets.
+func (f *fakeKetoClient) Expand(_ context.Context, set ketoSubjectSet, _ int) (UsersetTree, error) {
+	tree := UsersetTree{Type: "union", Subject: ketoSubjectString("", &set)}
+	for _, tuple := range f.tuples {
+		if tuple.Namespace == set.Namespace && tuple.Object == set.Object && tuple.Relation == set.Relation {
+			tree.Children = append(tree.Children, UsersetTree{Type: "leaf", Subject: ketoSubjectString(tuple.SubjectID, tuple.SubjectSet)})
+		}
+	}
+	return tree, nil
 }
 
 func (f *fakeKetoClient) wrote(want ketoTuple) bool {
@@ -1,13 +1,16 @@
 package internal
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"net/http"
 	"strings"
 
 	"github.com/GoCodeAlone/workflow-plugin-authz/internal/contracts"
-	permitapi "github.com/permitio/permit-golang/pkg/api"
 	permitconfig "github.com/permitio/permit-golang/pkg/config"
 	"github.com/permitio/permit-golang/pkg/enforcement"
 	permitmodels "github.com/permitio/permit-golang/pkg/models"
@@ -67,22 +70,54 @@ func (p *permitScopeProvider) DeclareScopes(ctx context.Context, scopes []*contr
 			return err
 		}
 	}
+	return p.reprojectPatternGrants(ctx)
+}
+
+// reprojectPatternGrants re-sends the permissions of roles and direct grants
+// that use wildcard patterns, so newly declared scopes reach Permit.
+func (p *permitScopeProvider) reprojectPatternGrants(ctx context.Context) error {
+	grants, assignments := p.store.patternGrants()
+	for _, grant := range grants {
+		if err := p.client.UpsertRole(ctx, permitRoleKey(grant.Context, grant.Role), p.permissions(grant.Context, grant.Scopes)); err != nil {
+			return err
+		}
+	}
+	for _, assignment := range assignments {
+		directRole := permitDirectRoleKey(assignment.Context, assignment.Subject)
+		if err := p.client.UpsertRole(ctx, directRole, p.permissions(assignment.Context, assignment.DirectScopes)); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// permissions projects scopes onto Permit "resource:action" permissions.
+// Permit has no wildcard permissions, so patterns are expanded against the
+// scopes declared in contextName.
+func (p *permitScopeProvider) permissions(contextName string, scopes []string) []string {
+	expanded := p.store.expandScopes(contextName, scopes)
+	permissions := make([]string, 0, len(expanded))
+	for _, scopeName := range expanded {
+		scope := scopeDeclarationFromName(scopeName)
+		permissions = append(permissions, permitPermission(scope.GetResource(), firstScopeAction(scope)))
+	}
+	return permissions
+}
+
 func (p *permitScopeProvider) UpsertRole(ctx context.Context, grant RoleScopeGrant) error {
+	grant = normalizeRoleScopeGrant(grant)
 	if err := p.store.UpsertRole(ctx, grant); err != nil {
 		return err
 	}
-	permissions := make([]string, 0, len(grant.Scopes))
-	for _, scopeName := range grant.Scopes {
-		scope := scopeDeclarationFromName(scopeName)
-		permissions = append(permissions, permitPermission(scope.GetResource(), firstScopeAction(scope)))
+	if err := p.client.UpsertRole(ctx, permitRoleKey(grant.Context, grant.Role), p.permissions(grant.Context, grant.Scopes)); err != nil {
+		return err
 	}
-	return p.client.UpsertRole(ctx, permitRoleKey(grant.Context, grant.Role), permissions)
+	publishChange(p.name, "permit", ChangeRoleUpserted, roleScopeGrantToMap(grant))
+	return nil
 }
 
 func (p *permitScopeProvider) AssignRole(ctx context.Context, assignment SubjectRoleAssignment) error {
+	assignment = normalizeSubjectRoleAssignment(assignment)
 	if err := p.store.AssignRole(ctx, assignment); err != nil {
 		return err
 	}
@@ -93,19 +128,14 @@ func (p *permitScopeProvider) AssignRole(ctx context.Context, assignment Subject
 	}
 	if len(assignment.DirectScopes) > 0 {
 		directRole := permitDirectRoleKey(assignment.Context, assignment.Subject)
-		direct := RoleScopeGrant{Role: directRole, Context: assignment.Context, Scopes: assignment.DirectScopes}
-		permissions := make([]string, 0, len(direct.Scopes))
-		for _, scopeName := range direct.Scopes {
-			scope := scopeDeclarationFromName(scopeName)
-			permissions = append(permissions, permitPermission(scope.GetResource(), firstScopeAction(scope)))
-		}
-		if err := p.client.UpsertRole(ctx, directRole, permissions); err != nil {
+		if err := p.client.UpsertRole(ctx, directRole, p.permissions(assignment.Context, assignment.DirectScopes)); err != nil {
 			return err
 		}
 		if err := p.client.AssignRole(ctx, assignment.Subject, directRole, p.tenant); err != nil {
 			return err
 		}
 	}
+	publishChange(p.name, "permit", ChangeRoleAssigned, subjectRoleAssignmentToMap(assignment))
 	return nil
 }
 
@@ -114,18 +144,21 @@ func (p *permitScopeProvider) ListAssignments(ctx context.Context, filter Assign
 }
 
 func (p *permitScopeProvider) RemoveAssignment(ctx context.Context, assignment SubjectRoleAssignment) error {
+	assignment = normalizeSubjectRoleAssignment(assignment)
 	if err := p.store.RemoveAssignment(ctx, assignment); err != nil {
 		return err
 	}
 	if assignment.Role != "" {
-		return p.client.UnassignRole(ctx, assignment.Subject, permitRoleKey(assignment.Context, assignment.Role), p.tenant)
+		if err := p.client.UnassignRole(ctx, assignment.Subject, permitRoleKey(assignment.Context, assignment.Role), p.tenant); err != nil {
+			return err
+		}
 	}
+	publishChange(p.name, "permit", ChangeRoleUnassigned, subjectRoleAssignmentToMap(assignment))
 	return nil
 }
 
 func (p *permitScopeProvider) CheckScope(ctx context.Context, check ScopeCheck) (ScopeCheckResult, error) {
 	scopeName := normalizeCheckScope(check)
-	scope := scopeDeclarationFromName(scopeName)
 	result := ScopeCheckResult{
 		Provider: p.store.provider,
 		Subject:  strings.TrimSpace(check.Subject),
@@ -144,6 +177,13 @@ func (p *permitScopeProvider) CheckScope(ctx context.Context, check ScopeCheck)
 		local.Provider = p.store.provider
 		return local, nil
 	}
+	// Permissions are written for the granted name, which is the old name when
+	// the grant predates a rename.
+	granted := scopeName
+	if len(local.MatchedScopes) > 0 && !isScopePattern(local.MatchedScopes[0]) {
+		granted = local.MatchedScopes[0]
+	}
+	scope := scopeDeclarationFromName(granted)
 	allowed, err := p.client.Check(ctx, result.Subject, firstScopeAction(scope), scope.GetResource())
 	if err != nil {
 		return result, err
@@ -151,6 +191,7 @@ func (p *permitScopeProvider) CheckScope(ctx context.Context, check ScopeCheck)
 	result.Allowed = allowed
 	result.MatchedRole = local.MatchedRole
 	result.MatchedScopes = local.MatchedScopes
+	result.Deprecations = local.Deprecations
 	if !allowed {
 		result.Reason = "permit denied"
 	}
@@ -160,14 +201,22 @@ func (p *permitScopeProvider) CheckScope(ctx context.Context, check ScopeCheck)
 type permitSDKScopeClient struct {
 	client *permit.Client
 	tenant string
+	// pdpURL, apiKey, and httpClient serve Check, which calls the PDP
+	// directly because the SDK's check does not take a context.
+	pdpURL     string
+	apiKey     string
+	httpClient *http.Client
 }
 
-func newPermitSDKScopeClient(cfg permitModuleConfig) *permitSDKScopeClient {
+func newPermitSDKScopeClient(cfg permitModuleConfig, httpClient *http.Client) *permitSDKScopeClient {
 	context := permitconfig.NewPermitContext(permitconfig.ProjectAPIKeyLevel, cfg.Project, cfg.Environment)
-	permitCfg := permitconfig.NewPermitConfig(cfg.APIURL, cfg.APIKey, cfg.PDPURL, false, context, nil).Build()
+	permitCfg := permitconfig.NewPermitConfig(cfg.APIURL, cfg.APIKey, cfg.PDPURL, false, context, nil).WithHTTPClient(httpClient).Build()
 	return &permitSDKScopeClient{
-		client: permit.New(permitCfg),
-		tenant: "default",
+		client:     permit.New(permitCfg),
+		tenant:     "default",
+		pdpURL:     cfg.PDPURL,
+		apiKey:     cfg.APIKey,
+		httpClient: httpClient,
 	}
 }
 
@@ -210,11 +259,35 @@ func (c *permitSDKScopeClient) UnassignRole(ctx context.Context, subject, role,
 	return ignorePermitConflict(err)
 }
 
+// Check asks the PDP's /allowed endpoint for a decision. The request only
+// reads, so the transport may retry it.
 func (c *permitSDKScopeClient) Check(ctx context.Context, subject, action, resource string) (bool, error) {
-	_ = ctx
 	user := enforcement.UserBuilder(subject).Build()
 	res := enforcement.ResourceBuilder(resource).WithKey(resource).WithTenant(c.tenant).Build()
-	return c.client.Check(user, enforcement.Action(action), res)
+	body, err := json.Marshal(enforcement.NewCheckRequest(user, enforcement.Action(action), res, map[string]string{}))
+	if err != nil {
+		return false, fmt.Errorf("permit: marshal check: %w", err)
+	}
+	req, err := http.NewRequestWithContext(withIdempotent(ctx), http.MethodPost, strings.TrimRight(c.pdpURL, "/")+"/allowed", bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("permit: build check request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("permit: check: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return false, fmt.Errorf("permit: check: status %d: %s", resp.StatusCode, detail)
+	}
+	var decision enforcement.CheckResponse
+	if err := json.NewDecoder(resp.Body).Decode(&decision); err != nil {
+		return false, fmt.Errorf("permit: decode check response: %w", err)
+	}
+	return decision.Allow, nil
 }
 
 func permitPermission(resource, action string) string {
@@ -260,4 +333,3 @@ func ignorePermitConflict(err error) error {
 }
 
 var _ ScopeRoleProvider = (*permitScopeProvider)(nil)
-var _ = permitapi.DefaultPerPageLimit
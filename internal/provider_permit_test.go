@@ -2,6 +2,7 @@ package internal
 
 import (
 	"context"
+	"net/http"
 	"os"
 	"testing"
 
@@ -57,6 +58,29 @@ func TestPermitSDKProviderMapsScopeRolesAndChecks(t *testing.T) {
 	}
 }
 
+func TestPermitProviderExpandsWildcardGrants(t *testing.T) {
+	ctx := context.Background()
+	client := &fakePermitScopeClient{}
+	provider := newPermitScopeProvider("permit", client)
+	if err := provider.DeclareScopes(ctx, []*contracts.ScopeDeclaration{scopeDeclarationFromName("admin:authz.roles:update")}); err != nil {
+		t.Fatalf("DeclareScopes: %v", err)
+	}
+	if err := provider.UpsertRole(ctx, RoleScopeGrant{Role: "authz-admin", Context: "admin", Scopes: []string{"admin:authz.*"}}); err != nil {
+		t.Fatalf("UpsertRole: %v", err)
+	}
+	if got := client.rolePermissions["admin__authz-admin"]; !got["authz.roles:update"] || len(got) != 1 {
+		t.Fatalf("role permissions = %#v, want the expanded authz.roles:update", got)
+	}
+
+	// Declaring another matching scope re-projects the wildcard role.
+	if err := provider.DeclareScopes(ctx, []*contracts.ScopeDeclaration{scopeDeclarationFromName("admin:authz.policies:update")}); err != nil {
+		t.Fatalf("DeclareScopes: %v", err)
+	}
+	if !client.rolePermissions["admin__authz-admin"]["authz.policies:update"] {
+		t.Fatalf("role permissions = %#v, want authz.policies:update after declaration", client.rolePermissions)
+	}
+}
+
 func TestPermitRealIntegration(t *testing.T) {
 	if os.Getenv("PERMIT_INTEGRATION") != "1" {
 		t.Skip("PERMIT_INTEGRATION=1 not set; skipping real Permit.io SDK integration")
@@ -76,7 +100,7 @@ func TestPermitRealIntegration(t *testing.T) {
 		PDPURL:      defaultString(os.Getenv("PERMIT_PDP_URL"), defaultPermitPDPURL),
 		Project:     os.Getenv("PERMIT_PROJECT"),
 		Environment: os.Getenv("PERMIT_ENVIRONMENT"),
-	}))
+	}, &http.Client{Timeout: permitDefaultTimeout}))
 	ctx := context.Background()
 	scope := scopeDeclarationFromName("admin:authz.roles:read")
 	if err := provider.DeclareScopes(ctx, []*contracts.ScopeDeclaration{scope}); err != nil {
@@ -49,6 +49,30 @@ type RelationCheckResult struct {
 	Reason   string
 }
 
+// RelationExpander is implemented by relationship providers that can list who
+// holds a relation, following subject sets.
+type RelationExpander interface {
+	ExpandRelation(context.Context, RelationExpand) (UsersetTree, error)
+}
+
+type RelationExpand struct {
+	Relation string
+	Object   string
+	Context  string
+	// MaxDepth bounds how many subject sets are followed. Zero uses the
+	// provider's default.
+	MaxDepth int
+}
+
+// UsersetTree is a node in the expansion of a relation. Leaves are subjects;
+// inner nodes are subject sets, written "context:object#relation", whose
+// children are their members.
+type UsersetTree struct {
+	Type     string
+	Subject  string
+	Children []UsersetTree
+}
+
 type relationTupleStore struct {
 	mu     sync.RWMutex
 	tuples map[string]RelationTuple
@@ -98,6 +122,27 @@ func (s *relationTupleStore) Contains(check RelationCheck) bool {
 	return len(s.List(filter)) > 0
 }
 
+// replace replaces every tuple with tuples, which must be normalized.
+func (s *relationTupleStore) replace(tuples []RelationTuple) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tuples = make(map[string]RelationTuple, len(tuples))
+	for _, tuple := range tuples {
+		s.tuples[relationTupleKey(tuple)] = tuple
+	}
+}
+
+// clone returns an independent copy of the store for what-if evaluation.
+func (s *relationTupleStore) clone() *relationTupleStore {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := newRelationTupleStore()
+	for key, tuple := range s.tuples {
+		out.tuples[key] = tuple
+	}
+	return out
+}
+
 func normalizeRelationTuple(tuple RelationTuple) RelationTuple {
 	tuple.Subject = strings.TrimSpace(tuple.Subject)
 	tuple.Relation = strings.TrimSpace(tuple.Relation)
@@ -113,6 +158,13 @@ func validateRelationTuple(tuple RelationTuple) error {
 	return nil
 }
 
+func normalizeRelationExpand(expand RelationExpand) RelationExpand {
+	expand.Relation = strings.TrimSpace(expand.Relation)
+	expand.Object = strings.TrimSpace(expand.Object)
+	expand.Context = strings.TrimSpace(expand.Context)
+	return expand
+}
+
 func relationTupleMatches(tuple RelationTuple, filter RelationTupleFilter) bool {
 	return (filter.Subject == "" || tuple.Subject == filter.Subject) &&
 		(filter.Relation == "" || tuple.Relation == filter.Relation) &&
@@ -159,6 +211,19 @@ func checkRelationInvoke(ctx context.Context, provider RelationshipProvider, inp
 	return relationCheckResultToMap(result), nil
 }
 
+func expandRelationInvoke(ctx context.Context, expander RelationExpander, input map[string]any) (map[string]any, error) {
+	tree, err := expander.ExpandRelation(ctx, RelationExpand{
+		Relation: stringValue(input["relation"]),
+		Object:   stringValue(input["object"]),
+		Context:  stringValue(input["context"]),
+		MaxDepth: intValue(input["max_depth"]),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"tree": usersetTreeToMap(tree)}, nil
+}
+
 func relationTupleFromMap(values map[string]any) RelationTuple {
 	return RelationTuple{
 		Subject:  stringValue(values["subject"]),
@@ -200,3 +265,15 @@ func relationCheckResultToMap(result RelationCheckResult) map[string]any {
 		"reason":   result.Reason,
 	})
 }
+
+func usersetTreeToMap(tree UsersetTree) map[string]any {
+	out := compactMap(map[string]any{"type": tree.Type, "subject": tree.Subject})
+	if len(tree.Children) > 0 {
+		children := make([]map[string]any, 0, len(tree.Children))
+		for _, child := range tree.Children {
+			children = append(children, usersetTreeToMap(child))
+		}
+		out["children"] = children
+	}
+	return out
+}
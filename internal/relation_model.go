@@ -0,0 +1,996 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// relationModelMaxDepth bounds how many rewrites and subject sets a relation
+// check follows, as OpenFGA's default resolve depth does.
+const relationModelMaxDepth = 25
+
+// Rewrite operators of a relationRewrite.
+const (
+	rewriteDirect         = "direct"
+	rewriteComputed       = "computed"
+	rewriteTupleToUserset = "tuple_to_userset"
+	rewriteUnion          = "union"
+	rewriteIntersection   = "intersection"
+	rewriteExclusion      = "exclusion"
+	rewriteNil            = "nil"
+)
+
+// relationModel is an OpenFGA authorization model or SpiceDB schema: the
+// relations each object type defines, the subject types that may be written
+// to them, and the rewrite rules deciding who holds them.
+//
+// Objects are written type:id, such as document:roadmap. Subjects are an
+// object, a wildcard type:* matching every object of the type, or a subject
+// set context:type:id#relation naming everyone holding relation on that
+// object.
+type relationModel struct {
+	types map[string]map[string]*relationRule
+}
+
+// relationRule is one relation, or SpiceDB permission, of an object type.
+type relationRule struct {
+	name string
+	// permission marks a SpiceDB permission, which is computed and never
+	// written.
+	permission bool
+	// direct lists the subject types tuples of the relation may have; it is
+	// empty when the relation cannot be written.
+	direct  []relationSubjectType
+	rewrite *relationRewrite
+}
+
+// relationSubjectType is a subject type allowed by a relation: objects of
+// Type, every object of Type when Wildcard is set, or the subject set of
+// Relation on objects of Type.
+type relationSubjectType struct {
+	Type     string
+	Relation string
+	Wildcard bool
+}
+
+func (t relationSubjectType) String() string {
+	switch {
+	case t.Wildcard:
+		return t.Type + ":*"
+	case t.Relation != "":
+		return t.Type + "#" + t.Relation
+	default:
+		return t.Type
+	}
+}
+
+// relationRewrite is a node of a relation's rewrite rule. A computed node
+// checks relation on the same object; a tuple_to_userset node checks
+// relation on each object related through tupleset. Exclusion children are
+// the base and the subtracted set.
+type relationRewrite struct {
+	op       string
+	relation string
+	tupleset string
+	children []*relationRewrite
+}
+
+// parseRelationModel parses text in format, which is "openfga" (the OpenFGA
+// DSL or its JSON form), "spicedb", or empty to detect it from the text.
+func parseRelationModel(format, text string) (*relationModel, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "" {
+		format = detectRelationModelFormat(text)
+	}
+	var (
+		model *relationModel
+		err   error
+	)
+	switch format {
+	case "openfga":
+		if strings.HasPrefix(strings.TrimSpace(text), "{") {
+			model, err = parseOpenFGAJSON([]byte(text))
+		} else {
+			model, err = parseOpenFGADSL(text)
+		}
+	case "spicedb":
+		model, err = parseSpiceDBSchema(text)
+	default:
+		return nil, fmt.Errorf("relation model format must be openfga or spicedb, got %q", format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse %s model: %w", format, err)
+	}
+	if err := model.validate(); err != nil {
+		return nil, fmt.Errorf("%s model: %w", format, err)
+	}
+	return model, nil
+}
+
+var spiceDBDefinition = regexp.MustCompile(`(?m)^\s*definition\s`)
+
+func detectRelationModelFormat(text string) string {
+	if spiceDBDefinition.MatchString(text) {
+		return "spicedb"
+	}
+	return "openfga"
+}
+
+func newRelationModel() *relationModel {
+	return &relationModel{types: map[string]map[string]*relationRule{}}
+}
+
+func (m *relationModel) addType(name string) error {
+	if _, ok := m.types[name]; ok {
+		return fmt.Errorf("type %q is defined twice", name)
+	}
+	m.types[name] = map[string]*relationRule{}
+	return nil
+}
+
+func (m *relationModel) addRule(typeName string, rule *relationRule) error {
+	if _, ok := m.types[typeName][rule.name]; ok {
+		return fmt.Errorf("type %q defines %q twice", typeName, rule.name)
+	}
+	m.types[typeName][rule.name] = rule
+	return nil
+}
+
+func (m *relationModel) rule(typeName, relation string) (*relationRule, bool) {
+	rule, ok := m.types[typeName][relation]
+	return rule, ok
+}
+
+// validate checks that every type and relation the rules refer to is
+// defined.
+func (m *relationModel) validate() error {
+	var errs []error
+	for _, typeName := range sortedKeys(m.types) {
+		for _, name := range sortedKeys(m.types[typeName]) {
+			rule := m.types[typeName][name]
+			for _, subject := range rule.direct {
+				if _, ok := m.types[subject.Type]; !ok {
+					errs = append(errs, fmt.Errorf("%s#%s: subject type %q is not defined", typeName, name, subject.Type))
+				} else if _, ok := m.rule(subject.Type, subject.Relation); subject.Relation != "" && !ok {
+					errs = append(errs, fmt.Errorf("%s#%s: subject set %s is not defined", typeName, name, subject))
+				}
+			}
+			errs = append(errs, m.validateRewrite(typeName, name, rule.rewrite)...)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (m *relationModel) validateRewrite(typeName, name string, rewrite *relationRewrite) []error {
+	var errs []error
+	switch rewrite.op {
+	case rewriteComputed:
+		if _, ok := m.rule(typeName, rewrite.relation); !ok {
+			errs = append(errs, fmt.Errorf("%s#%s: relation %q is not defined on %s", typeName, name, rewrite.relation, typeName))
+		}
+	case rewriteTupleToUserset:
+		tupleset, ok := m.rule(typeName, rewrite.tupleset)
+		if !ok || len(tupleset.direct) == 0 {
+			errs = append(errs, fmt.Errorf("%s#%s: %q is not a relation that can be written on %s", typeName, name, rewrite.tupleset, typeName))
+			break
+		}
+		found := false
+		for _, subject := range tupleset.direct {
+			if _, ok := m.rule(subject.Type, rewrite.relation); ok {
+				found = true
+			}
+		}
+		if !found {
+			errs = append(errs, fmt.Errorf("%s#%s: no type related through %q defines %q", typeName, name, rewrite.tupleset, rewrite.relation))
+		}
+	}
+	for _, child := range rewrite.children {
+		errs = append(errs, m.validateRewrite(typeName, name, child)...)
+	}
+	return errs
+}
+
+// validateTuple checks that tuple writes a relation that accepts direct
+// tuples and that its subject has one of the relation's subject types.
+func (m *relationModel) validateTuple(tuple RelationTuple) error {
+	objectType, ok := relationObjectType(tuple.Object)
+	if !ok {
+		return fmt.Errorf("object %q must be written type:id", tuple.Object)
+	}
+	rule, ok := m.rule(objectType, tuple.Relation)
+	if !ok {
+		return fmt.Errorf("relation %q is not defined on type %q", tuple.Relation, objectType)
+	}
+	if len(rule.direct) == 0 {
+		return fmt.Errorf("%s#%s is computed and cannot be written", objectType, tuple.Relation)
+	}
+	subjectType, ok := relationSubjectTypeOf(tuple.Subject)
+	if !ok {
+		return fmt.Errorf("subject %q must be written type:id, type:*, or context:type:id#relation", tuple.Subject)
+	}
+	allowed := make([]string, 0, len(rule.direct))
+	for _, candidate := range rule.direct {
+		if candidate == subjectType {
+			return nil
+		}
+		allowed = append(allowed, candidate.String())
+	}
+	return fmt.Errorf("subject type %s is not allowed for %s#%s (allowed: %s)", subjectType, objectType, tuple.Relation, strings.Join(allowed, ", "))
+}
+
+// relationObjectType returns the type of an object written type:id.
+func relationObjectType(object string) (string, bool) {
+	typeName, id, ok := strings.Cut(object, ":")
+	if !ok || typeName == "" || id == "" {
+		return "", false
+	}
+	return typeName, true
+}
+
+// relationSubjectSet splits a subject set written context:object#relation.
+func relationSubjectSet(subject string) (contextName, object, relation string, ok bool) {
+	left, relation, found := strings.Cut(subject, "#")
+	if !found || relation == "" {
+		return "", "", "", false
+	}
+	contextName, object, found = strings.Cut(left, ":")
+	if !found || contextName == "" || object == "" {
+		return "", "", "", false
+	}
+	return contextName, object, relation, true
+}
+
+// relationSubjectTypeOf returns the subject type of a tuple subject.
+func relationSubjectTypeOf(subject string) (relationSubjectType, bool) {
+	if _, object, relation, ok := relationSubjectSet(subject); ok {
+		typeName, ok := relationObjectType(object)
+		return relationSubjectType{Type: typeName, Relation: relation}, ok
+	}
+	typeName, ok := relationObjectType(subject)
+	if !ok {
+		return relationSubjectType{}, false
+	}
+	return relationSubjectType{Type: typeName, Wildcard: strings.HasSuffix(subject, ":*")}, true
+}
+
+// relationModelCheck evaluates check against the model, reading tuples with
+// read. An error reports a check the model cannot answer, such as an
+// undefined relation or a resolution deeper than relationModelMaxDepth.
+func relationModelCheck(model *relationModel, read func(RelationTupleFilter) []RelationTuple, check RelationCheck) (bool, error) {
+	objectType, ok := relationObjectType(check.Object)
+	if !ok {
+		return false, fmt.Errorf("object %q must be written type:id", check.Object)
+	}
+	if _, ok := model.rule(objectType, check.Relation); !ok {
+		return false, fmt.Errorf("relation %q is not defined on type %q", check.Relation, objectType)
+	}
+	e := &relationEvaluator{model: model, read: read, subject: check.Subject, visiting: map[string]int{}}
+	return e.check(check.Context, check.Object, check.Relation, 0)
+}
+
+type relationEvaluator struct {
+	model   *relationModel
+	read    func(RelationTupleFilter) []RelationTuple
+	subject string
+	// visiting maps each relation being resolved to one more than the value
+	// of guarded when it was entered.
+	visiting map[string]int
+	// guarded counts the subtracted children of exclusions and the non-first
+	// children of intersections being evaluated.
+	guarded int
+}
+
+// check reports whether the subject holds relation on object. A relation
+// reached again while it is being resolved is treated as not held, which
+// breaks cycles between subject sets. A cycle that passes through the
+// subtracted child of an exclusion, or a later child of an intersection, is
+// an error instead, since treating it as not held there could turn an
+// exclusion into a grant.
+func (e *relationEvaluator) check(contextName, object, relation string, depth int) (bool, error) {
+	if depth > relationModelMaxDepth {
+		return false, fmt.Errorf("relation check exceeded the resolution depth of %d", relationModelMaxDepth)
+	}
+	objectType, _ := relationObjectType(object)
+	rule, ok := e.model.rule(objectType, relation)
+	if !ok {
+		return false, nil
+	}
+	key := contextName + "\x00" + object + "\x00" + relation
+	if level, ok := e.visiting[key]; ok {
+		if e.guarded >= level {
+			return false, fmt.Errorf("relation %s on %s is defined in a cycle through an intersection or exclusion", relation, object)
+		}
+		return false, nil
+	}
+	e.visiting[key] = e.guarded + 1
+	defer delete(e.visiting, key)
+	return e.eval(contextName, object, rule, rule.rewrite, depth)
+}
+
+func (e *relationEvaluator) eval(contextName, object string, rule *relationRule, rewrite *relationRewrite, depth int) (bool, error) {
+	switch rewrite.op {
+	case rewriteDirect:
+		for _, tuple := range e.read(RelationTupleFilter{Relation: rule.name, Object: object, Context: contextName}) {
+			if tuple.Subject == e.subject || e.matchesWildcard(tuple.Subject) {
+				return true, nil
+			}
+			if setContext, setObject, setRelation, ok := relationSubjectSet(tuple.Subject); ok {
+				held, err := e.check(setContext, setObject, setRelation, depth+1)
+				if err != nil || held {
+					return held, err
+				}
+			}
+		}
+		return false, nil
+	case rewriteComputed:
+		return e.check(contextName, object, rewrite.relation, depth+1)
+	case rewriteTupleToUserset:
+		for _, tuple := range e.read(RelationTupleFilter{Relation: rewrite.tupleset, Object: object, Context: contextName}) {
+			if _, _, _, ok := relationSubjectSet(tuple.Subject); ok || strings.HasSuffix(tuple.Subject, ":*") {
+				continue
+			}
+			held, err := e.check(contextName, tuple.Subject, rewrite.relation, depth+1)
+			if err != nil || held {
+				return held, err
+			}
+		}
+		return false, nil
+	case rewriteUnion:
+		for _, child := range rewrite.children {
+			held, err := e.eval(contextName, object, rule, child, depth)
+			if err != nil || held {
+				return held, err
+			}
+		}
+		return false, nil
+	case rewriteIntersection:
+		for i, child := range rewrite.children {
+			held, err := e.evalGuarded(i > 0, contextName, object, rule, child, depth)
+			if err != nil || !held {
+				return false, err
+			}
+		}
+		return true, nil
+	case rewriteExclusion:
+		held, err := e.eval(contextName, object, rule, rewrite.children[0], depth)
+		if err != nil || !held {
+			return false, err
+		}
+		excluded, err := e.evalGuarded(true, contextName, object, rule, rewrite.children[1], depth)
+		return !excluded && err == nil, err
+	default:
+		return false, nil
+	}
+}
+
+// evalGuarded evaluates rewrite, counting it as guarded when a cycle through
+// it must be reported rather than treated as not held.
+func (e *relationEvaluator) evalGuarded(guard bool, contextName, object string, rule *relationRule, rewrite *relationRewrite, depth int) (bool, error) {
+	if guard {
+		e.guarded++
+		defer func() { e.guarded-- }()
+	}
+	return e.eval(contextName, object, rule, rewrite, depth)
+}
+
+// matchesWildcard reports whether subject is a type:* tuple subject covering
+// the checked subject.
+func (e *relationEvaluator) matchesWildcard(subject string) bool {
+	typeName, ok := strings.CutSuffix(subject, ":*")
+	if !ok {
+		return false
+	}
+	if _, _, _, isSet := relationSubjectSet(e.subject); isSet {
+		return false
+	}
+	checked, ok := relationObjectType(e.subject)
+	return ok && checked == typeName
+}
+
+// --- OpenFGA DSL ---
+
+// parseOpenFGADSL parses an OpenFGA model written in the schema 1.1 DSL.
+// Conditions and modular models are not supported.
+func parseOpenFGADSL(text string) (*relationModel, error) {
+	model := newRelationModel()
+	currentType := ""
+	sawSchema := false
+	for i, raw := range strings.Split(text, "\n") {
+		line := strings.TrimSpace(stripOpenFGAComment(raw))
+		if line == "" {
+			continue
+		}
+		lineErr := func(format string, args ...any) error {
+			return fmt.Errorf("line %d: %s", i+1, fmt.Sprintf(format, args...))
+		}
+		fields := strings.Fields(line)
+		switch fields[0] {
+		case "model":
+			if len(fields) != 1 {
+				return nil, lineErr("unexpected %q", line)
+			}
+		case "schema":
+			if len(fields) != 2 || fields[1] != "1.1" {
+				return nil, lineErr("only schema 1.1 is supported, got %q", line)
+			}
+			sawSchema = true
+		case "type":
+			if len(fields) != 2 {
+				return nil, lineErr("expected type <name>, got %q", line)
+			}
+			if err := model.addType(fields[1]); err != nil {
+				return nil, lineErr("%v", err)
+			}
+			currentType = fields[1]
+		case "relations":
+			if currentType == "" {
+				return nil, lineErr("relations outside a type")
+			}
+		case "define":
+			if currentType == "" {
+				return nil, lineErr("define outside a type")
+			}
+			name, expression, ok := strings.Cut(strings.TrimSpace(strings.TrimPrefix(line, "define")), ":")
+			if !ok || strings.TrimSpace(name) == "" {
+				return nil, lineErr("expected define <relation>: <expression>, got %q", line)
+			}
+			rule := &relationRule{name: strings.TrimSpace(name)}
+			parser := &relationExpressionParser{tokens: lexRelationModel(expression, true), rule: rule}
+			rewrite, err := parser.parseOpenFGA()
+			if err != nil {
+				return nil, lineErr("%s: %v", rule.name, err)
+			}
+			rule.rewrite = rewrite
+			if err := model.addRule(currentType, rule); err != nil {
+				return nil, lineErr("%v", err)
+			}
+		case "condition":
+			return nil, lineErr("conditions are not supported")
+		case "module", "extend":
+			return nil, lineErr("modular models are not supported")
+		default:
+			return nil, lineErr("unexpected %q", line)
+		}
+	}
+	if !sawSchema {
+		return nil, fmt.Errorf("missing schema 1.1 declaration")
+	}
+	return model, nil
+}
+
+// stripOpenFGAComment removes a # comment, which starts a line or follows
+// whitespace so that subject sets such as group#member are kept.
+func stripOpenFGAComment(line string) string {
+	for i, r := range line {
+		if r == '#' && (i == 0 || unicode.IsSpace(rune(line[i-1]))) {
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// --- SpiceDB schema ---
+
+// parseSpiceDBSchema parses a SpiceDB schema of definitions with relations
+// and permissions. Caveats and functioned arrows other than any are not
+// supported.
+func parseSpiceDBSchema(text string) (*relationModel, error) {
+	model := newRelationModel()
+	tokens := lexRelationModel(stripSpiceDBComments(text), false)
+	p := &relationExpressionParser{tokens: tokens}
+	for !p.done() {
+		switch keyword := p.next(); keyword {
+		case "definition":
+			name := p.next()
+			if !relationIdentifier(name) {
+				return nil, fmt.Errorf("expected a definition name, got %q", name)
+			}
+			if err := model.addType(name); err != nil {
+				return nil, err
+			}
+			if err := p.expect("{"); err != nil {
+				return nil, fmt.Errorf("definition %s: %w", name, err)
+			}
+			for p.peek() != "}" {
+				if p.done() {
+					return nil, fmt.Errorf("definition %s: missing }", name)
+				}
+				rule, err := p.parseSpiceDBMember()
+				if err != nil {
+					return nil, fmt.Errorf("definition %s: %w", name, err)
+				}
+				if err := model.addRule(name, rule); err != nil {
+					return nil, err
+				}
+			}
+			p.next()
+		case "caveat":
+			return nil, fmt.Errorf("caveats are not supported")
+		default:
+			return nil, fmt.Errorf("expected definition, got %q", keyword)
+		}
+	}
+	return model, nil
+}
+
+// parseSpiceDBMember parses one relation or permission of a definition.
+func (p *relationExpressionParser) parseSpiceDBMember() (*relationRule, error) {
+	kind := p.next()
+	name := p.next()
+	if !relationIdentifier(name) {
+		return nil, fmt.Errorf("expected a %s name, got %q", kind, name)
+	}
+	p.rule = &relationRule{name: name}
+	switch kind {
+	case "relation":
+		if err := p.expect(":"); err != nil {
+			return nil, fmt.Errorf("relation %s: %w", name, err)
+		}
+		for {
+			subject, err := p.parseSubjectType()
+			if err != nil {
+				return nil, fmt.Errorf("relation %s: %w", name, err)
+			}
+			p.rule.direct = append(p.rule.direct, subject)
+			if p.peek() != "|" {
+				break
+			}
+			p.next()
+		}
+		p.rule.rewrite = &relationRewrite{op: rewriteDirect}
+	case "permission":
+		if err := p.expect("="); err != nil {
+			return nil, fmt.Errorf("permission %s: %w", name, err)
+		}
+		rewrite, err := p.parseSpiceDBUnion()
+		if err != nil {
+			return nil, fmt.Errorf("permission %s: %w", name, err)
+		}
+		p.rule.permission = true
+		p.rule.rewrite = rewrite
+	default:
+		return nil, fmt.Errorf("expected relation or permission, got %q", kind)
+	}
+	return p.rule, nil
+}
+
+// stripSpiceDBComments removes // line comments and /* */ block comments.
+func stripSpiceDBComments(text string) string {
+	var b strings.Builder
+	for i := 0; i < len(text); i++ {
+		switch {
+		case strings.HasPrefix(text[i:], "//"):
+			for i < len(text) && text[i] != '\n' {
+				i++
+			}
+			b.WriteByte('\n')
+		case strings.HasPrefix(text[i:], "/*"):
+			end := strings.Index(text[i+2:], "*/")
+			if end < 0 {
+				return b.String()
+			}
+			i += end + 3
+			b.WriteByte(' ')
+		default:
+			b.WriteByte(text[i])
+		}
+	}
+	return b.String()
+}
+
+// --- expressions ---
+
+// lexRelationModel splits text into identifiers and punctuation. OpenFGA
+// identifiers may contain hyphens; in SpiceDB a hyphen is the exclusion
+// operator.
+func lexRelationModel(text string, hyphenInIdentifiers bool) []string {
+	var tokens []string
+	for i := 0; i < len(text); {
+		r := rune(text[i])
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case strings.HasPrefix(text[i:], "->"):
+			tokens = append(tokens, "->")
+			i += 2
+		case relationIdentifierByte(text[i], hyphenInIdentifiers):
+			start := i
+			for i < len(text) && relationIdentifierByte(text[i], hyphenInIdentifiers) {
+				i++
+			}
+			tokens = append(tokens, text[start:i])
+		default:
+			tokens = append(tokens, text[i:i+1])
+			i++
+		}
+	}
+	return tokens
+}
+
+func relationIdentifierByte(c byte, hyphen bool) bool {
+	return c == '_' || c == '/' || (c == '-' && hyphen) ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+func relationIdentifier(token string) bool {
+	if token == "" {
+		return false
+	}
+	for i := 0; i < len(token); i++ {
+		if !relationIdentifierByte(token[i], true) {
+			return false
+		}
+	}
+	return true
+}
+
+type relationExpressionParser struct {
+	tokens []string
+	pos    int
+	rule   *relationRule
+}
+
+func (p *relationExpressionParser) done() bool { return p.pos >= len(p.tokens) }
+
+func (p *relationExpressionParser) peek() string {
+	if p.done() {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *relationExpressionParser) next() string {
+	token := p.peek()
+	if !p.done() {
+		p.pos++
+	}
+	return token
+}
+
+func (p *relationExpressionParser) expect(token string) error {
+	if got := p.next(); got != token {
+		if got == "" {
+			got = "end of input"
+		}
+		return fmt.Errorf("expected %q, got %q", token, got)
+	}
+	return nil
+}
+
+// parseSubjectType parses type, type:* or type#relation, rejecting
+// conditions.
+func (p *relationExpressionParser) parseSubjectType() (relationSubjectType, error) {
+	typeName := p.next()
+	if !relationIdentifier(typeName) {
+		return relationSubjectType{}, fmt.Errorf("expected a subject type, got %q", typeName)
+	}
+	subject := relationSubjectType{Type: typeName}
+	switch p.peek() {
+	case ":":
+		p.next()
+		if err := p.expect("*"); err != nil {
+			return relationSubjectType{}, err
+		}
+		subject.Wildcard = true
+	case "#":
+		p.next()
+		subject.Relation = p.next()
+		if !relationIdentifier(subject.Relation) {
+			return relationSubjectType{}, fmt.Errorf("expected a relation after %s#, got %q", typeName, subject.Relation)
+		}
+	}
+	if p.peek() == "with" {
+		return relationSubjectType{}, fmt.Errorf("conditions are not supported")
+	}
+	return subject, nil
+}
+
+// parseOpenFGA parses an OpenFGA relation definition. As in the DSL, one
+// operator may be repeated but different operators must be parenthesized,
+// and but not takes a single operand.
+func (p *relationExpressionParser) parseOpenFGA() (*relationRewrite, error) {
+	rewrite, err := p.parseOpenFGAExpression()
+	if err != nil {
+		return nil, err
+	}
+	if !p.done() {
+		return nil, fmt.Errorf("unexpected %q", p.peek())
+	}
+	return rewrite, nil
+}
+
+func (p *relationExpressionParser) parseOpenFGAExpression() (*relationRewrite, error) {
+	first, err := p.parseOpenFGATerm()
+	if err != nil {
+		return nil, err
+	}
+	op := ""
+	operands := []*relationRewrite{first}
+	for {
+		var next string
+		switch p.peek() {
+		case "or":
+			next = rewriteUnion
+		case "and":
+			next = rewriteIntersection
+		case "but":
+			next = rewriteExclusion
+		default:
+			if op == "" {
+				return first, nil
+			}
+			return &relationRewrite{op: op, children: operands}, nil
+		}
+		p.next()
+		if next == rewriteExclusion {
+			if err := p.expect("not"); err != nil {
+				return nil, err
+			}
+		}
+		if op != "" && (op != next || op == rewriteExclusion) {
+			return nil, fmt.Errorf("combine different operators with parentheses")
+		}
+		op = next
+		operand, err := p.parseOpenFGATerm()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, operand)
+	}
+}
+
+func (p *relationExpressionParser) parseOpenFGATerm() (*relationRewrite, error) {
+	switch token := p.next(); {
+	case token == "[":
+		if len(p.rule.direct) > 0 {
+			return nil, fmt.Errorf("direct types are listed twice")
+		}
+		for {
+			subject, err := p.parseSubjectType()
+			if err != nil {
+				return nil, err
+			}
+			p.rule.direct = append(p.rule.direct, subject)
+			if p.peek() != "," {
+				break
+			}
+			p.next()
+		}
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+		return &relationRewrite{op: rewriteDirect}, nil
+	case token == "(":
+		rewrite, err := p.parseOpenFGAExpression()
+		if err != nil {
+			return nil, err
+		}
+		return rewrite, p.expect(")")
+	case relationIdentifier(token) && !openFGAKeyword(token):
+		if p.peek() == "from" {
+			p.next()
+			tupleset := p.next()
+			if !relationIdentifier(tupleset) {
+				return nil, fmt.Errorf("expected a relation after from, got %q", tupleset)
+			}
+			return &relationRewrite{op: rewriteTupleToUserset, relation: token, tupleset: tupleset}, nil
+		}
+		return &relationRewrite{op: rewriteComputed, relation: token}, nil
+	case token == "":
+		return nil, fmt.Errorf("unexpected end of expression")
+	default:
+		return nil, fmt.Errorf("unexpected %q", token)
+	}
+}
+
+func openFGAKeyword(token string) bool {
+	switch token {
+	case "or", "and", "but", "not", "from", "with":
+		return true
+	}
+	return false
+}
+
+// parseSpiceDBUnion parses a permission expression. As in SpiceDB, +
+// (union) binds loosest, then & (intersection), then - (exclusion), and all
+// three are left-associative.
+func (p *relationExpressionParser) parseSpiceDBUnion() (*relationRewrite, error) {
+	return p.parseSpiceDBBinary("+", rewriteUnion, func() (*relationRewrite, error) {
+		return p.parseSpiceDBBinary("&", rewriteIntersection, func() (*relationRewrite, error) {
+			return p.parseSpiceDBBinary("-", rewriteExclusion, p.parseSpiceDBArrow)
+		})
+	})
+}
+
+func (p *relationExpressionParser) parseSpiceDBBinary(operator, op string, operand func() (*relationRewrite, error)) (*relationRewrite, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == operator {
+		p.next()
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		if op != rewriteExclusion && left.op == op {
+			left.children = append(left.children, right)
+			continue
+		}
+		left = &relationRewrite{op: op, children: []*relationRewrite{left, right}}
+	}
+	return left, nil
+}
+
+// parseSpiceDBArrow parses a relation, nil, a parenthesized expression, or
+// an arrow tupleset->relation, also written tupleset.any(relation).
+func (p *relationExpressionParser) parseSpiceDBArrow() (*relationRewrite, error) {
+	token := p.next()
+	switch {
+	case token == "(":
+		rewrite, err := p.parseSpiceDBUnion()
+		if err != nil {
+			return nil, err
+		}
+		return rewrite, p.expect(")")
+	case token == "nil":
+		return &relationRewrite{op: rewriteNil}, nil
+	case relationIdentifier(token):
+	case token == "":
+		return nil, fmt.Errorf("unexpected end of expression")
+	default:
+		return nil, fmt.Errorf("unexpected %q", token)
+	}
+	switch p.peek() {
+	case "->":
+		p.next()
+		relation := p.next()
+		if !relationIdentifier(relation) {
+			return nil, fmt.Errorf("expected a relation after %s->, got %q", token, relation)
+		}
+		if p.peek() == "->" {
+			return nil, fmt.Errorf("chained arrows are not supported")
+		}
+		return &relationRewrite{op: rewriteTupleToUserset, relation: relation, tupleset: token}, nil
+	case ".":
+		p.next()
+		if function := p.next(); function != "any" {
+			return nil, fmt.Errorf("arrow function %q is not supported", function)
+		}
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		relation := p.next()
+		if !relationIdentifier(relation) {
+			return nil, fmt.Errorf("expected a relation in %s.any(), got %q", token, relation)
+		}
+		return &relationRewrite{op: rewriteTupleToUserset, relation: relation, tupleset: token}, p.expect(")")
+	}
+	return &relationRewrite{op: rewriteComputed, relation: token}, nil
+}
+
+// --- OpenFGA JSON ---
+
+type openFGAJSONModel struct {
+	AuthorizationModel *openFGAJSONModel `json:"authorization_model"`
+	SchemaVersion      string            `json:"schema_version"`
+	TypeDefinitions    []struct {
+		Type      string                        `json:"type"`
+		Relations map[string]openFGAJSONUserset `json:"relations"`
+		Metadata  *struct {
+			Relations map[string]struct {
+				DirectlyRelatedUserTypes []struct {
+					Type      string    `json:"type"`
+					Relation  string    `json:"relation"`
+					Wildcard  *struct{} `json:"wildcard"`
+					Condition string    `json:"condition"`
+				} `json:"directly_related_user_types"`
+			} `json:"relations"`
+		} `json:"metadata"`
+	} `json:"type_definitions"`
+}
+
+type openFGAJSONUserset struct {
+	This            *struct{} `json:"this"`
+	ComputedUserset *struct {
+		Relation string `json:"relation"`
+	} `json:"computedUserset"`
+	TupleToUserset *struct {
+		Tupleset struct {
+			Relation string `json:"relation"`
+		} `json:"tupleset"`
+		ComputedUserset struct {
+			Relation string `json:"relation"`
+		} `json:"computedUserset"`
+	} `json:"tupleToUserset"`
+	Union *struct {
+		Child []openFGAJSONUserset `json:"child"`
+	} `json:"union"`
+	Intersection *struct {
+		Child []openFGAJSONUserset `json:"child"`
+	} `json:"intersection"`
+	Difference *struct {
+		Base     openFGAJSONUserset `json:"base"`
+		Subtract openFGAJSONUserset `json:"subtract"`
+	} `json:"difference"`
+}
+
+// parseOpenFGAJSON parses an OpenFGA model in its JSON form, as written by
+// fga model transform or returned by the API under authorization_model.
+func parseOpenFGAJSON(raw []byte) (*relationModel, error) {
+	var doc openFGAJSONModel
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+	if doc.AuthorizationModel != nil {
+		doc = *doc.AuthorizationModel
+	}
+	if doc.SchemaVersion != "" && doc.SchemaVersion != "1.1" {
+		return nil, fmt.Errorf("only schema 1.1 is supported, got %q", doc.SchemaVersion)
+	}
+	model := newRelationModel()
+	for _, definition := range doc.TypeDefinitions {
+		if err := model.addType(definition.Type); err != nil {
+			return nil, err
+		}
+	}
+	for _, definition := range doc.TypeDefinitions {
+		for _, name := range sortedKeys(definition.Relations) {
+			rule := &relationRule{name: name}
+			if definition.Metadata != nil {
+				for _, subject := range definition.Metadata.Relations[name].DirectlyRelatedUserTypes {
+					if subject.Condition != "" {
+						return nil, fmt.Errorf("%s#%s: conditions are not supported", definition.Type, name)
+					}
+					rule.direct = append(rule.direct, relationSubjectType{Type: subject.Type, Relation: subject.Relation, Wildcard: subject.Wildcard != nil})
+				}
+			}
+			rewrite, err := definition.Relations[name].rewrite()
+			if err != nil {
+				return nil, fmt.Errorf("%s#%s: %w", definition.Type, name, err)
+			}
+			rule.rewrite = rewrite
+			if err := model.addRule(definition.Type, rule); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return model, nil
+}
+
+func (u openFGAJSONUserset) rewrite() (*relationRewrite, error) {
+	children := func(usersets []openFGAJSONUserset) ([]*relationRewrite, error) {
+		out := make([]*relationRewrite, 0, len(usersets))
+		for _, userset := range usersets {
+			child, err := userset.rewrite()
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, child)
+		}
+		return out, nil
+	}
+	switch {
+	case u.This != nil:
+		return &relationRewrite{op: rewriteDirect}, nil
+	case u.ComputedUserset != nil:
+		return &relationRewrite{op: rewriteComputed, relation: u.ComputedUserset.Relation}, nil
+	case u.TupleToUserset != nil:
+		return &relationRewrite{op: rewriteTupleToUserset, relation: u.TupleToUserset.ComputedUserset.Relation, tupleset: u.TupleToUserset.Tupleset.Relation}, nil
+	case u.Union != nil:
+		out, err := children(u.Union.Child)
+		return &relationRewrite{op: rewriteUnion, children: out}, err
+	case u.Intersection != nil:
+		out, err := children(u.Intersection.Child)
+		return &relationRewrite{op: rewriteIntersection, children: out}, err
+	case u.Difference != nil:
+		out, err := children([]openFGAJSONUserset{u.Difference.Base, u.Difference.Subtract})
+		return &relationRewrite{op: rewriteExclusion, children: out}, err
+	default:
+		return nil, fmt.Errorf("userset has no rewrite")
+	}
+}
@@ -0,0 +1,354 @@
+package internal
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testOpenFGAModel = `model
+  schema 1.1
+
+type user
+
+# Groups may nest.
+type group
+  relations
+    define member: [user, group#member]
+
+type folder
+  relations
+    define owner: [user]
+    define viewer: [user, user:*, group#member] or owner
+
+type document
+  relations
+    define parent: [folder]
+    define owner: [user]
+    define editor: [user] or owner
+    define blocked: [user]
+    define viewer: ([user, group#member] or editor or viewer from parent) but not blocked
+`
+
+const testSpiceDBSchema = `definition user {}
+
+/* Groups may nest. */
+definition group {
+	relation member: user | group#member
+}
+
+definition folder {
+	relation owner: user
+	relation reader: user | user:* | group#member
+	permission viewer = reader + owner
+}
+
+definition document {
+	relation parent: folder
+	relation owner: user
+	relation writer: user
+	relation reader: user | group#member
+	relation blocked: user
+	permission editor = writer + owner // owners can edit
+	permission viewer = (reader + editor + parent->viewer) - blocked
+}
+`
+
+// testRelationTuples share document:roadmap with group:eng, whose members are
+// alice and the nested group:leads, and place it in folder:plans.
+var testRelationTuples = []RelationTuple{
+	{Subject: "user:alice", Relation: "member", Object: "group:eng", Context: "docs"},
+	{Subject: "docs:group:leads#member", Relation: "member", Object: "group:eng", Context: "docs"},
+	{Subject: "user:carol", Relation: "member", Object: "group:leads", Context: "docs"},
+	{Subject: "docs:group:eng#member", Relation: "viewer", Object: "document:roadmap", Context: "docs"},
+	{Subject: "folder:plans", Relation: "parent", Object: "document:roadmap", Context: "docs"},
+	{Subject: "user:dave", Relation: "owner", Object: "folder:plans", Context: "docs"},
+	{Subject: "user:erin", Relation: "owner", Object: "document:roadmap", Context: "docs"},
+	{Subject: "user:carol", Relation: "blocked", Object: "document:roadmap", Context: "docs"},
+}
+
+func TestRelationModelCheckOpenFGA(t *testing.T) {
+	model, err := parseRelationModel("", testOpenFGAModel)
+	if err != nil {
+		t.Fatalf("parseRelationModel: %v", err)
+	}
+	store := newRelationTupleStore()
+	for _, tuple := range testRelationTuples {
+		if err := model.validateTuple(tuple); err != nil {
+			t.Fatalf("validateTuple(%v): %v", tuple, err)
+		}
+		_ = store.Upsert(tuple)
+	}
+	_ = store.Upsert(RelationTuple{Subject: "user:*", Relation: "viewer", Object: "folder:public", Context: "docs"})
+
+	for _, tc := range []struct {
+		subject, relation, object string
+		want                      bool
+	}{
+		{"user:alice", "viewer", "document:roadmap", true},  // through group:eng
+		{"user:carol", "member", "group:eng", true},         // through nested group:leads
+		{"user:carol", "viewer", "document:roadmap", false}, // blocked
+		{"user:dave", "viewer", "document:roadmap", true},   // folder owner, through parent
+		{"user:erin", "viewer", "document:roadmap", true},   // owner implies editor implies viewer
+		{"user:erin", "editor", "document:roadmap", true},
+		{"user:alice", "editor", "document:roadmap", false},
+		{"user:bob", "viewer", "document:roadmap", false},
+		{"user:bob", "viewer", "folder:public", true}, // wildcard
+		{"group:eng", "viewer", "folder:public", false},
+	} {
+		got, err := relationModelCheck(model, store.List, RelationCheck{Subject: tc.subject, Relation: tc.relation, Object: tc.object, Context: "docs"})
+		if err != nil || got != tc.want {
+			t.Errorf("check %s %s %s = %v, %v; want %v", tc.subject, tc.relation, tc.object, got, err, tc.want)
+		}
+	}
+	if _, err := relationModelCheck(model, store.List, RelationCheck{Subject: "user:alice", Relation: "approver", Object: "document:roadmap", Context: "docs"}); err == nil {
+		t.Fatal("expected an error for an undefined relation")
+	}
+}
+
+func TestRelationModelCheckRejectsCyclesThroughExclusion(t *testing.T) {
+	model, err := parseRelationModel("", `model
+  schema 1.1
+
+type user
+
+type group
+  relations
+    define member: [user, group#member]
+
+type team
+  relations
+    define member: [user, team#member] but not banned
+    define banned: [user]
+
+type document
+  relations
+    define viewer: [user] but not blocked
+    define blocked: viewer
+`)
+	if err != nil {
+		t.Fatalf("parseRelationModel: %v", err)
+	}
+	store := newRelationTupleStore()
+	for _, tuple := range []RelationTuple{
+		{Subject: "user:alice", Relation: "viewer", Object: "document:roadmap", Context: "docs"},
+		{Subject: "docs:group:b#member", Relation: "member", Object: "group:a", Context: "docs"},
+		{Subject: "docs:group:a#member", Relation: "member", Object: "group:b", Context: "docs"},
+		{Subject: "docs:team:b#member", Relation: "member", Object: "team:a", Context: "docs"},
+		{Subject: "docs:team:a#member", Relation: "member", Object: "team:b", Context: "docs"},
+		{Subject: "user:alice", Relation: "member", Object: "team:b", Context: "docs"},
+		{Subject: "user:mallory", Relation: "member", Object: "team:b", Context: "docs"},
+		{Subject: "user:mallory", Relation: "banned", Object: "team:b", Context: "docs"},
+	} {
+		_ = store.Upsert(tuple)
+	}
+	allowed, err := relationModelCheck(model, store.List, RelationCheck{Subject: "user:alice", Relation: "viewer", Object: "document:roadmap", Context: "docs"})
+	if err == nil || !strings.Contains(err.Error(), "cycle") || allowed {
+		t.Fatalf("check through a cyclic exclusion = %v, %v; want a cycle error", allowed, err)
+	}
+	// A cycle of unions alone still just fails to grant.
+	allowed, err = relationModelCheck(model, store.List, RelationCheck{Subject: "user:alice", Relation: "member", Object: "group:a", Context: "docs"})
+	if err != nil || allowed {
+		t.Fatalf("check through nested groups = %v, %v; want not held", allowed, err)
+	}
+	// A cycle through the base of an exclusion is safe to treat as not held,
+	// so the later direct tuple still grants.
+	allowed, err = relationModelCheck(model, store.List, RelationCheck{Subject: "user:alice", Relation: "member", Object: "team:a", Context: "docs"})
+	if err != nil || !allowed {
+		t.Fatalf("check through nested teams with an exclusion = %v, %v; want held", allowed, err)
+	}
+	allowed, err = relationModelCheck(model, store.List, RelationCheck{Subject: "user:mallory", Relation: "member", Object: "team:a", Context: "docs"})
+	if err != nil || allowed {
+		t.Fatalf("check for a banned member of a nested team = %v, %v; want not held", allowed, err)
+	}
+}
+
+func TestRelationModelCheckSpiceDB(t *testing.T) {
+	model, err := parseRelationModel("spicedb", testSpiceDBSchema)
+	if err != nil {
+		t.Fatalf("parseRelationModel: %v", err)
+	}
+	store := newRelationTupleStore()
+	for _, tuple := range testRelationTuples {
+		if tuple.Relation == "viewer" {
+			tuple.Relation = "reader"
+		}
+		if err := model.validateTuple(tuple); err != nil {
+			t.Fatalf("validateTuple(%v): %v", tuple, err)
+		}
+		_ = store.Upsert(tuple)
+	}
+	for _, tc := range []struct {
+		subject, relation string
+		want              bool
+	}{
+		{"user:alice", "viewer", true},
+		{"user:carol", "viewer", false},
+		{"user:dave", "viewer", true},
+		{"user:erin", "editor", true},
+		{"user:alice", "editor", false},
+	} {
+		got, err := relationModelCheck(model, store.List, RelationCheck{Subject: tc.subject, Relation: tc.relation, Object: "document:roadmap", Context: "docs"})
+		if err != nil || got != tc.want {
+			t.Errorf("check %s %s = %v, %v; want %v", tc.subject, tc.relation, got, err, tc.want)
+		}
+	}
+	if err := model.validateTuple(RelationTuple{Subject: "user:alice", Relation: "viewer", Object: "document:roadmap", Context: "docs"}); err == nil || !strings.Contains(err.Error(), "cannot be written") {
+		t.Fatalf("writing a permission = %v, want an error", err)
+	}
+}
+
+func TestRelationModelOpenFGAJSON(t *testing.T) {
+	model, err := parseRelationModel("openfga", `{"authorization_model": {"schema_version": "1.1", "type_definitions": [
+		{"type": "user"},
+		{"type": "document",
+		 "relations": {
+		   "owner": {"this": {}},
+		   "viewer": {"union": {"child": [{"this": {}}, {"computedUserset": {"relation": "owner"}}]}}
+		 },
+		 "metadata": {"relations": {
+		   "owner": {"directly_related_user_types": [{"type": "user"}]},
+		   "viewer": {"directly_related_user_types": [{"type": "user"}, {"type": "user", "wildcard": {}}]}
+		 }}}
+	]}}`)
+	if err != nil {
+		t.Fatalf("parseRelationModel: %v", err)
+	}
+	store := newRelationTupleStore()
+	_ = store.Upsert(RelationTuple{Subject: "user:alice", Relation: "owner", Object: "document:roadmap", Context: "docs"})
+	if got, err := relationModelCheck(model, store.List, RelationCheck{Subject: "user:alice", Relation: "viewer", Object: "document:roadmap", Context: "docs"}); err != nil || !got {
+		t.Fatalf("owner implies viewer = %v, %v", got, err)
+	}
+	if err := model.validateTuple(RelationTuple{Subject: "user:*", Relation: "viewer", Object: "document:roadmap", Context: "docs"}); err != nil {
+		t.Fatalf("validateTuple(user:*): %v", err)
+	}
+}
+
+func TestRelationModelValidateTuple(t *testing.T) {
+	model, err := parseRelationModel("", testOpenFGAModel)
+	if err != nil {
+		t.Fatalf("parseRelationModel: %v", err)
+	}
+	for _, tc := range []struct {
+		tuple RelationTuple
+		want  string
+	}{
+		{RelationTuple{Subject: "group:eng", Relation: "owner", Object: "document:roadmap"}, "subject type group is not allowed for document#owner (allowed: user)"},
+		{RelationTuple{Subject: "user:*", Relation: "viewer", Object: "document:roadmap"}, "subject type user:* is not allowed"},
+		{RelationTuple{Subject: "alice", Relation: "owner", Object: "document:roadmap"}, "must be written type:id"},
+		{RelationTuple{Subject: "user:alice", Relation: "owner", Object: "roadmap"}, `object "roadmap" must be written type:id`},
+		{RelationTuple{Subject: "user:alice", Relation: "approver", Object: "document:roadmap"}, `relation "approver" is not defined on type "document"`},
+	} {
+		if err := model.validateTuple(tc.tuple); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("validateTuple(%v) = %v, want %q", tc.tuple, err, tc.want)
+		}
+	}
+}
+
+func TestParseRelationModelErrors(t *testing.T) {
+	for name, tc := range map[string]struct {
+		format, text, want string
+	}{
+		"undefined subject type": {"", "model\n  schema 1.1\ntype doc\n  relations\n    define viewer: [team]", `subject type "team" is not defined`},
+		"undefined computed":     {"", "model\n  schema 1.1\ntype user\ntype doc\n  relations\n    define viewer: editor", `relation "editor" is not defined on doc`},
+		"mixed operators":        {"", "model\n  schema 1.1\ntype user\ntype doc\n  relations\n    define a: [user]\n    define b: [user] or a and a", "parentheses"},
+		"conditions":             {"", "model\n  schema 1.1\ntype user\ntype doc\n  relations\n    define a: [user with ip_allowed]", "conditions are not supported"},
+		"schema version":         {"", "model\n  schema 1.0\ntype user", "only schema 1.1"},
+		"spicedb caveat":         {"spicedb", "caveat ip(a int) { a > 1 }\ndefinition user {}", "caveats are not supported"},
+		"spicedb arrow target":   {"", "definition user {}\ndefinition doc {\n relation parent: user\n permission view = parent->view\n}", `no type related through "parent" defines "view"`},
+		"unknown format":         {"zanzibar", "", "openfga or spicedb"},
+	} {
+		if _, err := parseRelationModel(tc.format, tc.text); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: parseRelationModel = %v, want %q", name, err, tc.want)
+		}
+	}
+}
+
+func relationModelCasbinModule(t *testing.T, relationModel any) *CasbinModule {
+	t.Helper()
+	m, err := newCasbinModule("authz", map[string]any{
+		"model": `
+[request_definition]
+r = sub, obj, act
+
+[policy_definition]
+p = sub, obj, act
+
+[role_definition]
+g = _, _
+g2 = _, _, _
+
+[policy_effect]
+e = some(where (p.eft == allow))
+
+[matchers]
+m = g(r.sub, p.sub) && r.obj == p.obj && r.act == p.act
+`,
+		"relation_model": relationModel,
+	})
+	if err != nil {
+		t.Fatalf("newCasbinModule: %v", err)
+	}
+	if err := m.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	return m
+}
+
+func TestCasbinModuleRelationModel(t *testing.T) {
+	ctx := context.Background()
+	file := filepath.Join(t.TempDir(), "model.fga")
+	if err := os.WriteFile(file, []byte(testOpenFGAModel), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	m := relationModelCasbinModule(t, map[string]any{"format": "openfga", "file": file})
+	for _, tuple := range testRelationTuples {
+		if err := m.UpsertRelationTuple(ctx, tuple); err != nil {
+			t.Fatalf("UpsertRelationTuple(%v): %v", tuple, err)
+		}
+	}
+	if err := m.UpsertRelationTuple(ctx, RelationTuple{Subject: "group:eng", Relation: "owner", Object: "document:roadmap", Context: "docs"}); err == nil {
+		t.Fatal("expected a tuple with a disallowed subject type to be rejected")
+	}
+
+	result, err := m.CheckRelation(ctx, RelationCheck{Subject: "user:dave", Relation: "viewer", Object: "document:roadmap", Context: "docs"})
+	if err != nil || !result.Allowed {
+		t.Fatalf("CheckRelation through the parent folder = %#v, %v; want allowed", result, err)
+	}
+	result, _ = m.CheckRelation(ctx, RelationCheck{Subject: "user:carol", Relation: "viewer", Object: "document:roadmap", Context: "docs"})
+	if result.Allowed || result.Reason != "relation is not granted by the relation model" {
+		t.Fatalf("CheckRelation for blocked carol = %#v, want denied", result)
+	}
+	result, _ = m.CheckRelation(ctx, RelationCheck{Subject: "user:alice", Relation: "viewer", Object: "document:roadmap", Context: "other"})
+	if result.Allowed {
+		t.Fatalf("CheckRelation in another context = %#v, want denied", result)
+	}
+
+	// What-if simulations evaluate against the same model.
+	snapshot, err := m.simulationSnapshot()
+	if err != nil {
+		t.Fatalf("simulationSnapshot: %v", err)
+	}
+	clone := snapshot.(*CasbinModule)
+	if err := clone.RemoveRelationTuple(ctx, RelationTuple{Subject: "user:carol", Relation: "blocked", Object: "document:roadmap", Context: "docs"}); err != nil {
+		t.Fatalf("RemoveRelationTuple on clone: %v", err)
+	}
+	if result, _ := clone.CheckRelation(ctx, RelationCheck{Subject: "user:carol", Relation: "viewer", Object: "document:roadmap", Context: "docs"}); !result.Allowed {
+		t.Fatalf("clone CheckRelation after unblocking = %#v, want allowed", result)
+	}
+}
+
+func TestCasbinModuleRelationModelRequiresG2(t *testing.T) {
+	m, err := newCasbinModule("authz", map[string]any{
+		"model":          "[request_definition]\nr = sub, obj, act\n\n[policy_definition]\np = sub, obj, act\n\n[policy_effect]\ne = some(where (p.eft == allow))\n\n[matchers]\nm = r.sub == p.sub && r.obj == p.obj && r.act == p.act",
+		"relation_model": testSpiceDBSchema,
+	})
+	if err != nil {
+		t.Fatalf("newCasbinModule: %v", err)
+	}
+	if err := m.Init(); err == nil || !strings.Contains(err.Error(), "g2") {
+		t.Fatalf("Init = %v, want an error about g2", err)
+	}
+}
@@ -0,0 +1,486 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/GoCodeAlone/workflow-plugin-authz/internal/contracts"
+)
+
+// ErrProviderUnavailable reports that a remote provider could not be reached:
+// its requests failed at the network level, kept returning retryable
+// statuses, or were refused by an open circuit breaker.
+var ErrProviderUnavailable = errors.New("authz provider unavailable")
+
+const (
+	defaultTransportRetries          = 2
+	defaultTransportBackoff          = 100 * time.Millisecond
+	defaultTransportMaxBackoff       = 2 * time.Second
+	defaultTransportBreakerThreshold = 5
+	defaultTransportBreakerCooldown  = 30 * time.Second
+)
+
+// transportConfig configures the HTTP transport of a remote provider.
+type transportConfig struct {
+	// Timeout bounds each request attempt. Zero uses the module's default.
+	Timeout time.Duration `yaml:"timeout"`
+	// MaxRetries is how many times an idempotent request is retried after a
+	// network error or a retryable status (default 2).
+	MaxRetries int `yaml:"maxRetries"`
+	// Backoff is the base delay before the first retry, doubled for each
+	// further retry up to MaxBackoff, with full jitter.
+	Backoff    time.Duration `yaml:"backoff"`
+	MaxBackoff time.Duration `yaml:"maxBackoff"`
+	// BreakerThreshold is how many consecutive failed requests open the
+	// circuit breaker (default 5). While open, requests fail immediately
+	// with ErrProviderUnavailable; after BreakerCooldown one trial request is
+	// let through.
+	BreakerThreshold int           `yaml:"breakerThreshold"`
+	BreakerCooldown  time.Duration `yaml:"breakerCooldown"`
+}
+
+func parseTransportConfig(raw map[string]any) transportConfig {
+	cfg := transportConfig{
+		Timeout:          parseDurationValue(raw["timeout"]),
+		MaxRetries:       defaultTransportRetries,
+		Backoff:          parseDurationValue(raw["backoff"]),
+		MaxBackoff:       parseDurationValue(firstNonNil(raw["maxBackoff"], raw["max_backoff"])),
+		BreakerThreshold: intValue(firstNonNil(raw["breakerThreshold"], raw["breaker_threshold"])),
+		BreakerCooldown:  parseDurationValue(firstNonNil(raw["breakerCooldown"], raw["breaker_cooldown"])),
+	}
+	// Zero retries is meaningful, so only an absent value takes the default.
+	if retries := firstNonNil(raw["maxRetries"], raw["max_retries"]); retries != nil {
+		cfg.MaxRetries = max(intValue(retries), 0)
+	}
+	if cfg.Backoff <= 0 {
+		cfg.Backoff = defaultTransportBackoff
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = defaultTransportMaxBackoff
+	}
+	if cfg.BreakerThreshold <= 0 {
+		cfg.BreakerThreshold = defaultTransportBreakerThreshold
+	}
+	if cfg.BreakerCooldown <= 0 {
+		cfg.BreakerCooldown = defaultTransportBreakerCooldown
+	}
+	return cfg
+}
+
+// parseDurationValue parses a duration string such as "500ms"; anything else
+// is zero.
+func parseDurationValue(value any) time.Duration {
+	d, _ := time.ParseDuration(stringValue(value))
+	return d
+}
+
+func transportConfigToMap(cfg *contracts.TransportConfig) map[string]any {
+	out := compactMap(map[string]any{
+		"timeout":         cfg.GetTimeout(),
+		"backoff":         cfg.GetBackoff(),
+		"maxBackoff":      cfg.GetMaxBackoff(),
+		"breakerCooldown": cfg.GetBreakerCooldown(),
+	})
+	if cfg.MaxRetries != nil {
+		out["maxRetries"] = int(cfg.GetMaxRetries())
+	}
+	if cfg.GetBreakerThreshold() > 0 {
+		out["breakerThreshold"] = int(cfg.GetBreakerThreshold())
+	}
+	return out
+}
+
+// remoteTransport is the http.RoundTripper shared by a remote provider's
+// clients. It applies the per-attempt timeout, retries idempotent requests
+// with jittered exponential backoff, and trips a circuit breaker after
+// consecutive failures.
+type remoteTransport struct {
+	name   string
+	config transportConfig
+	base   http.RoundTripper
+
+	// now and sleep are replaced in tests.
+	now   func() time.Time
+	sleep func(context.Context, time.Duration) error
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+// newRemoteTransport returns a transport for the named provider, using
+// defaultTimeout per attempt unless config sets a timeout.
+func newRemoteTransport(name string, config transportConfig, defaultTimeout time.Duration) *remoteTransport {
+	if config.Timeout <= 0 {
+		config.Timeout = defaultTimeout
+	}
+	return &remoteTransport{
+		name:   name,
+		config: config,
+		base:   http.DefaultTransport,
+		now:    time.Now,
+		sleep:  sleepContext,
+	}
+}
+
+// client returns an http.Client that sends its requests through t.
+func (t *remoteTransport) client() *http.Client {
+	return &http.Client{Transport: t}
+}
+
+func (t *remoteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.allow(); err != nil {
+		markUnavailable(req.Context(), err)
+		return nil, err
+	}
+	retries := 0
+	if isIdempotentRequest(req) {
+		retries = t.config.MaxRetries
+	}
+	for attempt := 0; ; attempt++ {
+		resp, err := t.attempt(req, attempt)
+		if req.Context().Err() != nil {
+			// The caller gave up; that says nothing about the provider.
+			t.release()
+			return resp, err
+		}
+		if !retryableResult(resp, err) {
+			// A 500 or 501 is not worth retrying but still says the
+			// provider is failing, so only other answers close the breaker.
+			t.record(resp.StatusCode < http.StatusInternalServerError)
+			return resp, err
+		}
+		if attempt >= retries {
+			t.record(false)
+			unavailable := t.unavailableError(req, resp, err)
+			markUnavailable(req.Context(), unavailable)
+			return nil, unavailable
+		}
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		if err := t.sleep(req.Context(), t.backoff(attempt)); err != nil {
+			t.release()
+			return nil, err
+		}
+	}
+}
+
+// attempt sends one try of req under the per-attempt timeout. The timeout
+// is released when the response body is closed.
+func (t *remoteTransport) attempt(req *http.Request, attempt int) (*http.Response, error) {
+	if attempt > 0 && req.Body != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		req = req.Clone(req.Context())
+		req.Body = body
+	}
+	if t.config.Timeout <= 0 {
+		return t.base.RoundTrip(req)
+	}
+	ctx, cancel := context.WithTimeout(req.Context(), t.config.Timeout)
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+func (t *remoteTransport) unavailableError(req *http.Request, resp *http.Response, err error) error {
+	if resp != nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		return fmt.Errorf("%w: %s: %s %s: status %d", ErrProviderUnavailable, t.name, req.Method, req.URL.Redacted(), resp.StatusCode)
+	}
+	return fmt.Errorf("%w: %s: %w", ErrProviderUnavailable, t.name, err)
+}
+
+func (t *remoteTransport) backoff(attempt int) time.Duration {
+	delay := t.config.Backoff << attempt
+	if delay <= 0 || delay > t.config.MaxBackoff {
+		delay = t.config.MaxBackoff
+	}
+	return rand.N(delay) + 1
+}
+
+// allow refuses requests while the breaker is open, and lets a single trial
+// request through once the cooldown has passed.
+func (t *remoteTransport) allow() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.openUntil.IsZero() {
+		return nil
+	}
+	if t.now().Before(t.openUntil) || t.probing {
+		return fmt.Errorf("%w: %s: circuit breaker open", ErrProviderUnavailable, t.name)
+	}
+	t.probing = true
+	return nil
+}
+
+// release ends a trial request without counting it either way.
+func (t *remoteTransport) release() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.probing = false
+}
+
+func (t *remoteTransport) record(ok bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.probing = false
+	if ok {
+		t.failures = 0
+		t.openUntil = time.Time{}
+		return
+	}
+	t.failures++
+	if t.failures >= t.config.BreakerThreshold {
+		t.openUntil = t.now().Add(t.config.BreakerCooldown)
+	}
+}
+
+// retryableResult reports whether a request failed in a way another attempt
+// might not: a network error, or a status that signals overload or an
+// unreachable upstream.
+func retryableResult(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+type idempotentKey struct{}
+
+// withIdempotent marks requests made under ctx as safe to retry even when
+// their method is not, such as a POST that only reads a decision.
+func withIdempotent(ctx context.Context) context.Context {
+	return context.WithValue(ctx, idempotentKey{}, true)
+}
+
+func isIdempotentRequest(req *http.Request) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	marked, _ := req.Context().Value(idempotentKey{}).(bool)
+	return marked
+}
+
+// remoteCall records whether the requests made under a context found the
+// provider unavailable. Provider SDKs do not all keep the transport error in
+// the errors they return, so checks consult it instead.
+type remoteCall struct {
+	mu  sync.Mutex
+	err error
+}
+
+type remoteCallKey struct{}
+
+func withRemoteCall(ctx context.Context) (context.Context, *remoteCall) {
+	call := &remoteCall{}
+	return context.WithValue(ctx, remoteCallKey{}, call), call
+}
+
+func markUnavailable(ctx context.Context, err error) {
+	if call, ok := ctx.Value(remoteCallKey{}).(*remoteCall); ok {
+		call.mu.Lock()
+		call.err = err
+		call.mu.Unlock()
+	}
+}
+
+// unavailable returns err wrapped with ErrProviderUnavailable when a request
+// of the call found the provider unavailable, and err otherwise.
+func (c *remoteCall) unavailable(err error) error {
+	if err == nil || errors.Is(err, ErrProviderUnavailable) {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err == nil {
+		return err
+	}
+	return fmt.Errorf("%w: %w", c.err, err)
+}
+
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// unavailablePolicy is what a check does when its provider is unavailable,
+// configured as on_unavailable: "deny", "allow", or "fallback:<module>".
+// Without a policy the check returns the error.
+type unavailablePolicy struct {
+	Action   string
+	Fallback string
+}
+
+func parseUnavailablePolicy(value string) (unavailablePolicy, error) {
+	value = strings.TrimSpace(value)
+	switch {
+	case value == "":
+		return unavailablePolicy{}, nil
+	case value == "deny" || value == "allow":
+		return unavailablePolicy{Action: value}, nil
+	case strings.HasPrefix(value, "fallback:") && strings.TrimSpace(strings.TrimPrefix(value, "fallback:")) != "":
+		return unavailablePolicy{Action: "fallback", Fallback: strings.TrimSpace(strings.TrimPrefix(value, "fallback:"))}, nil
+	default:
+		return unavailablePolicy{}, fmt.Errorf("on_unavailable must be deny, allow, or fallback:<module>, got %q", value)
+	}
+}
+
+func (p unavailablePolicy) String() string {
+	if p.Action == "fallback" {
+		return "fallback:" + p.Fallback
+	}
+	return p.Action
+}
+
+// applies reports whether the policy handles err.
+func (p unavailablePolicy) applies(err error) bool {
+	return p.Action != "" && errors.Is(err, ErrProviderUnavailable)
+}
+
+func (p unavailablePolicy) reason(err error) string {
+	return fmt.Sprintf("%v; on_unavailable: %s", err, p)
+}
+
+// unavailableFallbacker is implemented by modules that take an
+// on_unavailable policy.
+type unavailableFallbacker interface {
+	onUnavailable() unavailablePolicy
+}
+
+// validateFallbackChain follows the on_unavailable fallbacks starting at the
+// named module and rejects a chain that leads back to a module already in
+// it, which would otherwise recurse while the providers are down. Modules
+// not registered yet end the walk; the cycle is caught when they initialize.
+func validateFallbackChain(registry authzProviderRegistry, name string, policy unavailablePolicy) error {
+	chain := []string{name}
+	seen := map[string]bool{name: true}
+	for policy.Action == "fallback" {
+		chain = append(chain, policy.Fallback)
+		if seen[policy.Fallback] {
+			return fmt.Errorf("on_unavailable fallbacks form a cycle: %s", strings.Join(chain, " -> "))
+		}
+		seen[policy.Fallback] = true
+		provider, ok := registry.GetAuthzProvider(policy.Fallback)
+		if !ok {
+			return nil
+		}
+		next, ok := provider.(unavailableFallbacker)
+		if !ok {
+			return nil
+		}
+		policy = next.onUnavailable()
+	}
+	return nil
+}
+
+// fallbackProvider returns the module named by a fallback policy.
+func (p unavailablePolicy) fallbackProvider() (AuthzProvider, error) {
+	provider, ok := globalRegistry.GetAuthzProvider(p.Fallback)
+	if !ok {
+		return nil, fmt.Errorf("on_unavailable fallback module %q not found", p.Fallback)
+	}
+	return provider, nil
+}
+
+// checkScope applies the policy to a scope check that failed with err.
+func (p unavailablePolicy) checkScope(ctx context.Context, check ScopeCheck, result ScopeCheckResult, err error) (ScopeCheckResult, error) {
+	if !p.applies(err) {
+		return result, err
+	}
+	switch p.Action {
+	case "fallback":
+		provider, ferr := p.fallbackProvider()
+		if ferr != nil {
+			return result, fmt.Errorf("%w; %w", err, ferr)
+		}
+		scopes, ok := provider.(ScopeRoleProvider)
+		if !ok {
+			return result, fmt.Errorf("%w; on_unavailable fallback module %q does not implement RBAC scope checks", err, p.Fallback)
+		}
+		return scopes.CheckScope(ctx, check)
+	default:
+		return ScopeCheckResult{Allowed: p.Action == "allow", Subject: check.Subject, Context: check.Context, Scope: check.Scope, Reason: p.reason(err)}, nil
+	}
+}
+
+// checkRelation applies the policy to a relation check that failed with err.
+func (p unavailablePolicy) checkRelation(ctx context.Context, check RelationCheck, result RelationCheckResult, err error) (RelationCheckResult, error) {
+	if !p.applies(err) {
+		return result, err
+	}
+	switch p.Action {
+	case "fallback":
+		provider, ferr := p.fallbackProvider()
+		if ferr != nil {
+			return result, fmt.Errorf("%w; %w", err, ferr)
+		}
+		relations, ok := provider.(RelationshipProvider)
+		if !ok {
+			return result, fmt.Errorf("%w; on_unavailable fallback module %q does not implement ReBAC checks", err, p.Fallback)
+		}
+		return relations.CheckRelation(ctx, check)
+	default:
+		return RelationCheckResult{Allowed: p.Action == "allow", Subject: check.Subject, Relation: check.Relation, Object: check.Object, Context: check.Context, Reason: p.reason(err)}, nil
+	}
+}
+
+// decide applies the policy to an authorization decision that failed with
+// err.
+func (p unavailablePolicy) decide(ctx context.Context, input AuthorizationDecisionInput, err error) (AuthorizationDecisionOutput, error) {
+	if !p.applies(err) {
+		return AuthorizationDecisionOutput{}, err
+	}
+	switch p.Action {
+	case "fallback":
+		provider, ferr := p.fallbackProvider()
+		if ferr != nil {
+			return AuthorizationDecisionOutput{}, fmt.Errorf("%w; %w", err, ferr)
+		}
+		return DecideAuthorization(ctx, provider, input)
+	default:
+		return AuthorizationDecisionOutput{Allowed: p.Action == "allow", Mode: input.Mode, Subject: input.Subject, Context: input.Context, Reason: p.reason(err)}, nil
+	}
+}
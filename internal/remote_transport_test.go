@@ -0,0 +1,276 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestTransport(config map[string]any) *remoteTransport {
+	transport := newRemoteTransport("remote", parseTransportConfig(config), time.Second)
+	transport.sleep = func(context.Context, time.Duration) error { return nil }
+	return transport
+}
+
+func TestRemoteTransportRetriesIdempotentRequests(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	client := newTestTransport(nil).client()
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || hits.Load() != 3 {
+		t.Fatalf("GET = %d after %d attempts, want 200 after 3", resp.StatusCode, hits.Load())
+	}
+
+	// A POST is sent once unless its context marks it idempotent.
+	hits.Store(0)
+	if _, err := client.Post(server.URL, "application/json", strings.NewReader("{}")); !errors.Is(err, ErrProviderUnavailable) {
+		t.Fatalf("POST error = %v, want ErrProviderUnavailable", err)
+	}
+	if hits.Load() != 1 {
+		t.Fatalf("POST attempts = %d, want 1", hits.Load())
+	}
+	hits.Store(0)
+	req, _ := http.NewRequestWithContext(withIdempotent(context.Background()), http.MethodPost, server.URL, strings.NewReader("{}"))
+	resp, err = client.Do(req)
+	if err != nil {
+		t.Fatalf("idempotent POST: %v", err)
+	}
+	resp.Body.Close()
+	if hits.Load() != 3 {
+		t.Fatalf("idempotent POST attempts = %d, want 3", hits.Load())
+	}
+}
+
+func TestRemoteTransportCircuitBreaker(t *testing.T) {
+	var hits atomic.Int32
+	var healthy atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+	transport := newTestTransport(map[string]any{"max_retries": 0, "breaker_threshold": 2, "breaker_cooldown": "1m"})
+	now := time.Now()
+	transport.now = func() time.Time { return now }
+	client := transport.client()
+
+	for range 2 {
+		if _, err := client.Get(server.URL); !errors.Is(err, ErrProviderUnavailable) {
+			t.Fatalf("GET error = %v, want ErrProviderUnavailable", err)
+		}
+	}
+	if _, err := client.Get(server.URL); !errors.Is(err, ErrProviderUnavailable) || !strings.Contains(err.Error(), "circuit breaker open") {
+		t.Fatalf("GET with open breaker = %v", err)
+	}
+	if hits.Load() != 2 {
+		t.Fatalf("requests reached the server while the breaker was open: %d", hits.Load())
+	}
+
+	// After the cooldown a trial request goes through and closes the breaker.
+	healthy.Store(true)
+	now = now.Add(time.Minute)
+	for range 2 {
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("GET after cooldown: %v", err)
+		}
+		resp.Body.Close()
+	}
+	if hits.Load() != 4 {
+		t.Fatalf("server hits = %d, want 4", hits.Load())
+	}
+}
+
+func TestRemoteTransportCountsServerErrorsAsFailures(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	transport := newTestTransport(map[string]any{"breaker_threshold": 2, "breaker_cooldown": "1m"})
+	client := transport.client()
+
+	// A 500 is returned without a retry, but still trips the breaker.
+	for range 2 {
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("GET: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusInternalServerError {
+			t.Fatalf("GET status = %d, want 500", resp.StatusCode)
+		}
+	}
+	if _, err := client.Get(server.URL); !errors.Is(err, ErrProviderUnavailable) {
+		t.Fatalf("GET after repeated 500s = %v, want the breaker open", err)
+	}
+	if hits.Load() != 2 {
+		t.Fatalf("server hits = %d, want 2", hits.Load())
+	}
+}
+
+func TestRemoteTransportTimesOutEachAttempt(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+	client := newTestTransport(map[string]any{"timeout": "20ms", "max_retries": 1}).client()
+	start := time.Now()
+	if _, err := client.Get(server.URL); !errors.Is(err, ErrProviderUnavailable) {
+		t.Fatalf("GET error = %v, want ErrProviderUnavailable", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("GET took %v despite the per-attempt timeout", elapsed)
+	}
+}
+
+func TestParseUnavailablePolicy(t *testing.T) {
+	for value, want := range map[string]unavailablePolicy{
+		"":                unavailablePolicy{},
+		"deny":            {Action: "deny"},
+		"allow":           {Action: "allow"},
+		"fallback:casbin": {Action: "fallback", Fallback: "casbin"},
+	} {
+		got, err := parseUnavailablePolicy(value)
+		if err != nil || got != want {
+			t.Errorf("parseUnavailablePolicy(%q) = %#v, %v; want %#v", value, got, err, want)
+		}
+	}
+	for _, value := range []string{"open", "fallback:"} {
+		if _, err := parseUnavailablePolicy(value); err == nil {
+			t.Errorf("parseUnavailablePolicy(%q) succeeded, want error", value)
+		}
+	}
+}
+
+// newUnavailableKetoModule returns an authz.keto module whose Keto refuses
+// connections.
+func newUnavailableKetoModule(t *testing.T, name string, onUnavailable string) *KetoModule {
+	t.Helper()
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	module, err := newKetoModule(name, map[string]any{
+		"read_url":       server.URL,
+		"write_url":      server.URL,
+		"on_unavailable": onUnavailable,
+		"transport":      map[string]any{"max_retries": 0},
+	})
+	if err != nil {
+		t.Fatalf("newKetoModule: %v", err)
+	}
+	if err := module.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	return module
+}
+
+func TestKetoModuleOnUnavailable(t *testing.T) {
+	ctx := context.Background()
+	check := RelationCheck{Subject: "alice", Relation: "viewer", Object: "doc1", Context: "docs"}
+
+	if _, err := newUnavailableKetoModule(t, "keto-down-error", "").CheckRelation(ctx, check); !errors.Is(err, ErrProviderUnavailable) {
+		t.Fatalf("CheckRelation without a policy = %v, want ErrProviderUnavailable", err)
+	}
+	result, err := newUnavailableKetoModule(t, "keto-down-deny", "deny").CheckRelation(ctx, check)
+	if err != nil || result.Allowed || !strings.Contains(result.Reason, "on_unavailable: deny") {
+		t.Fatalf("CheckRelation with deny = %#v, %v", result, err)
+	}
+	result, err = newUnavailableKetoModule(t, "keto-down-allow", "allow").CheckRelation(ctx, check)
+	if err != nil || !result.Allowed {
+		t.Fatalf("CheckRelation with allow = %#v, %v", result, err)
+	}
+
+	fallback := NewMemoryProvider("keto-fallback")
+	if err := fallback.UpsertRelationTuple(ctx, RelationTuple(check)); err != nil {
+		t.Fatalf("UpsertRelationTuple: %v", err)
+	}
+	RegisterAuthzProvider("keto-fallback", fallback)
+	result, err = newUnavailableKetoModule(t, "keto-down-fallback", "fallback:keto-fallback").CheckRelation(ctx, check)
+	if err != nil || !result.Allowed {
+		t.Fatalf("CheckRelation with fallback = %#v, %v", result, err)
+	}
+
+	if _, err := newKetoModule("keto-self", map[string]any{"on_unavailable": "fallback:keto-self"}); err == nil {
+		t.Fatal("expected an error for a module that falls back to itself")
+	}
+}
+
+func TestKetoModuleStartsWhenKetoIsUnreachable(t *testing.T) {
+	ctx := context.Background()
+	module := newUnavailableKetoModule(t, "keto-down-start", "deny")
+	if err := module.Start(ctx); err != nil {
+		t.Fatalf("Start with Keto unreachable = %v, want the module started", err)
+	}
+	t.Cleanup(func() { _ = module.Stop(context.Background()) })
+	result, err := module.CheckRelation(ctx, RelationCheck{Subject: "alice", Relation: "viewer", Object: "doc1", Context: "docs"})
+	if err != nil || result.Allowed || !strings.Contains(result.Reason, "on_unavailable: deny") {
+		t.Fatalf("CheckRelation after degraded start = %#v, %v", result, err)
+	}
+}
+
+func TestOnUnavailableFallbackCycleIsRejected(t *testing.T) {
+	first, err := newKetoModule("keto-cycle-a", map[string]any{"on_unavailable": "fallback:keto-cycle-b"})
+	if err != nil {
+		t.Fatalf("newKetoModule: %v", err)
+	}
+	second, err := newKetoModule("keto-cycle-b", map[string]any{"on_unavailable": "fallback:keto-cycle-a"})
+	if err != nil {
+		t.Fatalf("newKetoModule: %v", err)
+	}
+	RegisterAuthzProvider(first.Name(), first)
+	RegisterAuthzProvider(second.Name(), second)
+	err = first.Init()
+	if err == nil || !strings.Contains(err.Error(), "keto-cycle-a -> keto-cycle-b -> keto-cycle-a") {
+		t.Fatalf("Init with a fallback cycle = %v, want a cycle error", err)
+	}
+}
+
+func TestAuthzCheckStepOnUnavailable(t *testing.T) {
+	module := newUnavailableKetoModule(t, "keto-down-step", "")
+	RegisterAuthzProvider(module.Name(), module)
+	step, err := newAuthzDecisionStep("check", map[string]any{
+		"module":         module.Name(),
+		"provider":       "keto",
+		"mode":           "rebac",
+		"subject":        "alice",
+		"context":        "docs",
+		"resource":       "doc1",
+		"relation":       "viewer",
+		"on_unavailable": "deny",
+	})
+	if err != nil {
+		t.Fatalf("newAuthzDecisionStep: %v", err)
+	}
+	result, err := step.Execute(context.Background(), nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if result.Output["allowed"] != false || !strings.Contains(stringValue(result.Output["reason"]), "unavailable") {
+		t.Fatalf("output = %#v, want a denial for the unavailable provider", result.Output)
+	}
+}
@@ -0,0 +1,337 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/GoCodeAlone/workflow-plugin-authz/internal/contracts"
+)
+
+// resolveScopeAlias follows replaced_by from name through declared and returns
+// the scope that currently stands for it. Undeclared names resolve to
+// themselves, as do names whose replaced_by chain loops or reaches an
+// undeclared scope.
+func resolveScopeAlias(declared map[string]*contracts.ScopeDeclaration, name string) string {
+	current := name
+	seen := map[string]bool{name: true}
+	for {
+		scope, ok := declared[current]
+		if !ok || scope.GetReplacedBy() == "" {
+			return current
+		}
+		next := scope.GetReplacedBy()
+		if _, ok := declared[next]; !ok || seen[next] {
+			return name
+		}
+		seen[next] = true
+		current = next
+	}
+}
+
+// validateScopeReplacements rejects scopes in scopes whose replaced_by names a
+// scope that is neither in scopes nor in declared.
+func validateScopeReplacements(declared map[string]*contracts.ScopeDeclaration, scopes []*contracts.ScopeDeclaration) error {
+	names := map[string]bool{}
+	for _, scope := range scopes {
+		names[scope.GetName()] = true
+	}
+	for _, scope := range scopes {
+		target := scope.GetReplacedBy()
+		if target == "" {
+			continue
+		}
+		if _, ok := declared[target]; !ok && !names[target] {
+			return fmt.Errorf("scope %q is replaced by undeclared scope %q", scope.GetName(), target)
+		}
+	}
+	return nil
+}
+
+// validateReplacementsLocked rejects the scopes and resources of set whose
+// replaced_by names a declaration that is neither in set nor in the catalog.
+// A nil set checks the whole catalog.
+func (m *scopeCatalogModule) validateReplacementsLocked(set *contracts.AuthzDeclarationSet) error {
+	scopes, resources := set.GetScopes(), set.GetResources()
+	if set == nil {
+		for _, key := range sortedKeys(m.scopes) {
+			scopes = append(scopes, m.scopes[key])
+		}
+		for _, key := range sortedKeys(m.resources) {
+			resources = append(resources, m.resources[key])
+		}
+	}
+	if err := validateScopeReplacements(m.scopes, scopes); err != nil {
+		return err
+	}
+	keys := map[string]bool{}
+	for _, resource := range resources {
+		keys[resourceKey(resource.GetContext(), resource.GetName())] = true
+	}
+	for _, resource := range resources {
+		if resource.GetReplacedBy() == "" {
+			continue
+		}
+		key := resourceKey(resource.GetContext(), resource.GetReplacedBy())
+		if _, ok := m.resources[key]; !ok && !keys[key] {
+			return fmt.Errorf("resource %q in context %q is replaced by undeclared resource %q", resource.GetName(), resource.GetContext(), resource.GetReplacedBy())
+		}
+	}
+	return nil
+}
+
+// inheritResourceLifecycleLocked applies resource deprecations to their
+// scopes. A scope of a deprecated resource is deprecated, and a scope of a
+// replaced resource is replaced by the scope with the same actions on the
+// replacement resource, when one is declared. Flags a scope declares itself
+// are kept. It returns copies of the scopes it changed.
+func (m *scopeCatalogModule) inheritResourceLifecycleLocked() []*contracts.ScopeDeclaration {
+	var changed []*contracts.ScopeDeclaration
+	for _, name := range sortedKeys(m.scopes) {
+		scope := m.scopes[name]
+		resource, ok := m.resources[resourceKey(scope.GetContext(), scope.GetResource())]
+		if !ok {
+			continue
+		}
+		updated := false
+		if resource.GetDeprecated() && !scope.GetDeprecated() {
+			scope.Deprecated = true
+			updated = true
+		}
+		if resource.GetReplacedBy() != "" && scope.GetReplacedBy() == "" {
+			if target := m.replacementScopeLocked(scope, resource.GetReplacedBy()); target != "" {
+				scope.ReplacedBy = target
+				updated = true
+			}
+		}
+		if updated {
+			changed = append(changed, cloneScopeDeclaration(scope))
+		}
+	}
+	return changed
+}
+
+// replacementScopeLocked returns the scope on resource, in scope's context,
+// with the same actions as scope.
+func (m *scopeCatalogModule) replacementScopeLocked(scope *contracts.ScopeDeclaration, resource string) string {
+	actions := uniqueStrings(scope.GetActions())
+	sort.Strings(actions)
+	for _, name := range sortedKeys(m.scopes) {
+		candidate := m.scopes[name]
+		if candidate.GetContext() != scope.GetContext() || candidate.GetResource() != resource {
+			continue
+		}
+		candidateActions := uniqueStrings(candidate.GetActions())
+		sort.Strings(candidateActions)
+		if slices.Equal(actions, candidateActions) {
+			return name
+		}
+	}
+	return ""
+}
+
+// mergeScopes returns scopes with every scope of changed replacing the one of
+// the same name, and the remaining changed scopes appended.
+func mergeScopes(scopes, changed []*contracts.ScopeDeclaration) []*contracts.ScopeDeclaration {
+	byName := map[string]*contracts.ScopeDeclaration{}
+	for _, scope := range changed {
+		byName[scope.GetName()] = scope
+	}
+	out := make([]*contracts.ScopeDeclaration, 0, len(scopes)+len(changed))
+	for _, scope := range scopes {
+		if replacement, ok := byName[scope.GetName()]; ok {
+			scope = replacement
+			delete(byName, scope.GetName())
+		}
+		out = append(out, scope)
+	}
+	for _, scope := range changed {
+		if _, ok := byName[scope.GetName()]; ok {
+			out = append(out, scope)
+		}
+	}
+	return out
+}
+
+// scopeRenames maps every declared scope with a replaced_by to the scope that
+// finally replaces it.
+func scopeRenames(declared map[string]*contracts.ScopeDeclaration) map[string]string {
+	renames := map[string]string{}
+	for name, scope := range declared {
+		if scope.GetReplacedBy() == "" {
+			continue
+		}
+		if target := resolveScopeAlias(declared, name); target != name {
+			renames[name] = target
+		}
+	}
+	return renames
+}
+
+// scopeDeprecations describes why a check for requested, resolved to target,
+// is deprecated. It returns nil when neither name is deprecated.
+func scopeDeprecations(declared map[string]*contracts.ScopeDeclaration, requested, target string) []string {
+	if requested != target {
+		return []string{fmt.Sprintf("scope %q is deprecated; use %q", requested, target)}
+	}
+	if scope, ok := declared[target]; ok && scope.GetDeprecated() {
+		return []string{fmt.Sprintf("scope %q is deprecated", target)}
+	}
+	return nil
+}
+
+// rewriteScopes replaces renamed scopes and reports each replacement as a
+// [from, to] pair. Wildcard patterns are left alone.
+func rewriteScopes(scopes []string, renames map[string]string) ([]string, [][2]string) {
+	out := make([]string, 0, len(scopes))
+	var changes [][2]string
+	for _, scope := range scopes {
+		if to, ok := renames[scope]; ok {
+			changes = append(changes, [2]string{scope, to})
+			scope = to
+		}
+		out = append(out, scope)
+	}
+	return uniqueStrings(out), changes
+}
+
+// matchGrantLocked returns the grant that grants target, either directly, by
+// pattern, or through a grant on a name that has since been replaced by
+// target.
+func (s *scopeRoleStore) matchGrantLocked(grants []string, target string) (string, bool) {
+	if matched, ok := matchScopeGrant(grants, target); ok {
+		return matched, true
+	}
+	for _, grant := range grants {
+		if !isScopePattern(grant) && resolveScopeAlias(s.scopes, grant) == target {
+			return grant, true
+		}
+	}
+	return "", false
+}
+
+// scopeRenames returns the renames declared in this store.
+func (s *scopeRoleStore) scopeRenames() map[string]string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return scopeRenames(s.scopes)
+}
+
+// migrationCandidates returns copies of the role grants and direct-scope
+// assignments in contextName (every context when empty) that reference a
+// renamed scope.
+func (s *scopeRoleStore) migrationCandidates(renames map[string]string, contextName string) ([]RoleScopeGrant, []SubjectRoleAssignment) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	references := func(scopes []string) bool {
+		for _, scope := range scopes {
+			if _, ok := renames[scope]; ok {
+				return true
+			}
+		}
+		return false
+	}
+	var grants []RoleScopeGrant
+	for _, key := range sortedKeys(s.roles) {
+		grant := s.roles[key]
+		if (contextName == "" || grant.Context == contextName) && references(grant.Scopes) {
+			grants = append(grants, cloneRoleScopeGrant(grant))
+		}
+	}
+	var assignments []SubjectRoleAssignment
+	for _, assignment := range s.assigns {
+		if (contextName == "" || assignment.Context == contextName) && references(assignment.DirectScopes) {
+			assignments = append(assignments, cloneSubjectRoleAssignment(assignment))
+		}
+	}
+	return grants, assignments
+}
+
+// migrateScopes rewrites role grants and direct scopes that still name a
+// replaced scope, in every provider the catalog's registry knows about. The
+// catalog's replaced_by declarations are combined with each provider's own.
+// Grants are rewritten through the provider's UpsertRole and AssignRole, so
+// remote providers are updated as well; authz.keto deletes the tuples of the
+// replaced names.
+func (m *scopeCatalogModule) migrateScopes(ctx context.Context, input *contracts.MigrateScopesInput) (*contracts.MigrateScopesOutput, error) {
+	lister, ok := m.registry.(authzProviderLister)
+	if !ok {
+		return nil, fmt.Errorf("registry cannot enumerate providers")
+	}
+	m.mu.RLock()
+	catalogRenames := scopeRenames(m.scopes)
+	m.mu.RUnlock()
+
+	out := &contracts.MigrateScopesOutput{}
+	for _, provider := range lister.authzProviders() {
+		roles, ok := provider.(ScopeRoleProvider)
+		if !ok {
+			continue
+		}
+		source, ok := provider.(scopeRoleStoreProvider)
+		if !ok || source.scopeRoleStore() == nil {
+			continue
+		}
+		store := source.scopeRoleStore()
+		renames := store.scopeRenames()
+		for from, to := range catalogRenames {
+			renames[from] = to
+		}
+		grants, assignments := store.migrationCandidates(renames, input.GetContext())
+		for _, grant := range grants {
+			scopes, changes := rewriteScopes(grant.Scopes, renames)
+			for _, change := range changes {
+				out.Migrations = append(out.Migrations, &contracts.ScopeMigration{
+					Provider: roles.Name(), Context: grant.Context, Role: grant.Role, From: change[0], To: change[1],
+				})
+			}
+			if input.GetDryRun() {
+				continue
+			}
+			grant.Scopes = scopes
+			if err := roles.UpsertRole(ctx, grant); err != nil {
+				return nil, fmt.Errorf("provider %q: migrate role %q: %w", roles.Name(), grant.Role, err)
+			}
+		}
+		for _, assignment := range assignments {
+			scopes, changes := rewriteScopes(assignment.DirectScopes, renames)
+			for _, change := range changes {
+				out.Migrations = append(out.Migrations, &contracts.ScopeMigration{
+					Provider: roles.Name(), Context: assignment.Context, Role: assignment.Role, Subject: assignment.Subject, From: change[0], To: change[1],
+				})
+			}
+			if input.GetDryRun() {
+				continue
+			}
+			assignment.DirectScopes = scopes
+			if err := roles.AssignRole(ctx, assignment); err != nil {
+				return nil, fmt.Errorf("provider %q: migrate direct scopes of %q: %w", roles.Name(), assignment.Subject, err)
+			}
+		}
+	}
+	out.Migrated = int32(len(out.Migrations))
+	return out, nil
+}
+
+func migrateScopesInputFromMap(values map[string]any) *contracts.MigrateScopesInput {
+	return &contracts.MigrateScopesInput{
+		Context: stringValue(values["context"]),
+		DryRun:  boolValue(values["dry_run"]),
+	}
+}
+
+func migrateScopesOutputToMap(out *contracts.MigrateScopesOutput) map[string]any {
+	migrations := make([]map[string]any, 0, len(out.GetMigrations()))
+	for _, migration := range out.GetMigrations() {
+		migrations = append(migrations, compactMap(map[string]any{
+			"provider": migration.GetProvider(),
+			"context":  migration.GetContext(),
+			"role":     migration.GetRole(),
+			"subject":  migration.GetSubject(),
+			"from":     migration.GetFrom(),
+			"to":       migration.GetTo(),
+		}))
+	}
+	return map[string]any{"migrated": int(out.GetMigrated()), "migrations": migrations}
+}
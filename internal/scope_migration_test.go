@@ -0,0 +1,187 @@
+package internal
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GoCodeAlone/workflow-plugin-authz/internal/contracts"
+)
+
+func TestMigrateScopesRewritesGrantsAcrossProviders(t *testing.T) {
+	ctx := context.Background()
+	mod := rbacTestModule(t, nil, nil)
+	if err := mod.DeclareScopes(ctx, []*contracts.ScopeDeclaration{
+		{Name: "shop:order:read", Context: "shop", Resource: "order", Actions: []string{"read"}},
+		{Name: "shop:orders:read", Context: "shop", Resource: "orders", Actions: []string{"read"}},
+	}); err != nil {
+		t.Fatalf("DeclareScopes: %v", err)
+	}
+	if err := mod.UpsertRole(ctx, RoleScopeGrant{Role: "clerk", Context: "shop", Scopes: []string{"shop:order:read"}}); err != nil {
+		t.Fatalf("UpsertRole: %v", err)
+	}
+	if err := mod.AssignRole(ctx, SubjectRoleAssignment{Subject: "alice", Context: "shop", DirectScopes: []string{"shop:order:read"}}); err != nil {
+		t.Fatalf("AssignRole: %v", err)
+	}
+
+	catalog := newScopeCatalogModule("catalog", map[string]any{
+		"scopes": []any{
+			map[string]any{"name": "shop:order:read", "context": "shop", "deprecated": true, "replaced_by": "shop:orders:read"},
+			map[string]any{"name": "shop:orders:read", "context": "shop", "version": "2"},
+		},
+	})
+	catalog.registry = &ownershipTestRegistry{testRegistry{mod: mod}}
+
+	out, err := catalog.InvokeMethod("MigrateScopes", map[string]any{"dry_run": true})
+	if err != nil {
+		t.Fatalf("MigrateScopes dry run: %v", err)
+	}
+	if out["migrated"] != 2 {
+		t.Fatalf("dry run = %v, want 2 migrations", out)
+	}
+	if scopes := mod.scopeRoleStore().roleScopes("shop", "clerk"); scopes[0] != "shop:order:read" {
+		t.Fatalf("dry run rewrote role scopes to %v", scopes)
+	}
+
+	out, err = catalog.InvokeMethod("MigrateScopes", map[string]any{"context": "shop"})
+	if err != nil {
+		t.Fatalf("MigrateScopes: %v", err)
+	}
+	migrations := out["migrations"].([]map[string]any)
+	if len(migrations) != 2 || migrations[0]["role"] != "clerk" || migrations[1]["subject"] != "alice" || migrations[1]["to"] != "shop:orders:read" {
+		t.Fatalf("migrations = %v, want the role and the direct scope rewritten", migrations)
+	}
+	if scopes := mod.scopeRoleStore().roleScopes("shop", "clerk"); len(scopes) != 1 || scopes[0] != "shop:orders:read" {
+		t.Fatalf("role scopes = %v, want shop:orders:read", scopes)
+	}
+	if out, _ := catalog.InvokeMethod("MigrateScopes", nil); out["migrated"] != 0 {
+		t.Fatalf("second migration = %v, want nothing left to migrate", out)
+	}
+
+	resolved, err := catalog.InvokeMethod("ResolveSubjectScopes", map[string]any{"direct_scopes": []any{"shop:order:read"}})
+	if err != nil {
+		t.Fatalf("ResolveSubjectScopes: %v", err)
+	}
+	if scopes := resolved["scopes"].([]string); len(scopes) != 1 || scopes[0] != "shop:orders:read" || len(resolved["deprecations"].([]string)) != 1 {
+		t.Fatalf("resolved = %v, want the replacement scope and a deprecation", resolved)
+	}
+}
+
+// ketoMigrationRegistry lists a single Keto module to MigrateScopes.
+type ketoMigrationRegistry struct {
+	testRegistry
+	keto *KetoModule
+}
+
+func (r *ketoMigrationRegistry) authzProviders() []AuthzProvider {
+	return []AuthzProvider{r.keto}
+}
+
+func TestMigrateScopesDeletesReplacedKetoTuples(t *testing.T) {
+	ctx := context.Background()
+	client := &fakeKetoClient{checks: map[ketoTuple]bool{}}
+	keto := &KetoModule{name: "keto", provider: newKetoScopeProvider("keto", client), namespaces: newRelationNamespaces()}
+	if err := keto.DeclareScopes(ctx, []*contracts.ScopeDeclaration{
+		{Name: "shop:orders:read", Context: "shop", Resource: "orders", Actions: []string{"read"}},
+		{Name: "shop:order:read", Context: "shop", Resource: "order", Actions: []string{"read"}, ReplacedBy: "shop:orders:read"},
+	}); err != nil {
+		t.Fatalf("DeclareScopes: %v", err)
+	}
+	if err := keto.UpsertRole(ctx, RoleScopeGrant{Role: "clerk", Context: "shop", Scopes: []string{"shop:order:read"}}); err != nil {
+		t.Fatalf("UpsertRole: %v", err)
+	}
+	if err := keto.AssignRole(ctx, SubjectRoleAssignment{Subject: "alice", Context: "shop", DirectScopes: []string{"shop:order:read"}}); err != nil {
+		t.Fatalf("AssignRole: %v", err)
+	}
+
+	catalog := newScopeCatalogModule("keto-migration-catalog", nil)
+	catalog.registry = &ketoMigrationRegistry{keto: keto}
+	out, err := catalog.InvokeMethod("MigrateScopes", nil)
+	if err != nil {
+		t.Fatalf("MigrateScopes: %v", err)
+	}
+	if out["migrated"] != 2 {
+		t.Fatalf("MigrateScopes = %v, want 2 migrations", out)
+	}
+	if client.wrote(ketoRoleScopeTuple("shop", "clerk", "shop:order:read")) || client.wrote(ketoDirectScopeTuple("alice", "shop:order:read")) {
+		t.Fatalf("old scope tuples were left in Keto: %#v", client.tuples)
+	}
+	if !client.wrote(ketoRoleScopeTuple("shop", "clerk", "shop:orders:read")) || !client.wrote(ketoDirectScopeTuple("alice", "shop:orders:read")) {
+		t.Fatalf("new scope tuples were not written: %#v", client.tuples)
+	}
+}
+
+func TestScopeReplacementsMustBeDeclared(t *testing.T) {
+	catalog := newScopeCatalogModule("replacement-catalog", nil)
+	if _, err := catalog.InvokeMethod("RegisterScopes", map[string]any{
+		"scopes": []any{map[string]any{"name": "shop:order:read", "context": "shop", "resource": "order", "actions": []any{"read"}, "replaced_by": "shop:orders:read"}},
+	}); err == nil {
+		t.Fatal("expected error for a replaced_by naming an undeclared scope")
+	}
+	if _, err := catalog.InvokeMethod("RegisterDeclarations", map[string]any{
+		"declarations": map[string]any{
+			"resources": []any{map[string]any{"name": "order", "context": "shop", "replaced_by": "orders"}},
+		},
+	}); err == nil {
+		t.Fatal("expected error for a replaced_by naming an undeclared resource")
+	}
+	if scopes := catalog.listScopes(nil); len(scopes) != 0 {
+		t.Fatalf("scopes = %v, want the rejected scope left out", scopes)
+	}
+
+	config := newScopeCatalogModule("replacement-config-catalog", map[string]any{
+		"scopes": []any{map[string]any{"name": "shop:order:read", "context": "shop", "replaced_by": "shop:orders:read"}},
+	})
+	if err := config.Init(); err == nil {
+		t.Fatal("expected Init error for a configured replaced_by naming an undeclared scope")
+	}
+
+	store := newScopeRoleStore("test")
+	if err := store.DeclareScopes(context.Background(), []*contracts.ScopeDeclaration{
+		{Name: "shop:order:read", Context: "shop", Resource: "order", Actions: []string{"read"}, ReplacedBy: "shop:orders:read"},
+	}); err == nil {
+		t.Fatal("expected DeclareScopes error for a replaced_by naming an undeclared scope")
+	}
+}
+
+func TestResourceLifecycleAppliesToItsScopes(t *testing.T) {
+	catalog := newScopeCatalogModule("resource-lifecycle-catalog", map[string]any{
+		"declarations": map[string]any{
+			"scopes": []any{
+				map[string]any{"name": "shop:order:read", "context": "shop", "resource": "order", "actions": []any{"read"}},
+				map[string]any{"name": "shop:orders:read", "context": "shop", "resource": "orders", "actions": []any{"read"}},
+				map[string]any{"name": "shop:cart:read", "context": "shop", "resource": "cart", "actions": []any{"read"}},
+			},
+			"resources": []any{
+				map[string]any{"name": "order", "context": "shop", "replaced_by": "orders"},
+				map[string]any{"name": "orders", "context": "shop"},
+				map[string]any{"name": "cart", "context": "shop", "deprecated": true},
+			},
+		},
+	})
+	if err := catalog.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	resolved, err := catalog.InvokeMethod("ResolveSubjectScopes", map[string]any{"direct_scopes": []any{"shop:order:read", "shop:cart:read"}})
+	if err != nil {
+		t.Fatalf("ResolveSubjectScopes: %v", err)
+	}
+	scopes := resolved["scopes"].([]string)
+	if len(scopes) != 2 || scopes[0] != "shop:cart:read" || scopes[1] != "shop:orders:read" || len(resolved["deprecations"].([]string)) != 2 {
+		t.Fatalf("resolved = %v, want the replacement scope and both scopes deprecated", resolved)
+	}
+
+	// A scope registered later on a replaced resource is renamed too.
+	out, err := catalog.InvokeMethod("RegisterScopes", map[string]any{
+		"scopes": []any{
+			map[string]any{"name": "shop:orders:write", "context": "shop", "resource": "orders", "actions": []any{"write"}},
+			map[string]any{"name": "shop:order:write", "context": "shop", "resource": "order", "actions": []any{"write"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("RegisterScopes: %v", err)
+	}
+	registered := out["scopes"].([]map[string]any)
+	if len(registered) != 2 || registered[1]["replaced_by"] != "shop:orders:write" {
+		t.Fatalf("registered scopes = %v, want shop:order:write replaced by shop:orders:write", registered)
+	}
+}
@@ -0,0 +1,89 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// scopeWildcard is the segment that matches any scope segment. As the last
+// segment it matches every scope below the prefix, so "billing.*" grants
+// "billing.invoices.read" and "billing:*" grants "billing:invoice:read".
+const scopeWildcard = "*"
+
+func isScopePattern(name string) bool {
+	return strings.Contains(name, scopeWildcard)
+}
+
+// splitScopeName splits a scope name into its segments and the "." or ":"
+// separators between them.
+func splitScopeName(name string) ([]string, []byte) {
+	var segments []string
+	var separators []byte
+	start := 0
+	for i := 0; i < len(name); i++ {
+		if name[i] == '.' || name[i] == ':' {
+			segments = append(segments, name[start:i])
+			separators = append(separators, name[i])
+			start = i + 1
+		}
+	}
+	return append(segments, name[start:]), separators
+}
+
+// validateScopePattern rejects patterns whose wildcard is not a whole
+// segment, such as "billing.inv*".
+func validateScopePattern(pattern string) error {
+	segments, _ := splitScopeName(pattern)
+	for _, segment := range segments {
+		if segment == "" {
+			return fmt.Errorf("scope pattern %q has an empty segment", pattern)
+		}
+		if segment != scopeWildcard && strings.Contains(segment, scopeWildcard) {
+			return fmt.Errorf("scope pattern %q: %q must be a whole segment", pattern, scopeWildcard)
+		}
+	}
+	return nil
+}
+
+// scopePatternMatches reports whether pattern grants scope. Separators must
+// line up; a "*" segment matches exactly one segment unless it is last, in
+// which case it matches one or more.
+func scopePatternMatches(pattern, scope string) bool {
+	if pattern == scope {
+		return true
+	}
+	if !isScopePattern(pattern) {
+		return false
+	}
+	want, wantSeps := splitScopeName(pattern)
+	have, haveSeps := splitScopeName(scope)
+	for i, segment := range want {
+		if i >= len(have) {
+			return false
+		}
+		if i > 0 && wantSeps[i-1] != haveSeps[i-1] {
+			return false
+		}
+		if segment == scopeWildcard && i == len(want)-1 {
+			return true
+		}
+		if segment != scopeWildcard && segment != have[i] {
+			return false
+		}
+	}
+	return len(have) == len(want)
+}
+
+// matchScopeGrant returns the first grant that grants scope, preferring an
+// exact grant over a pattern.
+func matchScopeGrant(grants []string, scope string) (string, bool) {
+	if containsString(grants, scope) {
+		return scope, true
+	}
+	for _, grant := range grants {
+		if scopePatternMatches(grant, scope) {
+			return grant, true
+		}
+	}
+	return "", false
+}
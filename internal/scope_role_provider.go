@@ -2,6 +2,7 @@ package internal
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sort"
 	"strings"
@@ -10,6 +11,8 @@ import (
 	"github.com/GoCodeAlone/workflow-plugin-authz/internal/contracts"
 )
 
+var errUnsupportedRBAC = errors.New("rbac provider is not supported by this module configuration")
+
 type ScopeRoleProvider interface {
 	Name() string
 	DeclareScopes(context.Context, []*contracts.ScopeDeclaration) error
@@ -20,6 +23,17 @@ type ScopeRoleProvider interface {
 	CheckScope(context.Context, ScopeCheck) (ScopeCheckResult, error)
 }
 
+// reasonNoScopeGrant is the CheckScope reason when the scope is declared but
+// the subject holds no grant for it.
+const reasonNoScopeGrant = "no matching role or direct scope grant"
+
+// RoleLister is implemented by ScopeRoleProviders that can list their role
+// grants. Delegated administration uses it for providers that keep no local
+// copy of them. An empty contextName lists every context.
+type RoleLister interface {
+	ListRoles(ctx context.Context, contextName string) ([]RoleScopeGrant, error)
+}
+
 type RoleScopeGrant struct {
 	Role    string
 	Context string
@@ -56,6 +70,9 @@ type ScopeCheckResult struct {
 	MatchedRole   string
 	MatchedScopes []string
 	Reason        string
+	// Deprecations warns when the check or the grant that satisfied it used a
+	// deprecated scope name.
+	Deprecations []string
 }
 
 type scopeRoleStore struct {
@@ -77,6 +94,9 @@ func newScopeRoleStore(provider string) *scopeRoleStore {
 func (s *scopeRoleStore) DeclareScopes(_ context.Context, scopes []*contracts.ScopeDeclaration) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	if err := validateScopeReplacements(s.scopes, scopes); err != nil {
+		return err
+	}
 	for _, incoming := range scopes {
 		scope := cloneScopeDeclaration(incoming)
 		if scope == nil || strings.TrimSpace(scope.GetName()) == "" {
@@ -91,23 +111,29 @@ func (s *scopeRoleStore) DeclareScopes(_ context.Context, scopes []*contracts.Sc
 	return nil
 }
 
+// undeclareScopes forgets the declarations of scopes.
+func (s *scopeRoleStore) undeclareScopes(scopes []*contracts.ScopeDeclaration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, scope := range scopes {
+		delete(s.scopes, scope.GetName())
+	}
+}
+
 func (s *scopeRoleStore) UpsertRole(_ context.Context, grant RoleScopeGrant) error {
-	grant.Role = strings.TrimSpace(grant.Role)
-	grant.Context = strings.TrimSpace(grant.Context)
+	grant = normalizeRoleScopeGrant(grant)
 	if grant.Role == "" {
 		return fmt.Errorf("role is required")
 	}
 	if grant.Context == "" {
 		return fmt.Errorf("context is required")
 	}
-	scopes := uniqueStrings(grant.Scopes)
 	s.mu.RLock()
-	err := s.validateScopesLocked(grant.Context, scopes)
+	err := s.validateScopesLocked(grant.Context, grant.Scopes)
 	s.mu.RUnlock()
 	if err != nil {
 		return err
 	}
-	grant.Scopes = scopes
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -116,16 +142,13 @@ func (s *scopeRoleStore) UpsertRole(_ context.Context, grant RoleScopeGrant) err
 }
 
 func (s *scopeRoleStore) AssignRole(_ context.Context, assignment SubjectRoleAssignment) error {
-	assignment.Subject = strings.TrimSpace(assignment.Subject)
-	assignment.Role = strings.TrimSpace(assignment.Role)
-	assignment.Context = strings.TrimSpace(assignment.Context)
+	assignment = normalizeSubjectRoleAssignment(assignment)
 	if assignment.Subject == "" {
 		return fmt.Errorf("subject is required")
 	}
 	if assignment.Context == "" {
 		return fmt.Errorf("context is required")
 	}
-	assignment.DirectScopes = uniqueStrings(assignment.DirectScopes)
 
 	s.mu.RLock()
 	if err := s.validateScopesLocked(assignment.Context, assignment.DirectScopes); err != nil {
@@ -168,19 +191,12 @@ func (s *scopeRoleStore) ListAssignments(_ context.Context, filter AssignmentFil
 		}
 		out = append(out, cloneSubjectRoleAssignment(assignment))
 	}
-	sort.Slice(out, func(i, j int) bool {
-		if out[i].Subject != out[j].Subject {
-			return out[i].Subject < out[j].Subject
-		}
-		if out[i].Context != out[j].Context {
-			return out[i].Context < out[j].Context
-		}
-		return out[i].Role < out[j].Role
-	})
+	sortAssignments(out)
 	return out, nil
 }
 
 func (s *scopeRoleStore) RemoveAssignment(_ context.Context, target SubjectRoleAssignment) error {
+	target = normalizeSubjectRoleAssignment(target)
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	kept := s.assigns[:0]
@@ -217,7 +233,8 @@ func (s *scopeRoleStore) CheckScope(_ context.Context, check ScopeCheck) (ScopeC
 
 	s.mu.RLock()
 	defer s.mu.RUnlock()
-	declared, ok := s.scopes[result.Scope]
+	target := resolveScopeAlias(s.scopes, result.Scope)
+	declared, ok := s.scopes[target]
 	if !ok {
 		result.Reason = "scope is not declared"
 		return result, nil
@@ -226,33 +243,257 @@ func (s *scopeRoleStore) CheckScope(_ context.Context, check ScopeCheck) (ScopeC
 		result.Reason = "scope context mismatch"
 		return result, nil
 	}
+	result.Deprecations = scopeDeprecations(s.scopes, result.Scope, target)
+	grantMatched := func(role, matched string) (ScopeCheckResult, error) {
+		result.Allowed = true
+		result.MatchedRole = role
+		result.MatchedScopes = []string{matched}
+		if matched != target && matched != result.Scope && !isScopePattern(matched) {
+			result.Deprecations = append(result.Deprecations, fmt.Sprintf("grant %q is deprecated; migrate to %q", matched, target))
+		}
+		return result, nil
+	}
 	for _, assignment := range s.assigns {
 		if assignment.Subject != result.Subject || assignment.Context != result.Context {
 			continue
 		}
-		if containsString(assignment.DirectScopes, result.Scope) {
-			result.Allowed = true
-			result.MatchedRole = assignment.Role
-			result.MatchedScopes = []string{result.Scope}
-			return result, nil
+		if matched, ok := s.matchGrantLocked(assignment.DirectScopes, target); ok {
+			return grantMatched(assignment.Role, matched)
 		}
 		grant, ok := s.roles[roleKey(assignment.Context, assignment.Role)]
 		if !ok {
 			continue
 		}
-		if containsString(grant.Scopes, result.Scope) {
-			result.Allowed = true
-			result.MatchedRole = grant.Role
-			result.MatchedScopes = []string{result.Scope}
-			return result, nil
+		if matched, ok := s.matchGrantLocked(grant.Scopes, target); ok {
+			return grantMatched(grant.Role, matched)
 		}
 	}
-	result.Reason = "no matching role or direct scope grant"
+	result.Reason = reasonNoScopeGrant
 	return result, nil
 }
 
+// removeRole deletes a role grant. Assignments referencing the role are kept
+// and simply stop granting scopes, matching how CheckScope treats unknown roles.
+func (s *scopeRoleStore) removeRole(contextName, role string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.roles, roleKey(strings.TrimSpace(contextName), strings.TrimSpace(role)))
+}
+
+// declaredScopes returns copies of all declared scopes sorted by name.
+func (s *scopeRoleStore) declaredScopes() []*contracts.ScopeDeclaration {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]*contracts.ScopeDeclaration, 0, len(s.scopes))
+	for _, scope := range s.scopes {
+		out = append(out, cloneScopeDeclaration(scope))
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].GetName() < out[j].GetName() })
+	return out
+}
+
+// roleScopes returns a copy of the scopes granted by role in contextName, or
+// nil when the role is not defined.
+func (s *scopeRoleStore) roleScopes(contextName, role string) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	grant, ok := s.roles[roleKey(strings.TrimSpace(contextName), strings.TrimSpace(role))]
+	if !ok {
+		return nil
+	}
+	return append([]string(nil), grant.Scopes...)
+}
+
+// scopeContext returns the context of the declared scope name, or of the
+// declared scopes a wildcard pattern matches.
+func (s *scopeRoleStore) scopeContext(name string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if scope, ok := s.scopes[name]; ok {
+		return scope.GetContext(), true
+	}
+	if isScopePattern(name) {
+		for _, declared := range sortedKeys(s.scopes) {
+			if scopePatternMatches(name, declared) {
+				return s.scopes[declared].GetContext(), true
+			}
+		}
+	}
+	return "", false
+}
+
+// holdsScopePattern reports whether subject holds a grant in contextName that
+// covers every scope pattern matches, now and once more scopes are declared:
+// "billing:*" covers "billing:invoice:*", but "billing:invoice:read" does not
+// cover "billing:*".
+func (s *scopeRoleStore) holdsScopePattern(subject, contextName, pattern string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	covers := func(grants []string) bool {
+		for _, grant := range grants {
+			if scopePatternMatches(grant, pattern) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, assignment := range s.assigns {
+		if assignment.Subject != subject || assignment.Context != contextName {
+			continue
+		}
+		if covers(assignment.DirectScopes) {
+			return true
+		}
+		if grant, ok := s.roles[roleKey(assignment.Context, assignment.Role)]; ok && covers(grant.Scopes) {
+			return true
+		}
+	}
+	return false
+}
+
+// scopeReferences counts the role grants and direct assignments that grant
+// scopeName, including through a wildcard pattern or a replaced name.
+func (s *scopeRoleStore) scopeReferences(scopeName string) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	count := 0
+	for _, grant := range s.roles {
+		if _, ok := s.matchGrantLocked(grant.Scopes, scopeName); ok {
+			count++
+		}
+	}
+	for _, assignment := range s.assigns {
+		if _, ok := s.matchGrantLocked(assignment.DirectScopes, scopeName); ok {
+			count++
+		}
+	}
+	return count
+}
+
+// expandScopes replaces each wildcard pattern in scopes with the declared
+// scopes of contextName it matches, for providers that can only store
+// concrete permissions.
+func (s *scopeRoleStore) expandScopes(contextName string, scopes []string) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]string, 0, len(scopes))
+	for _, name := range scopes {
+		if !isScopePattern(name) {
+			out = append(out, name)
+			continue
+		}
+		for _, declared := range sortedKeys(s.scopes) {
+			if s.scopes[declared].GetContext() == contextName && scopePatternMatches(name, declared) {
+				out = append(out, declared)
+			}
+		}
+	}
+	return uniqueStrings(out)
+}
+
+// patternGrants returns the role grants and direct-scope assignments that
+// contain a wildcard pattern; their expansion changes when scopes are
+// declared.
+func (s *scopeRoleStore) patternGrants() ([]RoleScopeGrant, []SubjectRoleAssignment) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var grants []RoleScopeGrant
+	for _, key := range sortedKeys(s.roles) {
+		if hasScopePattern(s.roles[key].Scopes) {
+			grants = append(grants, cloneRoleScopeGrant(s.roles[key]))
+		}
+	}
+	var assignments []SubjectRoleAssignment
+	for _, assignment := range s.assigns {
+		if hasScopePattern(assignment.DirectScopes) {
+			assignments = append(assignments, cloneSubjectRoleAssignment(assignment))
+		}
+	}
+	return grants, assignments
+}
+
+func hasScopePattern(scopes []string) bool {
+	for _, scope := range scopes {
+		if isScopePattern(scope) {
+			return true
+		}
+	}
+	return false
+}
+
+// contexts returns the sorted contexts that have declared scopes or roles.
+func (s *scopeRoleStore) contexts() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	seen := map[string]bool{}
+	for _, scope := range s.scopes {
+		if scope.GetContext() != "" {
+			seen[scope.GetContext()] = true
+		}
+	}
+	for _, grant := range s.roles {
+		seen[grant.Context] = true
+	}
+	return sortedKeys(seen)
+}
+
+// clone returns an independent copy of the store for what-if evaluation.
+func (s *scopeRoleStore) clone() *scopeRoleStore {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := newScopeRoleStore(s.provider)
+	for name, scope := range s.scopes {
+		out.scopes[name] = cloneScopeDeclaration(scope)
+	}
+	for key, grant := range s.roles {
+		out.roles[key] = cloneRoleScopeGrant(grant)
+	}
+	for _, assignment := range s.assigns {
+		out.assigns = append(out.assigns, cloneSubjectRoleAssignment(assignment))
+	}
+	return out
+}
+
+// grants returns copies of every role grant, sorted by key, and every
+// assignment.
+func (s *scopeRoleStore) grants() ([]RoleScopeGrant, []SubjectRoleAssignment) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	roles := make([]RoleScopeGrant, 0, len(s.roles))
+	for _, key := range sortedKeys(s.roles) {
+		roles = append(roles, cloneRoleScopeGrant(s.roles[key]))
+	}
+	assigns := make([]SubjectRoleAssignment, 0, len(s.assigns))
+	for _, assignment := range s.assigns {
+		assigns = append(assigns, cloneSubjectRoleAssignment(assignment))
+	}
+	return roles, assigns
+}
+
+// replaceGrants replaces every role grant and assignment. Unlike UpsertRole
+// and AssignRole it does not validate scopes against the declarations, since
+// the grants come from a backend that already holds them.
+func (s *scopeRoleStore) replaceGrants(roles []RoleScopeGrant, assigns []SubjectRoleAssignment) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.roles = make(map[string]RoleScopeGrant, len(roles))
+	for _, grant := range roles {
+		s.roles[roleKey(grant.Context, grant.Role)] = cloneRoleScopeGrant(grant)
+	}
+	s.assigns = make([]SubjectRoleAssignment, 0, len(assigns))
+	for _, assignment := range assigns {
+		s.assigns = append(s.assigns, cloneSubjectRoleAssignment(assignment))
+	}
+}
+
 func (s *scopeRoleStore) validateScopesLocked(contextName string, scopes []string) error {
 	for _, name := range scopes {
+		if isScopePattern(name) {
+			if err := s.validatePatternLocked(contextName, name); err != nil {
+				return err
+			}
+			continue
+		}
 		scope, ok := s.scopes[name]
 		if !ok {
 			return fmt.Errorf("scope %q is not declared", name)
@@ -264,6 +505,20 @@ func (s *scopeRoleStore) validateScopesLocked(contextName string, scopes []strin
 	return nil
 }
 
+// validatePatternLocked requires a wildcard pattern to be well formed and to
+// match at least one scope declared in contextName.
+func (s *scopeRoleStore) validatePatternLocked(contextName, pattern string) error {
+	if err := validateScopePattern(pattern); err != nil {
+		return err
+	}
+	for name, scope := range s.scopes {
+		if scope.GetContext() == contextName && scopePatternMatches(pattern, name) {
+			return nil
+		}
+	}
+	return fmt.Errorf("scope pattern %q matches no scope declared in context %q", pattern, contextName)
+}
+
 func roleKey(contextName, role string) string {
 	return contextName + "\x00" + role
 }
@@ -299,6 +554,24 @@ func scopeDeclarationFromName(name string) *contracts.ScopeDeclaration {
 	return scope
 }
 
+// normalizeRoleScopeGrant trims the grant's names and deduplicates its scopes,
+// so providers write the same values the store keeps.
+func normalizeRoleScopeGrant(grant RoleScopeGrant) RoleScopeGrant {
+	grant.Role = strings.TrimSpace(grant.Role)
+	grant.Context = strings.TrimSpace(grant.Context)
+	grant.Scopes = uniqueStrings(grant.Scopes)
+	return grant
+}
+
+// normalizeSubjectRoleAssignment is normalizeRoleScopeGrant for assignments.
+func normalizeSubjectRoleAssignment(assignment SubjectRoleAssignment) SubjectRoleAssignment {
+	assignment.Subject = strings.TrimSpace(assignment.Subject)
+	assignment.Role = strings.TrimSpace(assignment.Role)
+	assignment.Context = strings.TrimSpace(assignment.Context)
+	assignment.DirectScopes = uniqueStrings(assignment.DirectScopes)
+	return assignment
+}
+
 func normalizeCheckScope(check ScopeCheck) string {
 	if strings.TrimSpace(check.Scope) != "" {
 		return strings.TrimSpace(check.Scope)
@@ -326,6 +599,18 @@ func cloneSubjectRoleAssignment(assignment SubjectRoleAssignment) SubjectRoleAss
 	}
 }
 
+func sortAssignments(assigns []SubjectRoleAssignment) {
+	sort.Slice(assigns, func(i, j int) bool {
+		if assigns[i].Subject != assigns[j].Subject {
+			return assigns[i].Subject < assigns[j].Subject
+		}
+		if assigns[i].Context != assigns[j].Context {
+			return assigns[i].Context < assigns[j].Context
+		}
+		return assigns[i].Role < assigns[j].Role
+	})
+}
+
 func sameAssignmentIdentity(a, b SubjectRoleAssignment) bool {
 	return a.Subject == b.Subject && a.Role == b.Role && a.Context == b.Context
 }
@@ -429,5 +714,6 @@ func scopeCheckResultToMap(result ScopeCheckResult) map[string]any {
 		"matched_role":   result.MatchedRole,
 		"matched_scopes": stringsToAny(result.MatchedScopes),
 		"reason":         result.Reason,
+		"deprecations":   stringsToAny(result.Deprecations),
 	})
 }
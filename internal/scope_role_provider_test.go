@@ -75,6 +75,123 @@ func TestCasbinScopeRoleProviderConformance(t *testing.T) {
 	}
 }
 
+func TestScopePatternMatches(t *testing.T) {
+	cases := []struct {
+		pattern, scope string
+		want           bool
+	}{
+		{"billing.*", "billing.invoices.read", true},
+		{"billing.invoices.*", "billing.invoices.read", true},
+		{"billing.invoices.*", "billing.payments.refund", false},
+		{"billing.*.read", "billing.invoices.read", true},
+		{"billing.*.read", "billing.invoices.write", false},
+		{"billing:*", "billing:invoice:read", true},
+		{"billing:*", "billing.invoices.read", false},
+		{"billing.*", "billing", false},
+		{"*", "cms:page:read", true},
+		{"billing.invoices.read", "billing.invoices.read", true},
+	}
+	for _, tc := range cases {
+		if got := scopePatternMatches(tc.pattern, tc.scope); got != tc.want {
+			t.Errorf("scopePatternMatches(%q, %q) = %v, want %v", tc.pattern, tc.scope, got, tc.want)
+		}
+	}
+}
+
+func TestScopeRoleStoreGrantsHierarchicalScopes(t *testing.T) {
+	ctx := context.Background()
+	provider := newTestCasbinScopeProvider(t)
+	declare := func(name, resource, action string) {
+		t.Helper()
+		if err := provider.DeclareScopes(ctx, []*contracts.ScopeDeclaration{{Name: name, Context: "billing", Resource: resource, Actions: []string{action}}}); err != nil {
+			t.Fatalf("DeclareScopes: %v", err)
+		}
+	}
+	declare("billing.invoices.read", "invoices", "read")
+	declare("billing.payments.refund", "payments", "refund")
+
+	for _, grant := range []RoleScopeGrant{
+		{Role: "billing-admin", Context: "billing", Scopes: []string{"billing.*"}},
+		{Role: "invoice-clerk", Context: "billing", Scopes: []string{"billing.invoices.*"}},
+	} {
+		if err := provider.UpsertRole(ctx, grant); err != nil {
+			t.Fatalf("UpsertRole %s: %v", grant.Role, err)
+		}
+	}
+	for _, bad := range []string{"billing.inv*", "cms.*"} {
+		if err := provider.UpsertRole(ctx, RoleScopeGrant{Role: "bad", Context: "billing", Scopes: []string{bad}}); err == nil {
+			t.Fatalf("expected pattern %q to be rejected", bad)
+		}
+	}
+	for subject, role := range map[string]string{"alice": "billing-admin", "bob": "invoice-clerk"} {
+		if err := provider.AssignRole(ctx, SubjectRoleAssignment{Subject: subject, Role: role, Context: "billing"}); err != nil {
+			t.Fatalf("AssignRole: %v", err)
+		}
+	}
+
+	assertScopeAllowed(t, provider, ScopeCheck{Subject: "alice", Context: "billing", Scope: "billing.payments.refund"}, true)
+	assertScopeAllowed(t, provider, ScopeCheck{Subject: "bob", Context: "billing", Scope: "billing.invoices.read"}, true)
+	assertScopeAllowed(t, provider, ScopeCheck{Subject: "bob", Context: "billing", Scope: "billing.payments.refund"}, false)
+
+	// A scope declared after the grant is covered without editing the role.
+	declare("billing.invoices.void", "invoices", "void")
+	result, err := provider.CheckScope(ctx, ScopeCheck{Subject: "bob", Context: "billing", Scope: "billing.invoices.void"})
+	if err != nil || !result.Allowed || result.MatchedScopes[0] != "billing.invoices.*" {
+		t.Fatalf("CheckScope = %+v, %v; want allowed through billing.invoices.*", result, err)
+	}
+}
+
+func TestScopeRoleStoreResolvesReplacedScopes(t *testing.T) {
+	ctx := context.Background()
+	provider := newTestCasbinScopeProvider(t)
+	if err := provider.DeclareScopes(ctx, []*contracts.ScopeDeclaration{
+		{Name: "docs:doc:read", Context: "docs", Resource: "doc", Actions: []string{"read"}, Deprecated: true, ReplacedBy: "docs:document:read"},
+		{Name: "docs:document:read", Context: "docs", Resource: "document", Actions: []string{"read"}, Version: "2"},
+	}); err != nil {
+		t.Fatalf("DeclareScopes: %v", err)
+	}
+	if err := provider.UpsertRole(ctx, RoleScopeGrant{Role: "reader", Context: "docs", Scopes: []string{"docs:doc:read"}}); err != nil {
+		t.Fatalf("UpsertRole: %v", err)
+	}
+	if err := provider.AssignRole(ctx, SubjectRoleAssignment{Subject: "alice", Role: "reader", Context: "docs"}); err != nil {
+		t.Fatalf("AssignRole: %v", err)
+	}
+
+	// A grant on the old name satisfies checks for the new one.
+	result, err := provider.CheckScope(ctx, ScopeCheck{Subject: "alice", Context: "docs", Scope: "docs:document:read"})
+	if err != nil || !result.Allowed || result.MatchedScopes[0] != "docs:doc:read" {
+		t.Fatalf("CheckScope = %+v, %v; want allowed through the old grant", result, err)
+	}
+	if len(result.Deprecations) != 1 {
+		t.Fatalf("deprecations = %v, want a warning about the old grant", result.Deprecations)
+	}
+
+	// Checking the old name resolves to the new declaration and warns.
+	result, err = provider.CheckScope(ctx, ScopeCheck{Subject: "alice", Context: "docs", Scope: "docs:doc:read"})
+	if err != nil || !result.Allowed {
+		t.Fatalf("CheckScope(old name) = %+v, %v; want allowed", result, err)
+	}
+	if len(result.Deprecations) != 1 || result.Deprecations[0] != `scope "docs:doc:read" is deprecated; use "docs:document:read"` {
+		t.Fatalf("deprecations = %v, want a warning about the old name", result.Deprecations)
+	}
+}
+
+func TestResolveScopeAliasStopsOnCycles(t *testing.T) {
+	declared := map[string]*contracts.ScopeDeclaration{
+		"a": {Name: "a", ReplacedBy: "b"},
+		"b": {Name: "b", ReplacedBy: "a"},
+		"c": {Name: "c", ReplacedBy: "d"},
+		"d": {Name: "d", ReplacedBy: "e"},
+		"e": {Name: "e"},
+	}
+	if got := resolveScopeAlias(declared, "a"); got != "a" {
+		t.Fatalf("resolveScopeAlias(a) = %q, want a for a cycle", got)
+	}
+	if got := resolveScopeAlias(declared, "c"); got != "e" {
+		t.Fatalf("resolveScopeAlias(c) = %q, want e", got)
+	}
+}
+
 func newTestCasbinScopeProvider(t *testing.T) ScopeRoleProvider {
 	t.Helper()
 	m := buildModule(t, nil, nil)
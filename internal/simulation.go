@@ -0,0 +1,659 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/GoCodeAlone/workflow-plugin-authz/internal/contracts"
+)
+
+// ErrInvalidSimulation marks simulation failures caused by the request itself,
+// such as a proposed change the provider rejects or a request without probes.
+var ErrInvalidSimulation = errors.New("invalid simulation request")
+
+// Simulation change kinds accepted by Simulate.
+const (
+	SimulationChangePolicy          = "policy"
+	SimulationChangeGrouping        = "grouping"
+	SimulationChangeRole            = "role"
+	SimulationChangeAssignment      = "assignment"
+	SimulationChangeRelation        = "relation"
+	SimulationChangeAttributePolicy = "abac_policy"
+)
+
+// Simulation probe kinds. Decision probes go through DecideAuthorization;
+// enforce probes call the raw Casbin enforcer with (subject, resource, action).
+const (
+	SimulationProbeDecision = "decision"
+	SimulationProbeEnforce  = "enforce"
+)
+
+// Flip directions reported in SimulationResult.Change.
+const (
+	SimulationAllowToDeny = "allow_to_deny"
+	SimulationDenyToAllow = "deny_to_allow"
+)
+
+// SimulationChange is one proposed mutation applied to the isolated copy of
+// provider state. Op is "add" or "remove"; Kind selects which field is used.
+type SimulationChange struct {
+	Op         string
+	Kind       string
+	Rule       []string
+	Grant      RoleScopeGrant
+	Assignment SubjectRoleAssignment
+	Tuple      RelationTuple
+	Policy     AttributePolicy
+}
+
+// SimulationProbe is one authorization request evaluated before and after the
+// proposed changes.
+type SimulationProbe struct {
+	Kind                  string
+	Mode                  AuthzCapability
+	Subject               string
+	Context               string
+	Resource              string
+	Action                string
+	Scope                 string
+	Relation              string
+	SubjectAttributes     map[string]string
+	ResourceAttributes    map[string]string
+	EnvironmentAttributes map[string]string
+}
+
+type SimulationInput struct {
+	Changes []SimulationChange
+	Probes  []SimulationProbe
+	// AllSubjectScopes adds a probe for every known subject against every
+	// scope declared in the subject's context, plus every Casbin policy
+	// subject against every (object, action) pair for in-process modules.
+	AllSubjectScopes bool
+}
+
+type SimulationResult struct {
+	Probe        SimulationProbe
+	Before       bool
+	After        bool
+	BeforeReason string
+	AfterReason  string
+	Change       string
+	Error        string
+}
+
+// SimulationOutput reports only the probes whose decision flipped, plus any
+// probes that could not be evaluated.
+type SimulationOutput struct {
+	Evaluated int
+	Granted   int
+	Revoked   int
+	Flips     []SimulationResult
+	Errors    []SimulationResult
+}
+
+// simulationSnapshotter is implemented by providers whose decision state can
+// be copied in-process. Snapshots never touch remote systems or publish
+// change events.
+type simulationSnapshotter interface {
+	simulationSnapshot() (any, error)
+}
+
+type scopeRoleStoreProvider interface {
+	scopeRoleStore() *scopeRoleStore
+}
+
+type casbinPolicyEditor interface {
+	AddPolicy([]string) (bool, error)
+	RemovePolicy([]string) (bool, error)
+	AddGroupingPolicy([]string) (bool, error)
+	RemoveGroupingPolicy([]string) (bool, error)
+	Enforce(sub, obj, act string, extra ...string) (bool, error)
+}
+
+// Simulate applies input.Changes to an isolated copy of the provider state and
+// reports which probe decisions flip. The live provider is never modified.
+func Simulate(ctx context.Context, provider any, input SimulationInput) (SimulationOutput, error) {
+	snapshotter, ok := provider.(simulationSnapshotter)
+	if !ok {
+		return SimulationOutput{}, fmt.Errorf("provider does not support simulation")
+	}
+	before, err := snapshotter.simulationSnapshot()
+	if err != nil {
+		return SimulationOutput{}, err
+	}
+	after, err := snapshotter.simulationSnapshot()
+	if err != nil {
+		return SimulationOutput{}, err
+	}
+	for i, change := range input.Changes {
+		if err := applySimulationChange(ctx, after, change); err != nil {
+			return SimulationOutput{}, fmt.Errorf("%w: changes[%d]: %w", ErrInvalidSimulation, i, err)
+		}
+	}
+	probes := append([]SimulationProbe(nil), input.Probes...)
+	if input.AllSubjectScopes {
+		probes = append(probes, subjectScopeProbes(ctx, before, after)...)
+	}
+	probes = uniqueSimulationProbes(probes)
+	if len(probes) == 0 {
+		return SimulationOutput{}, fmt.Errorf("%w: at least one probe or all_subject_scopes is required", ErrInvalidSimulation)
+	}
+
+	var out SimulationOutput
+	for _, probe := range probes {
+		out.Evaluated++
+		result := SimulationResult{Probe: probe}
+		var beforeErr, afterErr error
+		result.Before, result.BeforeReason, beforeErr = evaluateSimulationProbe(ctx, before, probe)
+		result.After, result.AfterReason, afterErr = evaluateSimulationProbe(ctx, after, probe)
+		switch {
+		case beforeErr != nil:
+			result.Error = beforeErr.Error()
+			out.Errors = append(out.Errors, result)
+		case afterErr != nil:
+			result.Error = afterErr.Error()
+			out.Errors = append(out.Errors, result)
+		case result.Before && !result.After:
+			result.Change = SimulationAllowToDeny
+			out.Revoked++
+			out.Flips = append(out.Flips, result)
+		case !result.Before && result.After:
+			result.Change = SimulationDenyToAllow
+			out.Granted++
+			out.Flips = append(out.Flips, result)
+		}
+	}
+	return out, nil
+}
+
+func applySimulationChange(ctx context.Context, provider any, change SimulationChange) error {
+	var remove bool
+	switch strings.ToLower(strings.TrimSpace(change.Op)) {
+	case "", "add", "upsert":
+	case "remove", "delete":
+		remove = true
+	default:
+		return fmt.Errorf("unsupported op %q", change.Op)
+	}
+	switch change.Kind {
+	case SimulationChangePolicy, SimulationChangeGrouping:
+		editor, ok := provider.(casbinPolicyEditor)
+		if !ok {
+			return fmt.Errorf("%s changes require an authz.casbin module", change.Kind)
+		}
+		if len(change.Rule) == 0 {
+			return fmt.Errorf("%s change requires rule", change.Kind)
+		}
+		var err error
+		switch {
+		case change.Kind == SimulationChangePolicy && remove:
+			_, err = editor.RemovePolicy(change.Rule)
+		case change.Kind == SimulationChangePolicy:
+			_, err = editor.AddPolicy(change.Rule)
+		case remove:
+			_, err = editor.RemoveGroupingPolicy(change.Rule)
+		default:
+			_, err = editor.AddGroupingPolicy(change.Rule)
+		}
+		return err
+	case SimulationChangeRole:
+		if remove {
+			stores, ok := provider.(scopeRoleStoreProvider)
+			if !ok {
+				return fmt.Errorf("provider does not support role removal")
+			}
+			stores.scopeRoleStore().removeRole(change.Grant.Context, change.Grant.Role)
+			return nil
+		}
+		roles, ok := provider.(ScopeRoleProvider)
+		if !ok {
+			return fmt.Errorf("provider does not implement scope roles")
+		}
+		return roles.UpsertRole(ctx, change.Grant)
+	case SimulationChangeAssignment:
+		roles, ok := provider.(ScopeRoleProvider)
+		if !ok {
+			return fmt.Errorf("provider does not implement scope roles")
+		}
+		if remove {
+			return roles.RemoveAssignment(ctx, change.Assignment)
+		}
+		return roles.AssignRole(ctx, change.Assignment)
+	case SimulationChangeRelation:
+		relations, ok := provider.(RelationshipProvider)
+		if !ok {
+			return errUnsupportedReBAC
+		}
+		if remove {
+			return relations.RemoveRelationTuple(ctx, change.Tuple)
+		}
+		return relations.UpsertRelationTuple(ctx, change.Tuple)
+	case SimulationChangeAttributePolicy:
+		attributes, ok := provider.(AttributePolicyProvider)
+		if !ok {
+			return errUnsupportedABAC
+		}
+		if remove {
+			return attributes.RemoveAttributePolicy(ctx, AttributePolicyFilter{ID: change.Policy.ID, Context: change.Policy.Context})
+		}
+		return attributes.UpsertAttributePolicy(ctx, change.Policy)
+	default:
+		return fmt.Errorf("unsupported change kind %q", change.Kind)
+	}
+}
+
+func evaluateSimulationProbe(ctx context.Context, provider any, probe SimulationProbe) (bool, string, error) {
+	if probe.Kind == SimulationProbeEnforce {
+		editor, ok := provider.(casbinPolicyEditor)
+		if !ok {
+			return false, "", fmt.Errorf("enforce probes require an authz.casbin module")
+		}
+		allowed, err := editor.Enforce(probe.Subject, probe.Resource, probe.Action)
+		return allowed, "", err
+	}
+	decision, err := DecideAuthorization(ctx, provider, AuthorizationDecisionInput{
+		Mode:                  probe.Mode,
+		Subject:               probe.Subject,
+		Context:               probe.Context,
+		Resource:              probe.Resource,
+		Action:                probe.Action,
+		Scope:                 probe.Scope,
+		Relation:              probe.Relation,
+		SubjectAttributes:     probe.SubjectAttributes,
+		ResourceAttributes:    probe.ResourceAttributes,
+		EnvironmentAttributes: probe.EnvironmentAttributes,
+	})
+	if err != nil {
+		return false, "", err
+	}
+	return decision.Allowed, decision.Reason, nil
+}
+
+// subjectScopeProbes expands "all known subjects × declared scopes" across the
+// before and after snapshots so subjects introduced by the change are covered.
+func subjectScopeProbes(ctx context.Context, snapshots ...any) []SimulationProbe {
+	subjects := map[string]map[string]struct{}{}
+	scopes := map[string]map[string]struct{}{}
+	enforceSubjects := map[string]struct{}{}
+	enforceRoles := map[string]struct{}{}
+	enforcePairs := map[string][]string{}
+	for _, snapshot := range snapshots {
+		if stores, ok := snapshot.(scopeRoleStoreProvider); ok {
+			store := stores.scopeRoleStore()
+			assignments, _ := store.ListAssignments(ctx, AssignmentFilter{})
+			for _, assignment := range assignments {
+				addToSet(subjects, assignment.Context, assignment.Subject)
+			}
+			for _, scope := range store.declaredScopes() {
+				addToSet(scopes, scope.GetContext(), scope.GetName())
+			}
+		}
+		if mod, ok := snapshot.(*CasbinModule); ok {
+			policySubjects, roles, pairs := mod.simulationRequestSpace()
+			for _, subject := range policySubjects {
+				enforceSubjects[subject] = struct{}{}
+			}
+			for _, role := range roles {
+				enforceRoles[role] = struct{}{}
+			}
+			for _, pair := range pairs {
+				enforcePairs[pair[0]+"\x00"+pair[1]] = pair
+			}
+		}
+	}
+
+	var probes []SimulationProbe
+	for _, contextName := range sortedKeys(subjects) {
+		for _, subject := range sortedKeys(subjects[contextName]) {
+			for _, scope := range sortedKeys(scopes[contextName]) {
+				probes = append(probes, SimulationProbe{Kind: SimulationProbeDecision, Mode: CapabilityRBAC, Subject: subject, Context: contextName, Scope: scope})
+			}
+		}
+	}
+	// Role names are policy subjects too, but probing them directly would
+	// report flips for groups rather than the users who hold them.
+	for _, subject := range sortedKeys(enforceSubjects) {
+		if _, isRole := enforceRoles[subject]; isRole {
+			continue
+		}
+		for _, key := range sortedKeys(enforcePairs) {
+			pair := enforcePairs[key]
+			probes = append(probes, SimulationProbe{Kind: SimulationProbeEnforce, Subject: subject, Resource: pair[0], Action: pair[1]})
+		}
+	}
+	return probes
+}
+
+func addToSet(sets map[string]map[string]struct{}, key, value string) {
+	if sets[key] == nil {
+		sets[key] = map[string]struct{}{}
+	}
+	sets[key][value] = struct{}{}
+}
+
+func sortedKeys[V any](values map[string]V) []string {
+	out := make([]string, 0, len(values))
+	for key := range values {
+		out = append(out, key)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func uniqueSimulationProbes(probes []SimulationProbe) []SimulationProbe {
+	seen := map[string]struct{}{}
+	out := make([]SimulationProbe, 0, len(probes))
+	for _, probe := range probes {
+		if probe.Kind == "" {
+			probe.Kind = SimulationProbeDecision
+		}
+		key := fmt.Sprintf("%s\x00%s\x00%s\x00%s\x00%s\x00%s\x00%s\x00%s\x00%v\x00%v\x00%v",
+			probe.Kind, probe.Mode, probe.Subject, probe.Context, probe.Resource, probe.Action, probe.Scope, probe.Relation,
+			probe.SubjectAttributes, probe.ResourceAttributes, probe.EnvironmentAttributes)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		out = append(out, probe)
+	}
+	return out
+}
+
+// simulationSnapshot copies the enforcer policies, scope roles, ABAC policies,
+// and relation tuples into a memory-backed module that never publishes events.
+func (m *CasbinModule) simulationSnapshot() (any, error) {
+	m.mu.RLock()
+	e := m.enforcer
+	m.mu.RUnlock()
+	if e == nil {
+		return nil, fmt.Errorf("authz.casbin %q: enforcer not initialized", m.name)
+	}
+	policies, err := e.GetPolicy()
+	if err != nil {
+		return nil, fmt.Errorf("authz.casbin %q: read policies: %w", m.name, err)
+	}
+	grouping, err := e.GetGroupingPolicy()
+	if err != nil {
+		return nil, fmt.Errorf("authz.casbin %q: read role assignments: %w", m.name, err)
+	}
+	clone := &CasbinModule{
+		name:          m.name,
+		config:        casbinConfig{Model: m.config.Model, Policies: policies, RoleAssignments: grouping},
+		scopeRoles:    m.scopeRoleStore().clone(),
+		abac:          m.abac.clone(),
+		relations:     m.relations.clone(),
+		simulated:     true,
+		relationModel: m.relationModel,
+	}
+	if err := clone.Init(); err != nil {
+		return nil, err
+	}
+	for _, tuple := range clone.relations.List(RelationTupleFilter{}) {
+		if _, err := clone.enforcer.AddNamedGroupingPolicy("g2", tuple.Subject, tuple.Relation, tuple.Object); err != nil {
+			return nil, err
+		}
+	}
+	return clone, nil
+}
+
+// simulationRequestSpace returns the subjects and roles named by role
+// assignments, the subjects of three-field policies, and their (object,
+// action) pairs.
+func (m *CasbinModule) simulationRequestSpace() (subjects, roles []string, pairs [][]string) {
+	m.mu.RLock()
+	e := m.enforcer
+	m.mu.RUnlock()
+	if e == nil {
+		return nil, nil, nil
+	}
+	policies, _ := e.GetPolicy()
+	grouping, _ := e.GetGroupingPolicy()
+	for _, rule := range grouping {
+		if len(rule) >= 2 {
+			subjects = append(subjects, rule[0])
+			roles = append(roles, rule[1])
+		}
+	}
+	for _, rule := range policies {
+		if len(rule) == 3 {
+			subjects = append(subjects, rule[0])
+			pairs = append(pairs, []string{rule[1], rule[2]})
+		}
+	}
+	return subjects, roles, pairs
+}
+
+func (m *KetoModule) simulationSnapshot() (any, error) {
+	if m.provider == nil {
+		return nil, fmt.Errorf("authz.keto %q: provider not initialized", m.name)
+	}
+	return &localSimulationProvider{
+		name:        m.name,
+		descriptors: m.CapabilityDescriptors(),
+		scopes:      m.provider.store.clone(),
+		relations:   m.provider.relations.clone(),
+	}, nil
+}
+
+func (m *PermitModule) simulationSnapshot() (any, error) {
+	if m.scopeProvider == nil {
+		return nil, fmt.Errorf("permit.provider %q: provider not initialized", m.name)
+	}
+	return &localSimulationProvider{
+		name:        m.name,
+		descriptors: m.CapabilityDescriptors(),
+		scopes:      m.scopeProvider.store.clone(),
+	}, nil
+}
+
+// localSimulationProvider evaluates decisions against a copy of the local
+// cache kept by remote-backed providers (Keto, Permit). The remote service is
+// not consulted, so results reflect what the cache would grant.
+type localSimulationProvider struct {
+	name        string
+	descriptors []CapabilityDescriptor
+	scopes      *scopeRoleStore
+	relations   *relationTupleStore
+}
+
+func (p *localSimulationProvider) Name() string { return p.name }
+
+func (p *localSimulationProvider) scopeRoleStore() *scopeRoleStore { return p.scopes }
+
+func (p *localSimulationProvider) Capabilities() []AuthzCapability {
+	return capabilitiesFromDescriptors(p.descriptors)
+}
+
+func (p *localSimulationProvider) SupportsCapability(cap AuthzCapability) bool {
+	for _, c := range p.Capabilities() {
+		if c == cap {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *localSimulationProvider) CapabilityDescriptors() []CapabilityDescriptor {
+	return p.descriptors
+}
+
+func (p *localSimulationProvider) RequireCapabilities(requirements []CapabilityRequirement) error {
+	return requireCapabilities(p.scopes.provider, p.descriptors, requirements)
+}
+
+func (p *localSimulationProvider) DeclareScopes(ctx context.Context, scopes []*contracts.ScopeDeclaration) error {
+	return p.scopes.DeclareScopes(ctx, scopes)
+}
+
+func (p *localSimulationProvider) UpsertRole(ctx context.Context, grant RoleScopeGrant) error {
+	return p.scopes.UpsertRole(ctx, grant)
+}
+
+func (p *localSimulationProvider) AssignRole(ctx context.Context, assignment SubjectRoleAssignment) error {
+	return p.scopes.AssignRole(ctx, assignment)
+}
+
+func (p *localSimulationProvider) ListAssignments(ctx context.Context, filter AssignmentFilter) ([]SubjectRoleAssignment, error) {
+	return p.scopes.ListAssignments(ctx, filter)
+}
+
+func (p *localSimulationProvider) RemoveAssignment(ctx context.Context, assignment SubjectRoleAssignment) error {
+	return p.scopes.RemoveAssignment(ctx, assignment)
+}
+
+func (p *localSimulationProvider) CheckScope(ctx context.Context, check ScopeCheck) (ScopeCheckResult, error) {
+	return p.scopes.CheckScope(ctx, check)
+}
+
+func (p *localSimulationProvider) UpsertRelationTuple(_ context.Context, tuple RelationTuple) error {
+	if p.relations == nil {
+		return errUnsupportedReBAC
+	}
+	return p.relations.Upsert(tuple)
+}
+
+func (p *localSimulationProvider) RemoveRelationTuple(_ context.Context, tuple RelationTuple) error {
+	if p.relations == nil {
+		return errUnsupportedReBAC
+	}
+	return p.relations.Remove(tuple)
+}
+
+func (p *localSimulationProvider) ListRelationTuples(_ context.Context, filter RelationTupleFilter) ([]RelationTuple, error) {
+	if p.relations == nil {
+		return nil, errUnsupportedReBAC
+	}
+	return p.relations.List(filter), nil
+}
+
+func (p *localSimulationProvider) CheckRelation(_ context.Context, check RelationCheck) (RelationCheckResult, error) {
+	if p.relations == nil {
+		return RelationCheckResult{}, errUnsupportedReBAC
+	}
+	result := RelationCheckResult{Subject: check.Subject, Relation: check.Relation, Object: check.Object, Context: check.Context}
+	result.Allowed = p.relations.Contains(check)
+	if !result.Allowed {
+		result.Reason = "relation tuple not found"
+	}
+	return result, nil
+}
+
+func simulateInvoke(ctx context.Context, provider any, input map[string]any) (map[string]any, error) {
+	out, err := Simulate(ctx, provider, simulationInputFromMap(input))
+	if err != nil {
+		return nil, err
+	}
+	return simulationOutputToMap(out), nil
+}
+
+// simulationInputFromMap decodes a simulation request in the JSON shape of the
+// adminapi simulate route. The step, the Simulate service method and the
+// admin route all go through it, so the same request means the same thing.
+func simulationInputFromMap(values map[string]any) SimulationInput {
+	input := SimulationInput{AllSubjectScopes: boolValue(values["all_subject_scopes"])}
+	for _, item := range mapListValue(values["changes"]) {
+		input.Changes = append(input.Changes, simulationChangeFromMap(item))
+	}
+	for _, item := range mapListValue(values["probes"]) {
+		input.Probes = append(input.Probes, simulationProbeFromMap(item))
+	}
+	return input
+}
+
+// simulationChangeFromMap accepts "role" and "abac_policy" as the adminapi
+// names for the grant and policy fields. Kind is trimmed and lower-cased, as
+// the adminapi does before it checks the change's context.
+func simulationChangeFromMap(values map[string]any) SimulationChange {
+	return SimulationChange{
+		Op:         stringValue(values["op"]),
+		Kind:       strings.ToLower(strings.TrimSpace(stringValue(values["kind"]))),
+		Rule:       stringSliceValue(values["rule"]),
+		Grant:      roleScopeGrantFromMap(mapValue(firstNonNil(values["grant"], values["role"]))),
+		Assignment: subjectRoleAssignmentFromMap(mapValue(values["assignment"])),
+		Tuple:      relationTupleFromMap(mapValue(values["tuple"])),
+		Policy:     attributePolicyFromMap(mapValue(firstNonNil(values["policy"], values["abac_policy"]))),
+	}
+}
+
+func simulationProbeFromMap(values map[string]any) SimulationProbe {
+	probe := SimulationProbe{
+		Kind:     stringValue(values["kind"]),
+		Mode:     AuthzCapability(stringValue(values["mode"])),
+		Subject:  stringValue(values["subject"]),
+		Context:  stringValue(values["context"]),
+		Resource: stringValue(values["resource"]),
+		Action:   stringValue(values["action"]),
+		Scope:    stringValue(values["scope"]),
+		Relation: stringValue(values["relation"]),
+	}
+	if values["subject_attributes"] != nil {
+		probe.SubjectAttributes = stringMapFromAny(values["subject_attributes"])
+	}
+	if values["resource_attributes"] != nil {
+		probe.ResourceAttributes = stringMapFromAny(values["resource_attributes"])
+	}
+	if values["environment_attributes"] != nil {
+		probe.EnvironmentAttributes = stringMapFromAny(values["environment_attributes"])
+	}
+	return probe
+}
+
+func simulationProbeToMap(probe SimulationProbe) map[string]any {
+	out := compactMap(map[string]any{
+		"kind":     probe.Kind,
+		"mode":     string(probe.Mode),
+		"subject":  probe.Subject,
+		"context":  probe.Context,
+		"resource": probe.Resource,
+		"action":   probe.Action,
+		"scope":    probe.Scope,
+		"relation": probe.Relation,
+	})
+	for key, attrs := range map[string]map[string]string{
+		"subject_attributes":     probe.SubjectAttributes,
+		"resource_attributes":    probe.ResourceAttributes,
+		"environment_attributes": probe.EnvironmentAttributes,
+	} {
+		if len(attrs) > 0 {
+			out[key] = stringMapToAnyMap(attrs)
+		}
+	}
+	return out
+}
+
+func simulationResultsToAny(results []SimulationResult) []any {
+	out := make([]any, 0, len(results))
+	for _, result := range results {
+		out = append(out, compactMap(map[string]any{
+			"probe":         simulationProbeToMap(result.Probe),
+			"before":        result.Before,
+			"after":         result.After,
+			"before_reason": result.BeforeReason,
+			"after_reason":  result.AfterReason,
+			"change":        result.Change,
+			"error":         result.Error,
+		}))
+	}
+	return out
+}
+
+func simulationOutputToMap(out SimulationOutput) map[string]any {
+	return map[string]any{
+		"evaluated": out.Evaluated,
+		"granted":   out.Granted,
+		"revoked":   out.Revoked,
+		"flips":     simulationResultsToAny(out.Flips),
+		"errors":    simulationResultsToAny(out.Errors),
+	}
+}
+
+func stringMapToAnyMap(values map[string]string) map[string]any {
+	out := make(map[string]any, len(values))
+	for key, value := range values {
+		out[key] = value
+	}
+	return out
+}
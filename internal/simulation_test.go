@@ -0,0 +1,214 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/GoCodeAlone/workflow-plugin-authz/internal/contracts"
+)
+
+func TestSimulateCasbinReportsFlipsWithoutMutatingModule(t *testing.T) {
+	ctx := context.Background()
+	mod := buildModule(t,
+		[][]string{{"editor", "/docs", "write"}, {"viewer", "/docs", "read"}},
+		[][]string{{"alice", "editor"}, {"bob", "viewer"}},
+	)
+	_, events, cancel, _ := SubscribeChangeEvents(0)
+	defer cancel()
+
+	out, err := Simulate(ctx, mod, SimulationInput{
+		Changes: []SimulationChange{
+			{Op: "remove", Kind: SimulationChangeGrouping, Rule: []string{"alice", "editor"}},
+			{Op: "add", Kind: SimulationChangePolicy, Rule: []string{"viewer", "/docs", "write"}},
+		},
+		AllSubjectScopes: true,
+	})
+	if err != nil {
+		t.Fatalf("Simulate: %v", err)
+	}
+	if out.Granted != 1 || out.Revoked != 1 || len(out.Flips) != 2 {
+		t.Fatalf("simulation output = %#v", out)
+	}
+	flips := map[string]string{}
+	for _, flip := range out.Flips {
+		flips[flip.Probe.Subject+" "+flip.Probe.Resource+" "+flip.Probe.Action] = flip.Change
+	}
+	if flips["alice /docs write"] != SimulationAllowToDeny || flips["bob /docs write"] != SimulationDenyToAllow {
+		t.Fatalf("flips = %#v", flips)
+	}
+
+	if ok, _ := mod.Enforce("alice", "/docs", "write"); !ok {
+		t.Fatal("simulation must not change the live enforcer")
+	}
+	if ok, _ := mod.Enforce("bob", "/docs", "write"); ok {
+		t.Fatal("simulation must not change the live enforcer")
+	}
+	select {
+	case event := <-events:
+		t.Fatalf("simulation published change event %#v", event)
+	default:
+	}
+}
+
+func TestSimulateCasbinScopeRoles(t *testing.T) {
+	ctx := context.Background()
+	mod := rbacTestModule(t, nil, nil)
+	read := &contracts.ScopeDeclaration{Name: "cms:page:read"}
+	publish := &contracts.ScopeDeclaration{Name: "cms:page:publish"}
+	if err := mod.DeclareScopes(ctx, []*contracts.ScopeDeclaration{read, publish}); err != nil {
+		t.Fatalf("DeclareScopes: %v", err)
+	}
+	if err := mod.UpsertRole(ctx, RoleScopeGrant{Role: "editor", Context: "cms", Scopes: []string{"cms:page:read"}}); err != nil {
+		t.Fatalf("UpsertRole: %v", err)
+	}
+	if err := mod.AssignRole(ctx, SubjectRoleAssignment{Subject: "alice", Role: "editor", Context: "cms"}); err != nil {
+		t.Fatalf("AssignRole: %v", err)
+	}
+
+	out, err := Simulate(ctx, mod, SimulationInput{
+		Changes: []SimulationChange{
+			{Op: "add", Kind: SimulationChangeRole, Grant: RoleScopeGrant{Role: "editor", Context: "cms", Scopes: []string{"cms:page:publish"}}},
+		},
+		AllSubjectScopes: true,
+	})
+	if err != nil {
+		t.Fatalf("Simulate: %v", err)
+	}
+	if out.Evaluated != 2 || out.Granted != 1 || out.Revoked != 1 {
+		t.Fatalf("simulation output = %#v", out)
+	}
+	for _, flip := range out.Flips {
+		switch flip.Probe.Scope {
+		case "cms:page:publish":
+			if flip.Change != SimulationDenyToAllow {
+				t.Fatalf("publish flip = %#v", flip)
+			}
+		case "cms:page:read":
+			if flip.Change != SimulationAllowToDeny || flip.AfterReason == "" {
+				t.Fatalf("read flip = %#v", flip)
+			}
+		}
+	}
+	check, err := mod.CheckScope(ctx, ScopeCheck{Subject: "alice", Context: "cms", Scope: "cms:page:publish"})
+	if err != nil || check.Allowed {
+		t.Fatalf("live role changed by simulation: %#v, %v", check, err)
+	}
+}
+
+func TestSimulateKetoUsesLocalCacheOnly(t *testing.T) {
+	ctx := context.Background()
+	client := &fakeKetoClient{}
+	mod := &KetoModule{name: "keto", provider: newKetoScopeProvider("keto", client)}
+	if err := mod.UpsertRelationTuple(ctx, RelationTuple{Subject: "alice", Relation: "viewer", Object: "doc-1", Context: "cms"}); err != nil {
+		t.Fatalf("UpsertRelationTuple: %v", err)
+	}
+	writes := len(client.tuples)
+
+	out, err := Simulate(ctx, mod, SimulationInput{
+		Changes: []SimulationChange{
+			{Op: "remove", Kind: SimulationChangeRelation, Tuple: RelationTuple{Subject: "alice", Relation: "viewer", Object: "doc-1", Context: "cms"}},
+			{Op: "add", Kind: SimulationChangeRelation, Tuple: RelationTuple{Subject: "bob", Relation: "viewer", Object: "doc-1", Context: "cms"}},
+		},
+		Probes: []SimulationProbe{
+			{Subject: "alice", Relation: "viewer", Resource: "doc-1", Context: "cms"},
+			{Subject: "bob", Relation: "viewer", Resource: "doc-1", Context: "cms"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Simulate: %v", err)
+	}
+	if out.Granted != 1 || out.Revoked != 1 {
+		t.Fatalf("simulation output = %#v", out)
+	}
+	if len(client.tuples) != writes {
+		t.Fatalf("simulation wrote %d tuples to keto", len(client.tuples)-writes)
+	}
+	if tuples, _ := mod.ListRelationTuples(ctx, RelationTupleFilter{Subject: "alice"}); len(tuples) != 1 {
+		t.Fatalf("live keto cache changed by simulation: %#v", tuples)
+	}
+}
+
+func TestSimulatePermitRejectsRelationChanges(t *testing.T) {
+	mod := &PermitModule{name: "permit", scopeProvider: newPermitScopeProvider("permit", &fakePermitScopeClient{})}
+	_, err := Simulate(context.Background(), mod, SimulationInput{
+		Changes: []SimulationChange{{Kind: SimulationChangeRelation, Tuple: RelationTuple{Subject: "alice", Relation: "viewer", Object: "doc-1", Context: "cms"}}},
+		Probes:  []SimulationProbe{{Subject: "alice", Context: "cms", Scope: "cms:page:read"}},
+	})
+	if !errors.Is(err, errUnsupportedReBAC) {
+		t.Fatalf("Simulate error = %v, want errUnsupportedReBAC", err)
+	}
+}
+
+func TestAuthzSimulateStep(t *testing.T) {
+	mod := buildModule(t, [][]string{{"editor", "/docs", "write"}}, [][]string{{"alice", "editor"}})
+	step, err := newAuthzSimulateStep("what-if", map[string]any{
+		"changes": []any{
+			map[string]any{"op": "remove", "kind": "policy", "rule": []any{"editor", "/docs", "write"}},
+		},
+		"probes": []any{
+			map[string]any{"kind": "enforce", "subject": "alice", "resource": "/docs", "action": "write"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("newAuthzSimulateStep: %v", err)
+	}
+	step.registry = &testRegistry{mod: mod}
+	result, err := step.Execute(context.Background(), nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if result.Output["revoked"] != 1 {
+		t.Fatalf("step output = %#v", result.Output)
+	}
+	flips, _ := result.Output["flips"].([]any)
+	if len(flips) != 1 || mapValue(flips[0])["change"] != SimulationAllowToDeny {
+		t.Fatalf("flips = %#v", flips)
+	}
+
+	empty, err := newAuthzSimulateStep("empty", map[string]any{})
+	if err != nil {
+		t.Fatalf("newAuthzSimulateStep: %v", err)
+	}
+	empty.registry = &testRegistry{mod: mod}
+	if _, err := empty.Execute(context.Background(), nil, nil, nil, nil, nil); err == nil || !strings.Contains(err.Error(), "probe") {
+		t.Fatalf("expected missing probes error, got %v", err)
+	}
+}
+
+func TestAuthzSimulateStepReadsRequestFromCurrent(t *testing.T) {
+	ctx := context.Background()
+	mod := rbacTestModule(t, nil, nil)
+	if err := mod.DeclareScopes(ctx, []*contracts.ScopeDeclaration{{Name: "cms:page:read"}, {Name: "cms:page:publish"}}); err != nil {
+		t.Fatalf("DeclareScopes: %v", err)
+	}
+	if err := mod.UpsertRole(ctx, RoleScopeGrant{Role: "editor", Context: "cms", Scopes: []string{"cms:page:read"}}); err != nil {
+		t.Fatalf("UpsertRole: %v", err)
+	}
+	if err := mod.AssignRole(ctx, SubjectRoleAssignment{Subject: "alice", Role: "editor", Context: "cms"}); err != nil {
+		t.Fatalf("AssignRole: %v", err)
+	}
+	step, err := newAuthzSimulateStep("what-if", map[string]any{})
+	if err != nil {
+		t.Fatalf("newAuthzSimulateStep: %v", err)
+	}
+	step.registry = &testRegistry{mod: mod}
+
+	// The current state carries a request in the adminapi simulate shape,
+	// where role grants use "role" rather than "grant". Kinds match in any case.
+	result, err := step.Execute(ctx, nil, nil, map[string]any{
+		"changes": []map[string]any{
+			{"kind": " Role ", "role": map[string]any{"role": "editor", "context": "cms", "scopes": []any{"cms:page:publish"}}},
+		},
+		"probes": []map[string]any{
+			{"subject": "alice", "context": "cms", "scope": "cms:page:publish"},
+		},
+	}, nil, nil)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if result.Output["evaluated"] != 1 || result.Output["granted"] != 1 {
+		t.Fatalf("step output = %#v", result.Output)
+	}
+}
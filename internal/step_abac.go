@@ -94,6 +94,7 @@ type authzABACAddPolicyStep struct {
 	ruleTmpls  []*template.Template
 	ruleStatic []string
 	registry   moduleRegistry
+	audit      stepAudit
 }
 
 func newAuthzABACAddPolicyStep(name string, config map[string]any) (*authzABACAddPolicyStep, error) {
@@ -101,6 +102,7 @@ func newAuthzABACAddPolicyStep(name string, config map[string]any) (*authzABACAd
 		name:       name,
 		moduleName: "authz",
 		registry:   globalRegistry,
+		audit:      newStepAudit("step.authz_abac_add_policy", config),
 	}
 	if v, ok := config["module"].(string); ok && v != "" {
 		s.moduleName = v
@@ -114,7 +116,7 @@ func newAuthzABACAddPolicyStep(name string, config map[string]any) (*authzABACAd
 }
 
 func (s *authzABACAddPolicyStep) Execute(
-	_ context.Context,
+	ctx context.Context,
 	triggerData map[string]any,
 	stepOutputs map[string]map[string]any,
 	current map[string]any,
@@ -127,6 +129,11 @@ func (s *authzABACAddPolicyStep) Execute(
 		return nil, fmt.Errorf("step.authz_abac_add_policy %q: resolve: %w", s.name, err)
 	}
 
+	audit, err := s.audit.begin(tmplData)
+	if err != nil {
+		return nil, fmt.Errorf("step.authz_abac_add_policy %q: %w", s.name, err)
+	}
+
 	mod, ok := s.registry.GetEnforcer(s.moduleName)
 	if !ok {
 		return nil, fmt.Errorf("step.authz_abac_add_policy %q: module %q not found", s.name, s.moduleName)
@@ -137,10 +144,13 @@ func (s *authzABACAddPolicyStep) Execute(
 		return nil, fmt.Errorf("step.authz_abac_add_policy %q: %w", s.name, err)
 	}
 
+	before, after := auditBeforeAfter(ruleAuditValue(rule), true, added)
+	audited := audit.record(ctx, AuditEntry{Module: s.moduleName, Resource: "authz.abac.policies", Action: "add", Before: before, After: after})
+
 	return &sdk.StepResult{
-		Output: map[string]any{
+		Output: markUnaudited(map[string]any{
 			"policy_added": added,
 			"rule":         rule,
-		},
+		}, audited),
 	}, nil
 }
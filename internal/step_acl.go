@@ -17,6 +17,7 @@ type authzACLGrantStep struct {
 	ruleTmpls  []*template.Template
 	ruleStatic []string
 	registry   moduleRegistry
+	audit      stepAudit
 }
 
 func newAuthzACLGrantStep(name string, config map[string]any) (*authzACLGrantStep, error) {
@@ -24,6 +25,7 @@ func newAuthzACLGrantStep(name string, config map[string]any) (*authzACLGrantSte
 		name:       name,
 		moduleName: "authz",
 		registry:   globalRegistry,
+		audit:      newStepAudit("step.authz_acl_grant", config),
 	}
 	if v, ok := config["module"].(string); ok && v != "" {
 		s.moduleName = v
@@ -39,7 +41,7 @@ func newAuthzACLGrantStep(name string, config map[string]any) (*authzACLGrantSte
 }
 
 func (s *authzACLGrantStep) Execute(
-	_ context.Context,
+	ctx context.Context,
 	triggerData map[string]any,
 	stepOutputs map[string]map[string]any,
 	current map[string]any,
@@ -52,6 +54,11 @@ func (s *authzACLGrantStep) Execute(
 		return nil, fmt.Errorf("step.authz_acl_grant %q: resolve: %w", s.name, err)
 	}
 
+	audit, err := s.audit.begin(tmplData)
+	if err != nil {
+		return nil, fmt.Errorf("step.authz_acl_grant %q: %w", s.name, err)
+	}
+
 	mod, ok := s.registry.GetEnforcer(s.moduleName)
 	if !ok {
 		return nil, fmt.Errorf("step.authz_acl_grant %q: module %q not found", s.name, s.moduleName)
@@ -62,13 +69,16 @@ func (s *authzACLGrantStep) Execute(
 		return nil, fmt.Errorf("step.authz_acl_grant %q: %w", s.name, err)
 	}
 
+	before, after := auditBeforeAfter(ruleAuditValue(rule), true, added)
+	audited := audit.record(ctx, AuditEntry{Module: s.moduleName, Resource: "authz.policies", Action: "add", Before: before, After: after})
+
 	return &sdk.StepResult{
-		Output: map[string]any{
+		Output: markUnaudited(map[string]any{
 			"granted": added,
 			"subject": rule[0],
 			"object":  rule[1],
 			"action":  rule[2],
-		},
+		}, audited),
 	}, nil
 }
 
@@ -80,6 +90,7 @@ type authzACLRevokeStep struct {
 	ruleTmpls  []*template.Template
 	ruleStatic []string
 	registry   moduleRegistry
+	audit      stepAudit
 }
 
 func newAuthzACLRevokeStep(name string, config map[string]any) (*authzACLRevokeStep, error) {
@@ -87,6 +98,7 @@ func newAuthzACLRevokeStep(name string, config map[string]any) (*authzACLRevokeS
 		name:       name,
 		moduleName: "authz",
 		registry:   globalRegistry,
+		audit:      newStepAudit("step.authz_acl_revoke", config),
 	}
 	if v, ok := config["module"].(string); ok && v != "" {
 		s.moduleName = v
@@ -102,7 +114,7 @@ func newAuthzACLRevokeStep(name string, config map[string]any) (*authzACLRevokeS
 }
 
 func (s *authzACLRevokeStep) Execute(
-	_ context.Context,
+	ctx context.Context,
 	triggerData map[string]any,
 	stepOutputs map[string]map[string]any,
 	current map[string]any,
@@ -115,6 +127,11 @@ func (s *authzACLRevokeStep) Execute(
 		return nil, fmt.Errorf("step.authz_acl_revoke %q: resolve: %w", s.name, err)
 	}
 
+	audit, err := s.audit.begin(tmplData)
+	if err != nil {
+		return nil, fmt.Errorf("step.authz_acl_revoke %q: %w", s.name, err)
+	}
+
 	mod, ok := s.registry.GetEnforcer(s.moduleName)
 	if !ok {
 		return nil, fmt.Errorf("step.authz_acl_revoke %q: module %q not found", s.name, s.moduleName)
@@ -125,13 +142,16 @@ func (s *authzACLRevokeStep) Execute(
 		return nil, fmt.Errorf("step.authz_acl_revoke %q: %w", s.name, err)
 	}
 
+	before, after := auditBeforeAfter(ruleAuditValue(rule), false, removed)
+	audited := audit.record(ctx, AuditEntry{Module: s.moduleName, Resource: "authz.policies", Action: "remove", Before: before, After: after})
+
 	return &sdk.StepResult{
-		Output: map[string]any{
+		Output: markUnaudited(map[string]any{
 			"revoked": removed,
 			"subject": rule[0],
 			"object":  rule[1],
 			"action":  rule[2],
-		},
+		}, audited),
 	}, nil
 }
 
@@ -22,6 +22,7 @@ type authzAddPolicyStep struct {
 	ruleTmpls  []*template.Template // one per rule element; nil entry = static string
 	ruleStatic []string             // static value for the corresponding index (when tmpl is nil)
 	registry   moduleRegistry
+	audit      stepAudit
 }
 
 func newAuthzAddPolicyStep(name string, config map[string]any) (*authzAddPolicyStep, error) {
@@ -29,6 +30,7 @@ func newAuthzAddPolicyStep(name string, config map[string]any) (*authzAddPolicyS
 		name:       name,
 		moduleName: "authz",
 		registry:   globalRegistry,
+		audit:      newStepAudit("step.authz_add_policy", config),
 	}
 	if v, ok := config["module"].(string); ok && v != "" {
 		s.moduleName = v
@@ -44,7 +46,7 @@ func newAuthzAddPolicyStep(name string, config map[string]any) (*authzAddPolicyS
 
 // Execute adds the policy rule to the enforcer.
 func (s *authzAddPolicyStep) Execute(
-	_ context.Context,
+	ctx context.Context,
 	triggerData map[string]any,
 	stepOutputs map[string]map[string]any,
 	current map[string]any,
@@ -58,6 +60,11 @@ func (s *authzAddPolicyStep) Execute(
 		return nil, fmt.Errorf("step.authz_add_policy %q: resolve rule: %w", s.name, err)
 	}
 
+	audit, err := s.audit.begin(tmplData)
+	if err != nil {
+		return nil, fmt.Errorf("step.authz_add_policy %q: %w", s.name, err)
+	}
+
 	mod, ok := s.registry.GetEnforcer(s.moduleName)
 	if !ok {
 		return nil, fmt.Errorf("step.authz_add_policy %q: authz module %q not found", s.name, s.moduleName)
@@ -68,11 +75,14 @@ func (s *authzAddPolicyStep) Execute(
 		return nil, fmt.Errorf("step.authz_add_policy %q: add policy: %w", s.name, err)
 	}
 
+	before, after := auditBeforeAfter(ruleAuditValue(rule), true, added)
+	audited := audit.record(ctx, AuditEntry{Module: s.moduleName, Resource: "authz.policies", Action: "add", Before: before, After: after})
+
 	return &sdk.StepResult{
-		Output: map[string]any{
+		Output: markUnaudited(map[string]any{
 			"authz_policy_added": added,
 			"authz_rule":         rule,
-		},
+		}, audited),
 	}, nil
 }
 
@@ -61,6 +61,10 @@ type authzProviderRegistry interface {
 	GetAuthzProvider(name string) (AuthzProvider, bool)
 }
 
+type customProviderRegistry interface {
+	GetCustomProvider(kind, name string) (AuthzProvider, bool)
+}
+
 // globalRegistry is the package-level registry shared between all steps created
 // by CreateModule. It maps module-name → *CasbinModule.
 var globalRegistry = &defaultRegistry{
@@ -79,11 +83,40 @@ func RegisterAuthzProvider(name string, provider AuthzProvider) {
 	globalRegistry.setAuthzProvider(name, provider)
 }
 
+// builtinProviderKinds are the provider names resolved by the plugin's own
+// modules; custom providers cannot claim them.
+var builtinProviderKinds = map[string]bool{"casbin": true, "keto": true, "permit": true, "composite": true, "opa": true, "cedar": true}
+
+// RegisterCustomProvider registers a host-implemented provider as module name
+// so steps configured with `module: <name>` and `provider: <kind>` use it.
+func RegisterCustomProvider(kind, name string, provider AuthzProvider) error {
+	switch {
+	case kind == "" || name == "":
+		return fmt.Errorf("custom provider kind and name are required")
+	case builtinProviderKinds[kind]:
+		return fmt.Errorf("provider kind %q is reserved", kind)
+	case provider == nil:
+		return fmt.Errorf("custom provider %q is nil", name)
+	}
+	return globalRegistry.setCustomProvider(kind, name, provider)
+}
+
 // defaultRegistry is a simple thread-safe module registry backed by a map.
 type defaultRegistry struct {
 	mu        sync.RWMutex
 	modules   map[string]*CasbinModule
 	providers map[string]AuthzProvider
+	catalogs  map[string]*scopeCatalogModule
+	// custom holds host-registered custom providers by module name, apart
+	// from the plugin's own modules so neither can shadow the other.
+	custom map[string]customProvider
+}
+
+// customProvider is a host-registered provider and the kind it was
+// registered under.
+type customProvider struct {
+	kind     string
+	provider AuthzProvider
 }
 
 func (r *defaultRegistry) set(name string, m *CasbinModule) {
@@ -108,11 +141,85 @@ func (r *defaultRegistry) setAuthzProvider(name string, provider AuthzProvider)
 	r.providers[name] = provider
 }
 
+// setCustomProvider registers provider as module name under kind. It fails
+// when name is taken by one of the plugin's modules or by a custom provider of
+// another kind; registering the same kind again replaces the provider.
+func (r *defaultRegistry) setCustomProvider(kind, name string, provider AuthzProvider) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.providers[name]; ok {
+		return fmt.Errorf("module %q is already registered", name)
+	}
+	if _, ok := r.modules[name]; ok {
+		return fmt.Errorf("module %q is already registered", name)
+	}
+	if existing, ok := r.custom[name]; ok && existing.kind != kind {
+		return fmt.Errorf("module %q is already registered as provider %q", name, existing.kind)
+	}
+	if r.custom == nil {
+		r.custom = make(map[string]customProvider)
+	}
+	r.custom[name] = customProvider{kind: kind, provider: provider}
+	return nil
+}
+
+// GetCustomProvider returns the provider registered under name when it was
+// registered with the given kind.
+func (r *defaultRegistry) GetCustomProvider(kind, name string) (AuthzProvider, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	custom, ok := r.custom[name]
+	if !ok || custom.kind != kind {
+		return nil, false
+	}
+	return custom.provider, true
+}
+
+// GetAuthzProvider returns the module registered as name, falling back to a
+// custom provider so composite children and fallbacks can name one.
 func (r *defaultRegistry) GetAuthzProvider(name string) (AuthzProvider, bool) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
-	provider, ok := r.providers[name]
-	return provider, ok
+	if provider, ok := r.providers[name]; ok {
+		return provider, true
+	}
+	custom, ok := r.custom[name]
+	return custom.provider, ok
+}
+
+// authzProviders returns the registered providers, custom ones included,
+// ordered by name.
+func (r *defaultRegistry) authzProviders() []AuthzProvider {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	byName := make(map[string]AuthzProvider, len(r.providers)+len(r.custom))
+	for name, custom := range r.custom {
+		byName[name] = custom.provider
+	}
+	for name, provider := range r.providers {
+		byName[name] = provider
+	}
+	out := make([]AuthzProvider, 0, len(byName))
+	for _, name := range sortedKeys(byName) {
+		out = append(out, byName[name])
+	}
+	return out
+}
+
+func (r *defaultRegistry) setScopeCatalog(name string, catalog *scopeCatalogModule) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.catalogs == nil {
+		r.catalogs = make(map[string]*scopeCatalogModule)
+	}
+	r.catalogs[name] = catalog
+}
+
+func (r *defaultRegistry) GetScopeCatalog(name string) (*scopeCatalogModule, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	catalog, ok := r.catalogs[name]
+	return catalog, ok
 }
 
 // newAuthzCheckStep parses step config and returns an authzCheckStep.
@@ -18,7 +18,10 @@ type authzDecisionStep struct {
 	subjectAttrs     map[string]string
 	resourceAttrs    map[string]string
 	environmentAttrs map[string]string
-	registry         moduleRegistry
+	// onUnavailable decides the check when the provider, after applying its
+	// own on_unavailable policy, still reports it is unavailable.
+	onUnavailable unavailablePolicy
+	registry      moduleRegistry
 }
 
 func newAuthzDecisionStep(name string, config map[string]any) (*authzDecisionStep, error) {
@@ -41,6 +44,11 @@ func newAuthzDecisionStep(name string, config map[string]any) (*authzDecisionSte
 	step.subjectAttrs = stringMapFromAny(config["subject_attributes"])
 	step.resourceAttrs = stringMapFromAny(config["resource_attributes"])
 	step.environmentAttrs = stringMapFromAny(config["environment_attributes"])
+	policy, err := parseUnavailablePolicy(stringValue(config["on_unavailable"]))
+	if err != nil {
+		return nil, fmt.Errorf("step.authz_check %q: %w", name, err)
+	}
+	step.onUnavailable = policy
 	return step, nil
 }
 
@@ -60,7 +68,7 @@ func (s *authzDecisionStep) Execute(
 	if err != nil {
 		return nil, fmt.Errorf("step.authz_check %q: %w", s.name, err)
 	}
-	decision, err := DecideAuthorization(ctx, provider, AuthorizationDecisionInput{
+	input := AuthorizationDecisionInput{
 		Provider:              s.provider,
 		Mode:                  s.mode,
 		Subject:               values[0],
@@ -73,7 +81,11 @@ func (s *authzDecisionStep) Execute(
 		ResourceAttributes:    s.resourceAttrs,
 		EnvironmentAttributes: s.environmentAttrs,
 		Explain:               boolValue(metadata["explain"]),
-	})
+	}
+	decision, err := DecideAuthorization(ctx, provider, input)
+	if err != nil {
+		decision, err = s.onUnavailable.decide(ctx, input, err)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("step.authz_check %q: %w", s.name, err)
 	}
@@ -132,7 +144,7 @@ func resolveDecisionProvider(registry moduleRegistry, moduleName, providerName s
 			return nil, fmt.Errorf("casbin module %q not found", moduleName)
 		}
 		return mod, nil
-	case "permit", "keto":
+	case "permit", "keto", "composite", "opa", "cedar":
 		reg, ok := registry.(authzProviderRegistry)
 		if !ok {
 			return nil, fmt.Errorf("registry cannot look up %s module %q", providerName, moduleName)
@@ -143,6 +155,11 @@ func resolveDecisionProvider(registry moduleRegistry, moduleName, providerName s
 		}
 		return provider, nil
 	default:
+		if reg, ok := registry.(customProviderRegistry); ok {
+			if provider, ok := reg.GetCustomProvider(providerName, moduleName); ok {
+				return provider, nil
+			}
+		}
 		return nil, fmt.Errorf("unknown provider %q", providerName)
 	}
 }
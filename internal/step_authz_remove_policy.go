@@ -21,6 +21,7 @@ type authzRemovePolicyStep struct {
 	ruleTmpls  []*template.Template
 	ruleStatic []string
 	registry   moduleRegistry
+	audit      stepAudit
 }
 
 func newAuthzRemovePolicyStep(name string, config map[string]any) (*authzRemovePolicyStep, error) {
@@ -28,6 +29,7 @@ func newAuthzRemovePolicyStep(name string, config map[string]any) (*authzRemoveP
 		name:       name,
 		moduleName: "authz",
 		registry:   globalRegistry,
+		audit:      newStepAudit("step.authz_remove_policy", config),
 	}
 	if v, ok := config["module"].(string); ok && v != "" {
 		s.moduleName = v
@@ -43,7 +45,7 @@ func newAuthzRemovePolicyStep(name string, config map[string]any) (*authzRemoveP
 
 // Execute removes the policy rule from the enforcer.
 func (s *authzRemovePolicyStep) Execute(
-	_ context.Context,
+	ctx context.Context,
 	triggerData map[string]any,
 	stepOutputs map[string]map[string]any,
 	current map[string]any,
@@ -57,6 +59,11 @@ func (s *authzRemovePolicyStep) Execute(
 		return nil, fmt.Errorf("step.authz_remove_policy %q: resolve rule: %w", s.name, err)
 	}
 
+	audit, err := s.audit.begin(tmplData)
+	if err != nil {
+		return nil, fmt.Errorf("step.authz_remove_policy %q: %w", s.name, err)
+	}
+
 	mod, ok := s.registry.GetEnforcer(s.moduleName)
 	if !ok {
 		return nil, fmt.Errorf("step.authz_remove_policy %q: authz module %q not found", s.name, s.moduleName)
@@ -67,10 +74,13 @@ func (s *authzRemovePolicyStep) Execute(
 		return nil, fmt.Errorf("step.authz_remove_policy %q: remove policy: %w", s.name, err)
 	}
 
+	before, after := auditBeforeAfter(ruleAuditValue(rule), false, removed)
+	audited := audit.record(ctx, AuditEntry{Module: s.moduleName, Resource: "authz.policies", Action: "remove", Before: before, After: after})
+
 	return &sdk.StepResult{
-		Output: map[string]any{
+		Output: markUnaudited(map[string]any{
 			"authz_policy_removed": removed,
 			"authz_rule":           rule,
-		},
+		}, audited),
 	}, nil
 }
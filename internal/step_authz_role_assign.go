@@ -23,6 +23,7 @@ type authzRoleAssignStep struct {
 	action      string // "add" or "remove"
 	assignments []roleAssignment
 	registry    moduleRegistry
+	audit       stepAudit
 }
 
 // roleAssignment holds the compiled template data for a single [user, role] pair.
@@ -37,6 +38,7 @@ func newAuthzRoleAssignStep(name string, config map[string]any) (*authzRoleAssig
 		moduleName: "authz",
 		action:     "add",
 		registry:   globalRegistry,
+		audit:      newStepAudit("step.authz_role_assign", config),
 	}
 	if v, ok := config["module"].(string); ok && v != "" {
 		s.moduleName = v
@@ -72,7 +74,7 @@ func newAuthzRoleAssignStep(name string, config map[string]any) (*authzRoleAssig
 
 // Execute adds or removes role mappings in the enforcer.
 func (s *authzRoleAssignStep) Execute(
-	_ context.Context,
+	ctx context.Context,
 	triggerData map[string]any,
 	stepOutputs map[string]map[string]any,
 	current map[string]any,
@@ -81,35 +83,46 @@ func (s *authzRoleAssignStep) Execute(
 ) (*sdk.StepResult, error) {
 	tmplData := buildTemplateData(triggerData, stepOutputs, current)
 
+	audit, err := s.audit.begin(tmplData)
+	if err != nil {
+		return nil, fmt.Errorf("step.authz_role_assign %q: %w", s.name, err)
+	}
+
 	mod, ok := s.registry.GetEnforcer(s.moduleName)
 	if !ok {
 		return nil, fmt.Errorf("step.authz_role_assign %q: authz module %q not found", s.name, s.moduleName)
 	}
 
 	var processed [][]string
+	audited := true
 	for i, a := range s.assignments {
 		rule, err := resolveRule(a.static, a.tmpls, tmplData)
 		if err != nil {
 			return nil, fmt.Errorf("step.authz_role_assign %q: resolve assignments[%d]: %w", s.name, i, err)
 		}
 
+		var changed bool
 		var opErr error
 		switch s.action {
 		case "add":
-			_, opErr = mod.AddGroupingPolicy(rule)
+			changed, opErr = mod.AddGroupingPolicy(rule)
 		case "remove":
-			_, opErr = mod.RemoveGroupingPolicy(rule)
+			changed, opErr = mod.RemoveGroupingPolicy(rule)
 		}
 		if opErr != nil {
 			return nil, fmt.Errorf("step.authz_role_assign %q: %s assignment[%d]: %w", s.name, s.action, i, opErr)
 		}
+		before, after := auditBeforeAfter(ruleAuditValue(rule), s.action == "add", changed)
+		if !audit.record(ctx, AuditEntry{Module: s.moduleName, Resource: "authz.roles", Action: s.action, Before: before, After: after}) {
+			audited = false
+		}
 		processed = append(processed, rule)
 	}
 
 	return &sdk.StepResult{
-		Output: map[string]any{
+		Output: markUnaudited(map[string]any{
 			"authz_role_action":      s.action,
 			"authz_role_assignments": processed,
-		},
+		}, audited),
 	}, nil
 }
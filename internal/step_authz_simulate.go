@@ -0,0 +1,74 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+
+	sdk "github.com/GoCodeAlone/workflow/plugin/external/sdk"
+)
+
+// authzSimulateStep implements sdk.StepInstance. It evaluates a proposed set
+// of policy, role, tuple, and ABAC changes against an isolated copy of the
+// provider state and reports which decisions flip.
+//
+// Config:
+//
+//	module: "authz"          # provider module name (default: "authz")
+//	provider: "casbin"       # "casbin" (default), "keto", or "permit"
+//	changes:                 # proposed mutations
+//	  - {op: add, kind: policy, rule: ["editor", "/docs", "write"]}
+//	probes:                  # decisions to compare; same fields as step.authz_check
+//	  - {subject: alice, context: cms, scope: "cms:page:publish"}
+//	all_subject_scopes: true # also probe every known subject × declared scope
+//
+// changes, probes and all_subject_scopes in the pipeline's current state, such
+// as a decoded simulate request body, are added to the configured ones.
+type authzSimulateStep struct {
+	name       string
+	moduleName string
+	provider   string
+	input      SimulationInput
+	registry   moduleRegistry
+}
+
+func newAuthzSimulateStep(name string, config map[string]any) (*authzSimulateStep, error) {
+	step := &authzSimulateStep{name: name, moduleName: "authz", provider: "casbin", registry: globalRegistry}
+	if v := stringValue(config["module"]); v != "" {
+		step.moduleName = v
+	}
+	if v := stringValue(config["provider"]); v != "" {
+		step.provider = v
+	}
+	step.input = simulationInputFromMap(config)
+	return step, nil
+}
+
+func (s *authzSimulateStep) Execute(
+	ctx context.Context,
+	_ map[string]any,
+	_ map[string]map[string]any,
+	current map[string]any,
+	_ map[string]any,
+	_ map[string]any,
+) (*sdk.StepResult, error) {
+	provider, err := resolveDecisionProvider(s.registry, s.moduleName, s.provider)
+	if err != nil {
+		return nil, fmt.Errorf("step.authz_simulate %q: %w", s.name, err)
+	}
+	out, err := Simulate(ctx, provider, s.requestInput(current))
+	if err != nil {
+		return nil, fmt.Errorf("step.authz_simulate %q: %w", s.name, err)
+	}
+	return &sdk.StepResult{Output: simulationOutputToMap(out)}, nil
+}
+
+// requestInput appends the changes and probes carried by the current pipeline
+// state to the configured ones.
+func (s *authzSimulateStep) requestInput(current map[string]any) SimulationInput {
+	runtime := simulationInputFromMap(current)
+	return SimulationInput{
+		Changes:          append(append([]SimulationChange(nil), s.input.Changes...), runtime.Changes...),
+		Probes:           append(append([]SimulationProbe(nil), s.input.Probes...), runtime.Probes...),
+		AllSubjectScopes: s.input.AllSubjectScopes || runtime.AllSubjectScopes,
+	}
+}
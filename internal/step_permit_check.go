@@ -2,6 +2,7 @@ package internal
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 
 	sdk "github.com/GoCodeAlone/workflow/plugin/external/sdk"
@@ -10,8 +11,9 @@ import (
 // --- step.permit_check ---
 
 type permitCheckStep struct {
-	name       string
-	moduleName string
+	name         string
+	moduleName   string
+	forbidOnDeny bool
 }
 
 func newPermitCheckStep(name string, config map[string]any) (*permitCheckStep, error) {
@@ -19,13 +21,17 @@ func newPermitCheckStep(name string, config map[string]any) (*permitCheckStep, e
 	if v, ok := config["module"].(string); ok && v != "" {
 		moduleName = v
 	}
-	return &permitCheckStep{name: name, moduleName: moduleName}, nil
+	forbidOnDeny, err := parsePermitOnDeny(config)
+	if err != nil {
+		return nil, fmt.Errorf("step.permit_check %q: %w", name, err)
+	}
+	return &permitCheckStep{name: name, moduleName: moduleName, forbidOnDeny: forbidOnDeny}, nil
 }
 
 func (s *permitCheckStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	userKey := resolvePermitValue("user", current, config)
@@ -34,19 +40,34 @@ func (s *permitCheckStep) Execute(ctx context.Context, _ map[string]any, _ map[s
 	resourceKey := resolvePermitValue("resource_key", current, config)
 	tenant := resolvePermitValue("tenant", current, config)
 
-	body := map[string]any{
-		"user":   map[string]any{"key": userKey},
-		"action": action,
-		"resource": map[string]any{
-			"type":   resourceType,
-			"key":    resourceKey,
-			"tenant": tenant,
-		},
+	var result map[string]any
+	if client.localPDP != nil {
+		if allowed, reason, ok := client.localPDP.check(userKey, action, resourceType, resourceKey, tenant); ok {
+			result = map[string]any{"allow": allowed, "reason": reason, "source": "local"}
+		}
 	}
-
-	result, err := client.doPDP(ctx, http.MethodPost, "/allowed", body)
-	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+	if result == nil {
+		body := map[string]any{
+			"user":   map[string]any{"key": userKey},
+			"action": action,
+			"resource": map[string]any{
+				"type":   resourceType,
+				"key":    resourceKey,
+				"tenant": tenant,
+			},
+		}
+		var err error
+		result, err = client.doPDP(ctx, http.MethodPost, "/allowed", body)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if allowed, _ := result["allow"].(bool); !allowed && s.forbidOnDeny {
+		resource := resourceType
+		if resourceKey != "" {
+			resource += ":" + resourceKey
+		}
+		return forbiddenResult(fmt.Sprintf("forbidden: %s is not permitted to %s %s", userKey, action, resource)), nil
 	}
 	return &sdk.StepResult{Output: result}, nil
 }
@@ -54,8 +75,9 @@ func (s *permitCheckStep) Execute(ctx context.Context, _ map[string]any, _ map[s
 // --- step.permit_check_bulk ---
 
 type permitCheckBulkStep struct {
-	name       string
-	moduleName string
+	name         string
+	moduleName   string
+	forbidOnDeny bool
 }
 
 func newPermitCheckBulkStep(name string, config map[string]any) (*permitCheckBulkStep, error) {
@@ -63,13 +85,17 @@ func newPermitCheckBulkStep(name string, config map[string]any) (*permitCheckBul
 	if v, ok := config["module"].(string); ok && v != "" {
 		moduleName = v
 	}
-	return &permitCheckBulkStep{name: name, moduleName: moduleName}, nil
+	forbidOnDeny, err := parsePermitOnDeny(config)
+	if err != nil {
+		return nil, fmt.Errorf("step.permit_check_bulk %q: %w", name, err)
+	}
+	return &permitCheckBulkStep{name: name, moduleName: moduleName, forbidOnDeny: forbidOnDeny}, nil
 }
 
 func (s *permitCheckBulkStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	checks, _ := config["checks"].([]any)
@@ -79,11 +105,36 @@ func (s *permitCheckBulkStep) Execute(ctx context.Context, _ map[string]any, _ m
 
 	result, err := client.doPDP(ctx, http.MethodPost, "/allowed/bulk", checks)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
+	}
+	if s.forbidOnDeny {
+		decisions, _ := result["allow"].([]any)
+		denied := max(len(checks)-len(decisions), 0)
+		for _, decision := range decisions {
+			if allowed, _ := mapValue(decision)["allow"].(bool); !allowed {
+				denied++
+			}
+		}
+		if denied > 0 {
+			return forbiddenResult(fmt.Sprintf("forbidden: %d of %d checks denied", denied, len(checks))), nil
+		}
 	}
 	return &sdk.StepResult{Output: result}, nil
 }
 
+// parsePermitOnDeny reads on_deny: "forbid" stops the pipeline with a 403 when
+// a check is denied, and "continue", the default, returns the decision.
+func parsePermitOnDeny(config map[string]any) (bool, error) {
+	switch onDeny := stringValue(config["on_deny"]); onDeny {
+	case "", "continue":
+		return false, nil
+	case "forbid":
+		return true, nil
+	default:
+		return false, fmt.Errorf("on_deny must be forbid or continue, got %q", onDeny)
+	}
+}
+
 // --- step.permit_user_permissions ---
 
 type permitUserPermissionsStep struct {
@@ -102,13 +153,13 @@ func newPermitUserPermissionsStep(name string, config map[string]any) (*permitUs
 func (s *permitUserPermissionsStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	userKey := resolvePermitValue("user_key", current, config)
 	result, err := client.doPDP(ctx, http.MethodGet, "/v2/pdp/user_permissions/"+userKey, nil)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: result}, nil
 }
@@ -131,7 +182,7 @@ func newPermitAuthorizedUsersStep(name string, config map[string]any) (*permitAu
 func (s *permitAuthorizedUsersStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	action := resolvePermitValue("action", current, config)
@@ -148,7 +199,7 @@ func (s *permitAuthorizedUsersStep) Execute(ctx context.Context, _ map[string]an
 
 	result, err := client.doPDP(ctx, http.MethodPost, "/authorized_users", body)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: result}, nil
 }
@@ -25,7 +25,7 @@ func newPermitConditionSetCreateStep(name string, config map[string]any) (*permi
 func (s *permitConditionSetCreateStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	body := map[string]any{}
@@ -40,7 +40,7 @@ func (s *permitConditionSetCreateStep) Execute(ctx context.Context, _ map[string
 
 	result, err := client.doAPI(ctx, http.MethodPost, client.permitSchemaPath("condition_sets"), body)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: result}, nil
 }
@@ -63,13 +63,13 @@ func newPermitConditionSetGetStep(name string, config map[string]any) (*permitCo
 func (s *permitConditionSetGetStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	conditionSetID := resolvePermitValue("condition_set_id", current, config)
 	result, err := client.doAPI(ctx, http.MethodGet, client.permitSchemaPath("condition_sets/"+conditionSetID), nil)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: result}, nil
 }
@@ -92,12 +92,12 @@ func newPermitConditionSetListStep(name string, config map[string]any) (*permitC
 func (s *permitConditionSetListStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, _ map[string]any, _ map[string]any, _ map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	result, err := client.doAPIList(ctx, http.MethodGet, client.permitSchemaPath("condition_sets"), nil)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: map[string]any{"condition_sets": result}}, nil
 }
@@ -120,7 +120,7 @@ func newPermitConditionSetUpdateStep(name string, config map[string]any) (*permi
 func (s *permitConditionSetUpdateStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	conditionSetID := resolvePermitValue("condition_set_id", current, config)
@@ -136,7 +136,7 @@ func (s *permitConditionSetUpdateStep) Execute(ctx context.Context, _ map[string
 
 	result, err := client.doAPI(ctx, http.MethodPatch, client.permitSchemaPath("condition_sets/"+conditionSetID), body)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: result}, nil
 }
@@ -159,13 +159,13 @@ func newPermitConditionSetDeleteStep(name string, config map[string]any) (*permi
 func (s *permitConditionSetDeleteStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	conditionSetID := resolvePermitValue("condition_set_id", current, config)
 	_, err := client.doAPI(ctx, http.MethodDelete, client.permitSchemaPath("condition_sets/"+conditionSetID), nil)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: map[string]any{"deleted": true, "condition_set_id": conditionSetID}}, nil
 }
@@ -26,7 +26,7 @@ func newPermitEnvCreateStep(name string, config map[string]any) (*permitEnvCreat
 func (s *permitEnvCreateStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	projectID := resolvePermitValue("project_id", current, config)
@@ -42,7 +42,7 @@ func (s *permitEnvCreateStep) Execute(ctx context.Context, _ map[string]any, _ m
 
 	result, err := client.doAPI(ctx, http.MethodPost, fmt.Sprintf("/v2/projects/%s/envs", projectID), body)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: result}, nil
 }
@@ -65,7 +65,7 @@ func newPermitEnvGetStep(name string, config map[string]any) (*permitEnvGetStep,
 func (s *permitEnvGetStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	projectID := resolvePermitValue("project_id", current, config)
@@ -75,7 +75,7 @@ func (s *permitEnvGetStep) Execute(ctx context.Context, _ map[string]any, _ map[
 	envID := resolvePermitValue("env_id", current, config)
 	result, err := client.doAPI(ctx, http.MethodGet, fmt.Sprintf("/v2/projects/%s/envs/%s", projectID, envID), nil)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: result}, nil
 }
@@ -98,7 +98,7 @@ func newPermitEnvListStep(name string, config map[string]any) (*permitEnvListSte
 func (s *permitEnvListStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	projectID := resolvePermitValue("project_id", current, config)
@@ -107,7 +107,7 @@ func (s *permitEnvListStep) Execute(ctx context.Context, _ map[string]any, _ map
 	}
 	result, err := client.doAPIList(ctx, http.MethodGet, fmt.Sprintf("/v2/projects/%s/envs", projectID), nil)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: map[string]any{"environments": result}}, nil
 }
@@ -130,7 +130,7 @@ func newPermitEnvUpdateStep(name string, config map[string]any) (*permitEnvUpdat
 func (s *permitEnvUpdateStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	projectID := resolvePermitValue("project_id", current, config)
@@ -147,7 +147,7 @@ func (s *permitEnvUpdateStep) Execute(ctx context.Context, _ map[string]any, _ m
 
 	result, err := client.doAPI(ctx, http.MethodPatch, fmt.Sprintf("/v2/projects/%s/envs/%s", projectID, envID), body)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: result}, nil
 }
@@ -170,7 +170,7 @@ func newPermitEnvDeleteStep(name string, config map[string]any) (*permitEnvDelet
 func (s *permitEnvDeleteStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	projectID := resolvePermitValue("project_id", current, config)
@@ -180,7 +180,7 @@ func (s *permitEnvDeleteStep) Execute(ctx context.Context, _ map[string]any, _ m
 	envID := resolvePermitValue("env_id", current, config)
 	_, err := client.doAPI(ctx, http.MethodDelete, fmt.Sprintf("/v2/projects/%s/envs/%s", projectID, envID), nil)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: map[string]any{"deleted": true, "env_id": envID}}, nil
 }
@@ -203,7 +203,7 @@ func newPermitEnvCopyStep(name string, config map[string]any) (*permitEnvCopySte
 func (s *permitEnvCopyStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	projectID := resolvePermitValue("project_id", current, config)
@@ -223,7 +223,7 @@ func (s *permitEnvCopyStep) Execute(ctx context.Context, _ map[string]any, _ map
 
 	result, err := client.doAPI(ctx, http.MethodPost, fmt.Sprintf("/v2/projects/%s/envs/%s/copy", projectID, envID), body)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: result}, nil
 }
@@ -25,7 +25,7 @@ func newPermitResourceInstanceCreateStep(name string, config map[string]any) (*p
 func (s *permitResourceInstanceCreateStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	body := map[string]any{}
@@ -40,7 +40,7 @@ func (s *permitResourceInstanceCreateStep) Execute(ctx context.Context, _ map[st
 
 	result, err := client.doAPI(ctx, http.MethodPost, client.permitFactsPath("resource_instances"), body)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: result}, nil
 }
@@ -63,13 +63,13 @@ func newPermitResourceInstanceGetStep(name string, config map[string]any) (*perm
 func (s *permitResourceInstanceGetStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	instanceID := resolvePermitValue("instance_id", current, config)
 	result, err := client.doAPI(ctx, http.MethodGet, client.permitFactsPath("resource_instances/"+instanceID), nil)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: result}, nil
 }
@@ -89,17 +89,21 @@ func newPermitResourceInstanceListStep(name string, config map[string]any) (*per
 	return &permitResourceInstanceListStep{name: name, moduleName: moduleName}, nil
 }
 
-func (s *permitResourceInstanceListStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, _ map[string]any, _ map[string]any, _ map[string]any) (*sdk.StepResult, error) {
+func (s *permitResourceInstanceListStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
-	result, err := client.doAPIList(ctx, http.MethodGet, client.permitFactsPath("resource_instances"), nil)
+	opts, err := parsePermitListOptions(current, config)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
-	return &sdk.StepResult{Output: map[string]any{"resource_instances": result}}, nil
+	result, err := client.listPages(ctx, client.permitFactsPath("resource_instances"), "resource_instances", opts)
+	if err != nil {
+		return nil, err
+	}
+	return &sdk.StepResult{Output: result}, nil
 }
 
 // --- step.permit_resource_instance_update ---
@@ -120,7 +124,7 @@ func newPermitResourceInstanceUpdateStep(name string, config map[string]any) (*p
 func (s *permitResourceInstanceUpdateStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	instanceID := resolvePermitValue("instance_id", current, config)
@@ -131,7 +135,7 @@ func (s *permitResourceInstanceUpdateStep) Execute(ctx context.Context, _ map[st
 
 	result, err := client.doAPI(ctx, http.MethodPatch, client.permitFactsPath("resource_instances/"+instanceID), body)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: result}, nil
 }
@@ -154,13 +158,13 @@ func newPermitResourceInstanceDeleteStep(name string, config map[string]any) (*p
 func (s *permitResourceInstanceDeleteStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	instanceID := resolvePermitValue("instance_id", current, config)
 	_, err := client.doAPI(ctx, http.MethodDelete, client.permitFactsPath("resource_instances/"+instanceID), nil)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: map[string]any{"deleted": true, "instance_id": instanceID}}, nil
 }
@@ -26,7 +26,7 @@ func newPermitAPIKeyCreateStep(name string, config map[string]any) (*permitAPIKe
 func (s *permitAPIKeyCreateStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	body := map[string]any{}
@@ -38,7 +38,7 @@ func (s *permitAPIKeyCreateStep) Execute(ctx context.Context, _ map[string]any,
 
 	result, err := client.doAPI(ctx, http.MethodPost, "/v2/api-key", body)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: result}, nil
 }
@@ -61,12 +61,12 @@ func newPermitAPIKeyListStep(name string, config map[string]any) (*permitAPIKeyL
 func (s *permitAPIKeyListStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, _ map[string]any, _ map[string]any, _ map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	result, err := client.doAPIList(ctx, http.MethodGet, "/v2/api-key", nil)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: map[string]any{"api_keys": result}}, nil
 }
@@ -89,13 +89,13 @@ func newPermitAPIKeyDeleteStep(name string, config map[string]any) (*permitAPIKe
 func (s *permitAPIKeyDeleteStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	keyID := resolvePermitValue("key_id", current, config)
 	_, err := client.doAPI(ctx, http.MethodDelete, "/v2/api-key/"+keyID, nil)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: map[string]any{"deleted": true, "key_id": keyID}}, nil
 }
@@ -118,13 +118,13 @@ func newPermitAPIKeyRotateStep(name string, config map[string]any) (*permitAPIKe
 func (s *permitAPIKeyRotateStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	keyID := resolvePermitValue("key_id", current, config)
 	result, err := client.doAPI(ctx, http.MethodPost, fmt.Sprintf("/v2/api-key/%s/rotate", keyID), nil)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: result}, nil
 }
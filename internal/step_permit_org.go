@@ -25,12 +25,12 @@ func newPermitOrgGetStep(name string, config map[string]any) (*permitOrgGetStep,
 func (s *permitOrgGetStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, _ map[string]any, _ map[string]any, _ map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	result, err := client.doAPI(ctx, http.MethodGet, "/v2/orgs/me", nil)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: result}, nil
 }
@@ -53,7 +53,7 @@ func newPermitOrgUpdateStep(name string, config map[string]any) (*permitOrgUpdat
 func (s *permitOrgUpdateStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	body := map[string]any{}
@@ -65,7 +65,7 @@ func (s *permitOrgUpdateStep) Execute(ctx context.Context, _ map[string]any, _ m
 
 	result, err := client.doAPI(ctx, http.MethodPatch, "/v2/orgs/me", body)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: result}, nil
 }
@@ -88,12 +88,12 @@ func newPermitOrgMemberListStep(name string, config map[string]any) (*permitOrgM
 func (s *permitOrgMemberListStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, _ map[string]any, _ map[string]any, _ map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	result, err := client.doAPIList(ctx, http.MethodGet, "/v2/members", nil)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: map[string]any{"members": result}}, nil
 }
@@ -116,7 +116,7 @@ func newPermitOrgMemberInviteStep(name string, config map[string]any) (*permitOr
 func (s *permitOrgMemberInviteStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	body := map[string]any{}
@@ -128,7 +128,7 @@ func (s *permitOrgMemberInviteStep) Execute(ctx context.Context, _ map[string]an
 
 	result, err := client.doAPI(ctx, http.MethodPost, "/v2/members", body)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: result}, nil
 }
@@ -151,13 +151,13 @@ func newPermitOrgMemberRemoveStep(name string, config map[string]any) (*permitOr
 func (s *permitOrgMemberRemoveStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	memberID := resolvePermitValue("member_id", current, config)
 	_, err := client.doAPI(ctx, http.MethodDelete, "/v2/members/"+memberID, nil)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: map[string]any{"removed": true, "member_id": memberID}}, nil
 }
@@ -25,7 +25,7 @@ func newPermitProjectCreateStep(name string, config map[string]any) (*permitProj
 func (s *permitProjectCreateStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	body := map[string]any{}
@@ -37,7 +37,7 @@ func (s *permitProjectCreateStep) Execute(ctx context.Context, _ map[string]any,
 
 	result, err := client.doAPI(ctx, http.MethodPost, "/v2/projects", body)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: result}, nil
 }
@@ -60,13 +60,13 @@ func newPermitProjectGetStep(name string, config map[string]any) (*permitProject
 func (s *permitProjectGetStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	projectID := resolvePermitValue("project_id", current, config)
 	result, err := client.doAPI(ctx, http.MethodGet, "/v2/projects/"+projectID, nil)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: result}, nil
 }
@@ -89,12 +89,12 @@ func newPermitProjectListStep(name string, config map[string]any) (*permitProjec
 func (s *permitProjectListStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, _ map[string]any, _ map[string]any, _ map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	result, err := client.doAPIList(ctx, http.MethodGet, "/v2/projects", nil)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: map[string]any{"projects": result}}, nil
 }
@@ -117,7 +117,7 @@ func newPermitProjectUpdateStep(name string, config map[string]any) (*permitProj
 func (s *permitProjectUpdateStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	projectID := resolvePermitValue("project_id", current, config)
@@ -130,7 +130,7 @@ func (s *permitProjectUpdateStep) Execute(ctx context.Context, _ map[string]any,
 
 	result, err := client.doAPI(ctx, http.MethodPatch, "/v2/projects/"+projectID, body)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: result}, nil
 }
@@ -153,13 +153,13 @@ func newPermitProjectDeleteStep(name string, config map[string]any) (*permitProj
 func (s *permitProjectDeleteStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	projectID := resolvePermitValue("project_id", current, config)
 	_, err := client.doAPI(ctx, http.MethodDelete, "/v2/projects/"+projectID, nil)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: map[string]any{"deleted": true, "project_id": projectID}}, nil
 }
@@ -1,8 +1,14 @@
 package internal
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"sort"
+	"strings"
+	"text/template"
 
 	sdk "github.com/GoCodeAlone/workflow/plugin/external/sdk"
 )
@@ -125,13 +131,18 @@ var permitStepConstructors = map[string]func(string, map[string]any) (sdk.StepIn
 	"step.permit_org_member_remove": wrapPermitStep(newPermitOrgMemberRemoveStep),
 }
 
-// createPermitStep dispatches to the appropriate permit step constructor.
+// createPermitStep dispatches to the appropriate permit step constructor and
+// wraps the step in a permitStep.
 func createPermitStep(typeName, name string, config map[string]any) (sdk.StepInstance, error) {
 	ctor, ok := permitStepConstructors[typeName]
 	if !ok {
 		return nil, fmt.Errorf("authz plugin: unknown permit step type %q", typeName)
 	}
-	return ctor(name, config)
+	step, err := ctor(name, config)
+	if err != nil {
+		return nil, err
+	}
+	return newPermitStep(typeName, name, config, step)
 }
 
 func isPermitStepType(typeName string) bool {
@@ -155,3 +166,252 @@ func wrapPermitStep[T sdk.StepInstance](ctor func(string, map[string]any) (T, er
 		return ctor(name, config)
 	}
 }
+
+const (
+	permitOnErrorFail     = "fail"
+	permitOnErrorOutput   = "output"
+	permitOnErrorContinue = "continue"
+)
+
+// permitStep gives every step.permit_* step the same contract. String config
+// values may be Go templates over the trigger data, step outputs and current
+// context, as in step.authz_check_casbin. Every successful write to the
+// Permit management API is recorded in the audit log, with the actor, reason
+// and audit_module keys shared by mutating steps. Errors from the step are
+// handled by on_error:
+//
+//	fail (default)  the step fails with the error.
+//	output          the pipeline stops with an HTTP error response.
+//	continue        the error is written to the output and the pipeline goes on.
+type permitStep struct {
+	typeName   string
+	name       string
+	moduleName string
+	step       sdk.StepInstance
+	onError    string
+	templates  map[string]*template.Template
+	audit      stepAudit
+}
+
+func newPermitStep(typeName, name string, config map[string]any, step sdk.StepInstance) (*permitStep, error) {
+	s := &permitStep{
+		typeName:   typeName,
+		name:       name,
+		moduleName: defaultString(stringValue(config["module"]), "permit"),
+		step:       step,
+		templates:  map[string]*template.Template{},
+		audit:      newStepAudit(typeName, config),
+	}
+	switch onError := stringValue(config["on_error"]); onError {
+	case "", permitOnErrorFail:
+		s.onError = permitOnErrorFail
+	case permitOnErrorOutput, permitOnErrorContinue:
+		s.onError = onError
+	default:
+		return nil, fmt.Errorf("%s %q: on_error must be fail, output, or continue, got %q", typeName, name, onError)
+	}
+	if err := s.parseTemplates(config); err != nil {
+		return nil, fmt.Errorf("%s %q: %w", typeName, name, err)
+	}
+	return s, nil
+}
+
+// parseTemplates compiles every templated string in value.
+func (s *permitStep) parseTemplates(value any) error {
+	switch v := value.(type) {
+	case string:
+		if !isTemplate(v) || s.templates[v] != nil {
+			return nil
+		}
+		tmpl, err := template.New("value").Parse(v)
+		if err != nil {
+			return fmt.Errorf("parse template %q: %w", v, err)
+		}
+		s.templates[v] = tmpl
+	case map[string]any:
+		for _, item := range v {
+			if err := s.parseTemplates(item); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, item := range v {
+			if err := s.parseTemplates(item); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// render returns value with every templated string executed against data.
+func (s *permitStep) render(value any, data map[string]any) (any, error) {
+	switch v := value.(type) {
+	case string:
+		if !isTemplate(v) {
+			return v, nil
+		}
+		if s.templates[v] == nil {
+			if err := s.parseTemplates(v); err != nil {
+				return nil, err
+			}
+		}
+		return resolve(v, s.templates[v], data)
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, item := range v {
+			rendered, err := s.render(item, data)
+			if err != nil {
+				return nil, err
+			}
+			out[key] = rendered
+		}
+		return out, nil
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			rendered, err := s.render(item, data)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = rendered
+		}
+		return out, nil
+	default:
+		return value, nil
+	}
+}
+
+func (s *permitStep) Execute(ctx context.Context, triggerData map[string]any, stepOutputs map[string]map[string]any, current map[string]any, metadata map[string]any, config map[string]any) (*sdk.StepResult, error) {
+	tmplData := buildTemplateData(triggerData, stepOutputs, current)
+	rendered := config
+	if config != nil {
+		value, err := s.render(config, tmplData)
+		if err != nil {
+			return nil, fmt.Errorf("%s %q: render config: %w", s.typeName, s.name, err)
+		}
+		rendered = value.(map[string]any)
+	}
+	// Most Permit steps only read, so a missing actor only matters once a
+	// write has to be recorded.
+	audit, err := s.audit.begin(tmplData)
+	if errors.Is(err, errAuditActorRequired) {
+		err = nil
+	}
+	var result *sdk.StepResult
+	audited := true
+	if err == nil {
+		// Writes made before a later call failed are committed in Permit, so
+		// they are recorded whether or not the step succeeded.
+		writes := &permitWriteLog{}
+		result, err = s.step.Execute(withPermitWriteLog(ctx, writes), triggerData, stepOutputs, current, metadata, rendered)
+		audited = s.recordWrites(ctx, audit, writes.writes)
+	}
+	if err == nil {
+		if result.Output == nil {
+			result.Output = map[string]any{}
+		}
+		markUnaudited(result.Output, audited)
+		return result, nil
+	}
+	switch s.onError {
+	case permitOnErrorContinue:
+		return &sdk.StepResult{Output: markUnaudited(permitErrorOutput(err), audited)}, nil
+	case permitOnErrorOutput:
+		status := permitResponseStatus(err)
+		output := markUnaudited(permitErrorOutput(err), audited)
+		output["response_status"] = status
+		output["response_body"] = fmt.Sprintf(`{"error":%q}`, fmt.Sprintf("permit request failed: %s", http.StatusText(status)))
+		output["response_headers"] = map[string]any{"Content-Type": "application/json"}
+		return &sdk.StepResult{StopPipeline: true, Output: output}, nil
+	default:
+		return nil, fmt.Errorf("%s %q: %w", s.typeName, s.name, err)
+	}
+}
+
+// recordWrites appends one audit entry per Permit write and reports whether
+// all of them were recorded. Deletes record the request as the before value;
+// every other write records it as the after value.
+func (s *permitStep) recordWrites(ctx context.Context, audit pendingAudit, writes []permitWrite) bool {
+	audited := true
+	for _, write := range writes {
+		value := map[string]any{"method": write.Method, "path": write.Path}
+		if body := auditValue(write.Body); body != nil {
+			value["body"] = body
+		}
+		entry := AuditEntry{Module: s.moduleName, Resource: permitAuditResource(s.typeName), Action: "add", After: value}
+		switch write.Method {
+		case http.MethodDelete:
+			entry.Action, entry.Before, entry.After = "remove", value, nil
+		case http.MethodPatch, http.MethodPut:
+			entry.Action = "update"
+		}
+		if tenant, ok := mapValue(value["body"])["tenant"].(string); ok {
+			entry.Context = tenant
+		}
+		if !audit.record(ctx, entry) {
+			audited = false
+		}
+	}
+	return audited
+}
+
+// permitAuditResource returns the adminapi resource a Permit step's writes
+// are audited under.
+func permitAuditResource(typeName string) string {
+	switch {
+	case strings.Contains(typeName, "role_"), strings.Contains(typeName, "bulk_assign"), strings.Contains(typeName, "bulk_unassign"):
+		return "authz.roles"
+	case strings.Contains(typeName, "relationship_tuple"), strings.Contains(typeName, "resource_relation"):
+		return "authz.rebac.tuples"
+	case strings.Contains(typeName, "condition_set"):
+		return "authz.abac.policies"
+	default:
+		return "authz.permit"
+	}
+}
+
+// auditValue converts a request body to its JSON object form.
+func auditValue(value any) map[string]any {
+	if value == nil {
+		return nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	var out map[string]any
+	if json.Unmarshal(data, &out) != nil {
+		return nil
+	}
+	return out
+}
+
+// permitErrorOutput describes err in step output, with the Permit status code
+// when Permit answered.
+func permitErrorOutput(err error) map[string]any {
+	output := map[string]any{"error": err.Error()}
+	var permitErr *PermitError
+	if errors.As(err, &permitErr) {
+		output["status_code"] = permitErr.StatusCode
+	}
+	return output
+}
+
+// permitResponseStatus maps err to the status of the HTTP error response:
+// Permit's own status for client errors about the request, 500 for a missing
+// client, and 502 when Permit failed, could not be reached, or rejected the
+// plugin's own credentials with a 401 or 403.
+func permitResponseStatus(err error) int {
+	var permitErr *PermitError
+	switch {
+	case errors.As(err, &permitErr) && (permitErr.StatusCode == http.StatusUnauthorized || permitErr.StatusCode == http.StatusForbidden):
+		return http.StatusBadGateway
+	case errors.As(err, &permitErr) && permitErr.StatusCode < http.StatusInternalServerError:
+		return permitErr.StatusCode
+	case errors.Is(err, ErrPermitClientNotFound):
+		return http.StatusInternalServerError
+	default:
+		return http.StatusBadGateway
+	}
+}
@@ -0,0 +1,221 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	sdk "github.com/GoCodeAlone/workflow/plugin/external/sdk"
+)
+
+// newTestPermitPDP registers a permitClient named name whose API and PDP are
+// served by handler.
+func newTestPermitPDP(t *testing.T, name string, handler http.HandlerFunc) {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	RegisterPermitClient(name, &permitClient{httpClient: server.Client(), apiURL: server.URL, pdpURL: server.URL, apiKey: "key"})
+	t.Cleanup(func() { UnregisterPermitClient(name) })
+}
+
+func executePermitStep(t *testing.T, typeName string, config, current map[string]any) (*sdk.StepResult, error) {
+	t.Helper()
+	step, err := createPermitStep(typeName, "step", config)
+	if err != nil {
+		t.Fatalf("createPermitStep(%s): %v", typeName, err)
+	}
+	return step.Execute(context.Background(), map[string]any{"user_id": "alice"}, nil, current, nil, config)
+}
+
+func TestPermitStepOnError(t *testing.T) {
+	newTestPermitPDP(t, "permit-errors", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"detail":"not found"}`))
+	})
+	config := map[string]any{"module": "permit-errors", "user_id": "alice"}
+
+	_, err := executePermitStep(t, "step.permit_user_get", config, nil)
+	var permitErr *PermitError
+	if !errors.As(err, &permitErr) || permitErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("default on_error = %v, want a PermitError with status 404", err)
+	}
+
+	config["on_error"] = "continue"
+	result, err := executePermitStep(t, "step.permit_user_get", config, nil)
+	if err != nil || result.StopPipeline || result.Output["status_code"] != http.StatusNotFound || result.Output["error"] == nil {
+		t.Fatalf("on_error continue = %#v, %v", result, err)
+	}
+
+	config["on_error"] = "output"
+	result, err = executePermitStep(t, "step.permit_user_get", config, nil)
+	if err != nil || !result.StopPipeline || result.Output["response_status"] != http.StatusNotFound {
+		t.Fatalf("on_error output = %#v, %v", result, err)
+	}
+
+	// A rejected API key is the plugin's fault, not the caller's.
+	newTestPermitPDP(t, "permit-bad-key", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+	result, err = executePermitStep(t, "step.permit_user_get", map[string]any{"module": "permit-bad-key", "user_id": "alice", "on_error": "output"}, nil)
+	if err != nil || result.Output["response_status"] != http.StatusBadGateway {
+		t.Fatalf("upstream 401 with on_error output = %#v, %v; want 502", result, err)
+	}
+
+	result, err = executePermitStep(t, "step.permit_user_get", map[string]any{"module": "permit-missing", "on_error": "output"}, nil)
+	if err != nil || result.Output["response_status"] != http.StatusInternalServerError {
+		t.Fatalf("missing client with on_error output = %#v, %v", result, err)
+	}
+	if _, err := executePermitStep(t, "step.permit_user_get", map[string]any{"module": "permit-missing"}, nil); !errors.Is(err, ErrPermitClientNotFound) {
+		t.Fatalf("missing client = %v, want ErrPermitClientNotFound", err)
+	}
+
+	if _, err := createPermitStep("step.permit_user_get", "step", map[string]any{"on_error": "ignore"}); err == nil {
+		t.Fatal("expected an error for an unknown on_error")
+	}
+}
+
+func TestPermitCheckStepOnDenyAndTemplates(t *testing.T) {
+	var checked map[string]any
+	newTestPermitPDP(t, "permit-deny", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&checked)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"allow":false}`))
+	})
+	config := map[string]any{
+		"module":        "permit-deny",
+		"user":          "{{.user_id}}",
+		"action":        "read",
+		"resource_type": "document",
+		"resource_key":  "{{.steps.load.doc}}",
+	}
+
+	result, err := executePermitStep(t, "step.permit_check", config, nil)
+	if err != nil || result.StopPipeline || result.Output["allow"] != false {
+		t.Fatalf("deny without on_deny = %#v, %v", result, err)
+	}
+	if user := mapValue(checked["user"])["key"]; user != "alice" {
+		t.Fatalf("templated user = %v, want alice", user)
+	}
+
+	config["on_deny"] = "forbid"
+	step, err := createPermitStep("step.permit_check", "step", config)
+	if err != nil {
+		t.Fatalf("createPermitStep: %v", err)
+	}
+	result, err = step.Execute(context.Background(), map[string]any{"user_id": "alice"}, map[string]map[string]any{"load": {"doc": "budget"}}, nil, nil, config)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	want := forbiddenResult("forbidden: alice is not permitted to read document:budget")
+	if !result.StopPipeline || result.Output["response_status"] != 403 || result.Output["response_body"] != want.Output["response_body"] {
+		t.Fatalf("deny with on_deny forbid = %#v", result)
+	}
+
+	if _, err := createPermitStep("step.permit_check", "step", map[string]any{"user": "{{.user}}{{end}}"}); err == nil {
+		t.Fatal("expected an error for a malformed template")
+	}
+}
+
+func TestPermitCheckBulkStepOnDeny(t *testing.T) {
+	newTestPermitPDP(t, "permit-bulk", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"allow":[{"allow":true},{"allow":false}]}`))
+	})
+	config := map[string]any{
+		"module":  "permit-bulk",
+		"on_deny": "forbid",
+		"checks":  []any{map[string]any{"user": "alice"}, map[string]any{"user": "bob"}},
+	}
+	result, err := executePermitStep(t, "step.permit_check_bulk", config, nil)
+	if err != nil || !result.StopPipeline || result.Output["response_status"] != 403 {
+		t.Fatalf("bulk deny with on_deny forbid = %#v, %v", result, err)
+	}
+}
+
+func TestPermitStepRecordsAuditEntries(t *testing.T) {
+	audit := startAuditModule(t, "permit-audit", nil)
+	newTestPermitPDP(t, "permit-audited", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	})
+	start := time.Now()
+	config := map[string]any{
+		"module":       "permit-audited",
+		"audit_module": "permit-audit",
+		"user":         "bob",
+		"role":         "editor",
+		"tenant":       "acme",
+		"actor":        "{{.user_id}}",
+	}
+	if _, err := executePermitStep(t, "step.permit_role_unassign", config, nil); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	// Reads are not audited.
+	if _, err := executePermitStep(t, "step.permit_role_assignment_list", map[string]any{"module": "permit-audited", "audit_module": "permit-audit"}, nil); err != nil {
+		t.Fatalf("Execute list: %v", err)
+	}
+
+	entries, err := audit.log.List(context.Background(), AuditFilter{Since: start})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("entries = %+v, want 1", entries)
+	}
+	entry := entries[0]
+	if entry.Module != "permit-audited" || entry.Resource != "authz.roles" || entry.Action != "remove" || entry.Actor != "alice" || entry.Context != "acme" {
+		t.Fatalf("entry = %+v", entry)
+	}
+	if entry.Before == nil || entry.After != nil {
+		t.Fatalf("before/after = %v/%v, want only before", entry.Before, entry.After)
+	}
+}
+
+// partialWriteStep makes two Permit writes; the second fails.
+type partialWriteStep struct{ moduleName string }
+
+func (s partialWriteStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, _ map[string]any, _ map[string]any, _ map[string]any) (*sdk.StepResult, error) {
+	client, _ := GetPermitClient(s.moduleName)
+	for _, user := range []string{"bob", "carol"} {
+		if _, err := client.doAPI(ctx, http.MethodPost, client.permitFactsPath("role_assignments"), map[string]any{"user": user, "role": "editor", "tenant": "acme"}); err != nil {
+			return nil, err
+		}
+	}
+	return &sdk.StepResult{Output: map[string]any{}}, nil
+}
+
+func TestPermitStepRecordsWritesBeforeAFailure(t *testing.T) {
+	audit := startAuditModule(t, "permit-partial-audit", nil)
+	newTestPermitPDP(t, "permit-partial", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if body["user"] == "carol" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	})
+	config := map[string]any{"module": "permit-partial", "audit_module": "permit-partial-audit", "actor": "{{.user_id}}"}
+	step, err := newPermitStep("step.permit_role_assign", "step", config, partialWriteStep{moduleName: "permit-partial"})
+	if err != nil {
+		t.Fatalf("newPermitStep: %v", err)
+	}
+	start := time.Now()
+	if _, err := step.Execute(context.Background(), map[string]any{"user_id": "alice"}, nil, nil, nil, config); err == nil {
+		t.Fatal("expected the failed second write to fail the step")
+	}
+
+	entries, err := audit.log.List(context.Background(), AuditFilter{Since: start})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Action != "add" || entries[0].Actor != "alice" {
+		t.Fatalf("entries = %+v, want the committed write to bob", entries)
+	}
+	if body := mapValue(mapValue(entries[0].After)["body"]); body["user"] != "bob" {
+		t.Fatalf("audited write = %+v, want the write to bob", entries[0].After)
+	}
+}
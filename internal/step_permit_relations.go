@@ -25,7 +25,7 @@ func newPermitResourceRelationCreateStep(name string, config map[string]any) (*p
 func (s *permitResourceRelationCreateStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	resourceID := resolvePermitValue("resource_id", current, config)
@@ -38,7 +38,7 @@ func (s *permitResourceRelationCreateStep) Execute(ctx context.Context, _ map[st
 
 	result, err := client.doAPI(ctx, http.MethodPost, client.permitSchemaPath("resources/"+resourceID+"/relations"), body)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: result}, nil
 }
@@ -61,13 +61,13 @@ func newPermitResourceRelationListStep(name string, config map[string]any) (*per
 func (s *permitResourceRelationListStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	resourceID := resolvePermitValue("resource_id", current, config)
 	result, err := client.doAPIList(ctx, http.MethodGet, client.permitSchemaPath("resources/"+resourceID+"/relations"), nil)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: map[string]any{"relations": result}}, nil
 }
@@ -90,14 +90,14 @@ func newPermitResourceRelationDeleteStep(name string, config map[string]any) (*p
 func (s *permitResourceRelationDeleteStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	resourceID := resolvePermitValue("resource_id", current, config)
 	relationID := resolvePermitValue("relation_id", current, config)
 	_, err := client.doAPI(ctx, http.MethodDelete, client.permitSchemaPath("resources/"+resourceID+"/relations/"+relationID), nil)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: map[string]any{"deleted": true, "relation_id": relationID}}, nil
 }
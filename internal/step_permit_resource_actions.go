@@ -25,7 +25,7 @@ func newPermitResourceActionCreateStep(name string, config map[string]any) (*per
 func (s *permitResourceActionCreateStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	resourceID := resolvePermitValue("resource_id", current, config)
@@ -38,7 +38,7 @@ func (s *permitResourceActionCreateStep) Execute(ctx context.Context, _ map[stri
 
 	result, err := client.doAPI(ctx, http.MethodPost, client.permitSchemaPath("resources/"+resourceID+"/actions"), body)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: result}, nil
 }
@@ -61,14 +61,14 @@ func newPermitResourceActionGetStep(name string, config map[string]any) (*permit
 func (s *permitResourceActionGetStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	resourceID := resolvePermitValue("resource_id", current, config)
 	actionID := resolvePermitValue("action_id", current, config)
 	result, err := client.doAPI(ctx, http.MethodGet, client.permitSchemaPath("resources/"+resourceID+"/actions/"+actionID), nil)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: result}, nil
 }
@@ -91,13 +91,13 @@ func newPermitResourceActionListStep(name string, config map[string]any) (*permi
 func (s *permitResourceActionListStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	resourceID := resolvePermitValue("resource_id", current, config)
 	result, err := client.doAPIList(ctx, http.MethodGet, client.permitSchemaPath("resources/"+resourceID+"/actions"), nil)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: map[string]any{"actions": result}}, nil
 }
@@ -120,7 +120,7 @@ func newPermitResourceActionUpdateStep(name string, config map[string]any) (*per
 func (s *permitResourceActionUpdateStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	resourceID := resolvePermitValue("resource_id", current, config)
@@ -134,7 +134,7 @@ func (s *permitResourceActionUpdateStep) Execute(ctx context.Context, _ map[stri
 
 	result, err := client.doAPI(ctx, http.MethodPatch, client.permitSchemaPath("resources/"+resourceID+"/actions/"+actionID), body)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: result}, nil
 }
@@ -157,14 +157,14 @@ func newPermitResourceActionDeleteStep(name string, config map[string]any) (*per
 func (s *permitResourceActionDeleteStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	resourceID := resolvePermitValue("resource_id", current, config)
 	actionID := resolvePermitValue("action_id", current, config)
 	_, err := client.doAPI(ctx, http.MethodDelete, client.permitSchemaPath("resources/"+resourceID+"/actions/"+actionID), nil)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: map[string]any{"deleted": true, "action_id": actionID}}, nil
 }
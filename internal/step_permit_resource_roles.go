@@ -25,7 +25,7 @@ func newPermitResourceRoleCreateStep(name string, config map[string]any) (*permi
 func (s *permitResourceRoleCreateStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	resourceID := resolvePermitValue("resource_id", current, config)
@@ -41,7 +41,7 @@ func (s *permitResourceRoleCreateStep) Execute(ctx context.Context, _ map[string
 
 	result, err := client.doAPI(ctx, http.MethodPost, client.permitSchemaPath("resources/"+resourceID+"/roles"), body)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: result}, nil
 }
@@ -64,14 +64,14 @@ func newPermitResourceRoleGetStep(name string, config map[string]any) (*permitRe
 func (s *permitResourceRoleGetStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	resourceID := resolvePermitValue("resource_id", current, config)
 	roleID := resolvePermitValue("role_id", current, config)
 	result, err := client.doAPI(ctx, http.MethodGet, client.permitSchemaPath("resources/"+resourceID+"/roles/"+roleID), nil)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: result}, nil
 }
@@ -94,13 +94,13 @@ func newPermitResourceRoleListStep(name string, config map[string]any) (*permitR
 func (s *permitResourceRoleListStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	resourceID := resolvePermitValue("resource_id", current, config)
 	result, err := client.doAPIList(ctx, http.MethodGet, client.permitSchemaPath("resources/"+resourceID+"/roles"), nil)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: map[string]any{"roles": result}}, nil
 }
@@ -123,7 +123,7 @@ func newPermitResourceRoleUpdateStep(name string, config map[string]any) (*permi
 func (s *permitResourceRoleUpdateStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	resourceID := resolvePermitValue("resource_id", current, config)
@@ -137,7 +137,7 @@ func (s *permitResourceRoleUpdateStep) Execute(ctx context.Context, _ map[string
 
 	result, err := client.doAPI(ctx, http.MethodPatch, client.permitSchemaPath("resources/"+resourceID+"/roles/"+roleID), body)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: result}, nil
 }
@@ -160,14 +160,14 @@ func newPermitResourceRoleDeleteStep(name string, config map[string]any) (*permi
 func (s *permitResourceRoleDeleteStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	resourceID := resolvePermitValue("resource_id", current, config)
 	roleID := resolvePermitValue("role_id", current, config)
 	_, err := client.doAPI(ctx, http.MethodDelete, client.permitSchemaPath("resources/"+resourceID+"/roles/"+roleID), nil)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: map[string]any{"deleted": true, "role_id": roleID}}, nil
 }
@@ -25,7 +25,7 @@ func newPermitRoleAssignStep(name string, config map[string]any) (*permitRoleAss
 func (s *permitRoleAssignStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	body := map[string]any{}
@@ -37,7 +37,7 @@ func (s *permitRoleAssignStep) Execute(ctx context.Context, _ map[string]any, _
 
 	result, err := client.doAPI(ctx, http.MethodPost, client.permitFactsPath("role_assignments"), body)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: result}, nil
 }
@@ -60,7 +60,7 @@ func newPermitRoleUnassignStep(name string, config map[string]any) (*permitRoleU
 func (s *permitRoleUnassignStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	body := map[string]any{}
@@ -72,7 +72,7 @@ func (s *permitRoleUnassignStep) Execute(ctx context.Context, _ map[string]any,
 
 	_, err := client.doAPI(ctx, http.MethodDelete, client.permitFactsPath("role_assignments"), body)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: map[string]any{"unassigned": true}}, nil
 }
@@ -92,17 +92,21 @@ func newPermitRoleAssignmentListStep(name string, config map[string]any) (*permi
 	return &permitRoleAssignmentListStep{name: name, moduleName: moduleName}, nil
 }
 
-func (s *permitRoleAssignmentListStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, _ map[string]any, _ map[string]any, _ map[string]any) (*sdk.StepResult, error) {
+func (s *permitRoleAssignmentListStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
-	result, err := client.doAPIList(ctx, http.MethodGet, client.permitFactsPath("role_assignments"), nil)
+	opts, err := parsePermitListOptions(current, config)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
-	return &sdk.StepResult{Output: map[string]any{"role_assignments": result}}, nil
+	result, err := client.listPages(ctx, client.permitFactsPath("role_assignments"), "role_assignments", opts)
+	if err != nil {
+		return nil, err
+	}
+	return &sdk.StepResult{Output: result}, nil
 }
 
 // --- step.permit_bulk_assign ---
@@ -123,7 +127,7 @@ func newPermitBulkAssignStep(name string, config map[string]any) (*permitBulkAss
 func (s *permitBulkAssignStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	assignments, _ := config["assignments"].([]any)
@@ -133,7 +137,7 @@ func (s *permitBulkAssignStep) Execute(ctx context.Context, _ map[string]any, _
 
 	result, err := client.doAPI(ctx, http.MethodPost, client.permitFactsPath("role_assignments/bulk"), assignments)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: result}, nil
 }
@@ -156,7 +160,7 @@ func newPermitBulkUnassignStep(name string, config map[string]any) (*permitBulkU
 func (s *permitBulkUnassignStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	assignments, _ := config["assignments"].([]any)
@@ -166,7 +170,7 @@ func (s *permitBulkUnassignStep) Execute(ctx context.Context, _ map[string]any,
 
 	_, err := client.doAPI(ctx, http.MethodDelete, client.permitFactsPath("role_assignments/bulk"), assignments)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: map[string]any{"unassigned": true}}, nil
 }
@@ -25,7 +25,7 @@ func newPermitRoleCreateStep(name string, config map[string]any) (*permitRoleCre
 func (s *permitRoleCreateStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	body := map[string]any{}
@@ -40,7 +40,7 @@ func (s *permitRoleCreateStep) Execute(ctx context.Context, _ map[string]any, _
 
 	result, err := client.doAPI(ctx, http.MethodPost, client.permitSchemaPath("roles"), body)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: result}, nil
 }
@@ -63,13 +63,13 @@ func newPermitRoleGetStep(name string, config map[string]any) (*permitRoleGetSte
 func (s *permitRoleGetStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	roleID := resolvePermitValue("role_id", current, config)
 	result, err := client.doAPI(ctx, http.MethodGet, client.permitSchemaPath("roles/"+roleID), nil)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: result}, nil
 }
@@ -92,12 +92,12 @@ func newPermitRoleListStep(name string, config map[string]any) (*permitRoleListS
 func (s *permitRoleListStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, _ map[string]any, _ map[string]any, _ map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	result, err := client.doAPIList(ctx, http.MethodGet, client.permitSchemaPath("roles"), nil)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: map[string]any{"roles": result}}, nil
 }
@@ -120,7 +120,7 @@ func newPermitRoleUpdateStep(name string, config map[string]any) (*permitRoleUpd
 func (s *permitRoleUpdateStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	roleID := resolvePermitValue("role_id", current, config)
@@ -133,7 +133,7 @@ func (s *permitRoleUpdateStep) Execute(ctx context.Context, _ map[string]any, _
 
 	result, err := client.doAPI(ctx, http.MethodPatch, client.permitSchemaPath("roles/"+roleID), body)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: result}, nil
 }
@@ -156,13 +156,13 @@ func newPermitRoleDeleteStep(name string, config map[string]any) (*permitRoleDel
 func (s *permitRoleDeleteStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	roleID := resolvePermitValue("role_id", current, config)
 	_, err := client.doAPI(ctx, http.MethodDelete, client.permitSchemaPath("roles/"+roleID), nil)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: map[string]any{"deleted": true, "role_id": roleID}}, nil
 }
@@ -185,7 +185,7 @@ func newPermitRoleAssignPermissionsStep(name string, config map[string]any) (*pe
 func (s *permitRoleAssignPermissionsStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	roleID := resolvePermitValue("role_id", current, config)
@@ -194,7 +194,7 @@ func (s *permitRoleAssignPermissionsStep) Execute(ctx context.Context, _ map[str
 
 	result, err := client.doAPI(ctx, http.MethodPost, client.permitSchemaPath("roles/"+roleID+"/permissions"), body)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: result}, nil
 }
@@ -217,7 +217,7 @@ func newPermitRoleRemovePermissionsStep(name string, config map[string]any) (*pe
 func (s *permitRoleRemovePermissionsStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	roleID := resolvePermitValue("role_id", current, config)
@@ -226,7 +226,7 @@ func (s *permitRoleRemovePermissionsStep) Execute(ctx context.Context, _ map[str
 
 	result, err := client.doAPI(ctx, http.MethodDelete, client.permitSchemaPath("roles/"+roleID+"/permissions"), body)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: result}, nil
 }
@@ -25,7 +25,7 @@ func newPermitTenantCreateStep(name string, config map[string]any) (*permitTenan
 func (s *permitTenantCreateStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	body := map[string]any{}
@@ -40,7 +40,7 @@ func (s *permitTenantCreateStep) Execute(ctx context.Context, _ map[string]any,
 
 	result, err := client.doAPI(ctx, http.MethodPost, client.permitFactsPath("tenants"), body)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: result}, nil
 }
@@ -63,13 +63,13 @@ func newPermitTenantGetStep(name string, config map[string]any) (*permitTenantGe
 func (s *permitTenantGetStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	tenantID := resolvePermitValue("tenant_id", current, config)
 	result, err := client.doAPI(ctx, http.MethodGet, client.permitFactsPath("tenants/"+tenantID), nil)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: result}, nil
 }
@@ -92,12 +92,12 @@ func newPermitTenantListStep(name string, config map[string]any) (*permitTenantL
 func (s *permitTenantListStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, _ map[string]any, _ map[string]any, _ map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	result, err := client.doAPIList(ctx, http.MethodGet, client.permitFactsPath("tenants"), nil)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: map[string]any{"tenants": result}}, nil
 }
@@ -120,7 +120,7 @@ func newPermitTenantUpdateStep(name string, config map[string]any) (*permitTenan
 func (s *permitTenantUpdateStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	tenantID := resolvePermitValue("tenant_id", current, config)
@@ -136,7 +136,7 @@ func (s *permitTenantUpdateStep) Execute(ctx context.Context, _ map[string]any,
 
 	result, err := client.doAPI(ctx, http.MethodPatch, client.permitFactsPath("tenants/"+tenantID), body)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: result}, nil
 }
@@ -159,13 +159,13 @@ func newPermitTenantDeleteStep(name string, config map[string]any) (*permitTenan
 func (s *permitTenantDeleteStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	tenantID := resolvePermitValue("tenant_id", current, config)
 	_, err := client.doAPI(ctx, http.MethodDelete, client.permitFactsPath("tenants/"+tenantID), nil)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: map[string]any{"deleted": true, "tenant_id": tenantID}}, nil
 }
@@ -188,13 +188,13 @@ func newPermitTenantListUsersStep(name string, config map[string]any) (*permitTe
 func (s *permitTenantListUsersStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	tenantID := resolvePermitValue("tenant_id", current, config)
 	result, err := client.doAPIList(ctx, http.MethodGet, client.permitFactsPath("tenants/"+tenantID+"/users"), nil)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: map[string]any{"users": result}}, nil
 }
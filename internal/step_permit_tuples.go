@@ -25,7 +25,7 @@ func newPermitRelationshipTupleCreateStep(name string, config map[string]any) (*
 func (s *permitRelationshipTupleCreateStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	body := map[string]any{}
@@ -37,7 +37,7 @@ func (s *permitRelationshipTupleCreateStep) Execute(ctx context.Context, _ map[s
 
 	result, err := client.doAPI(ctx, http.MethodPost, client.permitFactsPath("relationship_tuples"), body)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: result}, nil
 }
@@ -60,7 +60,7 @@ func newPermitRelationshipTupleDeleteStep(name string, config map[string]any) (*
 func (s *permitRelationshipTupleDeleteStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	body := map[string]any{}
@@ -72,7 +72,7 @@ func (s *permitRelationshipTupleDeleteStep) Execute(ctx context.Context, _ map[s
 
 	_, err := client.doAPI(ctx, http.MethodDelete, client.permitFactsPath("relationship_tuples"), body)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: map[string]any{"deleted": true}}, nil
 }
@@ -92,17 +92,21 @@ func newPermitRelationshipTupleListStep(name string, config map[string]any) (*pe
 	return &permitRelationshipTupleListStep{name: name, moduleName: moduleName}, nil
 }
 
-func (s *permitRelationshipTupleListStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, _ map[string]any, _ map[string]any, _ map[string]any) (*sdk.StepResult, error) {
+func (s *permitRelationshipTupleListStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
-	result, err := client.doAPIList(ctx, http.MethodGet, client.permitFactsPath("relationship_tuples"), nil)
+	opts, err := parsePermitListOptions(current, config)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
-	return &sdk.StepResult{Output: map[string]any{"relationship_tuples": result}}, nil
+	result, err := client.listPages(ctx, client.permitFactsPath("relationship_tuples"), "relationship_tuples", opts)
+	if err != nil {
+		return nil, err
+	}
+	return &sdk.StepResult{Output: result}, nil
 }
 
 // --- step.permit_relationship_tuple_bulk_create ---
@@ -123,7 +127,7 @@ func newPermitRelationshipTupleBulkCreateStep(name string, config map[string]any
 func (s *permitRelationshipTupleBulkCreateStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	tuples, _ := config["tuples"].([]any)
@@ -133,7 +137,7 @@ func (s *permitRelationshipTupleBulkCreateStep) Execute(ctx context.Context, _ m
 
 	result, err := client.doAPI(ctx, http.MethodPost, client.permitFactsPath("relationship_tuples/bulk"), tuples)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: result}, nil
 }
@@ -156,7 +160,7 @@ func newPermitRelationshipTupleBulkDeleteStep(name string, config map[string]any
 func (s *permitRelationshipTupleBulkDeleteStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	tuples, _ := config["tuples"].([]any)
@@ -166,7 +170,7 @@ func (s *permitRelationshipTupleBulkDeleteStep) Execute(ctx context.Context, _ m
 
 	_, err := client.doAPI(ctx, http.MethodDelete, client.permitFactsPath("relationship_tuples/bulk"), tuples)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: map[string]any{"deleted": true}}, nil
 }
@@ -25,7 +25,7 @@ func newPermitUserCreateStep(name string, config map[string]any) (*permitUserCre
 func (s *permitUserCreateStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	body := map[string]any{}
@@ -40,7 +40,7 @@ func (s *permitUserCreateStep) Execute(ctx context.Context, _ map[string]any, _
 
 	result, err := client.doAPI(ctx, http.MethodPost, client.permitFactsPath("users"), body)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: result}, nil
 }
@@ -63,13 +63,13 @@ func newPermitUserGetStep(name string, config map[string]any) (*permitUserGetSte
 func (s *permitUserGetStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	userID := resolvePermitValue("user_id", current, config)
 	result, err := client.doAPI(ctx, http.MethodGet, client.permitFactsPath("users/"+userID), nil)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: result}, nil
 }
@@ -89,17 +89,21 @@ func newPermitUserListStep(name string, config map[string]any) (*permitUserListS
 	return &permitUserListStep{name: name, moduleName: moduleName}, nil
 }
 
-func (s *permitUserListStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, _ map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
+func (s *permitUserListStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
-	result, err := client.doAPIList(ctx, http.MethodGet, client.permitFactsPath("users"), nil)
+	opts, err := parsePermitListOptions(current, config)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
-	return &sdk.StepResult{Output: map[string]any{"users": result}}, nil
+	result, err := client.listPages(ctx, client.permitFactsPath("users"), "users", opts)
+	if err != nil {
+		return nil, err
+	}
+	return &sdk.StepResult{Output: result}, nil
 }
 
 // --- step.permit_user_update ---
@@ -120,7 +124,7 @@ func newPermitUserUpdateStep(name string, config map[string]any) (*permitUserUpd
 func (s *permitUserUpdateStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	userID := resolvePermitValue("user_id", current, config)
@@ -136,7 +140,7 @@ func (s *permitUserUpdateStep) Execute(ctx context.Context, _ map[string]any, _
 
 	result, err := client.doAPI(ctx, http.MethodPatch, client.permitFactsPath("users/"+userID), body)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: result}, nil
 }
@@ -159,13 +163,13 @@ func newPermitUserDeleteStep(name string, config map[string]any) (*permitUserDel
 func (s *permitUserDeleteStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	userID := resolvePermitValue("user_id", current, config)
 	_, err := client.doAPI(ctx, http.MethodDelete, client.permitFactsPath("users/"+userID), nil)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: map[string]any{"deleted": true, "user_id": userID}}, nil
 }
@@ -188,7 +192,7 @@ func newPermitUserSyncStep(name string, config map[string]any) (*permitUserSyncS
 func (s *permitUserSyncStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	body := map[string]any{}
@@ -203,7 +207,7 @@ func (s *permitUserSyncStep) Execute(ctx context.Context, _ map[string]any, _ ma
 
 	result, err := client.doAPI(ctx, http.MethodPut, client.permitFactsPath("users"), body)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: result}, nil
 }
@@ -226,13 +230,13 @@ func newPermitUserGetRolesStep(name string, config map[string]any) (*permitUserG
 func (s *permitUserGetRolesStep) Execute(ctx context.Context, _ map[string]any, _ map[string]map[string]any, current map[string]any, _ map[string]any, config map[string]any) (*sdk.StepResult, error) {
 	client, ok := GetPermitClient(s.moduleName)
 	if !ok {
-		return &sdk.StepResult{Output: map[string]any{"error": "permit client not found: " + s.moduleName}}, nil
+		return nil, permitClientNotFound(s.moduleName)
 	}
 
 	userID := resolvePermitValue("user_id", current, config)
 	result, err := client.doAPIList(ctx, http.MethodGet, client.permitFactsPath("users/"+userID+"/roles"), nil)
 	if err != nil {
-		return &sdk.StepResult{Output: map[string]any{"error": err.Error()}}, nil
+		return nil, err
 	}
 	return &sdk.StepResult{Output: map[string]any{"roles": result}}, nil
 }
@@ -20,6 +20,7 @@ type authzReBACAddRelationStep struct {
 	ruleTmpls  []*template.Template
 	ruleStatic []string
 	registry   moduleRegistry
+	audit      stepAudit
 }
 
 func newAuthzReBACAddRelationStep(name string, config map[string]any) (*authzReBACAddRelationStep, error) {
@@ -27,6 +28,7 @@ func newAuthzReBACAddRelationStep(name string, config map[string]any) (*authzReB
 		name:       name,
 		moduleName: "authz",
 		registry:   globalRegistry,
+		audit:      newStepAudit("step.authz_rebac_add_relation", config),
 	}
 	if v, ok := config["module"].(string); ok && v != "" {
 		s.moduleName = v
@@ -43,7 +45,7 @@ func newAuthzReBACAddRelationStep(name string, config map[string]any) (*authzReB
 }
 
 func (s *authzReBACAddRelationStep) Execute(
-	_ context.Context,
+	ctx context.Context,
 	triggerData map[string]any,
 	stepOutputs map[string]map[string]any,
 	current map[string]any,
@@ -56,6 +58,11 @@ func (s *authzReBACAddRelationStep) Execute(
 		return nil, fmt.Errorf("step.authz_rebac_add_relation %q: resolve: %w", s.name, err)
 	}
 
+	audit, err := s.audit.begin(tmplData)
+	if err != nil {
+		return nil, fmt.Errorf("step.authz_rebac_add_relation %q: %w", s.name, err)
+	}
+
 	mod, ok := s.registry.GetEnforcer(s.moduleName)
 	if !ok {
 		return nil, fmt.Errorf("step.authz_rebac_add_relation %q: module %q not found", s.name, s.moduleName)
@@ -79,13 +86,16 @@ func (s *authzReBACAddRelationStep) Execute(
 		}
 	}
 
+	before, after := auditBeforeAfter(relationAuditValue(vals), true, added)
+	audited := audit.record(ctx, AuditEntry{Module: s.moduleName, Resource: "authz.rebac.tuples", Action: "add", Before: before, After: after})
+
 	return &sdk.StepResult{
-		Output: map[string]any{
+		Output: markUnaudited(map[string]any{
 			"added":    added,
 			"subject":  vals[0],
 			"relation": vals[1],
 			"object":   vals[2],
-		},
+		}, audited),
 	}, nil
 }
 
@@ -97,6 +107,7 @@ type authzReBACRemoveRelationStep struct {
 	ruleTmpls  []*template.Template
 	ruleStatic []string
 	registry   moduleRegistry
+	audit      stepAudit
 }
 
 func newAuthzReBACRemoveRelationStep(name string, config map[string]any) (*authzReBACRemoveRelationStep, error) {
@@ -104,6 +115,7 @@ func newAuthzReBACRemoveRelationStep(name string, config map[string]any) (*authz
 		name:       name,
 		moduleName: "authz",
 		registry:   globalRegistry,
+		audit:      newStepAudit("step.authz_rebac_remove_relation", config),
 	}
 	if v, ok := config["module"].(string); ok && v != "" {
 		s.moduleName = v
@@ -119,7 +131,7 @@ func newAuthzReBACRemoveRelationStep(name string, config map[string]any) (*authz
 }
 
 func (s *authzReBACRemoveRelationStep) Execute(
-	_ context.Context,
+	ctx context.Context,
 	triggerData map[string]any,
 	stepOutputs map[string]map[string]any,
 	current map[string]any,
@@ -132,6 +144,11 @@ func (s *authzReBACRemoveRelationStep) Execute(
 		return nil, fmt.Errorf("step.authz_rebac_remove_relation %q: resolve: %w", s.name, err)
 	}
 
+	audit, err := s.audit.begin(tmplData)
+	if err != nil {
+		return nil, fmt.Errorf("step.authz_rebac_remove_relation %q: %w", s.name, err)
+	}
+
 	mod, ok := s.registry.GetEnforcer(s.moduleName)
 	if !ok {
 		return nil, fmt.Errorf("step.authz_rebac_remove_relation %q: module %q not found", s.name, s.moduleName)
@@ -153,13 +170,16 @@ func (s *authzReBACRemoveRelationStep) Execute(
 		}
 	}
 
+	before, after := auditBeforeAfter(relationAuditValue(vals), false, removed)
+	audited := audit.record(ctx, AuditEntry{Module: s.moduleName, Resource: "authz.rebac.tuples", Action: "remove", Before: before, After: after})
+
 	return &sdk.StepResult{
-		Output: map[string]any{
+		Output: markUnaudited(map[string]any{
 			"removed":  removed,
 			"subject":  vals[0],
 			"relation": vals[1],
 			"object":   vals[2],
-		},
+		}, audited),
 	}, nil
 }
 
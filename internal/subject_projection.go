@@ -0,0 +1,330 @@
+package internal
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/GoCodeAlone/workflow-plugin-authz/internal/contracts"
+	sdk "github.com/GoCodeAlone/workflow/plugin/external/sdk"
+)
+
+const defaultProjectionTTL = 5 * time.Minute
+
+// ErrProjectionSignature is returned by VerifySubjectProjection for projections
+// that were not signed by the catalog or have been modified.
+var ErrProjectionSignature = errors.New("invalid projection signature")
+
+// ErrProjectionExpired is returned by VerifySubjectProjection once ExpiresAt
+// has passed.
+var ErrProjectionExpired = errors.New("projection expired")
+
+// SubjectProjection is the per-subject view of the scope catalog that an SPA
+// uses to show or hide UI actions. It is a display hint only: enforcement
+// stays server-side. Signature is an HMAC-SHA256 over every other field, so a
+// cached projection can be handed back to the server and verified.
+type SubjectProjection struct {
+	Subject       string              `json:"subject"`
+	Module        string              `json:"module"`
+	Provider      string              `json:"provider"`
+	Context       string              `json:"context,omitempty"`
+	GrantedScopes []string            `json:"granted_scopes"`
+	UIActionIDs   []string            `json:"ui_action_ids"`
+	Resources     []PermittedResource `json:"resources"`
+	IssuedAt      time.Time           `json:"issued_at"`
+	ExpiresAt     time.Time           `json:"expires_at"`
+	Signature     string              `json:"signature,omitempty"`
+}
+
+// PermittedResource lists the actions a subject holds on one declared
+// resource.
+type PermittedResource struct {
+	Context  string   `json:"context"`
+	Resource string   `json:"resource"`
+	Actions  []string `json:"actions"`
+}
+
+// scopeCatalogRegistry looks up authz.scope_catalog modules by name.
+type scopeCatalogRegistry interface {
+	GetScopeCatalog(name string) (*scopeCatalogModule, bool)
+}
+
+// registerScopeCatalog adds a scope catalog to the global registry so steps
+// and the adminapi bridge can find it by name.
+func registerScopeCatalog(m *scopeCatalogModule) {
+	globalRegistry.setScopeCatalog(m.name, m)
+}
+
+func lookupScopeCatalog(registry moduleRegistry, name string) (*scopeCatalogModule, error) {
+	catalogs, ok := registry.(scopeCatalogRegistry)
+	if !ok {
+		return nil, fmt.Errorf("registry cannot look up scope catalog %q", name)
+	}
+	catalog, ok := catalogs.GetScopeCatalog(name)
+	if !ok {
+		return nil, fmt.Errorf("scope catalog %q not found", name)
+	}
+	return catalog, nil
+}
+
+func newProjectionSigningKey(configured string) []byte {
+	if configured != "" {
+		return []byte(configured)
+	}
+	// Without a configured key projections are only verifiable by this
+	// process, which is enough for a single-instance host.
+	key := make([]byte, 32)
+	_, _ = rand.Read(key)
+	return key
+}
+
+// resolveSubjectProjection evaluates every declared scope and UI action of the
+// catalog for input.Subject against the named provider module.
+func (m *scopeCatalogModule) resolveSubjectProjection(ctx context.Context, registry moduleRegistry, input *contracts.ResolveSubjectProjectionInput) (SubjectProjection, error) {
+	if input.GetSubject() == "" {
+		return SubjectProjection{}, fmt.Errorf("subject is required")
+	}
+	projection := SubjectProjection{
+		Subject:  input.GetSubject(),
+		Module:   defaultString(input.GetModule(), "authz"),
+		Provider: defaultString(input.GetProvider(), "casbin"),
+		Context:  input.GetContext(),
+		// Empty rather than nil slices keep the signed JSON identical after
+		// a client round trip.
+		GrantedScopes: []string{},
+		UIActionIDs:   []string{},
+		Resources:     []PermittedResource{},
+	}
+	roles, _, err := resolveScopeRoles(registry, projection.Module, projection.Provider)
+	if err != nil {
+		return SubjectProjection{}, err
+	}
+	var descriptors []CapabilityDescriptor
+	if provider, ok := roles.(AuthzProvider); ok {
+		descriptors = provider.CapabilityDescriptors()
+	}
+
+	declarations := m.listDeclarations(&contracts.ListDeclarationsInput{Context: projection.Context})
+	granted := map[string]bool{}
+	check := func(contextName, scope string) (bool, error) {
+		key := contextName + "|" + scope
+		if allowed, ok := granted[key]; ok {
+			return allowed, nil
+		}
+		result, err := roles.CheckScope(ctx, ScopeCheck{Subject: projection.Subject, Context: contextName, Scope: scope})
+		if err != nil {
+			return false, fmt.Errorf("check scope %q: %w", scope, err)
+		}
+		granted[key] = result.Allowed
+		return result.Allowed, nil
+	}
+
+	resources := map[string]*PermittedResource{}
+	for _, scope := range declarations.GetScopes() {
+		allowed, err := check(scope.GetContext(), scope.GetName())
+		if err != nil {
+			return SubjectProjection{}, err
+		}
+		if !allowed {
+			continue
+		}
+		projection.GrantedScopes = append(projection.GrantedScopes, scope.GetName())
+		if scope.GetResource() == "" {
+			continue
+		}
+		key := resourceKey(scope.GetContext(), scope.GetResource())
+		resource, ok := resources[key]
+		if !ok {
+			resource = &PermittedResource{Context: scope.GetContext(), Resource: scope.GetResource()}
+			resources[key] = resource
+		}
+		resource.Actions = uniqueStrings(append(resource.Actions, scope.GetActions()...))
+	}
+	for _, resource := range resources {
+		sort.Strings(resource.Actions)
+		projection.Resources = append(projection.Resources, *resource)
+	}
+	sort.Slice(projection.Resources, func(i, j int) bool {
+		return resourceKey(projection.Resources[i].Context, projection.Resources[i].Resource) <
+			resourceKey(projection.Resources[j].Context, projection.Resources[j].Resource)
+	})
+
+	for _, action := range declarations.GetUiActions() {
+		visible := len(missingCapabilityRequirements(descriptors, uiActionRequirements(action))) == 0
+		for _, scope := range action.GetRequiredScopes() {
+			if !visible {
+				break
+			}
+			if visible, err = check(action.GetContext(), scope); err != nil {
+				return SubjectProjection{}, err
+			}
+		}
+		if visible {
+			projection.UIActionIDs = append(projection.UIActionIDs, action.GetId())
+		}
+	}
+	sort.Strings(projection.GrantedScopes)
+	sort.Strings(projection.UIActionIDs)
+
+	projection.IssuedAt = m.now().UTC().Truncate(time.Second)
+	projection.ExpiresAt = projection.IssuedAt.Add(m.projectionTTL)
+	projection.Signature = m.signProjection(projection)
+	return projection, nil
+}
+
+func uiActionRequirements(action *contracts.UIActionDeclaration) []CapabilityRequirement {
+	return capabilityRequirementsFromAny(capabilityRequirementsToAny(action.GetRequiredCapabilities()))
+}
+
+func (m *scopeCatalogModule) signProjection(projection SubjectProjection) string {
+	projection.Signature = ""
+	payload, _ := json.Marshal(projection)
+	mac := hmac.New(sha256.New, m.projectionKey)
+	mac.Write(payload)
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// verifySubjectProjection checks the signature and expiry of a projection
+// issued by this catalog.
+func (m *scopeCatalogModule) verifySubjectProjection(projection SubjectProjection) error {
+	if !hmac.Equal([]byte(projection.Signature), []byte(m.signProjection(projection))) {
+		return ErrProjectionSignature
+	}
+	if !m.now().Before(projection.ExpiresAt) {
+		return ErrProjectionExpired
+	}
+	return nil
+}
+
+func resolveSubjectProjectionInputFromMap(values map[string]any) *contracts.ResolveSubjectProjectionInput {
+	return &contracts.ResolveSubjectProjectionInput{
+		Subject:  stringValue(values["subject"]),
+		Module:   stringValue(values["module"]),
+		Provider: stringValue(values["provider"]),
+		Context:  stringValue(values["context"]),
+	}
+}
+
+func subjectProjectionToMap(projection SubjectProjection) map[string]any {
+	resources := make([]any, 0, len(projection.Resources))
+	for _, resource := range projection.Resources {
+		resources = append(resources, map[string]any{
+			"context":  resource.Context,
+			"resource": resource.Resource,
+			"actions":  stringsToAny(resource.Actions),
+		})
+	}
+	out := compactMap(map[string]any{
+		"subject":    projection.Subject,
+		"module":     projection.Module,
+		"provider":   projection.Provider,
+		"context":    projection.Context,
+		"issued_at":  projection.IssuedAt.Format(time.RFC3339),
+		"expires_at": projection.ExpiresAt.Format(time.RFC3339),
+		"signature":  projection.Signature,
+	})
+	out["granted_scopes"] = stringsToAny(projection.GrantedScopes)
+	out["ui_action_ids"] = stringsToAny(projection.UIActionIDs)
+	out["resources"] = resources
+	return out
+}
+
+func subjectProjectionFromMap(values map[string]any) *contracts.SubjectProjection {
+	out := &contracts.SubjectProjection{
+		Subject:       stringValue(values["subject"]),
+		Module:        stringValue(values["module"]),
+		Provider:      stringValue(values["provider"]),
+		Context:       stringValue(values["context"]),
+		GrantedScopes: stringSliceValue(values["granted_scopes"]),
+		UiActionIds:   stringSliceValue(values["ui_action_ids"]),
+		IssuedAt:      stringValue(values["issued_at"]),
+		ExpiresAt:     stringValue(values["expires_at"]),
+		Signature:     stringValue(values["signature"]),
+	}
+	items, _ := values["resources"].([]any)
+	for _, item := range items {
+		resource := mapValue(item)
+		out.Resources = append(out.Resources, &contracts.PermittedResource{
+			Context:  stringValue(resource["context"]),
+			Resource: stringValue(resource["resource"]),
+			Actions:  stringSliceValue(resource["actions"]),
+		})
+	}
+	return out
+}
+
+// authzSubjectProjectionStep implements sdk.StepInstance. It resolves the
+// signed SPA access projection for one subject.
+//
+// Config:
+//
+//	catalog: "catalog"             # authz.scope_catalog module name (required)
+//	module: "authz"                # provider module name (default: "authz")
+//	provider: "casbin"             # "casbin" (default), "keto", or "permit"
+//	subject: "{{.auth_user_id}}"   # Go template (required)
+//	context: "cms"                 # optional context filter; Go template
+type authzSubjectProjectionStep struct {
+	name        string
+	catalogName string
+	moduleName  string
+	provider    string
+	static      []string
+	tmpls       []*template.Template
+	registry    moduleRegistry
+}
+
+func newAuthzSubjectProjectionStep(name string, config map[string]any) (*authzSubjectProjectionStep, error) {
+	step := &authzSubjectProjectionStep{
+		name:        name,
+		catalogName: stringValue(config["catalog"]),
+		moduleName:  stringValue(config["module"]),
+		provider:    stringValue(config["provider"]),
+		registry:    globalRegistry,
+	}
+	if step.catalogName == "" {
+		return nil, fmt.Errorf("step.authz_subject_projection %q: config.catalog is required", name)
+	}
+	subject := stringValue(config["subject"])
+	if strings.TrimSpace(subject) == "" {
+		return nil, fmt.Errorf("step.authz_subject_projection %q: config.subject is required", name)
+	}
+	step.static, step.tmpls = compileRuleTemplates([]string{subject, stringValue(config["context"])})
+	return step, nil
+}
+
+func (s *authzSubjectProjectionStep) Execute(
+	ctx context.Context,
+	triggerData map[string]any,
+	stepOutputs map[string]map[string]any,
+	current map[string]any,
+	_ map[string]any,
+	_ map[string]any,
+) (*sdk.StepResult, error) {
+	values, err := resolveRule(s.static, s.tmpls, buildTemplateData(triggerData, stepOutputs, current))
+	if err != nil {
+		return nil, fmt.Errorf("step.authz_subject_projection %q: resolve: %w", s.name, err)
+	}
+	catalog, err := lookupScopeCatalog(s.registry, s.catalogName)
+	if err != nil {
+		return nil, fmt.Errorf("step.authz_subject_projection %q: %w", s.name, err)
+	}
+	projection, err := catalog.resolveSubjectProjection(ctx, s.registry, &contracts.ResolveSubjectProjectionInput{
+		Subject:  values[0],
+		Module:   s.moduleName,
+		Provider: s.provider,
+		Context:  values[1],
+	})
+	if err != nil {
+		return nil, fmt.Errorf("step.authz_subject_projection %q: %w", s.name, err)
+	}
+	return &sdk.StepResult{Output: map[string]any{"projection": subjectProjectionToMap(projection)}}, nil
+}
@@ -0,0 +1,145 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GoCodeAlone/workflow-plugin-authz/internal/contracts"
+)
+
+type projectionTestRegistry struct {
+	testRegistry
+	catalog *scopeCatalogModule
+}
+
+func (r *projectionTestRegistry) GetScopeCatalog(name string) (*scopeCatalogModule, bool) {
+	return r.catalog, r.catalog != nil && r.catalog.name == name
+}
+
+func projectionTestSetup(t *testing.T) (*scopeCatalogModule, *projectionTestRegistry) {
+	t.Helper()
+	ctx := context.Background()
+	mod := rbacTestModule(t, nil, nil)
+	scopes := []*contracts.ScopeDeclaration{
+		{Name: "cms:page:read"},
+		{Name: "cms:page:publish"},
+		{Name: "billing:invoice:read"},
+	}
+	if err := mod.DeclareScopes(ctx, scopes); err != nil {
+		t.Fatalf("DeclareScopes: %v", err)
+	}
+	if err := mod.UpsertRole(ctx, RoleScopeGrant{Role: "editor", Context: "cms", Scopes: []string{"cms:page:read"}}); err != nil {
+		t.Fatalf("UpsertRole: %v", err)
+	}
+	if err := mod.AssignRole(ctx, SubjectRoleAssignment{Subject: "alice", Role: "editor", Context: "cms"}); err != nil {
+		t.Fatalf("AssignRole: %v", err)
+	}
+
+	catalog := newScopeCatalogModule("catalog", map[string]any{
+		"projection_signing_key": "test-key",
+		"declarations": map[string]any{
+			"scopes": []any{
+				map[string]any{"name": "cms:page:read", "context": "cms", "resource": "page", "actions": []any{"read"}},
+				map[string]any{"name": "cms:page:publish", "context": "cms", "resource": "page", "actions": []any{"publish"}},
+				map[string]any{"name": "billing:invoice:read", "context": "billing", "resource": "invoice", "actions": []any{"read"}},
+			},
+			"ui_actions": []any{
+				map[string]any{"id": "page.view", "context": "cms", "label": "View", "required_scopes": []any{"cms:page:read"}},
+				map[string]any{"id": "page.publish", "context": "cms", "label": "Publish", "required_scopes": []any{"cms:page:publish"}},
+				map[string]any{"id": "page.graph", "context": "cms", "label": "Graph", "required_scopes": []any{"cms:page:read"},
+					"required_capabilities": []any{map[string]any{"mode": "abac", "operations": []any{"manage_policies"}}}},
+			},
+		},
+	})
+	registry := &projectionTestRegistry{testRegistry: testRegistry{mod: mod}, catalog: catalog}
+	catalog.registry = registry
+	return catalog, registry
+}
+
+func TestResolveSubjectProjectionEvaluatesDeclarations(t *testing.T) {
+	catalog, _ := projectionTestSetup(t)
+	out, err := catalog.InvokeMethod("ResolveSubjectProjection", map[string]any{"subject": "alice", "module": "authz"})
+	if err != nil {
+		t.Fatalf("ResolveSubjectProjection: %v", err)
+	}
+	projection := subjectProjectionFromMap(mapValue(out["projection"]))
+	if strings.Join(projection.GetGrantedScopes(), ",") != "cms:page:read" {
+		t.Fatalf("granted scopes = %v", projection.GetGrantedScopes())
+	}
+	if strings.Join(projection.GetUiActionIds(), ",") != "page.view" {
+		t.Fatalf("ui actions = %v, want only page.view", projection.GetUiActionIds())
+	}
+	resources := projection.GetResources()
+	if len(resources) != 1 || resources[0].GetResource() != "page" || strings.Join(resources[0].GetActions(), ",") != "read" {
+		t.Fatalf("resources = %v", resources)
+	}
+	if projection.GetSignature() == "" || projection.GetExpiresAt() == "" {
+		t.Fatalf("projection is not signed: %v", projection)
+	}
+
+	if _, err := catalog.InvokeMethod("ResolveSubjectProjection", map[string]any{"module": "authz"}); err == nil {
+		t.Fatal("expected error without subject")
+	}
+}
+
+func TestSubjectProjectionSignatureAndExpiry(t *testing.T) {
+	catalog, registry := projectionTestSetup(t)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	catalog.now = func() time.Time { return now }
+	projection, err := catalog.resolveSubjectProjection(context.Background(), registry, &contracts.ResolveSubjectProjectionInput{Subject: "alice"})
+	if err != nil {
+		t.Fatalf("resolveSubjectProjection: %v", err)
+	}
+	if !projection.ExpiresAt.Equal(now.Add(defaultProjectionTTL)) {
+		t.Fatalf("ExpiresAt = %v", projection.ExpiresAt)
+	}
+	if err := catalog.verifySubjectProjection(projection); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+
+	tampered := projection
+	tampered.UIActionIDs = append([]string{"page.publish"}, projection.UIActionIDs...)
+	if err := catalog.verifySubjectProjection(tampered); !errors.Is(err, ErrProjectionSignature) {
+		t.Fatalf("tampered projection: got %v, want ErrProjectionSignature", err)
+	}
+
+	now = now.Add(defaultProjectionTTL)
+	if err := catalog.verifySubjectProjection(projection); !errors.Is(err, ErrProjectionExpired) {
+		t.Fatalf("expired projection: got %v, want ErrProjectionExpired", err)
+	}
+}
+
+func TestScopeCatalogRejectsInvalidProjectionTTL(t *testing.T) {
+	catalog := newScopeCatalogModule("catalog", map[string]any{"projection_ttl": "soon"})
+	if err := catalog.Init(); err == nil {
+		t.Fatal("expected error for invalid projection_ttl")
+	}
+}
+
+func TestSubjectProjectionStep(t *testing.T) {
+	_, registry := projectionTestSetup(t)
+	step, err := newAuthzSubjectProjectionStep("projection", map[string]any{
+		"catalog": "catalog",
+		"subject": "{{.auth_user_id}}",
+		"context": "cms",
+	})
+	if err != nil {
+		t.Fatalf("newAuthzSubjectProjectionStep: %v", err)
+	}
+	step.registry = registry
+	result, err := step.Execute(context.Background(), map[string]any{"auth_user_id": "alice"}, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	projection := mapValue(result.Output["projection"])
+	if projection["subject"] != "alice" || projection["context"] != "cms" {
+		t.Fatalf("projection = %v", projection)
+	}
+
+	if _, err := newAuthzSubjectProjectionStep("bad", map[string]any{"subject": "alice"}); err == nil {
+		t.Fatal("expected error without catalog")
+	}
+}
@@ -14,7 +14,8 @@ func casbinModuleConfigToMap(cfg *contracts.CasbinModuleConfig) map[string]any {
 		return nil
 	}
 	out := compactMap(map[string]any{
-		"model": cfg.GetModel(),
+		"model":   cfg.GetModel(),
+		"catalog": cfg.GetCatalog(),
 	})
 	if policies := stringListsToAny(cfg.GetPolicies()); len(policies) > 0 {
 		out["policies"] = policies
@@ -23,37 +24,65 @@ func casbinModuleConfigToMap(cfg *contracts.CasbinModuleConfig) map[string]any {
 		out["roleAssignments"] = assignments
 	}
 	if adapter := cfg.GetAdapter(); adapter != nil {
-		out["adapter"] = compactMap(map[string]any{
-			"type":         adapter.GetType(),
-			"path":         adapter.GetPath(),
-			"driver":       adapter.GetDriver(),
-			"dsn":          adapter.GetDsn(),
-			"table_name":   adapter.GetTableName(),
-			"tenant":       adapter.GetTenant(),
-			"filter_field": adapter.GetFilterField(),
-			"filter_value": adapter.GetFilterValue(),
-		})
+		out["adapter"] = adapterConfigToMap(adapter)
 	}
 	if watcher := cfg.GetWatcher(); watcher != nil {
-		out["watcher"] = compactMap(map[string]any{
-			"type":     watcher.GetType(),
-			"interval": watcher.GetInterval(),
+		out["watcher"] = watcherConfigToMap(watcher)
+	}
+	if relationModel := cfg.GetRelationModel(); relationModel != nil {
+		out["relation_model"] = compactMap(map[string]any{
+			"format": relationModel.GetFormat(),
+			"schema": relationModel.GetSchema(),
+			"file":   relationModel.GetFile(),
 		})
 	}
 	return out
 }
 
+func adapterConfigToMap(adapter *contracts.AdapterConfig) map[string]any {
+	return compactMap(map[string]any{
+		"type":         adapter.GetType(),
+		"path":         adapter.GetPath(),
+		"driver":       adapter.GetDriver(),
+		"dsn":          adapter.GetDsn(),
+		"table_name":   adapter.GetTableName(),
+		"tenant":       adapter.GetTenant(),
+		"filter_field": adapter.GetFilterField(),
+		"filter_value": adapter.GetFilterValue(),
+	})
+}
+
+func watcherConfigToMap(watcher *contracts.WatcherConfig) map[string]any {
+	return compactMap(map[string]any{
+		"type":     watcher.GetType(),
+		"interval": watcher.GetInterval(),
+	})
+}
+
 func permitModuleConfigToMap(cfg *contracts.PermitModuleConfig) map[string]any {
 	if cfg == nil {
 		return nil
 	}
-	return compactMap(map[string]any{
-		"apiKey":      cfg.GetApiKey(),
-		"pdpUrl":      cfg.GetPdpUrl(),
-		"apiUrl":      cfg.GetApiUrl(),
-		"project":     cfg.GetProject(),
-		"environment": cfg.GetEnvironment(),
+	out := compactMap(map[string]any{
+		"apiKey":        cfg.GetApiKey(),
+		"pdpUrl":        cfg.GetPdpUrl(),
+		"apiUrl":        cfg.GetApiUrl(),
+		"project":       cfg.GetProject(),
+		"environment":   cfg.GetEnvironment(),
+		"catalog":       cfg.GetCatalog(),
+		"onUnavailable": cfg.GetOnUnavailable(),
 	})
+	if transport := cfg.GetTransport(); transport != nil {
+		out["transport"] = transportConfigToMap(transport)
+	}
+	if localPDP := cfg.GetLocalPdp(); localPDP != nil {
+		out["localPdp"] = compactMap(map[string]any{
+			"enabled":      localPDP.GetEnabled(),
+			"interval":     localPDP.GetInterval(),
+			"maxStaleness": localPDP.GetMaxStaleness(),
+		})
+	}
+	return out
 }
 
 func typedAuthzCheck(registry moduleRegistry) sdk.TypedStepHandler[*contracts.AuthzCheckConfig, *contracts.AuthzCheckInput, *contracts.AuthzCheckOutput] {
@@ -180,6 +209,25 @@ func typedAuthzRequireCapabilities(registry moduleRegistry) sdk.TypedStepHandler
 	}
 }
 
+func typedAuthzSimulate(registry moduleRegistry) sdk.TypedStepHandler[*contracts.SimulateConfig, *contracts.SimulateInput, *contracts.SimulateOutput] {
+	return func(ctx context.Context, req sdk.TypedStepRequest[*contracts.SimulateConfig, *contracts.SimulateInput]) (*sdk.TypedStepResult[*contracts.SimulateOutput], error) {
+		cfg := simulateConfigToMap(req.Config)
+		for k, v := range simulateInputToMap(req.Input) {
+			cfg[k] = v
+		}
+		step, err := newAuthzSimulateStep("typed", cfg)
+		if err != nil {
+			return nil, err
+		}
+		step.registry = registry
+		result, err := step.Execute(ctx, req.TriggerData, req.StepOutputs, req.Current, req.Metadata, nil)
+		if err != nil {
+			return nil, err
+		}
+		return &sdk.TypedStepResult[*contracts.SimulateOutput]{Output: simulateOutputFromMap(result.Output)}, nil
+	}
+}
+
 func typedSubjectObjectAction(create func(string, map[string]any) (sdk.StepInstance, error), registry moduleRegistry) sdk.TypedStepHandler[*contracts.SubjectObjectActionConfig, *contracts.SubjectObjectActionInput, *contracts.SubjectObjectActionOutput] {
 	return func(ctx context.Context, req sdk.TypedStepRequest[*contracts.SubjectObjectActionConfig, *contracts.SubjectObjectActionInput]) (*sdk.TypedStepResult[*contracts.SubjectObjectActionOutput], error) {
 		cfg := mergeStringFields(subjectObjectActionConfigToMap(req.Config), subjectObjectActionInputToMap(req.Input))
@@ -302,11 +350,41 @@ func authzCheckInputToMap(input *contracts.AuthzCheckInput) map[string]any {
 	return out
 }
 
+func auditModuleConfigToMap(cfg *contracts.AuditModuleConfig) map[string]any {
+	if cfg == nil {
+		return nil
+	}
+	return compactMap(map[string]any{"sink": cfg.GetSink(), "path": cfg.GetPath(), "driver": cfg.GetDriver(), "dsn": cfg.GetDsn(), "table_name": cfg.GetTableName()})
+}
+
+func compositeModuleConfigToMap(cfg *contracts.CompositeModuleConfig) map[string]any {
+	if cfg == nil {
+		return nil
+	}
+	out := compactMap(map[string]any{"combine": cfg.GetCombine()})
+	if len(cfg.GetFallback()) > 0 {
+		out["fallback"] = stringsToAny(cfg.GetFallback())
+	}
+	routes := make([]any, 0, len(cfg.GetRoutes()))
+	for _, route := range cfg.GetRoutes() {
+		values := compactMap(map[string]any{"context": route.GetContext(), "mode": authzModeString(route.GetMode()), "resource_prefix": route.GetResourcePrefix(), "combine": route.GetCombine()})
+		values["providers"] = stringsToAny(route.GetProviders())
+		if len(route.GetFallback()) > 0 {
+			values["fallback"] = stringsToAny(route.GetFallback())
+		}
+		routes = append(routes, values)
+	}
+	if len(routes) > 0 {
+		out["routes"] = routes
+	}
+	return out
+}
+
 func policyRuleConfigToMap(cfg *contracts.PolicyRuleConfig) map[string]any {
 	if cfg == nil {
 		return nil
 	}
-	out := compactMap(map[string]any{"module": cfg.GetModule()})
+	out := compactMap(map[string]any{"module": cfg.GetModule(), "actor": cfg.GetActor(), "reason": cfg.GetReason(), "audit_module": cfg.GetAuditModule()})
 	if len(cfg.GetRule()) > 0 {
 		out["rule"] = stringsToAny(cfg.GetRule())
 	}
@@ -317,7 +395,7 @@ func policyRuleInputToMap(input *contracts.PolicyRuleInput) map[string]any {
 	if input == nil {
 		return nil
 	}
-	out := compactMap(map[string]any{"module": input.GetModule()})
+	out := compactMap(map[string]any{"module": input.GetModule(), "actor": input.GetActor(), "reason": input.GetReason(), "audit_module": input.GetAuditModule()})
 	if len(input.GetRule()) > 0 {
 		out["rule"] = stringsToAny(input.GetRule())
 	}
@@ -328,7 +406,7 @@ func roleAssignConfigToMap(cfg *contracts.RoleAssignConfig) map[string]any {
 	if cfg == nil {
 		return nil
 	}
-	out := compactMap(map[string]any{"module": cfg.GetModule(), "action": cfg.GetAction()})
+	out := compactMap(map[string]any{"module": cfg.GetModule(), "action": cfg.GetAction(), "actor": cfg.GetActor(), "reason": cfg.GetReason(), "audit_module": cfg.GetAuditModule()})
 	if assignments := stringListsToAny(cfg.GetAssignments()); len(assignments) > 0 {
 		out["assignments"] = assignments
 	}
@@ -339,7 +417,7 @@ func roleAssignInputToMap(input *contracts.RoleAssignInput) map[string]any {
 	if input == nil {
 		return nil
 	}
-	out := compactMap(map[string]any{"module": input.GetModule(), "action": input.GetAction()})
+	out := compactMap(map[string]any{"module": input.GetModule(), "action": input.GetAction(), "actor": input.GetActor(), "reason": input.GetReason(), "audit_module": input.GetAuditModule()})
 	if assignments := stringListsToAny(input.GetAssignments()); len(assignments) > 0 {
 		out["assignments"] = assignments
 	}
@@ -364,7 +442,7 @@ func authorizationDecisionConfigToMap(cfg *contracts.AuthorizationDecisionConfig
 	if cfg == nil {
 		return map[string]any{}
 	}
-	return compactMap(map[string]any{"module": cfg.GetModule(), "provider": cfg.GetProvider(), "mode": authzModeString(cfg.GetMode()), "subject": cfg.GetSubject(), "context": cfg.GetContext(), "resource": cfg.GetResource(), "action": cfg.GetAction(), "scope": cfg.GetScope(), "relation": cfg.GetRelation(), "explain": cfg.GetExplain()})
+	return compactMap(map[string]any{"module": cfg.GetModule(), "provider": cfg.GetProvider(), "mode": authzModeString(cfg.GetMode()), "subject": cfg.GetSubject(), "context": cfg.GetContext(), "resource": cfg.GetResource(), "action": cfg.GetAction(), "scope": cfg.GetScope(), "relation": cfg.GetRelation(), "explain": cfg.GetExplain(), "on_unavailable": cfg.GetOnUnavailable()})
 }
 
 func authorizationDecisionInputToMap(input *contracts.AuthorizationDecisionInput) map[string]any {
@@ -381,6 +459,35 @@ func requireCapabilitiesConfigToMap(cfg *contracts.RequireCapabilitiesConfig) ma
 	return compactMap(map[string]any{"module": cfg.GetModule(), "provider": cfg.GetProvider(), "requirements": capabilityRequirementsToAny(cfg.GetRequirements())})
 }
 
+func typedAuthzSubjectProjection(registry moduleRegistry) sdk.TypedStepHandler[*contracts.SubjectProjectionConfig, *contracts.SubjectProjectionInput, *contracts.ResolveSubjectProjectionOutput] {
+	return func(ctx context.Context, req sdk.TypedStepRequest[*contracts.SubjectProjectionConfig, *contracts.SubjectProjectionInput]) (*sdk.TypedStepResult[*contracts.ResolveSubjectProjectionOutput], error) {
+		step, err := newAuthzSubjectProjectionStep("typed", mergeStringFields(subjectProjectionConfigToMap(req.Config), subjectProjectionInputToMap(req.Input)))
+		if err != nil {
+			return nil, err
+		}
+		step.registry = registry
+		result, err := step.Execute(ctx, req.TriggerData, req.StepOutputs, req.Current, req.Metadata, nil)
+		if err != nil {
+			return nil, err
+		}
+		return &sdk.TypedStepResult[*contracts.ResolveSubjectProjectionOutput]{Output: &contracts.ResolveSubjectProjectionOutput{Projection: subjectProjectionFromMap(mapValue(result.Output["projection"]))}}, nil
+	}
+}
+
+func subjectProjectionConfigToMap(cfg *contracts.SubjectProjectionConfig) map[string]any {
+	if cfg == nil {
+		return map[string]any{}
+	}
+	return compactMap(map[string]any{"catalog": cfg.GetCatalog(), "module": cfg.GetModule(), "provider": cfg.GetProvider(), "subject": cfg.GetSubject(), "context": cfg.GetContext()})
+}
+
+func subjectProjectionInputToMap(input *contracts.SubjectProjectionInput) map[string]any {
+	if input == nil {
+		return map[string]any{}
+	}
+	return compactMap(map[string]any{"catalog": input.GetCatalog(), "module": input.GetModule(), "provider": input.GetProvider(), "subject": input.GetSubject(), "context": input.GetContext()})
+}
+
 func requireCapabilitiesInputToMap(input *contracts.RequireCapabilitiesInput) map[string]any {
 	if input == nil {
 		return map[string]any{}
@@ -388,18 +495,106 @@ func requireCapabilitiesInputToMap(input *contracts.RequireCapabilitiesInput) ma
 	return compactMap(map[string]any{"module": input.GetModule(), "provider": input.GetProvider(), "requirements": capabilityRequirementsToAny(input.GetRequirements())})
 }
 
+func simulateConfigToMap(cfg *contracts.SimulateConfig) map[string]any {
+	if cfg == nil {
+		return map[string]any{}
+	}
+	return simulationFieldsToMap(cfg.GetModule(), cfg.GetProvider(), cfg.GetChanges(), cfg.GetProbes(), cfg.GetAllSubjectScopes())
+}
+
+func simulateInputToMap(input *contracts.SimulateInput) map[string]any {
+	if input == nil {
+		return map[string]any{}
+	}
+	return simulationFieldsToMap(input.GetModule(), input.GetProvider(), input.GetChanges(), input.GetProbes(), input.GetAllSubjectScopes())
+}
+
+func simulationFieldsToMap(module, provider string, changes []*contracts.SimulationChange, probes []*contracts.SimulationProbe, all bool) map[string]any {
+	out := compactMap(map[string]any{"module": module, "provider": provider})
+	if len(changes) > 0 {
+		items := make([]any, 0, len(changes))
+		for _, change := range changes {
+			items = append(items, compactMap(map[string]any{
+				"op":         change.GetOp(),
+				"kind":       change.GetKind(),
+				"rule":       stringsToAny(change.GetRule()),
+				"grant":      roleScopeGrantContractToMap(change.GetGrant()),
+				"assignment": subjectRoleAssignmentContractToMap(change.GetAssignment()),
+				"tuple":      relationTupleContractToMap(change.GetTuple()),
+				"policy":     attributePolicyContractToMap(change.GetPolicy()),
+			}))
+		}
+		out["changes"] = items
+	}
+	if len(probes) > 0 {
+		items := make([]any, 0, len(probes))
+		for _, probe := range probes {
+			items = append(items, simulationProbeToMap(SimulationProbe{
+				Kind:                  probe.GetKind(),
+				Mode:                  AuthzCapability(authzModeString(probe.GetMode())),
+				Subject:               probe.GetSubject(),
+				Context:               probe.GetContext(),
+				Resource:              probe.GetResource(),
+				Action:                probe.GetAction(),
+				Scope:                 probe.GetScope(),
+				Relation:              probe.GetRelation(),
+				SubjectAttributes:     stringMapFromStruct(probe.GetSubjectAttributes()),
+				ResourceAttributes:    stringMapFromStruct(probe.GetResourceAttributes()),
+				EnvironmentAttributes: stringMapFromStruct(probe.GetEnvironmentAttributes()),
+			}))
+		}
+		out["probes"] = items
+	}
+	if all {
+		out["all_subject_scopes"] = true
+	}
+	return out
+}
+
+func roleScopeGrantContractToMap(grant *contracts.RoleScopeGrant) map[string]any {
+	if grant == nil {
+		return nil
+	}
+	return compactMap(map[string]any{"role": grant.GetRole(), "context": grant.GetContext(), "scopes": stringsToAny(grant.GetScopes())})
+}
+
+func subjectRoleAssignmentContractToMap(assignment *contracts.SubjectRoleAssignment) map[string]any {
+	if assignment == nil {
+		return nil
+	}
+	return compactMap(map[string]any{"subject": assignment.GetSubject(), "role": assignment.GetRole(), "context": assignment.GetContext(), "direct_scopes": stringsToAny(assignment.GetDirectScopes())})
+}
+
+func relationTupleContractToMap(tuple *contracts.RelationTuple) map[string]any {
+	if tuple == nil {
+		return nil
+	}
+	return compactMap(map[string]any{"subject": tuple.GetSubject(), "relation": tuple.GetRelation(), "object": tuple.GetObject(), "context": tuple.GetContext()})
+}
+
+func attributePolicyContractToMap(policy *contracts.AttributePolicy) map[string]any {
+	if policy == nil {
+		return nil
+	}
+	conditions := make([]any, 0, len(policy.GetConditions()))
+	for _, condition := range policy.GetConditions() {
+		conditions = append(conditions, compactMap(map[string]any{"target": condition.GetTarget(), "attribute": condition.GetAttribute(), "operator": condition.GetOperator(), "values": stringsToAny(condition.GetValues())}))
+	}
+	return compactMap(map[string]any{"id": policy.GetId(), "context": policy.GetContext(), "resource": policy.GetResource(), "action": policy.GetAction(), "effect": policy.GetEffect(), "conditions": conditions, "description": policy.GetDescription(), "owner_plugin": policy.GetOwnerPlugin(), "owner_module": policy.GetOwnerModule()})
+}
+
 func subjectObjectActionConfigToMap(cfg *contracts.SubjectObjectActionConfig) map[string]any {
 	if cfg == nil {
 		return nil
 	}
-	return compactMap(map[string]any{"module": cfg.GetModule(), "subject": cfg.GetSubject(), "object": cfg.GetObject(), "action": cfg.GetAction()})
+	return compactMap(map[string]any{"module": cfg.GetModule(), "subject": cfg.GetSubject(), "object": cfg.GetObject(), "action": cfg.GetAction(), "actor": cfg.GetActor(), "reason": cfg.GetReason(), "audit_module": cfg.GetAuditModule()})
 }
 
 func subjectObjectActionInputToMap(input *contracts.SubjectObjectActionInput) map[string]any {
 	if input == nil {
 		return nil
 	}
-	return compactMap(map[string]any{"module": input.GetModule(), "subject": input.GetSubject(), "object": input.GetObject(), "action": input.GetAction()})
+	return compactMap(map[string]any{"module": input.GetModule(), "subject": input.GetSubject(), "object": input.GetObject(), "action": input.GetAction(), "actor": input.GetActor(), "reason": input.GetReason(), "audit_module": input.GetAuditModule()})
 }
 
 func listConfigToMap(cfg *contracts.ListConfig) map[string]any {
@@ -420,14 +615,14 @@ func relationConfigToMap(cfg *contracts.RelationConfig) map[string]any {
 	if cfg == nil {
 		return nil
 	}
-	return compactMap(map[string]any{"module": cfg.GetModule(), "subject": cfg.GetSubject(), "relation": cfg.GetRelation(), "object": cfg.GetObject()})
+	return compactMap(map[string]any{"module": cfg.GetModule(), "subject": cfg.GetSubject(), "relation": cfg.GetRelation(), "object": cfg.GetObject(), "actor": cfg.GetActor(), "reason": cfg.GetReason(), "audit_module": cfg.GetAuditModule()})
 }
 
 func relationInputToMap(input *contracts.RelationInput) map[string]any {
 	if input == nil {
 		return nil
 	}
-	return compactMap(map[string]any{"module": input.GetModule(), "subject": input.GetSubject(), "relation": input.GetRelation(), "object": input.GetObject()})
+	return compactMap(map[string]any{"module": input.GetModule(), "subject": input.GetSubject(), "relation": input.GetRelation(), "object": input.GetObject(), "actor": input.GetActor(), "reason": input.GetReason(), "audit_module": input.GetAuditModule()})
 }
 
 func permitStepConfigToMap(cfg *contracts.PermitStepConfig) map[string]any {
@@ -511,6 +706,50 @@ func authorizationDecisionOutputFromMap(values map[string]any) *contracts.Author
 	}
 }
 
+func simulateOutputFromMap(values map[string]any) *contracts.SimulateOutput {
+	return &contracts.SimulateOutput{
+		Evaluated: int32(intValue(values["evaluated"])),
+		Granted:   int32(intValue(values["granted"])),
+		Revoked:   int32(intValue(values["revoked"])),
+		Flips:     simulationResultsFromAny(values["flips"]),
+		Errors:    simulationResultsFromAny(values["errors"]),
+	}
+}
+
+func simulationResultsFromAny(value any) []*contracts.SimulationResult {
+	items, ok := value.([]any)
+	if !ok {
+		return nil
+	}
+	out := make([]*contracts.SimulationResult, 0, len(items))
+	for _, item := range items {
+		values := mapValue(item)
+		probe := mapValue(values["probe"])
+		out = append(out, &contracts.SimulationResult{
+			Probe: &contracts.SimulationProbe{
+				Kind:                  stringValue(probe["kind"]),
+				Mode:                  contractAuthzMode(stringValue(probe["mode"])),
+				Subject:               stringValue(probe["subject"]),
+				Context:               stringValue(probe["context"]),
+				Resource:              stringValue(probe["resource"]),
+				Action:                stringValue(probe["action"]),
+				Scope:                 stringValue(probe["scope"]),
+				Relation:              stringValue(probe["relation"]),
+				SubjectAttributes:     structFromAnyMap(probe["subject_attributes"]),
+				ResourceAttributes:    structFromAnyMap(probe["resource_attributes"]),
+				EnvironmentAttributes: structFromAnyMap(probe["environment_attributes"]),
+			},
+			Before:       boolValue(values["before"]),
+			After:        boolValue(values["after"]),
+			BeforeReason: stringValue(values["before_reason"]),
+			AfterReason:  stringValue(values["after_reason"]),
+			Change:       stringValue(values["change"]),
+			Error:        stringValue(values["error"]),
+		})
+	}
+	return out
+}
+
 func subjectObjectActionOutputFromMap(values map[string]any) *contracts.SubjectObjectActionOutput {
 	return &contracts.SubjectObjectActionOutput{
 		Allowed: boolValue(values["allowed"]),
@@ -752,6 +991,18 @@ func contractAuthzMode(mode string) contracts.AuthzMode {
 	}
 }
 
+func contractCapabilityRequirements(requirements []CapabilityRequirement) []*contracts.CapabilityRequirement {
+	out := make([]*contracts.CapabilityRequirement, 0, len(requirements))
+	for _, requirement := range requirements {
+		operations := make([]string, 0, len(requirement.Operations))
+		for _, operation := range requirement.Operations {
+			operations = append(operations, string(operation))
+		}
+		out = append(out, &contracts.CapabilityRequirement{Mode: contractAuthzMode(string(requirement.Mode)), Operations: contractAuthzOperations(operations)})
+	}
+	return out
+}
+
 func contractAuthzOperations(operations []string) []contracts.AuthzOperation {
 	out := make([]contracts.AuthzOperation, 0, len(operations))
 	for _, operation := range operations {